@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -21,13 +22,13 @@ var BlocksCommand = &cli.Command{
 	Flags: []cli.Flag{
 		&cli.StringFlag{
 			Name:        "bn-endpoint",
-			Usage:       "Beacon node endpoint (to request the Beacon States and Blocks)",
+			Usage:       "Beacon node endpoint (to request the Beacon States and Blocks). Comma separated list, the extra endpoints are only compared with the first one to detect chain splits",
 			EnvVars:     []string{"ANALYZER_BN_ENDPOINT"},
 			DefaultText: "http://localhost:5052",
 		},
 		&cli.StringFlag{
 			Name:        "el-endpoint",
-			Usage:       "Execution node endpoint (to request more specific data on Blocks)",
+			Usage:       "Execution node endpoint (to request more specific data on Blocks). Comma separated list, the extra endpoints are used as failover",
 			EnvVars:     []string{"ANALYZER_EL_ENDPOINT"},
 			DefaultText: "http://localhost:8545",
 		},
@@ -63,25 +64,43 @@ var BlocksCommand = &cli.Command{
 		},
 		&cli.IntFlag{
 			Name:        "workers-num",
-			Usage:       "Number of workers to process validators",
+			Usage:       "Number of workers computing the validator rewards of an epoch",
 			EnvVars:     []string{"ANALYZER_WORKER_NUM"},
-			DefaultText: "4",
+			DefaultText: "1",
+		},
+		&cli.StringFlag{
+			Name:        "rewards-storage",
+			Usage:       "How validator rewards are stored: full (t_validator_rewards_summary) or delta (t_validator_rewards_delta, read through v_validator_rewards_delta)",
+			EnvVars:     []string{"ANALYZER_REWARDS_STORAGE"},
+			DefaultText: "full",
+		},
+		&cli.Uint64Flag{
+			Name:        "rewards-keyframe-epochs",
+			Usage:       "With delta storage, epochs between full validator rewards rows",
+			EnvVars:     []string{"ANALYZER_REWARDS_KEYFRAME_EPOCHS"},
+			DefaultText: "225",
 		},
 		&cli.IntFlag{
 			Name:        "db-workers-num",
-			Usage:       "Number of workers to process database operations",
+			Usage:       "Number of database connections the batches are written through",
 			EnvVars:     []string{"ANALYZER_DB_WORKER_NUM"},
-			DefaultText: "4",
+			DefaultText: "1",
 		},
 		&cli.StringFlag{
 			Name:        "download-mode",
-			Usage:       "Either backfill specified slots or follow the chain head example: hybrid,historical,finalized",
+			Usage:       "Either backfill specified slots or follow the chain head example: hybrid,historical,finalized. Expert: branch analyzes the blocks given in --branch-roots",
 			EnvVars:     []string{"ANALYZER_DOWNLOAD_MODE"},
 			DefaultText: "finalized",
 		},
+		&cli.StringFlag{
+			Name:        "mode",
+			Usage:       "Data to download from the beacon node: full, blocks-only (no states, no epoch or validator metrics), epochs-only (no block bodies, blocks derived from the states)",
+			EnvVars:     []string{"ANALYZER_MODE"},
+			DefaultText: "full",
+		},
 		&cli.StringFlag{
 			Name:        "metrics",
-			Usage:       "Metrics to be persisted to the database: epoch,block,rewards,transactions,api_rewards",
+			Usage:       "Metrics to be persisted to the database: epoch,block,rewards,epoch_aggregates,transactions,api_rewards",
 			EnvVars:     []string{"ANALYZER_METRICS"},
 			DefaultText: "epoch,block",
 		},
@@ -103,6 +122,354 @@ var BlocksCommand = &cli.Command{
 			EnvVars:     []string{"ANALYZER_BEACON_CONTRACT_ADDRESS"},
 			DefaultText: "mainnet",
 		},
+		&cli.Int64Flag{
+			Name:        "genesis-time",
+			Usage:       "Custom genesis time (unix seconds) for devnets, overrides the one reported by the node",
+			EnvVars:     []string{"ANALYZER_GENESIS_TIME"},
+			DefaultText: "read from the node",
+		},
+		&cli.StringFlag{
+			Name:        "fork-schedule",
+			Usage:       "Custom fork epochs for devnets as comma separated <fork>=<epoch> (i.e. altair=0,bellatrix=10), override the ones reported by the node",
+			EnvVars:     []string{"ANALYZER_FORK_SCHEDULE"},
+			DefaultText: "read from the node",
+		},
+		&cli.Uint64Flag{
+			Name:        "lag-alarm-slots",
+			Usage:       "Slots behind the chain head from which transactions and blobs are paused to catch up (0 to disable)",
+			EnvVars:     []string{"ANALYZER_LAG_ALARM_SLOTS"},
+			DefaultText: "64",
+		},
+		&cli.Uint64Flag{
+			Name:        "lag-resume-slots",
+			Usage:       "Slots behind the chain head under which transactions and blobs are resumed",
+			EnvVars:     []string{"ANALYZER_LAG_RESUME_SLOTS"},
+			DefaultText: "8",
+		},
+		&cli.StringFlag{
+			Name:    "kafka-brokers",
+			Usage:   "Comma separated list of kafka brokers. If set, the processed epochs, blocks and validator rewards are also published as JSON messages",
+			EnvVars: []string{"ANALYZER_KAFKA_BROKERS"},
+		},
+		&cli.StringFlag{
+			Name:        "kafka-epochs-topic",
+			Usage:       "Kafka topic where epoch summaries are published",
+			EnvVars:     []string{"ANALYZER_KAFKA_EPOCHS_TOPIC"},
+			DefaultText: "goteth-epochs",
+		},
+		&cli.StringFlag{
+			Name:        "kafka-blocks-topic",
+			Usage:       "Kafka topic where block metrics are published",
+			EnvVars:     []string{"ANALYZER_KAFKA_BLOCKS_TOPIC"},
+			DefaultText: "goteth-blocks",
+		},
+		&cli.StringFlag{
+			Name:        "kafka-rewards-topic",
+			Usage:       "Kafka topic where validator reward batches are published",
+			EnvVars:     []string{"ANALYZER_KAFKA_REWARDS_TOPIC"},
+			DefaultText: "goteth-validator-rewards",
+		},
+		&cli.BoolFlag{
+			Name:        "rewards-windows",
+			Usage:       "Maintain the last 1d/7d/30d validator rewards summaries (v_validator_rewards_1d/7d/30d) after each epoch",
+			EnvVars:     []string{"ANALYZER_REWARDS_WINDOWS"},
+			DefaultText: "false",
+		},
+		&cli.IntFlag{
+			Name:        "rewards-retention-epochs",
+			Usage:       "Number of epochs of validator rewards to keep in the database, older ones are deleted as new epochs are processed (0 to keep all)",
+			EnvVars:     []string{"ANALYZER_REWARDS_RETENTION_EPOCHS"},
+			DefaultText: "0",
+		},
+		&cli.StringFlag{
+			Name:    "monitor-validators",
+			Usage:   "Comma separated list of validator indexes whose included votes are checked for double and surround votes",
+			EnvVars: []string{"ANALYZER_MONITOR_VALIDATORS"},
+		},
+		&cli.StringFlag{
+			Name:    "admin-token",
+			Usage:   "Bearer token required by the admin endpoints (POST /reprocess?epoch=N, GET and POST /downtime). The endpoints are disabled if not set",
+			EnvVars: []string{"ANALYZER_ADMIN_TOKEN"},
+		},
+		&cli.Uint64Flag{
+			Name:        "chain-split-slots",
+			Usage:       "Slots the extra beacon endpoints may follow a different chain before a chain split is recorded and the finalized advancement is paused, 0 to disable",
+			EnvVars:     []string{"ANALYZER_CHAIN_SPLIT_SLOTS"},
+			DefaultText: "4",
+		},
+		&cli.BoolFlag{
+			Name:        "attestation-events",
+			Usage:       "Subscribe to the attestation events while following the head to measure the vote latency of each epoch (f_vote_latency_mean_ms, f_vote_latency_p95_ms)",
+			EnvVars:     []string{"ANALYZER_ATTESTATION_EVENTS"},
+			DefaultText: "false",
+		},
+		&cli.Uint64Flag{
+			Name:        "status-check-epochs",
+			Usage:       "Epochs between checks of the computed number of validators per status against the one reported by the beacon node, mismatches are stored in t_validator_status_anomalies. 0 to disable",
+			EnvVars:     []string{"ANALYZER_STATUS_CHECK_EPOCHS"},
+			DefaultText: "0",
+		},
+		&cli.IntFlag{
+			Name:        "el-concurrency",
+			Usage:       "Number of receipt requests sent in parallel to the execution endpoints (eth_getBlockReceipts, or batches of eth_getTransactionReceipt when it is not supported)",
+			EnvVars:     []string{"ANALYZER_EL_CONCURRENCY"},
+			DefaultText: "16",
+		},
+		&cli.StringFlag{
+			Name:    "notification-rules",
+			Usage:   "JSON file with the notification rules evaluated after each epoch (missed attestations of validators, efficiency of pools). Disabled if not set",
+			EnvVars: []string{"ANALYZER_NOTIFICATION_RULES"},
+		},
+		&cli.StringFlag{
+			Name:    "notification-webhook",
+			Usage:   "URL where the triggered notifications are posted as JSON, they are only logged if not set",
+			EnvVars: []string{"ANALYZER_NOTIFICATION_WEBHOOK"},
+		},
+		&cli.StringFlag{
+			Name:    "branch-roots",
+			Usage:   "Comma separated list of block roots of a non-canonical (i.e. orphaned) branch, analyzed with --download-mode=branch. Results are stored in t_orphans and t_orphan_epoch_metrics",
+			EnvVars: []string{"ANALYZER_BRANCH_ROOTS"},
+		},
+		&cli.StringFlag{
+			Name:    "bn-auth",
+			Usage:   "Auth sent to the beacon endpoints: bearer:<token> or basic:<user>:<password>. Credentials in the endpoint url are also used as basic auth",
+			EnvVars: []string{"ANALYZER_BN_AUTH"},
+		},
+		&cli.StringFlag{
+			Name:    "bn-headers",
+			Usage:   "Comma separated list of Name=value headers sent to the beacon endpoints (e.g. the API key of a node provider)",
+			EnvVars: []string{"ANALYZER_BN_HEADERS"},
+		},
+		&cli.StringFlag{
+			Name:    "el-auth",
+			Usage:   "Auth sent to the execution endpoints: bearer:<token> or basic:<user>:<password>. Credentials in the endpoint url are also used as basic auth",
+			EnvVars: []string{"ANALYZER_EL_AUTH"},
+		},
+		&cli.StringFlag{
+			Name:    "el-headers",
+			Usage:   "Comma separated list of Name=value headers sent to the execution endpoints (e.g. the API key of a node provider)",
+			EnvVars: []string{"ANALYZER_EL_HEADERS"},
+		},
+		&cli.BoolFlag{
+			Name:        "graphql",
+			Usage:       "Serve a GraphQL API over the metrics database at /graphql, and the validator rewards summaries, in the prometheus port",
+			EnvVars:     []string{"ANALYZER_GRAPHQL"},
+			DefaultText: "false",
+		},
+		&cli.StringFlag{
+			Name:    "graphql-auth",
+			Usage:   "Auth required by the GraphQL API and the rewards summaries: bearer:<token> or basic:<user>:<password>, as the Authorization header of the requests",
+			EnvVars: []string{"ANALYZER_GRAPHQL_AUTH"},
+		},
+		&cli.BoolFlag{
+			Name:        "registry-check",
+			Usage:       "Check the validator registry of every state against the previous one (index reuse, activation and exit epochs changed once set, duplicated public keys), anomalies are stored in t_registry_anomalies",
+			EnvVars:     []string{"ANALYZER_REGISTRY_CHECK"},
+			DefaultText: "false",
+		},
+		&cli.StringFlag{
+			Name:        "tx-storage",
+			Usage:       "How transactions are stored: light (hash, addresses, value, gas and fees) or full (also the calldata in f_data and the receipt logs in t_transaction_logs)",
+			EnvVars:     []string{"ANALYZER_TX_STORAGE"},
+			DefaultText: "light",
+		},
+		&cli.StringFlag{
+			Name:    "era-blocks-dir",
+			Usage:   "Directory with era files, only the blocks they cover are read from disk instead of the beacon node, states are still downloaded",
+			EnvVars: []string{"ANALYZER_ERA_BLOCKS_DIR"},
+		},
+		&cli.BoolFlag{
+			Name:        "self-check",
+			Usage:       "Compare the finality checkpoints and a sample of validator balances stored for every processed epoch with the ones reported by the beacon node, discrepancies are stored in t_self_check_discrepancies",
+			EnvVars:     []string{"ANALYZER_SELF_CHECK"},
+			DefaultText: "false",
+		},
+		&cli.IntFlag{
+			Name:        "shutdown-grace",
+			Usage:       "Seconds to wait for the routines to finish on shutdown (SIGINT/SIGTERM) before aborting the pending requests and writes",
+			EnvVars:     []string{"ANALYZER_SHUTDOWN_GRACE"},
+			DefaultText: "60",
+		},
+		&cli.StringFlag{
+			Name:    "db-schema",
+			Usage:   "Database of the clickhouse server where the metrics are stored instead of the one in --db-url, created with its own migrations if missing (i.e. to run parallel experiments on the same server)",
+			EnvVars: []string{"ANALYZER_DB_SCHEMA"},
+		},
+		&cli.IntFlag{
+			Name:        "db-reconnect-timeout",
+			Usage:       "Seconds each write waits for the database to come back after losing the connection, pausing the analysis meanwhile (0 to drop the write)",
+			EnvVars:     []string{"ANALYZER_DB_RECONNECT_TIMEOUT"},
+			DefaultText: "600",
+		},
+		&cli.StringFlag{
+			Name:    "failure-report",
+			Usage:   "File where to write a JSON report of the epochs and metrics that failed to process, the exit code is non-zero if any failed",
+			EnvVars: []string{"ANALYZER_FAILURE_REPORT"},
+		},
+		&cli.StringFlag{
+			Name:    "journal",
+			Usage:   "File where to journal the processed slots and epochs, to resume exactly where the analyzer stopped after a crash instead of rewinding from the database",
+			EnvVars: []string{"ANALYZER_JOURNAL"},
+		},
+		&cli.StringFlag{
+			Name:    "downtime-windows",
+			Usage:   "JSON file with the planned maintenance windows of the validators, their missed attestations inside them are tagged in the rewards table",
+			EnvVars: []string{"ANALYZER_DOWNTIME_WINDOWS"},
+		},
+		&cli.BoolFlag{
+			Name:        "sample-balances",
+			Usage:       "Request the balances of the validators in --monitor-validators (up to 1000) at every slot, stored in t_validator_balance_samples",
+			EnvVars:     []string{"ANALYZER_SAMPLE_BALANCES"},
+			DefaultText: "false",
+		},
+		&cli.IntFlag{
+			Name:        "node-health-interval",
+			Usage:       "Seconds between checks of the beacon node sync status, downloads are throttled while it is syncing or slow (0 to disable)",
+			EnvVars:     []string{"ANALYZER_NODE_HEALTH_INTERVAL"},
+			DefaultText: "0",
+		},
+		&cli.IntFlag{
+			Name:        "node-max-latency",
+			Usage:       "Milliseconds the beacon node can take to answer a health check before downloads are throttled",
+			EnvVars:     []string{"ANALYZER_NODE_MAX_LATENCY"},
+			DefaultText: "2000",
+		},
+		&cli.BoolFlag{
+			Name:        "pause-backfill",
+			Usage:       "Pause the historical downloads (and the fill to head) while the beacon node is unhealthy, the head is still followed",
+			EnvVars:     []string{"ANALYZER_PAUSE_BACKFILL"},
+			DefaultText: "false",
+		},
+		&cli.IntFlag{
+			Name:        "stuck-epoch-timeout",
+			Usage:       "Seconds an epoch transition can be in flight (downloading, processing or persisting) before it is reported as stuck (0 to disable)",
+			EnvVars:     []string{"ANALYZER_STUCK_EPOCH_TIMEOUT"},
+			DefaultText: "0",
+		},
+		&cli.BoolFlag{
+			Name:        "requeue-stuck-epochs",
+			Usage:       "Request again the states of the stuck epochs, or reprocess them if they got stuck later",
+			EnvVars:     []string{"ANALYZER_REQUEUE_STUCK_EPOCHS"},
+			DefaultText: "false",
+		},
+		&cli.StringFlag{
+			Name:    "db-dual-write-url",
+			Usage:   "Secondary database where every batch is written too (i.e. while migrating to a new server), the row counts of each epoch are compared in both",
+			EnvVars: []string{"ANALYZER_DB_DUAL_WRITE_URL"},
+		},
+		&cli.IntFlag{
+			Name:        "state-proof-validators",
+			Usage:       "Number of validators whose record and balance are proven against the root of every downloaded state with merkle proofs, 0 to disable. Builds the tree of the whole state (memory intensive)",
+			EnvVars:     []string{"ANALYZER_STATE_PROOF_VALIDATORS"},
+			DefaultText: "0",
+		},
+		&cli.BoolFlag{
+			Name:        "warm-restart",
+			Usage:       "On restart in head mode, replay the persisted head events against the node and resume from the last canonical one instead of the finalized checkpoint",
+			EnvVars:     []string{"ANALYZER_WARM_RESTART"},
+			DefaultText: "false",
+		},
+		&cli.StringFlag{
+			Name:    "rewards-shards",
+			Usage:   "Comma separated list of <first validator index>=<url> databases the validator rewards of each range of validators are written to, the ones below the first index stay in --db-url. Not compatible with the delta rewards storage",
+			EnvVars: []string{"ANALYZER_REWARDS_SHARDS"},
+		},
+		&cli.BoolFlag{
+			Name:        "dry-run",
+			Usage:       "Check the configuration, the connection with the beacon node, the execution node and the database, the spec of the chain and the schema version, print the expected size of the run and exit without writing",
+			EnvVars:     []string{"ANALYZER_DRY_RUN"},
+			DefaultText: "false",
+		},
+		&cli.IntFlag{
+			Name:        "proposer-fairness-epochs",
+			Usage:       "Accumulate the expected (from the stake share) and the assigned proposer duties of every validator, persisting a snapshot every this many epochs, 0 to disable",
+			EnvVars:     []string{"ANALYZER_PROPOSER_FAIRNESS_EPOCHS"},
+			DefaultText: "0",
+		},
+		&cli.BoolFlag{
+			Name:        "validator-keys",
+			Usage:       "Maintain t_validator_keys, the public key, index, activation epoch and withdrawal credentials of every validator, updated as the registry changes",
+			EnvVars:     []string{"ANALYZER_VALIDATOR_KEYS"},
+			DefaultText: "false",
+		},
+		&cli.StringFlag{
+			Name:        "finality-alert-epochs",
+			Usage:       "Comma separated distances (in epochs) between the processed epoch and its finalized checkpoint that raise a notification, empty to disable",
+			EnvVars:     []string{"ANALYZER_FINALITY_ALERT_EPOCHS"},
+			DefaultText: "4,8,16",
+		},
+		&cli.IntFlag{
+			Name:        "prefetch-max-epochs",
+			Usage:       "Maximum epochs of states and blocks downloaded ahead of the processor. Over 2, the window adapts to the download and processing times",
+			EnvVars:     []string{"ANALYZER_PREFETCH_MAX_EPOCHS"},
+			DefaultText: "2",
+		},
+		&cli.IntFlag{
+			Name:        "prefetch-memory",
+			Usage:       "Heap in use (MB) over which the prefetch window shrinks, 0 for no limit",
+			EnvVars:     []string{"ANALYZER_PREFETCH_MEMORY"},
+			DefaultText: "0",
+		},
+		&cli.IntFlag{
+			Name:        "client-diversity-epochs",
+			Usage:       "Estimate the consensus clients of every pool from the graffiti of its blocks, persisting a snapshot of the last this many epochs every this many epochs, 0 to disable",
+			EnvVars:     []string{"ANALYZER_CLIENT_DIVERSITY_EPOCHS"},
+			DefaultText: "0",
+		},
+		&cli.StringFlag{
+			Name:        "custom-pools",
+			Usage:       "Comma separated CSV files or HTTP(S) URLs with the pool of the validators (validator_index,pool_name), merged into t_eth2_pubkeys. A validator in several sources keeps the pool of the first one (optional)",
+			EnvVars:     []string{"ANALYZER_CUSTOM_POOLS"},
+			DefaultText: "",
+		},
+		&cli.IntFlag{
+			Name:        "custom-pools-refresh",
+			Usage:       "Seconds between reloads of the remote custom pools sources, 0 to load them only on start",
+			EnvVars:     []string{"ANALYZER_CUSTOM_POOLS_REFRESH"},
+			DefaultText: "3600",
+		},
+		&cli.StringFlag{
+			Name:        "slashing-protection",
+			Usage:       "EIP-3076 slashing protection file of the operator validators. Their blocks and votes included on chain are checked against it, only for the monitored validators if --monitor-validators is set (optional)",
+			EnvVars:     []string{"ANALYZER_SLASHING_PROTECTION"},
+			DefaultText: "",
+		},
+		&cli.IntFlag{
+			Name:        "calibrate-epochs",
+			Usage:       "Measure the state downloads, validator rewards processing and database writes of the first epochs, then select --workers-num and --db-workers-num from them, 0 to disable",
+			EnvVars:     []string{"ANALYZER_CALIBRATE_EPOCHS"},
+			DefaultText: "0",
+		},
+		&cli.IntFlag{
+			Name:        "state-fallback-slots",
+			Usage:       "When the state of an epoch cannot be decoded, try the states of the slots of the same epoch up to this distance, recording the substitution in t_data_quality_notes. Needs the block metrics, 0 to disable",
+			EnvVars:     []string{"ANALYZER_STATE_FALLBACK_SLOTS"},
+			DefaultText: "0",
+		},
+		&cli.StringFlag{
+			Name:        "statsd-address",
+			Usage:       "host:port of a statsd agent (UDP) the prometheus metrics are also pushed to, in the DogStatsD format (optional)",
+			EnvVars:     []string{"ANALYZER_STATSD_ADDRESS"},
+			DefaultText: "",
+		},
+		&cli.StringFlag{
+			Name:        "statsd-prefix",
+			Usage:       "Prefix of the metric names pushed to statsd (optional)",
+			EnvVars:     []string{"ANALYZER_STATSD_PREFIX"},
+			DefaultText: "",
+		},
+		&cli.StringFlag{
+			Name:        "statsd-tags",
+			Usage:       "Comma separated list of key:value tags added to every metric pushed to statsd (optional)",
+			EnvVars:     []string{"ANALYZER_STATSD_TAGS"},
+			DefaultText: "",
+		},
+		&cli.IntFlag{
+			Name:        "balance-drop-offline-percent",
+			Usage:       "Percentage of the monitored validators that miss their attestation in the same epoch that raises a mass offline notification, 0 to disable",
+			EnvVars:     []string{"ANALYZER_BALANCE_DROP_OFFLINE_PERCENT"},
+			DefaultText: "50",
+		},
 	},
 }
 
@@ -120,13 +487,22 @@ func LaunchBlockMetrics(c *cli.Context) error {
 
 	logrus.SetLevel(utils.ParseLogLevel(conf.LogLevel))
 
+	if conf.DryRun {
+		report := analyzer.DryRun(c.Context, *conf)
+		report.Write(os.Stdout)
+		if report.Failed() {
+			return fmt.Errorf("dry run failed")
+		}
+		return nil
+	}
+
 	// generate the block analyzer
 	blockAnalyzer, err := analyzer.NewChainAnalyzer(c.Context, *conf)
 	if err != nil {
 		return err
 	}
 
-	procDoneC := make(chan struct{})
+	procDoneC := make(chan struct{}, 1) // Run may return after Close, do not block it
 	sigtermC := make(chan os.Signal, 1)
 
 	signal.Notify(sigtermC, syscall.SIGINT, syscall.SIGTERM, os.Interrupt, syscall.SIGTERM)
@@ -144,8 +520,17 @@ func LaunchBlockMetrics(c *cli.Context) error {
 	case <-procDoneC:
 		logCmdChain.Info("Process successfully finished!")
 	}
-	close(sigtermC)
-	close(procDoneC)
+	signal.Stop(sigtermC)
 
+	report := blockAnalyzer.FailureReport()
+	if conf.FailureReport != "" {
+		if err := analyzer.WriteFailureReport(report, conf.FailureReport); err != nil {
+			logCmdChain.Errorf("could not write the failure report: %s", err)
+		}
+	}
+	if len(report.FailedEpochs) > 0 {
+		return fmt.Errorf("%d epochs failed to process, from epoch %d to %d", len(report.FailedEpochs),
+			report.FailedEpochs[0], report.FailedEpochs[len(report.FailedEpochs)-1])
+	}
 	return nil
 }
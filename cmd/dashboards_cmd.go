@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"github.com/migalabs/goteth/pkg/config"
+	"github.com/migalabs/goteth/pkg/dashboards"
+	"github.com/migalabs/goteth/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+	cli "github.com/urfave/cli/v2"
+)
+
+var DashboardsCommand = &cli.Command{
+	Name:   "dashboards",
+	Usage:  "Provisions the prebuilt Grafana dashboards (epoch health, validator rewards, pool comparison, blob market)",
+	Action: LaunchDashboards,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "log-level",
+			Usage:       "Log level: debug, warn, info, error",
+			EnvVars:     []string{"ANALYZER_LOG_LEVEL"},
+			DefaultText: "info",
+		},
+		&cli.StringFlag{
+			Name:        "grafana-url",
+			Usage:       "Grafana url where to provision the dashboards",
+			EnvVars:     []string{"GRAFANA_URL"},
+			DefaultText: "http://localhost:3000",
+		},
+		&cli.StringFlag{
+			Name:     "api-key",
+			Usage:    "Grafana API key (or service account token) with permissions to edit dashboards",
+			EnvVars:  []string{"GRAFANA_API_KEY"},
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:        "datasource-uid",
+			Usage:       "UID of the Grafana ClickHouse datasource the dashboards will query",
+			EnvVars:     []string{"GRAFANA_DATASOURCE_UID"},
+			DefaultText: "goteth-clickhouse",
+		},
+		&cli.StringFlag{
+			Name:    "folder-uid",
+			Usage:   "UID of the Grafana folder where to store the dashboards (optional)",
+			EnvVars: []string{"GRAFANA_FOLDER_UID"},
+		},
+		&cli.StringFlag{
+			Name:    "db-url",
+			Usage:   "Clickhouse database url, if provided the datasource is created when it does not exist",
+			EnvVars: []string{"ANALYZER_DB_URL"},
+		},
+	},
+}
+
+func LaunchDashboards(c *cli.Context) error {
+
+	conf := config.NewDashboardsConfig()
+	conf.Apply(c)
+
+	logrus.SetLevel(utils.ParseLogLevel(conf.LogLevel))
+
+	provisioner, err := dashboards.NewProvisioner(c.Context, conf.GrafanaUrl, conf.ApiKey, conf.DatasourceUID, conf.FolderUID)
+	if err != nil {
+		return err
+	}
+
+	if conf.DBUrl != "" {
+		if err := provisioner.EnsureDatasource(conf.DBUrl); err != nil {
+			return err
+		}
+	}
+
+	if err := provisioner.Provision(); err != nil {
+		return err
+	}
+	logCmdChain.Info("Dashboards successfully provisioned!")
+	return nil
+}
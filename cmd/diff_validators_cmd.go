@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/clientapi"
+	"github.com/migalabs/goteth/pkg/config"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+	cli "github.com/urfave/cli/v2"
+)
+
+var (
+	statusNames = []string{"queued", "active", "exited", "slashed"} // indexed by spec.ValidatorStatus
+	// validator indexes printed in the text output, the json output lists all of them
+	maxListedValidators = 20
+)
+
+var DiffValidatorsCommand = &cli.Command{
+	Name:   "diff-validators",
+	Usage:  "Compares the validator sets of two epochs: activations, exits, slashings, balances and pools",
+	Action: LaunchDiffValidators,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "log-level",
+			Usage:       "Log level: debug, warn, info, error",
+			EnvVars:     []string{"ANALYZER_LOG_LEVEL"},
+			DefaultText: "info",
+		},
+		&cli.StringFlag{
+			Name:    "db-url",
+			Usage:   "Database where the metrics are persisted, read first for both epochs and for the pools of the validators",
+			EnvVars: []string{"ANALYZER_DB_URL"},
+		},
+		&cli.StringFlag{
+			Name:        "rewards-storage",
+			Usage:       "How validator rewards are stored: full or delta",
+			EnvVars:     []string{"ANALYZER_REWARDS_STORAGE"},
+			DefaultText: "full",
+		},
+		&cli.StringFlag{
+			Name:    "bn-endpoint",
+			Usage:   "Beacon node endpoint, to download the epochs not in the database",
+			EnvVars: []string{"ANALYZER_BN_ENDPOINT"},
+		},
+		&cli.IntFlag{
+			Name:        "max-request-retries",
+			Usage:       "Number of retries to make when a request fails",
+			EnvVars:     []string{"ANALYZER_MAX_REQUEST_RETRIES"},
+			DefaultText: "3",
+		},
+		&cli.Uint64Flag{
+			Name:     "epoch-a",
+			Usage:    "First epoch to compare",
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:     "epoch-b",
+			Usage:    "Second epoch to compare",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Usage:       "Output format: text, json",
+			DefaultText: "text",
+		},
+	},
+}
+
+type poolDiffOutput struct {
+	Pool          string `json:"pool"`
+	ValidatorsA   uint64 `json:"validators_a"`
+	ValidatorsB   uint64 `json:"validators_b"`
+	ActiveA       uint64 `json:"active_a"`
+	ActiveB       uint64 `json:"active_b"`
+	BalanceA      uint64 `json:"balance_a"`
+	BalanceB      uint64 `json:"balance_b"`
+	BalanceChange int64  `json:"balance_change"`
+}
+
+type diffValidatorsOutput struct {
+	EpochA        uint64            `json:"epoch_a"`
+	EpochB        uint64            `json:"epoch_b"`
+	StatusA       map[string]uint64 `json:"status_a"`
+	StatusB       map[string]uint64 `json:"status_b"`
+	New           []uint64          `json:"new"`
+	Activations   []uint64          `json:"activations"`
+	Exits         []uint64          `json:"exits"`
+	Slashings     []uint64          `json:"slashings"`
+	BalanceA      uint64            `json:"balance_a"` // gwei
+	BalanceB      uint64            `json:"balance_b"`
+	BalanceChange int64             `json:"balance_change"`
+	Pools         []poolDiffOutput  `json:"pools"`
+}
+
+// validatorSetSource reads the validator set of an epoch from the database, or downloads it if it is not there
+type validatorSetSource struct {
+	dbClient *db.DBService
+	apiCli   *clientapi.APIClient
+}
+
+func (s validatorSetSource) validators(epoch phase0.Epoch) ([]spec.ValidatorSnapshot, error) {
+	if s.dbClient != nil {
+		snapshots, err := s.dbClient.RetrieveValidatorSnapshots(epoch)
+		if err == nil && len(snapshots) > 0 {
+			logCmdChain.Infof("epoch %d: %d validators read from the database", epoch, len(snapshots))
+			return snapshots, nil
+		}
+		if err != nil {
+			logCmdChain.Warnf("could not read epoch %d from the database: %s", epoch, err)
+		}
+	}
+	if s.apiCli == nil {
+		return nil, fmt.Errorf("epoch %d is not in the database and no --bn-endpoint was given to download it", epoch)
+	}
+	// the state at the end of the epoch, as the rewards stored in the database
+	slot := phase0.Slot(epoch+1)*spec.SlotsPerEpoch - 1
+	snapshots, err := s.apiCli.RequestValidatorSnapshots(slot)
+	if err != nil {
+		return nil, err
+	}
+	logCmdChain.Infof("epoch %d: %d validators downloaded from the node at slot %d", epoch, len(snapshots), slot)
+	return snapshots, nil
+}
+
+func LaunchDiffValidators(c *cli.Context) error {
+	conf := config.NewDiffValidatorsConfig()
+	conf.Apply(c)
+
+	logrus.SetLevel(utils.ParseLogLevel(conf.LogLevel))
+	if conf.Format == "json" {
+		logrus.SetOutput(os.Stderr) // keep the standard output for the diff
+	}
+
+	if conf.DBUrl == "" && conf.BnEndpoint == "" {
+		return fmt.Errorf("either --db-url or --bn-endpoint must be given")
+	}
+
+	source := validatorSetSource{}
+	pools := make(map[phase0.ValidatorIndex]string)
+	if conf.DBUrl != "" {
+		options := make([]db.DBServiceOption, 0)
+		if conf.RewardsStorage == "delta" {
+			options = append(options, db.WithRewardsDeltaEncoding())
+		}
+		dbClient, err := db.New(c.Context, conf.DBUrl, options...)
+		if err != nil {
+			return err
+		}
+		if err := dbClient.Connect(); err != nil {
+			return err
+		}
+		defer dbClient.Finish()
+		source.dbClient = dbClient
+
+		if pools, err = dbClient.RetrieveValidatorPools(); err != nil {
+			return err
+		}
+	}
+	if conf.BnEndpoint != "" {
+		apiCli, err := clientapi.NewAPIClient(c.Context, conf.BnEndpoint, conf.MaxRequestRetries)
+		if err != nil {
+			return err
+		}
+		source.apiCli = apiCli
+	}
+
+	epochA, epochB := phase0.Epoch(conf.EpochA), phase0.Epoch(conf.EpochB)
+	a, err := source.validators(epochA)
+	if err != nil {
+		return err
+	}
+	b, err := source.validators(epochB)
+	if err != nil {
+		return err
+	}
+	for _, snapshots := range [][]spec.ValidatorSnapshot{a, b} {
+		for i := range snapshots {
+			snapshots[i].Pool = pools[snapshots[i].Index]
+		}
+	}
+
+	output := newDiffValidatorsOutput(spec.DiffValidators(epochA, a, epochB, b))
+	switch conf.Format {
+	case "text":
+		printDiffValidators(output)
+		return nil
+	case "json":
+		return json.NewEncoder(os.Stdout).Encode(output)
+	default:
+		return fmt.Errorf("unknown format %s, expected text or json", conf.Format)
+	}
+}
+
+func newDiffValidatorsOutput(diff spec.ValidatorSetDiff) diffValidatorsOutput {
+	indexes := func(validators []phase0.ValidatorIndex) []uint64 {
+		result := make([]uint64, 0, len(validators))
+		for _, valIdx := range validators {
+			result = append(result, uint64(valIdx))
+		}
+		return result
+	}
+	output := diffValidatorsOutput{
+		EpochA:        uint64(diff.EpochA),
+		EpochB:        uint64(diff.EpochB),
+		StatusA:       make(map[string]uint64),
+		StatusB:       make(map[string]uint64),
+		New:           indexes(diff.New),
+		Activations:   indexes(diff.Activations),
+		Exits:         indexes(diff.Exits),
+		Slashings:     indexes(diff.Slashings),
+		BalanceA:      uint64(diff.BalanceA),
+		BalanceB:      uint64(diff.BalanceB),
+		BalanceChange: diff.BalanceChange,
+		Pools:         make([]poolDiffOutput, 0, len(diff.Pools)),
+	}
+	for status, name := range statusNames {
+		output.StatusA[name] = diff.StatusA[status]
+		output.StatusB[name] = diff.StatusB[status]
+	}
+	for _, pool := range diff.Pools {
+		output.Pools = append(output.Pools, poolDiffOutput{
+			Pool:          pool.Pool,
+			ValidatorsA:   pool.ValidatorsA,
+			ValidatorsB:   pool.ValidatorsB,
+			ActiveA:       pool.ActiveA,
+			ActiveB:       pool.ActiveB,
+			BalanceA:      uint64(pool.BalanceA),
+			BalanceB:      uint64(pool.BalanceB),
+			BalanceChange: pool.BalanceChange,
+		})
+	}
+	return output
+}
+
+func printDiffValidators(output diffValidatorsOutput) {
+	eth := func(gwei float64) float64 {
+		return gwei / spec.EffectiveBalanceInc
+	}
+	listed := func(validators []uint64) string {
+		if len(validators) > maxListedValidators {
+			return fmt.Sprintf("%v ... (%d more)", validators[:maxListedValidators], len(validators)-maxListedValidators)
+		}
+		return fmt.Sprintf("%v", validators)
+	}
+
+	fmt.Printf("epoch %d -> epoch %d\n\n", output.EpochA, output.EpochB)
+	for _, name := range statusNames {
+		fmt.Printf("%-12s %10d -> %10d\n", name, output.StatusA[name], output.StatusB[name])
+	}
+	fmt.Println()
+	fmt.Printf("new          %d %s\n", len(output.New), listed(output.New))
+	fmt.Printf("activations  %d %s\n", len(output.Activations), listed(output.Activations))
+	fmt.Printf("exits        %d %s\n", len(output.Exits), listed(output.Exits))
+	fmt.Printf("slashings    %d %s\n", len(output.Slashings), listed(output.Slashings))
+	fmt.Println()
+	fmt.Printf("balance      %.4f ETH -> %.4f ETH, %+.6f ETH for the validators in both epochs\n",
+		eth(float64(output.BalanceA)), eth(float64(output.BalanceB)), eth(float64(output.BalanceChange)))
+
+	if len(output.Pools) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("%-24s %20s %20s %16s\n", "pool", "validators", "active", "balance change")
+	for _, pool := range output.Pools {
+		fmt.Printf("%-24s %10d -> %6d %10d -> %6d %+16.6f\n", pool.Pool,
+			pool.ValidatorsA, pool.ValidatorsB, pool.ActiveA, pool.ActiveB, eth(float64(pool.BalanceChange)))
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/migalabs/goteth/pkg/config"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+	cli "github.com/urfave/cli/v2"
+)
+
+var EstimateCommand = &cli.Command{
+	Name:   "estimate",
+	Usage:  "Estimates the rows and storage per table of a planned run, to provision the database before launching it",
+	Action: LaunchEstimate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "log-level",
+			Usage:       "Log level: debug, warn, info, error",
+			EnvVars:     []string{"ANALYZER_LOG_LEVEL"},
+			DefaultText: "info",
+		},
+		&cli.StringFlag{
+			Name:        "metrics",
+			Usage:       "Metrics of the planned run: epoch,block,rewards,epoch_aggregates,transactions,api_rewards",
+			EnvVars:     []string{"ANALYZER_METRICS"},
+			DefaultText: "epoch,block",
+		},
+		&cli.Uint64Flag{
+			Name:     "epochs",
+			Usage:    "Number of epochs of the planned run",
+			Required: true,
+		},
+		&cli.Uint64Flag{
+			Name:     "validators",
+			Usage:    "Number of validators of the network",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "db-url",
+			Usage: "Database of a previous run to measure the average row sizes in, approximate sizes are used otherwise",
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Usage:       "Output format: text, json",
+			DefaultText: "text",
+		},
+	},
+}
+
+type tableEstimateOutput struct {
+	Table    string `json:"table"`
+	Metric   string `json:"metric"`
+	Rows     uint64 `json:"rows"`
+	Bytes    uint64 `json:"bytes"`
+	Measured bool   `json:"measured"` // the row size was measured in --db-url
+}
+
+type estimateOutput struct {
+	Epochs     uint64                `json:"epochs"`
+	Validators uint64                `json:"validators"`
+	Tables     []tableEstimateOutput `json:"tables"`
+	Bytes      uint64                `json:"bytes"` // all the tables
+}
+
+func LaunchEstimate(c *cli.Context) error {
+	conf := config.NewEstimateConfig()
+	conf.Apply(c)
+
+	logrus.SetLevel(utils.ParseLogLevel(conf.LogLevel))
+	if conf.Format == "json" {
+		logrus.SetOutput(os.Stderr) // keep the standard output for the estimate
+	}
+
+	metrics, err := db.NewMetrics(conf.Metrics)
+	if err != nil {
+		return err
+	}
+
+	measured := make(map[string]uint64)
+	if conf.DBUrl != "" {
+		dbClient, err := db.New(c.Context, conf.DBUrl)
+		if err != nil {
+			return err
+		}
+		if err := dbClient.Connect(); err != nil {
+			return err
+		}
+		defer dbClient.Finish()
+		if measured, err = dbClient.MeasureRowBytes(); err != nil {
+			return err
+		}
+		logCmdChain.Infof("row sizes of %d tables measured in the database", len(measured))
+	}
+
+	output := estimateOutput{
+		Epochs:     conf.Epochs,
+		Validators: conf.Validators,
+		Tables:     make([]tableEstimateOutput, 0),
+	}
+	for _, estimate := range db.EstimateRun(metrics, conf.Epochs, conf.Validators, measured) {
+		output.Tables = append(output.Tables, tableEstimateOutput{
+			Table:    estimate.Table,
+			Metric:   estimate.Metric,
+			Rows:     estimate.Rows,
+			Bytes:    estimate.Bytes,
+			Measured: estimate.Measured,
+		})
+		output.Bytes += estimate.Bytes
+	}
+
+	switch conf.Format {
+	case "text":
+		fmt.Printf("%d epochs with %d validators:\n", output.Epochs, output.Validators)
+		for _, table := range output.Tables {
+			rowSize := "approximate"
+			if table.Measured {
+				rowSize = "measured"
+			}
+			fmt.Printf("%-32s %-12s %14d rows %10.2f GB (%s row size)\n",
+				table.Table, table.Metric, table.Rows, float64(table.Bytes)/1e9, rowSize)
+		}
+		fmt.Printf("%-32s %-12s %19s %10.2f GB\n", "total", "", "", float64(output.Bytes)/1e9)
+		return nil
+	case "json":
+		return json.NewEncoder(os.Stdout).Encode(output)
+	default:
+		return fmt.Errorf("unknown format %s, expected text or json", conf.Format)
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/chaintime"
+	"github.com/migalabs/goteth/pkg/config"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/export"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+	cli "github.com/urfave/cli/v2"
+)
+
+var ExitEstimateCommand = &cli.Command{
+	Name:   "exit-estimate",
+	Usage:  "Estimates the exit epoch and withdrawable date of validators from the exit queue at the latest analyzed epoch, as JSON",
+	Action: LaunchExitEstimate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "log-level",
+			Usage:       "Log level: debug, warn, info, error",
+			EnvVars:     []string{"ANALYZER_LOG_LEVEL"},
+			DefaultText: "info",
+		},
+		&cli.StringFlag{
+			Name:    "db-url",
+			Usage:   "Database where the metrics are persisted, the validator registry is read from its last status",
+			EnvVars: []string{"ANALYZER_DB_URL"},
+		},
+		&cli.StringFlag{
+			Name:        "network",
+			Usage:       "Network whose genesis time is used: mainnet, sepolia, holesky",
+			DefaultText: "mainnet",
+		},
+		&cli.Uint64Flag{
+			Name:  "genesis",
+			Usage: "Genesis time (unix) of the network, for networks not in --network",
+		},
+		&cli.StringFlag{
+			Name:     "indexes",
+			Usage:    "Comma separated validator indexes, their exits are queued in the given order",
+			Required: true,
+		},
+		&cli.BoolFlag{
+			Name:  "pre-electra",
+			Usage: "Count the churn in validators, as before Electra, instead of in balance",
+		},
+	},
+}
+
+type exitEstimateValidatorOutput struct {
+	Index             uint64 `json:"index"`
+	ExitEpoch         uint64 `json:"exit_epoch,omitempty"`
+	ExitTime          string `json:"exit_time,omitempty"`
+	WithdrawableEpoch uint64 `json:"withdrawable_epoch,omitempty"`
+	WithdrawableTime  string `json:"withdrawable_time,omitempty"`
+	Exiting           bool   `json:"exiting"` // the exit was already requested
+	Error             string `json:"error,omitempty"`
+}
+
+type exitEstimateOutput struct {
+	Epoch      uint64                        `json:"epoch"`       // latest analyzed epoch
+	ChurnLimit uint64                        `json:"churn_limit"` // validators, or gwei since Electra
+	QueueEnd   uint64                        `json:"queue_end"`   // exit epoch of the last exit in the queue
+	Validators []exitEstimateValidatorOutput `json:"validators"`
+}
+
+func LaunchExitEstimate(c *cli.Context) error {
+	conf := config.NewExitEstimateConfig()
+	conf.Apply(c)
+
+	logrus.SetLevel(utils.ParseLogLevel(conf.LogLevel))
+	logrus.SetOutput(os.Stderr) // keep the standard output for the estimates
+
+	chainTime, err := chaintime.ForNetwork(conf.Network)
+	if conf.Genesis != 0 {
+		chainTime, err = chaintime.New(time.Unix(int64(conf.Genesis), 0)), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	indexes, err := export.ParseValidators(conf.Indexes)
+	if err != nil {
+		return err
+	}
+	if len(indexes) == 0 {
+		return fmt.Errorf("no validator indexes given")
+	}
+
+	dbClient, err := db.New(c.Context, conf.DBUrl)
+	if err != nil {
+		return err
+	}
+	if err := dbClient.Connect(); err != nil {
+		return err
+	}
+	defer dbClient.Finish()
+
+	epoch, registry, err := dbClient.RetrieveExitQueueRegistry()
+	if err != nil {
+		return err
+	}
+	if len(registry) == 0 {
+		return fmt.Errorf("no validator status in the database, run the analyzer first")
+	}
+	logCmdChain.Infof("exit queue at epoch %d, %d validators in the registry", epoch, len(registry))
+
+	queue := spec.NewExitQueue(epoch, registry, !conf.PreElectra)
+	output := exitEstimateOutput{
+		Epoch:      uint64(epoch),
+		ChurnLimit: queue.ChurnLimit,
+		QueueEnd:   uint64(queue.ExitEpoch),
+		Validators: make([]exitEstimateValidatorOutput, 0, len(indexes)),
+	}
+	for _, estimate := range queue.Estimate(registry, indexes) {
+		validator := exitEstimateValidatorOutput{
+			Index:   uint64(estimate.Index),
+			Exiting: estimate.Exiting,
+			Error:   estimate.Error,
+		}
+		if estimate.Error == "" {
+			validator.ExitEpoch = uint64(estimate.ExitEpoch)
+			validator.ExitTime = epochTime(chainTime, estimate.ExitEpoch)
+			validator.WithdrawableEpoch = uint64(estimate.WithdrawableEpoch)
+			validator.WithdrawableTime = epochTime(chainTime, estimate.WithdrawableEpoch)
+		}
+		output.Validators = append(output.Validators, validator)
+	}
+	return json.NewEncoder(os.Stdout).Encode(output)
+}
+
+func epochTime(chainTime chaintime.ChainTime, epoch phase0.Epoch) string {
+	return chainTime.AtEpoch(epoch).Time.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/migalabs/goteth/pkg/config"
+	"github.com/migalabs/goteth/pkg/sink"
+	"github.com/migalabs/goteth/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+	cli "github.com/urfave/cli/v2"
+)
+
+var (
+	maxRecordSize = 64 * 1024 * 1024 // validator rewards batches are big
+	schemaFlags   = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "log-level",
+			Usage:       "Log level: debug, warn, info, error",
+			EnvVars:     []string{"ANALYZER_LOG_LEVEL"},
+			DefaultText: "info",
+		},
+		&cli.StringFlag{
+			Name:        "kind",
+			Usage:       "Kind of record: epoch, block, validator_rewards",
+			DefaultText: "epoch",
+		},
+	}
+)
+
+var SchemaCommand = &cli.Command{
+	Name:  "schema",
+	Usage: "Prints the JSON schema of the records published by the sinks, or converts old records to the current schema version",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "show",
+			Usage:  "Prints the JSON schema of a kind of record",
+			Action: LaunchSchemaShow,
+			Flags: append(schemaFlags, &cli.IntFlag{
+				Name:        "version",
+				Usage:       "Schema version",
+				DefaultText: fmt.Sprintf("%d", sink.SchemaVersion),
+			}),
+		},
+		{
+			Name:   "convert",
+			Usage:  "Reads JSON records (one per line) from the standard input and writes them in the current schema version to the standard output",
+			Action: LaunchSchemaConvert,
+			Flags:  schemaFlags,
+		},
+	},
+}
+
+func LaunchSchemaShow(c *cli.Context) error {
+	conf := config.NewSchemaConfig()
+	conf.Apply(c)
+
+	logrus.SetLevel(utils.ParseLogLevel(conf.LogLevel))
+
+	version := conf.Version
+	if version == 0 {
+		version = sink.SchemaVersion
+	}
+	schema, err := sink.Schema(sink.RecordKind(conf.Kind), version)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(schema)
+	return err
+}
+
+func LaunchSchemaConvert(c *cli.Context) error {
+	conf := config.NewSchemaConfig()
+	conf.Apply(c)
+
+	logrus.SetLevel(utils.ParseLogLevel(conf.LogLevel))
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 1024*1024), maxRecordSize)
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record, err := sink.UpgradeRecord(sink.RecordKind(conf.Kind), scanner.Bytes())
+		if err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+		if _, err := writer.Write(append(record, '\n')); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/chaintime"
+	"github.com/migalabs/goteth/pkg/config"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+	cli "github.com/urfave/cli/v2"
+)
+
+var WhenCommand = &cli.Command{
+	Name:   "when",
+	Usage:  "Converts between slots, epochs, sync committee periods and wall-clock times of the network (the current slot if none is given)",
+	Action: LaunchWhen,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "log-level",
+			Usage:       "Log level: debug, warn, info, error",
+			EnvVars:     []string{"ANALYZER_LOG_LEVEL"},
+			DefaultText: "info",
+		},
+		&cli.StringFlag{
+			Name:        "network",
+			Usage:       "Network whose genesis time is used: mainnet, sepolia, holesky",
+			DefaultText: "mainnet",
+		},
+		&cli.Uint64Flag{
+			Name:  "genesis",
+			Usage: "Genesis time (unix) of the network, for networks not in --network",
+		},
+		&cli.Uint64Flag{
+			Name:  "slot",
+			Usage: "Slot to convert",
+		},
+		&cli.Uint64Flag{
+			Name:  "epoch",
+			Usage: "Epoch to convert, from its first slot",
+		},
+		&cli.Uint64Flag{
+			Name:  "sync-period",
+			Usage: "Sync committee period to convert, from its first slot",
+		},
+		&cli.StringFlag{
+			Name:  "date",
+			Usage: "Date to convert to the slot in progress: RFC3339 (2024-03-13T13:55:35Z), YYYY-MM-DD (UTC) or a unix timestamp",
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Usage:       "Output format: text, json",
+			DefaultText: "text",
+		},
+	},
+}
+
+type whenOutput struct {
+	Slot        uint64 `json:"slot"`
+	Epoch       uint64 `json:"epoch"`
+	SyncPeriod  uint64 `json:"sync_period"`
+	Time        string `json:"time"` // start of the slot
+	Unix        int64  `json:"unix"`
+	SlotInEpoch uint64 `json:"slot_in_epoch"`
+}
+
+func LaunchWhen(c *cli.Context) error {
+	conf := config.NewWhenConfig()
+	conf.Apply(c)
+
+	logrus.SetLevel(utils.ParseLogLevel(conf.LogLevel))
+
+	chainTime, err := chaintime.ForNetwork(conf.Network)
+	if conf.Genesis != 0 {
+		chainTime, err = chaintime.New(time.Unix(int64(conf.Genesis), 0)), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	given := 0
+	for _, isSet := range []bool{conf.Slot >= 0, conf.Epoch >= 0, conf.SyncPeriod >= 0, conf.Date != ""} {
+		if isSet {
+			given++
+		}
+	}
+	if given > 1 {
+		return fmt.Errorf("only one of --slot, --epoch, --sync-period and --date can be given")
+	}
+
+	var point chaintime.Point
+	switch {
+	case conf.Slot >= 0:
+		point = chainTime.AtSlot(phase0.Slot(conf.Slot))
+	case conf.Epoch >= 0:
+		point = chainTime.AtEpoch(phase0.Epoch(conf.Epoch))
+	case conf.SyncPeriod >= 0:
+		point = chainTime.AtSyncPeriod(uint64(conf.SyncPeriod))
+	default:
+		date := time.Now()
+		if conf.Date != "" {
+			if date, err = chaintime.ParseTime(conf.Date); err != nil {
+				return err
+			}
+		}
+		if point, err = chainTime.AtTime(date); err != nil {
+			return err
+		}
+	}
+
+	output := whenOutput{
+		Slot:        uint64(point.Slot),
+		Epoch:       uint64(point.Epoch),
+		SyncPeriod:  point.SyncPeriod,
+		Time:        point.Time.UTC().Format(time.RFC3339),
+		Unix:        point.Time.Unix(),
+		SlotInEpoch: uint64(point.Slot) % spec.SlotsPerEpoch,
+	}
+	switch conf.Format {
+	case "text":
+		fmt.Printf("slot         %d (%d of the epoch)\n", output.Slot, output.SlotInEpoch)
+		fmt.Printf("epoch        %d\n", output.Epoch)
+		fmt.Printf("sync period  %d (epochs %d to %d)\n", output.SyncPeriod,
+			output.SyncPeriod*chaintime.EpochsPerSyncCommitteePeriod, (output.SyncPeriod+1)*chaintime.EpochsPerSyncCommitteePeriod-1)
+		fmt.Printf("time         %s (unix %d)\n", output.Time, output.Unix)
+		return nil
+	case "json":
+		return json.NewEncoder(os.Stdout).Encode(output)
+	default:
+		return fmt.Errorf("unknown format %s, expected text or json", conf.Format)
+	}
+}
@@ -19,7 +19,7 @@ var (
 )
 
 func main() {
-	fmt.Println(utils.CliName, utils.Version)
+	fmt.Fprintln(os.Stderr, utils.CliName, utils.Version) // stdout is kept for the commands output
 
 	customFormatter := new(logrus.TextFormatter)
 	customFormatter.FullTimestamp = true
@@ -47,6 +47,14 @@ func main() {
 		Commands: []*cli.Command{
 			cmd.BlocksCommand,
 			cmd.ValidatorWindowCommand,
+			cmd.DashboardsCommand,
+			cmd.SchemaCommand,
+			cmd.ExportCommand,
+			cmd.TaxReportCommand,
+			cmd.WhenCommand,
+			cmd.DiffValidatorsCommand,
+			cmd.ExitEstimateCommand,
+			cmd.EstimateCommand,
 		},
 	}
 
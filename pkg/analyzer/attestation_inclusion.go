@@ -0,0 +1,34 @@
+package analyzer
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+)
+
+// processAttestationInclusions persists the proposer that first included each vote of the monitored validators
+// in the epoch blocks, and at what delay. The blocks of the previous epoch tell the votes already included
+func (s *ChainAnalyzer) processAttestationInclusions(bundle metrics.StateMetrics) {
+	if !s.voteTracker.Monitoring() || !s.metrics.Block {
+		return
+	}
+	base := bundle.GetMetricsBase()
+
+	committee := func(slot phase0.Slot, index phase0.CommitteeIndex) []phase0.ValidatorIndex {
+		if spec.EpochAtSlot(slot) == base.NextState.Epoch {
+			return base.NextState.EpochStructs.GetValList(slot, index)
+		}
+		if spec.EpochAtSlot(slot) == base.CurrentState.Epoch {
+			return base.CurrentState.EpochStructs.GetValList(slot, index)
+		}
+		return nil // votes of the epoch before, only in the previous blocks
+	}
+	inclusions := spec.NewAttestationInclusions(base.CurrentState.Blocks, base.NextState.Blocks, committee, s.voteTracker.Monitored)
+	if len(inclusions) == 0 {
+		return
+	}
+	err := s.dbClient.PersistAttestationInclusions(inclusions)
+	if err != nil {
+		log.Errorf("error persisting attestation inclusions: %s", err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// checkpointRootsEpochs is the number of epochs whose checkpoint is kept, blocks only include votes
+// for the current and the previous epoch
+const checkpointRootsEpochs = 4
+
+// checkpointRoots caches the canonical checkpoint (the block at the first slot of the epoch,
+// or the last one before it if missed) of the recent epochs
+type checkpointRoots struct {
+	sync.Mutex
+	roots  map[phase0.Epoch]phase0.Root
+	reorgs uint64 // resets so far, a root requested before a reset is not stored
+}
+
+func newCheckpointRoots() *checkpointRoots {
+	return &checkpointRoots{
+		roots: make(map[phase0.Epoch]phase0.Root),
+	}
+}
+
+// get returns the cached checkpoint of the epoch and the resets so far, to store the root once requested
+func (c *checkpointRoots) get(epoch phase0.Epoch) (phase0.Root, uint64, bool) {
+	c.Lock()
+	defer c.Unlock()
+	root, ok := c.roots[epoch]
+	return root, c.reorgs, ok
+}
+
+// store keeps the checkpoint of the epoch unless there was a reset since it was requested,
+// dropping the ones that blocks can no longer vote for
+func (c *checkpointRoots) store(epoch phase0.Epoch, root phase0.Root, reorgs uint64) {
+	c.Lock()
+	defer c.Unlock()
+	if reorgs != c.reorgs {
+		return
+	}
+	c.roots[epoch] = root
+	for cached := range c.roots {
+		if cached+checkpointRootsEpochs < epoch {
+			delete(c.roots, cached)
+		}
+	}
+}
+
+// reset drops the cached checkpoints, which a reorg may have changed
+func (c *checkpointRoots) reset() {
+	c.Lock()
+	defer c.Unlock()
+	c.roots = make(map[phase0.Epoch]phase0.Root)
+	c.reorgs++
+}
+
+// checkpointRoot returns the canonical checkpoint of the epoch, requested to the beacon node the first time.
+// An empty root (not rated) is returned if the request fails
+func (s *ChainAnalyzer) checkpointRoot(epoch phase0.Epoch) phase0.Root {
+	root, reorgs, ok := s.checkpointRoots.get(epoch)
+	if ok {
+		return root
+	}
+
+	for slot := phase0.Slot(epoch) * spec.SlotsPerEpoch; ; slot-- {
+		var err error
+		if root, err = s.cli.RequestBlockRoot(slot); err != nil {
+			log.Errorf("could not get the checkpoint of epoch %d: %s", epoch, err)
+			return phase0.Root{}
+		}
+		if root != (phase0.Root{}) || slot == 0 {
+			break
+		}
+	}
+	s.checkpointRoots.store(epoch, root, reorgs)
+	return root
+}
+
+// processAttestationTargets counts the aggregates of the block voting for the canonical checkpoint of their epoch,
+// persisted with the block as an indicator of the quality of the votes the proposer packed
+func (s *ChainAnalyzer) processAttestationTargets(block *spec.AgnosticBlock) {
+	if !block.Proposed {
+		return
+	}
+	block.CountAttestationTargets(s.checkpointRoot)
+	if block.WrongTargetAttestations > 0 {
+		log.Debugf("block at slot %d includes %d aggregates with a wrong target", block.Slot, block.WrongTargetAttestations)
+	}
+}
@@ -0,0 +1,33 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpointRoots(t *testing.T) {
+	roots := newCheckpointRoots()
+	_, reorgs, ok := roots.get(10)
+	assert.False(t, ok)
+
+	roots.store(10, phase0.Root{1}, reorgs)
+	root, _, ok := roots.get(10)
+	assert.True(t, ok)
+	assert.Equal(t, phase0.Root{1}, root)
+
+	// the old epochs are dropped
+	roots.store(10+checkpointRootsEpochs+1, phase0.Root{2}, reorgs)
+	_, _, ok = roots.get(10)
+	assert.False(t, ok)
+
+	// a reorg drops the cache and the roots requested before it
+	_, before, _ := roots.get(20)
+	roots.reset()
+	_, _, ok = roots.get(10 + checkpointRootsEpochs + 1)
+	assert.False(t, ok)
+	roots.store(20, phase0.Root{3}, before)
+	_, _, ok = roots.get(20)
+	assert.False(t, ok)
+}
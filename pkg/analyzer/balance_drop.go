@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	prom_metrics "github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	BalanceDrops = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "balance_drops",
+		Help:      "The number of abnormal balance drops of the monitored validators, by type (slashing or mass_offline)",
+	}, []string{"type"})
+
+	balanceDropListed       = 10 // validators listed in the message of a notification, all of them are in its indexes
+	balanceDropMinMonitored = 10 // active monitored validators needed to notify a share of them offline
+)
+
+// processBalanceDrops scans the rewards of the monitored validators for balance drops beyond the max
+// penalty of the epoch (possible slashing) or a large share of them offline at once (mass offline event).
+// Only epochs followed live are notified, not the backfilled or reprocessed ones
+func (s *ChainAnalyzer) processBalanceDrops(bundle metrics.StateMetrics, rewards []spec.ValidatorRewards, aggregate bool) {
+	if !s.voteTracker.Monitoring() || s.downloadMode != "finalized" || !aggregate {
+		return
+	}
+	nextState := bundle.GetMetricsBase().NextState
+
+	monitored := make([]spec.ValidatorRewards, 0)
+	for _, reward := range rewards {
+		if s.voteTracker.Monitored(reward.ValidatorIndex) && int(reward.ValidatorIndex) < len(nextState.Validators) {
+			monitored = append(monitored, reward)
+		}
+	}
+	if len(monitored) == 0 {
+		return
+	}
+
+	weights := bundle.GetRewardWeights()
+	inactivity := metrics.InactivityConfigFromNode(s.cli.Api)
+	maxPenalty := func(reward spec.ValidatorRewards) phase0.Gwei {
+		effectiveBalance := nextState.Validators[reward.ValidatorIndex].EffectiveBalance
+		inactivityScore := uint64(0)
+		if int(reward.ValidatorIndex) < len(nextState.InactivityScores) {
+			inactivityScore = nextState.InactivityScores[reward.ValidatorIndex]
+		}
+		return spec.MaxEpochPenalty(reward, weights, effectiveBalance, inactivityScore, inactivity, nextState.Version)
+	}
+	drops := spec.NewBalanceDrops(monitored, maxPenalty)
+	offline, active := spec.OfflineValidators(monitored)
+	share := 0.0
+	if active > 0 {
+		share = float64(len(offline)) / float64(active)
+	}
+	massOffline := s.balanceDropOfflineShare > 0 && active >= balanceDropMinMonitored && share >= s.balanceDropOfflineShare
+	if len(drops) == 0 && !massOffline {
+		return
+	}
+
+	// the pools are only needed to notify
+	pools, err := s.dbClient.RetrieveValidatorPools()
+	if err != nil {
+		log.Errorf("error retrieving the validator pools of the balance drops: %s", err.Error())
+		pools = make(map[phase0.ValidatorIndex]string)
+	}
+
+	if len(drops) > 0 {
+		BalanceDrops.WithLabelValues("slashing").Add(float64(len(drops)))
+		s.balanceDropsNum.Add(uint64(len(drops)))
+		indexes := make([]phase0.ValidatorIndex, 0, len(drops))
+		listed := make([]string, 0, balanceDropListed)
+		for i, drop := range drops {
+			indexes = append(indexes, drop.ValidatorIndex)
+			if i < balanceDropListed {
+				listed = append(listed, fmt.Sprintf("%d (pool %q) lost %d gwei, max penalty %d",
+					drop.ValidatorIndex, pools[drop.ValidatorIndex], drop.Drop, drop.MaxPenalty))
+			}
+		}
+		message := fmt.Sprintf("%d monitored validators lost more than their max penalty at epoch %d, possible slashing: %s",
+			len(drops), nextState.Epoch, strings.Join(listed, ", "))
+		s.notifyBalanceDrop(nextState.Epoch, message, indexes, pools)
+	}
+
+	if massOffline {
+		BalanceDrops.WithLabelValues("mass_offline").Inc()
+		s.balanceDropsNum.Add(1)
+		listed := make([]string, 0, balanceDropListed)
+		for i, valIdx := range offline {
+			if i < balanceDropListed {
+				listed = append(listed, fmt.Sprintf("%d", valIdx))
+			}
+		}
+		message := fmt.Sprintf("%d of %d active monitored validators (%.1f%%) missed their attestation at epoch %d, possible mass offline event: %s",
+			len(offline), active, share*100, nextState.Epoch, strings.Join(listed, ", "))
+		s.notifyBalanceDrop(nextState.Epoch, message, offline, pools)
+	}
+}
+
+// notifyBalanceDrop sends a high priority notification with the affected validators and their pools
+func (s *ChainAnalyzer) notifyBalanceDrop(epoch phase0.Epoch, message string, indexes []phase0.ValidatorIndex, pools map[phase0.ValidatorIndex]string) {
+	poolSet := make(map[string]struct{})
+	for _, valIdx := range indexes {
+		if poolName := pools[valIdx]; poolName != "" {
+			poolSet[poolName] = struct{}{}
+		}
+	}
+	poolNames := make([]string, 0, len(poolSet))
+	for poolName := range poolSet {
+		poolNames = append(poolNames, poolName)
+	}
+	sort.Strings(poolNames)
+
+	err := s.notifier.Notify(notifier.Notification{
+		Rule:     "balance_drop",
+		Kind:     notifier.BalanceDropKind,
+		Epoch:    epoch,
+		Message:  message,
+		Priority: notifier.HighPriority,
+		Indexes:  indexes,
+		Pools:    poolNames,
+	})
+	if err != nil {
+		log.Errorf("error sending balance drop notification: %s", err.Error())
+	}
+}
+
+func (s *ChainAnalyzer) getBalanceDrops() *prom_metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(BalanceDrops)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.balanceDropsNum.Load(), nil
+	}
+
+	indvMetr, err := prom_metrics.NewIndvMetrics(
+		"balance_drops",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init balance_drops"))
+		return nil
+	}
+	return indvMetr
+}
@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var maxSampledValidators = 1000 // balances requested at every slot, in a single request
+
+// sampledValidators returns the monitored validators, sorted, whose balances are sampled at every slot
+func sampledValidators(tracker *voteTracker) ([]phase0.ValidatorIndex, error) {
+	if !tracker.Monitoring() {
+		return nil, fmt.Errorf("balance sampling requires the validators given in --monitor-validators")
+	}
+	if len(tracker.monitored) > maxSampledValidators {
+		return nil, fmt.Errorf("balance sampling is limited to %d validators, %d are monitored", maxSampledValidators, len(tracker.monitored))
+	}
+	validators := make([]phase0.ValidatorIndex, 0, len(tracker.monitored))
+	for valIdx := range tracker.monitored {
+		validators = append(validators, valIdx)
+	}
+	sort.Slice(validators, func(i, j int) bool { return validators[i] < validators[j] })
+	return validators, nil
+}
+
+// processBalanceSamples requests the balances of the sampled validators in the state of the slot and persists them,
+// so that balance drops can be located at the slot instead of the epoch
+func (s *ChainAnalyzer) processBalanceSamples(slot phase0.Slot) {
+	if len(s.sampledValidators) == 0 {
+		return
+	}
+	balances, err := s.cli.RequestValidatorBalances(slot, s.sampledValidators)
+	if err != nil {
+		log.Errorf("could not sample the validator balances at slot %d: %s", slot, err)
+		return
+	}
+
+	samples := make([]spec.ValidatorBalanceSample, 0, len(balances))
+	for _, valIdx := range s.sampledValidators {
+		balance, ok := balances[valIdx]
+		if !ok { // not in the registry yet
+			continue
+		}
+		samples = append(samples, spec.ValidatorBalanceSample{
+			Slot:           slot,
+			ValidatorIndex: valIdx,
+			Balance:        balance,
+		})
+	}
+	if len(samples) == 0 {
+		return
+	}
+	if err := s.dbClient.PersistValidatorBalanceSamples(samples); err != nil {
+		log.Errorf("error persisting balance samples of slot %d: %s", slot, err)
+	}
+}
@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	prom_metrics "github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	HeadBlockArrivalDelay = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "head_block_arrival_delay_ms",
+		Help:      "Milliseconds between the slot start and the arrival of each head block processed",
+		Buckets:   []float64{250, 500, 1000, 2000, 3000, 4000, 6000, 8000, 12000},
+	})
+	LateHeadBlocks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "late_head_blocks",
+		Help:      "The number of head blocks received late, as f_late in t_block_arrivals",
+	})
+)
+
+// headArrivals keeps the time at which each head block was received (unix ms),
+// until the block is processed
+type headArrivals struct {
+	sync.Mutex
+	arrivals map[phase0.Slot]int64
+}
+
+func newHeadArrivals() *headArrivals {
+	return &headArrivals{
+		arrivals: make(map[phase0.Slot]int64),
+	}
+}
+
+// Record keeps the first arrival of the slot, later ones are the same block again (or a reorg)
+func (h *headArrivals) Record(slot phase0.Slot, arrivalTimestamp int64) {
+	h.Lock()
+	defer h.Unlock()
+	if _, ok := h.arrivals[slot]; !ok {
+		h.arrivals[slot] = arrivalTimestamp
+	}
+}
+
+// Pop returns and removes the arrival of the slot
+func (h *headArrivals) Pop(slot phase0.Slot) (int64, bool) {
+	h.Lock()
+	defer h.Unlock()
+	arrival, ok := h.arrivals[slot]
+	delete(h.arrivals, slot)
+	return arrival, ok
+}
+
+// CleanUpTo removes the arrivals of slots that were never processed (reorged)
+func (h *headArrivals) CleanUpTo(maxSlot phase0.Slot) {
+	h.Lock()
+	defer h.Unlock()
+	for slot := range h.arrivals {
+		if slot < maxSlot {
+			delete(h.arrivals, slot)
+		}
+	}
+}
+
+// processBlockArrival persists how late the block was, only for blocks received through head events
+func (s *ChainAnalyzer) processBlockArrival(block *spec.AgnosticBlock) {
+	arrivalTimestamp, ok := s.headArrivals.Pop(block.Slot)
+	if !ok || !block.Proposed {
+		return
+	}
+
+	arrival := spec.NewBlockArrival(*block, s.genesisTime, arrivalTimestamp)
+	HeadBlockArrivalDelay.Observe(float64(arrival.ArrivalDelay))
+	if statsd := s.PromMetrics.Statsd(); statsd != nil {
+		if err := statsd.Timing("head_block_arrival_delay", float64(arrival.ArrivalDelay)); err != nil {
+			log.Warnf("could not send the block arrival delay to statsd: %s", err.Error())
+		}
+	}
+	if arrival.Late {
+		LateHeadBlocks.Inc()
+		log.Warnf("late block at slot %d (proposer %d): received %d ms after the slot start", block.Slot, block.ProposerIndex, arrival.ArrivalDelay)
+	}
+
+	err := s.dbClient.PersistBlockArrivals([]spec.BlockArrival{arrival})
+	if err != nil {
+		log.Errorf("error persisting block arrival: %s", err.Error())
+	}
+}
+
+func (s *ChainAnalyzer) getHeadBlockArrival() *prom_metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(HeadBlockArrivalDelay)
+		prometheus.MustRegister(LateHeadBlocks)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return nil, nil
+	}
+
+	indvMetr, err := prom_metrics.NewIndvMetrics(
+		"head_block_arrival",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init head_block_arrival"))
+		return nil
+	}
+	return indvMetr
+}
@@ -0,0 +1,189 @@
+package analyzer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+)
+
+// parseBranchRoots reads a comma separated list of block roots
+func parseBranchRoots(input string) ([]phase0.Root, error) {
+	roots := make([]phase0.Root, 0)
+	for _, item := range strings.Split(input, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		decoded, err := hex.DecodeString(strings.TrimPrefix(item, "0x"))
+		if err != nil || len(decoded) != len(phase0.Root{}) {
+			return nil, fmt.Errorf("invalid block root: %s", item)
+		}
+		roots = append(roots, phase0.Root(decoded))
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no block roots provided")
+	}
+	return roots, nil
+}
+
+// runBranch analyzes the given chain of block roots, which is not expected to be canonical (i.e. an orphaned branch),
+// requesting its blocks and states by root. Blocks are persisted in t_orphans and the epoch metrics in t_orphan_epoch_metrics
+func (s *ChainAnalyzer) runBranch() {
+	defer s.wgMainRoutine.Done()
+
+	if err := s.analyzeBranch(s.branchRoots); err != nil {
+		log.Errorf("could not analyze the branch: %s", err)
+	}
+}
+
+func (s *ChainAnalyzer) analyzeBranch(roots []phase0.Root) error {
+	log.Infof("analyzing branch of %d blocks", len(roots))
+
+	blocks := make([]*spec.AgnosticBlock, 0, len(roots))
+	for _, root := range roots {
+		block, err := s.cli.RequestBeaconBlockByRoot(root)
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Slot < blocks[j].Slot
+	})
+	for i := 1; i < len(blocks); i++ {
+		if blocks[i].ParentRoot != blocks[i-1].Root {
+			return fmt.Errorf("block %s at slot %d is not a child of block %s, the roots must form a chain", blocks[i].Root, blocks[i].Slot, blocks[i-1].Root)
+		}
+	}
+
+	orphans := make([]spec.AgnosticBlock, 0, len(blocks))
+	for _, block := range blocks {
+		orphans = append(orphans, *block)
+	}
+	if err := s.dbClient.PersistOrphans(orphans); err != nil {
+		return err
+	}
+
+	if !s.metrics.Epoch {
+		return nil
+	}
+
+	// the parent of the first block is where the branch forked from the canonical chain
+	parent, err := s.cli.RequestBeaconBlockByRoot(blocks[0].ParentRoot)
+	if err != nil {
+		return err
+	}
+	chain := append([]*spec.AgnosticBlock{parent}, blocks...)
+
+	// the rest of the slots of the epoch are empty in the branch,
+	// so the state of its last block in the epoch acts as the state at the end of the epoch
+	lastBlocks := make(map[phase0.Epoch]*spec.AgnosticBlock)
+	epochs := make([]phase0.Epoch, 0)
+	for i, block := range chain {
+		epoch := phase0.Epoch(block.Slot / spec.SlotsPerEpoch)
+		if _, ok := lastBlocks[epoch]; !ok && i > 0 {
+			epochs = append(epochs, epoch)
+		}
+		lastBlocks[epoch] = block
+	}
+
+	states := make(map[phase0.Epoch]*spec.AgnosticState)
+	branchState := func(epoch phase0.Epoch) (*spec.AgnosticState, error) {
+		if state, ok := states[epoch]; ok {
+			return state, nil
+		}
+		var state *spec.AgnosticState
+		var err error
+		if block, ok := lastBlocks[epoch]; ok {
+			state, err = s.downloadBranchState(block, chain)
+		} else if lastSlot := phase0.Slot((epoch+1)*spec.SlotsPerEpoch - 1); lastSlot < parent.Slot {
+			// before the fork, the canonical chain is the one of the branch
+			state, err = s.downloadEpochState(epoch, nil)
+		} else {
+			err = fmt.Errorf("epoch %d has no blocks in the branch", epoch)
+		}
+		if err != nil {
+			return nil, err
+		}
+		states[epoch] = state
+		return state, nil
+	}
+
+	for _, epoch := range epochs {
+		if epoch < 2 {
+			log.Warnf("skipping epoch %d of the branch, two previous epochs are needed", epoch)
+			continue
+		}
+		nextState, err := branchState(epoch)
+		if err != nil {
+			return err
+		}
+		currentState, err := branchState(epoch - 1)
+		if err != nil {
+			log.Warnf("skipping epoch %d of the branch: %s", epoch, err)
+			continue
+		}
+		prevState, err := branchState(epoch - 2)
+		if err != nil {
+			log.Warnf("skipping epoch %d of the branch: %s", epoch, err)
+			continue
+		}
+
+		bundle, err := metrics.StateMetricsByForkVersion(nextState, currentState, prevState, s.cli.Api)
+		if err != nil {
+			return err
+		}
+		epochMetrics := bundle.GetMetricsBase().ExportToEpoch()
+		if err := s.dbClient.PersistOrphanEpochs([]spec.Epoch{epochMetrics}); err != nil {
+			return err
+		}
+		log.Infof("epoch %d of the branch processed", epoch)
+	}
+	log.Infof("branch analyzed")
+	return nil
+}
+
+// downloadBranchState downloads the state after the given block of the branch, adding the blocks of the epoch:
+// the ones in the branch, the previous ones (by the roots in the state) and missed blocks after the given one
+func (s *ChainAnalyzer) downloadBranchState(lastBlock *spec.AgnosticBlock, chain []*spec.AgnosticBlock) (*spec.AgnosticState, error) {
+	state, err := s.cli.RequestBeaconStateByRoot(lastBlock.StateRoot, lastBlock.Slot)
+	if err != nil {
+		return nil, err
+	}
+
+	bySlot := make(map[phase0.Slot]*spec.AgnosticBlock, len(chain))
+	for _, block := range chain {
+		bySlot[block.Slot] = block
+	}
+
+	firstSlot := phase0.Slot(state.Epoch * spec.SlotsPerEpoch)
+	blocks := make([]*spec.AgnosticBlock, 0, spec.SlotsPerEpoch)
+	missedBlocks := make([]phase0.Slot, 0)
+	for slot := firstSlot; slot < firstSlot+spec.SlotsPerEpoch; slot++ {
+		block, ok := bySlot[slot]
+		switch {
+		case ok:
+		case slot < lastBlock.Slot && !slices.Contains(state.MissedBlocks, slot):
+			// block roots are only filled until the state slot
+			block, err = s.cli.RequestBeaconBlockByRoot(state.GetBlockRootAtSlot(slot))
+			if err != nil {
+				return nil, fmt.Errorf("could not download block at slot %d: %s", slot, err)
+			}
+		default:
+			block = s.cli.CreateMissingBlock(slot)
+		}
+		if !block.Proposed {
+			missedBlocks = append(missedBlocks, slot)
+		}
+		blocks = append(blocks, block)
+	}
+	state.MissedBlocks = missedBlocks
+	state.AddBlocks(blocks)
+	return state, nil
+}
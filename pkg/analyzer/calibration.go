@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/migalabs/goteth/pkg/db"
+)
+
+// calibration measures the first epochs processed to select the workers computing the validator rewards and
+// the database connections, so that neither of them falls behind the state downloads
+type calibration struct {
+	sync.Mutex
+	epochs int // epochs to measure, 0 once the workers are selected
+
+	measured       int
+	download       time.Duration // added up over the states downloaded
+	downloads      int
+	rewards        time.Duration // computation of the validator rewards, added up over the epochs measured
+	rewardsWorkers int           // workers computing them while measured
+	writeStart     time.Duration // write time of the database when the first epoch was measured
+	writes         time.Duration // write time over the epochs measured
+}
+
+func newCalibration(epochs int) *calibration {
+	if epochs <= 0 {
+		return nil
+	}
+	return &calibration{epochs: epochs}
+}
+
+func (c *calibration) StateDownloaded(elapsed time.Duration) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.download += elapsed
+	c.downloads++
+}
+
+func (c *calibration) RewardsComputed(elapsed time.Duration, workers int) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.rewards += elapsed
+	c.rewardsWorkers = workers
+}
+
+// EpochProcessed adds the epoch to the measures, given the write time of the database so far.
+// It returns whether it was the last epoch to measure
+func (c *calibration) EpochProcessed(writeTime time.Duration) bool {
+	if c == nil {
+		return false
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.epochs == 0 {
+		return false
+	}
+	if c.measured == 0 {
+		c.writeStart = writeTime
+	}
+	c.measured++
+	c.writes = writeTime - c.writeStart
+	if c.measured <= c.epochs {
+		return false
+	}
+	c.epochs = 0
+	return true
+}
+
+// calibrationResult is what an epoch takes on average, and the workers selected from it
+type calibrationResult struct {
+	Download  time.Duration // of a state
+	Rewards   time.Duration // validator rewards computed by a single worker
+	Writes    time.Duration // database writes, one after the other
+	Workers   int           // 0 if the validator rewards were not computed
+	DBWorkers int
+}
+
+// Result selects the workers that compute the validator rewards of an epoch and write its batches
+// within the time a state takes to download, so the processor keeps up with the downloads.
+// The first epoch measured only marks the start of the write time
+func (c *calibration) Result(cpus int) calibrationResult {
+	c.Lock()
+	defer c.Unlock()
+
+	result := calibrationResult{DBWorkers: 1}
+	epochs := max(c.measured-1, 1)
+	if c.downloads > 0 {
+		result.Download = c.download / time.Duration(c.downloads)
+	}
+	result.Rewards = c.rewards * time.Duration(max(c.rewardsWorkers, 1)) / time.Duration(c.measured)
+	result.Writes = c.writes / time.Duration(epochs)
+	if result.Download == 0 {
+		return result
+	}
+	if result.Rewards > 0 {
+		result.Workers = max(min(int((result.Rewards+result.Download-1)/result.Download), cpus, maxWorkers), 1)
+	}
+	result.DBWorkers = max(min(int((result.Writes+result.Download-1)/result.Download), db.MaxWriters), 1)
+	return result
+}
+
+// processCalibration measures the epoch just processed and, after the last one, applies the workers selected
+func (s *ChainAnalyzer) processCalibration() {
+	if !s.calibration.EpochProcessed(s.dbClient.WriteTime()) {
+		return
+	}
+	result := s.calibration.Result(runtime.NumCPU())
+	log.Infof("calibration: state download %s, validator rewards %s (single worker), database writes %s per epoch",
+		result.Download, result.Rewards, result.Writes)
+	if result.Workers > 0 {
+		log.Infof("calibration: selected workers-num=%d (was %d)", result.Workers, s.rewardsWorkers.Load())
+		s.rewardsWorkers.Store(int32(result.Workers))
+	}
+	log.Infof("calibration: selected db-workers-num=%d (was %d)", result.DBWorkers, s.dbClient.Writers())
+	if err := s.dbClient.SetWriters(result.DBWorkers); err != nil {
+		log.Errorf("could not apply the calibrated database workers: %s", err.Error())
+	}
+}
@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalibration(t *testing.T) {
+	assert.Nil(t, newCalibration(0))
+	assert.False(t, (*calibration)(nil).EpochProcessed(time.Second))
+
+	c := newCalibration(2)
+	c.StateDownloaded(10 * time.Second)
+	c.StateDownloaded(10 * time.Second)
+
+	// rewards take 20s per epoch with 2 workers (40s with a single one), writes 25s per epoch
+	writeTime := 5 * time.Second
+	for i := 0; i < 2; i++ {
+		c.RewardsComputed(20*time.Second, 2)
+		assert.False(t, c.EpochProcessed(writeTime))
+		writeTime += 25 * time.Second
+	}
+	c.RewardsComputed(20*time.Second, 2)
+	assert.True(t, c.EpochProcessed(writeTime))
+	assert.False(t, c.EpochProcessed(writeTime)) // selected once
+
+	result := c.Result(16)
+	assert.Equal(t, 10*time.Second, result.Download)
+	assert.Equal(t, 40*time.Second, result.Rewards)
+	assert.Equal(t, 25*time.Second, result.Writes)
+	assert.Equal(t, 4, result.Workers)
+	assert.Equal(t, 3, result.DBWorkers)
+
+	// limited by the cpus
+	assert.Equal(t, 2, c.Result(2).Workers)
+}
+
+func TestCalibrationWithoutRewards(t *testing.T) {
+	c := newCalibration(1)
+	c.StateDownloaded(10 * time.Second)
+	c.EpochProcessed(0)
+	assert.True(t, c.EpochProcessed(time.Second))
+
+	result := c.Result(16)
+	assert.Equal(t, 0, result.Workers) // keep the configured ones
+	assert.Equal(t, 1, result.DBWorkers)
+}
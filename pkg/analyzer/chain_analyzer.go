@@ -3,16 +3,22 @@ package analyzer
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/migalabs/goteth/pkg/chaintime"
 	"github.com/migalabs/goteth/pkg/clientapi"
 	"github.com/migalabs/goteth/pkg/config"
 	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/graphql"
 	prom_metrics "github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/notifier"
 	"github.com/migalabs/goteth/pkg/relay"
+	"github.com/migalabs/goteth/pkg/sink"
 	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
 	"github.com/migalabs/goteth/pkg/utils"
 
 	"github.com/migalabs/goteth/pkg/events"
@@ -26,6 +32,8 @@ type ChainAnalyzer struct {
 	// Chain Variables
 	beaconContractAddress common.Address
 
+	genesisTime time.Time
+
 	// Slot Range for historical
 	initSlot  phase0.Slot
 	finalSlot phase0.Slot
@@ -38,21 +46,104 @@ type ChainAnalyzer struct {
 	relayCli  *relay.RelaysMonitor // client to monitor all relays in list
 	eventsObj events.Events        // object to receive signals from beacon node
 	dbClient  *db.DBService        // client to communicate with clickhouse
+	kafkaSink *sink.KafkaSink      // optional, publishes the processed metrics to kafka
 
 	// Control Variables
 	wgMainRoutine            *sync.WaitGroup    // wait group for main routine (either historical or head)
 	wgDownload               *sync.WaitGroup    // wait group for download routine
+	wgBackground             *sync.WaitGroup    // wait group for the routines that last until the context is cancelled
 	stop                     bool               // flag to notify all routine to finish
-	routineClosed            chan struct{}      // signal that everything was closed succesfully
+	done                     chan struct{}      // closed once Run returns, everything was closed
+	shutdownGrace            time.Duration      // time Close waits for the routines to finish before aborting them
 	downloadMode             string             // whether to download historical blocks (defined by user) or follow chain head
 	rewardsAggregationEpochs int                // number of epochs to aggregate rewards
 	startEpochAggregation    phase0.Epoch       // epoch to start rewards aggregation
 	endEpochAggregation      phase0.Epoch       // epoch to end rewards aggregation
+	rewardsWindows           bool               // whether to maintain the 1d/7d/30d validator rewards windows
+	rewardsRetentionEpochs   phase0.Epoch       // epochs of validator rewards to keep, 0 keeps all
+	adminToken               string             // token for the admin endpoints (i.e. reprocess), disabled if empty
+	statusCheckEpochs        phase0.Epoch       // epochs between validator status cross-checks with the beacon node, 0 disables them
+	reprocessChan            chan phase0.Epoch  // epochs queued to be reprocessed
 	metrics                  db.DBMetrics       // what metrics to be downloaded / processed
 	processerBook            *utils.RoutineBook // defines slot to process new metrics into the database, good for monitoring
 
-	downloadCache                 ChainCache // store the blocks and states downloaded
+	downloadCache                 ChainCache    // store the blocks and states downloaded
+	headArrivals                  *headArrivals // arrival time of the head blocks, to detect late blocks
+	slotRegistry                  *slotRegistry // slots already taken by the download routine, so overlapping routines do not repeat them
+	voteArrivals                  *voteArrivals // first time each vote was seen, nil unless attestation events are followed
+	headLag                       *headLag      // how far behind the head the analyzer is
+	voteTracker                   *voteTracker  // recent votes of the monitored validators, to detect equivocations
+	chainSplits                   *chainSplits  // divergences between the main beacon node and the extra ones
 	validatorsRewardsAggregations map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation
+	rewardsDelta                  *spec.RewardsDeltaEncoder // nil unless validator rewards are delta-encoded
+
+	rulesEngine *notifier.RulesEngine // nil unless notification rules are configured
+	notifier    *notifier.Notifier
+	branchRoots []phase0.Root // blocks of the non-canonical branch analyzed in branch mode
+
+	stateSetups *metrics.SetupCache // consecutive transitions reuse the setup of their common states
+
+	epochCompleteness *epochCompleteness // block metric families persisted without errors over the slots of each epoch
+
+	liveParticipation *liveParticipation // epoch of the last participation estimate published from a head state
+
+	pubkeyRegistry *spec.PubkeyRegistry // public keys of the registry indexed by the setup cache, nil unless the registry check is enabled
+
+	txStorage spec.TxStorage // whether the calldata and receipt logs of the transactions are stored
+
+	selfCheck bool // compare the checkpoints and a sample of balances of every state with the beacon node
+
+	voteTallies *voteTallies // votes included so far for the epochs at the head
+
+	failures *failureRecorder // epochs and metrics that failed to process, for the failure report
+
+	journal *downloadJournal // slots and epochs committed to the database, nil unless enabled
+
+	downtime *downtimeWindows // planned maintenance windows of the validators, to tag their missed attestations
+
+	sampledValidators []phase0.ValidatorIndex // validators whose balances are sampled at every slot, empty unless enabled
+
+	nodeThrottle *nodeThrottle // slows down the downloads while the beacon node is unhealthy
+
+	epochWatchdog *epochWatchdog // flags the epoch transitions in flight for too long
+
+	stateFallbackSlots    int           // distance of the slots whose state replaces one that cannot be decoded, 0 disables it
+	stateSubstitutionsNum atomic.Uint64 // states replaced since the start
+
+	stateProofs int // validators proven against the root of every downloaded state, 0 if disabled
+
+	warmRestart bool // resume from the last canonical head instead of the finalized checkpoint
+
+	checkpointRoots *checkpointRoots // canonical checkpoints of the recent epochs, to rate the attestation targets
+
+	proposerFairness       *spec.ProposerFairnessTracker // expected and assigned proposer duties of every validator
+	proposerFairnessEpochs phase0.Epoch                  // epochs between the snapshots persisted, 0 disables the tracking
+	proposerFairnessOnce   sync.Once                     // restores the last snapshot
+
+	progress *progressSummary // blocks and states handled since the last epoch summary
+
+	validatorKeys     bool      // maintain the public key to index table
+	validatorKeysOnce sync.Once // writes the whole registry the first time
+
+	finalityAlerts *finalityAlerts // distance to the finalized checkpoint, notified when it exceeds the thresholds
+
+	headerOnlyTxs *headerOnlyTxs // transactions persisted without receipts, nil unless there is no execution endpoint
+
+	prefetch *prefetchWindow // epochs downloaded ahead of the processor
+
+	clientDiversityEpochs phase0.Epoch // epochs covered by (and between) the pool client diversity snapshots, 0 disables them
+
+	customPools *customPools // sources of t_eth2_pubkeys, nil if not given
+
+	slashingProtection *slashingProtection // signing history of the operator validators, nil if not given
+
+	exitEvents exitEventsBuffer // voluntary exit events waiting to be written, only used by the head routine
+
+	rewardsWorkers atomic.Int32 // workers computing the validator rewards of an epoch
+	calibration    *calibration // measures the first epochs to select the workers, nil unless enabled
+
+	balanceDropOfflineShare float64       // share of the monitored validators offline at once notified as a mass offline event, 0 disables it
+	balanceDropsNum         atomic.Uint64 // balance drops notified since the start
 
 	initTime    time.Time
 	PromMetrics *prom_metrics.PrometheusMetrics // metrics to be stored to prometheus
@@ -67,6 +158,17 @@ func NewChainAnalyzer(
 
 	// generate the central exporting service
 	promethMetrics := prom_metrics.NewPrometheusMetrics(ctx, "0.0.0.0", iConfig.PrometheusPort)
+	if iConfig.StatsdAddress != "" {
+		statsd, err := prom_metrics.NewStatsdExporter(iConfig.StatsdAddress, iConfig.StatsdPrefix, iConfig.StatsdTags)
+		if err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to set up the statsd exporter")
+		}
+		promethMetrics.SetStatsd(statsd)
+		log.Infof("pushing the metrics to statsd at %s", iConfig.StatsdAddress)
+	}
 
 	startEpochAggregation := phase0.Epoch(0)
 	endEpochAggregation := phase0.Epoch(0)
@@ -81,7 +183,7 @@ func NewChainAnalyzer(
 			}, errors.Errorf("Final Slot cannot be greater than Init Slot")
 		}
 		// Start 2 epochs before and finish 1 epoch after
-		iConfig.InitSlot = iConfig.InitSlot/spec.SlotsPerEpoch*spec.SlotsPerEpoch - spec.SlotsPerEpoch*2
+		iConfig.InitSlot = slotsBefore(iConfig.InitSlot/spec.SlotsPerEpoch*spec.SlotsPerEpoch, spec.SlotsPerEpoch*2)
 		iConfig.FinalSlot = iConfig.FinalSlot/spec.SlotsPerEpoch*spec.SlotsPerEpoch + spec.SlotsPerEpoch
 		log.Infof("generating new Block Analyzer from slots %d:%d", iConfig.InitSlot, iConfig.FinalSlot)
 		// 2 epochs after the start since thats when we start processing rewards
@@ -97,8 +199,58 @@ func NewChainAnalyzer(
 			cancel: cancel,
 		}, errors.Wrap(err, "unable to read metric.")
 	}
+	metricsObj, err = applyMode(iConfig.Mode, metricsObj)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "unable to apply mode.")
+	}
+
+	workers := iConfig.WorkerNum
+	if workers < 1 {
+		log.Warnf("the number of workers must be at least 1, got %d: using 1", workers)
+		workers = 1
+	} else if workers > maxWorkers {
+		log.Warnf("%d workers compute the validator rewards, over %d they rarely help", workers, maxWorkers)
+	}
+	dbWorkers := min(max(iConfig.DbWorkerNum, 1), db.MaxWriters)
+	if dbWorkers != iConfig.DbWorkerNum {
+		log.Warnf("the number of database workers must be between 1 and %d, got %d: using %d", db.MaxWriters, iConfig.DbWorkerNum, dbWorkers)
+	}
 
-	idbClient, err := db.New(ctx, iConfig.DBUrl)
+	dbOptions := []db.DBServiceOption{
+		db.WithSchema(iConfig.DBSchema),
+		db.WithWriters(dbWorkers),
+		db.WithReconnectTimeout(time.Duration(iConfig.DBReconnectTimeout) * time.Second),
+	}
+	var rewardsDelta *spec.RewardsDeltaEncoder
+	switch iConfig.RewardsStorage {
+	case rewardsStorageFull:
+	case rewardsStorageDelta:
+		dbOptions = append(dbOptions, db.WithRewardsDeltaEncoding())
+	default:
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Errorf("unknown rewards storage: %s", iConfig.RewardsStorage)
+	}
+	if iConfig.DBDualWriteUrl != "" {
+		dbOptions = append(dbOptions, db.WithDualWrite(iConfig.DBDualWriteUrl))
+	}
+	if iConfig.RewardsShards != "" {
+		dbOptions = append(dbOptions, db.WithRewardsShards(iConfig.RewardsShards))
+	}
+
+	txStorage, err := spec.NewTxStorage(iConfig.TxStorage)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, err
+	}
+
+	idbClient, err := db.New(ctx, iConfig.DBUrl, dbOptions...)
 	if err != nil {
 		return &ChainAnalyzer{
 			ctx:    ctx,
@@ -113,14 +265,56 @@ func NewChainAnalyzer(
 			cancel: cancel,
 		}, errors.Wrap(err, "unable to connect DB Client.")
 	}
+	if iConfig.RewardsStorage == rewardsStorageDelta {
+		// keyframes persisted before a restart are loaded from the database
+		rewardsDelta = spec.NewRewardsDeltaEncoder(iConfig.RewardsKeyframeEpochs, idbClient.RetrieveValidatorRewardsKeyframe)
+	}
+
+	// the first beacon endpoint is the one queried, the rest are compared with it to detect chain splits
+	bnEndpoint := iConfig.BnEndpoint
+	bnPeers := make([]string, 0)
+	if bnEndpoints := clientapi.ParseEndpoints(iConfig.BnEndpoint); len(bnEndpoints) > 0 {
+		bnEndpoint = bnEndpoints[0]
+		bnPeers = bnEndpoints[1:]
+	}
+
+	bnHeaders, err := clientapi.ParseNodeHeaders(iConfig.BnAuth, iConfig.BnHeaders)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "invalid beacon node auth.")
+	}
+	elHeaders, err := clientapi.ParseNodeHeaders(iConfig.ElAuth, iConfig.ElHeaders)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "invalid execution node auth.")
+	}
+	forkSchedule, err := spec.ParseForkSchedule(iConfig.ForkSchedule)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "invalid fork schedule.")
+	}
+	metrics.UseForkSchedule(forkSchedule)
 
 	// generate the httpAPI client
 	cli, err := clientapi.NewAPIClient(pCtx,
-		iConfig.BnEndpoint,
+		bnEndpoint,
 		iConfig.MaxRequestRetries,
+		clientapi.WithNodeHeaders(bnHeaders, elHeaders), // before connecting to the nodes
 		clientapi.WithELEndpoint(iConfig.ElEndpoint),
+		clientapi.WithELConcurrency(iConfig.ElConcurrency),
+		clientapi.WithBNPeers(bnPeers),
 		clientapi.WithDBMetrics(metricsObj),
-		clientapi.WithPromMetrics(promethMetrics))
+		clientapi.WithPromMetrics(promethMetrics),
+		clientapi.WithGenesisTime(iConfig.GenesisTime),
+		clientapi.WithForkSchedule(forkSchedule),
+		clientapi.WithEraBlocksDir(iConfig.EraBlocksDir),
+		clientapi.WithStateProofs(iConfig.StateProofValidators))
 	if err != nil {
 		return &ChainAnalyzer{
 			ctx:    ctx,
@@ -128,6 +322,30 @@ func NewChainAnalyzer(
 		}, errors.Wrap(err, "unable to generate API Client.")
 	}
 
+	// fail fast if the execution endpoints cannot serve the receipts of the requested range,
+	// the ones that are not archive nodes are kept as head-only backups
+	if metricsObj.Transactions && cli.ELApi() != nil {
+		fromBlock, err := elHistoryStart(cli, iConfig)
+		if err != nil {
+			log.Warnf("skipping the archive check of the execution endpoints: %s", err)
+		} else if err := cli.CheckELHistory(fromBlock); err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "execution endpoints cannot serve the requested range.")
+		}
+	}
+
+	// without execution endpoint the transactions are stored from the block bodies, instead of failing every block
+	var headerOnly *headerOnlyTxs
+	if metricsObj.Transactions && cli.ELApi() == nil {
+		log.Warnf("no execution endpoint available, transactions are stored without receipts (gas used, logs and eth1 deposits are skipped)")
+		headerOnly = newHeaderOnlyTxs()
+	}
+	if iConfig.ClientDiversityEpochs > 0 && !metricsObj.Block {
+		log.Warnf("the pool client diversity needs the block metrics to read the graffiti, it will not be persisted")
+	}
+
 	// Parse beacon contract address
 	beaconContractAddressInput := iConfig.BeaconContractAddress
 	// check if input was a network name and the contract address is known
@@ -142,6 +360,12 @@ func NewChainAnalyzer(
 	beaconContractAddress := common.HexToAddress(beaconContractAddressInput)
 
 	genesisTime := cli.RequestGenesis()
+	if err := waitForGenesis(ctx, genesisTime); err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "chain did not reach genesis.")
+	}
 
 	// generate the relays client
 	relayCli, err := relay.InitRelaysMonitorer(pCtx, uint64(genesisTime.Unix()))
@@ -152,42 +376,283 @@ func NewChainAnalyzer(
 		}, errors.Wrap(err, "unable to generate API Client.")
 	}
 
-	idbClient.InitGenesis(genesisTime)
+	idbClient.InitGenesis(genesisTime, cli.RequestSecondsPerSlot())
+
+	var kafkaSink *sink.KafkaSink
+	if iConfig.KafkaBrokers != "" {
+		kafkaSink, err = sink.NewKafkaSink(ctx, iConfig.KafkaBrokers, sink.KafkaTopics{
+			Epochs:  iConfig.KafkaEpochsTopic,
+			Blocks:  iConfig.KafkaBlocksTopic,
+			Rewards: iConfig.KafkaRewardsTopic,
+		})
+		if err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to generate Kafka sink.")
+		}
+	}
+
+	voteTracker, err := newVoteTracker(iConfig.MonitorValidators)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "unable to parse the monitored validators.")
+	}
+
+	var balanceSampled []phase0.ValidatorIndex
+	if iConfig.SampleBalances {
+		balanceSampled, err = sampledValidators(voteTracker)
+		if err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to sample the validator balances.")
+		}
+	}
+
+	var branchRoots []phase0.Root
+	if iConfig.DownloadMode == "branch" {
+		branchRoots, err = parseBranchRoots(iConfig.BranchRoots)
+		if err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to parse the branch roots.")
+		}
+	}
+
+	var rulesEngine *notifier.RulesEngine
+	if iConfig.NotificationRules != "" {
+		rules, err := notifier.ReadRulesFile(iConfig.NotificationRules)
+		if err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to read the notification rules.")
+		}
+		log.Infof("evaluating %d notification rules after each epoch", len(rules))
+		rulesEngine = notifier.NewRulesEngine(rules)
+	}
+
+	var protection *slashingProtection
+	if iConfig.SlashingProtection != "" {
+		interchange, err := spec.ReadSlashingProtectionFile(iConfig.SlashingProtection)
+		if err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to read the slashing protection file.")
+		}
+		if !metricsObj.Block {
+			log.Warnf("the slashing protection audit needs the block metrics to read the blocks and votes, it will not run")
+		}
+		log.Infof("auditing %d validators against their slashing protection history", len(interchange.Data))
+		protection = newSlashingProtection(interchange)
+	}
+
+	var voteArrivals *voteArrivals
+	if iConfig.AttestationEvents {
+		voteArrivals = newVoteArrivals()
+	}
+
+	rewardsRetentionEpochs := phase0.Epoch(iConfig.RewardsRetentionEpochs)
+	if iConfig.RewardsWindows && rewardsRetentionEpochs > 0 && rewardsRetentionEpochs < db.RewardsBucketEpochs {
+		// the bucket being filled is aggregated from the validator rewards
+		log.Warnf("rewards retention raised to %d epochs to maintain the rewards windows", db.RewardsBucketEpochs)
+		rewardsRetentionEpochs = db.RewardsBucketEpochs
+	}
+
+	stateSetups := metrics.NewSetupCache(cli.Api, maxProcessers)
+	var pubkeyRegistry *spec.PubkeyRegistry
+	if iConfig.RegistryCheck {
+		pubkeyRegistry = stateSetups.Pubkeys()
+	}
+
+	downtime := newDowntimeWindows(chaintime.New(genesisTime))
+	if iConfig.DowntimeWindows != "" {
+		windows, err := ReadDowntimeWindowsFile(iConfig.DowntimeWindows)
+		if err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to read the downtime windows.")
+		}
+		for _, window := range windows {
+			if err := downtime.Add(window); err != nil {
+				return &ChainAnalyzer{
+					ctx:    ctx,
+					cancel: cancel,
+				}, errors.Wrap(err, "invalid downtime window.")
+			}
+		}
+		log.Infof("%d downtime windows registered", len(windows))
+	}
+
+	finalityThresholds, err := parseFinalityThresholds(iConfig.FinalityAlertEpochs)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "unable to parse the finality alert thresholds.")
+	}
+	if iConfig.BalanceDropOfflinePct < 0 || iConfig.BalanceDropOfflinePct > 100 {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Errorf("the balance drop offline percentage must be between 0 and 100, got %d", iConfig.BalanceDropOfflinePct)
+	}
+
+	var journal *downloadJournal
+	if iConfig.Journal != "" {
+		journal, err = openDownloadJournal(iConfig.Journal, metricsObj.Block, metricsObj.Epoch)
+		if err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to open the journal.")
+		}
+	}
 
 	analyzer := &ChainAnalyzer{
 		ctx:                           ctx,
 		cancel:                        cancel,
 		beaconContractAddress:         beaconContractAddress,
+		genesisTime:                   genesisTime,
 		initSlot:                      phase0.Slot(iConfig.InitSlot),
 		finalSlot:                     phase0.Slot(iConfig.FinalSlot),
 		downloadTaskChan:              make(chan phase0.Slot, rateLimit), // TODO: define size of buffer depending on performance
 		cli:                           cli,
 		relayCli:                      relayCli,
 		dbClient:                      idbClient,
-		routineClosed:                 make(chan struct{}, 1),
+		kafkaSink:                     kafkaSink,
+		done:                          make(chan struct{}),
+		shutdownGrace:                 time.Duration(iConfig.ShutdownGrace) * time.Second,
 		eventsObj:                     events.NewEventsObj(ctx, cli),
 		downloadMode:                  iConfig.DownloadMode,
 		rewardsAggregationEpochs:      iConfig.RewardsAggregationEpochs,
 		startEpochAggregation:         startEpochAggregation,
 		endEpochAggregation:           endEpochAggregation,
+		rewardsWindows:                iConfig.RewardsWindows,
+		rewardsRetentionEpochs:        rewardsRetentionEpochs,
+		rewardsDelta:                  rewardsDelta,
+		adminToken:                    iConfig.AdminToken,
+		statusCheckEpochs:             phase0.Epoch(iConfig.StatusCheckEpochs),
+		reprocessChan:                 make(chan phase0.Epoch, reprocessQueueSize),
 		metrics:                       metricsObj,
 		PromMetrics:                   promethMetrics,
 		downloadCache:                 NewQueue(),
+		headArrivals:                  newHeadArrivals(),
+		slotRegistry:                  newSlotRegistry(),
+		voteArrivals:                  voteArrivals,
+		headLag:                       newHeadLag(iConfig.LagAlarmSlots, iConfig.LagResumeSlots),
+		voteTracker:                   voteTracker,
+		chainSplits:                   newChainSplits(iConfig.ChainSplitSlots),
 		validatorsRewardsAggregations: make(map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation),
-		processerBook:                 utils.NewRoutineBook(32, "processer"), // one whole epoch
+		processerBook:                 utils.NewRoutineBook(maxProcessers, "processer"),
 		wgMainRoutine:                 &sync.WaitGroup{},
 		wgDownload:                    &sync.WaitGroup{},
+		wgBackground:                  &sync.WaitGroup{},
+
+		rulesEngine: rulesEngine,
+		notifier:    notifier.NewNotifier(ctx, iConfig.NotificationWebhook),
+		branchRoots: branchRoots,
+
+		stateSetups: stateSetups,
+
+		epochCompleteness: newEpochCompleteness(blockMetricFamilies(metricsObj.Block, metricsObj.Transactions)),
+
+		liveParticipation: newLiveParticipation(),
+
+		pubkeyRegistry: pubkeyRegistry,
+
+		txStorage: txStorage,
+
+		selfCheck: iConfig.SelfCheck,
+
+		voteTallies: newVoteTallies(),
+
+		failures: newFailureRecorder(),
+
+		journal: journal,
+
+		downtime: downtime,
+
+		sampledValidators: balanceSampled,
+
+		nodeThrottle: newNodeThrottle(
+			time.Duration(iConfig.NodeHealthInterval)*time.Second,
+			time.Duration(iConfig.NodeMaxLatency)*time.Millisecond,
+			iConfig.PauseBackfill),
+
+		epochWatchdog: newEpochWatchdog(time.Duration(iConfig.StuckEpochTimeout)*time.Second, iConfig.RequeueStuckEpochs),
+
+		stateFallbackSlots: max(iConfig.StateFallbackSlots, 0),
+
+		stateProofs: iConfig.StateProofValidators,
+
+		warmRestart: iConfig.WarmRestart,
+
+		checkpointRoots: newCheckpointRoots(),
+
+		proposerFairness:       spec.NewProposerFairnessTracker(),
+		proposerFairnessEpochs: phase0.Epoch(iConfig.ProposerFairnessEpochs),
+
+		progress: newProgressSummary(),
+
+		validatorKeys: iConfig.ValidatorKeys,
+
+		finalityAlerts: newFinalityAlerts(finalityThresholds),
+
+		headerOnlyTxs: headerOnly,
+
+		prefetch: newPrefetchWindow(uint64(max(iConfig.PrefetchMaxEpochs, 0)), uint64(max(iConfig.PrefetchMemory, 0))*1e6),
+
+		clientDiversityEpochs: phase0.Epoch(max(iConfig.ClientDiversityEpochs, 0)),
+
+		slashingProtection: protection,
+
+		customPools: newCustomPools(iConfig.CustomPools, time.Duration(max(iConfig.CustomPoolsRefresh, 0))*time.Second),
+
+		calibration: newCalibration(iConfig.CalibrateEpochs),
+
+		balanceDropOfflineShare: float64(iConfig.BalanceDropOfflinePct) / 100,
+	}
+	analyzer.rewardsWorkers.Store(int32(workers))
+	if analyzer.calibration != nil && !metricsObj.Epoch {
+		log.Warnf("the calibration measures the state downloads, it needs the epoch metrics and will not run")
+		analyzer.calibration = nil
+	}
+	if analyzer.customPools != nil {
+		if err := analyzer.restoreCustomPools(); err != nil {
+			return analyzer, errors.Wrap(err, "unable to restore the custom pools.")
+		}
+		if err := analyzer.loadCustomPools(); err != nil {
+			return analyzer, errors.Wrap(err, "unable to load the custom pools.")
+		}
 	}
 
 	analyzerMet := analyzer.GetPrometheusMetrics()
 	promethMetrics.AddMeticsModule(analyzerMet)
 	promethMetrics.AddMeticsModule(analyzer.processerBook.GetPrometheusMetrics())
 	promethMetrics.AddMeticsModule(idbClient.GetPrometheusMetrics())
+	if iConfig.GraphQL {
+		// the same format as the node auth, the header it sends is the one expected
+		graphqlHeaders, err := clientapi.ParseNodeHeaders(iConfig.GraphQLAuth, "")
+		if err != nil {
+			return analyzer, errors.Wrap(err, "invalid graphql auth.")
+		}
+		authorization := graphqlHeaders["Authorization"]
+		promethMetrics.AddHandler(graphql.Endpoint, graphql.WithAuth(authorization, graphql.NewHandler(idbClient)))
+		promethMetrics.AddHandler(rewardsSummaryEndpoint, graphql.WithAuth(authorization, analyzer.rewardsSummaryHandler))
+	}
 
 	return analyzer, nil
 }
 
 func (s *ChainAnalyzer) Run() {
+	defer close(s.done)
 	defer s.cancel()
 	// Get init time
 	s.initTime = time.Now()
@@ -203,6 +668,16 @@ func (s *ChainAnalyzer) Run() {
 		// Block requester + Task generator
 		s.wgMainRoutine.Add(1)
 
+		if resume, ok := s.journalResumeSlot(); ok && resume > s.initSlot && resume <= s.finalSlot {
+			s.initSlot = resume
+			s.startEpochAggregation = phase0.Epoch(spec.EpochAtSlot(s.initSlot) + 2)
+			s.endEpochAggregation = s.startEpochAggregation + phase0.Epoch(s.rewardsAggregationEpochs-1)
+		}
+		if initSlot := s.availableInitSlot(s.initSlot, s.finalSlot); initSlot != s.initSlot {
+			s.initSlot = initSlot
+			s.startEpochAggregation = phase0.Epoch(spec.EpochAtSlot(s.initSlot) + 2)
+			s.endEpochAggregation = s.startEpochAggregation + phase0.Epoch(s.rewardsAggregationEpochs-1)
+		}
 		go s.runHistorical(s.initSlot, s.finalSlot)
 	}
 
@@ -210,9 +685,29 @@ func (s *ChainAnalyzer) Run() {
 		// Block requester in finalized slots, not used for now
 		s.wgMainRoutine.Add(1)
 		go s.runHead()
+		s.goBackground(s.runHeadLagMonitor)
+		s.goBackground(s.runChainSplitMonitor)
+	}
+
+	if s.downloadMode == "branch" {
+		// blocks and states of a non-canonical branch, requested by root
+		s.wgMainRoutine.Add(1)
+		go s.runBranch()
 	}
 
+	s.PromMetrics.AddHandler(routinesEndpoint, s.routinesHandler)
+	if s.adminToken != "" {
+		s.PromMetrics.AddHandler(reprocessEndpoint, s.reprocessHandler)
+		s.PromMetrics.AddHandler(downtimeEndpoint, s.downtimeHandler)
+	}
+	if s.adminToken != "" || s.epochWatchdog.requeue { // stuck epochs are requeued as reprocessing
+		s.goBackground(s.runReprocessing)
+	}
 	s.PromMetrics.Start()
+	s.goBackground(s.runRoutinesSummary)
+	s.goBackground(s.runNodeHealthMonitor)
+	s.goBackground(s.runEpochWatchdog)
+	s.goBackground(s.runCustomPoolsRefresh)
 
 	s.wgMainRoutine.Wait()
 	s.stop = true
@@ -223,15 +718,58 @@ func (s *ChainAnalyzer) Run() {
 	log.Infof("downloader finished, waiting for db client...")
 
 	s.dbClient.Finish()
+	if s.journal != nil {
+		s.journal.Close()
+	}
+	if s.kafkaSink != nil {
+		if err := s.kafkaSink.Close(); err != nil {
+			log.Errorf("could not close kafka sink: %s", err)
+		}
+	}
+
+	s.cancel()
+	s.wgBackground.Wait()
 
 	totalTime += int64(time.Since(start).Seconds())
 	analysisDuration := time.Since(s.initTime).Seconds()
 	log.Info("Blocks Analyzer finished in ", analysisDuration)
-	s.routineClosed <- struct{}{}
+	if s.headerOnlyTxs != nil {
+		if summary := s.headerOnlyTxs.Summary(); summary != nil {
+			log.Warnf("%d transactions of %d blocks (slots %d to %d) stored without receipts, skipped: %v",
+				summary.Transactions, summary.Blocks, summary.FirstSlot, summary.LastSlot, summary.Skipped)
+		}
+	}
 }
 
+// goBackground launches a routine that lasts until the context is cancelled, Run waits for it before returning
+func (s *ChainAnalyzer) goBackground(routine func()) {
+	s.wgBackground.Add(1)
+	go func() {
+		defer s.wgBackground.Done()
+		routine()
+	}()
+}
+
+// Close asks the routines to finish and waits for Run to return during the grace period.
+// Once it expires, the context is cancelled to abort the pending requests and writes,
+// and Close returns after forcedShutdownTimeout even if Run did not
 func (s *ChainAnalyzer) Close() {
 	log.Info("Sudden closed detected, closing StateAnalyzer")
 	s.stop = true
-	<-s.routineClosed // Wait for services to stop before returning
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownGrace)
+	defer cancel()
+	select {
+	case <-s.done:
+		return
+	case <-ctx.Done():
+		log.Warnf("routines did not finish in %s, forcing the shutdown", s.shutdownGrace)
+	}
+
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-time.After(forcedShutdownTimeout):
+		log.Errorf("routines did not finish after aborting them, exiting anyway")
+	}
 }
@@ -38,10 +38,29 @@ func (s *ChainCache) AddNewState(newState *spec.AgnosticState) {
 	// the 32 blocks were retrieved
 	newState.AddBlocks(blockList)
 
+	s.shareValidatorRegistry(newState)
+
 	s.StateHistory.Set(EpochTo[uint64](newState.Epoch), newState)
 	log.Debugf("state at slot %d successfully added to the queue", newState.Slot)
 }
 
+// shareValidatorRegistry points the unchanged validator entries of the new state
+// to the ones of a consecutive state already in the cache, to avoid keeping duplicates in memory
+func (s *ChainCache) shareValidatorRegistry(newState *spec.AgnosticState) {
+	epoch := EpochTo[uint64](newState.Epoch)
+
+	neighbour, ok := s.StateHistory.Get(epoch - 1)
+	if !ok || epoch == 0 {
+		neighbour, ok = s.StateHistory.Get(epoch + 1)
+	}
+	if !ok {
+		return
+	}
+	shared := newState.ShareValidatorRegistry(neighbour)
+	log.Tracef("state at epoch %d shares %d/%d validator entries with epoch %d",
+		newState.Epoch, shared, len(newState.Validators), neighbour.Epoch)
+}
+
 func (s *ChainCache) AddNewBlock(block *spec.AgnosticBlock) {
 
 	keys := s.BlockHistory.GetKeyList()
@@ -86,4 +105,11 @@ func (s *ChainCache) CleanUpTo(maxSlot phase0.Slot) {
 		}
 	}
 
+	// blocks of epochs without state (blocks-only mode)
+	for _, slot := range s.BlockHistory.GetKeyList() {
+		if slot < uint64(maxSlot) {
+			s.BlockHistory.Delete(slot)
+		}
+	}
+
 }
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func cacheState(epoch phase0.Epoch, balances ...phase0.Gwei) *spec.AgnosticState {
+	state := &spec.AgnosticState{Epoch: epoch}
+	for i, balance := range balances {
+		state.Validators = append(state.Validators, &phase0.Validator{PublicKey: phase0.BLSPubKey{byte(i)}, EffectiveBalance: balance})
+	}
+	return state
+}
+
+func TestChainCacheShareValidatorRegistry(t *testing.T) {
+	cache := NewQueue()
+
+	// the previous epoch is preferred
+	prev := cacheState(9, 32_000_000_000, 32_000_000_000)
+	next := cacheState(11, 32_000_000_000, 32_000_000_000)
+	cache.StateHistory.Set(9, prev)
+	cache.StateHistory.Set(11, next)
+
+	state := cacheState(10, 32_000_000_000, 31_000_000_000)
+	cache.shareValidatorRegistry(state)
+	assert.Same(t, prev.Validators[0], state.Validators[0])
+	assert.NotSame(t, prev.Validators[1], state.Validators[1])
+
+	// without the previous epoch, the next one is used
+	cache.StateHistory.Delete(9)
+	state = cacheState(10, 32_000_000_000, 32_000_000_000)
+	cache.shareValidatorRegistry(state)
+	assert.Same(t, next.Validators[0], state.Validators[0])
+	assert.Same(t, next.Validators[1], state.Validators[1])
+
+	// without consecutive states nothing is shared
+	state = cacheState(20, 32_000_000_000)
+	original := state.Validators[0]
+	cache.shareValidatorRegistry(state)
+	assert.Same(t, original, state.Validators[0])
+}
@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/clientapi"
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ChainSplitsActive = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "chain_splits_active",
+		Help:      "The number of extra beacon endpoints following a different chain than the main one",
+	})
+)
+
+// chainSplits tracks, per extra beacon endpoint, whether it follows a different chain than the main one.
+// A divergence becomes a split once it lasts the threshold slots
+type chainSplits struct {
+	sync.Mutex
+	thresholdSlots uint64
+	splits         map[string]*spec.ChainSplit // ongoing divergences per peer endpoint
+}
+
+func newChainSplits(thresholdSlots uint64) *chainSplits {
+	return &chainSplits{
+		thresholdSlots: thresholdSlots,
+		splits:         make(map[string]*spec.ChainSplit),
+	}
+}
+
+// Update compares the canonical roots of the main node and the peer at the given slot.
+// It returns the split to be persisted when it is detected or resolved, nil otherwise
+func (c *chainSplits) Update(peerEndpoint string, slot phase0.Slot, root phase0.Root, peerRoot phase0.Root) *spec.ChainSplit {
+	c.Lock()
+	defer c.Unlock()
+
+	split, ok := c.splits[peerEndpoint]
+	if root == peerRoot {
+		if !ok {
+			return nil
+		}
+		delete(c.splits, peerEndpoint)
+		if split.DetectedSlot == 0 {
+			return nil // short divergence, did not reach the threshold
+		}
+		split.EndSlot = slot
+		return split
+	}
+
+	if !ok {
+		split = &spec.ChainSplit{
+			StartSlot:    slot,
+			PeerEndpoint: peerEndpoint,
+			Root:         root,
+			PeerRoot:     peerRoot,
+		}
+		c.splits[peerEndpoint] = split
+	}
+	if split.DetectedSlot == 0 && uint64(slot-split.StartSlot) >= c.thresholdSlots {
+		split.DetectedSlot = slot
+		detected := *split
+		return &detected
+	}
+	return nil
+}
+
+// Active returns the number of detected splits not resolved yet
+func (c *chainSplits) Active() int {
+	c.Lock()
+	defer c.Unlock()
+	active := 0
+	for _, split := range c.splits {
+		if split.DetectedSlot != 0 {
+			active++
+		}
+	}
+	return active
+}
+
+// runChainSplitMonitor compares every slot the chain of the main beacon node with the one of the extra endpoints
+func (s *ChainAnalyzer) runChainSplitMonitor() {
+	peers := s.cli.BNPeers()
+	if len(peers) == 0 || s.chainSplits.thresholdSlots == 0 {
+		log.Infof("chain split detection disabled")
+		return
+	}
+	ticker := time.NewTicker(spec.SlotSeconds * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.checkChainSplits(peers)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *ChainAnalyzer) checkChainSplits(peers []*clientapi.BNPeer) {
+	// published even if the main node cannot be reached, the splits stay as they were
+	defer func() { ChainSplitsActive.Set(float64(s.chainSplits.Active())) }()
+
+	headSlot, err := s.cli.RequestHeadSlot(nil)
+	if err != nil {
+		log.Warnf("could not request the head to check chain splits: %s", err)
+		return
+	}
+
+	for _, peer := range peers {
+		peerHeadSlot, err := s.cli.RequestHeadSlot(peer)
+		if err != nil {
+			log.Warnf("could not request the head of %s to check chain splits: %s", peer.Endpoint, err)
+			continue // an unreachable peer is not a split
+		}
+		// both nodes should know the chain up to the lowest head
+		slot := min(headSlot, peerHeadSlot)
+		root, err := s.cli.RequestCanonicalRoot(nil, slot)
+		if err != nil {
+			log.Warnf("could not request the block root at slot %d: %s", slot, err)
+			continue // the next peer may compare at a slot the main node can serve
+		}
+		peerRoot, err := s.cli.RequestCanonicalRoot(peer, slot)
+		if err != nil {
+			log.Warnf("could not request the block root at slot %d from %s: %s", slot, peer.Endpoint, err)
+			continue
+		}
+
+		split := s.chainSplits.Update(peer.Endpoint, slot, root, peerRoot)
+		if split == nil {
+			continue
+		}
+		if split.Resolved() {
+			log.Infof("chain split with %s since slot %d resolved at slot %d", split.PeerEndpoint, split.StartSlot, split.EndSlot)
+		} else {
+			log.Warnf("chain split with %s since slot %d (%s vs %s): pausing finalized advancement",
+				split.PeerEndpoint, split.StartSlot, split.Root, split.PeerRoot)
+		}
+		err = s.dbClient.PersistChainSplits([]spec.ChainSplit{*split})
+		if err != nil {
+			log.Errorf("error persisting chain split: %s", err.Error())
+		}
+	}
+}
+
+// waitChainAgreement blocks while the main beacon node and any extra endpoint follow different chains,
+// so that a minority fork is not confirmed as finalized in the database
+func (s *ChainAnalyzer) waitChainAgreement() {
+	if s.chainSplits.Active() == 0 {
+		return
+	}
+	log.Warnf("finalized advancement paused until the beacon endpoints agree on the chain")
+	ticker := time.NewTicker(utils.RoutineFlushTimeout)
+	defer ticker.Stop()
+	for s.chainSplits.Active() > 0 && !s.stop {
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+	log.Infof("beacon endpoints agree on the chain, resuming finalized advancement")
+}
+
+func (s *ChainAnalyzer) getChainSplits() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(ChainSplitsActive)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.chainSplits.Active(), nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"chain_splits_active",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init chain_splits_active"))
+		return nil
+	}
+
+	return indvMetr
+}
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainSplitsUpdate(t *testing.T) {
+	rootA := phase0.Root{0xa}
+	rootB := phase0.Root{0xb}
+
+	type step struct {
+		slot     phase0.Slot
+		peerRoot phase0.Root
+		returned bool // a split is returned to be persisted
+		detected phase0.Slot
+		resolved phase0.Slot
+		active   int
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "Same chain",
+			steps: []step{
+				{slot: 100, peerRoot: rootA},
+				{slot: 101, peerRoot: rootA},
+			},
+		},
+		{
+			name: "Short divergence is not a split",
+			steps: []step{
+				{slot: 100, peerRoot: rootB},
+				{slot: 101, peerRoot: rootB},
+				{slot: 102, peerRoot: rootA},
+			},
+		},
+		{
+			name: "Split opens at the threshold and persists",
+			steps: []step{
+				{slot: 100, peerRoot: rootB},
+				{slot: 102, peerRoot: rootB},
+				{slot: 103, peerRoot: rootB, returned: true, detected: 103, active: 1},
+				{slot: 104, peerRoot: rootB, active: 1},
+				{slot: 110, peerRoot: rootB, active: 1},
+			},
+		},
+		{
+			name: "Split resolves",
+			steps: []step{
+				{slot: 100, peerRoot: rootB},
+				{slot: 103, peerRoot: rootB, returned: true, detected: 103, active: 1},
+				{slot: 105, peerRoot: rootA, returned: true, detected: 103, resolved: 105},
+				{slot: 106, peerRoot: rootA},
+				{slot: 107, peerRoot: rootB}, // a new divergence starts from scratch
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			splits := newChainSplits(3)
+			for _, step := range test.steps {
+				split := splits.Update("http://peer:5052", step.slot, rootA, step.peerRoot)
+				assert.Equal(t, step.returned, split != nil, step.slot)
+				if split != nil {
+					assert.Equal(t, phase0.Slot(100), split.StartSlot, step.slot)
+					assert.Equal(t, step.detected, split.DetectedSlot, step.slot)
+					assert.Equal(t, step.resolved, split.EndSlot, step.slot)
+					assert.Equal(t, step.resolved != 0, split.Resolved(), step.slot)
+				}
+				assert.Equal(t, step.active, splits.Active(), step.slot)
+			}
+		})
+	}
+}
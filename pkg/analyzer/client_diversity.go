@@ -0,0 +1,27 @@
+package analyzer
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// processClientDiversity persists, every clientDiversityEpochs epochs, the consensus clients of every pool
+// estimated from the graffiti of the blocks of the previous clientDiversityEpochs epochs, all persisted by then
+func (s *ChainAnalyzer) processClientDiversity(epoch phase0.Epoch) {
+	window := s.clientDiversityEpochs
+	if window == 0 || !s.metrics.Block || epoch < window || epoch%window != 0 {
+		return
+	}
+	proposals, err := s.dbClient.RetrievePoolProposals(epoch, window)
+	if err != nil {
+		log.Errorf("error retrieving the pool proposals of the client diversity at epoch %d: %s", epoch, err.Error())
+		return
+	}
+	diversity := spec.NewPoolClientDiversity(epoch, window, proposals)
+	if len(diversity) == 0 {
+		return
+	}
+	if err := s.dbClient.PersistPoolClientDiversity(diversity); err != nil {
+		log.Errorf("error persisting pool client diversity at epoch %d: %s", epoch, err.Error())
+	}
+}
@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/migalabs/goteth/pkg/pools"
+)
+
+// customPools keeps t_eth2_pubkeys in sync with the pool sources given by the user
+type customPools struct {
+	registry *pools.Registry
+	refresh  time.Duration // between reloads of the remote sources, 0 disables them
+}
+
+func newCustomPools(input string, refresh time.Duration) *customPools {
+	sources := pools.ParseSources(input)
+	if len(sources) == 0 {
+		return nil
+	}
+	return &customPools{
+		registry: pools.NewRegistry(sources),
+		refresh:  refresh,
+	}
+}
+
+// restoreCustomPools starts from the pools persisted by the previous runs, so the validators removed from
+// the sources while the analyzer was stopped are cleared on the first load
+func (s *ChainAnalyzer) restoreCustomPools() error {
+	keys, err := s.dbClient.RetrieveCustomPoolKeys()
+	if err != nil {
+		return err
+	}
+	s.customPools.registry.Restore(keys)
+	return nil
+}
+
+// loadCustomPools reads the pool sources and persists the validators whose pool changed since the last load.
+// It only fails if a source could not be read the first time
+func (s *ChainAnalyzer) loadCustomPools() error {
+	failed, err := s.customPools.registry.Load(s.ctx)
+	if err != nil {
+		return err
+	}
+	for _, err := range failed {
+		log.Warnf("%s", err.Error())
+	}
+
+	changed, conflicts := s.customPools.registry.Update()
+	for _, conflict := range conflicts {
+		log.Warnf("pools conflict: %s", conflict)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	log.Infof("%d validator pools updated, %d conflicts between sources", len(changed), len(conflicts))
+	return s.dbClient.PersistPoolKeys(changed)
+}
+
+// runCustomPoolsRefresh reloads the pool sources periodically, as the remote ones can change while the analyzer runs
+func (s *ChainAnalyzer) runCustomPoolsRefresh() {
+	if s.customPools == nil || s.customPools.refresh == 0 || !s.customPools.registry.Remote() {
+		return
+	}
+	ticker := time.NewTicker(s.customPools.refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.loadCustomPools(); err != nil {
+				log.Errorf("error refreshing the custom pools: %s", err.Error())
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
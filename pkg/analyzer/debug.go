@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/migalabs/goteth/pkg/utils"
+)
+
+var (
+	routinesEndpoint        = "/debug/routines"
+	routinesSummaryInterval = 1 * time.Minute
+	routineHeldWarnTime     = 5 * time.Minute // pages held for longer than this are reported as stuck
+)
+
+type routinePage struct {
+	Key     string  `json:"key"`
+	HeldFor float64 `json:"held_for_seconds"`
+}
+
+// routineBooks returns every book tracking in flight routines, indexed by tag
+func (s *ChainAnalyzer) routineBooks() map[string]*utils.RoutineBook {
+	books := map[string]*utils.RoutineBook{
+		s.processerBook.Tag(): s.processerBook,
+	}
+	for _, book := range s.cli.RoutineBooks() {
+		books[book.Tag()] = book
+	}
+	return books
+}
+
+// routinesHandler lists the pages currently acquired in each book (slots/epochs in flight)
+func (s *ChainAnalyzer) routinesHandler(w http.ResponseWriter, r *http.Request) {
+	result := make(map[string][]routinePage)
+
+	for tag, book := range s.routineBooks() {
+		pages := make([]routinePage, 0)
+		for _, page := range book.GetPages() {
+			pages = append(pages, routinePage{
+				Key:     page.Key,
+				HeldFor: page.HeldFor.Seconds(),
+			})
+		}
+		result[tag] = pages
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Errorf("could not encode routines: %s", err)
+	}
+}
+
+// runRoutinesSummary periodically logs the pages in flight, warning about the ones held for too long
+func (s *ChainAnalyzer) runRoutinesSummary() {
+	ticker := time.NewTicker(routinesSummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for tag, book := range s.routineBooks() {
+				pages := book.GetPages()
+				if len(pages) == 0 {
+					continue
+				}
+				log.Debugf("routines in flight at %s: %d (oldest %s held for %s)",
+					tag, len(pages), pages[0].Key, pages[0].HeldFor.Truncate(time.Second))
+
+				for _, page := range pages {
+					if page.HeldFor < routineHeldWarnTime {
+						break // sorted by time held
+					}
+					log.Warnf("routine %s at %s held for %s, might be stuck",
+						page.Key, tag, page.HeldFor.Truncate(time.Second))
+				}
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
@@ -1,21 +1,27 @@
 package analyzer
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/clientapi"
 	"github.com/migalabs/goteth/pkg/spec"
 )
 
 func (s *ChainAnalyzer) DownloadBlockCotrolled(slot phase0.Slot) {
 	s.WaitForPrevState(slot)
+	if s.downloadCache.BlockHistory.Available(SlotTo[uint64](slot)) {
+		log.Tracef("block at slot %d already downloaded", slot) // e.g. the head block when filling to head
+		return
+	}
 	s.DownloadBlock(slot)
 }
 
 func (s *ChainAnalyzer) DownloadBlock(slot phase0.Slot) {
 	if !s.metrics.Block {
-		log.Infof("skipping block download at slot %d: no metrics activated for block...", slot)
+		log.Debugf("skipping block download at slot %d: no metrics activated for block...", slot)
 		return
 	}
 
@@ -23,11 +29,21 @@ func (s *ChainAnalyzer) DownloadBlock(slot phase0.Slot) {
 	if err != nil {
 		log.Errorf("block error at slot %d: %s", slot, err)
 		s.stop = true
+	} else {
+		s.progress.BlockDownloaded(newBlock.Proposed)
 	}
 	s.downloadCache.AddNewBlock(newBlock)
+	if s.journal != nil {
+		s.journal.Downloaded(slot)
+	}
 	// check if the min Request time has been completed (to avoid spaming the API)
 }
 
+// stateSlot returns the slot at which the state of the epoch is downloaded, the last one of the epoch
+func (s *ChainAnalyzer) stateSlot(epoch phase0.Epoch) phase0.Slot {
+	return phase0.Slot(epoch+1)*spec.SlotsPerEpoch - 1
+}
+
 func (s *ChainAnalyzer) DownloadState(slot phase0.Slot) {
 	if !s.metrics.Epoch {
 		log.Infof("skipping state download: no metrics activated for state...")
@@ -35,26 +51,63 @@ func (s *ChainAnalyzer) DownloadState(slot phase0.Slot) {
 	}
 	log := log.WithField("routine", "download")
 
+	start := time.Now()
 	state, err := s.cli.RequestBeaconState(slot)
+	if errors.Is(err, clientapi.ErrStateDecode) {
+		state, err = s.downloadFallbackState(slot, err)
+	}
 	if err != nil {
 		// close the channel (to tell other routines to stop processing and end)
 		log.Errorf("unable to retrieve beacon state from the beacon node, closing requester routine. %s", err.Error())
 		s.stop = true
+	} else {
+		s.progress.StateDownloaded()
+		s.prefetch.StateDownloaded(time.Since(start))
+		s.calibration.StateDownloaded(time.Since(start))
 	}
 
+	if !s.metrics.Block { // block bodies are never downloaded, derive the blocks from the state
+		s.addBlocksFromState(state)
+	}
+	s.processStateProof(state)
 	s.downloadCache.AddNewState(state)
+	if s.downloadMode == "finalized" && !s.stop {
+		s.publishLiveParticipation(state)
+	}
+	if !s.stop {
+		// the duties of the next epoch are known already, keep them out of its state download
+		go s.cli.PrefetchEpochDuties(phase0.Epoch(slot/spec.SlotsPerEpoch) + 1)
+	}
 	// check if the min Request time has been completed (to avoid spaming the API)
 }
 
+// addBlocksFromState fills the cache with the blocks of the state epoch,
+// with the withdrawals estimated from the previous state in the last one
+func (s *ChainAnalyzer) addBlocksFromState(state *spec.AgnosticState) {
+	blocks := state.BlocksFromState()
+
+	epoch := EpochTo[uint64](state.Epoch)
+	if epoch > 0 && epoch-1 >= SlotTo[uint64](s.initSlot)/spec.SlotsPerEpoch { // previous state is downloaded as well
+		prevState := s.downloadCache.StateHistory.Wait(epoch - 1)
+		lastBlock := blocks[len(blocks)-1]
+		lastBlock.ExecutionPayload.Withdrawals = state.EstimateWithdrawals(prevState)
+	}
+
+	for _, block := range blocks {
+		s.downloadCache.AddNewBlock(block)
+	}
+}
+
 func (s *ChainAnalyzer) WaitForPrevState(slot phase0.Slot) {
-	// check if state two epochs before is available
+	// check if the state of the prefetch window (two epochs by default) before is available
 	// the idea is that blocks are too fast to download, wait for states as well
 
-	if slot < spec.SlotsPerEpoch*2 {
+	window := phase0.Slot(s.prefetch.Epochs())
+	if slot < spec.SlotsPerEpoch*window || !s.metrics.Epoch { // no states to wait for
 		return
 	}
-	prevStateEpoch := slot/spec.SlotsPerEpoch - 2              // epoch to check if state downloaded
-	prevStateSlot := (prevStateEpoch+1)*spec.SlotsPerEpoch - 1 // slot at which the check state was downloaded
+	prevStateEpoch := slot/spec.SlotsPerEpoch - window         // epoch to check if state downloaded
+	prevStateSlot := s.stateSlot(phase0.Epoch(prevStateEpoch)) // slot at which the check state was downloaded
 
 	prevStateAvailable := s.downloadCache.StateHistory.Available(uint64(prevStateEpoch))
 	prevStateProcessing := s.processerBook.CheckPageActive(fmt.Sprintf("%s%d", epochProcesserTag, prevStateEpoch))
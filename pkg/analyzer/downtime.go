@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/chaintime"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var downtimeEndpoint = "/downtime"
+
+// DowntimeWindow is a planned maintenance of the nodes running the given validators.
+// The attestations they miss inside it are tagged in the validator rewards
+type DowntimeWindow struct {
+	Validators []uint64 `json:"validators"`
+	From       string   `json:"from"` // RFC3339, YYYY-MM-DD (UTC) or unix timestamp
+	To         string   `json:"to"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
+type DowntimeWindowsFile struct {
+	Windows []DowntimeWindow `json:"windows"`
+}
+
+// ReadDowntimeWindowsFile reads the downtime windows from a JSON file
+func ReadDowntimeWindowsFile(path string) ([]DowntimeWindow, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file DowntimeWindowsFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("could not parse downtime windows file %s: %s", path, err)
+	}
+	return file.Windows, nil
+}
+
+type slotRange struct {
+	from phase0.Slot
+	to   phase0.Slot // included
+}
+
+// downtimeWindows are the registered windows, as slot ranges per validator
+type downtimeWindows struct {
+	sync.RWMutex
+	chainTime chaintime.ChainTime
+	windows   []DowntimeWindow
+	slots     map[phase0.ValidatorIndex][]slotRange
+}
+
+func newDowntimeWindows(chainTime chaintime.ChainTime) *downtimeWindows {
+	return &downtimeWindows{
+		chainTime: chainTime,
+		windows:   make([]DowntimeWindow, 0),
+		slots:     make(map[phase0.ValidatorIndex][]slotRange),
+	}
+}
+
+// Add registers the window, from the slot in progress at its start to the one in progress at its end
+func (d *downtimeWindows) Add(window DowntimeWindow) error {
+	if len(window.Validators) == 0 {
+		return fmt.Errorf("downtime window without validators")
+	}
+	from, err := chaintime.ParseTime(window.From)
+	if err != nil {
+		return err
+	}
+	to, err := chaintime.ParseTime(window.To)
+	if err != nil {
+		return err
+	}
+	if !to.After(from) {
+		return fmt.Errorf("downtime window ends (%s) before it starts (%s)", window.To, window.From)
+	}
+	start, err := d.chainTime.AtTime(from)
+	if err != nil {
+		return err
+	}
+	end, err := d.chainTime.AtTime(to)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	d.windows = append(d.windows, window)
+	for _, valIdx := range window.Validators {
+		d.slots[phase0.ValidatorIndex(valIdx)] = append(d.slots[phase0.ValidatorIndex(valIdx)], slotRange{from: start.Slot, to: end.Slot})
+	}
+	return nil
+}
+
+// Windows returns the registered windows
+func (d *downtimeWindows) Windows() []DowntimeWindow {
+	d.RLock()
+	defer d.RUnlock()
+	return append(make([]DowntimeWindow, 0, len(d.windows)), d.windows...)
+}
+
+// Covers tells whether the slot is inside a downtime window of the validator
+func (d *downtimeWindows) Covers(valIdx phase0.ValidatorIndex, slot phase0.Slot) bool {
+	d.RLock()
+	defer d.RUnlock()
+	for _, window := range d.slots[valIdx] {
+		if slot >= window.from && slot <= window.to {
+			return true
+		}
+	}
+	return false
+}
+
+// Tag marks the missed attestations of the active validators that were due inside their downtime windows,
+// returning how many were tagged
+func (d *downtimeWindows) Tag(rewards []spec.ValidatorRewards) int {
+	tagged := 0
+	for i, reward := range rewards {
+		if reward.Status != spec.ACTIVE_STATUS || reward.AttestationIncluded {
+			continue
+		}
+		if d.Covers(reward.ValidatorIndex, reward.AttSlot) {
+			rewards[i].PlannedDowntime = true
+			tagged++
+		}
+	}
+	return tagged
+}
+
+// downtimeHandler lists the downtime windows (GET /downtime) or registers a new one (POST /downtime with the window as body).
+// Requests must carry the admin token as a bearer token
+func (s *ChainAnalyzer) downtimeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := []byte("Bearer " + s.adminToken)
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), token) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	response := any(nil)
+	status := http.StatusOK
+	if r.Method == http.MethodGet {
+		response = s.downtime.Windows()
+	} else {
+		var window DowntimeWindow
+		if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+			http.Error(w, fmt.Sprintf("could not parse the downtime window: %s", err), http.StatusBadRequest)
+			return
+		}
+		if err := s.downtime.Add(window); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Infof("downtime window registered for %d validators from %s to %s", len(window.Validators), window.From, window.To)
+		response = window
+		status = http.StatusCreated
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("could not encode downtime response: %s", err)
+	}
+}
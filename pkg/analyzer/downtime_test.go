@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/migalabs/goteth/pkg/chaintime"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDowntimeWindowsTag(t *testing.T) {
+	genesis := time.Unix(1606824023, 0)
+	downtime := newDowntimeWindows(chaintime.New(genesis))
+
+	// slots 100 to 110
+	err := downtime.Add(DowntimeWindow{
+		Validators: []uint64{1, 2},
+		From:       "1606825223",
+		To:         "1606825343",
+	})
+	assert.NoError(t, err)
+	assert.Error(t, downtime.Add(DowntimeWindow{From: "1606825223", To: "1606825343"}))
+	assert.Error(t, downtime.Add(DowntimeWindow{Validators: []uint64{1}, From: "1606825343", To: "1606825223"}))
+	assert.Error(t, downtime.Add(DowntimeWindow{Validators: []uint64{1}, From: "2020-01-01", To: "2020-01-02"}))
+	assert.Len(t, downtime.Windows(), 1)
+
+	assert.True(t, downtime.Covers(1, 100))
+	assert.True(t, downtime.Covers(2, 110))
+	assert.False(t, downtime.Covers(1, 111))
+	assert.False(t, downtime.Covers(3, 105))
+
+	rewards := []spec.ValidatorRewards{
+		{ValidatorIndex: 1, AttSlot: 105, Status: spec.ACTIVE_STATUS},                            // missed inside
+		{ValidatorIndex: 2, AttSlot: 105, Status: spec.ACTIVE_STATUS, AttestationIncluded: true}, // included
+		{ValidatorIndex: 2, AttSlot: 120, Status: spec.ACTIVE_STATUS},                            // missed outside
+		{ValidatorIndex: 3, AttSlot: 105, Status: spec.ACTIVE_STATUS},                            // no window
+		{ValidatorIndex: 1, AttSlot: 105, Status: spec.EXIT_STATUS},                              // not active
+	}
+	assert.Equal(t, 1, downtime.Tag(rewards))
+	planned := make([]bool, 0, len(rewards))
+	for _, reward := range rewards {
+		planned = append(planned, reward.PlannedDowntime)
+	}
+	assert.Equal(t, []bool{true, false, false, false, false}, planned)
+}
@@ -0,0 +1,260 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/clientapi"
+	"github.com/migalabs/goteth/pkg/config"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	epochsPerDay = uint64(24*time.Hour/time.Second) / (spec.SlotsPerEpoch * spec.SlotSeconds)
+)
+
+// DryRunCheck is the result of one of the checks of a dry run
+type DryRunCheck struct {
+	Name   string
+	Ok     bool
+	Detail string
+}
+
+// DryRunReport gathers the checks of a dry run and the expected size of the run
+type DryRunReport struct {
+	Checks     []DryRunCheck
+	Epochs     uint64 // epochs of the range, or of a day in head mode
+	PerDay     bool   // the estimates are per day of head mode
+	Validators uint64 // validators at the head
+	Estimates  []db.TableEstimate
+}
+
+func (r *DryRunReport) add(name string, err error, detail string) {
+	check := DryRunCheck{Name: name, Ok: err == nil, Detail: detail}
+	if err != nil {
+		check.Detail = err.Error()
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+// Failed tells whether any of the checks failed
+func (r DryRunReport) Failed() bool {
+	for _, check := range r.Checks {
+		if !check.Ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Write prints the report as text, one line per check and table
+func (r DryRunReport) Write(w io.Writer) {
+	for _, check := range r.Checks {
+		status := "ok"
+		if !check.Ok {
+			status = "FAILED"
+		}
+		fmt.Fprintf(w, "%-16s %-6s %s\n", check.Name, status, check.Detail)
+	}
+	if len(r.Estimates) == 0 {
+		return
+	}
+	span := fmt.Sprintf("%d epochs", r.Epochs)
+	if r.PerDay {
+		span = fmt.Sprintf("a day (%d epochs)", r.Epochs)
+	}
+	fmt.Fprintf(w, "\nexpected size of %s with %d validators:\n", span, r.Validators)
+	total := uint64(0)
+	for _, estimate := range r.Estimates {
+		fmt.Fprintf(w, "%-32s %14d rows %10.2f GB\n", estimate.Table, estimate.Rows, float64(estimate.Bytes)/1e9)
+		total += estimate.Bytes
+	}
+	fmt.Fprintf(w, "%-32s %14s      %10.2f GB\n", "total", "", float64(total)/1e9)
+}
+
+// DryRun validates the configuration, the connectivity with the beacon node, the execution node and the database,
+// the spec of the chain and the schema version, and estimates the size of the run. Nothing is written
+func DryRun(ctx context.Context, iConfig config.AnalyzerConfig) DryRunReport {
+	report := DryRunReport{}
+
+	metricsObj, err := checkDryRunConfig(iConfig)
+	report.add("config", err, fmt.Sprintf("%s mode, metrics %s", iConfig.DownloadMode, iConfig.Metrics))
+
+	bnHeaders, err := clientapi.ParseNodeHeaders(iConfig.BnAuth, iConfig.BnHeaders)
+	if err != nil {
+		report.add("beacon node", err, "")
+		return report
+	}
+	elHeaders, err := clientapi.ParseNodeHeaders(iConfig.ElAuth, iConfig.ElHeaders)
+	if err != nil {
+		report.add("execution node", err, "")
+		return report
+	}
+	forkSchedule, err := spec.ParseForkSchedule(iConfig.ForkSchedule)
+	if err != nil {
+		report.add("spec", err, "")
+		return report
+	}
+	bnEndpoint := iConfig.BnEndpoint
+	if bnEndpoints := clientapi.ParseEndpoints(iConfig.BnEndpoint); len(bnEndpoints) > 0 {
+		bnEndpoint = bnEndpoints[0]
+	}
+	cli, err := clientapi.NewAPIClient(ctx,
+		bnEndpoint,
+		iConfig.MaxRequestRetries,
+		clientapi.WithNodeHeaders(bnHeaders, elHeaders),
+		clientapi.WithELEndpoint(iConfig.ElEndpoint),
+		clientapi.WithForkSchedule(forkSchedule))
+	if err != nil {
+		report.add("beacon node", err, "")
+		return report
+	}
+
+	health, err := cli.RequestNodeHealth()
+	report.add("beacon node", err, fmt.Sprintf("%s, syncing %t, sync distance %d, latency %s",
+		clientapi.RedactEndpoint(bnEndpoint), health.Syncing, health.SyncDistance, health.Latency.Truncate(time.Millisecond)))
+	if err != nil {
+		return report
+	}
+
+	specCheck, err := cli.CheckSpec()
+	if err == nil && iConfig.DownloadMode == "historical" && iConfig.FinalSlot > specCheck.HeadSlot {
+		err = fmt.Errorf("final slot %d is after the head of the node (%d)", iConfig.FinalSlot, specCheck.HeadSlot)
+	}
+	report.add("spec", err, fmt.Sprintf("head at slot %d, %s fork", specCheck.HeadSlot, specCheck.HeadFork))
+
+	if len(clientapi.ParseEndpoints(iConfig.ElEndpoint)) == 0 && metricsObj.Transactions {
+		report.add("execution node", nil, "not configured, transactions stored without receipts")
+	} else if metricsObj.Transactions || cli.ELApi() != nil {
+		report.add("execution node", checkDryRunEL(cli, iConfig, metricsObj), clientapi.RedactEndpoint(iConfig.ElEndpoint))
+	}
+
+	urls := map[string]string{"database": iConfig.DBUrl}
+	if iConfig.DBDualWriteUrl != "" {
+		urls["dual write"] = iConfig.DBDualWriteUrl
+	}
+	for _, name := range []string{"database", "dual write"} {
+		url, ok := urls[name]
+		if !ok {
+			continue
+		}
+		version, err := db.CheckSchemaVersion(ctx, url, iConfig.DBSchema)
+		if err == nil && version.Dirty {
+			err = fmt.Errorf("migration %d failed halfway, fix it by hand before running", version.Current)
+		}
+		if err == nil && version.Current > version.Latest {
+			err = fmt.Errorf("schema version %d is newer than the one of this binary (%d)", version.Current, version.Latest)
+		}
+		detail := fmt.Sprintf("schema version %d of %d, %d migrations pending", version.Current, version.Latest, version.Pending())
+		if !version.Exists {
+			detail = fmt.Sprintf("schema %s created on start, %d migrations pending", iConfig.DBSchema, version.Pending())
+		}
+		report.add(name, err, detail)
+	}
+
+	if customPools := newCustomPools(iConfig.CustomPools, 0); customPools != nil {
+		_, err := customPools.registry.Load(ctx)
+		detail := ""
+		if err == nil {
+			keys, conflicts := customPools.registry.Update()
+			detail = fmt.Sprintf("%d validators, %d conflicts between sources", len(keys), len(conflicts))
+		}
+		report.add("custom pools", err, detail)
+	}
+
+	if iConfig.SlashingProtection != "" {
+		interchange, err := spec.ReadSlashingProtectionFile(iConfig.SlashingProtection)
+		report.add("slashing protection", err, fmt.Sprintf("%d validators", len(interchange.Data)))
+	}
+
+	// estimate with the validators of the head, an upper bound for historical ranges
+	if specCheck.HeadSlot == 0 {
+		return report
+	}
+	counts, err := cli.RequestValidatorStatusCounts(specCheck.HeadSlot)
+	if err != nil {
+		report.add("validators", err, "")
+		return report
+	}
+	for _, count := range counts {
+		report.Validators += count
+	}
+	report.Epochs, report.PerDay = epochsPerDay, true
+	if iConfig.DownloadMode == "historical" && iConfig.FinalSlot > iConfig.InitSlot {
+		report.Epochs = uint64(iConfig.FinalSlot-iConfig.InitSlot)/spec.SlotsPerEpoch + 1
+		report.PerDay = false
+	}
+	report.Estimates = db.EstimateRun(metricsObj, report.Epochs, report.Validators, nil)
+	return report
+}
+
+// checkDryRunConfig validates the options the analyzer would reject on start
+func checkDryRunConfig(iConfig config.AnalyzerConfig) (db.DBMetrics, error) {
+	metricsObj, err := db.NewMetrics(iConfig.Metrics)
+	if err != nil {
+		return metricsObj, err
+	}
+	if metricsObj, err = applyMode(iConfig.Mode, metricsObj); err != nil {
+		return metricsObj, err
+	}
+	switch iConfig.DownloadMode {
+	case "historical":
+		if iConfig.FinalSlot <= iConfig.InitSlot {
+			return metricsObj, fmt.Errorf("final slot %d must be greater than init slot %d", iConfig.FinalSlot, iConfig.InitSlot)
+		}
+	case "finalized", "branch":
+	default:
+		return metricsObj, fmt.Errorf("unknown download mode: %s", iConfig.DownloadMode)
+	}
+	if iConfig.RewardsStorage != rewardsStorageFull && iConfig.RewardsStorage != rewardsStorageDelta {
+		return metricsObj, fmt.Errorf("unknown rewards storage: %s", iConfig.RewardsStorage)
+	}
+	if iConfig.RewardsShards != "" && iConfig.RewardsStorage == rewardsStorageDelta {
+		return metricsObj, fmt.Errorf("rewards shards do not support the delta rewards storage")
+	}
+	if _, err := parseFinalityThresholds(iConfig.FinalityAlertEpochs); err != nil {
+		return metricsObj, err
+	}
+	if _, ok := spec.BeaconContractAddresses[iConfig.BeaconContractAddress]; !ok && !spec.HexStringAddressIsValid(iConfig.BeaconContractAddress) {
+		return metricsObj, fmt.Errorf("invalid beacon contract address: %s", iConfig.BeaconContractAddress)
+	}
+	return metricsObj, nil
+}
+
+// checkDryRunEL checks that the execution endpoints answer and, for transactions, that they serve
+// the receipts from the first block to analyze
+func checkDryRunEL(cli *clientapi.APIClient, iConfig config.AnalyzerConfig, metricsObj db.DBMetrics) error {
+	if _, err := cli.RequestELHead(); err != nil {
+		return err
+	}
+	if !metricsObj.Transactions {
+		return nil
+	}
+	fromBlock, err := elHistoryStart(cli, iConfig)
+	if err != nil {
+		return err
+	}
+	return cli.CheckELHistory(fromBlock)
+}
+
+// elHistoryStart returns the first execution block whose receipts are requested: the one at the init slot
+// in historical mode, or the one at the head when following the chain
+func elHistoryStart(cli *clientapi.APIClient, iConfig config.AnalyzerConfig) (uint64, error) {
+	slot := phase0.Slot(iConfig.InitSlot)
+	if iConfig.DownloadMode != "historical" {
+		head, err := cli.RequestHeadSlot(nil)
+		if err != nil {
+			return 0, fmt.Errorf("could not obtain the head slot: %s", err)
+		}
+		slot = head
+	}
+	fromBlock, err := cli.RequestExecutionBlockNumber(slot)
+	if err != nil {
+		return 0, fmt.Errorf("could not obtain the execution block at slot %d: %s", slot, err)
+	}
+	return fromBlock, nil
+}
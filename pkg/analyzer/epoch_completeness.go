@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// blockFamilies gathers the outcome of the block metric families over the slots of an epoch
+type blockFamilies struct {
+	slots  map[phase0.Slot]struct{}
+	failed map[spec.MetricFamily]bool
+}
+
+// epochCompleteness tracks, for every epoch, the block metric families that failed in any of its slots.
+// Once every slot of the epoch is processed the families are persisted, and again when reorged slots are processed
+type epochCompleteness struct {
+	sync.Mutex
+	families []spec.MetricFamily // enabled block families
+	epochs   map[phase0.Epoch]*blockFamilies
+}
+
+func newEpochCompleteness(families []spec.MetricFamily) *epochCompleteness {
+	return &epochCompleteness{
+		families: families,
+		epochs:   make(map[phase0.Epoch]*blockFamilies),
+	}
+}
+
+// Record adds the outcome of the slot, returning the completeness of the epoch families if all its slots were processed
+func (c *epochCompleteness) Record(slot phase0.Slot, failed []spec.MetricFamily) []spec.EpochCompleteness {
+	c.Lock()
+	defer c.Unlock()
+
+	epoch := phase0.Epoch(slot / spec.SlotsPerEpoch)
+	entry, ok := c.epochs[epoch]
+	if !ok {
+		entry = &blockFamilies{
+			slots:  make(map[phase0.Slot]struct{}, spec.SlotsPerEpoch),
+			failed: make(map[spec.MetricFamily]bool),
+		}
+		c.epochs[epoch] = entry
+	}
+	entry.slots[slot] = struct{}{}
+	for _, family := range failed {
+		entry.failed[family] = true
+	}
+
+	if len(entry.slots) < spec.SlotsPerEpoch {
+		return nil
+	}
+	completeness := make([]spec.EpochCompleteness, 0, len(c.families))
+	for _, family := range c.families {
+		completeness = append(completeness, spec.EpochCompleteness{
+			Epoch:    epoch,
+			Family:   family,
+			Complete: !entry.failed[family],
+		})
+	}
+	return completeness
+}
+
+// CleanUpTo forgets the epochs before the given slot, the ones never completed are not recorded
+func (c *epochCompleteness) CleanUpTo(maxSlot phase0.Slot) {
+	c.Lock()
+	defer c.Unlock()
+	for epoch := range c.epochs {
+		if phase0.Slot((epoch+1)*spec.SlotsPerEpoch) <= maxSlot {
+			delete(c.epochs, epoch)
+		}
+	}
+}
+
+// blockMetricFamilies returns the families persisted with every block, according to the enabled metrics
+func blockMetricFamilies(blocks bool, transactions bool) []spec.MetricFamily {
+	families := make([]spec.MetricFamily, 0)
+	if !blocks { // nothing is persisted per block
+		return families
+	}
+	families = append(families, spec.BlocksFamily, spec.WithdrawalsFamily)
+	if transactions {
+		families = append(families, spec.TransactionsFamily, spec.BlobsFamily)
+	}
+	return families
+}
+
+// processBlockCompleteness records the families that failed for the block, persisting the epoch ones once complete
+func (s *ChainAnalyzer) processBlockCompleteness(slot phase0.Slot, failed []spec.MetricFamily) {
+	completeness := s.epochCompleteness.Record(slot, failed)
+	if len(completeness) == 0 {
+		return
+	}
+	if err := s.dbClient.PersistEpochCompleteness(completeness); err != nil {
+		log.Errorf("error persisting completeness of epoch %d: %s", completeness[0].Epoch, err)
+	}
+}
@@ -0,0 +1,243 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type epochStage string
+
+const (
+	downloadStage epochStage = "download" // waiting for the states (and their blocks)
+	processStage  epochStage = "process"  // computing the metrics of the transition
+	persistStage  epochStage = "persist"  // writing the metrics to the database
+)
+
+var (
+	StuckEpochs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "stuck_epochs",
+		Help:      "The number of epochs in flight for longer than the stuck epoch timeout",
+	})
+	StuckEpochsDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "stuck_epochs_detected",
+		Help:      "The number of epochs flagged as stuck, by the stage they were stuck at",
+	}, []string{"stage"})
+	StuckEpochsRequeued = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "stuck_epochs_requeued",
+		Help:      "The number of stuck epochs requeued by the watchdog",
+	})
+
+	epochWatchdogInterval = 30 * time.Second
+)
+
+// epochTask is an epoch transition in flight
+type epochTask struct {
+	stage      epochStage
+	start      time.Time
+	stageStart time.Time
+	flagged    bool // reported as stuck already
+}
+
+type stuckEpoch struct {
+	epoch        phase0.Epoch
+	stage        epochStage
+	heldFor      time.Duration
+	stageHeldFor time.Duration
+}
+
+// epochWatchdog tracks the stage of the epoch transitions in flight, flagging the ones that take too long
+type epochWatchdog struct {
+	sync.Mutex
+	timeout time.Duration // 0 disables the watchdog
+	requeue bool          // whether stuck epochs are requeued
+
+	tasks map[phase0.Epoch]*epochTask
+	stuck int // epochs found stuck in the last check
+}
+
+func newEpochWatchdog(timeout time.Duration, requeue bool) *epochWatchdog {
+	return &epochWatchdog{
+		timeout: timeout,
+		requeue: requeue,
+		tasks:   make(map[phase0.Epoch]*epochTask),
+	}
+}
+
+// Start tracks the transition to the epoch, which begins waiting for its states
+func (w *epochWatchdog) Start(epoch phase0.Epoch) {
+	w.Lock()
+	defer w.Unlock()
+	now := time.Now()
+	w.tasks[epoch] = &epochTask{
+		stage:      downloadStage,
+		start:      now,
+		stageStart: now,
+	}
+}
+
+// Stage moves the transition to the epoch to the given stage, epochs not tracked (reprocessed) are ignored
+func (w *epochWatchdog) Stage(epoch phase0.Epoch, stage epochStage) {
+	w.Lock()
+	defer w.Unlock()
+	task, ok := w.tasks[epoch]
+	if !ok {
+		return
+	}
+	task.stage = stage
+	task.stageStart = time.Now()
+}
+
+// Done stops tracking the transition to the epoch
+func (w *epochWatchdog) Done(epoch phase0.Epoch) {
+	w.Lock()
+	defer w.Unlock()
+	delete(w.tasks, epoch)
+}
+
+// Stuck returns the epochs in flight for longer than the timeout that were not flagged before
+func (w *epochWatchdog) Stuck(now time.Time) []stuckEpoch {
+	w.Lock()
+	defer w.Unlock()
+	stuck := make([]stuckEpoch, 0)
+	total := 0
+	for epoch, task := range w.tasks {
+		if now.Sub(task.start) < w.timeout {
+			continue
+		}
+		total++
+		if task.flagged {
+			continue
+		}
+		task.flagged = true
+		stuck = append(stuck, stuckEpoch{
+			epoch:        epoch,
+			stage:        task.stage,
+			heldFor:      now.Sub(task.start),
+			stageHeldFor: now.Sub(task.stageStart),
+		})
+	}
+	w.stuck = total
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].epoch < stuck[j].epoch })
+	return stuck
+}
+
+// StuckNum returns the number of epochs found stuck in the last check, flagged or not
+func (w *epochWatchdog) StuckNum() int {
+	w.Lock()
+	defer w.Unlock()
+	return w.stuck
+}
+
+// runEpochWatchdog periodically reports the epochs stuck in the pipeline, with a notification,
+// and requeues them if configured
+func (s *ChainAnalyzer) runEpochWatchdog() {
+	if s.epochWatchdog.timeout == 0 {
+		return
+	}
+	ticker := time.NewTicker(epochWatchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			stuck := s.epochWatchdog.Stuck(time.Now())
+			StuckEpochs.Set(float64(s.epochWatchdog.StuckNum()))
+			for _, task := range stuck {
+				StuckEpochsDetected.WithLabelValues(string(task.stage)).Inc()
+				message := fmt.Sprintf("transition to epoch %d in flight for %s, at the %s stage for %s",
+					task.epoch, task.heldFor.Truncate(time.Second), task.stage, task.stageHeldFor.Truncate(time.Second))
+				err := s.notifier.Notify(notifier.Notification{
+					Rule:    "epoch_watchdog",
+					Kind:    notifier.StuckEpochKind,
+					Epoch:   task.epoch,
+					Message: message,
+				})
+				if err != nil {
+					log.Errorf("error sending stuck epoch notification: %s", err.Error())
+				}
+				if s.epochWatchdog.requeue {
+					s.requeueStuckEpoch(task)
+				}
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// requeueStuckEpoch downloads again the states the epoch is waiting for, or queues it to be reprocessed
+// if it got stuck afterwards. The stuck routine cannot be stopped, rows written twice are replaced
+func (s *ChainAnalyzer) requeueStuckEpoch(task stuckEpoch) {
+	if task.stage != downloadStage {
+		select {
+		case s.reprocessChan <- task.epoch:
+			StuckEpochsRequeued.Inc()
+			log.Infof("stuck epoch %d queued to be reprocessed", task.epoch)
+		default:
+			log.Warnf("stuck epoch %d could not be requeued: too many epochs waiting to be reprocessed", task.epoch)
+		}
+		return
+	}
+
+	initEpoch := phase0.Epoch(s.initSlot / spec.SlotsPerEpoch)
+	for i := phase0.Epoch(0); i <= 2 && i <= task.epoch; i++ { // the transition needs the states of the last 3 epochs
+		epoch := task.epoch - i
+		if epoch < initEpoch || s.downloadCache.StateHistory.Available(EpochTo[uint64](epoch)) {
+			continue
+		}
+		lastSlot := phase0.Slot((epoch+1)*spec.SlotsPerEpoch - 1)
+		if s.metrics.Block {
+			for slot := lastSlot + 1 - spec.SlotsPerEpoch; slot <= lastSlot; slot++ {
+				if !s.downloadCache.BlockHistory.Available(SlotTo[uint64](slot)) {
+					go s.DownloadBlock(slot)
+				}
+			}
+		}
+		go s.DownloadState(s.stateSlot(epoch))
+		log.Infof("state at epoch %d requested again for stuck epoch %d", epoch, task.epoch)
+	}
+	StuckEpochsRequeued.Inc()
+}
+
+func (s *ChainAnalyzer) getEpochWatchdog() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(StuckEpochs)
+		prometheus.MustRegister(StuckEpochsDetected)
+		prometheus.MustRegister(StuckEpochsRequeued)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.epochWatchdog.StuckNum(), nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"stuck_epochs",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init stuck_epochs"))
+		return nil
+	}
+
+	return indvMetr
+}
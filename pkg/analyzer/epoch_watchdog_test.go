@@ -0,0 +1,38 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochWatchdogStuck(t *testing.T) {
+	watchdog := newEpochWatchdog(time.Minute, false)
+	watchdog.Start(10)
+	watchdog.Start(11)
+	watchdog.Start(12)
+	watchdog.Stage(11, processStage)
+	watchdog.Stage(11, persistStage)
+	watchdog.Stage(20, processStage) // not tracked
+	watchdog.Done(12)
+
+	assert.Empty(t, watchdog.Stuck(time.Now()))
+	assert.Equal(t, 0, watchdog.StuckNum())
+
+	later := time.Now().Add(2 * time.Minute)
+	stuck := watchdog.Stuck(later)
+	assert.Len(t, stuck, 2)
+	assert.Equal(t, phase0.Epoch(10), stuck[0].epoch)
+	assert.Equal(t, downloadStage, stuck[0].stage)
+	assert.Equal(t, phase0.Epoch(11), stuck[1].epoch)
+	assert.Equal(t, persistStage, stuck[1].stage)
+
+	// reported only once, but still counted while in flight
+	assert.Empty(t, watchdog.Stuck(later))
+	assert.Equal(t, 2, watchdog.StuckNum())
+	watchdog.Done(10)
+	watchdog.Stuck(later)
+	assert.Equal(t, 1, watchdog.StuckNum())
+}
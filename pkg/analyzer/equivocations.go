@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	EquivocationsDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "equivocations_detected",
+		Help:      "The number of slashable votes detected, by type and whether they were included as attester slashings",
+	}, []string{"type", "included"})
+
+	trackedVoteEpochs = phase0.Epoch(256) // epochs of votes kept per monitored validator
+)
+
+// voteTracker keeps the recent votes of the monitored validators to detect double and surround votes
+type voteTracker struct {
+	sync.Mutex
+	monitored map[phase0.ValidatorIndex]struct{}
+	votes     map[phase0.ValidatorIndex][]spec.VoteSummary
+}
+
+// newVoteTracker parses the comma separated list of validator indexes to monitor
+func newVoteTracker(validators string) (*voteTracker, error) {
+	tracker := &voteTracker{
+		monitored: make(map[phase0.ValidatorIndex]struct{}),
+		votes:     make(map[phase0.ValidatorIndex][]spec.VoteSummary),
+	}
+	for _, item := range strings.Split(validators, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		valIdx, err := strconv.ParseUint(item, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validator index %s: %s", item, err)
+		}
+		tracker.monitored[phase0.ValidatorIndex(valIdx)] = struct{}{}
+	}
+	return tracker, nil
+}
+
+func (t *voteTracker) Monitoring() bool {
+	return len(t.monitored) > 0
+}
+
+func (t *voteTracker) Monitored(valIdx phase0.ValidatorIndex) bool {
+	_, ok := t.monitored[valIdx]
+	return ok
+}
+
+// Check records the vote of the validator and returns the previous votes it is slashable with
+func (t *voteTracker) Check(valIdx phase0.ValidatorIndex, vote spec.VoteSummary) []spec.VoteSummary {
+	t.Lock()
+	defer t.Unlock()
+
+	conflicts := make([]spec.VoteSummary, 0)
+	for _, prevVote := range t.votes[valIdx] {
+		if prevVote.Root == vote.Root {
+			return conflicts // already seen, it was checked the first time
+		}
+		if spec.ClassifyVotes(prevVote, vote) != spec.NoEquivocation {
+			conflicts = append(conflicts, prevVote)
+		}
+	}
+	t.votes[valIdx] = append(t.votes[valIdx], vote)
+	return conflicts
+}
+
+// CleanUpTo forgets the votes older than the tracked window
+func (t *voteTracker) CleanUpTo(epoch phase0.Epoch) {
+	if epoch < trackedVoteEpochs {
+		return
+	}
+	minTarget := epoch - trackedVoteEpochs
+	t.Lock()
+	defer t.Unlock()
+	for valIdx, votes := range t.votes {
+		kept := votes[:0]
+		for _, vote := range votes {
+			if vote.TargetEpoch >= minTarget {
+				kept = append(kept, vote)
+			}
+		}
+		t.votes[valIdx] = kept
+	}
+}
+
+// notifyEquivocations sends a high priority notification with the validators of the equivocations.
+// Only epochs followed live are notified, not the backfilled or reprocessed ones
+func (s *ChainAnalyzer) notifyEquivocations(epoch phase0.Epoch, equivocations []spec.Equivocation) {
+	if s.downloadMode != "finalized" || len(equivocations) == 0 {
+		return
+	}
+	indexes := make([]phase0.ValidatorIndex, 0, len(equivocations))
+	listed := make([]string, 0, len(equivocations))
+	for _, equivocation := range equivocations {
+		if !slices.Contains(indexes, equivocation.ValidatorIndex) {
+			indexes = append(indexes, equivocation.ValidatorIndex)
+		}
+		listed = append(listed, fmt.Sprintf("%s by %d at slot %d (included: %t)",
+			equivocation.Kind, equivocation.ValidatorIndex, equivocation.Slot, equivocation.Included))
+	}
+	err := s.notifier.Notify(notifier.Notification{
+		Rule:     "equivocation",
+		Kind:     notifier.EquivocationKind,
+		Epoch:    epoch,
+		Message:  fmt.Sprintf("%d equivocations detected at epoch %d: %s", len(equivocations), epoch, strings.Join(listed, ", ")),
+		Priority: notifier.HighPriority,
+		Indexes:  indexes,
+	})
+	if err != nil {
+		log.Errorf("error sending equivocation notification: %s", err.Error())
+	}
+}
+
+func (s *ChainAnalyzer) getEquivocations() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(EquivocationsDetected)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return len(s.voteTracker.monitored), nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"equivocations",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init equivocations"))
+		return nil
+	}
+	return indvMetr
+}
@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyEquivocationsHeadOnly(t *testing.T) {
+	notifications := make([]notifier.Notification, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification notifier.Notification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+		notifications = append(notifications, notification)
+	}))
+	defer server.Close()
+
+	equivocations := []spec.Equivocation{
+		{ValidatorIndex: 7, Kind: spec.DoubleVote, Slot: 3200},
+		{ValidatorIndex: 7, Kind: spec.SurroundVote, Slot: 3201},
+		{ValidatorIndex: 9, Kind: spec.DoubleVote, Slot: 3201, Included: true},
+	}
+	for _, downloadMode := range []string{"historical", "finalized"} {
+		s := &ChainAnalyzer{
+			downloadMode: downloadMode,
+			notifier:     notifier.NewNotifier(context.Background(), server.URL),
+		}
+		s.notifyEquivocations(100, equivocations)
+		s.notifyEquivocations(101, nil)
+	}
+
+	assert.Len(t, notifications, 1)
+	assert.Equal(t, notifier.EquivocationKind, notifications[0].Kind)
+	assert.Equal(t, phase0.Epoch(100), notifications[0].Epoch)
+	assert.Equal(t, notifier.HighPriority, notifications[0].Priority)
+	assert.Equal(t, []phase0.ValidatorIndex{7, 9}, notifications[0].Indexes)
+}
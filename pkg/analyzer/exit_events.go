@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+const (
+	exitEventsBatch = 64               // events written in a single insert at most
+	exitEventsFlush = 12 * time.Second // the oldest event buffered waits a slot at most
+)
+
+// exitEventsBuffer batches the voluntary exits broadcast in the network, which arrive one at a time
+// from the event stream, so that they are not written with an insert each
+type exitEventsBuffer struct {
+	events []spec.VoluntaryExitEvent
+	first  time.Time // arrival of the oldest event buffered
+}
+
+func (b *exitEventsBuffer) Add(event spec.VoluntaryExitEvent, now time.Time) {
+	if len(b.events) == 0 {
+		b.first = now
+	}
+	b.events = append(b.events, event)
+}
+
+// Due returns whether the buffer is full or its oldest event waited long enough
+func (b *exitEventsBuffer) Due(now time.Time) bool {
+	return len(b.events) >= exitEventsBatch || (len(b.events) > 0 && now.Sub(b.first) >= exitEventsFlush)
+}
+
+// Pop returns the events buffered and empties the buffer
+func (b *exitEventsBuffer) Pop() []spec.VoluntaryExitEvent {
+	events := b.events
+	b.events = nil
+	return events
+}
+
+// flushExitEvents writes the voluntary exit events buffered, in the background unless the analyzer is stopping
+func (s *ChainAnalyzer) flushExitEvents(wait bool) {
+	events := s.exitEvents.Pop()
+	if len(events) == 0 {
+		return
+	}
+	if wait {
+		s.dbClient.PersistVoluntaryExitEvents(events)
+		return
+	}
+	go s.dbClient.PersistVoluntaryExitEvents(events)
+}
@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitEventsBuffer(t *testing.T) {
+	buffer := exitEventsBuffer{}
+	start := time.Unix(1700000000, 0)
+	assert.False(t, buffer.Due(start))
+
+	buffer.Add(spec.VoluntaryExitEvent{ValidatorIndex: 1}, start)
+	buffer.Add(spec.VoluntaryExitEvent{ValidatorIndex: 2}, start.Add(5*time.Second))
+	assert.False(t, buffer.Due(start.Add(exitEventsFlush-time.Second)))
+	// the wait counts from the oldest event
+	assert.True(t, buffer.Due(start.Add(exitEventsFlush)))
+
+	events := buffer.Pop()
+	assert.Len(t, events, 2)
+	assert.Equal(t, phase0.ValidatorIndex(2), events[1].ValidatorIndex)
+	assert.False(t, buffer.Due(start.Add(time.Hour)))
+
+	for i := 0; i < exitEventsBatch; i++ {
+		buffer.Add(spec.VoluntaryExitEvent{ValidatorIndex: phase0.ValidatorIndex(i)}, start)
+	}
+	assert.True(t, buffer.Due(start))
+}
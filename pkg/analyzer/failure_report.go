@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// Metrics of the failures that are not a metric family of the epoch completeness
+const (
+	transitionFailure = "transition"
+	epochFailure      = "epoch"
+)
+
+// ProcessingFailure is a metric that could not be processed or persisted for an epoch,
+// with the slots it failed at for the metrics persisted per block
+type ProcessingFailure struct {
+	Epoch  uint64   `json:"epoch"`
+	Metric string   `json:"metric"`
+	Slots  []uint64 `json:"slots,omitempty"`
+	Reason string   `json:"reason"` // first error, the rest are in the logs
+}
+
+// FailureReport lists the epochs of the run that failed to process, so they can be reprocessed.
+// Slots and epochs are plain numbers, unlike the quoted ones of the beacon API
+type FailureReport struct {
+	DownloadMode string              `json:"download_mode"`
+	InitSlot     uint64              `json:"init_slot"`
+	FinalSlot    uint64              `json:"final_slot"`
+	FailedEpochs []uint64            `json:"failed_epochs"`
+	Failures     []ProcessingFailure `json:"failures"`
+
+	HeaderOnlyTransactions *HeaderOnlySummary `json:"header_only_transactions,omitempty"` // stored without receipts
+}
+
+type failureKey struct {
+	epoch  phase0.Epoch
+	metric string
+}
+
+// failureRecorder gathers the failures of the run, one per epoch and metric
+type failureRecorder struct {
+	sync.Mutex
+	failures map[failureKey]*ProcessingFailure
+}
+
+func newFailureRecorder() *failureRecorder {
+	return &failureRecorder{
+		failures: make(map[failureKey]*ProcessingFailure),
+	}
+}
+
+// Add records that the metric failed for the epoch, at the given slot if it is persisted per block
+func (r *failureRecorder) Add(epoch phase0.Epoch, metric string, slot *phase0.Slot, reason string) {
+	r.Lock()
+	defer r.Unlock()
+
+	key := failureKey{epoch: epoch, metric: metric}
+	failure, ok := r.failures[key]
+	if !ok {
+		failure = &ProcessingFailure{
+			Epoch:  uint64(epoch),
+			Metric: metric,
+			Reason: reason,
+		}
+		r.failures[key] = failure
+	}
+	if slot != nil {
+		failure.Slots = append(failure.Slots, uint64(*slot))
+	}
+}
+
+// Failures returns the failures sorted by epoch and metric
+func (r *failureRecorder) Failures() []ProcessingFailure {
+	r.Lock()
+	defer r.Unlock()
+
+	failures := make([]ProcessingFailure, 0, len(r.failures))
+	for _, failure := range r.failures {
+		failures = append(failures, *failure)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Epoch != failures[j].Epoch {
+			return failures[i].Epoch < failures[j].Epoch
+		}
+		return failures[i].Metric < failures[j].Metric
+	})
+	return failures
+}
+
+// recordBlockFailure records that the family could not be persisted for the block at the slot
+func (s *ChainAnalyzer) recordBlockFailure(slot phase0.Slot, family spec.MetricFamily, reason string) {
+	s.failures.Add(phase0.Epoch(slot/spec.SlotsPerEpoch), string(family), &slot, reason)
+}
+
+// recordEpochFailure records that the metric could not be processed or persisted for the epoch
+func (s *ChainAnalyzer) recordEpochFailure(epoch phase0.Epoch, metric string, reason string) {
+	s.failures.Add(epoch, metric, nil, reason)
+}
+
+// FailureReport returns the failures of the run, empty if every epoch was processed
+func (s *ChainAnalyzer) FailureReport() FailureReport {
+	report := FailureReport{
+		DownloadMode: s.downloadMode,
+		InitSlot:     uint64(s.initSlot),
+		FinalSlot:    uint64(s.finalSlot),
+		FailedEpochs: make([]uint64, 0),
+		Failures:     s.failures.Failures(),
+	}
+	if s.headerOnlyTxs != nil {
+		report.HeaderOnlyTransactions = s.headerOnlyTxs.Summary()
+	}
+	for _, failure := range report.Failures {
+		if len(report.FailedEpochs) == 0 || report.FailedEpochs[len(report.FailedEpochs)-1] != failure.Epoch {
+			report.FailedEpochs = append(report.FailedEpochs, failure.Epoch)
+		}
+	}
+	return report
+}
+
+// WriteFailureReport writes the report as JSON to the given file
+func WriteFailureReport(report FailureReport, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	FinalityDistance = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "finality_distance_epochs",
+		Help:      "The number of epochs between the last processed epoch and its finalized checkpoint",
+	})
+	FinalityStalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "finality_stalls",
+		Help:      "The number of times the finality distance exceeded each alert threshold",
+	}, []string{"threshold"})
+)
+
+// parseFinalityThresholds parses the comma separated finality distances (in epochs) that raise an alert
+func parseFinalityThresholds(input string) ([]uint64, error) {
+	thresholds := make([]uint64, 0)
+	for _, item := range strings.Split(input, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		threshold, err := strconv.ParseUint(item, 10, 64)
+		if err != nil || threshold <= 2 { // 2 epochs is the distance of a healthy chain
+			return nil, fmt.Errorf("invalid finality alert threshold %s, expected a number of epochs greater than 2", item)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i] < thresholds[j] })
+	return thresholds, nil
+}
+
+// finalityAlerts tracks the distance between the processed epochs and their finalized checkpoint,
+// alerting once per threshold exceeded until finality recovers under the lowest one
+type finalityAlerts struct {
+	sync.Mutex
+	thresholds []uint64 // ascending, empty disables the alerts
+
+	lastEpoch phase0.Epoch
+	distance  uint64
+	exceeded  int // thresholds alerted in the current stall
+}
+
+func newFinalityAlerts(thresholds []uint64) *finalityAlerts {
+	return &finalityAlerts{
+		thresholds: thresholds,
+	}
+}
+
+// Check registers the finality distance at the epoch, returning the highest threshold it exceeded for
+// the first time in the stall, if any, and whether finality recovered. Epochs older than the last one are ignored
+func (a *finalityAlerts) Check(epoch phase0.Epoch, distance uint64) (threshold uint64, alert bool, recovered bool) {
+	a.Lock()
+	defer a.Unlock()
+	if epoch < a.lastEpoch {
+		return 0, false, false
+	}
+	a.lastEpoch, a.distance = epoch, distance
+
+	exceeded := 0
+	for exceeded < len(a.thresholds) && distance > a.thresholds[exceeded] {
+		exceeded++
+	}
+	if exceeded == 0 {
+		recovered = a.exceeded > 0
+		a.exceeded = 0
+		return 0, false, recovered
+	}
+	if exceeded <= a.exceeded {
+		return 0, false, false
+	}
+	a.exceeded = exceeded
+	return a.thresholds[exceeded-1], true, false
+}
+
+// Distance returns the finality distance of the last epoch checked
+func (a *finalityAlerts) Distance() uint64 {
+	a.Lock()
+	defer a.Unlock()
+	return a.distance
+}
+
+// processFinalityStall updates the finality distance with the checkpoints of the epoch, notifying the thresholds it exceeds.
+// Only epochs followed live are notified, a stall in the history is not actionable
+func (s *ChainAnalyzer) processFinalityStall(checkpoints spec.EpochCheckpoints) {
+	distance := checkpoints.FinalityDelay()
+	threshold, alert, recovered := s.finalityAlerts.Check(checkpoints.Epoch, distance)
+	FinalityDistance.Set(float64(s.finalityAlerts.Distance()))
+	if recovered {
+		log.Infof("finality recovered at epoch %d, %d epochs behind", checkpoints.Epoch, distance)
+	}
+	if !alert || s.downloadMode != "finalized" {
+		return
+	}
+
+	FinalityStalls.WithLabelValues(strconv.FormatUint(threshold, 10)).Inc()
+	message := fmt.Sprintf("epoch %d is %d epochs ahead of its finalized checkpoint at epoch %d, more than %d",
+		checkpoints.Epoch, distance, checkpoints.FinalizedCheckpoint.Epoch, threshold)
+	err := s.notifier.Notify(notifier.Notification{
+		Rule:    "finality_stall",
+		Kind:    notifier.FinalityStallKind,
+		Epoch:   checkpoints.Epoch,
+		Message: message,
+	})
+	if err != nil {
+		log.Errorf("error sending finality stall notification: %s", err.Error())
+	}
+}
+
+func (s *ChainAnalyzer) getFinalityDistance() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(FinalityDistance)
+		prometheus.MustRegister(FinalityStalls)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.finalityAlerts.Distance(), nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"finality_distance",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init finality_distance"))
+		return nil
+	}
+	return indvMetr
+}
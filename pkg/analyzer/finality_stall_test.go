@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFinalityThresholds(t *testing.T) {
+	thresholds, err := parseFinalityThresholds("16, 4,8")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{4, 8, 16}, thresholds)
+
+	thresholds, err = parseFinalityThresholds("")
+	assert.NoError(t, err)
+	assert.Empty(t, thresholds)
+
+	_, err = parseFinalityThresholds("2")
+	assert.Error(t, err)
+	_, err = parseFinalityThresholds("4,x")
+	assert.Error(t, err)
+}
+
+func TestFinalityAlertsCheck(t *testing.T) {
+	alerts := newFinalityAlerts([]uint64{4, 8, 16})
+
+	for _, step := range []struct {
+		epoch     uint64
+		distance  uint64
+		threshold uint64
+		alert     bool
+		recovered bool
+	}{
+		{epoch: 100, distance: 2},
+		{epoch: 101, distance: 5, threshold: 4, alert: true},
+		{epoch: 102, distance: 6}, // same threshold
+		{epoch: 90, distance: 20}, // older epoch
+		{epoch: 103, distance: 20, threshold: 16, alert: true},
+		{epoch: 104, distance: 10}, // still stalled
+		{epoch: 105, distance: 2, recovered: true},
+		{epoch: 106, distance: 9, threshold: 8, alert: true},
+	} {
+		threshold, alert, recovered := alerts.Check(phase0.Epoch(step.epoch), step.distance)
+		assert.Equal(t, step.threshold, threshold, step.epoch)
+		assert.Equal(t, step.alert, alert, step.epoch)
+		assert.Equal(t, step.recovered, recovered, step.epoch)
+	}
+	assert.Equal(t, uint64(9), alerts.Distance())
+
+	disabled := newFinalityAlerts(nil)
+	_, alert, _ := disabled.Check(1, 100)
+	assert.False(t, alert)
+	assert.Equal(t, uint64(100), disabled.Distance())
+}
+
+func TestProcessFinalityStallHeadOnly(t *testing.T) {
+	var posted atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted.Add(1)
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		downloadMode string
+		notified     int32
+	}{
+		{downloadMode: "historical", notified: 0},
+		{downloadMode: "finalized", notified: 1},
+	} {
+		posted.Store(0)
+		s := &ChainAnalyzer{
+			downloadMode:   test.downloadMode,
+			finalityAlerts: newFinalityAlerts([]uint64{4}),
+			notifier:       notifier.NewNotifier(context.Background(), server.URL),
+		}
+		checkpoints := spec.EpochCheckpoints{Epoch: 100}
+		checkpoints.FinalizedCheckpoint.Epoch = 90
+		s.processFinalityStall(checkpoints)
+		assert.Equal(t, test.notified, posted.Load(), test.downloadMode)
+		assert.Equal(t, uint64(10), s.finalityAlerts.Distance())
+	}
+}
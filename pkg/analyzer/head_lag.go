@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	HeadLagSlots = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "head_lag_slots",
+		Help:      "The number of slots the analyzer is behind the chain head",
+	})
+	CatchingUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "catching_up",
+		Help:      "Whether optional metrics are disabled to catch up with the chain head (1) or not (0)",
+	})
+)
+
+// headLag tracks how far behind the chain head the analyzer is, and whether
+// the expensive optional metrics are paused to catch up
+type headLag struct {
+	sync.Mutex
+	lastProcessedSlot phase0.Slot
+	catchingUp        int32 // read on every block, atomic
+
+	alarmSlots  uint64 // lag from which optional metrics are paused, 0 disables the alarm
+	resumeSlots uint64 // lag under which optional metrics are resumed
+}
+
+func newHeadLag(alarmSlots uint64, resumeSlots uint64) *headLag {
+	return &headLag{
+		alarmSlots:  alarmSlots,
+		resumeSlots: resumeSlots,
+	}
+}
+
+func (h *headLag) Processed(slot phase0.Slot) {
+	h.Lock()
+	defer h.Unlock()
+	if slot > h.lastProcessedSlot {
+		h.lastProcessedSlot = slot
+	}
+}
+
+func (h *headLag) LastProcessed() phase0.Slot {
+	h.Lock()
+	defer h.Unlock()
+	return h.lastProcessedSlot
+}
+
+func (h *headLag) CatchingUp() bool {
+	return atomic.LoadInt32(&h.catchingUp) == 1
+}
+
+// Check registers the lag, returning whether it raised the alarm (above alarmSlots) or recovered from it
+// (at or under resumeSlots). In between the state is kept, so the lag does not flap around a single threshold
+func (h *headLag) Check(lag uint64) (alarm bool, recovered bool) {
+	if h.alarmSlots == 0 {
+		return false, false
+	}
+	switch {
+	case !h.CatchingUp() && lag > h.alarmSlots:
+		h.setCatchingUp(true)
+		return true, false
+	case h.CatchingUp() && lag <= h.resumeSlots:
+		h.setCatchingUp(false)
+		return false, true
+	}
+	return false, false
+}
+
+func (h *headLag) setCatchingUp(catchingUp bool) {
+	value := int32(0)
+	if catchingUp {
+		value = 1
+	}
+	atomic.StoreInt32(&h.catchingUp, value)
+	CatchingUp.Set(float64(value))
+}
+
+// currentLag returns the slots between the wall clock slot and the last processed one
+func (s *ChainAnalyzer) currentLag() uint64 {
+	if time.Now().Before(s.genesisTime) {
+		return 0
+	}
+	wallSlot := uint64(time.Since(s.genesisTime).Seconds()) / spec.SlotSeconds
+	lastProcessed := uint64(s.headLag.LastProcessed())
+	if lastProcessed >= wallSlot {
+		return 0
+	}
+	return wallSlot - lastProcessed
+}
+
+// runHeadLagMonitor checks the lag every slot. Above the alarm threshold transactions
+// and blobs stop being processed until the lag goes back under the resume threshold
+func (s *ChainAnalyzer) runHeadLagMonitor() {
+	if s.headLag.alarmSlots == 0 {
+		log.Infof("head lag alarm disabled")
+	}
+	ticker := time.NewTicker(spec.SlotSeconds * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.checkHeadLag(s.currentLag())
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// checkHeadLag updates the lag gauge and notifies the alarm and the recovery
+func (s *ChainAnalyzer) checkHeadLag(lag uint64) {
+	HeadLagSlots.Set(float64(lag))
+
+	alarm, recovered := s.headLag.Check(lag)
+	var message string
+	switch {
+	case alarm:
+		message = fmt.Sprintf("analyzer is %d slots behind the chain head (alarm at %d): pausing transactions and blobs to catch up", lag, s.headLag.alarmSlots)
+		log.Warn(message)
+	case recovered:
+		message = fmt.Sprintf("analyzer caught up with the chain head (%d slots behind): resuming transactions and blobs", lag)
+		log.Info(message)
+	default:
+		return
+	}
+	err := s.notifier.Notify(notifier.Notification{
+		Rule:    "head_lag",
+		Kind:    notifier.HeadLagKind,
+		Epoch:   phase0.Epoch(s.headLag.LastProcessed() / spec.SlotsPerEpoch),
+		Message: message,
+	})
+	if err != nil {
+		log.Errorf("error sending head lag notification: %s", err.Error())
+	}
+}
+
+func (s *ChainAnalyzer) getHeadLag() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(HeadLagSlots)
+		prometheus.MustRegister(CatchingUp)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.currentLag(), nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"head_lag_slots",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init head_lag_slots"))
+		return nil
+	}
+
+	return indvMetr
+}
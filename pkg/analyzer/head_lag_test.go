@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeadLagCheck(t *testing.T) {
+	lag := newHeadLag(10, 4)
+
+	for _, step := range []struct {
+		lag        uint64
+		alarm      bool
+		recovered  bool
+		catchingUp bool
+	}{
+		{lag: 2},
+		{lag: 10}, // at the alarm threshold
+		{lag: 11, alarm: true, catchingUp: true},
+		{lag: 20, catchingUp: true},
+		{lag: 8, catchingUp: true}, // under the alarm, over the resume threshold
+		{lag: 11, catchingUp: true},
+		{lag: 4, recovered: true},
+		{lag: 8}, // over the resume threshold, under the alarm
+		{lag: 12, alarm: true, catchingUp: true},
+	} {
+		alarm, recovered := lag.Check(step.lag)
+		assert.Equal(t, step.alarm, alarm, step.lag)
+		assert.Equal(t, step.recovered, recovered, step.lag)
+		assert.Equal(t, step.catchingUp, lag.CatchingUp(), step.lag)
+	}
+
+	disabled := newHeadLag(0, 0)
+	alarm, _ := disabled.Check(1000)
+	assert.False(t, alarm)
+	assert.False(t, disabled.CatchingUp())
+}
+
+func TestCheckHeadLagNotifies(t *testing.T) {
+	var posted atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted.Add(1)
+	}))
+	defer server.Close()
+
+	s := &ChainAnalyzer{
+		headLag:  newHeadLag(10, 4),
+		notifier: notifier.NewNotifier(context.Background(), server.URL),
+	}
+	for _, lag := range []uint64{2, 11, 20, 8, 3, 0} {
+		s.checkHeadLag(lag)
+	}
+	assert.Equal(t, int32(2), posted.Load()) // alarm and recovery
+}
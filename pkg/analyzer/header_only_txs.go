@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// data of the transactions metric that requires the receipts, skipped without execution endpoint
+var headerOnlySkipped = []string{"gas_used", "effective_gas_price", "contract_address", "receipt_logs", "eth1_deposits"}
+
+// HeaderOnlySummary reports the transactions persisted from the block bodies alone, without their receipts
+type HeaderOnlySummary struct {
+	Blocks       uint64   `json:"blocks"`
+	Transactions uint64   `json:"transactions"`
+	FirstSlot    uint64   `json:"first_slot"`
+	LastSlot     uint64   `json:"last_slot"`
+	Skipped      []string `json:"skipped"`
+}
+
+// headerOnlyTxs counts the blocks whose transactions were persisted without receipts
+type headerOnlyTxs struct {
+	sync.Mutex
+	blocks       uint64
+	transactions uint64
+	firstSlot    phase0.Slot
+	lastSlot     phase0.Slot
+}
+
+func newHeaderOnlyTxs() *headerOnlyTxs {
+	return &headerOnlyTxs{}
+}
+
+func (h *headerOnlyTxs) Add(slot phase0.Slot, transactions int) {
+	h.Lock()
+	defer h.Unlock()
+	if h.blocks == 0 || slot < h.firstSlot {
+		h.firstSlot = slot
+	}
+	if slot > h.lastSlot {
+		h.lastSlot = slot
+	}
+	h.blocks++
+	h.transactions += uint64(transactions)
+}
+
+// Summary returns the blocks persisted so far, nil if there were none
+func (h *headerOnlyTxs) Summary() *HeaderOnlySummary {
+	h.Lock()
+	defer h.Unlock()
+	if h.blocks == 0 {
+		return nil
+	}
+	return &HeaderOnlySummary{
+		Blocks:       h.blocks,
+		Transactions: h.transactions,
+		FirstSlot:    uint64(h.firstSlot),
+		LastSlot:     uint64(h.lastSlot),
+		Skipped:      headerOnlySkipped,
+	}
+}
+
+// processHeaderOnlyTransactions persists the transactions of the block without receipts, flagged as header only,
+// and the blob sidecars, which come from the beacon node
+func (s *ChainAnalyzer) processHeaderOnlyTransactions(block *spec.AgnosticBlock) ([]spec.MetricFamily, error) {
+	txs, err := spec.ParseHeaderOnlyTransactions(*block, s.txStorage)
+	if err != nil {
+		log.Errorf("error getting slot %d transactions: %s", block.Slot, err.Error())
+		return []spec.MetricFamily{spec.TransactionsFamily, spec.BlobsFamily}, err
+	}
+	block.ExecutionPayload.AgnosticTransactions = txs
+	if len(txs) > 0 {
+		if err := s.dbClient.PersistTransactions(txs); err != nil {
+			log.Errorf("error persisting transactions: %s", err.Error())
+			return []spec.MetricFamily{spec.TransactionsFamily, spec.BlobsFamily}, err
+		}
+	}
+	s.headerOnlyTxs.Add(block.Slot, len(txs))
+
+	if err := s.processBlobSidecars(block, txs); err != nil {
+		return []spec.MetricFamily{spec.BlobsFamily}, err
+	}
+	return nil, nil
+}
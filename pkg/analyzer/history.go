@@ -0,0 +1,38 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/chaintime"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// availableInitSlot moves the start of the range to the first epoch whose state the node can serve.
+// Pruned nodes do not keep the history before their checkpoint, requesting it would fail on every state.
+// The unavailable span is reported so it can be filled later from an archive node
+func (s *ChainAnalyzer) availableInitSlot(init phase0.Slot, end phase0.Slot) phase0.Slot {
+	if !s.metrics.Epoch { // no states are downloaded
+		return init
+	}
+	initEpoch := phase0.Epoch(init / spec.SlotsPerEpoch)
+	endEpoch := phase0.Epoch(end / spec.SlotsPerEpoch)
+
+	earliest, err := s.cli.EarliestAvailableEpoch(initEpoch, endEpoch)
+	if err != nil {
+		log.Errorf("could not detect the history available in the node, keeping the range from slot %d: %s", init, err)
+		return init
+	}
+	if earliest == initEpoch {
+		return init
+	}
+
+	chainTime := chaintime.New(s.genesisTime)
+	from := chainTime.AtEpoch(initEpoch)
+	to := chainTime.AtEpoch(earliest)
+	log.Warnf("the node does not keep the states of epochs %d to %d (slots %d to %d, %s to %s), it is a pruned node. "+
+		"Skipping %d epochs, use an archive node to analyze them",
+		initEpoch, earliest-1, from.Slot, to.Slot-1,
+		from.Time.UTC().Format(time.DateOnly), to.Time.UTC().Format(time.DateOnly), earliest-initEpoch)
+	return to.Slot
+}
@@ -0,0 +1,369 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+const (
+	// journal records, one per line followed by the slot or epoch
+	journalMarks         = "m" // first slot of the range, next slot and next epoch to commit, everything between is committed
+	journalDownloaded    = "d" // block of the slot downloaded
+	journalBlock         = "b" // block of the slot processed, the metrics that failed are in t_epoch_completeness
+	journalEpoch         = "e" // transition to the epoch processed
+	journalReopenedSlot  = "r" // metrics of the slot deleted to be rewritten (i.e. a reorg), not committed until processed again
+	journalReopenedEpoch = "x" // metrics of the epoch deleted to be rewritten
+
+	journalCompactRecords = 50000 // records appended before rewriting the journal with the marks only
+)
+
+// downloadJournal is a write-ahead journal of the slots downloaded and committed to the database.
+// Every record is synced to disk, so after a crash the analyzer resumes from the first slot that was
+// not committed instead of rewinding several epochs from the last slot in the database
+type downloadJournal struct {
+	sync.Mutex
+	path        string
+	file        *os.File
+	trackBlocks bool // blocks are persisted per slot
+	trackEpochs bool // states are processed per epoch
+
+	started        bool
+	base           phase0.Slot  // first slot of the range the marks refer to
+	slotMark       phase0.Slot  // every slot from the base is committed up to this one
+	epochMark      phase0.Epoch // every full transition from the base is committed up to this one
+	blocks         map[phase0.Slot]struct{}
+	epochs         map[phase0.Epoch]struct{}
+	pending        map[phase0.Slot]struct{} // downloaded but not committed yet
+	reopenedSlots  map[phase0.Slot]struct{} // committed once, deleted to be rewritten
+	reopenedEpochs map[phase0.Epoch]struct{}
+	records        int
+}
+
+// openDownloadJournal loads the journal at the given path, creating it if missing, and rewrites it compacted
+func openDownloadJournal(path string, trackBlocks bool, trackEpochs bool) (*downloadJournal, error) {
+	j := &downloadJournal{
+		path:           path,
+		trackBlocks:    trackBlocks,
+		trackEpochs:    trackEpochs,
+		blocks:         make(map[phase0.Slot]struct{}),
+		epochs:         make(map[phase0.Epoch]struct{}),
+		pending:        make(map[phase0.Slot]struct{}),
+		reopenedSlots:  make(map[phase0.Slot]struct{}),
+		reopenedEpochs: make(map[phase0.Epoch]struct{}),
+	}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	if err := j.compact(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *downloadJournal) load() error {
+	file, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var kind string
+		var first, second, third uint64
+		// the last line may be cut by the crash, it is ignored
+		n, _ := fmt.Sscanf(scanner.Text(), "%s %d %d %d", &kind, &first, &second, &third)
+		if n < 2 {
+			continue
+		}
+		switch kind {
+		case journalMarks:
+			if n < 4 {
+				continue
+			}
+			j.started = true
+			j.base = phase0.Slot(first)
+			j.slotMark = phase0.Slot(second)
+			j.epochMark = phase0.Epoch(third)
+		case journalDownloaded:
+			j.pending[phase0.Slot(first)] = struct{}{}
+		case journalBlock:
+			j.blocks[phase0.Slot(first)] = struct{}{}
+			delete(j.pending, phase0.Slot(first))
+			delete(j.reopenedSlots, phase0.Slot(first))
+		case journalEpoch:
+			j.epochs[phase0.Epoch(first)] = struct{}{}
+			delete(j.reopenedEpochs, phase0.Epoch(first))
+		case journalReopenedSlot:
+			j.reopenedSlots[phase0.Slot(first)] = struct{}{}
+			delete(j.blocks, phase0.Slot(first))
+		case journalReopenedEpoch:
+			j.reopenedEpochs[phase0.Epoch(first)] = struct{}{}
+			delete(j.epochs, phase0.Epoch(first))
+		}
+	}
+	j.advance()
+	return scanner.Err()
+}
+
+// compact rewrites the journal with the marks and the records after them, replacing the old one atomically
+func (j *downloadJournal) compact() error {
+	tmpPath := j.path + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(tmp)
+	if j.started {
+		fmt.Fprintf(writer, "%s %d %d %d\n", journalMarks, j.base, j.slotMark, j.epochMark)
+	}
+	for slot := range j.blocks {
+		fmt.Fprintf(writer, "%s %d\n", journalBlock, slot)
+	}
+	for epoch := range j.epochs {
+		fmt.Fprintf(writer, "%s %d\n", journalEpoch, epoch)
+	}
+	for slot := range j.pending {
+		fmt.Fprintf(writer, "%s %d\n", journalDownloaded, slot)
+	}
+	for slot := range j.reopenedSlots {
+		fmt.Fprintf(writer, "%s %d\n", journalReopenedSlot, slot)
+	}
+	for epoch := range j.reopenedEpochs {
+		fmt.Fprintf(writer, "%s %d\n", journalReopenedEpoch, epoch)
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return err
+	}
+
+	if j.file != nil {
+		j.file.Close()
+	}
+	j.file, err = os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	j.records = 0
+	return err
+}
+
+// advance moves the marks over the committed slots and epochs that follow them,
+// dropping the ones rewritten under the marks
+func (j *downloadJournal) advance() {
+	for slot := range j.blocks {
+		if slot < j.slotMark {
+			delete(j.blocks, slot)
+		}
+	}
+	for epoch := range j.epochs {
+		if epoch < j.epochMark {
+			delete(j.epochs, epoch)
+		}
+	}
+	for _, ok := j.blocks[j.slotMark]; ok; _, ok = j.blocks[j.slotMark] {
+		delete(j.blocks, j.slotMark)
+		j.slotMark++
+	}
+	for _, ok := j.epochs[j.epochMark]; ok; _, ok = j.epochs[j.epochMark] {
+		delete(j.epochs, j.epochMark)
+		j.epochMark++
+	}
+}
+
+// append writes the record and syncs it. It must be called holding the lock
+func (j *downloadJournal) append(kind string, value uint64) {
+	if _, err := fmt.Fprintf(j.file, "%s %d\n", kind, value); err != nil {
+		log.Errorf("could not write to the journal: %s", err)
+		return
+	}
+	if err := j.file.Sync(); err != nil {
+		log.Errorf("could not sync the journal: %s", err)
+	}
+	j.records++
+	if j.records < journalCompactRecords {
+		return
+	}
+	j.advance()
+	if err := j.compact(); err != nil {
+		log.Errorf("could not compact the journal: %s", err)
+	}
+}
+
+// ResumeSlot returns the first slot of the epoch to download from so that every slot and transition
+// not committed is processed again, false if the journal is empty
+func (j *downloadJournal) ResumeSlot() (phase0.Slot, bool) {
+	j.Lock()
+	defer j.Unlock()
+	if !j.started {
+		return 0, false
+	}
+	epoch := phase0.Epoch(j.slotMark / spec.SlotsPerEpoch)
+	if !j.trackBlocks {
+		epoch = j.epochMark
+	}
+	for slot := range j.reopenedSlots {
+		epoch = min(epoch, phase0.Epoch(slot/spec.SlotsPerEpoch))
+	}
+	if j.trackEpochs {
+		// the transition to an epoch needs the states of the two previous ones
+		epoch = min(epoch, max(j.epochMark, 2)-2)
+		for reopened := range j.reopenedEpochs {
+			epoch = min(epoch, max(reopened, 2)-2)
+		}
+	}
+	return phase0.Slot(epoch) * spec.SlotsPerEpoch, true
+}
+
+// Pending returns the slots downloaded before the last stop that were not committed, sorted
+func (j *downloadJournal) Pending() []phase0.Slot {
+	j.Lock()
+	defer j.Unlock()
+	pending := make([]phase0.Slot, 0, len(j.pending))
+	for slot := range j.pending {
+		pending = append(pending, slot)
+	}
+	sort.Slice(pending, func(a, b int) bool { return pending[a] < pending[b] })
+	return pending
+}
+
+// Begin sets the marks at the start of a download range. The journal is kept if the range starts
+// within the committed slots (i.e. at ResumeSlot), otherwise it starts over from the range
+func (j *downloadJournal) Begin(init phase0.Slot) {
+	j.Lock()
+	defer j.Unlock()
+	if j.started && init >= j.base && init <= j.slotMark {
+		return
+	}
+	j.started = true
+	j.base = init
+	j.slotMark = init
+	// the first full transition is two epochs after the first state
+	j.epochMark = phase0.Epoch(init/spec.SlotsPerEpoch) + 2
+	j.blocks = make(map[phase0.Slot]struct{})
+	j.epochs = make(map[phase0.Epoch]struct{})
+	j.pending = make(map[phase0.Slot]struct{})
+	j.reopenedSlots = make(map[phase0.Slot]struct{})
+	j.reopenedEpochs = make(map[phase0.Epoch]struct{})
+	if err := j.compact(); err != nil {
+		log.Errorf("could not write the journal: %s", err)
+	}
+}
+
+// Downloaded records that the block of the slot is in the cache waiting to be processed
+func (j *downloadJournal) Downloaded(slot phase0.Slot) {
+	j.Lock()
+	defer j.Unlock()
+	j.pending[slot] = struct{}{}
+	j.append(journalDownloaded, uint64(slot))
+}
+
+// CommitBlock records that the block was processed
+func (j *downloadJournal) CommitBlock(slot phase0.Slot) {
+	j.Lock()
+	defer j.Unlock()
+	delete(j.pending, slot)
+	_, reopened := j.reopenedSlots[slot]
+	delete(j.reopenedSlots, slot)
+	if !reopened && (slot < j.base || slot < j.slotMark) {
+		return
+	}
+	j.blocks[slot] = struct{}{}
+	j.append(journalBlock, uint64(slot))
+}
+
+// CommitEpoch records that the transition to the epoch was processed
+func (j *downloadJournal) CommitEpoch(epoch phase0.Epoch) {
+	j.Lock()
+	defer j.Unlock()
+	_, reopened := j.reopenedEpochs[epoch]
+	delete(j.reopenedEpochs, epoch)
+	if !reopened && (epoch < phase0.Epoch(j.base/spec.SlotsPerEpoch)+2 || epoch < j.epochMark) {
+		return
+	}
+	j.epochs[epoch] = struct{}{}
+	j.append(journalEpoch, uint64(epoch))
+}
+
+// BlockCommitted tells whether the block of the slot was persisted before
+func (j *downloadJournal) BlockCommitted(slot phase0.Slot) bool {
+	j.Lock()
+	defer j.Unlock()
+	if _, reopened := j.reopenedSlots[slot]; reopened {
+		return false
+	}
+	_, ok := j.blocks[slot]
+	return j.started && slot >= j.base && (slot < j.slotMark || ok)
+}
+
+// EpochCommitted tells whether the transition to the epoch was persisted before
+func (j *downloadJournal) EpochCommitted(epoch phase0.Epoch) bool {
+	j.Lock()
+	defer j.Unlock()
+	if _, reopened := j.reopenedEpochs[epoch]; reopened {
+		return false
+	}
+	_, ok := j.epochs[epoch]
+	return j.started && epoch >= phase0.Epoch(j.base/spec.SlotsPerEpoch)+2 && (epoch < j.epochMark || ok)
+}
+
+// ReopenBlock records that the metrics of the slot were deleted to be written again, so that
+// the slot is processed even if it was committed, also after a restart
+func (j *downloadJournal) ReopenBlock(slot phase0.Slot) {
+	j.Lock()
+	defer j.Unlock()
+	delete(j.blocks, slot)
+	j.reopenedSlots[slot] = struct{}{}
+	j.append(journalReopenedSlot, uint64(slot))
+}
+
+// ReopenEpoch records that the metrics of the epoch were deleted to be written again
+func (j *downloadJournal) ReopenEpoch(epoch phase0.Epoch) {
+	j.Lock()
+	defer j.Unlock()
+	delete(j.epochs, epoch)
+	j.reopenedEpochs[epoch] = struct{}{}
+	j.append(journalReopenedEpoch, uint64(epoch))
+}
+
+func (j *downloadJournal) Close() {
+	j.Lock()
+	defer j.Unlock()
+	j.advance()
+	if err := j.compact(); err != nil {
+		log.Errorf("could not compact the journal: %s", err)
+	}
+	j.file.Close()
+}
+
+// journalResumeSlot returns the slot to resume from according to the journal, reporting the slots
+// that were downloaded but not processed before the analyzer stopped
+func (s *ChainAnalyzer) journalResumeSlot() (phase0.Slot, bool) {
+	if s.journal == nil {
+		return 0, false
+	}
+	resume, ok := s.journal.ResumeSlot()
+	if !ok {
+		return 0, false
+	}
+	if pending := s.journal.Pending(); len(pending) > 0 {
+		log.Warnf("%d slots were downloaded but not processed before the analyzer stopped (%d to %d)",
+			len(pending), pending[0], pending[len(pending)-1])
+	}
+	log.Infof("journal found, resuming from slot %d, epoch %d", resume, resume/spec.SlotsPerEpoch)
+	return resume, true
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadJournalResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+
+	journal, err := openDownloadJournal(path, true, true)
+	assert.NoError(t, err)
+	_, ok := journal.ResumeSlot()
+	assert.False(t, ok)
+
+	journal.Begin(320)
+	for _, slot := range []phase0.Slot{321, 320, 322, 324} { // 323 never processed
+		journal.Downloaded(slot)
+		journal.CommitBlock(slot)
+	}
+	journal.Downloaded(323)
+	journal.CommitEpoch(12)
+	journal.CommitEpoch(14) // 13 missing
+	journal.file.Close()    // crash, no compaction
+
+	// a record cut by the crash is ignored
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(t, err)
+	_, err = file.WriteString("b")
+	assert.NoError(t, err)
+	file.Close()
+
+	journal, err = openDownloadJournal(path, true, true)
+	assert.NoError(t, err)
+	resume, ok := journal.ResumeSlot()
+	assert.True(t, ok)
+	assert.Equal(t, phase0.Slot(320), resume)
+	assert.Equal(t, []phase0.Slot{323}, journal.Pending())
+
+	assert.True(t, journal.BlockCommitted(322))
+	assert.False(t, journal.BlockCommitted(323))
+	assert.True(t, journal.BlockCommitted(324))
+	assert.True(t, journal.EpochCommitted(12))
+	assert.False(t, journal.EpochCommitted(13))
+	assert.True(t, journal.EpochCommitted(14))
+
+	// resuming keeps the journal, a range out of it starts over
+	journal.Begin(resume)
+	assert.True(t, journal.BlockCommitted(324))
+	journal.Begin(6400)
+	assert.False(t, journal.BlockCommitted(324))
+	resume, _ = journal.ResumeSlot()
+	assert.Equal(t, phase0.Slot(6400), resume)
+	journal.Close()
+}
+
+func TestDownloadJournalReorg(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+
+	journal, err := openDownloadJournal(path, true, true)
+	assert.NoError(t, err)
+	journal.Begin(320)
+	for slot := phase0.Slot(320); slot < 416; slot++ {
+		journal.Downloaded(slot)
+		journal.CommitBlock(slot)
+	}
+	journal.CommitEpoch(12)
+	journal.advance()
+	assert.True(t, journal.BlockCommitted(350))
+	assert.True(t, journal.EpochCommitted(12))
+
+	// the reorg deletes the metrics of the slot and the epoch, they must be processed again
+	journal.ReopenBlock(350)
+	journal.ReopenEpoch(12)
+	assert.False(t, journal.BlockCommitted(350))
+	assert.False(t, journal.EpochCommitted(12))
+	assert.True(t, journal.BlockCommitted(351))
+	journal.file.Close() // crash before the rewrite
+
+	journal, err = openDownloadJournal(path, true, true)
+	assert.NoError(t, err)
+	assert.False(t, journal.BlockCommitted(350))
+	assert.False(t, journal.EpochCommitted(12))
+	resume, _ := journal.ResumeSlot()
+	assert.Equal(t, phase0.Slot(320), resume) // the states of epoch 10 and 11 are needed for the transition to 12
+
+	// once rewritten, they are committed again, also after a restart
+	journal.CommitBlock(350)
+	journal.CommitEpoch(12)
+	assert.True(t, journal.BlockCommitted(350))
+	assert.True(t, journal.EpochCommitted(12))
+	journal.file.Close()
+
+	journal, err = openDownloadJournal(path, true, true)
+	assert.NoError(t, err)
+	assert.True(t, journal.BlockCommitted(350))
+	assert.True(t, journal.EpochCommitted(12))
+	resume, _ = journal.ResumeSlot()
+	assert.Equal(t, phase0.Slot(352), resume) // the transition to epoch 13 is next
+	journal.Close()
+}
@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	participationFlags = []string{"source", "target", "head"}
+
+	LiveParticipationEpoch = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "live_participation_epoch",
+		Help:      "The epoch of the last live participation estimate",
+	})
+	LiveParticipationRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "live_participation_rate",
+		Help:      "The attesting balance over the active balance of the head epoch with the attestations included so far, in %",
+	}, []string{"flag"})
+	LiveAttestingBalance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "live_attesting_balance_eth",
+		Help:      "The effective balance of the validators with each flag in the head epoch so far, in ETH",
+	}, []string{"flag"})
+	LiveAttestingValidators = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "live_attesting_validators",
+		Help:      "The number of validators with each flag in the head epoch so far",
+	}, []string{"flag"})
+)
+
+// liveParticipation keeps the epoch of the last published estimate, so older states do not overwrite it
+type liveParticipation struct {
+	sync.Mutex
+	epoch phase0.Epoch
+}
+
+func newLiveParticipation() *liveParticipation {
+	return &liveParticipation{}
+}
+
+// publishLiveParticipation publishes the current epoch participation of a state at the chain head:
+// the gauges and the participation notification rules
+func (s *ChainAnalyzer) publishLiveParticipation(state *spec.AgnosticState) {
+	participation := state.CurrentParticipation
+	if len(participation.AttestingBalance) == 0 {
+		return // phase0 states do not have participation flags
+	}
+	if time.Now().Before(s.genesisTime) {
+		return
+	}
+	// states of the historical fill are not live
+	wallEpoch := phase0.Epoch(uint64(time.Since(s.genesisTime).Seconds()) / spec.SlotSeconds / spec.SlotsPerEpoch)
+	if participation.Epoch+1 < wallEpoch {
+		return
+	}
+
+	s.liveParticipation.Lock()
+	if participation.Epoch <= s.liveParticipation.epoch {
+		s.liveParticipation.Unlock()
+		return
+	}
+	s.liveParticipation.epoch = participation.Epoch
+	s.liveParticipation.Unlock()
+
+	LiveParticipationEpoch.Set(float64(participation.Epoch))
+	for flag, name := range participationFlags {
+		LiveParticipationRate.WithLabelValues(name).Set(participation.Rate(altair.ParticipationFlag(flag)))
+		LiveAttestingBalance.WithLabelValues(name).Set(float64(participation.AttestingBalance[flag]) / spec.EffectiveBalanceInc)
+		LiveAttestingValidators.WithLabelValues(name).Set(float64(participation.AttestingVals[flag]))
+	}
+	targetRate := participation.Rate(altair.TimelyTargetFlagIndex)
+	log.Debugf("live participation at epoch %d: %.2f%% target", participation.Epoch, targetRate)
+
+	if s.rulesEngine == nil || !s.rulesEngine.HasParticipationRules() {
+		return
+	}
+	for _, notification := range s.rulesEngine.EvaluateParticipation(participation.Epoch, targetRate) {
+		if err := s.notifier.Notify(notification); err != nil {
+			log.Errorf("error sending notification of rule %s: %s", notification.Rule, err.Error())
+		}
+	}
+}
+
+func (s *ChainAnalyzer) getLiveParticipation() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(LiveParticipationEpoch)
+		prometheus.MustRegister(LiveParticipationRate)
+		prometheus.MustRegister(LiveAttestingBalance)
+		prometheus.MustRegister(LiveAttestingValidators)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		s.liveParticipation.Lock()
+		defer s.liveParticipation.Unlock()
+		return s.liveParticipation.epoch, nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"live_participation_epoch",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init live_participation_epoch"))
+		return nil
+	}
+
+	return indvMetr
+}
@@ -0,0 +1,175 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/migalabs/goteth/pkg/clientapi"
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	NodeLatency = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "node_health_latency_seconds",
+		Help:      "The time the beacon node took to answer the last health check",
+	})
+	NodeThrottled = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "node_throttled",
+		Help:      "Whether the downloads are throttled (1) or not (0) because the beacon node is unhealthy",
+	})
+	BackfillPaused = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "backfill_paused",
+		Help:      "Whether the historical downloads are paused (1) or not (0) because the beacon node is unhealthy",
+	})
+	NodeThrottleTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "node_throttle_transitions",
+		Help:      "The number of times the downloads were throttled or resumed after a beacon node health check",
+	}, []string{"state"})
+
+	throttledRoutines      = 8 // slots processed concurrently while throttled, a quarter of the processer book
+	healthyChecksToRecover = 3 // consecutive healthy checks before resuming, so a flapping node is not hammered
+)
+
+// nodeThrottle slows down the downloads while the beacon node reports it is syncing or answers slowly
+type nodeThrottle struct {
+	interval      time.Duration // between health checks, 0 disables them
+	maxLatency    time.Duration // above it the node is considered overloaded
+	pauseBackfill bool          // whether the historical downloads stop while throttled
+
+	throttled int32 // read on every download task, atomic
+	healthy   int   // consecutive healthy checks while throttled
+}
+
+func newNodeThrottle(interval time.Duration, maxLatency time.Duration, pauseBackfill bool) *nodeThrottle {
+	return &nodeThrottle{
+		interval:      interval,
+		maxLatency:    maxLatency,
+		pauseBackfill: pauseBackfill,
+	}
+}
+
+func (t *nodeThrottle) Throttled() bool {
+	return atomic.LoadInt32(&t.throttled) == 1
+}
+
+// Limited tells whether a new slot must wait given the routines already active
+func (t *nodeThrottle) Limited(activeRoutines int) bool {
+	return t.Throttled() && activeRoutines >= throttledRoutines
+}
+
+// BackfillPaused tells whether the historical downloads must wait for the node to recover
+func (t *nodeThrottle) BackfillPaused() bool {
+	return t.pauseBackfill && t.Throttled()
+}
+
+// unhealthyReason returns why the node is considered unhealthy, empty if it is healthy
+func (t *nodeThrottle) unhealthyReason(health clientapi.NodeHealth, err error) string {
+	switch {
+	case err != nil:
+		return err.Error()
+	case health.Syncing:
+		return fmt.Sprintf("node is syncing, %d slots behind", health.SyncDistance)
+	case t.maxLatency > 0 && health.Latency > t.maxLatency:
+		return fmt.Sprintf("node answered in %s, above %s", health.Latency.Round(time.Millisecond), t.maxLatency)
+	default:
+		return ""
+	}
+}
+
+// update applies the result of a health check, returning whether the throttle changed
+func (t *nodeThrottle) update(reason string) bool {
+	if reason != "" {
+		t.healthy = 0
+		return atomic.CompareAndSwapInt32(&t.throttled, 0, 1)
+	}
+	if !t.Throttled() {
+		return false
+	}
+	t.healthy++
+	if t.healthy < healthyChecksToRecover {
+		return false
+	}
+	t.healthy = 0
+	return atomic.CompareAndSwapInt32(&t.throttled, 1, 0)
+}
+
+// runNodeHealthMonitor checks the sync status of the beacon node periodically. While it is syncing
+// or slow, fewer slots are downloaded at once and, optionally, the historical downloads are paused
+func (s *ChainAnalyzer) runNodeHealthMonitor() {
+	if s.nodeThrottle.interval == 0 {
+		log.Infof("node health checks disabled")
+		return
+	}
+	ticker := time.NewTicker(s.nodeThrottle.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			health, err := s.cli.RequestNodeHealth()
+			NodeLatency.Set(health.Latency.Seconds())
+			reason := s.nodeThrottle.unhealthyReason(health, err)
+			if !s.nodeThrottle.update(reason) {
+				continue
+			}
+
+			paused := 0.0
+			if s.nodeThrottle.Throttled() {
+				if s.nodeThrottle.pauseBackfill {
+					paused = 1
+				}
+				NodeThrottled.Set(1)
+				NodeThrottleTransitions.WithLabelValues("throttled").Inc()
+				log.Warnf("beacon node unhealthy (%s): throttling downloads to %d slots at once, backfill paused: %t",
+					reason, throttledRoutines, s.nodeThrottle.pauseBackfill)
+			} else {
+				NodeThrottled.Set(0)
+				NodeThrottleTransitions.WithLabelValues("resumed").Inc()
+				log.Infof("beacon node healthy for %d checks: resuming downloads", healthyChecksToRecover)
+			}
+			BackfillPaused.Set(paused)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *ChainAnalyzer) getNodeThrottle() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(NodeLatency)
+		prometheus.MustRegister(NodeThrottled)
+		prometheus.MustRegister(BackfillPaused)
+		prometheus.MustRegister(NodeThrottleTransitions)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.nodeThrottle.Throttled(), nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"node_throttled",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init node_throttled"))
+		return nil
+	}
+
+	return indvMetr
+}
@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/migalabs/goteth/pkg/clientapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeThrottle(t *testing.T) {
+	throttle := newNodeThrottle(time.Second, 2*time.Second, true)
+
+	healthy := throttle.unhealthyReason(clientapi.NodeHealth{Latency: time.Second}, nil)
+	assert.Empty(t, healthy)
+	assert.NotEmpty(t, throttle.unhealthyReason(clientapi.NodeHealth{Syncing: true}, nil))
+	assert.NotEmpty(t, throttle.unhealthyReason(clientapi.NodeHealth{Latency: 3 * time.Second}, nil))
+	assert.NotEmpty(t, throttle.unhealthyReason(clientapi.NodeHealth{}, errors.New("timeout")))
+
+	assert.False(t, throttle.update(healthy))
+	assert.True(t, throttle.update("node is syncing"))
+	assert.False(t, throttle.update("node is syncing"))
+	assert.True(t, throttle.BackfillPaused())
+	assert.True(t, throttle.Limited(throttledRoutines))
+	assert.False(t, throttle.Limited(throttledRoutines-1))
+
+	// a flapping node stays throttled
+	assert.False(t, throttle.update(healthy))
+	assert.False(t, throttle.update(healthy))
+	assert.True(t, throttle.Throttled())
+	assert.False(t, throttle.update("slow"))
+	for i := 1; i < healthyChecksToRecover; i++ {
+		assert.False(t, throttle.update(healthy))
+	}
+	assert.True(t, throttle.update(healthy))
+	assert.False(t, throttle.Throttled())
+	assert.False(t, throttle.Limited(throttledRoutines))
+}
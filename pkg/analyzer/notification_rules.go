@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+)
+
+// processNotificationRules evaluates the notification rules once the epoch has been processed:
+// the attestations of the monitored validators at nextState and the pool efficiencies at currentState
+func (s *ChainAnalyzer) processNotificationRules(bundle metrics.StateMetrics) {
+	if s.rulesEngine == nil {
+		return
+	}
+	notifications := make([]notifier.Notification, 0)
+
+	validators := s.rulesEngine.Validators()
+	if len(validators) > 0 {
+		nextState := bundle.GetMetricsBase().NextState
+		rewards := make([]spec.ValidatorRewards, 0, len(validators))
+		for _, valIdx := range validators {
+			if int(valIdx) >= len(nextState.Validators) {
+				continue // validator is not in the chain yet
+			}
+			reward, err := bundle.GetMaxReward(valIdx)
+			if err != nil {
+				log.Errorf("error obtaining rewards of validator %d for the notification rules: %s", valIdx, err.Error())
+				continue
+			}
+			rewards = append(rewards, reward)
+		}
+		notifications = append(notifications, s.rulesEngine.EvaluateRewards(nextState.Epoch, rewards)...)
+	}
+
+	if s.rulesEngine.HasPoolRules() {
+		// pool summaries are created at currentState
+		epoch := bundle.GetMetricsBase().CurrentState.Epoch
+		efficiencies, err := s.dbClient.RetrievePoolEfficiencies(epoch)
+		if err != nil {
+			log.Errorf("error retrieving pool efficiencies at epoch %d: %s", epoch, err.Error())
+		} else {
+			notifications = append(notifications, s.rulesEngine.EvaluatePools(epoch, efficiencies)...)
+		}
+	}
+
+	for _, notification := range notifications {
+		if err := s.notifier.Notify(notification); err != nil {
+			log.Errorf("error sending notification of rule %s: %s", notification.Rule, err.Error())
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package analyzer
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+)
+
+// processPoolLuck compares the proposals and sync committee seats of every pool at the given epoch with the ones
+// expected from its share of the active stake, read from the validator rewards persisted by then
+func (s *ChainAnalyzer) processPoolLuck(epoch phase0.Epoch) {
+	pools, total, err := s.dbClient.RetrievePoolStakes(epoch)
+	if err != nil {
+		log.Errorf("error retrieving the pool stakes of the pool luck at epoch %d: %s", epoch, err.Error())
+		return
+	}
+	luck := spec.NewPoolLuck(epoch, pools, total, metrics.CommitteeConfigFromNode(s.cli.Api))
+	if len(luck) == 0 {
+		return
+	}
+	if err := s.dbClient.PersistPoolLuck(luck); err != nil {
+		log.Errorf("error persisting pool luck at epoch %d: %s", epoch, err.Error())
+	}
+}
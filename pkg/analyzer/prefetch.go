@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	minPrefetchEpochs = 2   // the transition to an epoch needs the states of the two previous ones
+	prefetchSmoothing = 0.2 // weight of the last measurement in the moving averages
+)
+
+var PrefetchEpochs = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: strings.ToLower(utils.CliName),
+	Subsystem: modName,
+	Name:      "prefetch_epochs",
+	Help:      "The number of epochs downloaded ahead of the epoch being processed",
+})
+
+// prefetchWindow adapts the number of epochs whose states and blocks are downloaded ahead of the processor.
+// The window grows while the state downloads are slower than the epoch processing, so the processor does not
+// wait for them, and shrinks while the heap is over the memory budget, moving one epoch at a time
+type prefetchWindow struct {
+	sync.Mutex
+	maxEpochs    uint64
+	memoryBudget uint64 // heap bytes, 0 for no limit
+
+	epochs   uint64
+	download time.Duration // moving average of the state downloads
+	process  time.Duration // moving average of the epoch transitions
+}
+
+func newPrefetchWindow(maxEpochs uint64, memoryBudget uint64) *prefetchWindow {
+	return &prefetchWindow{
+		maxEpochs:    max(maxEpochs, minPrefetchEpochs),
+		memoryBudget: memoryBudget,
+		epochs:       minPrefetchEpochs,
+	}
+}
+
+func movingAverage(avg time.Duration, sample time.Duration) time.Duration {
+	if avg == 0 {
+		return sample
+	}
+	return time.Duration(float64(avg)*(1-prefetchSmoothing) + float64(sample)*prefetchSmoothing)
+}
+
+// Adaptive tells whether the window can grow over the minimum
+func (w *prefetchWindow) Adaptive() bool {
+	return w.maxEpochs > minPrefetchEpochs
+}
+
+func (w *prefetchWindow) StateDownloaded(elapsed time.Duration) {
+	w.Lock()
+	defer w.Unlock()
+	w.download = movingAverage(w.download, elapsed)
+}
+
+func (w *prefetchWindow) EpochProcessed(elapsed time.Duration) {
+	w.Lock()
+	defer w.Unlock()
+	w.process = movingAverage(w.process, elapsed)
+}
+
+// Epochs returns the number of epochs downloaded ahead of the processed one
+func (w *prefetchWindow) Epochs() uint64 {
+	w.Lock()
+	defer w.Unlock()
+	return w.epochs
+}
+
+// Adjust moves the window one epoch towards the one that keeps the processor busy, given the heap in use
+func (w *prefetchWindow) Adjust(heapBytes uint64) uint64 {
+	w.Lock()
+	defer w.Unlock()
+
+	target := uint64(minPrefetchEpochs)
+	if w.process > 0 && w.download > w.process {
+		// one extra epoch per epoch processed while a state downloads
+		target += uint64((w.download+w.process-1)/w.process) - 1
+	}
+	target = min(target, w.maxEpochs)
+
+	switch {
+	case w.memoryBudget > 0 && heapBytes > w.memoryBudget:
+		if w.epochs > minPrefetchEpochs {
+			w.epochs--
+		}
+	case target > w.epochs:
+		w.epochs++
+	case target < w.epochs:
+		w.epochs--
+	}
+	return w.epochs
+}
+
+// adjustPrefetch updates the prefetch window after the transition of an epoch
+func (s *ChainAnalyzer) adjustPrefetch() {
+	if !s.prefetch.Adaptive() {
+		return
+	}
+	heapBytes := uint64(0)
+	if s.prefetch.memoryBudget > 0 {
+		var memStats runtime.MemStats
+		runtime.ReadMemStats(&memStats)
+		heapBytes = memStats.HeapAlloc
+	}
+	prev := s.prefetch.Epochs()
+	if epochs := s.prefetch.Adjust(heapBytes); epochs != prev {
+		log.Debugf("prefetch window moved from %d to %d epochs", prev, epochs)
+	}
+}
+
+// historicalCleanUpSlot returns the slot the caches can be cleaned up to while downloading the given one in historical
+// mode: the prefetch window and 3 more epochs before it, but never the states the epochs still in flight (the epoch
+// processer pages) wait for, which can be further behind once the window shrinks
+func historicalCleanUpSlot(slot phase0.Slot, window uint64, inFlight []string) (phase0.Slot, bool) {
+	keep := phase0.Slot(window+3) * spec.SlotsPerEpoch
+	if slot <= keep {
+		return 0, false
+	}
+	cleanUpTo := slot - keep
+	for _, key := range inFlight {
+		epoch, err := strconv.ParseUint(strings.TrimPrefix(key, epochProcesserTag), 10, 64)
+		if err != nil || !strings.HasPrefix(key, epochProcesserTag) {
+			continue
+		}
+		// the transition to the epoch waits for the states of the two previous ones
+		firstSlot := phase0.Slot(0)
+		if epoch >= 2 {
+			firstSlot = phase0.Slot(epoch-2) * spec.SlotsPerEpoch
+		}
+		if firstSlot < cleanUpTo {
+			cleanUpTo = firstSlot
+		}
+	}
+	return cleanUpTo, cleanUpTo > 0
+}
+
+func (s *ChainAnalyzer) getPrefetchEpochs() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(PrefetchEpochs)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		epochs := s.prefetch.Epochs()
+		PrefetchEpochs.Set(float64(epochs))
+		return epochs, nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"prefetch_epochs",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init prefetch_epochs"))
+		return nil
+	}
+	return indvMetr
+}
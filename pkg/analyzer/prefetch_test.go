@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefetchWindow(t *testing.T) {
+	window := newPrefetchWindow(5, 1000)
+	assert.True(t, window.Adaptive())
+	assert.Equal(t, uint64(minPrefetchEpochs), window.Epochs())
+
+	// states take 4 times the processing of an epoch: grow one epoch at a time up to the maximum
+	window.StateDownloaded(4 * time.Second)
+	window.EpochProcessed(time.Second)
+	assert.Equal(t, uint64(3), window.Adjust(0))
+	assert.Equal(t, uint64(4), window.Adjust(0))
+	assert.Equal(t, uint64(5), window.Adjust(0))
+	assert.Equal(t, uint64(5), window.Adjust(0))
+
+	// over the memory budget
+	assert.Equal(t, uint64(4), window.Adjust(2000))
+
+	// the processor is the bottleneck: back to the minimum
+	for i := 0; i < 20; i++ {
+		window.EpochProcessed(10 * time.Second)
+	}
+	window.Adjust(0)
+	window.Adjust(0)
+	assert.Equal(t, uint64(minPrefetchEpochs), window.Adjust(0))
+	assert.Equal(t, uint64(minPrefetchEpochs), window.Adjust(2000))
+
+	assert.False(t, newPrefetchWindow(0, 0).Adaptive())
+}
+
+func TestHistoricalCleanUpSlot(t *testing.T) {
+	// too close to the start of the range
+	_, ok := historicalCleanUpSlot(100, 2, nil)
+	assert.False(t, ok)
+
+	// nothing in flight: the window and 3 more epochs
+	slot, ok := historicalCleanUpSlot(3200, 2, []string{"block=3199"})
+	assert.True(t, ok)
+	assert.Equal(t, phase0.Slot(3200-5*32), slot)
+
+	// the window shrank from 6 to 2 epochs while epoch 92 was still waiting for the states from epoch 90
+	slot, ok = historicalCleanUpSlot(3200, 2, []string{epochProcesserTag + "97", epochProcesserTag + "92"})
+	assert.True(t, ok)
+	assert.Equal(t, phase0.Slot(90*32), slot)
+
+	// an epoch in flight waiting for the first states
+	_, ok = historicalCleanUpSlot(3200, 2, []string{epochProcesserTag + "1"})
+	assert.False(t, ok)
+}
@@ -20,42 +20,91 @@ func (s *ChainAnalyzer) ProcessBlock(slot phase0.Slot) {
 	s.processerBook.Acquire(routineKey) // register a new slot to process, good for monitoring
 
 	block := s.downloadCache.BlockHistory.Wait(SlotTo[uint64](slot))
+	if s.journal != nil && s.journal.BlockCommitted(slot) {
+		log.Debugf("block at slot %d already processed before the restart", slot)
+		s.headLag.Processed(slot)
+		s.processerBook.FreePage(routineKey)
+		return
+	}
+	failed := make([]spec.MetricFamily, 0) // metric families not persisted for the block
+	fail := func(reason string, families ...spec.MetricFamily) {
+		for _, family := range families {
+			failed = append(failed, family)
+			s.recordBlockFailure(slot, family, reason)
+		}
+	}
+	s.processAttestationTargets(block)
 	err := s.dbClient.PersistBlocks([]spec.AgnosticBlock{*block})
 	if err != nil {
 		log.Errorf("error persisting blocks: %s", err.Error())
+		fail(err.Error(), spec.BlocksFamily)
+	} else {
+		s.progress.BlockPersisted(slot)
+	}
+	if s.kafkaSink != nil {
+		if err := s.kafkaSink.PublishBlock(*block); err != nil {
+			log.Errorf("error publishing block: %s", err.Error())
+		}
 	}
 
-	s.processWithdrawals(block)
+	s.processBlockArrival(block)
+	s.processVoteTally(block)
+	s.processBalanceSamples(slot)
+	if err := s.processWithdrawals(block); err != nil {
+		fail(err.Error(), spec.WithdrawalsFamily)
+	}
 
 	if s.metrics.Transactions {
-		s.ProcessETH1Data(block)
+		if s.headLag.CatchingUp() { // skipped, the epoch stays incomplete
+			fail("skipped while catching up with the head", spec.TransactionsFamily, spec.BlobsFamily)
+		} else if families, err := s.ProcessETH1Data(block); err != nil {
+			fail(err.Error(), families...)
+		}
 	}
 	s.processBLSToExecutionChanges(block)
 	s.processDeposits(block)
+	s.processVoluntaryExits(block)
+	s.processConsolidationRequests(block)
+	s.processBlockCompleteness(slot, failed)
+	if s.journal != nil {
+		s.journal.CommitBlock(slot)
+	}
+	s.headLag.Processed(slot)
+	if !s.metrics.Epoch && (slot+1)%spec.SlotsPerEpoch == 0 { // no epoch transitions to summarize at
+		s.logProgress(phase0.Epoch(slot / spec.SlotsPerEpoch))
+	}
 	s.processerBook.FreePage(routineKey)
 }
 
-func (s *ChainAnalyzer) ProcessETH1Data(block *spec.AgnosticBlock) {
+// ProcessETH1Data persists the execution data of the block, returning the metric families that could not be persisted
+// and the error that stopped them
+func (s *ChainAnalyzer) ProcessETH1Data(block *spec.AgnosticBlock) ([]spec.MetricFamily, error) {
+	if s.headerOnlyTxs != nil {
+		return s.processHeaderOnlyTransactions(block)
+	}
 	receipts, err := s.cli.GetBlockReceipts(*block)
 	if err != nil {
 		log.Errorf("error getting slot %d receipts: %s", block.Slot, err.Error())
-		return
+		return []spec.MetricFamily{spec.TransactionsFamily, spec.BlobsFamily}, err
 	}
 
 	err = s.processTransactions(block, receipts)
 	if err != nil {
 		log.Errorf("error processing transactions: %s", err.Error())
-		return
+		return []spec.MetricFamily{spec.TransactionsFamily, spec.BlobsFamily}, err
 	}
 
 	// process eth1 deposits depends on processTransactions storing the receipts on the Agnostic transactions
 	err = s.processETH1Deposits(block)
 	if err != nil {
 		log.Errorf("error processing eth1 deposits: %s", err.Error())
-		return
+		return []spec.MetricFamily{spec.TransactionsFamily, spec.BlobsFamily}, err
 	}
 
-	s.processBlobSidecars(block, block.ExecutionPayload.AgnosticTransactions)
+	if err := s.processBlobSidecars(block, block.ExecutionPayload.AgnosticTransactions); err != nil {
+		return []spec.MetricFamily{spec.BlobsFamily}, err
+	}
+	return nil, nil
 }
 
 func (s *ChainAnalyzer) processETH1Deposits(block *spec.AgnosticBlock) error {
@@ -103,6 +152,25 @@ func (s *ChainAnalyzer) processDeposits(block *spec.AgnosticBlock) {
 
 }
 
+func (s *ChainAnalyzer) processVoluntaryExits(block *spec.AgnosticBlock) {
+	if len(block.VoluntaryExits) == 0 {
+		return
+	}
+	var exits []spec.VoluntaryExit
+	for _, item := range block.VoluntaryExits {
+		exits = append(exits, spec.VoluntaryExit{
+			Slot:           block.Slot,
+			ValidatorIndex: item.Message.ValidatorIndex,
+			Epoch:          item.Message.Epoch,
+		})
+	}
+
+	err := s.dbClient.PersistVoluntaryExits(exits)
+	if err != nil {
+		log.Errorf("error persisting voluntary exits: %s", err.Error())
+	}
+}
+
 func (s *ChainAnalyzer) processBLSToExecutionChanges(block *spec.AgnosticBlock) {
 	if len(block.BLSToExecutionChanges) == 0 {
 		return
@@ -124,7 +192,30 @@ func (s *ChainAnalyzer) processBLSToExecutionChanges(block *spec.AgnosticBlock)
 	}
 }
 
-func (s *ChainAnalyzer) processWithdrawals(block *spec.AgnosticBlock) {
+// Process EIP-7251 consolidation requests (since Electra)
+func (s *ChainAnalyzer) processConsolidationRequests(block *spec.AgnosticBlock) {
+	if len(block.ConsolidationRequests) == 0 {
+		return
+	}
+	var requests []spec.ConsolidationRequest
+	for i, item := range block.ConsolidationRequests {
+		requests = append(requests, spec.ConsolidationRequest{
+			Slot:          block.Slot,
+			Epoch:         spec.EpochAtSlot(block.Slot),
+			Index:         uint8(i),
+			SourceAddress: item.SourceAddress,
+			SourcePubkey:  item.SourcePubkey,
+			TargetPubkey:  item.TargetPubkey,
+		})
+	}
+
+	err := s.dbClient.PersistConsolidationRequests(requests)
+	if err != nil {
+		log.Errorf("error persisting consolidation requests: %s", err.Error())
+	}
+}
+
+func (s *ChainAnalyzer) processWithdrawals(block *spec.AgnosticBlock) error {
 	var withdrawals []spec.Withdrawal
 	for _, item := range block.ExecutionPayload.Withdrawals {
 		withdrawals = append(withdrawals, spec.Withdrawal{
@@ -140,12 +231,12 @@ func (s *ChainAnalyzer) processWithdrawals(block *spec.AgnosticBlock) {
 	if err != nil {
 		log.Errorf("error persisting withdrawals: %s", err.Error())
 	}
-
+	return err
 }
 
 func (s *ChainAnalyzer) processTransactions(block *spec.AgnosticBlock, receipts []*types.Receipt) error {
 
-	txs, err := spec.ParseTransactionsFromBlock(*block, receipts)
+	txs, err := spec.ParseTransactionsFromBlock(*block, receipts, s.txStorage)
 	if err != nil {
 		log.Errorf("error getting slot %d transactions: %s", block.Slot, err.Error())
 		return err
@@ -157,11 +248,23 @@ func (s *ChainAnalyzer) processTransactions(block *spec.AgnosticBlock, receipts
 	err = s.dbClient.PersistTransactions(txs)
 	if err != nil {
 		log.Errorf("error persisting transactions: %s", err.Error())
+		return err
+	}
+	if s.txStorage != spec.FullTxStorage {
+		return nil
+	}
+	logs := spec.TransactionLogs(txs)
+	if len(logs) == 0 {
+		return nil
+	}
+	err = s.dbClient.PersistTransactionLogs(logs)
+	if err != nil {
+		log.Errorf("error persisting transaction logs: %s", err.Error())
 	}
 	return err
 }
 
-func (s *ChainAnalyzer) processBlobSidecars(block *spec.AgnosticBlock, txs []spec.AgnosticTransaction) {
+func (s *ChainAnalyzer) processBlobSidecars(block *spec.AgnosticBlock, txs []spec.AgnosticTransaction) error {
 	blobs, err := s.cli.RequestBlobSidecars(block.Slot)
 	if err != nil {
 		log.Fatalf("could not download blob sidecars for slot %d: %s", block.Slot, err)
@@ -170,6 +273,7 @@ func (s *ChainAnalyzer) processBlobSidecars(block *spec.AgnosticBlock, txs []spe
 		for _, blob := range blobs {
 			blob.GetTxHash(txs)
 		}
-		s.dbClient.PersistBlobSidecars(blobs)
+		return s.dbClient.PersistBlobSidecars(blobs)
 	}
+	return nil
 }
@@ -2,6 +2,9 @@ package analyzer
 
 import (
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/migalabs/goteth/pkg/db"
@@ -24,6 +27,7 @@ func (s *ChainAnalyzer) ProcessStateTransitionMetrics(epoch phase0.Epoch) {
 
 	routineKey := fmt.Sprintf("%s%d", epochProcesserTag, epoch)
 	s.processerBook.Acquire(routineKey) // resgiter we are about to process metrics for epoch
+	s.epochWatchdog.Start(epoch)
 
 	// Retrieve states to process metrics
 
@@ -40,29 +44,86 @@ func (s *ChainAnalyzer) ProcessStateTransitionMetrics(epoch phase0.Epoch) {
 	}
 	nextState = s.downloadCache.StateHistory.Wait(EpochTo[uint64](epoch))
 
-	bundle, err := metrics.StateMetricsByForkVersion(nextState, currentState, prevState, s.cli.Api)
-	if err != nil {
+	// there is no epoch before genesis, the genesis state acts as previous one
+	if epoch == 1 && !currentState.EmptyStateRoot() {
+		log.Debugf("processing genesis transition, no previous epoch participation")
+		prevState = currentState.GenesisPrevState()
+	}
+
+	if s.journal != nil && s.journal.EpochCommitted(epoch) {
+		log.Debugf("transition to epoch %d already processed before the restart", epoch)
+		s.epochWatchdog.Done(epoch)
 		s.processerBook.FreePage(routineKey)
+		return
+	}
+	s.epochWatchdog.Stage(epoch, processStage)
+	start := time.Now()
+	err := s.processStateTransition(prevState, currentState, nextState, true)
+	if err != nil {
 		log.Errorf("could not parse bundle metrics at epoch: %s", err)
+		s.recordEpochFailure(epoch, transitionFailure, err.Error())
 		s.stop = true
+	} else {
+		s.progress.EpochPersisted()
+		if s.journal != nil {
+			s.journal.CommitEpoch(epoch)
+		}
+		s.logProgress(epoch)
+		s.prefetch.EpochProcessed(time.Since(start))
+		s.adjustPrefetch()
+		s.processCalibration()
+	}
+
+	s.epochWatchdog.Done(epoch)
+	s.processerBook.FreePage(routineKey)
+
+}
+
+// processStateTransition computes and persists the metrics of the transition between the given states,
+// aggregate tells whether the validator rewards are added to the aggregation in progress
+func (s *ChainAnalyzer) processStateTransition(prevState *spec.AgnosticState, currentState *spec.AgnosticState, nextState *spec.AgnosticState, aggregate bool) error {
+	bundle, err := s.stateSetups.Transition(nextState, currentState, prevState)
+	if err != nil {
+		return err
 	}
+	s.epochWatchdog.Stage(nextState.Epoch, persistStage)
 
 	// If prevState, currentState and nextState are filled, we can process proposer duties, epoch metrics and validator rewards
 	if !nextState.EmptyStateRoot() && !currentState.EmptyStateRoot() && !prevState.EmptyStateRoot() {
 		s.processEpochDuties(bundle)
-		s.processValLastStatus(bundle)
+		if aggregate { // the table only keeps the last epoch, a reprocessed one would leave stale rows next to it
+			s.processValLastStatus(bundle)
+		}
 
 		s.processPoolMetrics(bundle.GetMetricsBase().CurrentState.Epoch)
 		s.processEpochMetrics(bundle)
-		s.processBlockRewards(bundle) // block rewards depend on two previous epochs
-		if s.metrics.ValidatorRewards {
-			s.processEpochValRewards(bundle)
+		s.processDataQualityNotes(bundle)
+		if s.metrics.Block { // block rewards need the block bodies
+			s.processBlockRewards(bundle) // block rewards depend on two previous epochs
+		}
+		if s.metrics.ValidatorRewards || s.metrics.EpochAggregates {
+			s.processEpochValRewards(bundle, aggregate)
 		}
 		s.processSlashings(bundle)
+		s.processCheckpoints(bundle)
+		s.processRandao(bundle)
+		s.processCompoundingBalances(bundle)
+		s.processEpochChurn(bundle)
+		s.processEffectiveBalanceHistogram(bundle)
+		s.processEquivocations(bundle)
+		s.processAttestationInclusions(bundle)
+		s.processSlashingProtection(bundle)
+		s.processValidatorStatusCheck(bundle.GetMetricsBase().CurrentState)
+		s.processRegistryCheck(bundle)
+		s.processValidatorKeys(bundle)
+		s.processSelfCheck(bundle)
+		s.processDualWriteCheck(bundle.GetMetricsBase().CurrentState.Epoch)
+		if aggregate { // reprocessed epochs were already evaluated
+			s.processNotificationRules(bundle)
+			s.processProposerFairness(bundle.GetMetricsBase().NextState)
+		}
 	}
-
-	s.processerBook.FreePage(routineKey)
-
+	return nil
 }
 
 func (s *ChainAnalyzer) processSlashings(bundle metrics.StateMetrics) {
@@ -76,23 +137,161 @@ func (s *ChainAnalyzer) processSlashings(bundle metrics.StateMetrics) {
 	}
 }
 
+// processEquivocations collects the attester slashings included in the epoch blocks and,
+// for the monitored validators, the slashable votes included in them
+func (s *ChainAnalyzer) processEquivocations(bundle metrics.StateMetrics) {
+	base := bundle.GetMetricsBase()
+
+	equivocations := make([]spec.Equivocation, 0)
+	for _, block := range base.NextState.Blocks {
+		for _, slashing := range block.AttesterSlashings {
+			equivocations = append(equivocations, spec.AttesterSlashingEquivocations(slashing, block.Slot)...)
+		}
+		if s.voteTracker.Monitoring() {
+			equivocations = append(equivocations, s.checkBlockVotes(block, base.CurrentState, base.NextState)...)
+		}
+	}
+	s.voteTracker.CleanUpTo(base.NextState.Epoch)
+
+	if len(equivocations) == 0 {
+		return
+	}
+	for _, equivocation := range equivocations {
+		log.Warnf("%s by validator %d at slot %d (included in a slashing: %t): votes %d->%d and %d->%d",
+			equivocation.Kind, equivocation.ValidatorIndex, equivocation.Slot, equivocation.Included,
+			equivocation.Vote1.SourceEpoch, equivocation.Vote1.TargetEpoch,
+			equivocation.Vote2.SourceEpoch, equivocation.Vote2.TargetEpoch)
+		EquivocationsDetected.WithLabelValues(string(equivocation.Kind), strconv.FormatBool(equivocation.Included)).Inc()
+	}
+	err := s.dbClient.PersistEquivocations(equivocations)
+	if err != nil {
+		log.Errorf("error persisting equivocations: %s", err.Error())
+	}
+	s.notifyEquivocations(base.NextState.Epoch, equivocations)
+}
+
+// checkBlockVotes checks the votes of the monitored validators in the block attestations
+func (s *ChainAnalyzer) checkBlockVotes(block *spec.AgnosticBlock, currentState *spec.AgnosticState, nextState *spec.AgnosticState) []spec.Equivocation {
+	equivocations := make([]spec.Equivocation, 0)
+
+	for _, attestation := range block.Attestations {
+		duties := nextState.EpochStructs
+		if spec.EpochAtSlot(attestation.Data.Slot) != nextState.Epoch {
+			duties = currentState.EpochStructs
+		}
+		committee := duties.GetValList(attestation.Data.Slot, attestation.Data.Index)
+
+		var vote *spec.VoteSummary
+		for i, valIdx := range committee {
+			if !s.voteTracker.Monitored(valIdx) || !attestation.AggregationBits.BitAt(uint64(i)) {
+				continue
+			}
+			if vote == nil { // only hash the data if a monitored validator voted
+				summary := spec.NewVoteSummary(attestation.Data)
+				vote = &summary
+			}
+			for _, conflict := range s.voteTracker.Check(valIdx, *vote) {
+				equivocations = append(equivocations, spec.Equivocation{
+					ValidatorIndex: valIdx,
+					Kind:           spec.ClassifyVotes(conflict, *vote),
+					Slot:           block.Slot,
+					Included:       false,
+					Vote1:          conflict,
+					Vote2:          *vote,
+				})
+			}
+		}
+	}
+	return equivocations
+}
+
+func (s *ChainAnalyzer) processCheckpoints(bundle metrics.StateMetrics) {
+	checkpoints := spec.NewEpochCheckpoints(*bundle.GetMetricsBase().NextState)
+	err := s.dbClient.PersistEpochCheckpoints([]spec.EpochCheckpoints{checkpoints})
+	if err != nil {
+		log.Errorf("error persisting epoch checkpoints: %s", err.Error())
+	}
+	s.processFinalityStall(checkpoints)
+}
+
+// processRandao persists the randao mix of the epoch and how much its last proposers could bias it
+func (s *ChainAnalyzer) processRandao(bundle metrics.StateMetrics) {
+	randao := spec.NewEpochRandao(*bundle.GetMetricsBase().NextState)
+	err := s.dbClient.PersistEpochRandao([]spec.EpochRandao{randao})
+	if err != nil {
+		log.Errorf("error persisting epoch randao: %s", err.Error())
+	}
+}
+
+// processCompoundingBalances persists the balances of the validators with compounding credentials (since Electra)
+func (s *ChainAnalyzer) processCompoundingBalances(bundle metrics.StateMetrics) {
+	base := bundle.GetMetricsBase()
+	balances := spec.NewCompoundingBalances(base.NextState, base.CurrentState)
+	if len(balances) == 0 {
+		return
+	}
+	err := s.dbClient.PersistCompoundingBalances(balances)
+	if err != nil {
+		log.Errorf("error persisting compounding balances: %s", err.Error())
+	}
+}
+
+// processEpochChurn persists the activation and exit churn consumed at the epoch (since Electra)
+func (s *ChainAnalyzer) processEpochChurn(bundle metrics.StateMetrics) {
+	base := bundle.GetMetricsBase()
+	churn, ok := spec.NewEpochChurn(base.CurrentState, base.NextState)
+	if !ok {
+		return
+	}
+	err := s.dbClient.PersistEpochChurn([]spec.EpochChurn{churn})
+	if err != nil {
+		log.Errorf("error persisting epoch churn: %s", err.Error())
+	}
+}
+
+// processEffectiveBalanceHistogram persists how the effective balance of the active validators is distributed
+func (s *ChainAnalyzer) processEffectiveBalanceHistogram(bundle metrics.StateMetrics) {
+	histogram := bundle.GetMetricsBase().NextState.EffectiveBalanceHistogram
+	if len(histogram) == 0 {
+		return
+	}
+	err := s.dbClient.PersistEffectiveBalanceHistogram(histogram)
+	if err != nil {
+		log.Errorf("error persisting effective balance histogram: %s", err.Error())
+	}
+}
+
 func (s *ChainAnalyzer) processEpochMetrics(bundle metrics.StateMetrics) {
 
 	// we need sameEpoch and nextEpoch
 	metricsBase := bundle.GetMetricsBase()
 	epoch := metricsBase.ExportToEpoch()
+	s.processVoteLatency(bundle, &epoch)
 
 	log.Debugf("persisting epoch metrics: epoch %d", epoch.Epoch)
 
 	err := s.dbClient.PersistEpochs([]spec.Epoch{epoch})
 	if err != nil {
 		log.Errorf("error persisting epoch: %s", err.Error())
+		s.recordEpochFailure(epoch.Epoch, epochFailure, err.Error())
+	}
+	if s.kafkaSink != nil {
+		if err := s.kafkaSink.PublishEpoch(epoch); err != nil {
+			log.Errorf("error publishing epoch: %s", err.Error())
+		}
 	}
 
 }
 
 func (s *ChainAnalyzer) processPoolMetrics(epoch phase0.Epoch) {
 
+	// without the validator rewards rows the pool summaries are aggregated in memory,
+	// pool luck and reconciliation need them
+	if s.metrics.CompactRewards() {
+		s.processClientDiversity(epoch)
+		return
+	}
+
 	log.Debugf("persisting pool summaries: epoch %d", epoch)
 
 	err := s.dbClient.InsertPoolSummary(epoch)
@@ -103,15 +302,51 @@ func (s *ChainAnalyzer) processPoolMetrics(epoch phase0.Epoch) {
 		log.Fatalf("error persisting pool metrics: %s", err.Error())
 	}
 
+	s.processPoolLuck(epoch)
+
+	if err := s.dbClient.InsertPoolReconciliation(epoch); err != nil {
+		log.Errorf("error persisting pool reward reconciliation: %s", err.Error())
+	}
+
+	s.processClientDiversity(epoch)
+
+}
+
+// processDualWriteCheck compares the rows of the epoch before the given one in both databases,
+// once all its blocks and metrics have been written
+func (s *ChainAnalyzer) processDualWriteCheck(epoch phase0.Epoch) {
+	if !s.dbClient.DualWrite() || epoch == 0 {
+		return
+	}
+	mismatches, err := s.dbClient.CheckDualWrite(epoch - 1)
+	if err != nil {
+		log.Errorf("error checking the dual write at epoch %d: %s", epoch-1, err.Error())
+	}
+	for _, mismatch := range mismatches {
+		log.Warnf("table %s has %d rows at epoch %d in the primary database and %d in the secondary",
+			mismatch.Table, mismatch.PrimaryRows, mismatch.Epoch, mismatch.SecondaryRows)
+	}
 }
 
 func (s *ChainAnalyzer) processEpochDuties(bundle metrics.StateMetrics) {
 
-	missedBlocks := bundle.GetMetricsBase().NextState.MissedBlocks
+	duties := epochProposerDuties(bundle.GetMetricsBase().NextState)
+
+	err := s.dbClient.PersistDuties(duties)
+	if err != nil {
+		log.Fatalf("error persisting proposer duties: %s", err.Error())
+	}
+
+}
+
+// epochProposerDuties returns the proposer duties of the state epoch, telling whether each block was proposed
+func epochProposerDuties(state *spec.AgnosticState) []spec.ProposerDuty {
+
+	missedBlocks := state.MissedBlocks
 
 	var duties []spec.ProposerDuty
 
-	for _, item := range bundle.GetMetricsBase().NextState.EpochStructs.ProposerDuties {
+	for _, item := range state.EpochStructs.ProposerDuties {
 
 		newDuty := spec.ProposerDuty{
 			ValIdx:       item.ValidatorIndex,
@@ -125,12 +360,7 @@ func (s *ChainAnalyzer) processEpochDuties(bundle metrics.StateMetrics) {
 		}
 		duties = append(duties, newDuty)
 	}
-
-	err := s.dbClient.PersistDuties(duties)
-	if err != nil {
-		log.Fatalf("error persisting proposer duties: %s", err.Error())
-	}
-
+	return duties
 }
 
 func (s *ChainAnalyzer) processValLastStatus(bundle metrics.StateMetrics) {
@@ -142,8 +372,9 @@ func (s *ChainAnalyzer) processValLastStatus(bundle metrics.StateMetrics) {
 			newVal := spec.ValidatorLastStatus{
 				ValIdx:          phase0.ValidatorIndex(valIdx),
 				Epoch:           bundle.GetMetricsBase().NextState.Epoch,
-				CurrentBalance:  bundle.GetMetricsBase().NextState.Balances[valIdx],
+				CurrentBalance:  bundle.GetMetricsBase().NextState.Balance(phase0.ValidatorIndex(valIdx)),
 				CurrentStatus:   bundle.GetMetricsBase().NextState.GetValStatus(phase0.ValidatorIndex(valIdx)),
+				DetailedStatus:  bundle.GetMetricsBase().NextState.GetValDetailedStatus(phase0.ValidatorIndex(valIdx)),
 				Slashed:         validator.Slashed,
 				ActivationEpoch: validator.ActivationEpoch,
 				WithdrawalEpoch: validator.WithdrawableEpoch,
@@ -166,23 +397,29 @@ func (s *ChainAnalyzer) processValLastStatus(bundle metrics.StateMetrics) {
 	}
 }
 
-func (s *ChainAnalyzer) processEpochValRewards(bundle metrics.StateMetrics) {
+func (s *ChainAnalyzer) processEpochValRewards(bundle metrics.StateMetrics, aggregate bool) {
 	var insertValsObj []spec.ValidatorRewards
 	log.Debugf("persising validator metrics: epoch %d", bundle.GetMetricsBase().NextState.Epoch)
+	complete := true
+
+	// get max reward at given epoch using the formulas
+	workers := int(s.rewardsWorkers.Load())
+	start := time.Now()
+	rewards, errs := computeValRewards(bundle, workers)
+	s.calibration.RewardsComputed(time.Since(start), workers)
 
 	// process each validator
-	for valIdx := range bundle.GetMetricsBase().NextState.Validators {
-		if valIdx >= len(bundle.GetMetricsBase().NextState.Validators) {
-			continue // validator is not in the chain yet
-		}
-		valIdx := phase0.ValidatorIndex(valIdx)
-		// get max reward at given epoch using the formulas
-		maxRewards, err := bundle.GetMaxReward(valIdx)
-		if err != nil {
+	for i, maxRewards := range rewards {
+		valIdx := phase0.ValidatorIndex(i)
+		if err := errs[i]; err != nil {
 			log.Errorf("Error obtaining max reward: %s", err.Error())
+			if complete {
+				s.recordEpochFailure(bundle.GetMetricsBase().NextState.Epoch, string(spec.RewardsFamily), err.Error())
+			}
+			complete = false
 			continue
 		}
-		if s.rewardsAggregationEpochs > 1 {
+		if s.rewardsAggregationEpochs > 1 && aggregate && !s.metrics.CompactRewards() {
 			// if validator is not in s.validatorsRewardsAggregations, we need to create it
 			if _, ok := s.validatorsRewardsAggregations[phase0.ValidatorIndex(valIdx)]; !ok {
 				s.validatorsRewardsAggregations[phase0.ValidatorIndex(valIdx)] = spec.NewValidatorRewardsAggregation(valIdx, s.startEpochAggregation, s.endEpochAggregation)
@@ -191,14 +428,43 @@ func (s *ChainAnalyzer) processEpochValRewards(bundle metrics.StateMetrics) {
 		}
 		insertValsObj = append(insertValsObj, maxRewards)
 	}
-	if len(insertValsObj) > 0 { // persist everything
-		err := s.dbClient.PersistValidatorRewards(insertValsObj)
+	setRewardPercentiles(bundle, insertValsObj)
+	s.processBalanceDrops(bundle, insertValsObj, aggregate)
+	if tagged := s.downtime.Tag(insertValsObj); tagged > 0 {
+		log.Infof("%d missed attestations of epoch %d inside downtime windows", tagged, bundle.GetMetricsBase().NextState.Epoch)
+	}
+
+	if s.metrics.CompactRewards() {
+		s.processPoolSummaries(bundle, insertValsObj)
+	} else if len(insertValsObj) > 0 { // persist everything
+		var err error
+		if s.rewardsDelta != nil {
+			err = s.persistValidatorRewardsDelta(bundle.GetMetricsBase().NextState.Epoch, insertValsObj)
+		} else {
+			err = s.dbClient.PersistValidatorRewards(insertValsObj)
+		}
 		if err != nil {
 			log.Fatalf("error persisting validator rewards: %s", err.Error())
 		}
+		if s.kafkaSink != nil {
+			if err := s.kafkaSink.PublishValidatorRewards(insertValsObj); err != nil {
+				log.Errorf("error publishing validator rewards: %s", err.Error())
+			}
+		}
 	}
 
-	if s.rewardsAggregationEpochs > 1 && bundle.GetMetricsBase().NextState.Epoch == s.endEpochAggregation {
+	err := s.dbClient.PersistEpochCompleteness([]spec.EpochCompleteness{{
+		Epoch:    bundle.GetMetricsBase().NextState.Epoch,
+		Family:   spec.RewardsFamily,
+		Complete: complete,
+	}})
+	if err != nil {
+		log.Errorf("error persisting completeness of epoch %d: %s", bundle.GetMetricsBase().NextState.Epoch, err)
+	}
+
+	s.maintainRewardsWindows(bundle.GetMetricsBase().NextState.Epoch)
+
+	if s.rewardsAggregationEpochs > 1 && aggregate && !s.metrics.CompactRewards() && bundle.GetMetricsBase().NextState.Epoch == s.endEpochAggregation {
 		if len(s.validatorsRewardsAggregations) > 0 {
 			err := s.dbClient.PersistValidatorRewardsAggregation(s.validatorsRewardsAggregations)
 			if err != nil {
@@ -212,6 +478,87 @@ func (s *ChainAnalyzer) processEpochValRewards(bundle metrics.StateMetrics) {
 
 }
 
+// computeValRewards gets the rewards of every validator of the next state, splitting the validators among the workers
+func computeValRewards(bundle metrics.StateMetrics, workers int) ([]spec.ValidatorRewards, []error) {
+	validators := len(bundle.GetMetricsBase().NextState.Validators)
+	rewards := make([]spec.ValidatorRewards, validators)
+	errs := make([]error, validators)
+	if validators == 0 {
+		return rewards, errs
+	}
+
+	chunk := (validators + workers - 1) / workers
+	var wg sync.WaitGroup
+	for first := 0; first < validators; first += chunk {
+		wg.Add(1)
+		go func(first int, last int) {
+			defer wg.Done()
+			for valIdx := first; valIdx < last; valIdx++ {
+				rewards[valIdx], errs[valIdx] = bundle.GetMaxReward(phase0.ValidatorIndex(valIdx))
+			}
+		}(first, min(first+chunk, validators))
+	}
+	wg.Wait()
+	return rewards, errs
+}
+
+// persistValidatorRewardsDelta delta-encodes the rewards of the epoch, persisting the new keyframe first, if any
+func (s *ChainAnalyzer) persistValidatorRewardsDelta(epoch phase0.Epoch, rewards []spec.ValidatorRewards) error {
+	deltas, keyframe, err := s.rewardsDelta.Encode(epoch, rewards)
+	if err != nil {
+		return err
+	}
+	if len(keyframe) > 0 {
+		if err := s.dbClient.PersistValidatorRewardsKeyframe(keyframe); err != nil {
+			return err
+		}
+	}
+	return s.dbClient.PersistValidatorRewardsDelta(deltas)
+}
+
+// processPoolSummaries aggregates the validator rewards of the epoch by pool and persists only the summaries,
+// the pool of every validator is read from t_eth2_pubkeys
+func (s *ChainAnalyzer) processPoolSummaries(bundle metrics.StateMetrics, rewards []spec.ValidatorRewards) {
+	nextState := bundle.GetMetricsBase().NextState
+	log.Debugf("persisting pool summaries: epoch %d", nextState.Epoch)
+
+	pools, err := s.dbClient.RetrieveValidatorPools()
+	if err != nil {
+		log.Errorf("error retrieving the validator pools: %s", err.Error())
+		return
+	}
+	summaries := spec.NewPoolSummaries(nextState.Epoch, rewards, pools, epochProposerDuties(nextState))
+	if len(summaries) == 0 {
+		return
+	}
+	if err := s.dbClient.PersistPoolSummaries(summaries); err != nil {
+		log.Fatalf("error persisting pool metrics: %s", err.Error())
+	}
+}
+
+// setRewardPercentiles ranks the reward of each active validator against the active validators
+// with the same effective balance, so that rewards of different balances are not compared
+func setRewardPercentiles(bundle metrics.StateMetrics, rewards []spec.ValidatorRewards) {
+	currentValidators := bundle.GetMetricsBase().CurrentState.Validators
+
+	positions := make([]int, 0, len(rewards))
+	rewardValues := make([]int64, 0, len(rewards))
+	buckets := make([]uint64, 0, len(rewards))
+	for i, reward := range rewards {
+		if reward.Status != spec.ACTIVE_STATUS || int(reward.ValidatorIndex) >= len(currentValidators) {
+			continue
+		}
+		positions = append(positions, i)
+		rewardValues = append(rewardValues, reward.Reward)
+		buckets = append(buckets, uint64(currentValidators[reward.ValidatorIndex].EffectiveBalance/spec.EffectiveBalanceInc))
+	}
+
+	ranks := spec.RewardPercentileRanks(rewardValues, buckets)
+	for i, position := range positions {
+		rewards[position].RewardPercentile = ranks[i]
+	}
+}
+
 func (s *ChainAnalyzer) processBlockRewards(bundle metrics.StateMetrics) {
 
 	blockRewards := make([]db.BlockReward, 0)
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// progressCounts are the blocks and states handled since the last summary
+type progressCounts struct {
+	blocksDownloaded uint64
+	missedBlocks     uint64
+	statesDownloaded uint64
+	blocksPersisted  uint64
+	epochsPersisted  uint64
+}
+
+// progressSummary aggregates the per-slot progress, logged at debug level, into one summary per epoch at info level
+type progressSummary struct {
+	sync.Mutex
+	counts   progressCounts
+	lastLog  time.Time
+	lastSlot phase0.Slot // last block persisted
+}
+
+func newProgressSummary() *progressSummary {
+	return &progressSummary{
+		lastLog: time.Now(),
+	}
+}
+
+func (p *progressSummary) BlockDownloaded(proposed bool) {
+	p.Lock()
+	defer p.Unlock()
+	p.counts.blocksDownloaded++
+	if !proposed {
+		p.counts.missedBlocks++
+	}
+}
+
+func (p *progressSummary) StateDownloaded() {
+	p.Lock()
+	defer p.Unlock()
+	p.counts.statesDownloaded++
+}
+
+func (p *progressSummary) BlockPersisted(slot phase0.Slot) {
+	p.Lock()
+	defer p.Unlock()
+	p.counts.blocksPersisted++
+	if slot > p.lastSlot {
+		p.lastSlot = slot
+	}
+}
+
+func (p *progressSummary) EpochPersisted() {
+	p.Lock()
+	defer p.Unlock()
+	p.counts.epochsPersisted++
+}
+
+// Flush returns the counts since the last summary and resets them
+func (p *progressSummary) Flush(now time.Time) (progressCounts, time.Duration, phase0.Slot) {
+	p.Lock()
+	defer p.Unlock()
+	counts, elapsed := p.counts, now.Sub(p.lastLog)
+	p.counts = progressCounts{}
+	p.lastLog = now
+	return counts, elapsed, p.lastSlot
+}
+
+// logProgress logs the summary of the blocks and states handled since the last one, once the epoch is processed
+func (s *ChainAnalyzer) logProgress(epoch phase0.Epoch) {
+	counts, elapsed, lastSlot := s.progress.Flush(time.Now())
+	log.Infof("epoch %d processed in %s: %d blocks (%d missed) and %d states downloaded, %d blocks and %d epochs persisted, last block at slot %d",
+		epoch, elapsed.Truncate(time.Millisecond), counts.blocksDownloaded, counts.missedBlocks, counts.statesDownloaded,
+		counts.blocksPersisted, counts.epochsPersisted, lastSlot)
+}
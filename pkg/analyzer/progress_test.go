@@ -0,0 +1,34 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressSummaryFlush(t *testing.T) {
+	progress := newProgressSummary()
+	progress.BlockDownloaded(true)
+	progress.BlockDownloaded(false)
+	progress.StateDownloaded()
+	progress.BlockPersisted(64)
+	progress.BlockPersisted(63)
+	progress.EpochPersisted()
+
+	counts, _, lastSlot := progress.Flush(time.Now())
+	assert.Equal(t, progressCounts{
+		blocksDownloaded: 2,
+		missedBlocks:     1,
+		statesDownloaded: 1,
+		blocksPersisted:  2,
+		epochsPersisted:  1,
+	}, counts)
+	assert.Equal(t, phase0.Slot(64), lastSlot)
+
+	// counts are reset, the last slot is kept
+	counts, _, lastSlot = progress.Flush(time.Now())
+	assert.Equal(t, progressCounts{}, counts)
+	assert.Equal(t, phase0.Slot(64), lastSlot)
+}
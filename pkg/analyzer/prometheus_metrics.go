@@ -36,6 +36,22 @@ func (c *ChainAnalyzer) GetPrometheusMetrics() *metrics.MetricsModule {
 
 	metricsMod.AddIndvMetric(c.getStateHistoryLength())
 	metricsMod.AddIndvMetric(c.getBlockHistoryLength())
+	metricsMod.AddIndvMetric(c.getHeadLag())
+	metricsMod.AddIndvMetric(c.getEquivocations())
+	metricsMod.AddIndvMetric(c.getSlashingProtection())
+	metricsMod.AddIndvMetric(c.getChainSplits())
+	metricsMod.AddIndvMetric(c.getValidatorStatusAnomalies())
+	metricsMod.AddIndvMetric(c.getLiveParticipation())
+	metricsMod.AddIndvMetric(c.getRegistryAnomalies())
+	metricsMod.AddIndvMetric(c.getSelfCheckDiscrepancies())
+	metricsMod.AddIndvMetric(c.getNodeThrottle())
+	metricsMod.AddIndvMetric(c.getEpochWatchdog())
+	metricsMod.AddIndvMetric(c.getStateProofs())
+	metricsMod.AddIndvMetric(c.getFinalityDistance())
+	metricsMod.AddIndvMetric(c.getPrefetchEpochs())
+	metricsMod.AddIndvMetric(c.getStateSubstitutions())
+	metricsMod.AddIndvMetric(c.getHeadBlockArrival())
+	metricsMod.AddIndvMetric(c.getBalanceDrops())
 
 	return metricsMod
 }
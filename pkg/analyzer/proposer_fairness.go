@@ -0,0 +1,33 @@
+package analyzer
+
+import (
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// processProposerFairness adds the proposer duties of the epoch of the state to the accumulated ones,
+// persisting a snapshot of every validator every proposerFairnessEpochs epochs that replaces the previous one.
+// The accumulation is resumed from the last snapshot on the first call
+func (s *ChainAnalyzer) processProposerFairness(state *spec.AgnosticState) {
+	if s.proposerFairnessEpochs == 0 {
+		return
+	}
+	s.proposerFairnessOnce.Do(func() {
+		values, err := s.dbClient.RetrieveProposerFairness()
+		if err != nil {
+			log.Errorf("could not restore the proposer fairness, starting from scratch: %s", err.Error())
+			return
+		}
+		s.proposerFairness.Restore(values)
+		if len(values) > 0 {
+			log.Infof("proposer fairness of %d validators restored", len(values))
+		}
+	})
+
+	if !s.proposerFairness.AddEpoch(state) || state.Epoch%s.proposerFairnessEpochs != 0 {
+		return
+	}
+	err := s.dbClient.PersistProposerFairness(s.proposerFairness.Snapshot(state.Epoch))
+	if err != nil {
+		log.Errorf("error persisting proposer fairness at epoch %d: %s", state.Epoch, err.Error())
+	}
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"strings"
+
+	prom_metrics "github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	RegistryAnomalies = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "registry_anomalies",
+		Help:      "The number of inconsistencies found in the validator registry between consecutive states",
+	}, []string{"kind"})
+)
+
+// processRegistryCheck compares the validator registry of the current state with the previous one
+// and looks up its new public keys in the index of the pipeline, persisting the inconsistencies found
+func (s *ChainAnalyzer) processRegistryCheck(bundle metrics.StateMetrics) {
+	if s.pubkeyRegistry == nil {
+		return
+	}
+	prevState := bundle.GetMetricsBase().PrevState
+	currentState := bundle.GetMetricsBase().CurrentState
+
+	anomalies := spec.CheckValidatorRegistry(prevState, currentState)
+	anomalies = append(anomalies, s.pubkeyRegistry.DuplicatePubkeys(prevState, currentState)...)
+	if len(anomalies) == 0 {
+		return
+	}
+	for _, anomaly := range anomalies {
+		log.Warnf("validator registry anomaly at epoch %d, validator %d (%s): %s",
+			anomaly.Epoch, anomaly.ValidatorIndex, anomaly.Kind, anomaly.Detail)
+		RegistryAnomalies.WithLabelValues(string(anomaly.Kind)).Inc()
+	}
+	err := s.dbClient.PersistRegistryAnomalies(anomalies)
+	if err != nil {
+		log.Errorf("error persisting registry anomalies: %s", err.Error())
+	}
+}
+
+func (s *ChainAnalyzer) getRegistryAnomalies() *prom_metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(RegistryAnomalies)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.pubkeyRegistry != nil, nil
+	}
+
+	indvMetr, err := prom_metrics.NewIndvMetrics(
+		"registry_anomalies",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init registry_anomalies"))
+		return nil
+	}
+	return indvMetr
+}
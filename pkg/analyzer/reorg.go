@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"fmt"
+	"time"
 
 	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
@@ -9,6 +10,7 @@ import (
 )
 
 func (s *ChainAnalyzer) AdvanceFinalized(newFinalizedSlot phase0.Slot) {
+	s.waitChainAgreement() // do not confirm a chain other nodes disagree with
 
 	finalizedEpoch := newFinalizedSlot / spec.SlotsPerEpoch
 
@@ -30,34 +32,41 @@ func (s *ChainAnalyzer) AdvanceFinalized(newFinalizedSlot phase0.Slot) {
 		if finalizedStateRoot != cacheStateRoot { // no match, reorg happened
 			log.Warnf("cache state root: %s\nfinalized block root: %s", cacheStateRoot, finalizedStateRoot)
 			log.Warnf("state root for state (slot=%d) incorrect, redownload", cacheState.Slot)
+			s.recordRootMismatch(spec.StateRootKind, phase0.Slot(cacheState.Slot), cacheStateRoot, finalizedStateRoot)
 
-			s.dbClient.DeleteStateMetrics(phase0.Epoch(epoch))
+			s.deleteStateMetrics(phase0.Epoch(epoch))
 			log.Infof("rewriting metrics for epoch %d", epoch)
 			// write epoch metrics
 			s.ProcessStateTransitionMetrics(phase0.Epoch(epoch))
 		}
 
+		if !s.metrics.Block {
+			continue // blocks derived from the state, already checked with the state root
+		}
 		// loop over slots in the epoch
 		for slot := (epoch * spec.SlotsPerEpoch); slot < ((epoch + 1) * spec.SlotsPerEpoch); slot++ {
+			s.checkFinalizedBlock(phase0.Slot(slot))
+		}
+	}
 
-			// Retrieve stored root and redownload root once finalized
-			cacheBlock := s.downloadCache.BlockHistory.Wait(slot)
-			finalizedBlockRoot := s.cli.RequestBlockRoot(phase0.Slot(cacheBlock.Slot))
-			cacheBlockRoot := cacheBlock.Root
-
-			if finalizedBlockRoot != cacheBlockRoot {
-				log.Warnf("cache block root: %s\nfinalized block root: %s", cacheBlockRoot, finalizedBlockRoot)
-				log.Warnf("block root for block (slot=%d) incorrect, redownload", cacheBlock.Slot)
-
-				s.dbClient.DeleteBlockMetrics(phase0.Slot(slot))
-				log.Infof("rewriting metrics for slot %d", slot)
-				// write slot metrics
-				s.ProcessBlock(phase0.Slot(slot))
+	if !s.metrics.Epoch { // no states in the cache, check the blocks directly
+		for _, slot := range s.downloadCache.BlockHistory.GetKeyList() {
+			if slot >= uint64(newFinalizedSlot) {
+				continue
 			}
+			advance = true
+			s.checkFinalizedBlock(phase0.Slot(slot))
 		}
 	}
 
 	s.downloadCache.CleanUpTo(newFinalizedSlot)
+	s.cli.CleanEpochDutiesUpTo(phase0.Epoch(newFinalizedSlot / spec.SlotsPerEpoch))
+	s.headArrivals.CleanUpTo(newFinalizedSlot)
+	s.slotRegistry.CleanUpTo(newFinalizedSlot)
+	s.epochCompleteness.CleanUpTo(newFinalizedSlot)
+	if s.voteArrivals != nil {
+		s.voteArrivals.CleanUpTo(newFinalizedSlot)
+	}
 
 	if advance {
 		log.Infof("checked states until slot %d, epoch %d", newFinalizedSlot, newFinalizedSlot/spec.SlotsPerEpoch)
@@ -65,7 +74,91 @@ func (s *ChainAnalyzer) AdvanceFinalized(newFinalizedSlot phase0.Slot) {
 	}
 }
 
+// checkFinalizedBlock compares the cached block root with the finalized one, rewriting the block if they differ
+func (s *ChainAnalyzer) checkFinalizedBlock(slot phase0.Slot) {
+	// Retrieve stored root and redownload root once finalized
+	cacheBlock := s.downloadCache.BlockHistory.Wait(SlotTo[uint64](slot))
+	finalizedBlockRoot, err := s.cli.RequestBlockRoot(phase0.Slot(cacheBlock.Slot))
+	if err != nil {
+		log.Errorf("could not check the finalized block at slot %d: %s", slot, err)
+		return
+	}
+	cacheBlockRoot := cacheBlock.Root
+
+	if finalizedBlockRoot != cacheBlockRoot {
+		log.Warnf("cache block root: %s\nfinalized block root: %s", cacheBlockRoot, finalizedBlockRoot)
+		log.Warnf("block root for block (slot=%d) incorrect, redownload", cacheBlock.Slot)
+		s.recordRootMismatch(spec.BlockRootKind, phase0.Slot(cacheBlock.Slot), cacheBlockRoot, finalizedBlockRoot)
+
+		s.deleteBlockMetrics(slot)
+		log.Infof("rewriting metrics for slot %d", slot)
+		// write slot metrics
+		s.ProcessBlock(slot)
+	}
+}
+
+// deleteBlockMetrics deletes the metrics of the slot to write them again, reopening it in the journal
+// so that it is not skipped as committed
+func (s *ChainAnalyzer) deleteBlockMetrics(slot phase0.Slot) {
+	if s.journal != nil {
+		s.journal.ReopenBlock(slot)
+	}
+	s.dbClient.DeleteBlockMetrics(slot)
+}
+
+// deleteStateMetrics deletes the metrics of the epoch to write them again, reopening it in the journal
+func (s *ChainAnalyzer) deleteStateMetrics(epoch phase0.Epoch) {
+	if s.journal != nil {
+		s.journal.ReopenEpoch(epoch)
+	}
+	s.dbClient.DeleteStateMetrics(epoch)
+}
+
+// recordRootMismatch persists a cached root that differs from the finalized one, together with
+// the justified checkpoint of the node, before the metrics are rewritten
+func (s *ChainAnalyzer) recordRootMismatch(kind spec.RootKind, slot phase0.Slot, cachedRoot phase0.Root, nodeRoot phase0.Root) {
+	mismatch := spec.RootMismatch{
+		Slot:       slot,
+		Kind:       kind,
+		CachedRoot: cachedRoot,
+		NodeRoot:   nodeRoot,
+		Timestamp:  time.Now().Unix(),
+	}
+	justified, err := s.cli.RequestJustifiedCheckpoint()
+	if err != nil {
+		log.Warnf("%s root mismatch at slot %d recorded without justified checkpoint: %s", kind, slot, err)
+	} else {
+		mismatch.JustifiedEpoch = justified.Epoch
+		mismatch.JustifiedRoot = justified.Root
+	}
+	s.dbClient.PersistRootMismatches([]spec.RootMismatch{mismatch})
+}
+
+// processReorgForkChoice persists the fork choice of the beacon node right after the reorg, with the weight
+// of the chains of the old and the new head. Nodes that do not serve the debug endpoint are only logged
+func (s *ChainAnalyzer) processReorgForkChoice(newReorg v1.ChainReorgEvent) {
+	forkChoice, err := s.cli.RequestForkChoice()
+	if err != nil {
+		log.Warnf("could not get the fork choice of the reorg at slot %d: %s", newReorg.Slot, err.Error())
+		return
+	}
+	nodes := spec.NewReorgForkChoice(newReorg, forkChoice)
+	if len(nodes) == 0 {
+		return
+	}
+	err = s.dbClient.PersistReorgForkChoice(nodes)
+	if err != nil {
+		log.Errorf("error persisting the fork choice of the reorg at slot %d: %s", newReorg.Slot, err.Error())
+	}
+}
+
 func (s *ChainAnalyzer) HandleReorg(newReorg v1.ChainReorgEvent) {
+	s.checkpointRoots.reset() // the checkpoints of the reorged epochs may have changed
+
+	if !s.metrics.Block { // no blocks downloaded, only the states can be rewritten
+		s.handleReorgStates(newReorg)
+		return
+	}
 	depth := newReorg.Depth
 	reorgSlot := newReorg.Slot
 
@@ -90,7 +183,7 @@ func (s *ChainAnalyzer) HandleReorg(newReorg v1.ChainReorgEvent) {
 			if block.Proposed { // keep orphans -> if previous block was proposed and roots have changed
 				s.dbClient.PersistOrphans([]spec.AgnosticBlock{oldBlock})
 			}
-			s.dbClient.DeleteBlockMetrics(i)
+			s.deleteBlockMetrics(i)
 			log.Infof("rewriting metrics for slot %d", i)
 			// write slot metrics
 			s.ProcessBlock(i)
@@ -99,22 +192,43 @@ func (s *ChainAnalyzer) HandleReorg(newReorg v1.ChainReorgEvent) {
 		}
 
 		if (i+1)%spec.SlotsPerEpoch == 0 { // then we are at the end of the epoch, rewrite state
-			epoch := phase0.Epoch(i / spec.SlotsPerEpoch)
-
-			state := s.downloadCache.StateHistory.Wait(EpochTo[uint64](epoch))           // first check that it was already in the cache
-			s.processerBook.WaitUntilInactive(fmt.Sprintf("%s%d", epochProcesserTag, i)) // wait until has been processed
-			oldState := *state
-			s.DownloadState(i) // -> inserts into the queue and replaces old block
-			newState := s.downloadCache.StateHistory.Wait(EpochTo[uint64](epoch))
-
-			if newState.StateRoot != oldState.StateRoot {
-				s.dbClient.DeleteStateMetrics(epoch)
-				log.Infof("rewriting metrics for epoch %d", epoch)
-				// write epoch metrics
-				s.ProcessStateTransitionMetrics(epoch)
-			}
+			s.rewriteReorgedState(i)
 		}
 		i -= 1
 	}
 
 }
+
+// handleReorgStates downloads again the cached states of the epochs the reorg went through
+func (s *ChainAnalyzer) handleReorgStates(newReorg v1.ChainReorgEvent) {
+	firstSlot := slotsBefore(newReorg.Slot, phase0.Slot(newReorg.Depth))
+
+	for epoch := firstSlot / spec.SlotsPerEpoch; epoch <= newReorg.Slot/spec.SlotsPerEpoch; epoch++ {
+		if !s.downloadCache.StateHistory.Available(SlotTo[uint64](epoch)) {
+			continue // not downloaded yet, it will come from the new chain
+		}
+		s.rewriteReorgedState(s.stateSlot(phase0.Epoch(epoch)))
+	}
+}
+
+// rewriteReorgedState downloads the state at the given (last of the epoch) slot again,
+// rewriting the epoch metrics if the state changed
+func (s *ChainAnalyzer) rewriteReorgedState(slot phase0.Slot) {
+	epoch := phase0.Epoch(slot / spec.SlotsPerEpoch)
+
+	state := s.downloadCache.StateHistory.Wait(EpochTo[uint64](epoch))              // first check that it was already in the cache
+	s.processerBook.WaitUntilInactive(fmt.Sprintf("%s%d", epochProcesserTag, slot)) // wait until has been processed
+	oldState := *state
+	// duties might have changed with the reorg, also the ones prefetched for the next epoch
+	s.cli.InvalidateEpochDuties(epoch)
+	s.cli.InvalidateEpochDuties(epoch + 1)
+	s.DownloadState(slot) // -> inserts into the queue and replaces old block
+	newState := s.downloadCache.StateHistory.Wait(EpochTo[uint64](epoch))
+
+	if newState.StateRoot != oldState.StateRoot {
+		s.deleteStateMetrics(epoch)
+		log.Infof("rewriting metrics for epoch %d", epoch)
+		// write epoch metrics
+		s.ProcessStateTransitionMetrics(epoch)
+	}
+}
@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	reprocessEndpoint  = "/reprocess"
+	reprocessQueueSize = 16 // epochs waiting to be reprocessed
+)
+
+type reprocessResponse struct {
+	Epoch  phase0.Epoch `json:"epoch"`
+	Status string       `json:"status"`
+}
+
+// reprocessHandler queues the epoch given in the query (POST /reprocess?epoch=N) to be downloaded and processed again.
+// Requests must carry the admin token as a bearer token
+func (s *ChainAnalyzer) reprocessHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := []byte("Bearer " + s.adminToken)
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), token) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !s.metrics.Epoch {
+		http.Error(w, "epoch metrics are not enabled", http.StatusConflict)
+		return
+	}
+
+	epoch, err := strconv.ParseUint(r.URL.Query().Get("epoch"), 10, 64)
+	if err != nil || epoch == 0 {
+		http.Error(w, "epoch must be a number greater than 0", http.StatusBadRequest)
+		return
+	}
+	if s.downloadCache.StateHistory.Available(epoch) {
+		http.Error(w, fmt.Sprintf("epoch %d is being processed, it is checked again once finalized", epoch), http.StatusConflict)
+		return
+	}
+
+	select {
+	case s.reprocessChan <- phase0.Epoch(epoch):
+	default:
+		http.Error(w, "too many epochs waiting to be reprocessed", http.StatusServiceUnavailable)
+		return
+	}
+	log.Infof("epoch %d queued to be reprocessed", epoch)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(reprocessResponse{Epoch: phase0.Epoch(epoch), Status: "queued"}); err != nil {
+		log.Errorf("could not encode reprocess response: %s", err)
+	}
+}
+
+// runReprocessing processes the queued epochs one by one
+func (s *ChainAnalyzer) runReprocessing() {
+	for {
+		select {
+		case epoch := <-s.reprocessChan:
+			if err := s.reprocessEpoch(epoch); err != nil {
+				log.Errorf("could not reprocess epoch %d: %s", epoch, err)
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// reprocessEpoch downloads again the states needed for the transition to the given epoch,
+// outside of the cache, and persists its metrics. Rows are replaced by the new ones
+func (s *ChainAnalyzer) reprocessEpoch(epoch phase0.Epoch) error {
+	log.Infof("reprocessing epoch %d", epoch)
+
+	currentState, err := s.downloadEpochState(epoch-1, nil)
+	if err != nil {
+		return err
+	}
+	nextState, err := s.downloadEpochState(epoch, currentState)
+	if err != nil {
+		return err
+	}
+
+	var prevState *spec.AgnosticState
+	if epoch == 1 { // there is no epoch before genesis, the genesis state acts as previous one
+		prevState = currentState.GenesisPrevState()
+	} else {
+		prevState, err = s.downloadEpochState(epoch-2, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.processStateTransition(prevState, currentState, nextState, false)
+	if err != nil {
+		return err
+	}
+	log.Infof("epoch %d reprocessed", epoch)
+	return nil
+}
+
+// downloadEpochState downloads the state at the last slot of the epoch together with its blocks.
+// Without blocks, these are derived from the state, estimating withdrawals if the previous state is given
+func (s *ChainAnalyzer) downloadEpochState(epoch phase0.Epoch, prevState *spec.AgnosticState) (*spec.AgnosticState, error) {
+	lastSlot := phase0.Slot((epoch+1)*spec.SlotsPerEpoch - 1)
+
+	state, err := s.cli.RequestBeaconState(s.stateSlot(epoch))
+	if err != nil {
+		return nil, fmt.Errorf("could not download state at slot %d: %s", s.stateSlot(epoch), err)
+	}
+
+	var blocks []*spec.AgnosticBlock
+	if s.metrics.Block {
+		blocks = make([]*spec.AgnosticBlock, 0, spec.SlotsPerEpoch)
+		for slot := lastSlot + 1 - spec.SlotsPerEpoch; slot <= lastSlot; slot++ {
+			block, err := s.cli.RequestBeaconBlock(slot)
+			if err != nil {
+				return nil, fmt.Errorf("could not download block at slot %d: %s", slot, err)
+			}
+			blocks = append(blocks, block)
+		}
+	} else {
+		blocks = state.BlocksFromState()
+		if prevState != nil {
+			blocks[len(blocks)-1].ExecutionPayload.Withdrawals = state.EstimateWithdrawals(prevState)
+		}
+	}
+	state.AddBlocks(blocks)
+	return state, nil
+}
@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	rewardsSummaryEndpoint = "GET /validators/{idx}/rewards/summary"
+)
+
+type rewardsSummaryResponse struct {
+	Validator            phase0.ValidatorIndex `json:"validator"`
+	From                 phase0.Epoch          `json:"from"`
+	To                   phase0.Epoch          `json:"to"`
+	Epochs               uint64                `json:"epochs"` // epochs of the range with rewards
+	Reward               int64                 `json:"reward"` // gwei
+	MaxReward            uint64                `json:"max_reward"`
+	MaxAttReward         uint64                `json:"max_att_reward"`
+	MaxSyncReward        uint64                `json:"max_sync_reward"`
+	BlockApiReward       uint64                `json:"block_api_reward"`
+	Efficiency           float64               `json:"efficiency"` // reward over max reward
+	AttestationsIncluded uint64                `json:"attestations_included"`
+	MissingSource        uint64                `json:"missing_source"`
+	MissingTarget        uint64                `json:"missing_target"`
+	MissingHead          uint64                `json:"missing_head"`
+	SyncCommitteeEpochs  uint64                `json:"sync_committee_epochs"`
+}
+
+// parseEpochParam returns the epoch of the query parameter, or the default if it is not given
+func parseEpochParam(r *http.Request, name string, defaultEpoch phase0.Epoch) (phase0.Epoch, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultEpoch, nil
+	}
+	epoch, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an epoch number", name)
+	}
+	return phase0.Epoch(epoch), nil
+}
+
+// rewardsSummaryHandler returns the rewards of a validator aggregated in the database over the epochs
+// of the query (GET /validators/{idx}/rewards/summary?from=&to=), all of them by default
+func (s *ChainAnalyzer) rewardsSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	valIdx, err := strconv.ParseUint(r.PathValue("idx"), 10, 64)
+	if err != nil {
+		http.Error(w, "validator index must be a number", http.StatusBadRequest)
+		return
+	}
+	from, err := parseEpochParam(r, "from", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseEpochParam(r, "to", math.MaxInt64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if from > to {
+		http.Error(w, "from must not be after to", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := s.dbClient.RetrieveValidatorRewardsRange(phase0.ValidatorIndex(valIdx), from, to)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not read the rewards: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := rewardsSummaryResponse{
+		Validator:            phase0.ValidatorIndex(valIdx),
+		From:                 from,
+		To:                   to,
+		Epochs:               summary.Epochs,
+		Reward:               summary.Reward,
+		MaxReward:            summary.MaxReward,
+		MaxAttReward:         summary.MaxAttReward,
+		MaxSyncReward:        summary.MaxSyncReward,
+		BlockApiReward:       summary.BlockApiReward,
+		Efficiency:           summary.Efficiency(),
+		AttestationsIncluded: summary.AttestationsIncluded,
+		MissingSource:        summary.MissingSource,
+		MissingTarget:        summary.MissingTarget,
+		MissingHead:          summary.MissingHead,
+		SyncCommitteeEpochs:  summary.SyncCommitteeEpochs,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("could not encode rewards summary: %s", err)
+	}
+}
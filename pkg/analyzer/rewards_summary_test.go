@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewardsSummaryHandlerParams(t *testing.T) {
+	s := &ChainAnalyzer{}
+	for _, test := range []struct {
+		idx   string
+		query string
+	}{
+		{idx: "abc"},
+		{idx: "1", query: "from=x"},
+		{idx: "1", query: "to=-1"},
+		{idx: "1", query: "from=10&to=9"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/validators/"+test.idx+"/rewards/summary?"+test.query, nil)
+		r.SetPathValue("idx", test.idx)
+		w := httptest.NewRecorder()
+		s.rewardsSummaryHandler(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code, test)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/validators/1/rewards/summary?from=5", nil)
+	from, err := parseEpochParam(r, "from", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, phase0.Epoch(5), from)
+	to, err := parseEpochParam(r, "to", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, phase0.Epoch(100), to)
+}
@@ -0,0 +1,31 @@
+package analyzer
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+)
+
+// maintainRewardsWindows refreshes the rollup bucket of the epoch whose validator rewards were just persisted,
+// and prunes the buckets and rewards out of the windows once per bucket
+func (s *ChainAnalyzer) maintainRewardsWindows(epoch phase0.Epoch) {
+	if s.rewardsWindows {
+		err := s.dbClient.RefreshValidatorRewardsBucket(epoch)
+		if err != nil {
+			log.Errorf("error refreshing validator rewards windows at epoch %d: %s", epoch, err.Error())
+		}
+	}
+
+	if epoch%db.RewardsBucketEpochs != 0 {
+		return // deletes are expensive, only prune when a new bucket starts
+	}
+
+	bucket := db.RewardsBucket(epoch)
+	if s.rewardsWindows && bucket > db.RewardsWindowBuckets {
+		log.Infof("deleting validator rewards buckets from %d backwards", bucket-db.RewardsWindowBuckets)
+		s.dbClient.DeleteValidatorRewardsBucketsUntil(bucket - db.RewardsWindowBuckets)
+	}
+	if s.rewardsRetentionEpochs > 0 && epoch > s.rewardsRetentionEpochs {
+		log.Infof("deleting validator rewards from %d epoch backwards", epoch-s.rewardsRetentionEpochs)
+		s.dbClient.DeleteValidatorRewardsUntil(epoch - s.rewardsRetentionEpochs)
+	}
+}
@@ -26,9 +26,22 @@ downloadRoutine:
 
 		case downloadSlot := <-s.downloadTaskChan: // wait for new head event
 			log.Tracef("received new download signal: %d", downloadSlot)
+			claimed, pending := s.slotRegistry.Claim(downloadSlot)
+			if !claimed {
+				if pending {
+					log.Debugf("slot %d still being processed by another routine, requeued", downloadSlot)
+					go s.requeueSlot(downloadSlot)
+				} else {
+					log.Debugf("slot %d already processed by another routine, skipping", downloadSlot)
+				}
+				continue
+			}
 
 			go s.DownloadBlockCotrolled(phase0.Slot(downloadSlot))
-			go s.ProcessBlock(downloadSlot)
+			go func(slot phase0.Slot) {
+				s.ProcessBlock(slot)
+				s.slotRegistry.Done(slot)
+			}(downloadSlot)
 
 			// if epoch boundary, download state
 			if (downloadSlot % spec.SlotsPerEpoch) == (spec.SlotsPerEpoch - 1) { // last slot of epoch
@@ -45,13 +58,32 @@ downloadRoutine:
 	log.Infof("Block Download routine finished")
 }
 
+// requeueSlot sends the slot to the download routine again after a while, until the routine that took it
+// finishes processing it
+func (s *ChainAnalyzer) requeueSlot(slot phase0.Slot) {
+	select {
+	case <-time.After(utils.RoutineFlushTimeout):
+	case <-s.ctx.Done():
+		return
+	}
+	if s.stop {
+		return
+	}
+	select {
+	case s.downloadTaskChan <- slot:
+	case <-s.ctx.Done():
+	}
+}
+
 func (s *ChainAnalyzer) runHead() {
 	defer s.wgMainRoutine.Done()
 	log.Info("launching head routine")
 	nextSlotDownload := s.fillToHead()
 
-	s.downloadCache.BlockHistory.Wait(SlotTo[uint64](nextSlotDownload))
-	// do not continue until fill is done
+	if s.metrics.Block { // without blocks the state of the head epoch is not there yet
+		s.downloadCache.BlockHistory.Wait(SlotTo[uint64](nextSlotDownload))
+		// do not continue until fill is done
+	}
 
 	log.Infof("Switch to head mode: following chain head")
 
@@ -62,6 +94,11 @@ func (s *ChainAnalyzer) runHead() {
 	s.eventsObj.SubscribeToFinalizedCheckpointEvents()
 	s.eventsObj.SubscribeToReorgsEvents()
 	s.eventsObj.SubscribeToBlobSidecarsEvents()
+	s.eventsObj.SubscribeToVoluntaryExitEvents()
+	if s.voteArrivals != nil {
+		s.eventsObj.SubscribeToAttestationEvents()
+	}
+	go s.eventsObj.RunWatchdog()
 	ticker := time.NewTicker(utils.RoutineFlushTimeout)
 	// loop over the list of slots that we need to analyze
 
@@ -72,6 +109,12 @@ func (s *ChainAnalyzer) runHead() {
 			// make the block query
 			log.Tracef("received new head signal: %d", event.HeadEvent.Slot)
 			s.dbClient.PersistHeadEvents([]db.HeadEvent{event})
+			s.headArrivals.Record(event.HeadEvent.Slot, event.ArrivalTimestamp)
+			// duties cached under another dependent root are stale, i.e. prefetched before a reorg
+			s.cli.CheckDependentRoots(phase0.Epoch(event.HeadEvent.Slot/spec.SlotsPerEpoch),
+				event.HeadEvent.CurrentDutyDependentRoot, event.HeadEvent.PreviousDutyDependentRoot)
+			// the new head goes before any pending backfill request
+			s.cli.PrioritizeFrom(event.HeadEvent.Slot)
 			for nextSlotDownload <= event.HeadEvent.Slot {
 
 				if s.processerBook.NumFreePages() > 0 {
@@ -84,24 +127,52 @@ func (s *ChainAnalyzer) runHead() {
 			s.dbClient.PersistFinalized([]v1.FinalizedCheckpointEvent{newFinalCheckpoint})
 			finalizedSlot := phase0.Slot(newFinalCheckpoint.Epoch * spec.SlotsPerEpoch)
 
-			go s.AdvanceFinalized(finalizedSlot - (2 * spec.SlotsPerEpoch))
+			go s.AdvanceFinalized(slotsBefore(finalizedSlot, 2*spec.SlotsPerEpoch))
 
 		case newReorg := <-s.eventsObj.ReorgChan:
 			s.dbClient.PersistReorgs([]v1.ChainReorgEvent{newReorg})
+			go s.processReorgForkChoice(newReorg)
 			go s.HandleReorg(newReorg)
 
+		case <-s.eventsObj.ReconnectChan:
+			// events might have been lost during the outage, reconcile with the current head
+			headSlot := s.cli.RequestCurrentHead()
+			if nextSlotDownload <= headSlot {
+				log.Infof("event stream reconnected, backfilling slots %d-%d", nextSlotDownload, headSlot)
+			}
+			for nextSlotDownload <= headSlot {
+				if s.processerBook.NumFreePages() > 0 {
+					s.downloadTaskChan <- nextSlotDownload
+					nextSlotDownload = nextSlotDownload + 1
+				}
+			}
+
 		case newBlobSidecarEvent := <-s.eventsObj.BlobSidecarChan:
 			s.dbClient.PersistBlobSidecarsEvents([]spec.BlobSideCarEventWraper{newBlobSidecarEvent})
 
+		case newVoluntaryExitEvent := <-s.eventsObj.VoluntaryExitChan:
+			s.exitEvents.Add(newVoluntaryExitEvent, time.Now())
+			if s.exitEvents.Due(time.Now()) {
+				s.flushExitEvents(false)
+			}
+
+		case newAttestation := <-s.eventsObj.AttestationChan:
+			s.voteArrivals.Record(newAttestation)
+
 		case <-s.ctx.Done():
 			log.Info("context has died, closing block requester routine")
+			s.flushExitEvents(true)
 			return
 
 		case <-ticker.C:
 			if s.stop {
 				log.Info("sudden shutdown detected, block downloader routine")
+				s.flushExitEvents(true)
 				return
 			}
+			if s.exitEvents.Due(time.Now()) {
+				s.flushExitEvents(false)
+			}
 		}
 
 	}
@@ -122,6 +193,11 @@ func (s *ChainAnalyzer) fillToHead() phase0.Slot {
 
 	// obtain last slot in database
 	dbHead, err := s.dbClient.RetrieveLastSlot()
+	if !s.metrics.Block { // no blocks in the database, continue from the last epoch
+		var dbEpoch phase0.Epoch
+		dbEpoch, err = s.dbClient.RetrieveLastEpoch()
+		dbHead = phase0.Slot(dbEpoch * spec.SlotsPerEpoch)
+	}
 	if err != nil {
 		log.Fatalf("could not get head block from database: %s", err)
 	}
@@ -132,19 +208,33 @@ func (s *ChainAnalyzer) fillToHead() phase0.Slot {
 	}
 	// if we did not get a last slot from the database, or we were too close to the head
 	// then start from two epochs before current finalized in the chain
-	if nextSlotDownload == 0 || nextSlotDownload > finalizedBlock.Slot {
+	seedSlot := phase0.Slot(0) // first slot in the cache when resuming from a warm restart
+	seeded := false
+	if s.warmRestart && nextSlotDownload > finalizedBlock.Slot {
+		// unless the heads persisted after finality are still canonical
+		nextSlotDownload, seedSlot = s.warmRestartSlot(dbHead, finalizedBlock.Slot)
+		seeded = true
+
+	} else if nextSlotDownload == 0 || nextSlotDownload > finalizedBlock.Slot {
 		log.Infof("continue from finalized slot %d, epoch %d", finalizedBlock.Slot, finalizedBlock.Slot/spec.SlotsPerEpoch)
-		nextSlotDownload = finalizedBlock.Slot - (epochsToFinalizedTentative * spec.SlotsPerEpoch) // 2 epochs before
+		nextSlotDownload = slotsBefore(finalizedBlock.Slot, epochsToFinalizedTentative*spec.SlotsPerEpoch) // 2 epochs before
 
 	} else {
 		// database detected
 		log.Infof("database detected, continue from slot %d, epoch %d", nextSlotDownload, nextSlotDownload/spec.SlotsPerEpoch)
-		nextSlotDownload = nextSlotDownload - (epochsToFinalizedTentative * spec.SlotsPerEpoch) // 2 epochs before
+		nextSlotDownload = slotsBefore(nextSlotDownload, epochsToFinalizedTentative*spec.SlotsPerEpoch) // 2 epochs before
 	}
 	nextSlotDownload = nextSlotDownload / spec.SlotsPerEpoch * spec.SlotsPerEpoch
+	if resume, ok := s.journalResumeSlot(); ok && resume <= headSlot {
+		nextSlotDownload = resume
+	}
+	nextSlotDownload = s.availableInitSlot(nextSlotDownload, headSlot)
 	s.initSlot = nextSlotDownload / spec.SlotsPerEpoch * spec.SlotsPerEpoch
 	s.startEpochAggregation = phase0.Epoch(spec.EpochAtSlot(s.initSlot) + 2)
 	s.endEpochAggregation = s.startEpochAggregation + phase0.Epoch(s.rewardsAggregationEpochs-1)
+	if seeded {
+		s.initSlot = min(s.initSlot, seedSlot) // the seeded states are there for the transitions
+	}
 
 	log.Infof("filling to head...")
 	s.wgMainRoutine.Add(1) // add because historical will defer it
@@ -156,6 +246,9 @@ func (s *ChainAnalyzer) runHistorical(init phase0.Slot, end phase0.Slot) {
 	defer s.wgMainRoutine.Done()
 
 	log.Infof("Switch to historical mode: %d - %d", init, end)
+	if s.journal != nil {
+		s.journal.Begin(init)
+	}
 
 	i := init
 	for i <= end {
@@ -163,7 +256,13 @@ func (s *ChainAnalyzer) runHistorical(init phase0.Slot, end phase0.Slot) {
 			log.Info("sudden shutdown detected, block downloader routine")
 			return
 		}
-		if s.processerBook.NumFreePages() == 0 {
+		if s.nodeThrottle.BackfillPaused() {
+			log.Debugf("backfill paused until the beacon node is healthy")
+			pauseTicker := time.NewTicker(utils.RoutineFlushTimeout)
+			<-pauseTicker.C
+			continue
+		}
+		if s.processerBook.NumFreePages() == 0 || s.nodeThrottle.Limited(s.processerBook.ActivePages()) {
 			log.Debugf("hit limit of concurrent processers")
 			limitTicker := time.NewTicker(utils.RoutineFlushTimeout)
 			<-limitTicker.C // if rate limit, wait for ticker
@@ -178,12 +277,26 @@ func (s *ChainAnalyzer) runHistorical(init phase0.Slot, end phase0.Slot) {
 
 			if i >= finalizedSlot.Slot {
 				// keep 2 epochs before finalized, needed to calculate epoch metrics
-				s.AdvanceFinalized(finalizedSlot.Slot - spec.SlotsPerEpoch*5) // includes check and clean
-			} else if i > (5 * spec.SlotsPerEpoch) {
-				// keep 5 epochs before current downloading slot, need 3 at least for epoch metrics
-				// magic number, 2 extra if processer takes long
-				cleanUpToSlot := i - (5 * spec.SlotsPerEpoch)
+				s.AdvanceFinalized(slotsBefore(finalizedSlot.Slot, spec.SlotsPerEpoch*5)) // includes check and clean
+			} else if cleanUpToSlot, ok := historicalCleanUpSlot(i, s.prefetch.Epochs(), s.processerBook.GetKeys()); ok {
+				// keep the prefetch window and 3 more epochs before current downloading slot, need 3 at least for epoch metrics
+				// magic number, 2 extra if processer takes long (5 epochs with the default window)
+				// the states of the epochs still being processed are kept as well
 				s.downloadCache.CleanUpTo(cleanUpToSlot) // only clean, no check, keep
+				s.slotRegistry.CleanUpTo(cleanUpToSlot)
+				s.epochCompleteness.CleanUpTo(cleanUpToSlot)
+				s.cli.CleanEpochDutiesUpTo(phase0.Epoch(cleanUpToSlot / spec.SlotsPerEpoch))
+			}
+
+			// prefetch the proposer duties of the next epochs in the range, missing blocks
+			// and epoch duties will be served from the cache
+			epoch := phase0.Epoch(i / spec.SlotsPerEpoch)
+			if epoch%dutiesPrefetchEpochs == 0 {
+				lastPrefetchEpoch := epoch + dutiesPrefetchEpochs - 1
+				if endEpoch := phase0.Epoch(end / spec.SlotsPerEpoch); lastPrefetchEpoch > endEpoch {
+					lastPrefetchEpoch = endEpoch
+				}
+				go s.cli.PrefetchProposerDuties(epoch, lastPrefetchEpoch)
 			}
 		}
 
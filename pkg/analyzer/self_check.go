@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"strings"
+
+	eth2spec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	prom_metrics "github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	selfCheckSampleSize = 64 // validators whose balance is checked every epoch
+
+	SelfCheckDiscrepancies = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "self_check_discrepancies",
+		Help:      "The number of values of the processed states that did not match the ones reported by the beacon node",
+	}, []string{"kind"})
+)
+
+// processSelfCheck compares the finality checkpoints, and the balances of a sample of validators, stored for the
+// epoch of the next state with the ones the beacon node reports for the same slot. The rewards of an epoch store the
+// balances of its state, or of the previous one before Altair. The check runs in the background
+func (s *ChainAnalyzer) processSelfCheck(bundle metrics.StateMetrics) {
+	if !s.selfCheck {
+		return
+	}
+	base := bundle.GetMetricsBase()
+	epoch, slot := base.NextState.Epoch, base.NextState.Slot
+	balancesSlot := slot
+	if base.NextState.Version == eth2spec.DataVersionPhase0 {
+		balancesSlot = base.CurrentState.Slot
+	}
+	sample := spec.SelfCheckSample(epoch, base.NextState.NumBalances(), selfCheckSampleSize)
+
+	go func() {
+		stored, ok, err := s.dbClient.RetrieveEpochCheckpoints(epoch)
+		if err != nil || !ok {
+			log.Errorf("could not self-check epoch %d: no checkpoints stored (%v)", epoch, err)
+			return
+		}
+		storedBalances, err := s.dbClient.RetrieveValidatorBalances(epoch, sample)
+		if err != nil {
+			log.Errorf("could not self-check epoch %d: %s", epoch, err)
+			return
+		}
+		finality, err := s.cli.RequestFinalityCheckpoints(slot)
+		if err != nil {
+			log.Errorf("could not self-check epoch %d: %s", epoch, err)
+			return
+		}
+		nodeBalances := make(map[phase0.ValidatorIndex]phase0.Gwei)
+		if len(storedBalances) > 0 { // nothing to check without the validator rewards stored
+			nodeBalances, err = s.cli.RequestValidatorBalances(balancesSlot, sample)
+			if err != nil {
+				log.Errorf("could not self-check epoch %d: %s", epoch, err)
+				return
+			}
+		}
+
+		discrepancies := spec.CompareWithNode(
+			stored,
+			*finality.Finalized,
+			*finality.Justified,
+			*finality.PreviousJustified,
+			storedBalances,
+			nodeBalances)
+		if len(discrepancies) == 0 {
+			log.Debugf("epoch %d matches the beacon node", epoch)
+			return
+		}
+		for _, discrepancy := range discrepancies {
+			log.Warnf("self-check %s mismatch at epoch %d (validator %d): %s stored, %s reported by the beacon node",
+				discrepancy.Kind, discrepancy.Epoch, discrepancy.ValidatorIndex, discrepancy.Computed, discrepancy.Node)
+			SelfCheckDiscrepancies.WithLabelValues(string(discrepancy.Kind)).Inc()
+		}
+		err = s.dbClient.PersistSelfCheckDiscrepancies(discrepancies)
+		if err != nil {
+			log.Errorf("error persisting self-check discrepancies: %s", err.Error())
+		}
+	}()
+}
+
+func (s *ChainAnalyzer) getSelfCheckDiscrepancies() *prom_metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(SelfCheckDiscrepancies)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.selfCheck, nil
+	}
+
+	indvMetr, err := prom_metrics.NewIndvMetrics(
+		"self_check_discrepancies",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init self_check_discrepancies"))
+		return nil
+	}
+	return indvMetr
+}
@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	prom_metrics "github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ProtectionAnomaliesDetected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "slashing_protection_anomalies",
+		Help:      "The number of blocks and votes included on chain that are not in the slashing protection history of their validator",
+	}, []string{"type"})
+)
+
+// slashingProtection audits the blocks and votes of the validators in an EIP-3076 file against their signing history.
+// The public keys are resolved to validator indexes as they appear in the registry
+type slashingProtection struct {
+	sync.Mutex
+	interchange spec.SlashingProtectionInterchange
+	checked     bool // whether the file was checked against the genesis validators root of the chain
+	disabled    bool // the file belongs to another chain
+
+	pending           map[phase0.BLSPubKey]*spec.ProtectionHistory // not in the registry yet
+	histories         map[phase0.ValidatorIndex]*spec.ProtectionHistory
+	indexedValidators int // validators of the registry already matched with the pending public keys
+}
+
+func newSlashingProtection(interchange spec.SlashingProtectionInterchange) *slashingProtection {
+	return &slashingProtection{
+		interchange: interchange,
+		pending:     make(map[phase0.BLSPubKey]*spec.ProtectionHistory),
+		histories:   make(map[phase0.ValidatorIndex]*spec.ProtectionHistory),
+	}
+}
+
+// resolve matches the public keys of the file with the validators of the registry, only keeping the monitored ones
+// if any. It returns false if the audit is disabled
+func (p *slashingProtection) resolve(state *spec.AgnosticState, tracker *voteTracker) bool {
+	if !p.checked {
+		p.checked = true
+		histories, err := p.interchange.Histories(state.GenesisValidatorsRoot)
+		if err != nil {
+			log.Errorf("slashing protection audit disabled: %s", err.Error())
+			p.disabled = true
+		}
+		for _, history := range histories {
+			p.pending[history.Pubkey] = history
+		}
+	}
+	if p.disabled {
+		return false
+	}
+	for ; p.indexedValidators < len(state.Validators) && len(p.pending) > 0; p.indexedValidators++ {
+		pubkey := state.Validators[p.indexedValidators].PublicKey
+		history, ok := p.pending[pubkey]
+		if !ok {
+			continue
+		}
+		delete(p.pending, pubkey)
+		valIdx := phase0.ValidatorIndex(p.indexedValidators)
+		if tracker.Monitoring() && !tracker.Monitored(valIdx) {
+			continue
+		}
+		p.histories[valIdx] = history
+	}
+	return true
+}
+
+// processSlashingProtection checks the blocks proposed and the votes included in the epoch blocks against the
+// slashing protection history of their validators. Anything covered by the history but not in it was signed
+// out of the protection of the validator client, i.e. by another instance with the same keys
+func (s *ChainAnalyzer) processSlashingProtection(bundle metrics.StateMetrics) {
+	if s.slashingProtection == nil || !s.metrics.Block {
+		return
+	}
+	base := bundle.GetMetricsBase()
+
+	s.slashingProtection.Lock()
+	defer s.slashingProtection.Unlock()
+	if !s.slashingProtection.resolve(base.NextState, s.voteTracker) {
+		return
+	}
+
+	anomalies := make([]spec.ProtectionAnomaly, 0)
+	for _, block := range base.NextState.Blocks {
+		if !block.Proposed {
+			continue
+		}
+		if history, ok := s.slashingProtection.histories[block.ProposerIndex]; ok && !history.CheckBlock(block.Slot) {
+			anomalies = append(anomalies, spec.ProtectionAnomaly{
+				ValidatorIndex: block.ProposerIndex,
+				Pubkey:         history.Pubkey,
+				Kind:           spec.BlockNotInHistory,
+				Slot:           block.Slot,
+			})
+		}
+		anomalies = append(anomalies, s.checkProtectedVotes(block, base.CurrentState, base.NextState)...)
+	}
+	if len(anomalies) == 0 {
+		return
+	}
+
+	for _, anomaly := range anomalies {
+		log.Warnf("%s: validator %d at slot %d (votes %d->%d)", anomaly.Kind, anomaly.ValidatorIndex, anomaly.Slot,
+			anomaly.SourceEpoch, anomaly.TargetEpoch)
+		ProtectionAnomaliesDetected.WithLabelValues(string(anomaly.Kind)).Inc()
+	}
+	err := s.dbClient.PersistProtectionAnomalies(anomalies)
+	if err != nil {
+		log.Errorf("error persisting slashing protection anomalies: %s", err.Error())
+	}
+}
+
+// checkProtectedVotes checks the votes of the validators with a protection history in the block attestations
+func (s *ChainAnalyzer) checkProtectedVotes(block *spec.AgnosticBlock, currentState *spec.AgnosticState, nextState *spec.AgnosticState) []spec.ProtectionAnomaly {
+	anomalies := make([]spec.ProtectionAnomaly, 0)
+	histories := s.slashingProtection.histories
+
+	for _, attestation := range block.Attestations {
+		duties := nextState.EpochStructs
+		if spec.EpochAtSlot(attestation.Data.Slot) != nextState.Epoch {
+			duties = currentState.EpochStructs
+		}
+		source, target := attestation.Data.Source.Epoch, attestation.Data.Target.Epoch
+		for i, valIdx := range duties.GetValList(attestation.Data.Slot, attestation.Data.Index) {
+			history, ok := histories[valIdx]
+			if !ok || !attestation.AggregationBits.BitAt(uint64(i)) || history.CheckAttestation(source, target) {
+				continue
+			}
+			anomalies = append(anomalies, spec.ProtectionAnomaly{
+				ValidatorIndex: valIdx,
+				Pubkey:         history.Pubkey,
+				Kind:           spec.AttestationNotInHistory,
+				Slot:           block.Slot,
+				SourceEpoch:    source,
+				TargetEpoch:    target,
+			})
+		}
+	}
+	return anomalies
+}
+
+func (s *ChainAnalyzer) getSlashingProtection() *prom_metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(ProtectionAnomaliesDetected)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		if s.slashingProtection == nil {
+			return 0, nil
+		}
+		s.slashingProtection.Lock()
+		defer s.slashingProtection.Unlock()
+		return len(s.slashingProtection.histories), nil
+	}
+
+	indvMetr, err := prom_metrics.NewIndvMetrics(
+		"slashing_protection",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init slashing_protection"))
+		return nil
+	}
+	return indvMetr
+}
@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// slotRegistry keeps the slots already taken by the download routine, so that a slot sent by several routines
+// (the historical fill and the head following overlap in finalized mode) is downloaded and persisted only once.
+// Reorgs and reprocessing download the slots again on purpose, outside of the registry
+type slotRegistry struct {
+	sync.Mutex
+	claimed map[phase0.Slot]bool // whether the block of the slot was processed already
+	floor   phase0.Slot          // slots below were cleaned up, they are never taken again
+}
+
+func newSlotRegistry() *slotRegistry {
+	return &slotRegistry{
+		claimed: make(map[phase0.Slot]bool),
+	}
+}
+
+// Claim registers the slot, returns false if it was already taken by another routine, and whether that
+// routine is still processing it, so the slot is requeued until it is processed instead of dropped
+func (r *slotRegistry) Claim(slot phase0.Slot) (bool, bool) {
+	r.Lock()
+	defer r.Unlock()
+	if slot < r.floor {
+		return false, false
+	}
+	if processed, ok := r.claimed[slot]; ok {
+		return false, !processed
+	}
+	r.claimed[slot] = false
+	return true, false
+}
+
+// Done marks the block of the claimed slot as processed, it is not requeued anymore
+func (r *slotRegistry) Done(slot phase0.Slot) {
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.claimed[slot]; ok {
+		r.claimed[slot] = true
+	}
+}
+
+// CleanUpTo forgets the slots below the given one, which are not claimable anymore
+func (r *slotRegistry) CleanUpTo(maxSlot phase0.Slot) {
+	r.Lock()
+	defer r.Unlock()
+	if maxSlot <= r.floor {
+		return
+	}
+	r.floor = maxSlot
+	for slot := range r.claimed {
+		if slot < maxSlot {
+			delete(r.claimed, slot)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlotRegistryClaim(t *testing.T) {
+	registry := newSlotRegistry()
+
+	claimed, pending := registry.Claim(100)
+	assert.True(t, claimed)
+	assert.False(t, pending)
+
+	// taken by another routine, requeued while it is being processed
+	claimed, pending = registry.Claim(100)
+	assert.False(t, claimed)
+	assert.True(t, pending)
+
+	// dropped once processed
+	registry.Done(100)
+	claimed, pending = registry.Claim(100)
+	assert.False(t, claimed)
+	assert.False(t, pending)
+
+	// cleaned up slots are never taken again
+	registry.CleanUpTo(150)
+	claimed, pending = registry.Claim(120)
+	assert.False(t, claimed)
+	assert.False(t, pending)
+	claimed, _ = registry.Claim(150)
+	assert.True(t, claimed)
+}
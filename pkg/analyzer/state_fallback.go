@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	prom_metrics "github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	StateSubstitutions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "state_substitutions",
+		Help:      "The number of states that could not be decoded and were replaced by the state of a neighboring slot",
+	})
+)
+
+// downloadFallbackState requests the states of the neighboring slots of the same epoch when the state at the slot
+// could not be decoded, attaching the substitution to the state as a data quality note, persisted with the epoch
+// metrics. The missed blocks after an earlier state are taken from the downloaded blocks, so it needs the block metrics
+func (s *ChainAnalyzer) downloadFallbackState(slot phase0.Slot, decodeErr error) (*spec.AgnosticState, error) {
+	if s.stateFallbackSlots == 0 || !s.metrics.Block {
+		return nil, decodeErr
+	}
+	for _, candidate := range spec.FallbackSlots(slot, s.stateFallbackSlots) {
+		state, err := s.cli.RequestBeaconState(candidate)
+		if err != nil {
+			log.Warnf("fallback state at slot %d not available either: %s", candidate, err.Error())
+			continue
+		}
+
+		log.Warnf("state at slot %d could not be decoded, using the state at slot %d instead: %s", slot, candidate, decodeErr.Error())
+		StateSubstitutions.Inc()
+		s.stateSubstitutionsNum.Add(1)
+		state.Substitution = &spec.DataQualityNote{
+			Epoch:  state.Epoch,
+			Kind:   spec.StateSubstitutionNote,
+			Slot:   slot,
+			UsedAt: candidate,
+			Detail: decodeErr.Error(),
+		}
+		return state, nil
+	}
+	return nil, decodeErr
+}
+
+// processDataQualityNotes persists the substitution of the next state, rewritten with the rest of its metrics
+func (s *ChainAnalyzer) processDataQualityNotes(bundle metrics.StateMetrics) {
+	note := bundle.GetMetricsBase().NextState.Substitution
+	if note == nil {
+		return
+	}
+	err := s.dbClient.PersistDataQualityNotes([]spec.DataQualityNote{*note})
+	if err != nil {
+		log.Errorf("error persisting the state substitution of epoch %d: %s", note.Epoch, err.Error())
+	}
+}
+
+func (s *ChainAnalyzer) getStateSubstitutions() *prom_metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(StateSubstitutions)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.stateSubstitutionsNum.Load(), nil
+	}
+
+	indvMetr, err := prom_metrics.NewIndvMetrics(
+		"state_substitutions",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init state_substitutions"))
+		return nil
+	}
+	return indvMetr
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	StateProofFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "state_proof_failures",
+		Help:      "The number of downloaded states whose merkle proofs did not match the state root",
+	})
+)
+
+// processStateProof persists the merkle proof verification of the downloaded state, if enabled,
+// reporting the states that do not match their root
+func (s *ChainAnalyzer) processStateProof(state *spec.AgnosticState) {
+	if state == nil || state.Proof == nil {
+		return
+	}
+	proof := state.Proof
+	if !proof.Verified() {
+		StateProofFailures.Inc()
+		message := fmt.Sprintf("state at slot %d does not match its root %s: %d of %d validators failed",
+			proof.Slot, proof.StateRoot, len(proof.Failed), proof.Validators)
+		if proof.Error != "" {
+			message = fmt.Sprintf("state at slot %d could not be verified against its root %s: %s",
+				proof.Slot, proof.StateRoot, proof.Error)
+		}
+		log.Error(message)
+		err := s.notifier.Notify(notifier.Notification{
+			Rule:    "state_proof",
+			Kind:    notifier.StateProofKind,
+			Epoch:   proof.Epoch,
+			Message: message,
+		})
+		if err != nil {
+			log.Errorf("error sending state proof notification: %s", err.Error())
+		}
+	}
+
+	err := s.dbClient.PersistStateProofs([]spec.StateProof{*proof})
+	if err != nil {
+		log.Errorf("error persisting the state proof at slot %d: %s", proof.Slot, err.Error())
+	}
+}
+
+func (s *ChainAnalyzer) getStateProofs() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(StateProofFailures)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.stateProofs, nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"state_proofs",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init state_proofs"))
+		return nil
+	}
+	return indvMetr
+}
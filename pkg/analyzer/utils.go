@@ -1,10 +1,13 @@
 package analyzer
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
 	"github.com/migalabs/goteth/pkg/spec"
 	"github.com/sirupsen/logrus"
 )
@@ -16,6 +19,9 @@ const (
 	minStateReqTime            = 1 * time.Second        // max 1 query per second, dont spam beacon node
 	epochsToFinalizedTentative = 3                      // usually, 2 full epochs before the head it is finalized
 	dataWaitInterval           = 1 * time.Minute        // wait for block or epoch to be in the cache
+	dutiesPrefetchEpochs       = 4                      // number of epochs to prefetch proposer duties in historical mode
+	forcedShutdownTimeout      = 10 * time.Second       // wait for the routines once aborted, after the shutdown grace period
+	maxProcessers              = 32                     // epoch transitions processed at a time, one whole epoch
 )
 
 var (
@@ -34,6 +40,66 @@ func EpochTo[T uint64 | int64 | int](epoch phase0.Epoch) T {
 	return T(epoch)
 }
 
+// analyzer modes, which data is downloaded from the beacon node
+const (
+	fullMode       = "full"        // blocks and states
+	blocksOnlyMode = "blocks-only" // never download states: no epoch or validator metrics
+	epochsOnlyMode = "epochs-only" // never download blocks: blocks are derived from the states
+)
+
+const (
+	rewardsStorageFull  = "full"  // one full row per validator and epoch
+	rewardsStorageDelta = "delta" // full rows every keyframe, deltas in between
+)
+
+// applyMode restricts the metrics to the ones the mode can produce
+func applyMode(mode string, metrics db.DBMetrics) (db.DBMetrics, error) {
+	switch mode {
+	case fullMode:
+	case blocksOnlyMode:
+		if metrics.Epoch || metrics.ValidatorRewards || metrics.EpochAggregates {
+			log.Warnf("%s mode: epoch and validator rewards metrics disabled", mode)
+		}
+		metrics.Epoch = false
+		metrics.ValidatorRewards = false
+		metrics.EpochAggregates = false
+		metrics.Block = true
+	case epochsOnlyMode:
+		if metrics.Block || metrics.Transactions {
+			log.Warnf("%s mode: block and transaction metrics disabled", mode)
+		}
+		metrics.Block = false
+		metrics.Transactions = false
+		metrics.Epoch = true
+	default:
+		return metrics, fmt.Errorf("unknown mode: %s", mode)
+	}
+	return metrics, nil
+}
+
+// slotsBefore returns the slot n slots before the given one, or genesis if there are not so many
+func slotsBefore(slot phase0.Slot, n phase0.Slot) phase0.Slot {
+	if slot < n {
+		return 0
+	}
+	return slot - n
+}
+
+// waitForGenesis blocks until the chain has started, for devnets launched before genesis
+func waitForGenesis(ctx context.Context, genesisTime time.Time) error {
+	untilGenesis := time.Until(genesisTime)
+	if untilGenesis <= 0 {
+		return nil
+	}
+	log.Infof("chain has not started yet, waiting %s until genesis (%s)", untilGenesis.Round(time.Second), genesisTime)
+	select {
+	case <-time.After(untilGenesis):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // --- Map ---
 
 type AgnosticMapOption[T spec.AgnosticBlock |
@@ -143,6 +209,15 @@ func (m *AgnosticMap[T]) Delete(key uint64) {
 
 }
 
+// Get returns the value at the given key without waiting for it
+func (m *AgnosticMap[T]) Get(key uint64) (*T, bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	value, ok := m.m[key]
+	return value, ok
+}
+
 func (m *AgnosticMap[T]) Available(key uint64) bool {
 	m.Lock()
 	// Unlock cannot be deferred so we can unblock Set() while waiting
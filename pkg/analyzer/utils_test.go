@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlotsBefore(t *testing.T) {
+	assert.Equal(t, phase0.Slot(36), slotsBefore(100, 64))
+	assert.Equal(t, phase0.Slot(0), slotsBefore(64, 64))
+	// young chains do not go below genesis
+	assert.Equal(t, phase0.Slot(0), slotsBefore(10, 64))
+	assert.Equal(t, phase0.Slot(0), slotsBefore(0, 64))
+}
+
+func TestWaitForGenesis(t *testing.T) {
+	// the chain already started
+	assert.NoError(t, waitForGenesis(context.Background(), time.Now().Add(-time.Hour)))
+
+	start := time.Now()
+	assert.NoError(t, waitForGenesis(context.Background(), start.Add(50*time.Millisecond)))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// stopped while waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, waitForGenesis(ctx, time.Now().Add(time.Hour)), context.DeadlineExceeded)
+}
@@ -0,0 +1,30 @@
+package analyzer
+
+import (
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+)
+
+// processValidatorKeys persists the public keys of the validators that appeared in the registry, or whose activation
+// epoch or withdrawal credentials changed. The whole registry is written on the first call, as it may have changed
+// while the analyzer was stopped
+func (s *ChainAnalyzer) processValidatorKeys(bundle metrics.StateMetrics) {
+	if !s.validatorKeys {
+		return
+	}
+	prevState := bundle.GetMetricsBase().PrevState
+	currentState := bundle.GetMetricsBase().CurrentState
+	s.validatorKeysOnce.Do(func() {
+		prevState = nil
+	})
+
+	keys := spec.ValidatorKeys(prevState, currentState)
+	if len(keys) == 0 {
+		return
+	}
+	log.Debugf("%d validator keys updated at epoch %d", len(keys), currentState.Epoch)
+	err := s.dbClient.PersistValidatorKeys(keys)
+	if err != nil {
+		log.Errorf("error persisting validator keys at epoch %d: %s", currentState.Epoch, err.Error())
+	}
+}
@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ValidatorStatusAnomalies = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "validator_status_anomalies",
+		Help:      "The number of statuses whose validator count did not match the one reported by the beacon node",
+	}, []string{"status"})
+)
+
+// processValidatorStatusCheck compares, every statusCheckEpochs epochs, the number of validators
+// per status computed from the current state with the one the beacon node reports for the same state.
+// The validator set is downloaded again, so the check runs in the background
+func (s *ChainAnalyzer) processValidatorStatusCheck(currentState *spec.AgnosticState) {
+	if s.statusCheckEpochs == 0 || currentState.Epoch%s.statusCheckEpochs != 0 {
+		return
+	}
+
+	epoch := currentState.Epoch
+	slot := currentState.Slot
+	computed := make([]uint64, spec.NUMBER_OF_STATUS)
+	computed[spec.QUEUE_STATUS] = uint64(currentState.NumQueuedVals)
+	computed[spec.ACTIVE_STATUS] = uint64(currentState.NumActiveVals)
+	computed[spec.EXIT_STATUS] = uint64(currentState.NumExitedVals)
+	computed[spec.SLASHED_STATUS] = uint64(currentState.NumSlashedVals)
+
+	go func() {
+		nodeCounts, err := s.cli.RequestValidatorStatusCounts(slot)
+		if err != nil {
+			log.Errorf("could not check the validator statuses of epoch %d: %s", epoch, err)
+			return
+		}
+
+		anomalies := spec.CompareValidatorStatuses(epoch, slot, computed, nodeCounts)
+		if len(anomalies) == 0 {
+			log.Debugf("validator statuses of epoch %d match the beacon node", epoch)
+			return
+		}
+		for _, anomaly := range anomalies {
+			log.Warnf("validator status %d mismatch at epoch %d: %d computed, %d reported by the beacon node",
+				anomaly.Status, epoch, anomaly.ComputedCount, anomaly.NodeCount)
+			ValidatorStatusAnomalies.WithLabelValues(strconv.Itoa(int(anomaly.Status))).Inc()
+		}
+		err = s.dbClient.PersistValidatorStatusAnomalies(anomalies)
+		if err != nil {
+			log.Errorf("error persisting validator status anomalies: %s", err.Error())
+		}
+	}()
+}
+
+func (s *ChainAnalyzer) getValidatorStatusAnomalies() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(ValidatorStatusAnomalies)
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return s.statusCheckEpochs, nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"validator_status_anomalies",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init validator_status_anomalies"))
+		return nil
+	}
+	return indvMetr
+}
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/spec/metrics"
+)
+
+// voteArrivals keeps the first time (unix ms) the vote of each validator was seen through the
+// attestation events, until the epoch of the vote is processed. Aggregates are kept by committee
+// position, as the committees are only known once the state of the epoch is downloaded
+type voteArrivals struct {
+	sync.Mutex
+	aggregates map[phase0.Slot]map[phase0.CommitteeIndex]map[int]int64
+	singles    map[phase0.Slot]map[phase0.ValidatorIndex]int64
+}
+
+func newVoteArrivals() *voteArrivals {
+	return &voteArrivals{
+		aggregates: make(map[phase0.Slot]map[phase0.CommitteeIndex]map[int]int64),
+		singles:    make(map[phase0.Slot]map[phase0.ValidatorIndex]int64),
+	}
+}
+
+// Record keeps the first arrival of every vote in the attestation
+func (v *voteArrivals) Record(attestation spec.AttestationEvent) {
+	v.Lock()
+	defer v.Unlock()
+
+	if attestation.Single {
+		if _, ok := v.singles[attestation.Slot]; !ok {
+			v.singles[attestation.Slot] = make(map[phase0.ValidatorIndex]int64)
+		}
+		if _, ok := v.singles[attestation.Slot][attestation.AttesterIndex]; !ok {
+			v.singles[attestation.Slot][attestation.AttesterIndex] = attestation.ArrivalTimestamp
+		}
+		return
+	}
+
+	if _, ok := v.aggregates[attestation.Slot]; !ok {
+		v.aggregates[attestation.Slot] = make(map[phase0.CommitteeIndex]map[int]int64)
+	}
+	committee, ok := v.aggregates[attestation.Slot][attestation.CommitteeIndex]
+	if !ok {
+		committee = make(map[int]int64)
+		v.aggregates[attestation.Slot][attestation.CommitteeIndex] = committee
+	}
+	for _, position := range attestation.AggregationBits.BitIndices() {
+		if _, ok := committee[position]; !ok {
+			committee[position] = attestation.ArrivalTimestamp
+		}
+	}
+}
+
+// PopEpoch returns the latency (ms since the slot start) of the first arrival of each vote of the epoch,
+// resolving the committee positions with the duties of the epoch, and removes the arrivals of the epoch
+func (v *voteArrivals) PopEpoch(duties spec.EpochDuties, epoch phase0.Epoch, genesis time.Time) []int64 {
+	v.Lock()
+	defer v.Unlock()
+
+	latencies := make([]int64, 0)
+	firstSlot := phase0.Slot(epoch) * spec.SlotsPerEpoch
+	for slot := firstSlot; slot < firstSlot+spec.SlotsPerEpoch; slot++ {
+		firstSeen := make(map[phase0.ValidatorIndex]int64)
+		for valIdx, arrival := range v.singles[slot] {
+			firstSeen[valIdx] = arrival
+		}
+		for committeeIndex, positions := range v.aggregates[slot] {
+			valList := duties.GetValList(slot, committeeIndex)
+			for position, arrival := range positions {
+				if position >= len(valList) {
+					continue // not matching the committee, ignore
+				}
+				valIdx := valList[position]
+				if seen, ok := firstSeen[valIdx]; !ok || arrival < seen {
+					firstSeen[valIdx] = arrival
+				}
+			}
+		}
+
+		slotStart := spec.SlotStart(genesis, slot)
+		for _, arrival := range firstSeen {
+			latencies = append(latencies, arrival-slotStart)
+		}
+		delete(v.singles, slot)
+		delete(v.aggregates, slot)
+	}
+	return latencies
+}
+
+// CleanUpTo removes the arrivals of slots whose epoch was never processed
+func (v *voteArrivals) CleanUpTo(maxSlot phase0.Slot) {
+	v.Lock()
+	defer v.Unlock()
+	for slot := range v.singles {
+		if slot < maxSlot {
+			delete(v.singles, slot)
+		}
+	}
+	for slot := range v.aggregates {
+		if slot < maxSlot {
+			delete(v.aggregates, slot)
+		}
+	}
+}
+
+// processVoteLatency fills the vote latency of the epoch, only for epochs followed through attestation events
+func (s *ChainAnalyzer) processVoteLatency(bundle metrics.StateMetrics, epoch *spec.Epoch) {
+	if s.voteArrivals == nil {
+		return
+	}
+	currentState := bundle.GetMetricsBase().CurrentState
+	latency := spec.NewVoteLatency(s.voteArrivals.PopEpoch(currentState.EpochStructs, currentState.Epoch, s.genesisTime))
+
+	epoch.NumVotesSeen = latency.NumVotes
+	epoch.VoteLatencyMean = latency.Mean
+	epoch.VoteLatencyP95 = latency.P95
+}
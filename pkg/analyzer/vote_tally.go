@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// voteTallies keeps the votes included so far for the epochs whose votes can still be included at the head
+type voteTallies struct {
+	sync.Mutex
+	trackers map[phase0.Epoch]*spec.EpochVoteTracker
+}
+
+func newVoteTallies() *voteTallies {
+	return &voteTallies{
+		trackers: make(map[phase0.Epoch]*spec.EpochVoteTracker),
+	}
+}
+
+// epochVoteTracker returns the tracker of the given epoch, created with the committees of the epoch the first time.
+// It must be called holding the voteTallies lock
+func (s *ChainAnalyzer) epochVoteTracker(epoch phase0.Epoch) (*spec.EpochVoteTracker, error) {
+	if tracker, ok := s.voteTallies.trackers[epoch]; ok {
+		return tracker, nil
+	}
+	// the committees are determined by the state of the previous epoch
+	stateSlot := max(phase0.Slot(epoch)*spec.SlotsPerEpoch, 1) - 1
+	committees, err := s.cli.RequestBeaconCommittees(epoch, stateSlot)
+	if err != nil {
+		return nil, err
+	}
+	tracker := spec.NewEpochVoteTracker(epoch, spec.EpochDuties{BeaconCommittees: committees})
+	s.voteTallies.trackers[epoch] = tracker
+	return tracker, nil
+}
+
+// processVoteTally persists the votes for the previous and the current epoch included up to the block,
+// giving the inclusion curve of every epoch while following the head. Blocks of the historical fill are skipped
+func (s *ChainAnalyzer) processVoteTally(block *spec.AgnosticBlock) {
+	if time.Now().Before(s.genesisTime) {
+		return
+	}
+	wallEpoch := phase0.Epoch(uint64(time.Since(s.genesisTime).Seconds()) / spec.SlotSeconds / spec.SlotsPerEpoch)
+	blockEpoch := phase0.Epoch(block.Slot / spec.SlotsPerEpoch)
+	if blockEpoch+1 < wallEpoch {
+		return
+	}
+
+	epochs := []phase0.Epoch{blockEpoch}
+	if blockEpoch > 0 {
+		epochs = []phase0.Epoch{blockEpoch - 1, blockEpoch}
+	}
+
+	s.voteTallies.Lock()
+	trackers := make([]*spec.EpochVoteTracker, 0, len(epochs))
+	duties := make([]spec.EpochDuties, 0, len(epochs))
+	for _, epoch := range epochs {
+		tracker, err := s.epochVoteTracker(epoch)
+		if err != nil {
+			// electra attestations can only be expanded once, with the committees of both epochs
+			s.voteTallies.Unlock()
+			log.Warnf("could not tally the votes of epoch %d at slot %d: %s", epoch, block.Slot, err)
+			return
+		}
+		trackers = append(trackers, tracker)
+		duties = append(duties, tracker.Duties())
+	}
+	block.ExpandElectraAttestations(duties...)
+
+	tallies := make([]spec.VoteTally, 0, len(trackers))
+	for _, tracker := range trackers {
+		tallies = append(tallies, tracker.AddBlock(block))
+	}
+	// votes of older epochs can no longer be included
+	for epoch := range s.voteTallies.trackers {
+		if epoch+1 < blockEpoch {
+			delete(s.voteTallies.trackers, epoch)
+		}
+	}
+	s.voteTallies.Unlock()
+
+	err := s.dbClient.PersistVoteTallies(tallies)
+	if err != nil {
+		log.Errorf("error persisting vote tallies: %s", err.Error())
+	}
+}
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// warmRestartSlot returns the slot to resume from when the database is ahead of the finalized checkpoint,
+// and the first slot seeded in the cache. Instead of rewinding to the finalized checkpoint, the heads received
+// before the restart (t_head_events) are replayed against the canonical chain of the node, from the last slot
+// checked at finality before the restart, and the analyzer resumes at the epoch of the first slot that is not
+// known to be canonical. The blocks and states persisted since the finalized checkpoint, and the two epochs the
+// transition of the resume epoch needs, are seeded in the cache without being processed, so that they are
+// checked once finalized and reorgs are rewritten as if the analyzer had not been restarted
+func (s *ChainAnalyzer) warmRestartSlot(dbHead phase0.Slot, finalizedSlot phase0.Slot) (phase0.Slot, phase0.Slot) {
+	checkFrom := finalizedSlot
+	dbFinalized, err := s.dbClient.RetrieveLastFinalized()
+	if err != nil {
+		log.Warnf("warm restart: could not get the last finalized checkpoint in the database: %s", err)
+	} else if dbFinalized > 0 {
+		// the finalized checkpoints before the restart only checked the slots up to two epochs before them
+		checkFrom = min(checkFrom, slotsBefore(phase0.Slot(dbFinalized)*spec.SlotsPerEpoch, 2*spec.SlotsPerEpoch))
+	}
+
+	coldSlot := slotsBefore(checkFrom, epochsToFinalizedTentative*spec.SlotsPerEpoch)
+	heads, err := s.dbClient.RetrieveHeadRoots(checkFrom, dbHead)
+	if err != nil {
+		log.Warnf("could not replay the head events, continue from slot %d: %s", coldSlot, err)
+		return coldSlot, coldSlot
+	}
+	canonical := lastCanonicalSlot(checkFrom, dbHead, heads, s.cli.RequestBlockRoot)
+
+	resumeEpoch := phase0.Epoch((canonical + 1) / spec.SlotsPerEpoch)
+	if s.metrics.Epoch { // transitions lag behind the blocks
+		lastEpoch, err := s.dbClient.RetrieveLastEpoch()
+		if err != nil {
+			log.Warnf("could not get the last epoch in the database: %s", err)
+		}
+		resumeEpoch = min(resumeEpoch, lastEpoch+1)
+	}
+	resumeSlot, seedSlot := warmRestartRange(resumeEpoch, finalizedSlot)
+
+	if err := s.seedCache(seedSlot, resumeSlot); err != nil {
+		log.Warnf("warm restart: could not seed the cache, continue from slot %d: %s", seedSlot, err)
+		return seedSlot, seedSlot
+	}
+	log.Infof("warm restart: slots up to %d are canonical, slots %d-%d seeded, continue from slot %d, epoch %d",
+		canonical, seedSlot, resumeSlot, resumeSlot, resumeSlot/spec.SlotsPerEpoch)
+	return resumeSlot, seedSlot
+}
+
+// lastCanonicalSlot replays the heads of the slots (from, to] against the block roots of the node, returning the
+// last slot whose head is the canonical block (or missed in both) before the first one that was reorged,
+// has no head event to compare with or cannot be requested
+func lastCanonicalSlot(from phase0.Slot, to phase0.Slot, heads map[phase0.Slot]string, nodeRoot func(phase0.Slot) (phase0.Root, error)) phase0.Slot {
+	canonical := from // last slot persisted in the canonical chain
+	for slot := from + 1; slot <= to; slot++ {
+		root, err := nodeRoot(slot)
+		if err != nil {
+			log.Warnf("warm restart: %s", err)
+			break
+		}
+		head, ok := heads[slot]
+		if !ok && root != (phase0.Root{}) {
+			log.Infof("warm restart: no head event at slot %d, its block cannot be checked", slot)
+			break
+		}
+		if ok && head != root.String() {
+			log.Infof("warm restart: head %s at slot %d was reorged, the node has %s", head, slot, root)
+			break
+		}
+		canonical = slot // missed in both, or the same block
+	}
+	return canonical
+}
+
+// warmRestartRange returns the slot to resume from, the first one of the resume epoch, and the first slot to seed
+// the cache with: from two epochs before the finalized checkpoint (the ones not checked at finality yet)
+// or the resume epoch, whichever is first
+func warmRestartRange(resumeEpoch phase0.Epoch, finalizedSlot phase0.Slot) (phase0.Slot, phase0.Slot) {
+	resumeSlot := phase0.Slot(resumeEpoch) * spec.SlotsPerEpoch
+	seedSlot := min(spec.FirstSlotInEpoch(finalizedSlot), resumeSlot)
+	return resumeSlot, slotsBefore(seedSlot, 2*spec.SlotsPerEpoch)
+}
+
+// seedCache downloads the blocks and states of the epochs [from, to) to the cache, without processing them.
+// The cache starts at from, so the first state is not expected to have a previous one
+func (s *ChainAnalyzer) seedCache(from phase0.Slot, to phase0.Slot) error {
+	s.initSlot = from
+	for epoch := phase0.Epoch(from / spec.SlotsPerEpoch); phase0.Slot(epoch)*spec.SlotsPerEpoch < to; epoch++ {
+		if s.metrics.Block {
+			for slot := phase0.Slot(epoch) * spec.SlotsPerEpoch; slot < phase0.Slot(epoch+1)*spec.SlotsPerEpoch; slot++ {
+				block, err := s.cli.RequestBeaconBlock(slot)
+				if err != nil {
+					return fmt.Errorf("block at slot %d: %w", slot, err)
+				}
+				s.downloadCache.AddNewBlock(block)
+			}
+		}
+		if s.metrics.Epoch {
+			state, err := s.cli.RequestBeaconState(s.stateSlot(epoch))
+			if err != nil {
+				return fmt.Errorf("state at epoch %d: %w", epoch, err)
+			}
+			if !s.metrics.Block {
+				s.addBlocksFromState(state)
+			}
+			s.downloadCache.AddNewState(state)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastCanonicalSlot(t *testing.T) {
+	node := map[phase0.Slot]phase0.Root{
+		101: {1},
+		102: {2},
+		// 103 missed
+		104: {4},
+		105: {5},
+	}
+	nodeRoot := func(slot phase0.Slot) (phase0.Root, error) {
+		if slot == 106 {
+			return phase0.Root{}, errors.New("node unavailable")
+		}
+		return node[slot], nil
+	}
+	heads := map[phase0.Slot]string{
+		101: phase0.Root{1}.String(),
+		102: phase0.Root{2}.String(),
+		104: phase0.Root{4}.String(),
+		105: phase0.Root{5}.String(),
+	}
+
+	// the missed slot is canonical as well, the node error stops the replay
+	assert.Equal(t, phase0.Slot(105), lastCanonicalSlot(100, 110, heads, nodeRoot))
+	assert.Equal(t, phase0.Slot(104), lastCanonicalSlot(100, 104, heads, nodeRoot))
+
+	// a reorged head
+	heads[104] = phase0.Root{9}.String()
+	assert.Equal(t, phase0.Slot(103), lastCanonicalSlot(100, 110, heads, nodeRoot))
+
+	// a block without head event cannot be checked
+	delete(heads, 102)
+	assert.Equal(t, phase0.Slot(101), lastCanonicalSlot(100, 110, heads, nodeRoot))
+}
+
+func TestWarmRestartRange(t *testing.T) {
+	finalizedSlot := phase0.Slot(100 * spec.SlotsPerEpoch)
+
+	// resuming after the finalized checkpoint, the slots since two epochs before it are seeded
+	resumeSlot, seedSlot := warmRestartRange(103, finalizedSlot)
+	assert.Equal(t, phase0.Slot(103*spec.SlotsPerEpoch), resumeSlot)
+	assert.Equal(t, phase0.Slot(98*spec.SlotsPerEpoch), seedSlot)
+
+	// a reorg found before the finalized checkpoint, only the states of the transition are seeded
+	resumeSlot, seedSlot = warmRestartRange(95, finalizedSlot)
+	assert.Equal(t, phase0.Slot(95*spec.SlotsPerEpoch), resumeSlot)
+	assert.Equal(t, phase0.Slot(93*spec.SlotsPerEpoch), seedSlot)
+
+	resumeSlot, seedSlot = warmRestartRange(1, spec.SlotsPerEpoch)
+	assert.Equal(t, phase0.Slot(spec.SlotsPerEpoch), resumeSlot)
+	assert.Equal(t, phase0.Slot(0), seedSlot)
+}
@@ -0,0 +1,94 @@
+package chaintime
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+const EpochsPerSyncCommitteePeriod = 256
+
+var genesisTimes = map[string]uint64{
+	"mainnet": spec.MainnetGenesis,
+	"sepolia": spec.SepoliaGenesis,
+	"holesky": spec.HoleskyGenesis,
+}
+
+// ChainTime converts between the slots, epochs and sync committee periods of a network and wall-clock times
+type ChainTime struct {
+	genesis time.Time
+}
+
+// Point is a slot of the chain, with its epoch, sync committee period and start time
+type Point struct {
+	Slot       phase0.Slot
+	Epoch      phase0.Epoch
+	SyncPeriod uint64
+	Time       time.Time
+}
+
+func New(genesis time.Time) ChainTime {
+	return ChainTime{
+		genesis: genesis,
+	}
+}
+
+// ForNetwork returns the ChainTime of a known network (mainnet, sepolia, holesky)
+func ForNetwork(network string) (ChainTime, error) {
+	genesis, ok := genesisTimes[network]
+	if !ok {
+		return ChainTime{}, fmt.Errorf("unknown network %s, the genesis time must be given", network)
+	}
+	return New(time.Unix(int64(genesis), 0)), nil
+}
+
+func (c ChainTime) Genesis() time.Time {
+	return c.genesis
+}
+
+// AtSlot returns the given slot
+func (c ChainTime) AtSlot(slot phase0.Slot) Point {
+	epoch := phase0.Epoch(slot / spec.SlotsPerEpoch)
+	return Point{
+		Slot:       slot,
+		Epoch:      epoch,
+		SyncPeriod: uint64(epoch) / EpochsPerSyncCommitteePeriod,
+		Time:       c.genesis.Add(time.Duration(slot) * spec.SlotSeconds * time.Second),
+	}
+}
+
+// AtEpoch returns the first slot of the given epoch
+func (c ChainTime) AtEpoch(epoch phase0.Epoch) Point {
+	return c.AtSlot(phase0.Slot(epoch) * spec.SlotsPerEpoch)
+}
+
+// AtSyncPeriod returns the first slot of the given sync committee period
+func (c ChainTime) AtSyncPeriod(period uint64) Point {
+	return c.AtEpoch(phase0.Epoch(period * EpochsPerSyncCommitteePeriod))
+}
+
+// AtTime returns the slot in progress at the given time
+func (c ChainTime) AtTime(t time.Time) (Point, error) {
+	if t.Before(c.genesis) {
+		return Point{}, fmt.Errorf("%s is before the genesis (%s)", t.UTC().Format(time.RFC3339), c.genesis.UTC().Format(time.RFC3339))
+	}
+	slot := phase0.Slot(t.Sub(c.genesis) / (spec.SlotSeconds * time.Second))
+	return c.AtSlot(slot), nil
+}
+
+// ParseTime reads a date as RFC3339 (2024-03-13T13:55:35Z), a day (2024-03-13, UTC) or a unix timestamp
+func ParseTime(date string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, date); err == nil {
+		return t, nil
+	}
+	var unix int64
+	if _, err := fmt.Sscanf(date, "%d", &unix); err == nil && fmt.Sprintf("%d", unix) == date {
+		return time.Unix(unix, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("could not parse date %q, expected RFC3339, YYYY-MM-DD or a unix timestamp", date)
+}
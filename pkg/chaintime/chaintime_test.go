@@ -0,0 +1,50 @@
+package chaintime_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/chaintime"
+)
+
+func TestAtTime(t *testing.T) {
+	chainTime, err := chaintime.ForNetwork("mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		date       string
+		slot       phase0.Slot
+		epoch      phase0.Epoch
+		syncPeriod uint64
+	}{
+		{"2020-12-01T12:00:23Z", 0, 0, 0},
+		{"2020-12-01T12:00:35Z", 1, 0, 0},
+		{"1710338135", 8626176, 269568, 1053}, // dencun
+		{"2024-03-13", 8621998, 269437, 1052},
+	}
+
+	for _, test := range tests {
+		date, err := chaintime.ParseTime(test.date)
+		if err != nil {
+			t.Fatalf("ParseTime(%s) returned an error: %s", test.date, err)
+		}
+		point, err := chainTime.AtTime(date)
+		if err != nil {
+			t.Fatalf("AtTime(%s) returned an error: %s", test.date, err)
+		}
+		if point.Slot != test.slot || point.Epoch != test.epoch || point.SyncPeriod != test.syncPeriod {
+			t.Errorf("AtTime(%s) returned slot %d, epoch %d, period %d, expected %d, %d, %d",
+				test.date, point.Slot, point.Epoch, point.SyncPeriod, test.slot, test.epoch, test.syncPeriod)
+		}
+	}
+
+	if _, err := chainTime.AtTime(time.Unix(0, 0)); err == nil {
+		t.Errorf("AtTime(0) returned no error, expected it to be before the genesis")
+	}
+	if point := chainTime.AtEpoch(269568); point.Time.Unix() != 1710338135 {
+		t.Errorf("AtEpoch(269568) returned time %d, expected 1710338135", point.Time.Unix())
+	}
+}
@@ -3,12 +3,15 @@ package clientapi
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/http"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/migalabs/goteth/pkg/db"
 	prom_metrics "github.com/migalabs/goteth/pkg/metrics"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
 	"github.com/migalabs/goteth/pkg/utils"
 	"github.com/rs/zerolog"
 	"github.com/sirupsen/logrus"
@@ -20,19 +23,41 @@ var (
 		"module", moduleName)
 	QueryTimeout     = 3 * time.Minute
 	maxParallelConns = 3
+	noPrioritySlot   = ^uint64(0)
+
+	DefaultELConcurrency = 16 // receipt requests sent in parallel to the execution nodes
 )
 
 type APIClientOption func(*APIClient) error
 
 type APIClient struct {
 	ctx        context.Context
-	Api        *http.Service     // Beacon Node
-	ELApi      *ethclient.Client // Execution Node
+	Api        *http.Service // Beacon Node
 	Metrics    db.DBMetrics
 	maxRetries int
+
+	elEndpoints    *ELEndpoints            // list of execution nodes for failover
+	bnPeers        []*BNPeer               // extra beacon nodes, only used to compare their view of the chain
+	proposerDuties *ProposerDutiesCache    // proposer duties already downloaded, per epoch
+	prioritySlot   uint64                  // requests from this slot onwards preempt the rest (head over backfill)
+	genesisTime    time.Time               // custom genesis time, the node's one is used when empty
+	forkSchedule   local_spec.ForkSchedule // custom fork epochs, over the node's ones
+	bnHeaders      map[string]string       // sent with every request to the beacon nodes (auth)
+	elHeaders      map[string]string       // sent with every request to the execution nodes (auth)
+
+	elSlots         chan struct{} // bounds the receipt requests sent in parallel to the execution nodes
+	noBlockReceipts *atomic.Bool  // the execution nodes do not support eth_getBlockReceipts
+
+	beaconCommittees *BeaconCommitteesCache // beacon committees already downloaded (or prefetched), per epoch
+
+	era *eraSource // blocks read from local era files, nil when downloaded from the node
+
+	stateProofValidators int // validators proven against the root of every state, 0 disables it
+
 	statesBook *utils.RoutineBook // Book to track what is being downloaded through the CL API: states
 	blocksBook *utils.RoutineBook // Book to track what is being downloaded through the CL API: blocks
 	txBook     *utils.RoutineBook // Book to track what is being downloaded through the EL API: transactions
+	dutiesBook *utils.RoutineBook // Book to track what is being downloaded through the CL API: proposer duties and committees
 }
 
 func NewAPIClient(ctx context.Context, bnEndpoint string, maxRequestRetries int, options ...APIClientOption) (*APIClient, error) {
@@ -43,13 +68,32 @@ func NewAPIClient(ctx context.Context, bnEndpoint string, maxRequestRetries int,
 		statesBook: utils.NewRoutineBook(1, "api-cli-states"),
 		blocksBook: utils.NewRoutineBook(1, "api-cli-blocks"),
 		txBook:     utils.NewRoutineBook(maxParallelConns, "api-cli-tx"),
+		dutiesBook: utils.NewRoutineBook(1, "api-cli-duties"),
+
+		proposerDuties: NewProposerDutiesCache(),
+		prioritySlot:   noPrioritySlot,
+
+		elSlots:         make(chan struct{}, DefaultELConcurrency),
+		noBlockReceipts: &atomic.Bool{},
+
+		beaconCommittees: NewBeaconCommitteesCache(),
+	}
+
+	apiService.maxRetries = maxRequestRetries
+	for _, o := range options {
+		err := o(apiService)
+		if err != nil {
+			log.Warn(err.Error()) // these are optional, show error and continue
+		}
 	}
 
+	// created after the options, which may set the headers
 	bnCli, err := http.New(
 		ctx,
 		http.WithAddress(bnEndpoint),
 		http.WithLogLevel(zerolog.WarnLevel),
 		http.WithTimeout(QueryTimeout),
+		http.WithExtraHeaders(apiService.bnHeaders),
 	)
 	if err != nil {
 		return &APIClient{}, err
@@ -61,27 +105,47 @@ func NewAPIClient(ctx context.Context, bnEndpoint string, maxRequestRetries int,
 	}
 
 	apiService.Api = hc
-	apiService.maxRetries = maxRequestRetries
-	for _, o := range options {
-		err := o(apiService)
-		if err != nil {
-			log.Warn(err.Error()) // these are optional, show error and continue
-		}
-	}
 
 	return apiService, nil
 }
 
-func WithELEndpoint(url string) APIClientOption {
+// WithELEndpoint accepts a comma separated list of execution endpoints
+// the first one is used by default, the rest are used as failover
+func WithELEndpoint(input string) APIClientOption {
 	return func(s *APIClient) error {
-		if url == "" {
+		urls := ParseEndpoints(input)
+		if len(urls) == 0 {
 			return fmt.Errorf("empty execution address, skipping. Beware transactions data might not be complete")
 		}
-		client, err := ethclient.DialContext(s.ctx, url)
-		if err != nil {
-			return err
+		endpoints := &ELEndpoints{
+			urls:    make([]string, 0),
+			clients: make([]*ethclient.Client, 0),
+		}
+		for _, url := range urls {
+			rpcClient, err := rpc.DialOptions(s.ctx, url, rpc.WithHeaders(toHTTPHeader(s.elHeaders)))
+			if err != nil {
+				log.Warnf("could not dial execution endpoint %s: %s", RedactEndpoint(url), err)
+				continue
+			}
+			client := ethclient.NewClient(rpcClient)
+			endpoints.urls = append(endpoints.urls, url)
+			endpoints.clients = append(endpoints.clients, client)
 		}
-		s.ELApi = client
+		if len(endpoints.clients) == 0 {
+			return fmt.Errorf("could not connect to any execution endpoint")
+		}
+		s.elEndpoints = endpoints
+		return nil
+	}
+}
+
+// WithELConcurrency sets how many receipt requests are sent in parallel to the execution nodes
+func WithELConcurrency(concurrency int) APIClientOption {
+	return func(s *APIClient) error {
+		if concurrency <= 0 {
+			return fmt.Errorf("invalid execution concurrency %d, using %d", concurrency, cap(s.elSlots))
+		}
+		s.elSlots = make(chan struct{}, concurrency)
 		return nil
 	}
 }
@@ -99,11 +163,17 @@ func WithPromMetrics(metrics *prom_metrics.PrometheusMetrics) APIClientOption {
 		metrics.AddMeticsModule(s.statesBook.GetPrometheusMetrics())
 		metrics.AddMeticsModule(s.blocksBook.GetPrometheusMetrics())
 		metrics.AddMeticsModule(s.txBook.GetPrometheusMetrics())
+		metrics.AddMeticsModule(s.dutiesBook.GetPrometheusMetrics())
 
 		return nil
 	}
 }
 
+// RoutineBooks returns the books tracking the requests in flight
+func (s APIClient) RoutineBooks() []*utils.RoutineBook {
+	return []*utils.RoutineBook{s.statesBook, s.blocksBook, s.txBook, s.dutiesBook}
+}
+
 func (s APIClient) ActiveReqNum() int {
 
 	return s.blocksBook.ActivePages() + s.statesBook.ActivePages() + s.txBook.ActivePages()
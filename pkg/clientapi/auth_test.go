@@ -0,0 +1,60 @@
+package clientapi_test
+
+import (
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/clientapi"
+)
+
+func TestParseNodeHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    string
+		headers string
+		result  map[string]string
+		err     bool
+	}{
+		{name: "empty", result: map[string]string{}},
+		{
+			name:   "bearer",
+			auth:   "bearer:abc",
+			result: map[string]string{"Authorization": "Bearer abc"},
+		},
+		{
+			name:   "basic",
+			auth:   "basic:user:pass",
+			result: map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+		},
+		{
+			name:    "custom headers",
+			headers: "X-Api-Key=abc, X-Other = 1",
+			result:  map[string]string{"X-Api-Key": "abc", "X-Other": "1"},
+		},
+		{
+			name:    "auth overrides header",
+			auth:    "bearer:abc",
+			headers: "Authorization=other",
+			result:  map[string]string{"Authorization": "Bearer abc"},
+		},
+		{name: "basic without password", auth: "basic:user", err: true},
+		{name: "unknown kind", auth: "digest:abc", err: true},
+		{name: "invalid header", headers: "X-Api-Key", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := clientapi.ParseNodeHeaders(test.auth, test.headers)
+			if (err != nil) != test.err {
+				t.Fatalf("ParseNodeHeaders returned error %v, expected error: %t", err, test.err)
+			}
+			if len(result) != len(test.result) {
+				t.Errorf("ParseNodeHeaders returned %d headers, expected %d", len(result), len(test.result))
+			}
+			for name, value := range test.result {
+				if result[name] != value {
+					t.Errorf("ParseNodeHeaders returned %s for %s, expected %s", result[name], name, value)
+				}
+			}
+		})
+	}
+}
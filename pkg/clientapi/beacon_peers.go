@@ -0,0 +1,103 @@
+package clientapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/rs/zerolog"
+)
+
+// BNPeer is an extra beacon node. Data is always requested to the main one,
+// peers are only asked for their head to detect chain splits
+type BNPeer struct {
+	Endpoint string // without credentials, safe to be logged or stored
+	api      *http.Service
+}
+
+// WithBNPeers connects to the given extra beacon nodes
+func WithBNPeers(urls []string) APIClientOption {
+	return func(s *APIClient) error {
+		for _, address := range urls {
+			bnCli, err := http.New(
+				s.ctx,
+				http.WithAddress(address),
+				http.WithLogLevel(zerolog.WarnLevel),
+				http.WithTimeout(QueryTimeout),
+				http.WithExtraHeaders(s.bnHeaders),
+			)
+			if err != nil {
+				log.Warnf("could not connect to beacon endpoint %s: %s", RedactEndpoint(address), err)
+				continue
+			}
+			hc, ok := bnCli.(*http.Service)
+			if !ok {
+				log.Errorf("generating the http api client for %s", RedactEndpoint(address))
+				continue
+			}
+			s.bnPeers = append(s.bnPeers, &BNPeer{
+				Endpoint: RedactEndpoint(address),
+				api:      hc,
+			})
+		}
+		if len(urls) > 0 && len(s.bnPeers) == 0 {
+			return fmt.Errorf("could not connect to any extra beacon endpoint, chain splits will not be detected")
+		}
+		return nil
+	}
+}
+
+func (s *APIClient) BNPeers() []*BNPeer {
+	return s.bnPeers
+}
+
+// RedactEndpoint removes the credentials and query of the endpoint
+func RedactEndpoint(endpoint string) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return strings.Split(endpoint, "?")[0]
+	}
+	parsed.User = nil
+	parsed.RawQuery = ""
+	return parsed.String()
+}
+
+// RequestHeadSlot returns the slot of the head block of the main beacon node, or the peer if given
+func (s *APIClient) RequestHeadSlot(peer *BNPeer) (phase0.Slot, error) {
+	cli := s.Api
+	if peer != nil {
+		cli = peer.api
+	}
+	head, err := cli.BeaconBlockHeader(s.ctx, &api.BeaconBlockHeaderOpts{
+		Block: "head",
+	})
+	if err != nil {
+		return 0, err
+	}
+	return head.Data.Header.Message.Slot, nil
+}
+
+// RequestCanonicalRoot returns the root of the block at the given slot in the chain followed
+// by the main beacon node, or the peer if given. Missed slots return an empty root
+func (s *APIClient) RequestCanonicalRoot(peer *BNPeer, slot phase0.Slot) (phase0.Root, error) {
+	cli := s.Api
+	if peer != nil {
+		cli = peer.api
+	}
+	root, err := cli.BeaconBlockRoot(s.ctx, &api.BeaconBlockRootOpts{
+		Block: fmt.Sprintf("%d", slot),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return phase0.Root{}, nil
+		}
+		return phase0.Root{}, err
+	}
+	if root == nil || root.Data == nil {
+		return phase0.Root{}, nil
+	}
+	return *root.Data, nil
+}
@@ -3,7 +3,6 @@ package clientapi
 import (
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/api"
@@ -13,6 +12,7 @@ import (
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
 	local_spec "github.com/migalabs/goteth/pkg/spec"
 	"github.com/migalabs/goteth/pkg/utils"
 	bitfield "github.com/prysmaticlabs/go-bitfield"
@@ -24,9 +24,13 @@ var (
 
 func (s *APIClient) RequestBeaconBlock(slot phase0.Slot) (*local_spec.AgnosticBlock, error) {
 	routineKey := fmt.Sprintf("%s%d", slotKeyTag, slot)
-	s.blocksBook.Acquire(routineKey)
+	s.acquireBySlot(s.blocksBook, routineKey, slot)
 	defer s.blocksBook.FreePage(routineKey)
 
+	if block, ok := s.requestEraBlock(slot); ok {
+		return block, nil
+	}
+
 	log.Debugf("downloading block at slot %d", slot)
 
 	startTime := time.Now()
@@ -41,7 +45,7 @@ func (s *APIClient) RequestBeaconBlock(slot phase0.Slot) (*local_spec.AgnosticBl
 		})
 		if err != nil {
 			if response404(err.Error()) {
-				log.Infof("the beacon block at slot %d does not exist, missing block", slot)
+				log.Debugf("the beacon block at slot %d does not exist, missing block", slot)
 				return s.CreateMissingBlock(slot), nil
 			}
 
@@ -65,6 +69,16 @@ func (s *APIClient) RequestBeaconBlock(slot phase0.Slot) (*local_spec.AgnosticBl
 		return &local_spec.AgnosticBlock{}, fmt.Errorf("unable to parse Beacon Block at slot %d: %s", slot, err.Error())
 	}
 
+	customBlock.StateRoot = s.RequestStateRoot(slot)
+	s.completeBlock(&customBlock)
+
+	log.Debugf("block at slot %d downloaded in %f seconds", slot, time.Since(startTime).Seconds())
+
+	return &customBlock, nil
+}
+
+// completeBlock fills in the payload size and the rewards of the block, which are not part of it
+func (s *APIClient) completeBlock(customBlock *local_spec.AgnosticBlock) {
 	// fill in block size on custom block using RequestBlockByHash
 	// shows error inside function if ELApi is not defined
 	block, err := s.RequestExecutionBlockByHash(common.Hash(customBlock.ExecutionPayload.BlockHash))
@@ -75,21 +89,15 @@ func (s *APIClient) RequestBeaconBlock(slot phase0.Slot) (*local_spec.AgnosticBl
 		customBlock.ExecutionPayload.PayloadSize = uint32(block.Size())
 	}
 
-	customBlock.StateRoot = s.RequestStateRoot(slot)
-
 	// optional depending on metrics
 	if s.Metrics.APIRewards {
-		reward, err := s.RequestBlockRewards(slot)
+		reward, err := s.RequestBlockRewards(customBlock.Slot)
 		if err != nil { // we only reach here if the block exists
 			log.Errorf("cannot request block reward: %s", err)
 		}
 
 		customBlock.Reward = reward
 	}
-
-	log.Infof("block at slot %d downloaded in %f seconds", slot, time.Since(startTime).Seconds())
-
-	return &customBlock, nil
 }
 
 func (s *APIClient) RequestFinalizedBeaconBlock() (*local_spec.AgnosticBlock, error) {
@@ -103,36 +111,34 @@ func (s *APIClient) RequestFinalizedBeaconBlock() (*local_spec.AgnosticBlock, er
 	return s.RequestBeaconBlock(phase0.Slot(finalizedSlot))
 }
 
-func (s *APIClient) RequestBlockRoot(slot phase0.Slot) phase0.Root {
+// RequestBlockRoot returns the root of the canonical block at the slot, empty if the slot was missed
+func (s *APIClient) RequestBlockRoot(slot phase0.Slot) (phase0.Root, error) {
 
 	root, err := s.Api.BeaconBlockRoot(s.ctx, &api.BeaconBlockRootOpts{
 		Block: fmt.Sprintf("%d", slot),
 	})
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if response404(err.Error()) {
 			// block was not found => block does not exist
-			return phase0.Root{}
+			return phase0.Root{}, nil
 		}
-		log.Panicf("could not download the block root at %d: %s", slot, err)
+		return phase0.Root{}, fmt.Errorf("could not download the block root at %d: %w", slot, err)
 	}
 
 	if root == nil { // block root may be empty
-		return phase0.Root{}
+		return phase0.Root{}, nil
 	}
 
-	return *root.Data
+	return *root.Data, nil
 }
 
 func (s *APIClient) CreateMissingBlock(slot phase0.Slot) *local_spec.AgnosticBlock {
-	duties, err := s.Api.ProposerDuties(s.ctx, &api.ProposerDutiesOpts{
-		Indices: []phase0.ValidatorIndex{},
-		Epoch:   phase0.Epoch(slot / 32),
-	})
+	duties, err := s.RequestProposerDuties(phase0.Epoch(slot / local_spec.SlotsPerEpoch))
 	proposerValIdx := phase0.ValidatorIndex(0)
 	if err != nil {
 		log.Errorf("could not request proposer duty: %s", err)
 	} else {
-		for _, duty := range duties.Data {
+		for _, duty := range duties {
 			if duty.Slot == phase0.Slot(slot) {
 				proposerValIdx = duty.ValidatorIndex
 			}
@@ -174,7 +180,7 @@ func (s *APIClient) CreateMissingBlock(slot phase0.Slot) *local_spec.AgnosticBlo
 // RequestBlockByHash retrieves block from the execution client for the given hash
 func (s *APIClient) RequestExecutionBlockByHash(hash common.Hash) (*types.Block, error) {
 
-	if s.ELApi == nil {
+	if s.ELApi() == nil {
 		return nil, nil
 	}
 	emptyHash := common.Hash{}
@@ -187,7 +193,12 @@ func (s *APIClient) RequestExecutionBlockByHash(hash common.Hash) (*types.Block,
 	s.txBook.Acquire(routineKey)
 	defer s.txBook.FreePage(routineKey)
 
-	block, err := s.ELApi.BlockByHash(s.ctx, hash)
+	var block *types.Block
+	err := s.ELCall(func(el *ethclient.Client) error {
+		var err error
+		block, err = el.BlockByHash(s.ctx, hash)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve block by hash %s: %s", hash.String(), err.Error())
 	}
@@ -205,3 +216,38 @@ func (s *APIClient) RequestCurrentHead() phase0.Slot {
 
 	return head.Data.Header.Message.Slot
 }
+
+// RequestBeaconBlockByRoot downloads the block with the given root, which does not need to be in the canonical chain.
+// The state root is the one in the block, and the API rewards are not requested
+func (s *APIClient) RequestBeaconBlockByRoot(root phase0.Root) (*local_spec.AgnosticBlock, error) {
+	log.Debugf("downloading block %s", root)
+
+	startTime := time.Now()
+	newBlock, err := s.Api.SignedBeaconBlock(s.ctx, &api.SignedBeaconBlockOpts{
+		Block: root.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve Beacon Block %s: %s", root, err.Error())
+	}
+	customBlock, err := local_spec.GetCustomBlock(*newBlock.Data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Beacon Block %s: %s", root, err.Error())
+	}
+	stateRoot, err := newBlock.Data.StateRoot()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the state root of Beacon Block %s: %s", root, err.Error())
+	}
+	customBlock.StateRoot = stateRoot
+
+	block, err := s.RequestExecutionBlockByHash(common.Hash(customBlock.ExecutionPayload.BlockHash))
+	if err != nil {
+		log.Errorf("cannot request block by hash: %s", err)
+	}
+	if block != nil {
+		customBlock.ExecutionPayload.PayloadSize = uint32(block.Size())
+	}
+
+	log.Debugf("block %s at slot %d downloaded in %f seconds", root, customBlock.Slot, time.Since(startTime).Seconds())
+
+	return &customBlock, nil
+}
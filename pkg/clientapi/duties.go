@@ -2,17 +2,277 @@ package clientapi
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/attestantio/go-eth2-client/api"
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/migalabs/goteth/pkg/spec"
 )
 
-func (s *APIClient) NewEpochData(slot phase0.Slot) spec.EpochDuties {
+var (
+	epochKeyTag      string = "epoch="
+	committeesKeyTag string = "committees-epoch="
+)
+
+// ProposerDutiesCache keeps the proposer duties already requested, indexed by epoch,
+// so that missing blocks and epoch duties do not request the same epoch several times.
+// The duties are kept with the dependent root they were computed from
+type ProposerDutiesCache struct {
+	sync.Mutex
+	duties map[phase0.Epoch][]*v1.ProposerDuty
+	roots  map[phase0.Epoch]phase0.Root
+}
+
+func NewProposerDutiesCache() *ProposerDutiesCache {
+	return &ProposerDutiesCache{
+		duties: make(map[phase0.Epoch][]*v1.ProposerDuty),
+		roots:  make(map[phase0.Epoch]phase0.Root),
+	}
+}
+
+func (c *ProposerDutiesCache) Get(epoch phase0.Epoch) ([]*v1.ProposerDuty, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	duties, ok := c.duties[epoch]
+	return duties, ok
+}
+
+func (c *ProposerDutiesCache) Set(epoch phase0.Epoch, duties []*v1.ProposerDuty, dependentRoot phase0.Root) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.duties[epoch] = duties
+	c.roots[epoch] = dependentRoot
+}
+
+func (c *ProposerDutiesCache) Delete(epoch phase0.Epoch) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.duties, epoch)
+	delete(c.roots, epoch)
+}
+
+// CheckDependentRoot removes the duties of the epoch if they were computed from another dependent root,
+// returning whether they were removed. Duties without a dependent root take the given one
+func (c *ProposerDutiesCache) CheckDependentRoot(epoch phase0.Epoch, dependentRoot phase0.Root) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	return checkDependentRoot(c.duties, c.roots, epoch, dependentRoot)
+}
+
+// CleanUpTo removes all the epochs below the given one (not included)
+func (c *ProposerDutiesCache) CleanUpTo(epoch phase0.Epoch) {
+	c.Lock()
+	defer c.Unlock()
+
+	for cachedEpoch := range c.duties {
+		if cachedEpoch < epoch {
+			delete(c.duties, cachedEpoch)
+			delete(c.roots, cachedEpoch)
+		}
+	}
+}
+
+// BeaconCommitteesCache keeps the beacon committees already requested, indexed by epoch,
+// so that the committees prefetched one epoch ahead are not requested again with the state.
+// The committees are kept with the dependent root they were computed from, once known
+type BeaconCommitteesCache struct {
+	sync.Mutex
+	committees map[phase0.Epoch][]*v1.BeaconCommittee
+	roots      map[phase0.Epoch]phase0.Root
+}
+
+func NewBeaconCommitteesCache() *BeaconCommitteesCache {
+	return &BeaconCommitteesCache{
+		committees: make(map[phase0.Epoch][]*v1.BeaconCommittee),
+		roots:      make(map[phase0.Epoch]phase0.Root),
+	}
+}
+
+func (c *BeaconCommitteesCache) Get(epoch phase0.Epoch) ([]*v1.BeaconCommittee, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	committees, ok := c.committees[epoch]
+	return committees, ok
+}
+
+func (c *BeaconCommitteesCache) Set(epoch phase0.Epoch, committees []*v1.BeaconCommittee) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.committees[epoch] = committees
+}
+
+func (c *BeaconCommitteesCache) Delete(epoch phase0.Epoch) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.committees, epoch)
+	delete(c.roots, epoch)
+}
+
+// CheckDependentRoot removes the committees of the epoch if they were computed from another dependent root,
+// returning whether they were removed. Committees without a dependent root take the given one
+func (c *BeaconCommitteesCache) CheckDependentRoot(epoch phase0.Epoch, dependentRoot phase0.Root) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	return checkDependentRoot(c.committees, c.roots, epoch, dependentRoot)
+}
+
+// CleanUpTo removes all the epochs below the given one (not included)
+func (c *BeaconCommitteesCache) CleanUpTo(epoch phase0.Epoch) {
+	c.Lock()
+	defer c.Unlock()
+
+	for cachedEpoch := range c.committees {
+		if cachedEpoch < epoch {
+			delete(c.committees, cachedEpoch)
+			delete(c.roots, cachedEpoch)
+		}
+	}
+}
+
+// checkDependentRoot removes the entry of the epoch if it is cached with another dependent root than the given one
+func checkDependentRoot[T any](entries map[phase0.Epoch]T, roots map[phase0.Epoch]phase0.Root, epoch phase0.Epoch, dependentRoot phase0.Root) bool {
+	if _, ok := entries[epoch]; !ok || dependentRoot == (phase0.Root{}) {
+		return false
+	}
+	if cachedRoot := roots[epoch]; cachedRoot == (phase0.Root{}) {
+		roots[epoch] = dependentRoot
+		return false
+	} else if cachedRoot == dependentRoot {
+		return false
+	}
+	delete(entries, epoch)
+	delete(roots, epoch)
+	return true
+}
+
+// RequestProposerDuties returns the proposer duties of the given epoch
+// the duties are only requested to the beacon node if not present in the cache
+func (s *APIClient) RequestProposerDuties(epoch phase0.Epoch) ([]*v1.ProposerDuty, error) {
+	if duties, ok := s.proposerDuties.Get(epoch); ok {
+		return duties, nil
+	}
+
+	routineKey := fmt.Sprintf("%s%d", epochKeyTag, epoch)
+	s.dutiesBook.Acquire(routineKey)
+	defer s.dutiesBook.FreePage(routineKey)
+
+	// the duties could have been downloaded while waiting for the page
+	if duties, ok := s.proposerDuties.Get(epoch); ok {
+		return duties, nil
+	}
+
+	proposerDuties, err := s.Api.ProposerDuties(s.ctx, &api.ProposerDutiesOpts{
+		Indices: []phase0.ValidatorIndex{},
+		Epoch:   epoch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not request proposer duties at epoch %d: %s", epoch, err)
+	}
+
+	dependentRoot, _ := proposerDuties.Metadata["dependent_root"].(phase0.Root)
+	s.proposerDuties.Set(epoch, proposerDuties.Data, dependentRoot)
+	return proposerDuties.Data, nil
+}
+
+// PrefetchProposerDuties downloads into the cache the proposer duties
+// of every epoch in the given range (both included)
+func (s *APIClient) PrefetchProposerDuties(initEpoch phase0.Epoch, finalEpoch phase0.Epoch) {
+	for epoch := initEpoch; epoch <= finalEpoch; epoch++ {
+		if _, err := s.RequestProposerDuties(epoch); err != nil {
+			log.Warnf("could not prefetch proposer duties: %s", err)
+		}
+	}
+}
+
+// RequestBeaconCommittees returns the beacon committees of the given epoch, read from the state at the given slot.
+// The committees are only requested to the beacon node if not present in the cache
+func (s *APIClient) RequestBeaconCommittees(epoch phase0.Epoch, stateSlot phase0.Slot) ([]*v1.BeaconCommittee, error) {
+	if committees, ok := s.beaconCommittees.Get(epoch); ok {
+		return committees, nil
+	}
+
+	routineKey := fmt.Sprintf("%s%d", committeesKeyTag, epoch)
+	s.dutiesBook.Acquire(routineKey)
+	defer s.dutiesBook.FreePage(routineKey)
+
+	// the committees could have been downloaded while waiting for the page
+	if committees, ok := s.beaconCommittees.Get(epoch); ok {
+		return committees, nil
+	}
 
 	epochCommittees, err := s.Api.BeaconCommittees(s.ctx, &api.BeaconCommitteesOpts{
-		State: fmt.Sprintf("%d", slot),
+		State: fmt.Sprintf("%d", stateSlot),
+		Epoch: &epoch,
 	})
+	if err != nil {
+		return nil, fmt.Errorf("could not request beacon committees at epoch %d: %s", epoch, err)
+	}
+
+	s.beaconCommittees.Set(epoch, epochCommittees.Data)
+	return epochCommittees.Data, nil
+}
+
+// PrefetchEpochDuties downloads into the cache the beacon committees and proposer duties of the given epoch,
+// so they are not requested once its state is downloaded. Committees are read from the state of the previous epoch,
+// which already determines them
+func (s *APIClient) PrefetchEpochDuties(epoch phase0.Epoch) {
+	if epoch == 0 {
+		return
+	}
+	startTime := time.Now()
+	prevStateSlot := phase0.Slot(epoch)*spec.SlotsPerEpoch - 1
+	if _, err := s.RequestBeaconCommittees(epoch, prevStateSlot); err != nil {
+		log.Warnf("could not prefetch beacon committees: %s", err)
+	}
+	if _, err := s.RequestProposerDuties(epoch); err != nil {
+		log.Warnf("could not prefetch proposer duties: %s", err)
+	}
+	log.Debugf("duties of epoch %d prefetched in %f seconds", epoch, time.Since(startTime).Seconds())
+}
+
+// CleanEpochDutiesUpTo removes from the cache the duties and committees of epochs below the given one
+func (s *APIClient) CleanEpochDutiesUpTo(epoch phase0.Epoch) {
+	s.proposerDuties.CleanUpTo(epoch)
+	s.beaconCommittees.CleanUpTo(epoch)
+}
+
+// CheckDependentRoots removes from the cache the duties and committees computed from other dependent roots than
+// the ones of a head event at the given epoch: its current duty dependent root (last block of the previous epoch)
+// the proposer duties of the epoch depend on, and so do the committees of the next one, and its previous duty
+// dependent root (last block of two epochs before) the committees of the epoch depend on
+func (s *APIClient) CheckDependentRoots(epoch phase0.Epoch, currentDutyRoot phase0.Root, previousDutyRoot phase0.Root) {
+	if s.proposerDuties.CheckDependentRoot(epoch, currentDutyRoot) {
+		log.Infof("proposer duties of epoch %d changed their dependent root to %s, requesting them again", epoch, currentDutyRoot)
+	}
+	if s.beaconCommittees.CheckDependentRoot(epoch, previousDutyRoot) {
+		log.Infof("beacon committees of epoch %d changed their dependent root to %s, requesting them again", epoch, previousDutyRoot)
+	}
+	if s.beaconCommittees.CheckDependentRoot(epoch+1, currentDutyRoot) {
+		log.Infof("beacon committees of epoch %d changed their dependent root to %s, requesting them again", epoch+1, currentDutyRoot)
+	}
+}
+
+// InvalidateEpochDuties removes from the cache the duties and committees of the given epoch
+// so they are requested again, i.e. after a reorg
+func (s *APIClient) InvalidateEpochDuties(epoch phase0.Epoch) {
+	s.proposerDuties.Delete(epoch)
+	s.beaconCommittees.Delete(epoch)
+}
+
+func (s *APIClient) NewEpochData(slot phase0.Slot) spec.EpochDuties {
+
+	epoch := phase0.Epoch(slot / spec.SlotsPerEpoch)
+	epochCommittees, err := s.RequestBeaconCommittees(epoch, slot)
 
 	if err != nil {
 		log.Error(err.Error())
@@ -21,7 +281,7 @@ func (s *APIClient) NewEpochData(slot phase0.Slot) spec.EpochDuties {
 	validatorsAttSlot := make(map[phase0.ValidatorIndex]phase0.Slot) // each validator, when it had to attest
 	validatorsPerSlot := make(map[phase0.Slot][]phase0.ValidatorIndex)
 
-	for _, committee := range epochCommittees.Data {
+	for _, committee := range epochCommittees {
 		for _, valID := range committee.Validators {
 			validatorsAttSlot[valID] = committee.Slot
 
@@ -35,17 +295,15 @@ func (s *APIClient) NewEpochData(slot phase0.Slot) spec.EpochDuties {
 		}
 	}
 
-	proposerDuties, err := s.Api.ProposerDuties(s.ctx, &api.ProposerDutiesOpts{
-		Epoch: phase0.Epoch(slot / spec.SlotsPerEpoch),
-	})
+	proposerDuties, err := s.RequestProposerDuties(epoch)
 
 	if err != nil {
 		log.Error(err.Error())
 	}
 
 	return spec.EpochDuties{
-		ProposerDuties:   proposerDuties.Data,
-		BeaconCommittees: epochCommittees.Data,
+		ProposerDuties:   proposerDuties,
+		BeaconCommittees: epochCommittees,
 		ValidatorAttSlot: validatorsAttSlot,
 	}
 }
@@ -0,0 +1,53 @@
+package clientapi
+
+import (
+	"testing"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDependentRoots(t *testing.T) {
+	s := &APIClient{
+		proposerDuties:   NewProposerDutiesCache(),
+		beaconCommittees: NewBeaconCommitteesCache(),
+	}
+	rootA, rootB, rootC := phase0.Root{0xa}, phase0.Root{0xb}, phase0.Root{0xc}
+	duties := []*v1.ProposerDuty{{Slot: 3200}}
+	committees := []*v1.BeaconCommittee{{Slot: 3200}}
+
+	s.proposerDuties.Set(100, duties, rootA)
+	s.beaconCommittees.Set(100, committees)
+	s.beaconCommittees.Set(101, committees) // prefetched
+
+	// the committees take the dependent roots of the first head event
+	s.CheckDependentRoots(100, rootA, rootB)
+	_, ok := s.proposerDuties.Get(100)
+	assert.True(t, ok)
+	_, ok = s.beaconCommittees.Get(100)
+	assert.True(t, ok)
+	_, ok = s.beaconCommittees.Get(101)
+	assert.True(t, ok)
+
+	// a reorg of the last block of epoch 99 changes the proposer duties of 100 and the committees of 101
+	s.CheckDependentRoots(100, rootC, rootB)
+	_, ok = s.proposerDuties.Get(100)
+	assert.False(t, ok)
+	_, ok = s.beaconCommittees.Get(100)
+	assert.True(t, ok)
+	_, ok = s.beaconCommittees.Get(101)
+	assert.False(t, ok)
+
+	// a reorg of the last block of epoch 98 changes the committees of 100
+	s.CheckDependentRoots(100, rootC, rootA)
+	_, ok = s.beaconCommittees.Get(100)
+	assert.False(t, ok)
+
+	// nothing cached, or no dependent root given, is not checked
+	s.proposerDuties.Set(102, duties, phase0.Root{})
+	assert.False(t, s.proposerDuties.CheckDependentRoot(102, phase0.Root{}))
+	assert.False(t, s.proposerDuties.CheckDependentRoot(103, rootA))
+	assert.False(t, s.proposerDuties.CheckDependentRoot(102, rootA))
+	assert.True(t, s.proposerDuties.CheckDependentRoot(102, rootB))
+}
@@ -0,0 +1,168 @@
+package clientapi
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/era"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	// spec keys of the fork epochs, in fork order
+	forkEpochKeys = []struct {
+		version spec.DataVersion
+		key     string
+	}{
+		{spec.DataVersionAltair, "ALTAIR_FORK_EPOCH"},
+		{spec.DataVersionBellatrix, "BELLATRIX_FORK_EPOCH"},
+		{spec.DataVersionCapella, "CAPELLA_FORK_EPOCH"},
+		{spec.DataVersionDeneb, "DENEB_FORK_EPOCH"},
+		{spec.DataVersionElectra, "ELECTRA_FORK_EPOCH"},
+	}
+)
+
+// eraSource reads the blocks from local era files instead of the beacon node
+type eraSource struct {
+	store *era.Store
+
+	forksMu sync.Mutex
+	forks   map[spec.DataVersion]phase0.Epoch // loaded from the node spec with the first block
+}
+
+// WithEraBlocksDir reads the blocks covered by the era files of the given directory from disk, and only the blocks:
+// era files only have the state at the end of each era, so the states are still downloaded from the node
+func WithEraBlocksDir(dir string) APIClientOption {
+	return func(s *APIClient) error {
+		if dir == "" {
+			return nil
+		}
+		store, err := era.NewStore(dir)
+		if err != nil {
+			return fmt.Errorf("could not open the era files, blocks will be downloaded from the node: %s", err)
+		}
+		s.era = &eraSource{
+			store: store,
+		}
+		return nil
+	}
+}
+
+// requestEraBlock returns the block at the given slot from the era files,
+// false if they do not cover the slot or it could not be read
+func (s *APIClient) requestEraBlock(slot phase0.Slot) (*local_spec.AgnosticBlock, bool) {
+	if s.era == nil {
+		return nil, false
+	}
+	startTime := time.Now()
+
+	data, ok, err := s.era.store.Block(slot)
+	if err != nil {
+		log.Warnf("%s, requesting it to the node", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	if data == nil {
+		log.Debugf("the beacon block at slot %d does not exist in the era files, missing block", slot)
+		return s.CreateMissingBlock(slot), true
+	}
+
+	version, err := s.forkVersion(slot)
+	if err != nil {
+		log.Warnf("could not determine the fork of slot %d, requesting the block to the node: %s", slot, err)
+		return nil, false
+	}
+	block, err := decodeSignedBlock(version, data)
+	if err != nil {
+		log.Warnf("could not decode the block at slot %d from the era files, requesting it to the node: %s", slot, err)
+		return nil, false
+	}
+	customBlock, err := local_spec.GetCustomBlock(*block)
+	if err != nil {
+		log.Warnf("could not parse the block at slot %d from the era files, requesting it to the node: %s", slot, err)
+		return nil, false
+	}
+	// the state root after a block is the one in the block
+	if customBlock.StateRoot, err = block.StateRoot(); err != nil {
+		log.Warnf("could not read the state root of the block at slot %d, requesting it to the node: %s", slot, err)
+		customBlock.StateRoot = s.RequestStateRoot(slot)
+	}
+	s.completeBlock(&customBlock)
+
+	log.Debugf("block at slot %d read from the era files in %f seconds", slot, time.Since(startTime).Seconds())
+	return &customBlock, true
+}
+
+// forkVersion returns the fork of the given slot from the fork epochs of the node spec, or the custom ones
+func (s *APIClient) forkVersion(slot phase0.Slot) (spec.DataVersion, error) {
+	s.era.forksMu.Lock()
+	defer s.era.forksMu.Unlock()
+
+	if s.era.forks == nil {
+		nodeSpec, err := s.requestSpec()
+		if err != nil {
+			return spec.DataVersionUnknown, err
+		}
+		forks := make(map[spec.DataVersion]phase0.Epoch)
+		for _, fork := range forkEpochKeys {
+			if epoch, ok := nodeSpec[fork.key].(uint64); ok {
+				forks[fork.version] = phase0.Epoch(epoch)
+			}
+		}
+		s.era.forks = forks
+	}
+
+	epoch := phase0.Epoch(slot / local_spec.SlotsPerEpoch)
+	version := spec.DataVersionPhase0
+	for _, fork := range forkEpochKeys {
+		forkEpoch, ok := s.era.forks[fork.version]
+		if !ok || epoch < forkEpoch {
+			break
+		}
+		version = fork.version
+	}
+	return version, nil
+}
+
+func decodeSignedBlock(version spec.DataVersion, data []byte) (*spec.VersionedSignedBeaconBlock, error) {
+	block := &spec.VersionedSignedBeaconBlock{
+		Version: version,
+	}
+	var err error
+	switch version {
+	case spec.DataVersionPhase0:
+		block.Phase0 = &phase0.SignedBeaconBlock{}
+		err = block.Phase0.UnmarshalSSZ(data)
+	case spec.DataVersionAltair:
+		block.Altair = &altair.SignedBeaconBlock{}
+		err = block.Altair.UnmarshalSSZ(data)
+	case spec.DataVersionBellatrix:
+		block.Bellatrix = &bellatrix.SignedBeaconBlock{}
+		err = block.Bellatrix.UnmarshalSSZ(data)
+	case spec.DataVersionCapella:
+		block.Capella = &capella.SignedBeaconBlock{}
+		err = block.Capella.UnmarshalSSZ(data)
+	case spec.DataVersionDeneb:
+		block.Deneb = &deneb.SignedBeaconBlock{}
+		err = block.Deneb.UnmarshalSSZ(data)
+	case spec.DataVersionElectra:
+		block.Electra = &electra.SignedBeaconBlock{}
+		err = block.Electra.UnmarshalSSZ(data)
+	default:
+		return nil, fmt.Errorf("unknown fork version %s", version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
@@ -0,0 +1,219 @@
+package clientapi
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+// ELEndpoints keeps the list of execution clients available
+// the active one is exposed by APIClient.ELApi, the rest are used as failover
+type ELEndpoints struct {
+	sync.Mutex
+	urls     []string
+	clients  []*ethclient.Client
+	earliest []uint64 // oldest block each client serves receipts from, 0 for archive nodes (or not checked yet)
+	active   int
+}
+
+func (e *ELEndpoints) Len() int {
+	e.Lock()
+	defer e.Unlock()
+	return len(e.clients)
+}
+
+// ELApi returns the active execution client, nil if no execution endpoint was given.
+// The active one changes on failover, so it must be requested for every call instead of kept
+func (s *APIClient) ELApi() *ethclient.Client {
+	if s.elEndpoints == nil {
+		return nil
+	}
+	s.elEndpoints.Lock()
+	defer s.elEndpoints.Unlock()
+	if len(s.elEndpoints.clients) == 0 {
+		return nil
+	}
+	return s.elEndpoints.clients[s.elEndpoints.active]
+}
+
+// ParseEndpoints splits a comma separated list of endpoints
+func ParseEndpoints(input string) []string {
+	urls := make([]string, 0)
+	for _, item := range strings.Split(input, ",") {
+		url := strings.TrimSpace(item)
+		if url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// ELCall runs the given request against the active execution client
+// if the request fails, it is retried on the rest of the endpoints in order,
+// and the first one to succeed becomes the active one
+func (s *APIClient) ELCall(request func(*ethclient.Client) error) error {
+	return s.ELCallAt(math.MaxUint64, request)
+}
+
+// ELCallAt runs the given request about the given execution block like ELCall,
+// skipping the endpoints that are not archive nodes and do not keep the receipts of the block anymore
+func (s *APIClient) ELCallAt(blockNumber uint64, request func(*ethclient.Client) error) error {
+	if s.elEndpoints == nil || s.elEndpoints.Len() == 0 {
+		return fmt.Errorf("no execution endpoint available")
+	}
+
+	s.elEndpoints.Lock()
+	clients := s.elEndpoints.clients
+	urls := s.elEndpoints.urls
+	earliest := s.elEndpoints.earliest
+	active := s.elEndpoints.active
+	s.elEndpoints.Unlock()
+
+	err := fmt.Errorf("no execution endpoint serves block %d", blockNumber)
+	for i := 0; i < len(clients); i++ {
+		idx := (active + i) % len(clients)
+		if idx < len(earliest) && blockNumber < earliest[idx] {
+			continue // head-only backup
+		}
+		err = request(clients[idx])
+		if err == nil {
+			if idx != active {
+				log.Warnf("execution endpoint failover: switching to %s", urls[idx])
+				s.elEndpoints.Lock()
+				s.elEndpoints.active = idx
+				s.elEndpoints.Unlock()
+			}
+			return nil
+		}
+		log.Debugf("execution request failed at %s: %s", urls[idx], err)
+	}
+	return err
+}
+
+// RequestExecutionBlockNumber returns the execution block number included in the first
+// proposed block from the given slot on (missed slots are skipped within the epoch)
+func (s *APIClient) RequestExecutionBlockNumber(slot phase0.Slot) (uint64, error) {
+	for i := phase0.Slot(0); i < local_spec.SlotsPerEpoch; i++ {
+		block, err := s.Api.SignedBeaconBlock(s.ctx, &api.SignedBeaconBlockOpts{
+			Block: fmt.Sprintf("%d", slot+i),
+		})
+		if err != nil {
+			if response404(err.Error()) {
+				continue // missed slot, try the next one
+			}
+			return 0, err
+		}
+		return block.Data.ExecutionBlockNumber()
+	}
+	return 0, fmt.Errorf("no proposed block found in slots %d-%d", slot, slot+local_spec.SlotsPerEpoch-1)
+}
+
+// servesReceipts returns whether the given client is able to serve the receipts at the given block
+func (s *APIClient) servesReceipts(client *ethclient.Client, blockNumber uint64) bool {
+	_, err := client.BlockReceipts(s.ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNumber)))
+	return err == nil
+}
+
+// earliestReceiptsBlock searches the oldest block for which the client still serves receipts
+func (s *APIClient) earliestReceiptsBlock(client *ethclient.Client, fromBlock uint64) (uint64, error) {
+	head, err := client.BlockNumber(s.ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !s.servesReceipts(client, head) {
+		return 0, fmt.Errorf("endpoint does not serve receipts at head block %d", head)
+	}
+	low, high := fromBlock, head
+	for low < high {
+		mid := low + (high-low)/2
+		if s.servesReceipts(client, mid) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, nil
+}
+
+// slotAtExecutionBlock estimates the slot of the given execution block using its timestamp
+// the lock over the endpoints must be held by the caller
+func (s *APIClient) slotAtExecutionBlock(blockNumber uint64) phase0.Slot {
+	header, err := s.elEndpoints.clients[s.elEndpoints.active].HeaderByNumber(s.ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		log.Warnf("could not request header at block %d: %s", blockNumber, err)
+		return 0
+	}
+	genesis := uint64(s.RequestGenesis().Unix())
+	if header.Time < genesis {
+		return 0
+	}
+	return phase0.Slot((header.Time - genesis) / local_spec.SlotSeconds)
+}
+
+// CheckELHistory verifies at startup that the execution endpoints can serve receipts
+// from the given block number on. Endpoints that cannot are kept after the rest as head-only backups,
+// used for the blocks they still serve. If none of them can, an error is returned with the gap that cannot be covered
+func (s *APIClient) CheckELHistory(fromBlock uint64) error {
+	if s.elEndpoints == nil || s.elEndpoints.Len() == 0 {
+		return fmt.Errorf("no execution endpoint available")
+	}
+
+	s.elEndpoints.Lock()
+	defer s.elEndpoints.Unlock()
+
+	validUrls := make([]string, 0)
+	validClients := make([]*ethclient.Client, 0)
+	validEarliest := make([]uint64, 0)
+	backupUrls := make([]string, 0)
+	backupClients := make([]*ethclient.Client, 0)
+	backupEarliest := make([]uint64, 0)
+	earliestBlock := uint64(0)
+
+	for i, client := range s.elEndpoints.clients {
+		url := s.elEndpoints.urls[i]
+		if s.servesReceipts(client, fromBlock) {
+			log.Infof("execution endpoint %s serves receipts from block %d", url, fromBlock)
+			validUrls = append(validUrls, url)
+			validClients = append(validClients, client)
+			validEarliest = append(validEarliest, 0)
+			continue
+		}
+		earliest, err := s.earliestReceiptsBlock(client, fromBlock)
+		if err != nil {
+			log.Warnf("execution endpoint %s is not usable: %s", url, err)
+			continue
+		}
+		log.Warnf("execution endpoint %s is not an archive node: receipts only available from block %d, kept as a head-only backup", url, earliest)
+		backupUrls = append(backupUrls, url)
+		backupClients = append(backupClients, client)
+		backupEarliest = append(backupEarliest, earliest)
+		if earliestBlock == 0 || earliest < earliestBlock {
+			earliestBlock = earliest
+		}
+	}
+
+	if len(validClients) == 0 {
+		if earliestBlock == 0 {
+			return fmt.Errorf("none of the execution endpoints serve receipts")
+		}
+		return fmt.Errorf("none of the execution endpoints serve receipts from block %d: "+
+			"blocks %d to %d cannot be analyzed with transactions. "+
+			"Provide an archive execution endpoint, start the analysis at slot %d (block %d), "+
+			"or analyze the gap without the transactions metric",
+			fromBlock, fromBlock, earliestBlock-1, s.slotAtExecutionBlock(earliestBlock), earliestBlock)
+	}
+
+	s.elEndpoints.urls = append(validUrls, backupUrls...)
+	s.elEndpoints.clients = append(validClients, backupClients...)
+	s.elEndpoints.earliest = append(validEarliest, backupEarliest...)
+	s.elEndpoints.active = 0
+	return nil
+}
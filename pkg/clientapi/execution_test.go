@@ -0,0 +1,135 @@
+package clientapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newELServer mocks an execution endpoint answering eth_blockNumber, or failing every request
+func newELServer(t *testing.T, healthy bool) *ethclient.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := ethclient.Dial(server.URL)
+	require.NoError(t, err)
+	return client
+}
+
+func TestELCallFailover(t *testing.T) {
+	down := newELServer(t, false)
+	up := newELServer(t, true)
+	s := &APIClient{
+		ctx: context.Background(),
+		elEndpoints: &ELEndpoints{
+			urls:    []string{"down", "up"},
+			clients: []*ethclient.Client{down, up},
+		},
+	}
+	assert.Equal(t, down, s.ELApi())
+
+	// the active client is read while the failover switches it
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			err := s.ELCall(func(client *ethclient.Client) error {
+				_, err := client.BlockNumber(s.ctx)
+				return err
+			})
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NotNil(t, s.ELApi())
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, up, s.ELApi())
+	assert.Nil(t, (&APIClient{}).ELApi())
+}
+
+// newReceiptsServer mocks an execution endpoint at block 100 that serves the receipts from the earliest block on
+func newReceiptsServer(t *testing.T, earliest uint64) *ethclient.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []any           `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.Header().Set("Content-Type", "application/json")
+		result := `"0x64"`
+		if request.Method == "eth_getBlockReceipts" {
+			block, err := strconv.ParseUint(request.Params[0].(string), 0, 64)
+			require.NoError(t, err)
+			if block < earliest {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"pruned"}}`, request.ID)
+				return
+			}
+			result = "[]"
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, request.ID, result)
+	}))
+	t.Cleanup(server.Close)
+	client, err := ethclient.Dial(server.URL)
+	require.NoError(t, err)
+	return client
+}
+
+func TestCheckELHistoryKeepsHeadOnlyBackups(t *testing.T) {
+	pruned := newReceiptsServer(t, 90)
+	archive := newReceiptsServer(t, 0)
+	s := &APIClient{
+		ctx: context.Background(),
+		elEndpoints: &ELEndpoints{
+			urls:    []string{"pruned", "archive"},
+			clients: []*ethclient.Client{pruned, archive},
+		},
+	}
+
+	require.NoError(t, s.CheckELHistory(10))
+	assert.Equal(t, []string{"archive", "pruned"}, s.elEndpoints.urls)
+	assert.Equal(t, []uint64{0, 90}, s.elEndpoints.earliest)
+
+	// the archive node fails: recent blocks fail over to the backup, older ones cannot
+	calls := func(blockNumber uint64) []*ethclient.Client {
+		called := make([]*ethclient.Client, 0)
+		s.ELCallAt(blockNumber, func(client *ethclient.Client) error {
+			called = append(called, client)
+			if client == archive {
+				return fmt.Errorf("archive down")
+			}
+			return nil
+		})
+		return called
+	}
+	assert.Equal(t, []*ethclient.Client{archive}, calls(50))
+	assert.Equal(t, []*ethclient.Client{archive, pruned}, calls(95))
+	assert.Equal(t, pruned, s.ELApi())
+
+	// without archive nodes the range cannot be analyzed
+	s.elEndpoints = &ELEndpoints{
+		urls:    []string{"pruned"},
+		clients: []*ethclient.Client{pruned},
+	}
+	assert.Error(t, s.CheckELHistory(10))
+	assert.NoError(t, s.CheckELHistory(95))
+}
@@ -0,0 +1,18 @@
+package clientapi
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/api"
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+)
+
+// RequestForkChoice returns the fork choice context of the beacon node at the moment (/eth/v1/debug/fork_choice),
+// the blocks it knows since the finalized checkpoint with their weight
+func (s *APIClient) RequestForkChoice() (*v1.ForkChoice, error) {
+	resp, err := s.Api.ForkChoice(s.ctx, &api.ForkChoiceOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("could not request the fork choice: %s", err)
+	}
+	return resp.Data, nil
+}
@@ -1,8 +1,47 @@
 package clientapi
 
-import "time"
+import (
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+// WithGenesisTime overrides the genesis time reported by the node (custom devnets)
+func WithGenesisTime(genesisTime int64) APIClientOption {
+	return func(s *APIClient) error {
+		if genesisTime > 0 {
+			s.genesisTime = time.Unix(genesisTime, 0)
+			log.Infof("using custom genesis time: %s", s.genesisTime)
+		}
+		return nil
+	}
+}
+
+// WithForkSchedule overrides the fork epochs reported by the node (custom devnets)
+func WithForkSchedule(schedule local_spec.ForkSchedule) APIClientOption {
+	return func(s *APIClient) error {
+		if len(schedule) > 0 {
+			s.forkSchedule = schedule
+			log.Infof("using custom fork schedule: %v", schedule)
+		}
+		return nil
+	}
+}
+
+// requestSpec returns the spec of the node with the custom fork epochs
+func (s APIClient) requestSpec() (map[string]any, error) {
+	nodeSpec, err := s.Api.Spec(s.ctx, &api.SpecOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return s.forkSchedule.Apply(nodeSpec.Data), nil
+}
 
 func (s APIClient) RequestGenesis() time.Time {
+	if !s.genesisTime.IsZero() {
+		return s.genesisTime
+	}
 	genesis, err := s.Api.GenesisTime(s.ctx)
 	if err != nil {
 		log.Panicf("could not get genesis time: %s", err)
@@ -10,3 +49,17 @@ func (s APIClient) RequestGenesis() time.Time {
 
 	return genesis
 }
+
+// RequestSecondsPerSlot returns the SECONDS_PER_SLOT of the node's spec, the mainnet one if it cannot be read
+func (s APIClient) RequestSecondsPerSlot() uint64 {
+	nodeSpec, err := s.Api.Spec(s.ctx, &api.SpecOpts{})
+	if err != nil {
+		log.Warnf("could not get the spec, assuming %ds slots: %s", local_spec.SlotSeconds, err)
+		return local_spec.SlotSeconds
+	}
+	seconds, ok := nodeSpec.Data["SECONDS_PER_SLOT"].(time.Duration)
+	if !ok || seconds < time.Second {
+		return local_spec.SlotSeconds
+	}
+	return uint64(seconds / time.Second)
+}
@@ -0,0 +1,32 @@
+package clientapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// NodeHealth is the sync status reported by the beacon node and the time it took to answer
+type NodeHealth struct {
+	Syncing      bool
+	Optimistic   bool
+	SyncDistance phase0.Slot
+	Latency      time.Duration
+}
+
+// RequestNodeHealth asks the beacon node for its sync status (/eth/v1/node/syncing), timing the request
+func (s *APIClient) RequestNodeHealth() (NodeHealth, error) {
+	startTime := time.Now()
+	resp, err := s.Api.NodeSyncing(s.ctx, &api.NodeSyncingOpts{})
+	if err != nil {
+		return NodeHealth{Latency: time.Since(startTime)}, fmt.Errorf("could not request the sync status: %s", err)
+	}
+	return NodeHealth{
+		Syncing:      resp.Data.IsSyncing,
+		Optimistic:   resp.Data.IsOptimistic,
+		SyncDistance: resp.Data.SyncDistance,
+		Latency:      time.Since(startTime),
+	}, nil
+}
@@ -0,0 +1,67 @@
+package clientapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+// stateAvailable returns whether the node can serve the state at the end of the epoch.
+// The finality checkpoints are requested instead of the state, as they need the state but are tiny
+func (s *APIClient) stateAvailable(epoch phase0.Epoch) (bool, error) {
+	slot := phase0.Slot(epoch+1)*local_spec.SlotsPerEpoch - 1
+	_, err := s.Api.Finality(s.ctx, &api.FinalityOpts{
+		State: fmt.Sprintf("%d", slot),
+	})
+	if err == nil {
+		return true, nil
+	}
+	if statePruned(err) {
+		log.Debugf("state at slot %d not available: %s", slot, err)
+		return false, nil
+	}
+	return false, fmt.Errorf("could not check the state at slot %d: %s", slot, err)
+}
+
+// statePruned returns whether the node answered that it does not have the state. Any other error,
+// i.e. a server error or a timeout, says nothing about the state and must not move the search
+func statePruned(err error) bool {
+	var apiErr *api.Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// EarliestAvailableEpoch returns the first epoch in [from, to] whose end state the node can serve.
+// Pruned nodes only keep the states after their checkpoint or their last restore point,
+// so availability is assumed to grow with the epoch and the range is searched in halves
+func (s *APIClient) EarliestAvailableEpoch(from phase0.Epoch, to phase0.Epoch) (phase0.Epoch, error) {
+	available, err := s.stateAvailable(from)
+	if err != nil || available {
+		return from, err
+	}
+	available, err = s.stateAvailable(to)
+	if err != nil {
+		return from, err
+	}
+	if !available {
+		return from, fmt.Errorf("the node has no state available between epochs %d and %d", from, to)
+	}
+
+	// from is not available, to is
+	for to-from > 1 {
+		middle := from + (to-from)/2
+		available, err = s.stateAvailable(middle)
+		if err != nil {
+			return from, err
+		}
+		if available {
+			to = middle
+		} else {
+			from = middle
+		}
+	}
+	return to, nil
+}
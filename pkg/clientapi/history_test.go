@@ -0,0 +1,21 @@
+package clientapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatePruned(t *testing.T) {
+	assert.True(t, statePruned(&api.Error{Method: http.MethodGet, StatusCode: http.StatusNotFound}))
+	assert.True(t, statePruned(fmt.Errorf("finality: %w", &api.Error{StatusCode: http.StatusNotFound})))
+
+	// the node could not answer, the state may be there
+	assert.False(t, statePruned(&api.Error{Method: http.MethodGet, StatusCode: http.StatusInternalServerError}))
+	assert.False(t, statePruned(&api.Error{Method: http.MethodGet, StatusCode: http.StatusServiceUnavailable}))
+	assert.False(t, statePruned(context.DeadlineExceeded))
+}
@@ -0,0 +1,28 @@
+package clientapi
+
+import (
+	"sync/atomic"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/utils"
+)
+
+// PrioritizeFrom marks every request from the given slot onwards as priority:
+// these will get the API before any other request waiting (backfill)
+func (s *APIClient) PrioritizeFrom(slot phase0.Slot) {
+	atomic.StoreUint64(&s.prioritySlot, uint64(slot))
+}
+
+func (s *APIClient) isPriority(slot phase0.Slot) bool {
+	prioritySlot := atomic.LoadUint64(&s.prioritySlot)
+	return prioritySlot != noPrioritySlot && uint64(slot) >= prioritySlot
+}
+
+// acquireBySlot acquires a page in the book, with priority if the slot is a priority one
+func (s *APIClient) acquireBySlot(book *utils.RoutineBook, key string, slot phase0.Slot) {
+	if s.isPriority(slot) {
+		book.AcquirePriority(key)
+		return
+	}
+	book.Acquire(key)
+}
@@ -0,0 +1,78 @@
+package clientapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+// SpecCheck is the view of the chain of the beacon node, once its spec is validated
+type SpecCheck struct {
+	HeadSlot phase0.Slot
+	HeadFork spec.DataVersion
+}
+
+// CheckSpec validates the spec parameters of the beacon node against the constants the analyzer is built with,
+// and that every fork already reached is supported. Unlike the rest of the requests, it never panics
+func (s *APIClient) CheckSpec() (SpecCheck, error) {
+	check := SpecCheck{}
+	nodeSpec, err := s.requestSpec()
+	if err != nil {
+		return check, fmt.Errorf("could not request the spec: %s", err)
+	}
+	if slots, ok := nodeSpec["SLOTS_PER_EPOCH"].(uint64); !ok || slots != local_spec.SlotsPerEpoch {
+		return check, fmt.Errorf("SLOTS_PER_EPOCH is %v, only %d is supported", nodeSpec["SLOTS_PER_EPOCH"], local_spec.SlotsPerEpoch)
+	}
+	if seconds, ok := nodeSpec["SECONDS_PER_SLOT"].(time.Duration); !ok || seconds != local_spec.SlotSeconds*time.Second {
+		return check, fmt.Errorf("SECONDS_PER_SLOT is %v, only %ds is supported", nodeSpec["SECONDS_PER_SLOT"], local_spec.SlotSeconds)
+	}
+
+	head, err := s.Api.BeaconBlockHeader(s.ctx, &api.BeaconBlockHeaderOpts{
+		Block: "head",
+	})
+	if err != nil {
+		return check, fmt.Errorf("could not request the head: %s", err)
+	}
+	check.HeadSlot = head.Data.Header.Message.Slot
+	headEpoch := phase0.Epoch(check.HeadSlot / local_spec.SlotsPerEpoch)
+
+	known := make(map[string]bool)
+	for _, fork := range forkEpochKeys {
+		known[fork.key] = true
+	}
+	unsupported := make([]string, 0)
+	check.HeadFork = spec.DataVersionPhase0
+	for key, value := range nodeSpec {
+		epoch, ok := value.(uint64)
+		if !ok || !strings.HasSuffix(key, "_FORK_EPOCH") || phase0.Epoch(epoch) > headEpoch {
+			continue
+		}
+		if !known[key] {
+			unsupported = append(unsupported, strings.TrimSuffix(key, "_FORK_EPOCH"))
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return check, fmt.Errorf("the chain reached forks not supported yet: %s", strings.Join(unsupported, ", "))
+	}
+	for _, fork := range forkEpochKeys {
+		if epoch, ok := nodeSpec[fork.key].(uint64); ok && phase0.Epoch(epoch) <= headEpoch {
+			check.HeadFork = fork.version
+		}
+	}
+	return check, nil
+}
+
+// RequestELHead returns the head block number of the active execution endpoint
+func (s *APIClient) RequestELHead() (uint64, error) {
+	if s.ELApi() == nil {
+		return 0, fmt.Errorf("no execution endpoint available")
+	}
+	return s.ELApi().BlockNumber(s.ctx)
+}
@@ -2,25 +2,48 @@ package clientapi
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/api"
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ssz "github.com/ferranbt/fastssz"
 	local_spec "github.com/migalabs/goteth/pkg/spec"
 	"github.com/migalabs/goteth/pkg/utils"
 )
 
 var (
 	stateKeyTag string = "state="
+
+	// ErrStateDecode is returned when the state was served but could not be decoded,
+	// i.e. a bug of the node or a fork version the client does not know
+	ErrStateDecode = errors.New("could not decode the beacon state")
 )
 
+// isDecodeError tells whether the node answered with a state the client could not decode (SSZ or JSON),
+// which requesting it again does not solve
+func isDecodeError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.Is(err, ssz.ErrOffset) ||
+		errors.Is(err, ssz.ErrSize) ||
+		errors.Is(err, ssz.ErrBytesLength) ||
+		errors.Is(err, ssz.ErrVectorLength) ||
+		errors.Is(err, ssz.ErrListTooBig) ||
+		errors.Is(err, ssz.ErrEmptyBitlist) ||
+		errors.Is(err, ssz.ErrInvalidVariableOffset) ||
+		errors.As(err, &syntaxErr) ||
+		errors.As(err, &typeErr)
+}
+
 func (s *APIClient) RequestBeaconState(slot phase0.Slot) (*local_spec.AgnosticState, error) {
 
 	routineKey := fmt.Sprintf("%s%d", stateKeyTag, slot)
-	s.statesBook.Acquire(routineKey)
+	s.acquireBySlot(s.statesBook, routineKey, slot)
 	defer s.statesBook.FreePage(routineKey)
 
 	startTime := time.Now()
@@ -34,6 +57,9 @@ func (s *APIClient) RequestBeaconState(slot phase0.Slot) (*local_spec.AgnosticSt
 		newState, err = s.Api.BeaconState(s.ctx, &api.BeaconStateOpts{
 			State: fmt.Sprintf("%d", slot),
 		})
+		if err != nil && isDecodeError(err) {
+			return nil, fmt.Errorf("%w at slot %d: %s", ErrStateDecode, slot, err.Error())
+		}
 
 		if errors.Is(err, context.DeadlineExceeded) {
 			ticker := time.NewTicker(utils.RoutineFlushTimeout)
@@ -50,15 +76,18 @@ func (s *APIClient) RequestBeaconState(slot phase0.Slot) (*local_spec.AgnosticSt
 
 	}
 
-	log.Infof("state at slot %d downloaded in %f seconds", slot, time.Since(startTime).Seconds())
+	log.Debugf("state at slot %d downloaded in %f seconds", slot, time.Since(startTime).Seconds())
 	resultState, err := local_spec.GetCustomState(*newState.Data, s.NewEpochData(slot))
 	if err != nil {
 		// close the channel (to tell other routines to stop processing and end)
-		return nil, fmt.Errorf("unable to open beacon state, closing requester routine. %s", err.Error())
+		return nil, fmt.Errorf("%w at slot %d: %s", ErrStateDecode, slot, err.Error())
 	}
 	// We have used HashTreeRoot method to hash the downloaded state, but it does not work ok
 	// meantime, we use this
 	resultState.StateRoot = s.RequestStateRoot(slot)
+	if s.stateProofValidators > 0 {
+		resultState.Proof = s.verifyStateProofs(*newState.Data, &resultState)
+	}
 
 	return &resultState, nil
 }
@@ -88,9 +117,57 @@ func (s *APIClient) GetFinalizedEndSlotStateRoot() (phase0.Slot, phase0.Root) {
 		log.Panicf("could not determine the current finalized checkpoint")
 	}
 
-	finalizedSlot := phase0.Slot(currentFinalized.Data.Finalized.Epoch*local_spec.SlotsPerEpoch - 1)
+	finalizedSlot := phase0.Slot(0) // nothing finalized yet on young chains
+	if currentFinalized.Data.Finalized.Epoch > 0 {
+		finalizedSlot = phase0.Slot(currentFinalized.Data.Finalized.Epoch*local_spec.SlotsPerEpoch - 1)
+	}
 
 	root := s.RequestStateRoot(finalizedSlot)
 
 	return finalizedSlot, root
 }
+
+// RequestJustifiedCheckpoint returns the current justified checkpoint of the node
+func (s *APIClient) RequestJustifiedCheckpoint() (*phase0.Checkpoint, error) {
+	finality, err := s.Api.Finality(s.ctx, &api.FinalityOpts{
+		State: "head",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not request the justified checkpoint: %s", err)
+	}
+	return finality.Data.Justified, nil
+}
+
+// RequestFinalityCheckpoints returns the finalized, justified and previous justified checkpoints
+// of the state at the given slot, as reported by the node
+func (s *APIClient) RequestFinalityCheckpoints(slot phase0.Slot) (*v1.Finality, error) {
+	finality, err := s.Api.Finality(s.ctx, &api.FinalityOpts{
+		State: fmt.Sprintf("%d", slot),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not request the finality checkpoints at slot %d: %s", slot, err)
+	}
+	return finality.Data, nil
+}
+
+// RequestBeaconStateByRoot downloads the state with the given root, which does not need to be in the canonical chain.
+// The slot of the state is needed to request the epoch data
+func (s *APIClient) RequestBeaconStateByRoot(root phase0.Root, slot phase0.Slot) (*local_spec.AgnosticState, error) {
+	startTime := time.Now()
+
+	newState, err := s.Api.BeaconState(s.ctx, &api.BeaconStateOpts{
+		State: root.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve Beacon State %s from the beacon node: %s", root, err.Error())
+	}
+
+	log.Debugf("state %s at slot %d downloaded in %f seconds", root, slot, time.Since(startTime).Seconds())
+	resultState, err := local_spec.GetCustomState(*newState.Data, s.NewEpochData(slot))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open beacon state %s: %s", root, err.Error())
+	}
+	resultState.StateRoot = root
+
+	return &resultState, nil
+}
@@ -0,0 +1,42 @@
+package clientapi
+
+import (
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+// WithStateProofs proves the record and the balance of the given number of validators of every downloaded state
+// against its root, so that a misbehaving node does not go unnoticed. 0 disables the verification
+func WithStateProofs(validators int) APIClientOption {
+	return func(s *APIClient) error {
+		s.stateProofValidators = validators
+		if validators > 0 {
+			log.Infof("verifying %d validators of every state with merkle proofs", validators)
+		}
+		return nil
+	}
+}
+
+// verifyStateProofs proves a sample of the validators of the parsed state against the state root
+func (s *APIClient) verifyStateProofs(bstate spec.VersionedBeaconState, state *local_spec.AgnosticState) *local_spec.StateProof {
+	startTime := time.Now()
+	sample := local_spec.SelfCheckSample(state.Epoch, len(state.Validators), s.stateProofValidators)
+	proof := &local_spec.StateProof{
+		Epoch:      state.Epoch,
+		Slot:       state.Slot,
+		StateRoot:  state.StateRoot,
+		Validators: len(sample),
+		Timestamp:  startTime.Unix(),
+	}
+
+	failed, err := local_spec.VerifyStateProofs(bstate, state, sample)
+	if err != nil {
+		proof.Error = err.Error()
+		return proof
+	}
+	proof.Failed = failed
+	log.Debugf("proofs of %d validators at slot %d verified in %f seconds", len(sample), state.Slot, time.Since(startTime).Seconds())
+	return proof
+}
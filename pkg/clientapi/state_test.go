@@ -0,0 +1,27 @@
+package clientapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDecodeError(t *testing.T) {
+	// the errors of the client wrap the ones of the decoders
+	sszErr := (&deneb.BeaconState{}).UnmarshalSSZ([]byte{1, 2, 3})
+	assert.True(t, isDecodeError(errors.Join(errors.New("failed to decode deneb beacon state"), sszErr)))
+	assert.True(t, isDecodeError(fmt.Errorf("decoding: %w", ssz.ErrOffset)))
+
+	jsonErr := json.Unmarshal([]byte("{"), &struct{}{})
+	assert.True(t, isDecodeError(errors.Join(errors.New("failed to parse JSON"), jsonErr)))
+
+	// a node that times out or fails to serve the state can be requested again
+	assert.False(t, isDecodeError(context.DeadlineExceeded))
+	assert.False(t, isDecodeError(errors.New("failed to decode: 503 service unavailable")))
+}
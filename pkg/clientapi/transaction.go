@@ -2,29 +2,124 @@ package clientapi
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/migalabs/goteth/pkg/spec"
 	"github.com/migalabs/goteth/pkg/utils"
 )
 
 var (
-	blobTxType uint8 = 3
+	blobTxType        uint8 = 3
+	receiptsBatchSize       = 100 // receipts per JSON-RPC batch, when eth_getBlockReceipts is not available
+	rpcMethodNotFound       = -32601
 )
 
+// GetBlockReceipts requests the receipts of all the transactions of the block.
+// eth_getBlockReceipts is used when the endpoint supports it, otherwise the receipts are
+// requested in batches of eth_getTransactionReceipt calls. Requests of all the blocks being
+// processed share the execution concurrency (WithELConcurrency)
 func (client *APIClient) GetBlockReceipts(block spec.AgnosticBlock) ([]*types.Receipt, error) {
-	blockNumber := rpc.BlockNumber(block.ExecutionPayload.BlockNumber)
-	receipts, err := client.ELApi.BlockReceipts(client.ctx, rpc.BlockNumberOrHashWithNumber(blockNumber))
-	if err != nil {
-		return nil, err
+	if !client.noBlockReceipts.Load() {
+		blockNumber := rpc.BlockNumber(block.ExecutionPayload.BlockNumber)
+		var receipts []*types.Receipt
+		client.elSlots <- struct{}{}
+		err := client.ELCallAt(block.ExecutionPayload.BlockNumber, func(el *ethclient.Client) error {
+			var err error
+			receipts, err = el.BlockReceipts(client.ctx, rpc.BlockNumberOrHashWithNumber(blockNumber))
+			return err
+		})
+		<-client.elSlots
+		if err == nil {
+			return receipts, nil
+		}
+		if !methodNotSupported(err) {
+			return nil, err
+		}
+		log.Warnf("execution endpoint does not support eth_getBlockReceipts, requesting receipts in batches: %s", err)
+		client.noBlockReceipts.Store(true)
+	}
+	return client.batchReceipts(block)
+}
+
+// batchReceipts requests the receipt of every transaction of the block through batched JSON-RPC calls
+func (client *APIClient) batchReceipts(block spec.AgnosticBlock) ([]*types.Receipt, error) {
+	hashes := make([]common.Hash, 0, len(block.ExecutionPayload.Transactions))
+	for _, tx := range block.ExecutionPayload.Transactions {
+		var parsedTx = &types.Transaction{}
+		if err := parsedTx.UnmarshalBinary(tx); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal transaction: %s", err)
+		}
+		hashes = append(hashes, parsedTx.Hash())
+	}
+
+	receipts := make([]*types.Receipt, len(hashes))
+	errs := make([]error, 0)
+	var errsMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for start := 0; start < len(hashes); start += receiptsBatchSize {
+		end := start + receiptsBatchSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+		batch := make([]rpc.BatchElem, 0, end-start)
+		for i := start; i < end; i++ {
+			receipts[i] = &types.Receipt{}
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []interface{}{hashes[i]},
+				Result: receipts[i],
+			})
+		}
+
+		wg.Add(1)
+		client.elSlots <- struct{}{}
+		go func(batch []rpc.BatchElem) {
+			defer wg.Done()
+			defer func() { <-client.elSlots }()
+
+			err := client.ELCallAt(block.ExecutionPayload.BlockNumber, func(el *ethclient.Client) error {
+				if err := el.Client().BatchCallContext(client.ctx, batch); err != nil {
+					return err
+				}
+				for _, elem := range batch {
+					if elem.Error != nil {
+						return elem.Error
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				errsMu.Lock()
+				errs = append(errs, err)
+				errsMu.Unlock()
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("unable to retrieve the receipts of block %d: %s", block.ExecutionPayload.BlockNumber, errs[0])
 	}
 	return receipts, nil
 }
 
+// methodNotSupported tells whether the endpoint rejected the request because it does not implement the method,
+// i.e. it answered with the JSON-RPC method not found error. Any other error, even with a similar message, is not
+// taken as the method being unsupported
+func methodNotSupported(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcMethodNotFound
+}
+
 // convert transactions from byte sequences to Transaction object
 func (s *APIClient) GetTransactionReceipt(iTx bellatrix.Transaction,
 	iSlot phase0.Slot,
@@ -40,11 +135,11 @@ func (s *APIClient) GetTransactionReceipt(iTx bellatrix.Transaction,
 	var receipt *types.Receipt
 	err := errors.New("first attempt")
 
-	if s.ELApi == nil {
+	if s.ELApi() == nil {
 		log.Warn("EL endpoint not provided. The gas price read from the CL may not be the effective gas price.")
 		attempts := 0
 		for err != nil && attempts < s.maxRetries {
-			receipt, err = s.ELApi.TransactionReceipt(s.ctx, parsedTx.Hash())
+			receipt, err = s.ELApi().TransactionReceipt(s.ctx, parsedTx.Hash())
 
 			if err != nil {
 				ticker := time.NewTicker(utils.RoutineFlushTimeout)
@@ -0,0 +1,37 @@
+package clientapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rpcError mocks the JSON-RPC errors returned by the execution endpoint
+type rpcError struct {
+	code    int
+	message string
+}
+
+func (e rpcError) Error() string  { return e.message }
+func (e rpcError) ErrorCode() int { return e.code }
+
+func TestMethodNotSupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Method not found", err: rpcError{code: -32601, message: "the method eth_getBlockReceipts does not exist/is not available"}, expected: true},
+		{name: "Wrapped method not found", err: fmt.Errorf("receipts: %w", rpcError{code: -32601, message: "method not found"}), expected: true},
+		{name: "Other JSON-RPC error", err: rpcError{code: -32000, message: "header not found, block does not exist"}, expected: false},
+		{name: "Invalid params", err: rpcError{code: -32602, message: "invalid params: not supported"}, expected: false},
+		{name: "Not a JSON-RPC error", err: errors.New("method not found"), expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, methodNotSupported(test.err))
+		})
+	}
+}
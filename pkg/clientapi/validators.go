@@ -0,0 +1,112 @@
+package clientapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// nodeValidator keeps only the fields of the validators endpoint needed to count statuses and compare validator sets
+type nodeValidator struct {
+	Index     uint64 `json:"index,string"`
+	Balance   uint64 `json:"balance,string"`
+	Status    string `json:"status"`
+	Validator struct {
+		Slashed bool `json:"slashed"`
+	} `json:"validator"`
+}
+
+// streamValidators requests the validators at the given slot and calls fn with each of them.
+// The response is decoded as a stream, as it contains the whole validator set
+func (s *APIClient) streamValidators(slot phase0.Slot, fn func(validator nodeValidator)) error {
+	ctx, cancel := context.WithTimeout(s.ctx, QueryTimeout)
+	defer cancel()
+
+	uri := fmt.Sprintf("%s/eth/v1/beacon/states/%d/validators", s.Api.Address(), slot)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range s.bnHeaders {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not request the validators at slot %d: %s", slot, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not request the validators at slot %d: status %d", slot, resp.StatusCode)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	// move to the beginning of the data array: {"execution_optimistic": ..., "data": [
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("could not find the validators in the response: %s", err)
+		}
+		if key, ok := token.(string); ok && key == "data" {
+			break
+		}
+	}
+	if _, err := decoder.Token(); err != nil { // [
+		return fmt.Errorf("could not read the validators: %s", err)
+	}
+
+	for decoder.More() {
+		var validator nodeValidator
+		if err := decoder.Decode(&validator); err != nil {
+			return fmt.Errorf("could not decode validator: %s", err)
+		}
+		fn(validator)
+	}
+	return nil
+}
+
+// RequestValidatorStatusCounts returns the number of validators per status (indexed by spec.ValidatorStatus)
+// at the given slot, as computed by the beacon node itself
+func (s *APIClient) RequestValidatorStatusCounts(slot phase0.Slot) ([]uint64, error) {
+	counts := make([]uint64, spec.NUMBER_OF_STATUS)
+	err := s.streamValidators(slot, func(validator nodeValidator) {
+		counts[spec.StatusFromNodeStatus(validator.Status, validator.Validator.Slashed)]++
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// RequestValidatorSnapshots returns the balance and status of every validator at the given slot
+func (s *APIClient) RequestValidatorSnapshots(slot phase0.Slot) ([]spec.ValidatorSnapshot, error) {
+	snapshots := make([]spec.ValidatorSnapshot, 0)
+	err := s.streamValidators(slot, func(validator nodeValidator) {
+		snapshots = append(snapshots, spec.ValidatorSnapshot{
+			Index:   phase0.ValidatorIndex(validator.Index),
+			Balance: phase0.Gwei(validator.Balance),
+			Status:  spec.StatusFromNodeStatus(validator.Status, validator.Validator.Slashed),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// RequestValidatorBalances returns the balances of the given validators at the given slot, as reported by the node
+func (s *APIClient) RequestValidatorBalances(slot phase0.Slot, indices []phase0.ValidatorIndex) (map[phase0.ValidatorIndex]phase0.Gwei, error) {
+	balances, err := s.Api.ValidatorBalances(s.ctx, &api.ValidatorBalancesOpts{
+		State:   fmt.Sprintf("%d", slot),
+		Indices: indices,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not request the validator balances at slot %d: %s", slot, err)
+	}
+	return balances.Data, nil
+}
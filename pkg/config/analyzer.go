@@ -14,12 +14,76 @@ type AnalyzerConfig struct {
 	ElEndpoint               string      `json:"el-endpoint"`
 	DBUrl                    string      `json:"db-url"`
 	DownloadMode             string      `json:"download-mode"`
+	Mode                     string      `json:"mode"`
 	WorkerNum                int         `json:"worker-num"`
 	DbWorkerNum              int         `json:"db-worker-num"`
 	Metrics                  string      `json:"metrics"`
 	PrometheusPort           int         `json:"prometheus-port"`
 	MaxRequestRetries        int         `json:"max-request-retries"`
 	BeaconContractAddress    string      `json:"beacon-contract-address"`
+	GenesisTime              int64       `json:"genesis-time"`
+	ForkSchedule             string      `json:"fork-schedule"`
+	LagAlarmSlots            uint64      `json:"lag-alarm-slots"`
+	LagResumeSlots           uint64      `json:"lag-resume-slots"`
+	KafkaBrokers             string      `json:"kafka-brokers"`
+	KafkaEpochsTopic         string      `json:"kafka-epochs-topic"`
+	KafkaBlocksTopic         string      `json:"kafka-blocks-topic"`
+	KafkaRewardsTopic        string      `json:"kafka-rewards-topic"`
+	RewardsWindows           bool        `json:"rewards-windows"`
+	RewardsRetentionEpochs   int         `json:"rewards-retention-epochs"`
+	MonitorValidators        string      `json:"monitor-validators"`
+	AdminToken               string      `json:"admin-token"`
+	ChainSplitSlots          uint64      `json:"chain-split-slots"`
+	RewardsStorage           string      `json:"rewards-storage"`
+	RewardsKeyframeEpochs    uint64      `json:"rewards-keyframe-epochs"`
+	AttestationEvents        bool        `json:"attestation-events"`
+	StatusCheckEpochs        uint64      `json:"status-check-epochs"`
+	ElConcurrency            int         `json:"el-concurrency"`
+	NotificationRules        string      `json:"notification-rules"`
+	NotificationWebhook      string      `json:"notification-webhook"`
+	BranchRoots              string      `json:"branch-roots"`
+	BnAuth                   string      `json:"bn-auth"`
+	BnHeaders                string      `json:"bn-headers"`
+	ElAuth                   string      `json:"el-auth"`
+	ElHeaders                string      `json:"el-headers"`
+	GraphQL                  bool        `json:"graphql"`
+	GraphQLAuth              string      `json:"graphql-auth"`
+	RegistryCheck            bool        `json:"registry-check"`
+	TxStorage                string      `json:"tx-storage"`
+	EraBlocksDir             string      `json:"era-blocks-dir"`
+	SelfCheck                bool        `json:"self-check"`
+	ShutdownGrace            int         `json:"shutdown-grace"`
+	DBSchema                 string      `json:"db-schema"`
+	DBReconnectTimeout       int         `json:"db-reconnect-timeout"`
+	FailureReport            string      `json:"failure-report"`
+	Journal                  string      `json:"journal"`
+	DowntimeWindows          string      `json:"downtime-windows"`
+	SampleBalances           bool        `json:"sample-balances"`
+	NodeHealthInterval       int         `json:"node-health-interval"`
+	NodeMaxLatency           int         `json:"node-max-latency"`
+	PauseBackfill            bool        `json:"pause-backfill"`
+	StuckEpochTimeout        int         `json:"stuck-epoch-timeout"`
+	RequeueStuckEpochs       bool        `json:"requeue-stuck-epochs"`
+	DBDualWriteUrl           string      `json:"db-dual-write-url"`
+	StateProofValidators     int         `json:"state-proof-validators"`
+	WarmRestart              bool        `json:"warm-restart"`
+	RewardsShards            string      `json:"rewards-shards"`
+	DryRun                   bool        `json:"dry-run"`
+	ProposerFairnessEpochs   int         `json:"proposer-fairness-epochs"`
+	ValidatorKeys            bool        `json:"validator-keys"`
+	FinalityAlertEpochs      string      `json:"finality-alert-epochs"`
+	PrefetchMaxEpochs        int         `json:"prefetch-max-epochs"`
+	PrefetchMemory           int         `json:"prefetch-memory"`
+	ClientDiversityEpochs    int         `json:"client-diversity-epochs"`
+	CustomPools              string      `json:"custom-pools"`
+	CustomPoolsRefresh       int         `json:"custom-pools-refresh"`
+	SlashingProtection       string      `json:"slashing-protection"`
+	CalibrateEpochs          int         `json:"calibrate-epochs"`
+	StateFallbackSlots       int         `json:"state-fallback-slots"`
+	StatsdAddress            string      `json:"statsd-address"`
+	StatsdPrefix             string      `json:"statsd-prefix"`
+	StatsdTags               string      `json:"statsd-tags"`
+	BalanceDropOfflinePct    int         `json:"balance-drop-offline-percent"`
 }
 
 // TODO: read from config-file
@@ -34,12 +98,76 @@ func NewAnalyzerConfig() *AnalyzerConfig {
 		ElEndpoint:               DefaultElEndpoint,
 		DBUrl:                    DefaultDBUrl,
 		DownloadMode:             DefaultDownloadMode,
+		Mode:                     DefaultMode,
 		WorkerNum:                DefaultWorkerNum,
 		DbWorkerNum:              DefaultDbWorkerNum,
 		Metrics:                  DefaultMetrics,
 		PrometheusPort:           DefaultPrometheusPort,
 		MaxRequestRetries:        DefaultMaxRequestRetries,
 		BeaconContractAddress:    DefaultBeaconContractAddress,
+		GenesisTime:              DefaultGenesisTime,
+		ForkSchedule:             DefaultForkSchedule,
+		LagAlarmSlots:            DefaultLagAlarmSlots,
+		LagResumeSlots:           DefaultLagResumeSlots,
+		KafkaBrokers:             DefaultKafkaBrokers,
+		KafkaEpochsTopic:         DefaultKafkaEpochsTopic,
+		KafkaBlocksTopic:         DefaultKafkaBlocksTopic,
+		KafkaRewardsTopic:        DefaultKafkaRewardsTopic,
+		RewardsWindows:           DefaultRewardsWindows,
+		RewardsRetentionEpochs:   DefaultRewardsRetentionEpochs,
+		MonitorValidators:        DefaultMonitorValidators,
+		AdminToken:               DefaultAdminToken,
+		ChainSplitSlots:          DefaultChainSplitSlots,
+		RewardsStorage:           DefaultRewardsStorage,
+		RewardsKeyframeEpochs:    DefaultRewardsKeyframeEpochs,
+		AttestationEvents:        DefaultAttestationEvents,
+		StatusCheckEpochs:        DefaultStatusCheckEpochs,
+		ElConcurrency:            DefaultElConcurrency,
+		NotificationRules:        DefaultNotificationRules,
+		NotificationWebhook:      DefaultNotificationWebhook,
+		BranchRoots:              DefaultBranchRoots,
+		BnAuth:                   DefaultNodeAuth,
+		BnHeaders:                DefaultNodeHeaders,
+		ElAuth:                   DefaultNodeAuth,
+		ElHeaders:                DefaultNodeHeaders,
+		GraphQL:                  DefaultGraphQL,
+		GraphQLAuth:              DefaultNodeAuth,
+		RegistryCheck:            DefaultRegistryCheck,
+		TxStorage:                DefaultTxStorage,
+		EraBlocksDir:             DefaultEraBlocksDir,
+		SelfCheck:                DefaultSelfCheck,
+		ShutdownGrace:            DefaultShutdownGrace,
+		DBSchema:                 DefaultDBSchema,
+		DBReconnectTimeout:       DefaultDBReconnectTimeout,
+		FailureReport:            DefaultFailureReport,
+		Journal:                  DefaultJournal,
+		DowntimeWindows:          DefaultDowntimeWindows,
+		SampleBalances:           DefaultSampleBalances,
+		NodeHealthInterval:       DefaultNodeHealthInterval,
+		NodeMaxLatency:           DefaultNodeMaxLatency,
+		PauseBackfill:            DefaultPauseBackfill,
+		StuckEpochTimeout:        DefaultStuckEpochTimeout,
+		RequeueStuckEpochs:       DefaultRequeueStuckEpochs,
+		DBDualWriteUrl:           DefaultDBDualWriteUrl,
+		StateProofValidators:     DefaultStateProofValidators,
+		WarmRestart:              DefaultWarmRestart,
+		RewardsShards:            DefaultRewardsShards,
+		DryRun:                   DefaultDryRun,
+		ProposerFairnessEpochs:   DefaultProposerFairnessEpochs,
+		ValidatorKeys:            DefaultValidatorKeys,
+		FinalityAlertEpochs:      DefaultFinalityAlertEpochs,
+		PrefetchMaxEpochs:        DefaultPrefetchMaxEpochs,
+		PrefetchMemory:           DefaultPrefetchMemory,
+		ClientDiversityEpochs:    DefaultClientDiversityEpochs,
+		CustomPools:              DefaultCustomPools,
+		CustomPoolsRefresh:       DefaultCustomPoolsRefresh,
+		SlashingProtection:       DefaultSlashingProtection,
+		CalibrateEpochs:          DefaultCalibrateEpochs,
+		StateFallbackSlots:       DefaultStateFallbackSlots,
+		StatsdAddress:            DefaultStatsdAddress,
+		StatsdPrefix:             DefaultStatsdPrefix,
+		StatsdTags:               DefaultStatsdTags,
+		BalanceDropOfflinePct:    DefaultBalanceDropOfflinePct,
 	}
 }
 
@@ -77,6 +205,10 @@ func (c *AnalyzerConfig) Apply(ctx *cli.Context) {
 	if ctx.IsSet("download-mode") {
 		c.DownloadMode = ctx.String("download-mode")
 	}
+	// mode
+	if ctx.IsSet("mode") {
+		c.Mode = ctx.String("mode")
+	}
 	// worker num
 	if ctx.IsSet("workers-num") {
 		c.WorkerNum = ctx.Int("workers-num")
@@ -101,4 +233,239 @@ func (c *AnalyzerConfig) Apply(ctx *cli.Context) {
 	if ctx.IsSet("beacon-contract-address") {
 		c.BeaconContractAddress = ctx.String("beacon-contract-address")
 	}
+	// custom genesis time
+	if ctx.IsSet("genesis-time") {
+		c.GenesisTime = ctx.Int64("genesis-time")
+	}
+	// custom fork schedule
+	if ctx.IsSet("fork-schedule") {
+		c.ForkSchedule = ctx.String("fork-schedule")
+	}
+	// head lag thresholds
+	if ctx.IsSet("lag-alarm-slots") {
+		c.LagAlarmSlots = ctx.Uint64("lag-alarm-slots")
+	}
+	if ctx.IsSet("lag-resume-slots") {
+		c.LagResumeSlots = ctx.Uint64("lag-resume-slots")
+	}
+	// kafka sink
+	if ctx.IsSet("kafka-brokers") {
+		c.KafkaBrokers = ctx.String("kafka-brokers")
+	}
+	if ctx.IsSet("kafka-epochs-topic") {
+		c.KafkaEpochsTopic = ctx.String("kafka-epochs-topic")
+	}
+	if ctx.IsSet("kafka-blocks-topic") {
+		c.KafkaBlocksTopic = ctx.String("kafka-blocks-topic")
+	}
+	if ctx.IsSet("kafka-rewards-topic") {
+		c.KafkaRewardsTopic = ctx.String("kafka-rewards-topic")
+	}
+	// validator rewards windows and retention
+	if ctx.IsSet("rewards-windows") {
+		c.RewardsWindows = ctx.Bool("rewards-windows")
+	}
+	if ctx.IsSet("rewards-retention-epochs") {
+		c.RewardsRetentionEpochs = ctx.Int("rewards-retention-epochs")
+	}
+	// validators whose votes are checked for equivocations
+	if ctx.IsSet("monitor-validators") {
+		c.MonitorValidators = ctx.String("monitor-validators")
+	}
+	// admin endpoints
+	if ctx.IsSet("admin-token") {
+		c.AdminToken = ctx.String("admin-token")
+	}
+	// divergence between beacon endpoints
+	if ctx.IsSet("chain-split-slots") {
+		c.ChainSplitSlots = ctx.Uint64("chain-split-slots")
+	}
+	// validator rewards encoding
+	if ctx.IsSet("rewards-storage") {
+		c.RewardsStorage = ctx.String("rewards-storage")
+	}
+	if ctx.IsSet("rewards-keyframe-epochs") {
+		c.RewardsKeyframeEpochs = ctx.Uint64("rewards-keyframe-epochs")
+	}
+	// vote latency
+	if ctx.IsSet("attestation-events") {
+		c.AttestationEvents = ctx.Bool("attestation-events")
+	}
+	// validator status cross-check
+	if ctx.IsSet("status-check-epochs") {
+		c.StatusCheckEpochs = ctx.Uint64("status-check-epochs")
+	}
+	// parallel receipt requests
+	if ctx.IsSet("el-concurrency") {
+		c.ElConcurrency = ctx.Int("el-concurrency")
+	}
+	// notification rules
+	if ctx.IsSet("notification-rules") {
+		c.NotificationRules = ctx.String("notification-rules")
+	}
+	if ctx.IsSet("notification-webhook") {
+		c.NotificationWebhook = ctx.String("notification-webhook")
+	}
+	// blocks of the branch analyzed in branch mode
+	if ctx.IsSet("branch-roots") {
+		c.BranchRoots = ctx.String("branch-roots")
+	}
+	// auth and custom headers for hosted nodes
+	if ctx.IsSet("bn-auth") {
+		c.BnAuth = ctx.String("bn-auth")
+	}
+	if ctx.IsSet("bn-headers") {
+		c.BnHeaders = ctx.String("bn-headers")
+	}
+	if ctx.IsSet("el-auth") {
+		c.ElAuth = ctx.String("el-auth")
+	}
+	if ctx.IsSet("el-headers") {
+		c.ElHeaders = ctx.String("el-headers")
+	}
+	// graphql endpoint over the database
+	if ctx.IsSet("graphql") {
+		c.GraphQL = ctx.Bool("graphql")
+	}
+	if ctx.IsSet("graphql-auth") {
+		c.GraphQLAuth = ctx.String("graphql-auth")
+	}
+	// validator registry consistency check
+	if ctx.IsSet("registry-check") {
+		c.RegistryCheck = ctx.Bool("registry-check")
+	}
+	// transactions encoding
+	if ctx.IsSet("tx-storage") {
+		c.TxStorage = ctx.String("tx-storage")
+	}
+	// local era files
+	if ctx.IsSet("era-blocks-dir") {
+		c.EraBlocksDir = ctx.String("era-blocks-dir")
+	}
+	// checks against the beacon node
+	if ctx.IsSet("self-check") {
+		c.SelfCheck = ctx.Bool("self-check")
+	}
+	// shutdown
+	if ctx.IsSet("shutdown-grace") {
+		c.ShutdownGrace = ctx.Int("shutdown-grace")
+	}
+	// database schema
+	if ctx.IsSet("db-schema") {
+		c.DBSchema = ctx.String("db-schema")
+	}
+	// database connection recovery
+	if ctx.IsSet("db-reconnect-timeout") {
+		c.DBReconnectTimeout = ctx.Int("db-reconnect-timeout")
+	}
+	// report of the epochs that failed to process
+	if ctx.IsSet("failure-report") {
+		c.FailureReport = ctx.String("failure-report")
+	}
+	// journal of the processed slots, to resume after a crash
+	if ctx.IsSet("journal") {
+		c.Journal = ctx.String("journal")
+	}
+	// planned maintenance windows of the validators
+	if ctx.IsSet("downtime-windows") {
+		c.DowntimeWindows = ctx.String("downtime-windows")
+	}
+	// balances of the monitored validators at every slot
+	if ctx.IsSet("sample-balances") {
+		c.SampleBalances = ctx.Bool("sample-balances")
+	}
+	// throttling while the beacon node is unhealthy
+	if ctx.IsSet("node-health-interval") {
+		c.NodeHealthInterval = ctx.Int("node-health-interval")
+	}
+	if ctx.IsSet("node-max-latency") {
+		c.NodeMaxLatency = ctx.Int("node-max-latency")
+	}
+	if ctx.IsSet("pause-backfill") {
+		c.PauseBackfill = ctx.Bool("pause-backfill")
+	}
+	// epoch transitions in flight for too long
+	if ctx.IsSet("stuck-epoch-timeout") {
+		c.StuckEpochTimeout = ctx.Int("stuck-epoch-timeout")
+	}
+	if ctx.IsSet("requeue-stuck-epochs") {
+		c.RequeueStuckEpochs = ctx.Bool("requeue-stuck-epochs")
+	}
+	// secondary database written while migrating
+	if ctx.IsSet("db-dual-write-url") {
+		c.DBDualWriteUrl = ctx.String("db-dual-write-url")
+	}
+	// validators proven against the root of every state
+	if ctx.IsSet("state-proof-validators") {
+		c.StateProofValidators = ctx.Int("state-proof-validators")
+	}
+	// resume from the last canonical head on restart
+	if ctx.IsSet("warm-restart") {
+		c.WarmRestart = ctx.Bool("warm-restart")
+	}
+	// databases holding the rewards of each range of validators
+	if ctx.IsSet("rewards-shards") {
+		c.RewardsShards = ctx.String("rewards-shards")
+	}
+	// check the configuration and exit without writing
+	if ctx.IsSet("dry-run") {
+		c.DryRun = ctx.Bool("dry-run")
+	}
+	// epochs between the proposer fairness snapshots
+	if ctx.IsSet("proposer-fairness-epochs") {
+		c.ProposerFairnessEpochs = ctx.Int("proposer-fairness-epochs")
+	}
+	// public key to index table
+	if ctx.IsSet("validator-keys") {
+		c.ValidatorKeys = ctx.Bool("validator-keys")
+	}
+	// finality distances that raise an alert
+	if ctx.IsSet("finality-alert-epochs") {
+		c.FinalityAlertEpochs = ctx.String("finality-alert-epochs")
+	}
+	// epochs downloaded ahead of the processor, at most
+	if ctx.IsSet("prefetch-max-epochs") {
+		c.PrefetchMaxEpochs = ctx.Int("prefetch-max-epochs")
+	}
+	// heap (MB) over which the prefetch window shrinks
+	if ctx.IsSet("prefetch-memory") {
+		c.PrefetchMemory = ctx.Int("prefetch-memory")
+	}
+	// epochs between the pool client diversity snapshots, and covered by each
+	if ctx.IsSet("client-diversity-epochs") {
+		c.ClientDiversityEpochs = ctx.Int("client-diversity-epochs")
+	}
+	// validator pools written to t_eth2_pubkeys
+	if ctx.IsSet("custom-pools") {
+		c.CustomPools = ctx.String("custom-pools")
+	}
+	if ctx.IsSet("custom-pools-refresh") {
+		c.CustomPoolsRefresh = ctx.Int("custom-pools-refresh")
+	}
+	// signing history audited against the chain
+	if ctx.IsSet("slashing-protection") {
+		c.SlashingProtection = ctx.String("slashing-protection")
+	}
+	// epochs measured to select the workers
+	if ctx.IsSet("calibrate-epochs") {
+		c.CalibrateEpochs = ctx.Int("calibrate-epochs")
+	}
+	// neighboring slots whose state replaces one that cannot be decoded
+	if ctx.IsSet("state-fallback-slots") {
+		c.StateFallbackSlots = ctx.Int("state-fallback-slots")
+	}
+	// statsd agent the runtime metrics are also pushed to
+	if ctx.IsSet("statsd-address") {
+		c.StatsdAddress = ctx.String("statsd-address")
+	}
+	if ctx.IsSet("statsd-prefix") {
+		c.StatsdPrefix = ctx.String("statsd-prefix")
+	}
+	if ctx.IsSet("statsd-tags") {
+		c.StatsdTags = ctx.String("statsd-tags")
+	}
+	// percentage of the monitored validators offline in an epoch that raises a mass offline alert
+	if ctx.IsSet("balance-drop-offline-percent") {
+		c.BalanceDropOfflinePct = ctx.Int("balance-drop-offline-percent")
+	}
 }
@@ -0,0 +1,51 @@
+package config
+
+import (
+	cli "github.com/urfave/cli/v2"
+)
+
+type DashboardsConfig struct {
+	LogLevel      string `json:"log-level"`
+	GrafanaUrl    string `json:"grafana-url"`
+	ApiKey        string `json:"api-key"`
+	DatasourceUID string `json:"datasource-uid"`
+	FolderUID     string `json:"folder-uid"`
+	DBUrl         string `json:"db-url"`
+}
+
+func NewDashboardsConfig() *DashboardsConfig {
+	// Return Default values for the dashboards configuration
+	return &DashboardsConfig{
+		LogLevel:      DefaultLogLevel,
+		GrafanaUrl:    DefaultGrafanaUrl,
+		DatasourceUID: DefaultDatasourceUID,
+	}
+}
+
+func (c *DashboardsConfig) Apply(ctx *cli.Context) {
+	// apply to the existing Default configuration the set flags
+	// log level
+	if ctx.IsSet("log-level") {
+		c.LogLevel = ctx.String("log-level")
+	}
+	// grafana url
+	if ctx.IsSet("grafana-url") {
+		c.GrafanaUrl = ctx.String("grafana-url")
+	}
+	// grafana api key
+	if ctx.IsSet("api-key") {
+		c.ApiKey = ctx.String("api-key")
+	}
+	// datasource uid
+	if ctx.IsSet("datasource-uid") {
+		c.DatasourceUID = ctx.String("datasource-uid")
+	}
+	// folder uid
+	if ctx.IsSet("folder-uid") {
+		c.FolderUID = ctx.String("folder-uid")
+	}
+	// db url, only used to create the datasource
+	if ctx.IsSet("db-url") {
+		c.DBUrl = ctx.String("db-url")
+	}
+}
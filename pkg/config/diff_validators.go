@@ -0,0 +1,64 @@
+package config
+
+import (
+	cli "github.com/urfave/cli/v2"
+)
+
+type DiffValidatorsConfig struct {
+	LogLevel          string `json:"log-level"`
+	DBUrl             string `json:"db-url"`
+	RewardsStorage    string `json:"rewards-storage"`
+	BnEndpoint        string `json:"bn-endpoint"`
+	MaxRequestRetries int    `json:"max-request-retries"`
+	EpochA            int64  `json:"epoch-a"`
+	EpochB            int64  `json:"epoch-b"`
+	Format            string `json:"format"`
+}
+
+func NewDiffValidatorsConfig() *DiffValidatorsConfig {
+	// Return Default values for the validator sets comparison, -1 for the epochs not given.
+	// The database is only read if its url is given
+	return &DiffValidatorsConfig{
+		LogLevel:          DefaultLogLevel,
+		RewardsStorage:    DefaultRewardsStorage,
+		BnEndpoint:        DefaultBnEndpoint,
+		MaxRequestRetries: DefaultMaxRequestRetries,
+		EpochA:            -1,
+		EpochB:            -1,
+		Format:            DefaultDiffValidatorsFormat,
+	}
+}
+
+func (c *DiffValidatorsConfig) Apply(ctx *cli.Context) {
+	// apply to the existing Default configuration the set flags
+	// log level
+	if ctx.IsSet("log-level") {
+		c.LogLevel = ctx.String("log-level")
+	}
+	// db url
+	if ctx.IsSet("db-url") {
+		c.DBUrl = ctx.String("db-url")
+	}
+	// where validator rewards are read from
+	if ctx.IsSet("rewards-storage") {
+		c.RewardsStorage = ctx.String("rewards-storage")
+	}
+	// beacon node, for the epochs not in the database
+	if ctx.IsSet("bn-endpoint") {
+		c.BnEndpoint = ctx.String("bn-endpoint")
+	}
+	if ctx.IsSet("max-request-retries") {
+		c.MaxRequestRetries = ctx.Int("max-request-retries")
+	}
+	// epochs to compare
+	if ctx.IsSet("epoch-a") {
+		c.EpochA = int64(ctx.Uint64("epoch-a"))
+	}
+	if ctx.IsSet("epoch-b") {
+		c.EpochB = int64(ctx.Uint64("epoch-b"))
+	}
+	// output format
+	if ctx.IsSet("format") {
+		c.Format = ctx.String("format")
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	cli "github.com/urfave/cli/v2"
+)
+
+type EstimateConfig struct {
+	LogLevel   string `json:"log-level"`
+	Metrics    string `json:"metrics"`
+	Epochs     uint64 `json:"epochs"`
+	Validators uint64 `json:"validators"`
+	DBUrl      string `json:"db-url"`
+	Format     string `json:"format"`
+}
+
+func NewEstimateConfig() *EstimateConfig {
+	// Return Default values for the estimate configuration
+	return &EstimateConfig{
+		LogLevel: DefaultLogLevel,
+		Metrics:  DefaultMetrics,
+		Format:   DefaultEstimateFormat,
+	}
+}
+
+func (c *EstimateConfig) Apply(ctx *cli.Context) {
+	// apply to the existing Default configuration the set flags
+	// log level
+	if ctx.IsSet("log-level") {
+		c.LogLevel = ctx.String("log-level")
+	}
+	// metrics of the planned run
+	if ctx.IsSet("metrics") {
+		c.Metrics = ctx.String("metrics")
+	}
+	// size of the planned run
+	if ctx.IsSet("epochs") {
+		c.Epochs = ctx.Uint64("epochs")
+	}
+	if ctx.IsSet("validators") {
+		c.Validators = ctx.Uint64("validators")
+	}
+	// database to measure the row sizes in, the approximate ones are used if empty
+	if ctx.IsSet("db-url") {
+		c.DBUrl = ctx.String("db-url")
+	}
+	// output format
+	if ctx.IsSet("format") {
+		c.Format = ctx.String("format")
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	cli "github.com/urfave/cli/v2"
+)
+
+type ExitEstimateConfig struct {
+	LogLevel   string `json:"log-level"`
+	DBUrl      string `json:"db-url"`
+	Network    string `json:"network"`
+	Genesis    uint64 `json:"genesis"`
+	Indexes    string `json:"indexes"`
+	PreElectra bool   `json:"pre-electra"`
+}
+
+func NewExitEstimateConfig() *ExitEstimateConfig {
+	// Return Default values for the exit estimate configuration
+	return &ExitEstimateConfig{
+		LogLevel: DefaultLogLevel,
+		DBUrl:    DefaultDBUrl,
+		Network:  DefaultWhenNetwork,
+	}
+}
+
+func (c *ExitEstimateConfig) Apply(ctx *cli.Context) {
+	// apply to the existing Default configuration the set flags
+	// log level
+	if ctx.IsSet("log-level") {
+		c.LogLevel = ctx.String("log-level")
+	}
+	// db url
+	if ctx.IsSet("db-url") {
+		c.DBUrl = ctx.String("db-url")
+	}
+	// network of the genesis time
+	if ctx.IsSet("network") {
+		c.Network = ctx.String("network")
+	}
+	// genesis time, overrides the network
+	if ctx.IsSet("genesis") {
+		c.Genesis = ctx.Uint64("genesis")
+	}
+	// validators to estimate
+	if ctx.IsSet("indexes") {
+		c.Indexes = ctx.String("indexes")
+	}
+	// churn counted in validators instead of balance
+	if ctx.IsSet("pre-electra") {
+		c.PreElectra = ctx.Bool("pre-electra")
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	cli "github.com/urfave/cli/v2"
+)
+
+type ExportConfig struct {
+	LogLevel       string `json:"log-level"`
+	DBUrl          string `json:"db-url"`
+	RewardsStorage string `json:"rewards-storage"`
+	Dataset        string `json:"dataset"`
+	Format         string `json:"format"`
+	Output         string `json:"output"`
+	FromEpoch      uint64 `json:"from-epoch"`
+	ToEpoch        uint64 `json:"to-epoch"`
+	Validators     string `json:"validators"`
+	Pool           string `json:"pool"`
+	Address        string `json:"address"`
+}
+
+func NewExportConfig() *ExportConfig {
+	// Return Default values for the export configuration
+	return &ExportConfig{
+		LogLevel:       DefaultLogLevel,
+		DBUrl:          DefaultDBUrl,
+		RewardsStorage: DefaultRewardsStorage,
+		Format:         DefaultExportFormat,
+		Output:         DefaultExportOutput,
+	}
+}
+
+func (c *ExportConfig) Apply(ctx *cli.Context) {
+	// apply to the existing Default configuration the set flags
+	// log level
+	if ctx.IsSet("log-level") {
+		c.LogLevel = ctx.String("log-level")
+	}
+	// db url
+	if ctx.IsSet("db-url") {
+		c.DBUrl = ctx.String("db-url")
+	}
+	// where validator rewards are read from
+	if ctx.IsSet("rewards-storage") {
+		c.RewardsStorage = ctx.String("rewards-storage")
+	}
+	// dataset to export
+	if ctx.IsSet("dataset") {
+		c.Dataset = ctx.String("dataset")
+	}
+	// output format
+	if ctx.IsSet("format") {
+		c.Format = ctx.String("format")
+	}
+	// output file, standard output if not set
+	if ctx.IsSet("output") {
+		c.Output = ctx.String("output")
+	}
+	// epoch range
+	if ctx.IsSet("from-epoch") {
+		c.FromEpoch = ctx.Uint64("from-epoch")
+	}
+	if ctx.IsSet("to-epoch") {
+		c.ToEpoch = ctx.Uint64("to-epoch")
+	}
+	// filters
+	if ctx.IsSet("validators") {
+		c.Validators = ctx.String("validators")
+	}
+	if ctx.IsSet("pool") {
+		c.Pool = ctx.String("pool")
+	}
+	if ctx.IsSet("address") {
+		c.Address = ctx.String("address")
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	cli "github.com/urfave/cli/v2"
+)
+
+type SchemaConfig struct {
+	LogLevel string `json:"log-level"`
+	Kind     string `json:"kind"`
+	Version  int    `json:"version"`
+}
+
+func NewSchemaConfig() *SchemaConfig {
+	// Return Default values for the schema configuration
+	return &SchemaConfig{
+		LogLevel: DefaultLogLevel,
+		Kind:     DefaultSchemaKind,
+		Version:  DefaultSchemaVersion,
+	}
+}
+
+func (c *SchemaConfig) Apply(ctx *cli.Context) {
+	// apply to the existing Default configuration the set flags
+	// log level
+	if ctx.IsSet("log-level") {
+		c.LogLevel = ctx.String("log-level")
+	}
+	// kind of record
+	if ctx.IsSet("kind") {
+		c.Kind = ctx.String("kind")
+	}
+	// schema version, the current one if not set
+	if ctx.IsSet("version") {
+		c.Version = ctx.Int("version")
+	}
+}
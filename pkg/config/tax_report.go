@@ -0,0 +1,57 @@
+package config
+
+import (
+	cli "github.com/urfave/cli/v2"
+)
+
+type TaxReportConfig struct {
+	LogLevel       string `json:"log-level"`
+	DBUrl          string `json:"db-url"`
+	RewardsStorage string `json:"rewards-storage"`
+	Address        string `json:"address"`
+	Year           int    `json:"year"`
+	Prices         string `json:"prices"`
+	Output         string `json:"output"`
+}
+
+func NewTaxReportConfig() *TaxReportConfig {
+	// Return Default values for the tax report configuration
+	return &TaxReportConfig{
+		LogLevel:       DefaultLogLevel,
+		DBUrl:          DefaultDBUrl,
+		RewardsStorage: DefaultRewardsStorage,
+		Prices:         DefaultTaxReportPrices,
+		Output:         DefaultExportOutput,
+	}
+}
+
+func (c *TaxReportConfig) Apply(ctx *cli.Context) {
+	// apply to the existing Default configuration the set flags
+	// log level
+	if ctx.IsSet("log-level") {
+		c.LogLevel = ctx.String("log-level")
+	}
+	// db url
+	if ctx.IsSet("db-url") {
+		c.DBUrl = ctx.String("db-url")
+	}
+	// where validator rewards are read from
+	if ctx.IsSet("rewards-storage") {
+		c.RewardsStorage = ctx.String("rewards-storage")
+	}
+	// withdrawal address and year of the report
+	if ctx.IsSet("address") {
+		c.Address = ctx.String("address")
+	}
+	if ctx.IsSet("year") {
+		c.Year = ctx.Int("year")
+	}
+	// daily prices, the report is not priced if not set
+	if ctx.IsSet("prices") {
+		c.Prices = ctx.String("prices")
+	}
+	// output file, standard output if not set
+	if ctx.IsSet("output") {
+		c.Output = ctx.String("output")
+	}
+}
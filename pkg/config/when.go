@@ -0,0 +1,61 @@
+package config
+
+import (
+	cli "github.com/urfave/cli/v2"
+)
+
+type WhenConfig struct {
+	LogLevel   string `json:"log-level"`
+	Network    string `json:"network"`
+	Genesis    uint64 `json:"genesis"`
+	Slot       int64  `json:"slot"`
+	Epoch      int64  `json:"epoch"`
+	SyncPeriod int64  `json:"sync-period"`
+	Date       string `json:"date"`
+	Format     string `json:"format"`
+}
+
+func NewWhenConfig() *WhenConfig {
+	// Return Default values for the when configuration, -1 for the points not given
+	return &WhenConfig{
+		LogLevel:   DefaultLogLevel,
+		Network:    DefaultWhenNetwork,
+		Slot:       -1,
+		Epoch:      -1,
+		SyncPeriod: -1,
+		Format:     DefaultWhenFormat,
+	}
+}
+
+func (c *WhenConfig) Apply(ctx *cli.Context) {
+	// apply to the existing Default configuration the set flags
+	// log level
+	if ctx.IsSet("log-level") {
+		c.LogLevel = ctx.String("log-level")
+	}
+	// network of the genesis time
+	if ctx.IsSet("network") {
+		c.Network = ctx.String("network")
+	}
+	// genesis time, overrides the network
+	if ctx.IsSet("genesis") {
+		c.Genesis = ctx.Uint64("genesis")
+	}
+	// point to convert
+	if ctx.IsSet("slot") {
+		c.Slot = int64(ctx.Uint64("slot"))
+	}
+	if ctx.IsSet("epoch") {
+		c.Epoch = int64(ctx.Uint64("epoch"))
+	}
+	if ctx.IsSet("sync-period") {
+		c.SyncPeriod = int64(ctx.Uint64("sync-period"))
+	}
+	if ctx.IsSet("date") {
+		c.Date = ctx.String("date")
+	}
+	// output format
+	if ctx.IsSet("format") {
+		c.Format = ctx.String("format")
+	}
+}
@@ -0,0 +1,181 @@
+package dashboards
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "dashboards"
+	log        = logrus.WithField(
+		"module", moduleName)
+
+	datasourcePlaceholder = "${DS_GOTETH}"
+	datasourceType        = "grafana-clickhouse-datasource"
+	grafanaTimeout        = 30 * time.Second
+
+	//go:embed json/*.json
+	dashboardFiles embed.FS
+)
+
+// Provisioner uploads the prebuilt dashboards to a Grafana instance
+type Provisioner struct {
+	ctx           context.Context
+	grafanaUrl    string
+	apiKey        string
+	datasourceUID string
+	folderUID     string
+	client        *http.Client
+}
+
+func NewProvisioner(ctx context.Context, grafanaUrl string, apiKey string, datasourceUID string, folderUID string) (*Provisioner, error) {
+	if grafanaUrl == "" {
+		return nil, fmt.Errorf("no grafana url provided")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("no grafana api key provided")
+	}
+	return &Provisioner{
+		ctx:           ctx,
+		grafanaUrl:    strings.TrimSuffix(grafanaUrl, "/"),
+		apiKey:        apiKey,
+		datasourceUID: datasourceUID,
+		folderUID:     folderUID,
+		client:        &http.Client{Timeout: grafanaTimeout},
+	}, nil
+}
+
+// EnsureDatasource creates the ClickHouse datasource from the database url if it does not exist yet.
+// Only a not found lookup creates it, any other failure (e.g. a rejected api key) is returned
+func (p *Provisioner) EnsureDatasource(dbUrl string) error {
+	status, body, err := p.request(http.MethodGet, "/api/datasources/uid/"+p.datasourceUID, nil)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case http.StatusOK:
+		log.Infof("datasource %s already exists", p.datasourceUID)
+		return nil
+	case http.StatusNotFound:
+		// create it below
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("not authorized to look up datasource %s, check the grafana api key (status %d): %s", p.datasourceUID, status, body)
+	default:
+		return fmt.Errorf("could not look up datasource %s (status %d): %s", p.datasourceUID, status, body)
+	}
+
+	parsedUrl, err := url.Parse(dbUrl)
+	if err != nil {
+		return fmt.Errorf("could not parse database url: %s", err)
+	}
+	password, _ := parsedUrl.User.Password()
+
+	datasource := map[string]interface{}{
+		"uid":    p.datasourceUID,
+		"name":   p.datasourceUID,
+		"type":   datasourceType,
+		"access": "proxy",
+		"jsonData": map[string]interface{}{
+			"host":            parsedUrl.Hostname(),
+			"port":            parsedUrl.Port(),
+			"protocol":        "native",
+			"username":        parsedUrl.User.Username(),
+			"defaultDatabase": strings.TrimPrefix(parsedUrl.Path, "/"),
+		},
+		"secureJsonData": map[string]interface{}{
+			"password": password,
+		},
+	}
+
+	status, body, err = p.request(http.MethodPost, "/api/datasources", datasource)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("could not create datasource %s (status %d): %s", p.datasourceUID, status, body)
+	}
+	log.Infof("datasource %s created", p.datasourceUID)
+	return nil
+}
+
+// Provision uploads (or overwrites) every embedded dashboard
+func (p *Provisioner) Provision() error {
+	files, err := dashboardFiles.ReadDir("json")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		content, err := dashboardFiles.ReadFile(path.Join("json", file.Name()))
+		if err != nil {
+			return err
+		}
+		// wire the dashboard to the configured datasource
+		content = bytes.ReplaceAll(content, []byte(datasourcePlaceholder), []byte(p.datasourceUID))
+
+		var dashboard map[string]interface{}
+		if err := json.Unmarshal(content, &dashboard); err != nil {
+			return fmt.Errorf("could not parse dashboard %s: %s", file.Name(), err)
+		}
+
+		payload := map[string]interface{}{
+			"dashboard": dashboard,
+			"overwrite": true,
+			"message":   "provisioned by goteth",
+		}
+		if p.folderUID != "" {
+			payload["folderUid"] = p.folderUID
+		}
+
+		status, body, err := p.request(http.MethodPost, "/api/dashboards/db", payload)
+		if err != nil {
+			return err
+		}
+		if status != http.StatusOK {
+			return fmt.Errorf("could not provision dashboard %s (status %d): %s", file.Name(), status, body)
+		}
+		log.Infof("dashboard %s provisioned", dashboard["title"])
+	}
+	return nil
+}
+
+func (p *Provisioner) request(method string, endpoint string, payload interface{}) (int, string, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return 0, "", err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(p.ctx, method, p.grafanaUrl+endpoint, reqBody)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("grafana request %s %s failed: %s", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+	return resp.StatusCode, string(body), nil
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	attestationInclusionsTable       = "t_attestation_inclusions"
+	insertAttestationInclusionsQuery = `
+	INSERT INTO %s (
+		f_val_idx,
+		f_att_slot,
+		f_slot,
+		f_proposer_index,
+		f_inclusion_delay)
+		VALUES`
+)
+
+func attestationInclusionsInput(inclusions []spec.AttestationInclusion) proto.Input {
+	// one object per column
+	var (
+		f_val_idx         proto.ColUInt64
+		f_att_slot        proto.ColUInt64
+		f_slot            proto.ColUInt64
+		f_proposer_index  proto.ColUInt64
+		f_inclusion_delay proto.ColUInt32
+	)
+
+	for _, inclusion := range inclusions {
+		f_val_idx.Append(uint64(inclusion.ValidatorIndex))
+		f_att_slot.Append(uint64(inclusion.AttSlot))
+		f_slot.Append(uint64(inclusion.Slot))
+		f_proposer_index.Append(uint64(inclusion.ProposerIndex))
+		f_inclusion_delay.Append(uint32(inclusion.InclusionDelay))
+	}
+
+	return proto.Input{
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_att_slot", Data: f_att_slot},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_proposer_index", Data: f_proposer_index},
+		{Name: "f_inclusion_delay", Data: f_inclusion_delay},
+	}
+}
+
+func (p *DBService) PersistAttestationInclusions(data []spec.AttestationInclusion) error {
+	persistObj := PersistableObject[spec.AttestationInclusion]{
+		input: attestationInclusionsInput,
+		table: attestationInclusionsTable,
+		query: insertAttestationInclusionsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting attestation inclusions: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	blockArrivalsTable       = "t_block_arrivals"
+	insertBlockArrivalsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_proposer_index,
+		f_arrival_delay_ms,
+		f_late)
+		VALUES`
+)
+
+func blockArrivalsInput(arrivals []spec.BlockArrival) proto.Input {
+	// one object per column
+	var (
+		f_slot             proto.ColUInt64
+		f_proposer_index   proto.ColUInt64
+		f_arrival_delay_ms proto.ColInt64
+		f_late             proto.ColBool
+	)
+
+	for _, arrival := range arrivals {
+		f_slot.Append(uint64(arrival.Slot))
+		f_proposer_index.Append(uint64(arrival.ProposerIndex))
+		f_arrival_delay_ms.Append(arrival.ArrivalDelay)
+		f_late.Append(arrival.Late)
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_proposer_index", Data: f_proposer_index},
+		{Name: "f_arrival_delay_ms", Data: f_arrival_delay_ms},
+		{Name: "f_late", Data: f_late},
+	}
+}
+
+func (p *DBService) PersistBlockArrivals(data []spec.BlockArrival) error {
+	persistObj := PersistableObject[spec.BlockArrival]{
+		input: blockArrivalsInput,
+		table: blockArrivalsTable,
+		query: insertBlockArrivalsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting block arrivals: %s", err.Error())
+	}
+	return err
+}
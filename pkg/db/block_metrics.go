@@ -37,7 +37,15 @@ var (
 		f_snappy_size_bytes,
 		f_compression_time_ms,
 		f_decompression_time_ms,
-		f_payload_size_bytes)
+		f_payload_size_bytes,
+		f_randao_reveal,
+		f_redundant_attestations,
+		f_attestation_duplication_ratio,
+		f_correct_target_attestations,
+		f_wrong_target_attestations,
+		f_block_root,
+		f_parent_root,
+		f_state_root)
 		VALUES`
 	selectLastSlotQuery = `
 		SELECT f_slot
@@ -78,6 +86,16 @@ func blocksInput(blocks []spec.AgnosticBlock) proto.Input {
 		f_snappy_size_bytes     proto.ColFloat32
 		f_compression_time_ms   proto.ColFloat32
 		f_decompression_time_ms proto.ColFloat32
+		f_randao_reveal         proto.ColStr
+
+		f_redundant_attestations        proto.ColUInt64
+		f_attestation_duplication_ratio proto.ColFloat64
+		f_correct_target_attestations   proto.ColUInt64
+		f_wrong_target_attestations     proto.ColUInt64
+
+		f_block_root  proto.ColStr
+		f_parent_root proto.ColStr
+		f_state_root  proto.ColStr
 	)
 
 	for _, block := range blocks {
@@ -91,7 +109,7 @@ func blocksInput(blocks []spec.AgnosticBlock) proto.Input {
 
 		f_proposer_index.Append(uint64(block.ProposerIndex))
 		f_proposed.Append(block.Proposed)
-		f_attestations.Append(uint64(len(block.Attestations)))
+		f_attestations.Append(uint64(block.NumAttestations()))
 		f_deposits.Append(uint64(len(block.Deposits)))
 		f_proposer_slashings.Append(uint64(len(block.ProposerSlashings)))
 		f_attester_slashings.Append(uint64(len(block.AttesterSlashings)))
@@ -114,6 +132,33 @@ func blocksInput(blocks []spec.AgnosticBlock) proto.Input {
 		f_compression_time_ms.Append(float32(utils.DurationToFloat64Millis(block.CompressionTime)))
 		f_decompression_time_ms.Append(float32(utils.DurationToFloat64Millis(block.DecompressionTime)))
 
+		// missed blocks have no reveal
+		randaoReveal := ""
+		if block.Proposed {
+			randaoReveal = block.RandaoReveal.String()
+		}
+		f_randao_reveal.Append(randaoReveal)
+
+		// aggregates whose votes are all in another aggregate of the block
+		f_redundant_attestations.Append(uint64(block.RedundantAttestations()))
+		f_attestation_duplication_ratio.Append(block.AttestationDuplicationRatio())
+
+		// aggregates voting for the canonical checkpoint of their epoch, or another one
+		f_correct_target_attestations.Append(block.CorrectTargetAttestations)
+		f_wrong_target_attestations.Append(block.WrongTargetAttestations)
+
+		// missed blocks have no block roots, but the state at their slot has a root
+		// (unknown when the blocks are built from the state)
+		blockRoot, parentRoot, stateRoot := "", "", ""
+		if block.Proposed {
+			blockRoot, parentRoot = block.Root.String(), block.ParentRoot.String()
+		}
+		if block.StateRoot != (phase0.Root{}) {
+			stateRoot = block.StateRoot.String()
+		}
+		f_block_root.Append(blockRoot)
+		f_parent_root.Append(parentRoot)
+		f_state_root.Append(stateRoot)
 	}
 
 	return proto.Input{
@@ -141,6 +186,14 @@ func blocksInput(blocks []spec.AgnosticBlock) proto.Input {
 		{Name: "f_compression_time_ms", Data: f_compression_time_ms},
 		{Name: "f_decompression_time_ms", Data: f_decompression_time_ms},
 		{Name: "f_payload_size_bytes", Data: f_payload_size_bytes},
+		{Name: "f_randao_reveal", Data: f_randao_reveal},
+		{Name: "f_redundant_attestations", Data: f_redundant_attestations},
+		{Name: "f_attestation_duplication_ratio", Data: f_attestation_duplication_ratio},
+		{Name: "f_correct_target_attestations", Data: f_correct_target_attestations},
+		{Name: "f_wrong_target_attestations", Data: f_wrong_target_attestations},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_parent_root", Data: f_parent_root},
+		{Name: "f_state_root", Data: f_state_root},
 	}
 }
 
@@ -163,6 +216,14 @@ func (s *DBService) DeleteBlockMetrics(slot phase0.Slot) error {
 	if err != nil {
 		return err
 	}
+	err = s.Delete(DeletableObject{
+		query: deleteTransactionLogsQuery,
+		table: transactionLogsTable,
+		args:  []any{slot},
+	})
+	if err != nil {
+		return err
+	}
 	err = s.Delete(DeletableObject{
 		query: deleteWithdrawalsQuery,
 		table: withdrawalsTable,
@@ -179,6 +240,22 @@ func (s *DBService) DeleteBlockMetrics(slot phase0.Slot) error {
 	if err != nil {
 		return err
 	}
+	err = s.Delete(DeletableObject{
+		query: deleteConsolidationRequestsQuery,
+		table: consolidationRequestsTable,
+		args:  []any{slot},
+	})
+	if err != nil {
+		return err
+	}
+	err = s.Delete(DeletableObject{
+		query: deleteVoluntaryExitsQuery,
+		table: voluntaryExitsTable,
+		args:  []any{slot},
+	})
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	chainSplitsTable       = "t_chain_splits"
+	insertChainSplitsQuery = `
+	INSERT INTO %s (
+		f_start_slot,
+		f_detected_slot,
+		f_end_slot,
+		f_peer_endpoint,
+		f_root,
+		f_peer_root)
+		VALUES`
+)
+
+func chainSplitsInput(splits []spec.ChainSplit) proto.Input {
+	// one object per column
+	var (
+		f_start_slot    proto.ColUInt64
+		f_detected_slot proto.ColUInt64
+		f_end_slot      proto.ColUInt64
+		f_peer_endpoint proto.ColStr
+		f_root          proto.ColStr
+		f_peer_root     proto.ColStr
+	)
+
+	for _, split := range splits {
+		f_start_slot.Append(uint64(split.StartSlot))
+		f_detected_slot.Append(uint64(split.DetectedSlot))
+		f_end_slot.Append(uint64(split.EndSlot))
+		f_peer_endpoint.Append(split.PeerEndpoint)
+		f_root.Append(split.Root.String())
+		f_peer_root.Append(split.PeerRoot.String())
+	}
+
+	return proto.Input{
+		{Name: "f_start_slot", Data: f_start_slot},
+		{Name: "f_detected_slot", Data: f_detected_slot},
+		{Name: "f_end_slot", Data: f_end_slot},
+		{Name: "f_peer_endpoint", Data: f_peer_endpoint},
+		{Name: "f_root", Data: f_root},
+		{Name: "f_peer_root", Data: f_peer_root},
+	}
+}
+
+// PersistChainSplits writes the splits, a resolved split replaces the ongoing row
+func (p *DBService) PersistChainSplits(data []spec.ChainSplit) error {
+	persistObj := PersistableObject[spec.ChainSplit]{
+		input: chainSplitsInput,
+		table: chainSplitsTable,
+		query: insertChainSplitsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting chain splits: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	poolClientDiversityTable       = "t_pool_client_diversity"
+	insertPoolClientDiversityQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_window_epochs,
+		f_pool_name,
+		f_client,
+		f_proposals,
+		f_inferred_proposals,
+		f_share)
+		VALUES`
+
+	selectPoolProposalsQuery = `
+		SELECT
+			b.f_slot AS f_slot,
+			b.f_proposer_index AS f_proposer_index,
+			k.f_pool_name AS f_pool_name,
+			b.f_graffiti AS f_graffiti
+		FROM %s AS b FINAL
+		INNER JOIN %s AS k FINAL ON b.f_proposer_index = k.f_val_idx
+		WHERE b.f_proposed AND b.f_epoch >= %d AND b.f_epoch < %d AND k.f_pool_name != ''`
+)
+
+func poolClientDiversityInput(diversity []spec.PoolClientDiversity) proto.Input {
+	// one object per column
+	var (
+		f_epoch              proto.ColUInt64
+		f_window_epochs      proto.ColUInt64
+		f_pool_name          proto.ColStr
+		f_client             proto.ColStr
+		f_proposals          proto.ColUInt64
+		f_inferred_proposals proto.ColUInt64
+		f_share              proto.ColFloat64
+	)
+
+	for _, item := range diversity {
+		f_epoch.Append(uint64(item.Epoch))
+		f_window_epochs.Append(uint64(item.WindowEpochs))
+		f_pool_name.Append(item.PoolName)
+		f_client.Append(item.Client)
+		f_proposals.Append(item.Proposals)
+		f_inferred_proposals.Append(item.InferredProposals)
+		f_share.Append(item.Share)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_window_epochs", Data: f_window_epochs},
+		{Name: "f_pool_name", Data: f_pool_name},
+		{Name: "f_client", Data: f_client},
+		{Name: "f_proposals", Data: f_proposals},
+		{Name: "f_inferred_proposals", Data: f_inferred_proposals},
+		{Name: "f_share", Data: f_share},
+	}
+}
+
+func (p *DBService) PersistPoolClientDiversity(data []spec.PoolClientDiversity) error {
+	persistObj := PersistableObject[spec.PoolClientDiversity]{
+		input: poolClientDiversityInput,
+		table: poolClientDiversityTable,
+		query: insertPoolClientDiversityQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting pool client diversity: %s", err.Error())
+	}
+	return err
+}
+
+// RetrievePoolProposals returns the blocks proposed by the validators of a pool in the given number of epochs
+// before the given one
+func (p *DBService) RetrievePoolProposals(epoch phase0.Epoch, window phase0.Epoch) ([]spec.PoolProposal, error) {
+	var dest []struct {
+		F_slot           uint64 `ch:"f_slot"`
+		F_proposer_index uint64 `ch:"f_proposer_index"`
+		F_pool_name      string `ch:"f_pool_name"`
+		F_graffiti       string `ch:"f_graffiti"`
+	}
+
+	startTime := time.Now()
+	err := p.highSelect(
+		fmt.Sprintf(selectPoolProposalsQuery, blocksTable, poolPubkeysTable, epoch-window, epoch),
+		&dest)
+	if err != nil {
+		return nil, err
+	}
+
+	proposals := make([]spec.PoolProposal, 0, len(dest))
+	for _, row := range dest {
+		proposals = append(proposals, spec.PoolProposal{
+			Slot:          phase0.Slot(row.F_slot),
+			ProposerIndex: phase0.ValidatorIndex(row.F_proposer_index),
+			PoolName:      row.F_pool_name,
+			Graffiti:      row.F_graffiti,
+		})
+	}
+	log.Debugf("pool proposals of epochs %d-%d retrieved, %f seconds", epoch-window, epoch-1, time.Since(startTime).Seconds())
+	return proposals, nil
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	consolidationRequestsTable       = "t_consolidation_requests"
+	insertConsolidationRequestsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_epoch,
+		f_index,
+		f_source_address,
+		f_source_pubkey,
+		f_target_pubkey,
+		f_switch_to_compounding)
+		VALUES`
+
+	deleteConsolidationRequestsQuery = `
+		DELETE FROM %s
+		WHERE f_slot = $1;`
+
+	compoundingBalancesTable       = "t_compounding_balances"
+	insertCompoundingBalancesQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_val_idx,
+		f_effective_balance,
+		f_balance,
+		f_effective_balance_change,
+		f_pending_consolidation)
+		VALUES`
+
+	deleteCompoundingBalancesQuery = `
+		DELETE FROM %s
+		WHERE f_epoch = $1;`
+)
+
+func consolidationRequestsInput(requests []spec.ConsolidationRequest) proto.Input {
+	// one object per column
+	var (
+		f_slot                  proto.ColUInt64
+		f_epoch                 proto.ColUInt64
+		f_index                 proto.ColUInt8
+		f_source_address        proto.ColStr
+		f_source_pubkey         proto.ColStr
+		f_target_pubkey         proto.ColStr
+		f_switch_to_compounding proto.ColBool
+	)
+
+	for _, request := range requests {
+		f_slot.Append(uint64(request.Slot))
+		f_epoch.Append(uint64(request.Epoch))
+		f_index.Append(request.Index)
+		f_source_address.Append(request.SourceAddress.String())
+		f_source_pubkey.Append(request.SourcePubkey.String())
+		f_target_pubkey.Append(request.TargetPubkey.String())
+		f_switch_to_compounding.Append(request.SwitchToCompounding())
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_index", Data: f_index},
+		{Name: "f_source_address", Data: f_source_address},
+		{Name: "f_source_pubkey", Data: f_source_pubkey},
+		{Name: "f_target_pubkey", Data: f_target_pubkey},
+		{Name: "f_switch_to_compounding", Data: f_switch_to_compounding},
+	}
+}
+
+func (p *DBService) PersistConsolidationRequests(data []spec.ConsolidationRequest) error {
+	persistObj := PersistableObject[spec.ConsolidationRequest]{
+		input: consolidationRequestsInput,
+		table: consolidationRequestsTable,
+		query: insertConsolidationRequestsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting consolidation requests: %s", err.Error())
+	}
+	return err
+}
+
+func compoundingBalancesInput(balances []spec.CompoundingBalance) proto.Input {
+	// one object per column
+	var (
+		f_epoch                    proto.ColUInt64
+		f_val_idx                  proto.ColUInt64
+		f_effective_balance        proto.ColUInt64
+		f_balance                  proto.ColUInt64
+		f_effective_balance_change proto.ColInt64
+		f_pending_consolidation    proto.ColBool
+	)
+
+	for _, balance := range balances {
+		f_epoch.Append(uint64(balance.Epoch))
+		f_val_idx.Append(uint64(balance.ValidatorIndex))
+		f_effective_balance.Append(uint64(balance.EffectiveBalance))
+		f_balance.Append(uint64(balance.Balance))
+		f_effective_balance_change.Append(balance.EffectiveBalanceChange)
+		f_pending_consolidation.Append(balance.PendingConsolidation)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_effective_balance", Data: f_effective_balance},
+		{Name: "f_balance", Data: f_balance},
+		{Name: "f_effective_balance_change", Data: f_effective_balance_change},
+		{Name: "f_pending_consolidation", Data: f_pending_consolidation},
+	}
+}
+
+func (p *DBService) PersistCompoundingBalances(data []spec.CompoundingBalance) error {
+	persistObj := PersistableObject[spec.CompoundingBalance]{
+		input: compoundingBalancesInput,
+		table: compoundingBalancesTable,
+		query: insertCompoundingBalancesQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting compounding balances: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	dataQualityNotesTable       = "t_data_quality_notes"
+	insertDataQualityNotesQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_type,
+		f_slot,
+		f_used_slot,
+		f_detail)
+		VALUES`
+)
+
+func dataQualityNotesInput(notes []spec.DataQualityNote) proto.Input {
+	// one object per column
+	var (
+		f_epoch     proto.ColUInt64
+		f_type      proto.ColStr
+		f_slot      proto.ColUInt64
+		f_used_slot proto.ColUInt64
+		f_detail    proto.ColStr
+	)
+
+	for _, note := range notes {
+		f_epoch.Append(uint64(note.Epoch))
+		f_type.Append(string(note.Kind))
+		f_slot.Append(uint64(note.Slot))
+		f_used_slot.Append(uint64(note.UsedAt))
+		f_detail.Append(note.Detail)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_type", Data: f_type},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_used_slot", Data: f_used_slot},
+		{Name: "f_detail", Data: f_detail},
+	}
+}
+
+func (p *DBService) PersistDataQualityNotes(data []spec.DataQualityNote) error {
+	persistObj := PersistableObject[spec.DataQualityNote]{
+		input: dataQualityNotesInput,
+		table: dataQualityNotesTable,
+		query: insertDataQualityNotesQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting data quality notes: %s", err.Error())
+	}
+	return err
+}
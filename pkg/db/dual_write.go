@@ -0,0 +1,227 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/metrics"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	dualWriteMismatchesTable       = "t_dual_write_mismatches"
+	insertDualWriteMismatchesQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_table,
+		f_primary_rows,
+		f_secondary_rows,
+		f_timestamp)
+		VALUES`
+
+	countEpochRowsQuery = `
+		SELECT count()
+		FROM %s FINAL
+		WHERE %s`
+
+	// a failed write to the secondary database waits at most this for it to come back, the primary is not held longer
+	dualWriteReconnectTimeout = 10 * time.Second
+
+	DualWriteErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "dual_write_errors",
+		Help:      "The number of writes that failed in the secondary database",
+	}, []string{"table"})
+	DualWriteMismatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "dual_write_mismatches",
+		Help:      "The number of epochs whose rows differ between the primary and the secondary database",
+	}, []string{"table"})
+)
+
+// DualWriteMismatch is an epoch whose rows in a table differ between the primary and the secondary database
+type DualWriteMismatch struct {
+	Epoch         phase0.Epoch
+	Table         string
+	PrimaryRows   uint64
+	SecondaryRows uint64
+	Timestamp     int64 // unix time of the check
+}
+
+// WithDualWrite writes every batch to a secondary database as well, i.e. while migrating to a new server.
+// The migrations are applied to it on connection, its errors are logged but never fail the writes
+func WithDualWrite(url string) DBServiceOption {
+	return func(s *DBService) error {
+		s.dualWriteUrl = url
+		return nil
+	}
+}
+
+// DualWrite tells whether the batches are written to a secondary database
+func (s *DBService) DualWrite() bool {
+	return s.secondary != nil
+}
+
+// connectSecondary connects to the secondary database with the same storage options
+func (s *DBService) connectSecondary() error {
+	secondary, err := s.connectPeer(s.dualWriteUrl, dualWriteReconnectTimeout)
+	if err != nil {
+		return fmt.Errorf("could not connect to the secondary database: %s", err)
+	}
+	s.secondary = secondary
+	log.Infof("dual write enabled, every batch is written to the secondary database too")
+	return nil
+}
+
+// persistSecondary writes the batch to the secondary database, if any
+func (s *DBService) persistSecondary(query string, table string, input proto.Input, rows int) {
+	if s.secondary == nil {
+		return
+	}
+	if err := s.secondary.Persist(query, table, input, rows); err != nil {
+		DualWriteErrors.WithLabelValues(table).Inc()
+		log.Errorf("error persisting table %s in the secondary database: %s", table, err)
+	}
+}
+
+// deleteSecondary deletes the rows from the secondary database, if any
+func (s *DBService) deleteSecondary(obj DeletableObject) {
+	if s.secondary == nil {
+		return
+	}
+	if err := s.secondary.Delete(obj); err != nil {
+		DualWriteErrors.WithLabelValues(obj.Table()).Inc()
+		log.Errorf("error deleting from table %s in the secondary database: %s", obj.Table(), err)
+	}
+}
+
+// epochRowsFilters returns the tables compared by the consistency check, with the filter of the rows of an epoch
+func (s *DBService) epochRowsFilters() map[string]string {
+	slotRange := "f_slot >= $1 * 32 AND f_slot < ($1 + 1) * 32"
+	rewardsTable := valRewardsTable
+	if s.rewardsDelta {
+		rewardsTable = valRewardsDeltaTable
+	}
+	return map[string]string{
+		epochsTable:         "f_epoch = $1",
+		rewardsTable:        "f_epoch = $1",
+		proposerDutiesTable: "f_proposer_slot >= $1 * 32 AND f_proposer_slot < ($1 + 1) * 32",
+		blocksTable:         slotRange,
+		withdrawalsTable:    slotRange,
+		transactionsTable:   slotRange,
+	}
+}
+
+func (s *DBService) countEpochRows(table string, filter string, epoch phase0.Epoch) (uint64, error) {
+	var count uint64
+	s.highMu.Lock()
+	err := s.highLevelClient.QueryRow(s.ctx, fmt.Sprintf(countEpochRowsQuery, table, filter), epoch).Scan(&count)
+	s.highMu.Unlock()
+	return count, err
+}
+
+// CheckDualWrite compares the rows of the epoch in both databases, persisting the tables that differ in the primary
+func (s *DBService) CheckDualWrite(epoch phase0.Epoch) ([]DualWriteMismatch, error) {
+	mismatches := make([]DualWriteMismatch, 0)
+	if s.secondary == nil {
+		return mismatches, nil
+	}
+
+	checkedAt := time.Now()
+	for table, filter := range s.epochRowsFilters() {
+		primaryRows, err := s.countEpochRows(table, filter, epoch)
+		if err != nil {
+			return mismatches, fmt.Errorf("could not count rows of %s at epoch %d: %s", table, epoch, err)
+		}
+		secondaryRows, err := s.secondary.countEpochRows(table, filter, epoch)
+		if err != nil {
+			return mismatches, fmt.Errorf("could not count rows of %s at epoch %d in the secondary database: %s", table, epoch, err)
+		}
+		if primaryRows == secondaryRows {
+			continue
+		}
+		DualWriteMismatches.WithLabelValues(table).Inc()
+		mismatches = append(mismatches, DualWriteMismatch{
+			Epoch:         epoch,
+			Table:         table,
+			PrimaryRows:   primaryRows,
+			SecondaryRows: secondaryRows,
+			Timestamp:     checkedAt.Unix(),
+		})
+	}
+	if len(mismatches) == 0 {
+		return mismatches, nil
+	}
+
+	persistObj := PersistableObject[DualWriteMismatch]{
+		input: dualWriteMismatchesInput,
+		table: dualWriteMismatchesTable,
+		query: insertDualWriteMismatchesQuery,
+	}
+	for _, item := range mismatches {
+		persistObj.Append(item)
+	}
+	query, table, input, rows := persistObj.ExportPersist()
+	writer := s.acquireWriter()
+	err := s.doWithReconnect(writer, query, input) // only in the primary
+	s.releaseWriter(writer)
+	if err == nil {
+		s.metricsMu.Lock()
+		s.monitorMetrics[table].addNewPersist(rows, time.Since(checkedAt))
+		s.metricsMu.Unlock()
+	}
+	return mismatches, err
+}
+
+func dualWriteMismatchesInput(mismatches []DualWriteMismatch) proto.Input {
+	// one object per column
+	var (
+		f_epoch          proto.ColUInt64
+		f_table          proto.ColStr
+		f_primary_rows   proto.ColUInt64
+		f_secondary_rows proto.ColUInt64
+		f_timestamp      proto.ColUInt64
+	)
+
+	for _, mismatch := range mismatches {
+		f_epoch.Append(uint64(mismatch.Epoch))
+		f_table.Append(mismatch.Table)
+		f_primary_rows.Append(mismatch.PrimaryRows)
+		f_secondary_rows.Append(mismatch.SecondaryRows)
+		f_timestamp.Append(uint64(mismatch.Timestamp))
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_table", Data: f_table},
+		{Name: "f_primary_rows", Data: f_primary_rows},
+		{Name: "f_secondary_rows", Data: f_secondary_rows},
+		{Name: "f_timestamp", Data: f_timestamp},
+	}
+}
+
+func (r *DBService) dualWriteMetric() *metrics.IndvMetrics {
+	initFn := func() error {
+		prometheus.MustRegister(DualWriteErrors)
+		prometheus.MustRegister(DualWriteMismatches)
+		return nil
+	}
+	updateFn := func() (interface{}, error) {
+		return r.DualWrite(), nil
+	}
+	dualWrite, err := metrics.NewIndvMetrics(
+		"dual_write",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		return nil
+	}
+	return dualWrite
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	effectiveBalanceHistogramTable       = "t_effective_balance_histogram"
+	insertEffectiveBalanceHistogramQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_max_effective_balance,
+		f_validators,
+		f_effective_balance)
+		VALUES`
+
+	deleteEffectiveBalanceHistogramQuery = `
+		DELETE FROM %s
+		WHERE f_epoch = $1;`
+)
+
+func effectiveBalanceHistogramInput(buckets []spec.EffectiveBalanceBucket) proto.Input {
+	// one object per column
+	var (
+		f_epoch                 proto.ColUInt64
+		f_max_effective_balance proto.ColUInt64
+		f_validators            proto.ColUInt64
+		f_effective_balance     proto.ColUInt64
+	)
+
+	for _, bucket := range buckets {
+		f_epoch.Append(uint64(bucket.Epoch))
+		f_max_effective_balance.Append(uint64(bucket.MaxEffectiveBalance))
+		f_validators.Append(bucket.Validators)
+		f_effective_balance.Append(uint64(bucket.EffectiveBalance))
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_max_effective_balance", Data: f_max_effective_balance},
+		{Name: "f_validators", Data: f_validators},
+		{Name: "f_effective_balance", Data: f_effective_balance},
+	}
+}
+
+func (p *DBService) PersistEffectiveBalanceHistogram(data []spec.EffectiveBalanceBucket) error {
+	persistObj := PersistableObject[spec.EffectiveBalanceBucket]{
+		input: effectiveBalanceHistogramInput,
+		table: effectiveBalanceHistogramTable,
+		query: insertEffectiveBalanceHistogramQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting effective balance histogram: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	epochCheckpointsTable       = "t_epoch_checkpoints"
+	insertEpochCheckpointsQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_slot,
+		f_justification_bits,
+		f_previous_justified_epoch,
+		f_previous_justified_root,
+		f_current_justified_epoch,
+		f_current_justified_root,
+		f_finalized_epoch,
+		f_finalized_root,
+		f_finality_delay)
+		VALUES`
+
+	deleteEpochCheckpointsQuery = `
+		DELETE FROM %s
+		WHERE f_epoch = $1;
+`
+
+	selectEpochCheckpointsQuery = `
+		SELECT
+			f_epoch,
+			f_slot,
+			f_justification_bits,
+			f_previous_justified_epoch,
+			f_previous_justified_root,
+			f_current_justified_epoch,
+			f_current_justified_root,
+			f_finalized_epoch,
+			f_finalized_root
+		FROM %s FINAL
+		WHERE f_epoch = %d`
+)
+
+func epochCheckpointsInput(checkpoints []spec.EpochCheckpoints) proto.Input {
+	// one object per column
+	var (
+		f_epoch                    proto.ColUInt64
+		f_slot                     proto.ColUInt64
+		f_justification_bits       proto.ColUInt8
+		f_previous_justified_epoch proto.ColUInt64
+		f_previous_justified_root  proto.ColStr
+		f_current_justified_epoch  proto.ColUInt64
+		f_current_justified_root   proto.ColStr
+		f_finalized_epoch          proto.ColUInt64
+		f_finalized_root           proto.ColStr
+		f_finality_delay           proto.ColUInt64
+	)
+
+	for _, checkpoint := range checkpoints {
+		f_epoch.Append(uint64(checkpoint.Epoch))
+		f_slot.Append(uint64(checkpoint.Slot))
+		f_justification_bits.Append(checkpoint.JustificationBits)
+		f_previous_justified_epoch.Append(uint64(checkpoint.PreviousJustifiedCheckpoint.Epoch))
+		f_previous_justified_root.Append(checkpoint.PreviousJustifiedCheckpoint.Root.String())
+		f_current_justified_epoch.Append(uint64(checkpoint.CurrentJustifiedCheckpoint.Epoch))
+		f_current_justified_root.Append(checkpoint.CurrentJustifiedCheckpoint.Root.String())
+		f_finalized_epoch.Append(uint64(checkpoint.FinalizedCheckpoint.Epoch))
+		f_finalized_root.Append(checkpoint.FinalizedCheckpoint.Root.String())
+		f_finality_delay.Append(checkpoint.FinalityDelay())
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_justification_bits", Data: f_justification_bits},
+		{Name: "f_previous_justified_epoch", Data: f_previous_justified_epoch},
+		{Name: "f_previous_justified_root", Data: f_previous_justified_root},
+		{Name: "f_current_justified_epoch", Data: f_current_justified_epoch},
+		{Name: "f_current_justified_root", Data: f_current_justified_root},
+		{Name: "f_finalized_epoch", Data: f_finalized_epoch},
+		{Name: "f_finalized_root", Data: f_finalized_root},
+		{Name: "f_finality_delay", Data: f_finality_delay},
+	}
+}
+
+func (p *DBService) PersistEpochCheckpoints(data []spec.EpochCheckpoints) error {
+	persistObj := PersistableObject[spec.EpochCheckpoints]{
+		input: epochCheckpointsInput,
+		table: epochCheckpointsTable,
+		query: insertEpochCheckpointsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting epoch checkpoints: %s", err.Error())
+	}
+	return err
+}
+
+// RetrieveEpochCheckpoints returns the checkpoints stored for the epoch, and whether there are any
+func (p *DBService) RetrieveEpochCheckpoints(epoch phase0.Epoch) (spec.EpochCheckpoints, bool, error) {
+	var dest []struct {
+		F_epoch                    uint64 `ch:"f_epoch"`
+		F_slot                     uint64 `ch:"f_slot"`
+		F_justification_bits       uint8  `ch:"f_justification_bits"`
+		F_previous_justified_epoch uint64 `ch:"f_previous_justified_epoch"`
+		F_previous_justified_root  string `ch:"f_previous_justified_root"`
+		F_current_justified_epoch  uint64 `ch:"f_current_justified_epoch"`
+		F_current_justified_root   string `ch:"f_current_justified_root"`
+		F_finalized_epoch          uint64 `ch:"f_finalized_epoch"`
+		F_finalized_root           string `ch:"f_finalized_root"`
+	}
+
+	err := p.highSelect(
+		fmt.Sprintf(selectEpochCheckpointsQuery, epochCheckpointsTable, epoch),
+		&dest)
+	if err != nil || len(dest) == 0 {
+		return spec.EpochCheckpoints{}, false, err
+	}
+
+	row := dest[0]
+	checkpoints := spec.EpochCheckpoints{
+		Epoch:             phase0.Epoch(row.F_epoch),
+		Slot:              phase0.Slot(row.F_slot),
+		JustificationBits: row.F_justification_bits,
+	}
+	for _, item := range []struct {
+		checkpoint *phase0.Checkpoint
+		epoch      uint64
+		root       string
+	}{
+		{&checkpoints.PreviousJustifiedCheckpoint, row.F_previous_justified_epoch, row.F_previous_justified_root},
+		{&checkpoints.CurrentJustifiedCheckpoint, row.F_current_justified_epoch, row.F_current_justified_root},
+		{&checkpoints.FinalizedCheckpoint, row.F_finalized_epoch, row.F_finalized_root},
+	} {
+		decoded, err := hex.DecodeString(strings.TrimPrefix(item.root, "0x"))
+		if err != nil || len(decoded) != len(phase0.Root{}) {
+			return spec.EpochCheckpoints{}, false, fmt.Errorf("invalid checkpoint root %s stored at epoch %d", item.root, epoch)
+		}
+		item.checkpoint.Epoch = phase0.Epoch(item.epoch)
+		copy(item.checkpoint.Root[:], decoded)
+	}
+	return checkpoints, true, nil
+}
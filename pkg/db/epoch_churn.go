@@ -0,0 +1,87 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	epochChurnTable       = "t_epoch_churn"
+	insertEpochChurnQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_churn_limit,
+		f_activation_churn,
+		f_exit_churn,
+		f_consolidation_churn_limit,
+		f_consolidation_churn,
+		f_activation_utilization,
+		f_exit_utilization,
+		f_consolidation_utilization,
+		f_pending_deposits,
+		f_exit_queue_epochs)
+		VALUES`
+)
+
+func epochChurnInput(churns []spec.EpochChurn) proto.Input {
+	// one object per column
+	var (
+		f_epoch                     proto.ColUInt64
+		f_churn_limit               proto.ColUInt64
+		f_activation_churn          proto.ColUInt64
+		f_exit_churn                proto.ColUInt64
+		f_consolidation_churn_limit proto.ColUInt64
+		f_consolidation_churn       proto.ColUInt64
+		f_activation_utilization    proto.ColFloat64
+		f_exit_utilization          proto.ColFloat64
+		f_consolidation_utilization proto.ColFloat64
+		f_pending_deposits          proto.ColUInt64
+		f_exit_queue_epochs         proto.ColUInt64
+	)
+
+	for _, churn := range churns {
+		f_epoch.Append(uint64(churn.Epoch))
+		f_churn_limit.Append(uint64(churn.ChurnLimit))
+		f_activation_churn.Append(uint64(churn.ActivationChurn))
+		f_exit_churn.Append(uint64(churn.ExitChurn))
+		f_consolidation_churn_limit.Append(uint64(churn.ConsolidationChurnLimit))
+		f_consolidation_churn.Append(uint64(churn.ConsolidationChurn))
+		f_activation_utilization.Append(churn.ActivationUtilization())
+		f_exit_utilization.Append(churn.ExitUtilization())
+		f_consolidation_utilization.Append(churn.ConsolidationUtilization())
+		f_pending_deposits.Append(uint64(churn.PendingDeposits))
+		f_exit_queue_epochs.Append(churn.ExitQueueEpochs)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_churn_limit", Data: f_churn_limit},
+		{Name: "f_activation_churn", Data: f_activation_churn},
+		{Name: "f_exit_churn", Data: f_exit_churn},
+		{Name: "f_consolidation_churn_limit", Data: f_consolidation_churn_limit},
+		{Name: "f_consolidation_churn", Data: f_consolidation_churn},
+		{Name: "f_activation_utilization", Data: f_activation_utilization},
+		{Name: "f_exit_utilization", Data: f_exit_utilization},
+		{Name: "f_consolidation_utilization", Data: f_consolidation_utilization},
+		{Name: "f_pending_deposits", Data: f_pending_deposits},
+		{Name: "f_exit_queue_epochs", Data: f_exit_queue_epochs},
+	}
+}
+
+func (p *DBService) PersistEpochChurn(data []spec.EpochChurn) error {
+	persistObj := PersistableObject[spec.EpochChurn]{
+		input: epochChurnInput,
+		table: epochChurnTable,
+		query: insertEpochChurnQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting epoch churn: %s", err.Error())
+	}
+	return err
+}
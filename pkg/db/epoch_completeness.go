@@ -0,0 +1,55 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	epochCompletenessTable       = "t_epoch_completeness"
+	insertEpochCompletenessQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_family,
+		f_complete)
+		VALUES`
+)
+
+func epochCompletenessInput(completeness []spec.EpochCompleteness) proto.Input {
+	// one object per column
+	var (
+		f_epoch    proto.ColUInt64
+		f_family   proto.ColStr
+		f_complete proto.ColBool
+	)
+
+	for _, item := range completeness {
+		f_epoch.Append(uint64(item.Epoch))
+		f_family.Append(string(item.Family))
+		f_complete.Append(item.Complete)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_family", Data: f_family},
+		{Name: "f_complete", Data: f_complete},
+	}
+}
+
+func (p *DBService) PersistEpochCompleteness(data []spec.EpochCompleteness) error {
+	persistObj := PersistableObject[spec.EpochCompleteness]{
+		input: epochCompletenessInput,
+		table: epochCompletenessTable,
+		query: insertEpochCompletenessQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting epoch completeness: %s", err.Error())
+	}
+	return err
+}
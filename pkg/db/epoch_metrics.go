@@ -22,6 +22,7 @@ var (
 		f_source_att_effective_balance_eth,
 		f_target_att_effective_balance_eth,
 		f_head_att_effective_balance_eth,
+		f_any_att_effective_balance_eth,
 		f_total_effective_balance_eth,
 		f_missing_source,
 		f_missing_target,
@@ -37,7 +38,32 @@ var (
 		f_withdrawals_num,
 		f_total_withdrawals_amount,
 		f_new_proposer_slashings,
-		f_new_attester_slashings	
+		f_new_attester_slashings,
+		f_rewards_median,
+		f_rewards_p5,
+		f_rewards_p95,
+		f_rewards_gini,
+		f_num_votes_seen,
+		f_vote_latency_mean_ms,
+		f_vote_latency_p95_ms,
+		f_committee_count,
+		f_avg_committee_size,
+		f_target_committee_size,
+		f_attester_seed,
+		f_blobs_num,
+		f_avg_blobs_per_block,
+		f_blob_gas_used,
+		f_target_blob_gas,
+		f_blob_utilization,
+		f_deposits_included,
+		f_voluntary_exits_included,
+		f_attester_slashings_included,
+		f_proposer_slashings_included,
+		f_state_substituted,
+		f_deposit_requests_included,
+		f_exit_requests_included,
+		f_partial_withdrawal_requests_included,
+		f_consolidation_requests_included
 		)
 		VALUES`
 
@@ -66,6 +92,7 @@ func epochsInput(epochs []spec.Epoch) proto.Input {
 		f_source_att_effective_balance_eth proto.ColUInt64
 		f_target_att_effective_balance_eth proto.ColUInt64
 		f_head_att_effective_balance_eth   proto.ColUInt64
+		f_any_att_effective_balance_eth    proto.ColUInt64
 		f_total_effective_balance_eth      proto.ColUInt64
 		f_missing_source                   proto.ColUInt64
 		f_missing_target                   proto.ColUInt64
@@ -82,6 +109,32 @@ func epochsInput(epochs []spec.Epoch) proto.Input {
 		f_total_withdrawals_amount         proto.ColUInt64
 		f_new_proposer_slashings           proto.ColUInt64
 		f_new_attester_slashings           proto.ColUInt64
+		f_rewards_median                   proto.ColInt64
+		f_rewards_p5                       proto.ColInt64
+		f_rewards_p95                      proto.ColInt64
+		f_rewards_gini                     proto.ColFloat64
+		f_num_votes_seen                   proto.ColUInt64
+		f_vote_latency_mean_ms             proto.ColFloat64
+		f_vote_latency_p95_ms              proto.ColInt64
+		f_committee_count                  proto.ColUInt64
+		f_avg_committee_size               proto.ColFloat64
+		f_target_committee_size            proto.ColUInt64
+		f_attester_seed                    proto.ColStr
+		f_blobs_num                        proto.ColUInt64
+		f_avg_blobs_per_block              proto.ColFloat64
+		f_blob_gas_used                    proto.ColUInt64
+		f_target_blob_gas                  proto.ColUInt64
+		f_blob_utilization                 proto.ColFloat64
+		f_deposits_included                proto.ColUInt64
+		f_voluntary_exits_included         proto.ColUInt64
+		f_attester_slashings_included      proto.ColUInt64
+		f_proposer_slashings_included      proto.ColUInt64
+		f_state_substituted                proto.ColBool
+
+		f_deposit_requests_included            proto.ColUInt64
+		f_exit_requests_included               proto.ColUInt64
+		f_partial_withdrawal_requests_included proto.ColUInt64
+		f_consolidation_requests_included      proto.ColUInt64
 	)
 
 	for _, epoch := range epochs {
@@ -95,6 +148,7 @@ func epochsInput(epochs []spec.Epoch) proto.Input {
 		f_source_att_effective_balance_eth.Append(uint64(epoch.SourceAttEffectiveBalance))
 		f_target_att_effective_balance_eth.Append(uint64(epoch.TargetAttEffectiveBalance))
 		f_head_att_effective_balance_eth.Append(uint64(epoch.HeadAttEffectiveBalance))
+		f_any_att_effective_balance_eth.Append(uint64(epoch.AnyAttEffectiveBalance))
 		f_total_effective_balance_eth.Append(uint64(epoch.TotalEffectiveBalance))
 		f_missing_source.Append(uint64(epoch.MissingSource))
 		f_missing_target.Append(uint64(epoch.MissingTarget))
@@ -111,6 +165,31 @@ func epochsInput(epochs []spec.Epoch) proto.Input {
 		f_total_withdrawals_amount.Append(uint64(epoch.TotalWithdrawalsAmount))
 		f_new_proposer_slashings.Append(uint64(epoch.NewProposerSlashings))
 		f_new_attester_slashings.Append(uint64(epoch.NewAttesterSlashings))
+		f_rewards_median.Append(epoch.RewardsMedian)
+		f_rewards_p5.Append(epoch.RewardsP5)
+		f_rewards_p95.Append(epoch.RewardsP95)
+		f_rewards_gini.Append(epoch.RewardsGini)
+		f_num_votes_seen.Append(uint64(epoch.NumVotesSeen))
+		f_vote_latency_mean_ms.Append(epoch.VoteLatencyMean)
+		f_vote_latency_p95_ms.Append(epoch.VoteLatencyP95)
+		f_committee_count.Append(uint64(epoch.CommitteeCount))
+		f_avg_committee_size.Append(epoch.AvgCommitteeSize)
+		f_target_committee_size.Append(uint64(epoch.TargetCommitteeSize))
+		f_attester_seed.Append(epoch.AttesterSeed.String())
+		f_blobs_num.Append(uint64(epoch.BlobsNum))
+		f_avg_blobs_per_block.Append(epoch.AvgBlobsPerBlock)
+		f_blob_gas_used.Append(epoch.BlobGasUsed)
+		f_target_blob_gas.Append(epoch.TargetBlobGas)
+		f_blob_utilization.Append(epoch.BlobUtilization)
+		f_deposits_included.Append(uint64(epoch.DepositsIncluded))
+		f_voluntary_exits_included.Append(uint64(epoch.VoluntaryExitsIncluded))
+		f_attester_slashings_included.Append(uint64(epoch.AttesterSlashingsIncluded))
+		f_proposer_slashings_included.Append(uint64(epoch.ProposerSlashingsIncluded))
+		f_state_substituted.Append(epoch.StateSubstituted)
+		f_deposit_requests_included.Append(uint64(epoch.DepositRequestsIncluded))
+		f_exit_requests_included.Append(uint64(epoch.ExitRequestsIncluded))
+		f_partial_withdrawal_requests_included.Append(uint64(epoch.PartialWithdrawalsIncluded))
+		f_consolidation_requests_included.Append(uint64(epoch.ConsolidationsIncluded))
 	}
 
 	return proto.Input{
@@ -124,6 +203,7 @@ func epochsInput(epochs []spec.Epoch) proto.Input {
 		{Name: "f_source_att_effective_balance_eth", Data: f_source_att_effective_balance_eth},
 		{Name: "f_target_att_effective_balance_eth", Data: f_target_att_effective_balance_eth},
 		{Name: "f_head_att_effective_balance_eth", Data: f_head_att_effective_balance_eth},
+		{Name: "f_any_att_effective_balance_eth", Data: f_any_att_effective_balance_eth},
 		{Name: "f_total_effective_balance_eth", Data: f_total_effective_balance_eth},
 		{Name: "f_missing_source", Data: f_missing_source},
 		{Name: "f_missing_target", Data: f_missing_target},
@@ -140,6 +220,31 @@ func epochsInput(epochs []spec.Epoch) proto.Input {
 		{Name: "f_total_withdrawals_amount", Data: f_total_withdrawals_amount},
 		{Name: "f_new_proposer_slashings", Data: f_new_proposer_slashings},
 		{Name: "f_new_attester_slashings", Data: f_new_attester_slashings},
+		{Name: "f_rewards_median", Data: f_rewards_median},
+		{Name: "f_rewards_p5", Data: f_rewards_p5},
+		{Name: "f_rewards_p95", Data: f_rewards_p95},
+		{Name: "f_rewards_gini", Data: f_rewards_gini},
+		{Name: "f_num_votes_seen", Data: f_num_votes_seen},
+		{Name: "f_vote_latency_mean_ms", Data: f_vote_latency_mean_ms},
+		{Name: "f_vote_latency_p95_ms", Data: f_vote_latency_p95_ms},
+		{Name: "f_committee_count", Data: f_committee_count},
+		{Name: "f_avg_committee_size", Data: f_avg_committee_size},
+		{Name: "f_target_committee_size", Data: f_target_committee_size},
+		{Name: "f_attester_seed", Data: f_attester_seed},
+		{Name: "f_blobs_num", Data: f_blobs_num},
+		{Name: "f_avg_blobs_per_block", Data: f_avg_blobs_per_block},
+		{Name: "f_blob_gas_used", Data: f_blob_gas_used},
+		{Name: "f_target_blob_gas", Data: f_target_blob_gas},
+		{Name: "f_blob_utilization", Data: f_blob_utilization},
+		{Name: "f_deposits_included", Data: f_deposits_included},
+		{Name: "f_voluntary_exits_included", Data: f_voluntary_exits_included},
+		{Name: "f_attester_slashings_included", Data: f_attester_slashings_included},
+		{Name: "f_proposer_slashings_included", Data: f_proposer_slashings_included},
+		{Name: "f_state_substituted", Data: f_state_substituted},
+		{Name: "f_deposit_requests_included", Data: f_deposit_requests_included},
+		{Name: "f_exit_requests_included", Data: f_exit_requests_included},
+		{Name: "f_partial_withdrawal_requests_included", Data: f_partial_withdrawal_requests_included},
+		{Name: "f_consolidation_requests_included", Data: f_consolidation_requests_included},
 	}
 }
 
@@ -211,6 +316,108 @@ func (s *DBService) DeleteStateMetrics(epoch phase0.Epoch) error {
 		return err
 	}
 
+	// checkpoints are written using nextState
+	err = s.Delete(DeletableObject{
+		query: deleteEpochCheckpointsQuery,
+		table: epochCheckpointsTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+
+	// randao mixes are written using nextState, like the checkpoints
+	err = s.Delete(DeletableObject{
+		query: deleteEpochsQuery,
+		table: epochRandaoTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+
+	// compounding balances are written using nextState
+	err = s.Delete(DeletableObject{
+		query: deleteCompoundingBalancesQuery,
+		table: compoundingBalancesTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+
+	// effective balance histograms are written using nextState, like the compounding balances
+	err = s.Delete(DeletableObject{
+		query: deleteEffectiveBalanceHistogramQuery,
+		table: effectiveBalanceHistogramTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+
+	// epoch churn is written using nextState, like the randao mixes
+	err = s.Delete(DeletableObject{
+		query: deleteEpochsQuery,
+		table: epochChurnTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+
+	// validator status anomalies are written at currentState, like the epochs
+	for _, anomaliesEpoch := range []phase0.Epoch{epoch - 1, epoch} {
+		err = s.Delete(DeletableObject{
+			query: deleteEpochsQuery,
+			table: valStatusAnomaliesTable,
+			args:  []any{anomaliesEpoch},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// equivocations are written using the blocks of nextState
+	err = s.Delete(DeletableObject{
+		query: deleteEquivocationsInEpochQuery,
+		table: equivocationsTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+
+	// slashing protection anomalies are written using the blocks of nextState, like the equivocations
+	err = s.Delete(DeletableObject{
+		query: deleteEquivocationsInEpochQuery,
+		table: protectionAnomaliesTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+
+	// attestation inclusions are written using the blocks of nextState, like the equivocations
+	err = s.Delete(DeletableObject{
+		query: deleteEquivocationsInEpochQuery,
+		table: attestationInclusionsTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+
+	// data quality notes are written using nextState
+	err = s.Delete(DeletableObject{
+		query: deleteEpochsQuery,
+		table: dataQualityNotesTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+
 	// valRewards are written at nextState using prevState, currentState and nextState
 	err = s.Delete(DeletableObject{
 		query: deleteValidatorRewardsInEpochQuery,
@@ -236,6 +443,20 @@ func (s *DBService) DeleteStateMetrics(epoch phase0.Epoch) error {
 	if err != nil {
 		return err
 	}
+
+	// the same epochs in case rewards are delta-encoded
+	if s.rewardsDelta {
+		for _, rewardsEpoch := range []phase0.Epoch{epoch + 2, epoch + 1, epoch} {
+			err = s.Delete(DeletableObject{
+				query: deleteValidatorRewardsInEpochQuery,
+				table: valRewardsDeltaTable,
+				args:  []any{rewardsEpoch},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 
 }
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	epochRandaoTable       = "t_epoch_randao"
+	insertEpochRandaoQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_slot,
+		f_randao_mix,
+		f_proposers_epoch,
+		f_last_reveal_slot,
+		f_last_reveal_proposer,
+		f_tail_missed_slots,
+		f_tail_proposer_slots)
+		VALUES`
+)
+
+func epochRandaoInput(randaos []spec.EpochRandao) proto.Input {
+	// one object per column
+	var (
+		f_epoch                proto.ColUInt64
+		f_slot                 proto.ColUInt64
+		f_randao_mix           proto.ColStr
+		f_proposers_epoch      proto.ColUInt64
+		f_last_reveal_slot     proto.ColUInt64
+		f_last_reveal_proposer proto.ColUInt64
+		f_tail_missed_slots    proto.ColUInt64
+		f_tail_proposer_slots  proto.ColUInt64
+	)
+
+	for _, randao := range randaos {
+		f_epoch.Append(uint64(randao.Epoch))
+		f_slot.Append(uint64(randao.Slot))
+		f_randao_mix.Append(randao.RandaoMix.String())
+		f_proposers_epoch.Append(uint64(randao.ProposersEpoch))
+		f_last_reveal_slot.Append(uint64(randao.LastRevealSlot))
+		f_last_reveal_proposer.Append(uint64(randao.LastRevealProposer))
+		f_tail_missed_slots.Append(randao.TailMissedSlots)
+		f_tail_proposer_slots.Append(randao.TailProposerSlots)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_randao_mix", Data: f_randao_mix},
+		{Name: "f_proposers_epoch", Data: f_proposers_epoch},
+		{Name: "f_last_reveal_slot", Data: f_last_reveal_slot},
+		{Name: "f_last_reveal_proposer", Data: f_last_reveal_proposer},
+		{Name: "f_tail_missed_slots", Data: f_tail_missed_slots},
+		{Name: "f_tail_proposer_slots", Data: f_tail_proposer_slots},
+	}
+}
+
+func (p *DBService) PersistEpochRandao(data []spec.EpochRandao) error {
+	persistObj := PersistableObject[spec.EpochRandao]{
+		input: epochRandaoInput,
+		table: epochRandaoTable,
+		query: insertEpochRandaoQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting epoch randao: %s", err.Error())
+	}
+	return err
+}
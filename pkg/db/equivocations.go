@@ -0,0 +1,88 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	equivocationsTable       = "t_equivocations"
+	insertEquivocationsQuery = `
+	INSERT INTO %s (
+		f_val_idx,
+		f_type,
+		f_slot,
+		f_included,
+		f_vote1_source_epoch,
+		f_vote1_target_epoch,
+		f_vote1_root,
+		f_vote2_source_epoch,
+		f_vote2_target_epoch,
+		f_vote2_root)
+		VALUES`
+
+	deleteEquivocationsInEpochQuery = `
+		DELETE FROM %s
+		WHERE toUInt64(f_slot / 32) = $1;
+`
+)
+
+func equivocationsInput(equivocations []spec.Equivocation) proto.Input {
+	// one object per column
+	var (
+		f_val_idx            proto.ColUInt64
+		f_type               proto.ColStr
+		f_slot               proto.ColUInt64
+		f_included           proto.ColBool
+		f_vote1_source_epoch proto.ColUInt64
+		f_vote1_target_epoch proto.ColUInt64
+		f_vote1_root         proto.ColStr
+		f_vote2_source_epoch proto.ColUInt64
+		f_vote2_target_epoch proto.ColUInt64
+		f_vote2_root         proto.ColStr
+	)
+
+	for _, equivocation := range equivocations {
+		f_val_idx.Append(uint64(equivocation.ValidatorIndex))
+		f_type.Append(string(equivocation.Kind))
+		f_slot.Append(uint64(equivocation.Slot))
+		f_included.Append(equivocation.Included)
+		f_vote1_source_epoch.Append(uint64(equivocation.Vote1.SourceEpoch))
+		f_vote1_target_epoch.Append(uint64(equivocation.Vote1.TargetEpoch))
+		f_vote1_root.Append(equivocation.Vote1.Root.String())
+		f_vote2_source_epoch.Append(uint64(equivocation.Vote2.SourceEpoch))
+		f_vote2_target_epoch.Append(uint64(equivocation.Vote2.TargetEpoch))
+		f_vote2_root.Append(equivocation.Vote2.Root.String())
+	}
+
+	return proto.Input{
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_type", Data: f_type},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_included", Data: f_included},
+		{Name: "f_vote1_source_epoch", Data: f_vote1_source_epoch},
+		{Name: "f_vote1_target_epoch", Data: f_vote1_target_epoch},
+		{Name: "f_vote1_root", Data: f_vote1_root},
+		{Name: "f_vote2_source_epoch", Data: f_vote2_source_epoch},
+		{Name: "f_vote2_target_epoch", Data: f_vote2_target_epoch},
+		{Name: "f_vote2_root", Data: f_vote2_root},
+	}
+}
+
+func (p *DBService) PersistEquivocations(data []spec.Equivocation) error {
+	persistObj := PersistableObject[spec.Equivocation]{
+		input: equivocationsInput,
+		table: equivocationsTable,
+		query: insertEquivocationsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting equivocations: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	// approximate compressed size on disk of a row of the largest tables
+	avgRowBytes = map[string]uint64{
+		epochsTable:         400,
+		valRewardsTable:     25,
+		proposerDutiesTable: 20,
+		blocksTable:         300,
+		withdrawalsTable:    30,
+		transactionsTable:   120,
+		blockRewardsTable:   40,
+		poolsTables:         150,
+	}
+
+	// average rows per block of the tables written once per item of a block
+	withdrawalsPerBlock  uint64 = 16
+	transactionsPerBlock uint64 = 150
+
+	// pools with a summary per epoch
+	estimatedPools uint64 = 100
+
+	selectRowBytesQuery = `
+		SELECT
+			table AS f_table,
+			sum(rows) AS f_rows,
+			sum(bytes_on_disk) AS f_bytes
+		FROM system.parts
+		WHERE active AND database = currentDatabase()
+		GROUP BY table`
+)
+
+// TableEstimate is the number of rows and bytes a run is expected to write to a table
+type TableEstimate struct {
+	Table    string
+	Metric   string // metric that writes the table
+	Rows     uint64
+	Bytes    uint64
+	Measured bool // the row size was measured in a database, approximated otherwise
+}
+
+type tableParts struct {
+	Table string `ch:"f_table"`
+	Rows  uint64 `ch:"f_rows"`
+	Bytes uint64 `ch:"f_bytes"`
+}
+
+// MeasureRowBytes returns the average size on disk of a row of the tables of the database that have rows
+func (p *DBService) MeasureRowBytes() (map[string]uint64, error) {
+	var parts []tableParts
+	if err := p.highSelect(selectRowBytesQuery, &parts); err != nil {
+		return nil, fmt.Errorf("could not read the size of the tables: %s", err)
+	}
+	rowBytes := make(map[string]uint64, len(parts))
+	for _, table := range parts {
+		if table.Rows == 0 {
+			continue
+		}
+		rowBytes[table.Table] = max(table.Bytes/table.Rows, 1)
+	}
+	return rowBytes, nil
+}
+
+// EstimateRun returns the rows and bytes the enabled metrics are expected to write to the largest tables
+// when analyzing the given number of epochs of a network with the given number of validators.
+// The row sizes measured in a database, if given, replace the approximate ones
+func EstimateRun(metrics DBMetrics, epochs uint64, validators uint64, measured map[string]uint64) []TableEstimate {
+	estimates := make([]TableEstimate, 0, len(avgRowBytes))
+	add := func(table string, metric string, rows uint64) {
+		estimate := TableEstimate{Table: table, Metric: metric, Rows: rows}
+		rowBytes, ok := measured[table]
+		if !ok {
+			rowBytes = avgRowBytes[table]
+		}
+		estimate.Bytes, estimate.Measured = rows*rowBytes, ok
+		estimates = append(estimates, estimate)
+	}
+
+	if metrics.Epoch {
+		add(epochsTable, "epoch", epochs)
+		add(proposerDutiesTable, "epoch", epochs*spec.SlotsPerEpoch)
+	}
+	if metrics.ValidatorRewards {
+		add(valRewardsTable, "rewards", epochs*validators)
+	}
+	if metrics.CompactRewards() {
+		add(poolsTables, "epoch_aggregates", epochs*estimatedPools)
+	}
+	if metrics.Block {
+		add(blocksTable, "block", epochs*spec.SlotsPerEpoch)
+		add(withdrawalsTable, "block", epochs*spec.SlotsPerEpoch*withdrawalsPerBlock)
+	}
+	if metrics.Transactions {
+		add(transactionsTable, "transactions", epochs*spec.SlotsPerEpoch*transactionsPerBlock)
+	}
+	if metrics.APIRewards {
+		add(blockRewardsTable, "api_rewards", epochs*spec.SlotsPerEpoch)
+	}
+	return estimates
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+type ExportDataset string
+
+const (
+	ValidatorRewardsDataset ExportDataset = "validator-rewards"
+	PoolSummaryDataset      ExportDataset = "pool-summary"
+	WithdrawalsDataset      ExportDataset = "withdrawals"
+	CanonicalRootsDataset   ExportDataset = "canonical-roots"
+)
+
+var (
+	ExportDatasets = []ExportDataset{ValidatorRewardsDataset, PoolSummaryDataset, WithdrawalsDataset, CanonicalRootsDataset}
+
+	exportValidatorRewardsQuery = `
+		SELECT
+			f_val_idx,
+			f_epoch,
+			f_balance_eth,
+			f_reward,
+			f_max_reward,
+			f_max_att_reward,
+			f_max_sync_reward,
+			f_att_slot,
+			f_attestation_included,
+			f_base_reward,
+			f_in_sync_committee,
+			f_missing_source,
+			f_missing_target,
+			f_missing_head,
+			f_status,
+			f_block_api_reward,
+			f_block_experimental_reward,
+			f_inclusion_delay
+		FROM %s
+		WHERE f_epoch >= $1 AND f_epoch <= $2 %s
+		ORDER BY f_val_idx, f_epoch`
+
+	exportPoolSummaryQuery = `
+		SELECT *
+		FROM %s FINAL
+		WHERE f_epoch >= $1 AND f_epoch <= $2 %s
+		ORDER BY f_pool_name, f_epoch`
+
+	exportWithdrawalsQuery = `
+		SELECT
+			f_slot,
+			toUInt64(f_slot / 32) as f_epoch,
+			f_index,
+			f_val_idx,
+			f_address,
+			f_amount
+		FROM %s
+		WHERE f_slot >= $1 AND f_slot <= $2 %s
+		ORDER BY f_slot, f_index`
+
+	exportCanonicalRootsQuery = `
+		SELECT
+			f_slot,
+			f_epoch,
+			f_proposed,
+			f_block_root,
+			f_parent_root,
+			f_state_root
+		FROM %s FINAL
+		WHERE f_slot >= $1 AND f_slot <= $2
+		ORDER BY f_slot`
+)
+
+// ExportParams filter the rows of a dataset, empty filters are not applied
+type ExportParams struct {
+	FromEpoch  phase0.Epoch
+	ToEpoch    phase0.Epoch
+	Validators []phase0.ValidatorIndex // validator-rewards
+	Pool       string                  // pool-summary
+	Address    string                  // withdrawals
+}
+
+// exportQuery builds the query (and its arguments) of the given dataset
+func (p *DBService) exportQuery(dataset ExportDataset, params ExportParams) (string, []any, error) {
+	if params.ToEpoch < params.FromEpoch {
+		return "", nil, fmt.Errorf("the last epoch (%d) is before the first one (%d)", params.ToEpoch, params.FromEpoch)
+	}
+
+	switch dataset {
+	case ValidatorRewardsDataset:
+		filter := ""
+		if len(params.Validators) > 0 {
+			indexes := make([]string, 0, len(params.Validators))
+			for _, valIdx := range params.Validators {
+				indexes = append(indexes, fmt.Sprintf("%d", valIdx))
+			}
+			filter = fmt.Sprintf("AND f_val_idx IN (%s)", strings.Join(indexes, ","))
+		}
+		query := fmt.Sprintf(exportValidatorRewardsQuery, p.valRewardsSource(), filter)
+		return query, []any{params.FromEpoch, params.ToEpoch}, nil
+
+	case PoolSummaryDataset:
+		args := []any{params.FromEpoch, params.ToEpoch}
+		filter := ""
+		if params.Pool != "" {
+			filter = "AND f_pool_name = $3"
+			args = append(args, params.Pool)
+		}
+		return fmt.Sprintf(exportPoolSummaryQuery, poolsTables, filter), args, nil
+
+	case WithdrawalsDataset:
+		firstSlot := phase0.Slot(params.FromEpoch) * spec.SlotsPerEpoch
+		lastSlot := phase0.Slot(params.ToEpoch+1)*spec.SlotsPerEpoch - 1
+		args := []any{firstSlot, lastSlot}
+		filter := ""
+		if params.Address != "" {
+			filter = "AND lower(f_address) = lower($3)"
+			args = append(args, params.Address)
+		}
+		return fmt.Sprintf(exportWithdrawalsQuery, withdrawalsTable, filter), args, nil
+
+	case CanonicalRootsDataset:
+		firstSlot := phase0.Slot(params.FromEpoch) * spec.SlotsPerEpoch
+		lastSlot := phase0.Slot(params.ToEpoch+1)*spec.SlotsPerEpoch - 1
+		return fmt.Sprintf(exportCanonicalRootsQuery, blocksTable), []any{firstSlot, lastSlot}, nil
+
+	default:
+		return "", nil, fmt.Errorf("unknown dataset %s", dataset)
+	}
+}
+
+// Export runs the query of the given dataset, passing the column names and then every row to the given functions
+func (p *DBService) Export(
+	dataset ExportDataset,
+	params ExportParams,
+	onColumns func([]string) error,
+	onRow func([]any) error) (int, error) {
+
+	query, args, err := p.exportQuery(dataset, params)
+	if err != nil {
+		return 0, err
+	}
+
+	startTime := time.Now()
+	count, err := p.QueryRows(query, args, onColumns, onRow)
+	if err != nil {
+		return count, err
+	}
+	log.Infof("exported %d rows of %s in %f seconds", count, dataset, time.Since(startTime).Seconds())
+	return count, nil
+}
+
+// QueryRows runs the given query, passing the column names and then every scanned row to the given functions
+func (p *DBService) QueryRows(
+	query string,
+	args []any,
+	onColumns func([]string) error,
+	onRow func([]any) error) (int, error) {
+
+	p.highMu.Lock()
+	defer p.highMu.Unlock()
+
+	rows, err := p.highLevelClient.Query(p.ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error executing %s: %s", query, err)
+	}
+	defer rows.Close()
+
+	if err := onColumns(rows.Columns()); err != nil {
+		return 0, err
+	}
+	columnTypes := rows.ColumnTypes()
+
+	count := 0
+	for rows.Next() {
+		dest := make([]any, len(columnTypes))
+		for i, columnType := range columnTypes {
+			dest[i] = reflect.New(columnType.ScanType()).Interface()
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return count, err
+		}
+		row := make([]any, len(dest))
+		for i := range dest {
+			row[i] = exportValue(dest[i])
+		}
+		if err := onRow(row); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// exportValue dereferences the scanned value, nil for null ones
+func exportValue(value any) any {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
@@ -1,8 +1,11 @@
 package db
 
 import (
+	"fmt"
+
 	"github.com/ClickHouse/ch-go/proto"
 	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
 var (
@@ -14,6 +17,12 @@ var (
 		f_state_root,
 		f_epoch)
 		VALUES`
+
+	selectLastFinalizedQuery = `
+		SELECT f_epoch
+		FROM %s
+		ORDER BY f_epoch DESC
+		LIMIT 1`
 )
 
 func finalizedInput(checkpoints []api.FinalizedCheckpointEvent) proto.Input {
@@ -55,3 +64,20 @@ func (p *DBService) PersistFinalized(data []api.FinalizedCheckpointEvent) error
 	}
 	return err
 }
+
+// RetrieveLastFinalized returns the epoch of the last finalized checkpoint received in head mode, 0 if none
+func (p *DBService) RetrieveLastFinalized() (phase0.Epoch, error) {
+
+	var dest []struct {
+		F_epoch uint64 `ch:"f_epoch"`
+	}
+
+	err := p.highSelect(
+		fmt.Sprintf(selectLastFinalizedQuery, finalizedTable),
+		&dest)
+
+	if len(dest) > 0 {
+		return phase0.Epoch(dest[0].F_epoch), err
+	}
+	return 0, err
+}
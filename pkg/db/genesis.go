@@ -5,34 +5,46 @@ import (
 	"time"
 
 	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
 )
 
 var (
 	genesisTable       = "t_genesis"
 	insertGenesisQuery = `
 	INSERT INTO %s (
-		f_genesis_time)
+		f_genesis_time,
+		f_seconds_per_slot)
 		VALUES`
 
 	selectGenesisQuery = `
 	SELECT f_genesis_time
 	FROM %s;
+`
+	selectSecondsPerSlotQuery = `
+	SELECT f_seconds_per_slot
+	FROM %s;
 `
 )
 
-func genesisInput(genesis []int64) proto.Input {
-	// one object per column
-	var (
-		f_genesis_time proto.ColUInt64
-	)
-
-	for _, genesis := range genesis {
-		f_genesis_time.Append(uint64(genesis))
-	}
+// genesisInput returns the input of the genesis times, all of them of a chain with the given slot duration
+func genesisInput(secondsPerSlot uint64) Input[[]int64] {
+	return func(genesis []int64) proto.Input {
+		// one object per column
+		var (
+			f_genesis_time     proto.ColUInt64
+			f_seconds_per_slot proto.ColUInt64
+		)
+
+		for _, genesis := range genesis {
+			f_genesis_time.Append(uint64(genesis))
+			f_seconds_per_slot.Append(secondsPerSlot)
+		}
 
-	return proto.Input{
+		return proto.Input{
 
-		{Name: "f_genesis_time", Data: f_genesis_time},
+			{Name: "f_genesis_time", Data: f_genesis_time},
+			{Name: "f_seconds_per_slot", Data: f_seconds_per_slot},
+		}
 	}
 }
 
@@ -53,7 +65,25 @@ func (p *DBService) RetrieveGenesis() (int64, error) {
 
 }
 
-func (p *DBService) InitGenesis(apiGenesis time.Time) error {
+// RetrieveSecondsPerSlot returns the slot duration of the chain in the database, the mainnet one if there is none
+func (p *DBService) RetrieveSecondsPerSlot() (uint64, error) {
+	var dest []struct {
+		F_seconds_per_slot uint64 `ch:"f_seconds_per_slot"`
+	}
+
+	err := p.highSelect(
+		fmt.Sprintf(selectSecondsPerSlotQuery, genesisTable),
+		&dest)
+
+	if len(dest) > 0 && dest[0].F_seconds_per_slot > 0 {
+		return dest[0].F_seconds_per_slot, err
+	}
+	return spec.SlotSeconds, err
+}
+
+// InitGenesis stores the genesis time and the slot duration of the chain, so that the views and commands
+// reading the database can date the slots
+func (p *DBService) InitGenesis(apiGenesis time.Time, secondsPerSlot uint64) error {
 	// Get genesis from the API
 
 	dbGenesis, err := p.RetrieveGenesis()
@@ -64,7 +94,7 @@ func (p *DBService) InitGenesis(apiGenesis time.Time) error {
 
 	if dbGenesis == 0 { // table is empty, probably first time use
 		insertGenesis := PersistableObject[int64]{
-			input: genesisInput,
+			input: genesisInput(secondsPerSlot),
 			table: genesisTable,
 			query: insertGenesisQuery,
 		}
@@ -7,8 +7,11 @@ This file together with the model, has all the needed methods to interact with t
 */
 
 import (
+	"fmt"
+
 	"github.com/ClickHouse/ch-go/proto"
 	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
 // Postgres intregration variables
@@ -24,6 +27,16 @@ var (
 		f_previous_duty_dependent_root,
 		f_arrival_timestamp)
 		VALUES`
+
+	// the last head received at each slot, the earlier ones were replaced by a reorg
+	selectHeadRootsQuery = `
+		SELECT
+			f_slot,
+			argMax(f_block, f_arrival_timestamp) AS f_block
+		FROM %s
+		WHERE f_slot > %d AND f_slot <= %d
+		GROUP BY f_slot
+		ORDER BY f_slot`
 )
 
 func headEventsInput(events []HeadEvent) proto.Input {
@@ -83,3 +96,24 @@ type HeadEvent struct {
 	HeadEvent        api.HeadEvent
 	ArrivalTimestamp int64
 }
+
+// RetrieveHeadRoots returns the root of the last head event received at each slot of the range (from, to]
+func (p *DBService) RetrieveHeadRoots(from phase0.Slot, to phase0.Slot) (map[phase0.Slot]string, error) {
+	var dest []struct {
+		F_slot  uint64 `ch:"f_slot"`
+		F_block string `ch:"f_block"`
+	}
+
+	err := p.highSelect(
+		fmt.Sprintf(selectHeadRootsQuery, headEventsTable, from, to),
+		&dest)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := make(map[phase0.Slot]string, len(dest))
+	for _, row := range dest {
+		roots[phase0.Slot(row.F_slot)] = row.F_block
+	}
+	return roots, nil
+}
@@ -95,6 +95,8 @@ func (p *DBService) Delete(obj DeletableObject) error {
 	p.highMu.Lock()
 	err = p.highLevelClient.Exec(p.ctx, obj.Query(), obj.Args()...)
 	p.highMu.Unlock()
+	p.deleteSecondary(obj)
+	p.deleteRewardsShards(obj)
 
 	if err == nil {
 		log.Infof("query: %s finished in %f seconds", obj.Query(), time.Since(startTime).Seconds())
@@ -12,14 +12,13 @@ import (
 func (s *DBService) ConnectLowLevel() error {
 	ctx := context.Background()
 
-	opts := ParseChUrlIntoOptionsLowLevel(s.connectionUrl)
-	lowLevelConn, err := ch.Dial(ctx, opts)
-	if err == nil {
-		s.lowLevelClient = lowLevelConn
-		err = s.makeMigrations()
+	for i := 0; i < s.writersNum; i++ {
+		if err := s.dialWriter(ctx); err != nil {
+			return err
+		}
 	}
 
-	return err
+	return s.makeMigrations()
 
 }
 
@@ -60,15 +59,14 @@ func (p *DBService) Persist(
 	input proto.Input,
 	rows int) error {
 
+	// the wait for a free connection is not measured, only the insert
+	writer := p.acquireWriter()
 	startTime := time.Now()
-
-	p.lowMu.Lock()
-	err := p.lowLevelClient.Do(p.ctx, ch.Query{
-		Body:  query,
-		Input: input,
-	})
-	p.lowMu.Unlock()
+	err := p.doWithReconnect(writer, query, input)
 	elapsedTime := time.Since(startTime)
+	p.releaseWriter(writer)
+	p.writeTime.Add(int64(elapsedTime))
+	p.persistSecondary(query, table, input, rows)
 
 	if err == nil {
 		log.Debugf("table %s persisted %d rows in %fs", table, rows, elapsedTime.Seconds())
@@ -11,6 +11,12 @@ type DBMetrics struct {
 	ValidatorRewards bool
 	APIRewards       bool
 	Transactions     bool
+	EpochAggregates  bool // validator rewards computed in memory, only the pool summaries are persisted
+}
+
+// CompactRewards returns whether the validator rewards are only persisted as pool aggregates
+func (m DBMetrics) CompactRewards() bool {
+	return m.EpochAggregates && !m.ValidatorRewards
 }
 
 func NewMetrics(input string) (DBMetrics, error) {
@@ -28,6 +34,10 @@ func NewMetrics(input string) (DBMetrics, error) {
 			dbMetrics.ValidatorRewards = true
 			dbMetrics.Epoch = true
 			dbMetrics.Block = true
+		case "epoch_aggregates":
+			dbMetrics.EpochAggregates = true
+			dbMetrics.Epoch = true
+			dbMetrics.Block = true
 		case "api_rewards":
 			dbMetrics.APIRewards = true
 		case "transactions":
@@ -7,10 +7,13 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
+// migrationsDir holds the migrations of the schema, relative to the working directory
+const migrationsDir = "pkg/db/migrations"
+
 func (s *DBService) makeMigrations() error {
 
 	m, err := migrate.New(
-		"file://pkg/db/migrations",
+		"file://"+migrationsDir,
 		s.connectionUrl)
 	if err != nil {
 		log.Error(err.Error())
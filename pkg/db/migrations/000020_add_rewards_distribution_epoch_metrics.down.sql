@@ -0,0 +1,4 @@
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_rewards_median;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_rewards_p5;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_rewards_p95;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_rewards_gini;
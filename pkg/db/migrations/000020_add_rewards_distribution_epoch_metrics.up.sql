@@ -0,0 +1,4 @@
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_rewards_median Int64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_rewards_p5 Int64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_rewards_p95 Int64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_rewards_gini Float64;
@@ -0,0 +1 @@
+ALTER TABLE t_pool_summary DROP COLUMN f_active;
@@ -0,0 +1 @@
+ALTER TABLE t_pool_summary ADD COLUMN f_active Bool;
@@ -0,0 +1 @@
+ALTER TABLE t_validator_rewards_summary ADD COLUMN f_reward_percentile Float32;
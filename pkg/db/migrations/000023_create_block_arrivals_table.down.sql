@@ -0,0 +1,3 @@
+DROP VIEW IF EXISTS v_late_blocks_per_pool;
+
+DROP TABLE IF EXISTS t_block_arrivals;
@@ -0,0 +1,21 @@
+CREATE TABLE t_block_arrivals
+(
+    f_slot               UInt64,
+    f_proposer_index     UInt64,
+    f_arrival_delay_ms   Int64,
+    f_late               Bool
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_slot);
+
+CREATE VIEW IF NOT EXISTS v_late_blocks_per_pool AS
+SELECT
+    t_eth2_pubkeys.f_pool_name AS f_pool_name,
+    t_block_arrivals.f_proposer_index AS f_proposer_index,
+    COUNT(*) AS f_blocks,
+    COUNT(CASE WHEN t_block_arrivals.f_late = TRUE THEN 1 ELSE null END) AS f_late_blocks,
+    AVG(t_block_arrivals.f_arrival_delay_ms) AS f_avg_arrival_delay_ms
+FROM t_block_arrivals
+LEFT JOIN t_eth2_pubkeys
+    ON t_block_arrivals.f_proposer_index = t_eth2_pubkeys.f_val_idx
+GROUP BY t_eth2_pubkeys.f_pool_name, t_block_arrivals.f_proposer_index;
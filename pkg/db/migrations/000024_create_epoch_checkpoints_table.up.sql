@@ -0,0 +1,15 @@
+CREATE TABLE t_epoch_checkpoints
+(
+    f_epoch                    UInt64,
+    f_slot                     UInt64,
+    f_justification_bits       UInt8,
+    f_previous_justified_epoch UInt64,
+    f_previous_justified_root  String,
+    f_current_justified_epoch  UInt64,
+    f_current_justified_root   String,
+    f_finalized_epoch          UInt64,
+    f_finalized_root           String,
+    f_finality_delay           UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch);
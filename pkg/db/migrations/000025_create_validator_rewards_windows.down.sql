@@ -0,0 +1,7 @@
+DROP VIEW IF EXISTS v_validator_rewards_30d;
+
+DROP VIEW IF EXISTS v_validator_rewards_7d;
+
+DROP VIEW IF EXISTS v_validator_rewards_1d;
+
+DROP TABLE IF EXISTS t_validator_rewards_buckets;
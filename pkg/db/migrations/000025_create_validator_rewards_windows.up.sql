@@ -0,0 +1,76 @@
+CREATE TABLE t_validator_rewards_buckets
+(
+    f_val_idx                 UInt64,
+    f_bucket                  UInt64,
+    f_start_epoch             UInt64,
+    f_end_epoch               UInt64,
+    f_epochs                  UInt64,
+    f_reward                  Int64,
+    f_max_reward              UInt64,
+    f_attestations_included   UInt64,
+    f_missing_source_count    UInt64,
+    f_missing_target_count    UInt64,
+    f_missing_head_count      UInt64,
+    f_in_sync_committee_count UInt64,
+    f_block_api_reward        UInt64,
+    f_inclusion_delay_sum     UInt64
+)
+ENGINE = ReplacingMergeTree(f_end_epoch)
+ORDER BY (f_bucket, f_val_idx);
+
+CREATE VIEW IF NOT EXISTS v_validator_rewards_1d AS
+SELECT
+    f_val_idx,
+    min(f_start_epoch) AS f_start_epoch,
+    max(f_end_epoch) AS f_end_epoch,
+    sum(f_epochs) AS f_epochs,
+    sum(f_reward) AS f_reward,
+    sum(f_max_reward) AS f_max_reward,
+    sum(f_attestations_included) AS f_attestations_included,
+    sum(f_missing_source_count) AS f_missing_source_count,
+    sum(f_missing_target_count) AS f_missing_target_count,
+    sum(f_missing_head_count) AS f_missing_head_count,
+    sum(f_in_sync_committee_count) AS f_in_sync_committee_count,
+    sum(f_block_api_reward) AS f_block_api_reward,
+    sum(f_inclusion_delay_sum) AS f_inclusion_delay_sum
+FROM t_validator_rewards_buckets FINAL
+WHERE f_bucket + 9 > (SELECT max(f_bucket) FROM t_validator_rewards_buckets)
+GROUP BY f_val_idx;
+
+CREATE VIEW IF NOT EXISTS v_validator_rewards_7d AS
+SELECT
+    f_val_idx,
+    min(f_start_epoch) AS f_start_epoch,
+    max(f_end_epoch) AS f_end_epoch,
+    sum(f_epochs) AS f_epochs,
+    sum(f_reward) AS f_reward,
+    sum(f_max_reward) AS f_max_reward,
+    sum(f_attestations_included) AS f_attestations_included,
+    sum(f_missing_source_count) AS f_missing_source_count,
+    sum(f_missing_target_count) AS f_missing_target_count,
+    sum(f_missing_head_count) AS f_missing_head_count,
+    sum(f_in_sync_committee_count) AS f_in_sync_committee_count,
+    sum(f_block_api_reward) AS f_block_api_reward,
+    sum(f_inclusion_delay_sum) AS f_inclusion_delay_sum
+FROM t_validator_rewards_buckets FINAL
+WHERE f_bucket + 63 > (SELECT max(f_bucket) FROM t_validator_rewards_buckets)
+GROUP BY f_val_idx;
+
+CREATE VIEW IF NOT EXISTS v_validator_rewards_30d AS
+SELECT
+    f_val_idx,
+    min(f_start_epoch) AS f_start_epoch,
+    max(f_end_epoch) AS f_end_epoch,
+    sum(f_epochs) AS f_epochs,
+    sum(f_reward) AS f_reward,
+    sum(f_max_reward) AS f_max_reward,
+    sum(f_attestations_included) AS f_attestations_included,
+    sum(f_missing_source_count) AS f_missing_source_count,
+    sum(f_missing_target_count) AS f_missing_target_count,
+    sum(f_missing_head_count) AS f_missing_head_count,
+    sum(f_in_sync_committee_count) AS f_in_sync_committee_count,
+    sum(f_block_api_reward) AS f_block_api_reward,
+    sum(f_inclusion_delay_sum) AS f_inclusion_delay_sum
+FROM t_validator_rewards_buckets FINAL
+WHERE f_bucket + 270 > (SELECT max(f_bucket) FROM t_validator_rewards_buckets)
+GROUP BY f_val_idx;
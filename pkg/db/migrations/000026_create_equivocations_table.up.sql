@@ -0,0 +1,15 @@
+CREATE TABLE t_equivocations
+(
+    f_val_idx            UInt64,
+    f_type               String,
+    f_slot               UInt64,
+    f_included           Bool,
+    f_vote1_source_epoch UInt64,
+    f_vote1_target_epoch UInt64,
+    f_vote1_root         String,
+    f_vote2_source_epoch UInt64,
+    f_vote2_target_epoch UInt64,
+    f_vote2_root         String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_slot, f_val_idx, f_vote1_root, f_vote2_root);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS t_consolidation_requests;
+DROP TABLE IF EXISTS t_compounding_balances;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS t_consolidation_requests
+(
+    f_slot                  UInt64,
+    f_epoch                 UInt64,
+    f_index                 UInt8,
+    f_source_address        String,
+    f_source_pubkey         String,
+    f_target_pubkey         String,
+    f_switch_to_compounding Bool
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_slot, f_index);
+
+CREATE TABLE IF NOT EXISTS t_compounding_balances
+(
+    f_epoch                    UInt64,
+    f_val_idx                  UInt64,
+    f_effective_balance        UInt64,
+    f_balance                  UInt64,
+    f_effective_balance_change Int64,
+    f_pending_consolidation    Bool
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_val_idx, f_epoch);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS t_chain_splits
+(
+    f_start_slot    UInt64,
+    f_detected_slot UInt64,
+    f_end_slot      UInt64,
+    f_peer_endpoint String,
+    f_root          String,
+    f_peer_root     String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_start_slot, f_peer_endpoint);
@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS v_validator_rewards_delta;
+
+DROP TABLE IF EXISTS t_validator_rewards_keyframes;
+
+DROP TABLE IF EXISTS t_validator_rewards_delta;
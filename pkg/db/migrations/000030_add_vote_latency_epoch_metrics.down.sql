@@ -0,0 +1,3 @@
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_num_votes_seen;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_vote_latency_mean_ms;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_vote_latency_p95_ms;
@@ -0,0 +1,3 @@
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_num_votes_seen UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_vote_latency_mean_ms Float64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_vote_latency_p95_ms Int64;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS t_validator_status_anomalies
+(
+    f_epoch          UInt64,
+    f_slot           UInt64,
+    f_status         UInt8,
+    f_computed_count UInt64,
+    f_node_count     UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_status);
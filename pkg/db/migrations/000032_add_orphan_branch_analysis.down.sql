@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS t_orphan_epoch_metrics;
+
+ALTER TABLE t_orphans DROP COLUMN f_block_root;
@@ -0,0 +1,3 @@
+ALTER TABLE t_orphans ADD COLUMN f_block_root TEXT;
+
+CREATE TABLE IF NOT EXISTS t_orphan_epoch_metrics AS t_epoch_metrics_summary;
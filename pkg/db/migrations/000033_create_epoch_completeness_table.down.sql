@@ -0,0 +1,3 @@
+DROP VIEW IF EXISTS v_epoch_completeness;
+
+DROP TABLE IF EXISTS t_epoch_completeness;
@@ -0,0 +1,19 @@
+CREATE TABLE t_epoch_completeness
+(
+    f_epoch    UInt64,
+    f_family   String,
+    f_complete Bool
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_family);
+
+CREATE VIEW IF NOT EXISTS v_epoch_completeness AS
+SELECT
+    f_epoch,
+    if(countIf(f_family = 'blocks') = 0, NULL, maxIf(f_complete, f_family = 'blocks')) AS f_blocks,
+    if(countIf(f_family = 'rewards') = 0, NULL, maxIf(f_complete, f_family = 'rewards')) AS f_rewards,
+    if(countIf(f_family = 'transactions') = 0, NULL, maxIf(f_complete, f_family = 'transactions')) AS f_transactions,
+    if(countIf(f_family = 'withdrawals') = 0, NULL, maxIf(f_complete, f_family = 'withdrawals')) AS f_withdrawals,
+    if(countIf(f_family = 'blobs') = 0, NULL, maxIf(f_complete, f_family = 'blobs')) AS f_blobs
+FROM t_epoch_completeness FINAL
+GROUP BY f_epoch;
@@ -0,0 +1,12 @@
+CREATE TABLE t_root_mismatches
+(
+    f_slot            UInt64,
+    f_kind            String,
+    f_cached_root     String,
+    f_node_root       String,
+    f_justified_epoch UInt64,
+    f_justified_root  String,
+    f_timestamp       UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_slot, f_kind, f_cached_root);
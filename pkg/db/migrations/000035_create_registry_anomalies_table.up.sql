@@ -0,0 +1,9 @@
+CREATE TABLE t_registry_anomalies
+(
+    f_epoch   UInt64,
+    f_val_idx UInt64,
+    f_kind    String,
+    f_detail  String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_val_idx, f_kind);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS t_transaction_logs;
@@ -0,0 +1,14 @@
+CREATE TABLE t_transaction_logs
+(
+    f_slot      UInt64,
+    f_tx_hash   String,
+    f_log_index UInt64,
+    f_address   String,
+    f_topic0    String,
+    f_topic1    String,
+    f_topic2    String,
+    f_topic3    String,
+    f_data      String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_slot, f_log_index);
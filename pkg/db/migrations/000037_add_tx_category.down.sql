@@ -0,0 +1 @@
+ALTER TABLE t_transactions DROP COLUMN f_tx_category;
@@ -0,0 +1 @@
+ALTER TABLE t_transactions ADD COLUMN f_tx_category TEXT DEFAULT '';
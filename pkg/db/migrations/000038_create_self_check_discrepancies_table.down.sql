@@ -0,0 +1 @@
+DROP TABLE IF EXISTS t_self_check_discrepancies;
@@ -0,0 +1,11 @@
+CREATE TABLE t_self_check_discrepancies
+(
+    f_epoch    UInt64,
+    f_slot     UInt64,
+    f_kind     String,
+    f_val_idx  UInt64,
+    f_computed String,
+    f_node     String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_kind, f_val_idx);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS t_epoch_randao;
+
+ALTER TABLE t_block_metrics DROP COLUMN f_randao_reveal;
@@ -0,0 +1,15 @@
+ALTER TABLE t_block_metrics ADD COLUMN f_randao_reveal TEXT DEFAULT '';
+
+CREATE TABLE t_epoch_randao
+(
+    f_epoch                UInt64,
+    f_slot                 UInt64,
+    f_randao_mix           String,
+    f_proposers_epoch      UInt64,
+    f_last_reveal_slot     UInt64,
+    f_last_reveal_proposer UInt64,
+    f_tail_missed_slots    UInt64,
+    f_tail_proposer_slots  UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch);
@@ -0,0 +1,8 @@
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_committee_count;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_avg_committee_size;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_target_committee_size;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_attester_seed;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_committee_count;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_avg_committee_size;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_target_committee_size;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_attester_seed;
@@ -0,0 +1,8 @@
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_committee_count UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_avg_committee_size Float64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_target_committee_size UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_attester_seed String;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_committee_count UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_avg_committee_size Float64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_target_committee_size UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_attester_seed String;
@@ -0,0 +1,10 @@
+CREATE TABLE t_vote_tallies
+(
+    f_slot           UInt64,
+    f_epoch          UInt64,
+    f_included_votes UInt64,
+    f_new_votes      UInt64,
+    f_expected_votes UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_slot);
@@ -0,0 +1,7 @@
+DROP VIEW IF EXISTS v_pool_luck_weekly;
+
+DROP VIEW IF EXISTS v_pool_luck_daily;
+
+DROP TABLE IF EXISTS t_pool_luck;
+
+ALTER TABLE t_genesis DROP COLUMN IF EXISTS f_seconds_per_slot;
@@ -0,0 +1,45 @@
+-- slot duration of the network, the dates of the views below are computed with it
+ALTER TABLE t_genesis ADD COLUMN IF NOT EXISTS f_seconds_per_slot UInt64 DEFAULT 12;
+
+CREATE TABLE t_pool_luck
+(
+    f_epoch               UInt64,
+    f_pool_name           String,
+    f_stake_share         Float64,
+    f_proposals           UInt64,
+    f_expected_proposals  Float64,
+    f_sync_seats          UInt64,
+    f_expected_sync_seats Float64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_pool_name);
+
+CREATE VIEW IF NOT EXISTS v_pool_luck_daily AS
+SELECT
+    toDate(toDateTime((SELECT max(f_genesis_time) FROM t_genesis) + f_epoch * 32 * (SELECT max(f_seconds_per_slot) FROM t_genesis))) AS f_day,
+    f_pool_name,
+    count() AS f_epochs,
+    avg(f_stake_share) AS f_stake_share,
+    sum(f_proposals) AS f_proposals,
+    sum(f_expected_proposals) AS f_expected_proposals,
+    if(f_expected_proposals = 0, 0, f_proposals / f_expected_proposals) AS f_proposer_luck,
+    sum(f_sync_seats) AS f_sync_seats,
+    sum(f_expected_sync_seats) AS f_expected_sync_seats,
+    if(f_expected_sync_seats = 0, 0, f_sync_seats / f_expected_sync_seats) AS f_sync_luck
+FROM t_pool_luck FINAL
+GROUP BY f_day, f_pool_name;
+
+CREATE VIEW IF NOT EXISTS v_pool_luck_weekly AS
+SELECT
+    toMonday(toDateTime((SELECT max(f_genesis_time) FROM t_genesis) + f_epoch * 32 * (SELECT max(f_seconds_per_slot) FROM t_genesis))) AS f_week,
+    f_pool_name,
+    count() AS f_epochs,
+    avg(f_stake_share) AS f_stake_share,
+    sum(f_proposals) AS f_proposals,
+    sum(f_expected_proposals) AS f_expected_proposals,
+    if(f_expected_proposals = 0, 0, f_proposals / f_expected_proposals) AS f_proposer_luck,
+    sum(f_sync_seats) AS f_sync_seats,
+    sum(f_expected_sync_seats) AS f_expected_sync_seats,
+    if(f_expected_sync_seats = 0, 0, f_sync_seats / f_expected_sync_seats) AS f_sync_luck
+FROM t_pool_luck FINAL
+GROUP BY f_week, f_pool_name;
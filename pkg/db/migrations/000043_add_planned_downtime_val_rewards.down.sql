@@ -0,0 +1,40 @@
+DROP VIEW IF EXISTS v_validator_rewards_delta;
+
+-- same columns as t_validator_rewards_summary: delta rows add the amounts of their keyframe
+CREATE VIEW v_validator_rewards_delta AS
+SELECT
+    d.f_val_idx AS f_val_idx,
+    d.f_epoch AS f_epoch,
+    toFloat32((d.f_balance_delta + if(d.f_keyframe, 0, k.f_balance)) / 1000000000) AS f_balance_eth,
+    d.f_reward AS f_reward,
+    toUInt64(d.f_max_reward_delta + if(d.f_keyframe, 0, k.f_max_reward)) AS f_max_reward,
+    toUInt64(d.f_max_att_reward_delta + if(d.f_keyframe, 0, k.f_max_att_reward)) AS f_max_att_reward,
+    toUInt64(d.f_max_sync_reward_delta + if(d.f_keyframe, 0, k.f_max_sync_reward)) AS f_max_sync_reward,
+    d.f_att_slot AS f_att_slot,
+    toUInt64(d.f_base_reward_delta + if(d.f_keyframe, 0, k.f_base_reward)) AS f_base_reward,
+    d.f_in_sync_committee AS f_in_sync_committee,
+    d.f_missing_source AS f_missing_source,
+    d.f_missing_target AS f_missing_target,
+    d.f_missing_head AS f_missing_head,
+    d.f_status AS f_status,
+    d.f_block_api_reward AS f_block_api_reward,
+    d.f_block_experimental_reward AS f_block_experimental_reward,
+    d.f_inclusion_delay AS f_inclusion_delay,
+    d.f_attestation_included AS f_attestation_included,
+    d.f_reward_percentile AS f_reward_percentile
+FROM t_validator_rewards_delta AS d FINAL
+LEFT JOIN
+(
+    SELECT
+        f_val_idx,
+        f_epoch,
+        f_balance,
+        f_max_reward,
+        f_max_att_reward,
+        f_max_sync_reward,
+        f_base_reward
+    FROM t_validator_rewards_keyframes FINAL
+) AS k ON d.f_val_idx = k.f_val_idx AND d.f_keyframe_epoch = k.f_epoch;
+
+ALTER TABLE t_validator_rewards_delta DROP COLUMN f_planned_downtime;
+ALTER TABLE t_validator_rewards_summary DROP COLUMN f_planned_downtime;
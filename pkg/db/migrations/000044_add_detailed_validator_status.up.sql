@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS t_detailed_status(
+	f_id UInt64 PRIMARY KEY,
+	f_status TEXT)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY f_id;
+
+INSERT INTO t_detailed_status VALUES(0, 'pending_initialized');
+INSERT INTO t_detailed_status VALUES(1, 'pending_queued');
+INSERT INTO t_detailed_status VALUES(2, 'active_ongoing');
+INSERT INTO t_detailed_status VALUES(3, 'active_exiting');
+INSERT INTO t_detailed_status VALUES(4, 'active_slashed');
+INSERT INTO t_detailed_status VALUES(5, 'exited_unslashed');
+INSERT INTO t_detailed_status VALUES(6, 'exited_slashed');
+INSERT INTO t_detailed_status VALUES(7, 'withdrawal_possible');
+INSERT INTO t_detailed_status VALUES(8, 'withdrawal_done');
+
+ALTER TABLE t_validator_rewards_summary ADD COLUMN f_detailed_status UInt8;
+ALTER TABLE t_validator_rewards_delta ADD COLUMN f_detailed_status UInt8;
+ALTER TABLE t_validator_last_status ADD COLUMN f_detailed_status UInt8;
+
+DROP VIEW IF EXISTS v_validator_rewards_delta;
+
+-- same columns as t_validator_rewards_summary: delta rows add the amounts of their keyframe
+CREATE VIEW v_validator_rewards_delta AS
+SELECT
+    d.f_val_idx AS f_val_idx,
+    d.f_epoch AS f_epoch,
+    toFloat32((d.f_balance_delta + if(d.f_keyframe, 0, k.f_balance)) / 1000000000) AS f_balance_eth,
+    d.f_reward AS f_reward,
+    toUInt64(d.f_max_reward_delta + if(d.f_keyframe, 0, k.f_max_reward)) AS f_max_reward,
+    toUInt64(d.f_max_att_reward_delta + if(d.f_keyframe, 0, k.f_max_att_reward)) AS f_max_att_reward,
+    toUInt64(d.f_max_sync_reward_delta + if(d.f_keyframe, 0, k.f_max_sync_reward)) AS f_max_sync_reward,
+    d.f_att_slot AS f_att_slot,
+    toUInt64(d.f_base_reward_delta + if(d.f_keyframe, 0, k.f_base_reward)) AS f_base_reward,
+    d.f_in_sync_committee AS f_in_sync_committee,
+    d.f_missing_source AS f_missing_source,
+    d.f_missing_target AS f_missing_target,
+    d.f_missing_head AS f_missing_head,
+    d.f_status AS f_status,
+    d.f_block_api_reward AS f_block_api_reward,
+    d.f_block_experimental_reward AS f_block_experimental_reward,
+    d.f_inclusion_delay AS f_inclusion_delay,
+    d.f_attestation_included AS f_attestation_included,
+    d.f_reward_percentile AS f_reward_percentile,
+    d.f_planned_downtime AS f_planned_downtime,
+    d.f_detailed_status AS f_detailed_status
+FROM t_validator_rewards_delta AS d FINAL
+LEFT JOIN
+(
+    SELECT
+        f_val_idx,
+        f_epoch,
+        f_balance,
+        f_max_reward,
+        f_max_att_reward,
+        f_max_sync_reward,
+        f_base_reward
+    FROM t_validator_rewards_keyframes FINAL
+) AS k ON d.f_val_idx = k.f_val_idx AND d.f_keyframe_epoch = k.f_epoch;
@@ -0,0 +1,2 @@
+DROP VIEW IF EXISTS v_validator_balance_changes;
+DROP TABLE IF EXISTS t_validator_balance_samples;
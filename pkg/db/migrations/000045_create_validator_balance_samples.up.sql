@@ -0,0 +1,30 @@
+CREATE TABLE t_validator_balance_samples
+(
+    f_slot    UInt64 CODEC(Delta, ZSTD),
+    f_val_idx UInt64,
+    f_balance UInt64 CODEC(T64, ZSTD)
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_val_idx, f_slot);
+
+-- change of the balance since the previous sample of the validator
+CREATE VIEW IF NOT EXISTS v_validator_balance_changes AS
+SELECT
+    f_slot,
+    f_val_idx,
+    f_balance,
+    f_prev_slot,
+    toInt64(f_balance) - toInt64(f_prev_balance) AS f_balance_change
+FROM
+(
+    SELECT
+        f_slot,
+        f_val_idx,
+        f_balance,
+        lagInFrame(f_slot) OVER w AS f_prev_slot,
+        lagInFrame(f_balance) OVER w AS f_prev_balance,
+        row_number() OVER w AS f_row
+    FROM t_validator_balance_samples FINAL
+    WINDOW w AS (PARTITION BY f_val_idx ORDER BY f_slot ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)
+)
+WHERE f_row > 1;
@@ -0,0 +1,3 @@
+DROP VIEW IF EXISTS v_voluntary_exit_inclusion;
+DROP TABLE IF EXISTS t_voluntary_exits;
+DROP TABLE IF EXISTS t_voluntary_exit_events;
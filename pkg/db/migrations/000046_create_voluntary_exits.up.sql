@@ -0,0 +1,36 @@
+CREATE TABLE t_voluntary_exit_events
+(
+    f_arrival_timestamp_ms UInt64,
+    f_val_idx              UInt64,
+    f_epoch                UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_val_idx, f_arrival_timestamp_ms);
+
+CREATE TABLE t_voluntary_exits
+(
+    f_slot    UInt64,
+    f_val_idx UInt64,
+    f_epoch   UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_slot, f_val_idx);
+
+-- first time each exit was seen in the network and the slot it was included at
+CREATE VIEW IF NOT EXISTS v_voluntary_exit_inclusion AS
+SELECT
+    e.f_val_idx AS f_val_idx,
+    e.f_epoch AS f_epoch,
+    e.f_seen_timestamp_ms AS f_seen_timestamp_ms,
+    x.f_slot AS f_inclusion_slot,
+    toInt64(((SELECT max(f_genesis_time) FROM t_genesis) + x.f_slot * (SELECT max(f_seconds_per_slot) FROM t_genesis)) * 1000) - toInt64(e.f_seen_timestamp_ms) AS f_inclusion_latency_ms
+FROM
+(
+    SELECT
+        f_val_idx,
+        any(f_epoch) AS f_epoch,
+        min(f_arrival_timestamp_ms) AS f_seen_timestamp_ms
+    FROM t_voluntary_exit_events
+    GROUP BY f_val_idx
+) AS e
+INNER JOIN t_voluntary_exits AS x FINAL ON e.f_val_idx = x.f_val_idx;
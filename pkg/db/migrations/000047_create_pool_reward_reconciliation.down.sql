@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS v_pool_reward_reconciliation_daily;
+
+DROP TABLE IF EXISTS t_pool_reward_reconciliation;
+
+DROP TABLE IF EXISTS t_pool_withdrawal_addresses;
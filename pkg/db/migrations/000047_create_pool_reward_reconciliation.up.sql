@@ -0,0 +1,47 @@
+-- filled by the user, like t_eth2_pubkeys
+CREATE TABLE t_pool_withdrawal_addresses
+(
+    f_pool_name String,
+    f_address   String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_pool_name, f_address);
+
+CREATE TABLE t_pool_reward_reconciliation
+(
+    f_epoch             UInt64,
+    f_pool_name         String,
+    f_modeled_rewards   Int64,
+    f_received          UInt64,
+    f_principal         UInt64,
+    f_unknown_address   UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_pool_name);
+
+-- rewards not withdrawn yet stay in the balances, so the cumulative delta should be bounded by a sweep cycle of rewards
+CREATE VIEW IF NOT EXISTS v_pool_reward_reconciliation_daily AS
+SELECT
+    f_day,
+    f_pool_name,
+    f_epochs,
+    f_modeled_rewards,
+    f_received,
+    f_principal,
+    f_unknown_address,
+    f_delta,
+    sum(f_delta) OVER (PARTITION BY f_pool_name ORDER BY f_day) AS f_cumulative_delta
+FROM
+(
+    SELECT
+        toDate(toDateTime((SELECT max(f_genesis_time) FROM t_genesis) + f_epoch * 384)) AS f_day,
+        f_pool_name,
+        count() AS f_epochs,
+        sum(f_modeled_rewards) AS f_modeled_rewards,
+        sum(f_received) AS f_received,
+        sum(f_principal) AS f_principal,
+        sum(f_unknown_address) AS f_unknown_address,
+        f_modeled_rewards - toInt64(f_received) AS f_delta
+    FROM t_pool_reward_reconciliation FINAL
+    GROUP BY f_day, f_pool_name
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS t_effective_balance_histogram;
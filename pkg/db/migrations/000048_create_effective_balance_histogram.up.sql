@@ -0,0 +1,9 @@
+CREATE TABLE t_effective_balance_histogram
+(
+    f_epoch                 UInt64,
+    f_max_effective_balance UInt64,
+    f_validators            UInt64,
+    f_effective_balance     UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_max_effective_balance);
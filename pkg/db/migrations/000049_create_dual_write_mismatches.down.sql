@@ -0,0 +1 @@
+DROP TABLE IF EXISTS t_dual_write_mismatches;
@@ -0,0 +1,10 @@
+CREATE TABLE t_dual_write_mismatches
+(
+    f_epoch          UInt64,
+    f_table          String,
+    f_primary_rows   UInt64,
+    f_secondary_rows UInt64,
+    f_timestamp      UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_table, f_timestamp);
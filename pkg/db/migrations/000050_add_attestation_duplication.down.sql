@@ -0,0 +1,2 @@
+ALTER TABLE t_block_metrics DROP COLUMN f_attestation_duplication_ratio;
+ALTER TABLE t_block_metrics DROP COLUMN f_redundant_attestations;
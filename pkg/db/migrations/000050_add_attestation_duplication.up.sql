@@ -0,0 +1,2 @@
+ALTER TABLE t_block_metrics ADD COLUMN f_redundant_attestations UInt64 DEFAULT 0;
+ALTER TABLE t_block_metrics ADD COLUMN f_attestation_duplication_ratio Float64 DEFAULT 0;
@@ -0,0 +1,13 @@
+CREATE TABLE t_state_proofs
+(
+    f_epoch           UInt64,
+    f_slot            UInt64,
+    f_state_root      String,
+    f_validators      UInt64,
+    f_verified        Bool,
+    f_failed_val_idxs Array(UInt64),
+    f_error           String,
+    f_timestamp       UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_slot, f_state_root);
@@ -0,0 +1,2 @@
+ALTER TABLE t_block_metrics DROP COLUMN f_wrong_target_attestations;
+ALTER TABLE t_block_metrics DROP COLUMN f_correct_target_attestations;
@@ -0,0 +1,2 @@
+ALTER TABLE t_block_metrics ADD COLUMN f_correct_target_attestations UInt64 DEFAULT 0;
+ALTER TABLE t_block_metrics ADD COLUMN f_wrong_target_attestations UInt64 DEFAULT 0;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS t_proposer_fairness;
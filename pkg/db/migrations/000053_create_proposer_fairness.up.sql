@@ -0,0 +1,14 @@
+CREATE TABLE t_proposer_fairness
+(
+    f_snapshot_epoch     UInt64,
+    f_val_idx            UInt64,
+    f_epoch              UInt64,
+    f_first_epoch        UInt64,
+    f_epochs             UInt64,
+    f_expected_proposals Float64,
+    f_duties             UInt64,
+    f_proposed           UInt64,
+    f_drift              Float64
+)
+ENGINE = ReplacingMergeTree(f_snapshot_epoch)
+ORDER BY f_val_idx;
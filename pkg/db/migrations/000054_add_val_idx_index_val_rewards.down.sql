@@ -0,0 +1 @@
+ALTER TABLE t_validator_rewards_summary DROP INDEX IF EXISTS idx_val_idx;
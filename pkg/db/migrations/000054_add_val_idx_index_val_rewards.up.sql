@@ -0,0 +1,3 @@
+-- rows are sorted by epoch, the index skips the granules of each epoch without the validator.
+-- Only the parts written from now on are indexed, the existing ones are left to the operator (see the README)
+ALTER TABLE t_validator_rewards_summary ADD INDEX IF NOT EXISTS idx_val_idx f_val_idx TYPE minmax GRANULARITY 1;
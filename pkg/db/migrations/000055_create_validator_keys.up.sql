@@ -0,0 +1,11 @@
+CREATE TABLE t_validator_keys
+(
+    f_val_idx                UInt64,
+    f_public_key             String,
+    f_activation_epoch       UInt64,
+    f_withdrawal_credentials String,
+    f_epoch                  UInt64,
+    INDEX idx_public_key f_public_key TYPE bloom_filter GRANULARITY 4
+)
+ENGINE = ReplacingMergeTree(f_epoch)
+ORDER BY f_val_idx;
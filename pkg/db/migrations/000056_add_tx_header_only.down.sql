@@ -0,0 +1 @@
+ALTER TABLE t_transactions DROP COLUMN IF EXISTS f_header_only;
@@ -0,0 +1 @@
+ALTER TABLE t_transactions ADD COLUMN IF NOT EXISTS f_header_only BOOL DEFAULT false;
@@ -0,0 +1,7 @@
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN IF EXISTS f_any_att_effective_balance_eth;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN IF EXISTS f_any_att_effective_balance_eth;
+
+ALTER TABLE t_pool_summary DROP COLUMN IF EXISTS source_att_balance_share;
+ALTER TABLE t_pool_summary DROP COLUMN IF EXISTS target_att_balance_share;
+ALTER TABLE t_pool_summary DROP COLUMN IF EXISTS head_att_balance_share;
+ALTER TABLE t_pool_summary DROP COLUMN IF EXISTS any_att_balance_share;
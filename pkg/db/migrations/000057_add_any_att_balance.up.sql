@@ -0,0 +1,7 @@
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN IF NOT EXISTS f_any_att_effective_balance_eth UInt64 AFTER f_head_att_effective_balance_eth;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN IF NOT EXISTS f_any_att_effective_balance_eth UInt64 AFTER f_head_att_effective_balance_eth;
+
+ALTER TABLE t_pool_summary ADD COLUMN IF NOT EXISTS source_att_balance_share Float64;
+ALTER TABLE t_pool_summary ADD COLUMN IF NOT EXISTS target_att_balance_share Float64;
+ALTER TABLE t_pool_summary ADD COLUMN IF NOT EXISTS head_att_balance_share Float64;
+ALTER TABLE t_pool_summary ADD COLUMN IF NOT EXISTS any_att_balance_share Float64;
@@ -0,0 +1,16 @@
+CREATE TABLE t_epoch_churn
+(
+    f_epoch                     UInt64,
+    f_churn_limit               UInt64,
+    f_activation_churn          UInt64,
+    f_exit_churn                UInt64,
+    f_consolidation_churn_limit UInt64,
+    f_consolidation_churn       UInt64,
+    f_activation_utilization    Float64,
+    f_exit_utilization          Float64,
+    f_consolidation_utilization Float64,
+    f_pending_deposits          UInt64,
+    f_exit_queue_epochs         UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY f_epoch;
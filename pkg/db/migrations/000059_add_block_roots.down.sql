@@ -0,0 +1,3 @@
+ALTER TABLE t_block_metrics DROP COLUMN f_state_root;
+ALTER TABLE t_block_metrics DROP COLUMN f_parent_root;
+ALTER TABLE t_block_metrics DROP COLUMN f_block_root;
@@ -0,0 +1,3 @@
+ALTER TABLE t_block_metrics ADD COLUMN f_block_root String DEFAULT '';
+ALTER TABLE t_block_metrics ADD COLUMN f_parent_root String DEFAULT '';
+ALTER TABLE t_block_metrics ADD COLUMN f_state_root String DEFAULT '';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS t_pool_client_diversity;
@@ -0,0 +1,12 @@
+CREATE TABLE t_pool_client_diversity
+(
+    f_epoch              UInt64,
+    f_window_epochs      UInt64,
+    f_pool_name          String,
+    f_client             String,
+    f_proposals          UInt64,
+    f_inferred_proposals UInt64,
+    f_share              Float64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_pool_name, f_client);
@@ -0,0 +1,10 @@
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_blobs_num;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_avg_blobs_per_block;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_blob_gas_used;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_target_blob_gas;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_blob_utilization;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_blobs_num;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_avg_blobs_per_block;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_blob_gas_used;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_target_blob_gas;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_blob_utilization;
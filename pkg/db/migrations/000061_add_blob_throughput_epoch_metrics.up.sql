@@ -0,0 +1,10 @@
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_blobs_num UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_avg_blobs_per_block Float64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_blob_gas_used UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_target_blob_gas UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_blob_utilization Float64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_blobs_num UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_avg_blobs_per_block Float64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_blob_gas_used UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_target_blob_gas UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_blob_utilization Float64;
@@ -0,0 +1,11 @@
+CREATE TABLE t_slashing_protection_anomalies
+(
+    f_val_idx      UInt64,
+    f_public_key   String,
+    f_type         String,
+    f_slot         UInt64,
+    f_source_epoch UInt64,
+    f_target_epoch UInt64
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_slot, f_val_idx, f_type, f_source_epoch, f_target_epoch);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS t_data_quality_notes;
+
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_state_substituted;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_state_substituted;
@@ -0,0 +1,13 @@
+CREATE TABLE t_data_quality_notes
+(
+    f_epoch     UInt64,
+    f_type      String,
+    f_slot      UInt64,
+    f_used_slot UInt64,
+    f_detail    String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_epoch, f_type, f_slot);
+
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_state_substituted Bool DEFAULT false;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_state_substituted Bool DEFAULT false;
@@ -0,0 +1,2 @@
+DROP VIEW IF EXISTS v_pool_inclusion_delays;
+DROP TABLE IF EXISTS t_attestation_inclusions;
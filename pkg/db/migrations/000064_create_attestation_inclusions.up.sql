@@ -0,0 +1,21 @@
+CREATE TABLE t_attestation_inclusions
+(
+    f_val_idx         UInt64,
+    f_att_slot        UInt64,
+    f_slot            UInt64,
+    f_proposer_index  UInt64,
+    f_inclusion_delay UInt32
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_slot, f_val_idx, f_att_slot);
+
+CREATE VIEW IF NOT EXISTS v_pool_inclusion_delays AS
+SELECT
+    toDate(toDateTime((SELECT max(f_genesis_time) FROM t_genesis) + i.f_slot * (SELECT max(f_seconds_per_slot) FROM t_genesis))) AS f_day,
+    k.f_pool_name AS f_pool_name,
+    count() AS f_inclusions,
+    avg(i.f_inclusion_delay) AS f_avg_inclusion_delay,
+    countIf(i.f_inclusion_delay = 1) / count() AS f_next_slot_share
+FROM t_attestation_inclusions AS i FINAL
+LEFT JOIN t_eth2_pubkeys AS k FINAL ON i.f_proposer_index = k.f_val_idx
+GROUP BY f_day, f_pool_name;
@@ -0,0 +1,16 @@
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_deposits_included;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_voluntary_exits_included;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_attester_slashings_included;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_proposer_slashings_included;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_deposit_requests_included;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_exit_requests_included;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_partial_withdrawal_requests_included;
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN f_consolidation_requests_included;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_deposits_included;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_voluntary_exits_included;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_attester_slashings_included;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_proposer_slashings_included;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_deposit_requests_included;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_exit_requests_included;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_partial_withdrawal_requests_included;
+ALTER TABLE t_orphan_epoch_metrics DROP COLUMN f_consolidation_requests_included;
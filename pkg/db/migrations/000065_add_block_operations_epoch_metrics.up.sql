@@ -0,0 +1,16 @@
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_deposits_included UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_voluntary_exits_included UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_attester_slashings_included UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_proposer_slashings_included UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_deposit_requests_included UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_exit_requests_included UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_partial_withdrawal_requests_included UInt64;
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN f_consolidation_requests_included UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_deposits_included UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_voluntary_exits_included UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_attester_slashings_included UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_proposer_slashings_included UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_deposit_requests_included UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_exit_requests_included UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_partial_withdrawal_requests_included UInt64;
+ALTER TABLE t_orphan_epoch_metrics ADD COLUMN f_consolidation_requests_included UInt64;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS t_reorg_fork_choice;
@@ -0,0 +1,15 @@
+CREATE TABLE t_reorg_fork_choice
+(
+    f_reorg_slot      UInt64,
+    f_slot            UInt64,
+    f_block_root      String,
+    f_parent_root     String,
+    f_weight          UInt64,
+    f_validity        String,
+    f_justified_epoch UInt64,
+    f_finalized_epoch UInt64,
+    f_branch          String,
+    f_extra_data      String
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY (f_reorg_slot, f_slot, f_block_root);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS t_custom_pool_keys;
@@ -0,0 +1,8 @@
+CREATE TABLE t_custom_pool_keys
+(
+    f_val_idx   UInt64,
+    f_pool_name String,
+    f_removed   Bool
+)
+ENGINE = ReplacingMergeTree()
+ORDER BY f_val_idx;
@@ -10,6 +10,7 @@ import (
 
 var (
 	orphansTable      = "t_orphans"
+	orphanEpochsTable = "t_orphan_epoch_metrics" // same columns as t_epoch_metrics_summary
 	insertOrphanQuery = `
 	INSERT INTO %s (
 		f_timestamp,
@@ -35,7 +36,8 @@ var (
 		f_snappy_size_bytes,
 		f_compression_time_ms,
 		f_decompression_time_ms,
-		f_payload_size_bytes)
+		f_payload_size_bytes,
+		f_block_root)
 		VALUES`
 )
 
@@ -66,6 +68,7 @@ func orphansInput(blocks []spec.AgnosticBlock) proto.Input {
 		f_snappy_size_bytes     proto.ColFloat32
 		f_compression_time_ms   proto.ColFloat32
 		f_decompression_time_ms proto.ColFloat32
+		f_block_root            proto.ColStr
 	)
 
 	for _, block := range blocks {
@@ -79,7 +82,7 @@ func orphansInput(blocks []spec.AgnosticBlock) proto.Input {
 
 		f_proposer_index.Append(uint64(block.ProposerIndex))
 		f_proposed.Append(block.Proposed)
-		f_attestations.Append(uint64(len(block.Attestations)))
+		f_attestations.Append(uint64(block.NumAttestations()))
 		f_deposits.Append(uint64(len(block.Deposits)))
 		f_proposer_slashings.Append(uint64(len(block.ProposerSlashings)))
 		f_attester_slashings.Append(uint64(len(block.AttesterSlashings)))
@@ -101,6 +104,7 @@ func orphansInput(blocks []spec.AgnosticBlock) proto.Input {
 		f_snappy_size_bytes.Append(float32(block.SnappySize))
 		f_compression_time_ms.Append(float32(utils.DurationToFloat64Millis(block.CompressionTime)))
 		f_decompression_time_ms.Append(float32(utils.DurationToFloat64Millis(block.DecompressionTime)))
+		f_block_root.Append(block.Root.String())
 
 	}
 
@@ -129,6 +133,7 @@ func orphansInput(blocks []spec.AgnosticBlock) proto.Input {
 		{Name: "f_compression_time_ms", Data: f_compression_time_ms},
 		{Name: "f_decompression_time_ms", Data: f_decompression_time_ms},
 		{Name: "f_payload_size_bytes", Data: f_payload_size_bytes},
+		{Name: "f_block_root", Data: f_block_root},
 	}
 }
 
@@ -149,3 +154,22 @@ func (p *DBService) PersistOrphans(data []spec.AgnosticBlock) error {
 	}
 	return err
 }
+
+// PersistOrphanEpochs persists the metrics of epochs computed from a non-canonical branch
+func (p *DBService) PersistOrphanEpochs(data []spec.Epoch) error {
+	persistObj := PersistableObject[spec.Epoch]{
+		input: epochsInput,
+		table: orphanEpochsTable,
+		query: insertEpochQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting orphan epoch: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	customPoolKeysTable = "t_custom_pool_keys"
+
+	insertPoolKeysQuery = `
+	INSERT INTO %s (
+		f_val_idx,
+		f_pool_name,
+		f_removed)
+		VALUES`
+
+	selectCustomPoolKeysQuery = `
+		SELECT f_val_idx, f_pool_name
+		FROM %s FINAL
+		WHERE NOT f_removed`
+
+	// keeps the public key and sub pool of the existing rows, and only clears the pool of the removed
+	// validators if it is still the one the custom pools gave them
+	syncPoolPubkeysQuery = `
+		INSERT INTO %[1]s (f_val_idx, f_public_key, f_pool_name, f_pool)
+		SELECT
+			c.f_val_idx,
+			p.f_public_key,
+			if(c.f_removed, '', c.f_pool_name),
+			p.f_pool
+		FROM %[2]s AS c FINAL
+		LEFT JOIN %[1]s AS p FINAL ON c.f_val_idx = p.f_val_idx
+		WHERE (NOT c.f_removed AND p.f_pool_name != c.f_pool_name)
+			OR (c.f_removed AND p.f_pool_name = c.f_pool_name AND p.f_pool_name != '')`
+)
+
+func poolKeysInput(keys []spec.PoolKey) proto.Input {
+	// one object per column
+	var (
+		f_val_idx   proto.ColUInt64
+		f_pool_name proto.ColStr
+		f_removed   proto.ColBool
+	)
+
+	for _, key := range keys {
+		f_val_idx.Append(uint64(key.ValIdx))
+		f_pool_name.Append(key.PoolName)
+		f_removed.Append(key.Removed)
+	}
+
+	return proto.Input{
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_pool_name", Data: f_pool_name},
+		{Name: "f_removed", Data: f_removed},
+	}
+}
+
+// PersistPoolKeys writes the custom pool of the given validators and merges it into t_eth2_pubkeys,
+// where the rest of the columns and the pools set by other means are kept
+func (p *DBService) PersistPoolKeys(data []spec.PoolKey) error {
+	persistObj := PersistableObject[spec.PoolKey]{
+		input: poolKeysInput,
+		table: customPoolKeysTable,
+		query: insertPoolKeysQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting pool keys: %s", err.Error())
+		return err
+	}
+
+	startTime := time.Now()
+	p.highMu.Lock()
+	err = p.highLevelClient.Exec(p.ctx, fmt.Sprintf(syncPoolPubkeysQuery, poolPubkeysTable, customPoolKeysTable))
+	p.highMu.Unlock()
+	if err != nil {
+		log.Errorf("error merging the pool keys into %s: %s", poolPubkeysTable, err.Error())
+		return err
+	}
+	log.Debugf("pool keys merged into %s in %f seconds", poolPubkeysTable, time.Since(startTime).Seconds())
+	return nil
+}
+
+// RetrieveCustomPoolKeys returns the custom pools persisted by previous runs, without the removed validators
+func (p *DBService) RetrieveCustomPoolKeys() ([]spec.PoolKey, error) {
+	var dest []struct {
+		F_val_idx   uint64 `ch:"f_val_idx"`
+		F_pool_name string `ch:"f_pool_name"`
+	}
+	if err := p.highSelect(fmt.Sprintf(selectCustomPoolKeysQuery, customPoolKeysTable), &dest); err != nil {
+		return nil, err
+	}
+
+	keys := make([]spec.PoolKey, 0, len(dest))
+	for _, row := range dest {
+		keys = append(keys, spec.PoolKey{
+			ValIdx:   phase0.ValidatorIndex(row.F_val_idx),
+			PoolName: row.F_pool_name,
+		})
+	}
+	return keys, nil
+}
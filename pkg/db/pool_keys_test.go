@@ -0,0 +1,67 @@
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/testutil"
+)
+
+func TestPersistPoolKeys(t *testing.T) {
+	ch := testutil.StartClickHouse(t)
+	dbClient, err := db.New(context.Background(), ch.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbClient.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer dbClient.Finish()
+
+	// rows filled by the user
+	ch.Exec(t, `INSERT INTO t_eth2_pubkeys (f_val_idx, f_public_key, f_pool_name, f_pool) VALUES (1, '0xaa', 'old', 'node-op'), (2, '0xbb', 'external', '')`)
+
+	pools := func() map[string]string {
+		rows := ch.Query(t, "SELECT f_val_idx, f_public_key, f_pool_name, f_pool FROM t_eth2_pubkeys FINAL ORDER BY f_val_idx")
+		result := make(map[string]string, len(rows))
+		for _, row := range rows {
+			result[fmt.Sprint(row["f_val_idx"])] = fmt.Sprintf("%s,%s,%s", row["f_public_key"], row["f_pool_name"], row["f_pool"])
+		}
+		return result
+	}
+
+	err = dbClient.PersistPoolKeys([]spec.PoolKey{{ValIdx: 1, PoolName: "lido"}, {ValIdx: 3, PoolName: "kiln"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"1": "0xaa,lido,node-op", "2": "0xbb,external,", "3": ",kiln,"}
+	if got := pools(); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	// validator 2 was never assigned by the custom pools, validator 3 was reassigned by the user
+	ch.Exec(t, `INSERT INTO t_eth2_pubkeys (f_val_idx, f_public_key, f_pool_name, f_pool) VALUES (3, '0xcc', 'figment', '')`)
+	err = dbClient.PersistPoolKeys([]spec.PoolKey{
+		{ValIdx: 1, PoolName: "lido", Removed: true},
+		{ValIdx: 2, PoolName: "external", Removed: true},
+		{ValIdx: 3, PoolName: "kiln", Removed: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string]string{"1": "0xaa,,node-op", "2": "0xbb,external,", "3": "0xcc,figment,"}
+	if got := pools(); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	keys, err := dbClient.RetrieveCustomPoolKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("expected no custom pools left, got %+v", keys)
+	}
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	poolLuckTable       = "t_pool_luck"
+	insertPoolLuckQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_pool_name,
+		f_stake_share,
+		f_proposals,
+		f_expected_proposals,
+		f_sync_seats,
+		f_expected_sync_seats)
+		VALUES`
+
+	// the validators without pool are grouped under an empty name, they are part of the active stake
+	selectPoolStakesQuery = `
+		SELECT
+			k.f_pool_name AS f_pool_name,
+			sum(r.f_base_reward) AS f_base_rewards,
+			countIf(r.f_in_sync_committee) AS f_sync_seats
+		FROM %s AS r
+		LEFT JOIN %s AS k FINAL ON r.f_val_idx = k.f_val_idx
+		WHERE r.f_epoch = %d AND r.f_status = 1
+		GROUP BY f_pool_name`
+
+	selectPoolProposalsCountQuery = `
+		SELECT
+			k.f_pool_name AS f_pool_name,
+			count() AS f_proposals
+		FROM %s AS d FINAL
+		INNER JOIN %s AS k FINAL ON d.f_val_idx = k.f_val_idx
+		WHERE d.f_proposer_slot >= %d AND d.f_proposer_slot < %d AND k.f_pool_name != ''
+		GROUP BY f_pool_name`
+)
+
+func poolLuckInput(luck []spec.PoolLuck) proto.Input {
+	// one object per column
+	var (
+		f_epoch               proto.ColUInt64
+		f_pool_name           proto.ColStr
+		f_stake_share         proto.ColFloat64
+		f_proposals           proto.ColUInt64
+		f_expected_proposals  proto.ColFloat64
+		f_sync_seats          proto.ColUInt64
+		f_expected_sync_seats proto.ColFloat64
+	)
+
+	for _, item := range luck {
+		f_epoch.Append(uint64(item.Epoch))
+		f_pool_name.Append(item.PoolName)
+		f_stake_share.Append(item.StakeShare)
+		f_proposals.Append(item.Proposals)
+		f_expected_proposals.Append(item.ExpectedProposals)
+		f_sync_seats.Append(item.SyncSeats)
+		f_expected_sync_seats.Append(item.ExpectedSyncSeats)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_pool_name", Data: f_pool_name},
+		{Name: "f_stake_share", Data: f_stake_share},
+		{Name: "f_proposals", Data: f_proposals},
+		{Name: "f_expected_proposals", Data: f_expected_proposals},
+		{Name: "f_sync_seats", Data: f_sync_seats},
+		{Name: "f_expected_sync_seats", Data: f_expected_sync_seats},
+	}
+}
+
+func (p *DBService) PersistPoolLuck(data []spec.PoolLuck) error {
+	persistObj := PersistableObject[spec.PoolLuck]{
+		input: poolLuckInput,
+		table: poolLuckTable,
+		query: insertPoolLuckQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting pool luck: %s", err.Error())
+	}
+	return err
+}
+
+// RetrievePoolStakes returns the stake, sync committee seats and proposer duties of the active validators
+// of every pool at the given epoch, and the ones of all the active validators
+func (p *DBService) RetrievePoolStakes(epoch phase0.Epoch) ([]spec.PoolStake, spec.PoolStake, error) {
+	var stakes []struct {
+		F_pool_name    string `ch:"f_pool_name"`
+		F_base_rewards uint64 `ch:"f_base_rewards"`
+		F_sync_seats   uint64 `ch:"f_sync_seats"`
+	}
+	var proposals []struct {
+		F_pool_name string `ch:"f_pool_name"`
+		F_proposals uint64 `ch:"f_proposals"`
+	}
+	total := spec.PoolStake{}
+
+	startTime := time.Now()
+	err := p.highSelect(
+		fmt.Sprintf(selectPoolStakesQuery, p.valRewardsSource(), poolPubkeysTable, epoch),
+		&stakes)
+	if err != nil {
+		return nil, total, err
+	}
+	firstSlot := phase0.Slot(epoch) * spec.SlotsPerEpoch
+	err = p.highSelect(
+		fmt.Sprintf(selectPoolProposalsCountQuery, proposerDutiesTable, poolPubkeysTable, firstSlot, firstSlot+spec.SlotsPerEpoch),
+		&proposals)
+	if err != nil {
+		return nil, total, err
+	}
+
+	poolProposals := make(map[string]uint64, len(proposals))
+	for _, row := range proposals {
+		poolProposals[row.F_pool_name] = row.F_proposals
+	}
+	pools := make([]spec.PoolStake, 0, len(stakes))
+	for _, row := range stakes {
+		total.BaseRewards += phase0.Gwei(row.F_base_rewards)
+		total.SyncSeats += row.F_sync_seats
+		if row.F_pool_name == "" {
+			continue
+		}
+		pools = append(pools, spec.PoolStake{
+			PoolName:    row.F_pool_name,
+			BaseRewards: phase0.Gwei(row.F_base_rewards),
+			SyncSeats:   row.F_sync_seats,
+			Proposals:   poolProposals[row.F_pool_name],
+		})
+	}
+	log.Debugf("pool stakes of epoch %d retrieved, %f seconds", epoch, time.Since(startTime).Seconds())
+	return pools, total, nil
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	poolWithdrawalAddressesTable = "t_pool_withdrawal_addresses"
+	poolReconciliationTable      = "t_pool_reward_reconciliation"
+
+	// only pools with known withdrawal addresses are reconciled. The withdrawals of validators that are not
+	// active anymore are their principal being paid out, not rewards
+	insertPoolReconciliationQuery = `
+		INSERT INTO %[1]s
+			SELECT
+				$1 AS f_epoch,
+				r.f_pool_name,
+				r.f_modeled_rewards,
+				w.f_received,
+				w.f_principal,
+				w.f_unknown_address
+			FROM (
+				SELECT
+					k.f_pool_name AS f_pool_name,
+					sum(r.f_reward) AS f_modeled_rewards
+				FROM %[2]s AS r
+				INNER JOIN %[3]s AS k FINAL ON r.f_val_idx = k.f_val_idx
+				WHERE r.f_epoch = $1 AND k.f_pool_name IN (SELECT f_pool_name FROM %[4]s)
+				GROUP BY f_pool_name
+			) AS r
+			LEFT JOIN (
+				SELECT
+					k.f_pool_name AS f_pool_name,
+					sumIf(w.f_amount, a.f_address != '' AND v.f_status = 1) AS f_received,
+					sumIf(w.f_amount, a.f_address != '' AND v.f_status != 1) AS f_principal,
+					sumIf(w.f_amount, a.f_address = '') AS f_unknown_address
+				FROM %[5]s AS w FINAL
+				INNER JOIN %[3]s AS k FINAL ON w.f_val_idx = k.f_val_idx
+				INNER JOIN (
+					SELECT f_val_idx, f_status FROM %[2]s WHERE f_epoch = $1
+				) AS v ON w.f_val_idx = v.f_val_idx
+				LEFT JOIN (
+					SELECT DISTINCT f_pool_name, lower(f_address) AS f_address FROM %[4]s FINAL
+				) AS a ON k.f_pool_name = a.f_pool_name AND lower(w.f_address) = a.f_address
+				WHERE w.f_slot >= $1 * 32 AND w.f_slot < ($1 + 1) * 32
+				GROUP BY f_pool_name
+			) AS w ON r.f_pool_name = w.f_pool_name`
+)
+
+// InsertPoolReconciliation compares the modeled rewards of every pool with known withdrawal addresses at the given epoch
+// with the withdrawals its addresses received in the slots of the epoch
+func (p *DBService) InsertPoolReconciliation(epoch phase0.Epoch) error {
+	query := fmt.Sprintf(insertPoolReconciliationQuery,
+		poolReconciliationTable, p.valRewardsSource(), poolPubkeysTable, poolWithdrawalAddressesTable, withdrawalsTable)
+	startTime := time.Now()
+
+	p.highMu.Lock()
+	err := p.highLevelClient.Exec(p.ctx, query, epoch)
+	p.highMu.Unlock()
+
+	if err == nil {
+		log.Debugf("pool reward reconciliation inserted for epoch %d, %f seconds", epoch, time.Since(startTime).Seconds())
+	}
+	return err
+}
@@ -4,41 +4,81 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ClickHouse/ch-go/proto"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
 )
 
 var (
 	poolsTables = "t_pool_summary"
 
+	// pools whose validators all left (wind-down) keep a row per epoch with f_active = false,
+	// only active validators are considered in the aggregations.
+	// The attesting balance shares are weighted with the base reward, proportional to the effective balance.
+	// The rewards are read from the table or view of the rewards storage
+
 	insertPoolSummary = `
-		INSERT INTO %s
+		INSERT INTO %[1]s
 			SELECT 
 				t_eth2_pubkeys.f_pool_name, f_epoch,
-				SUM(CASE WHEN (f_reward <= f_max_reward) THEN f_reward ELSE 0 END) as aggregated_rewards,
-				SUM(CASE WHEN (f_reward <= f_max_reward) THEN f_max_reward ELSE 0 END) as aggregated_max_rewards,
-				COUNT(CASE WHEN f_in_sync_committee = TRUE THEN 1 ELSE null END) as count_sync_committee,
-				COUNT(CASE WHEN f_missing_source = TRUE THEN 1 ELSE null END) as count_missing_source,
-				COUNT(CASE WHEN f_missing_target = TRUE THEN 1 ELSE null END) as count_missing_target,
-				COUNT(CASE WHEN f_missing_head = TRUE THEN 1 ELSE null END) as count_missing_head,
-				COUNT(*) as count_expected_attestations,
-				SUM(CASE WHEN f_attestation_included = TRUE THEN 1 ELSE 0 END) as count_included_attestations,
+				SUM(CASE WHEN (f_status = 1 AND f_reward <= f_max_reward) THEN f_reward ELSE 0 END) as aggregated_rewards,
+				SUM(CASE WHEN (f_status = 1 AND f_reward <= f_max_reward) THEN f_max_reward ELSE 0 END) as aggregated_max_rewards,
+				COUNT(CASE WHEN f_status = 1 AND f_in_sync_committee = TRUE THEN 1 ELSE null END) as count_sync_committee,
+				COUNT(CASE WHEN f_status = 1 AND f_missing_source = TRUE THEN 1 ELSE null END) as count_missing_source,
+				COUNT(CASE WHEN f_status = 1 AND f_missing_target = TRUE THEN 1 ELSE null END) as count_missing_target,
+				COUNT(CASE WHEN f_status = 1 AND f_missing_head = TRUE THEN 1 ELSE null END) as count_missing_head,
+				COUNT(CASE WHEN f_status = 1 THEN 1 ELSE null END) as count_expected_attestations,
+				SUM(CASE WHEN f_status = 1 AND f_attestation_included = TRUE THEN 1 ELSE 0 END) as count_included_attestations,
 				SUM(CASE WHEN t_proposer_duties.f_proposed = TRUE THEN 1 ELSE 0 END) as proposed_blocks_performance,
-				SUM(CASE WHEN t_proposer_duties.f_proposed = FALSE and t_validator_rewards_summary.f_val_idx = t_proposer_duties.f_val_idx THEN 1 ELSE 0 END) as missed_blocks_performance,
-				count(distinct(t_validator_rewards_summary.f_val_idx)) as number_active_vals,
-				AVG(f_inclusion_delay) as avg_inclusion_delay
-			FROM t_validator_rewards_summary
+				SUM(CASE WHEN t_proposer_duties.f_proposed = FALSE and r.f_val_idx = t_proposer_duties.f_val_idx THEN 1 ELSE 0 END) as missed_blocks_performance,
+				count(distinct(CASE WHEN f_status = 1 THEN r.f_val_idx ELSE null END)) as number_active_vals,
+				if(number_active_vals = 0, 0, avgIf(f_inclusion_delay, f_status = 1)) as avg_inclusion_delay,
+				number_active_vals > 0 as f_active,
+				if(sumIf(f_base_reward, f_status = 1) = 0, 0, sumIf(f_base_reward, f_status = 1 AND f_missing_source = FALSE) / sumIf(f_base_reward, f_status = 1)) as source_att_balance_share,
+				if(sumIf(f_base_reward, f_status = 1) = 0, 0, sumIf(f_base_reward, f_status = 1 AND f_missing_target = FALSE) / sumIf(f_base_reward, f_status = 1)) as target_att_balance_share,
+				if(sumIf(f_base_reward, f_status = 1) = 0, 0, sumIf(f_base_reward, f_status = 1 AND f_missing_head = FALSE) / sumIf(f_base_reward, f_status = 1)) as head_att_balance_share,
+				if(sumIf(f_base_reward, f_status = 1) = 0, 0, sumIf(f_base_reward, f_status = 1 AND NOT (f_missing_source AND f_missing_target AND f_missing_head)) / sumIf(f_base_reward, f_status = 1)) as any_att_balance_share
+			FROM %[2]s AS r
 			LEFT JOIN t_eth2_pubkeys 
-				ON t_validator_rewards_summary.f_val_idx = t_eth2_pubkeys.f_val_idx
+				ON r.f_val_idx = t_eth2_pubkeys.f_val_idx
 			LEFT JOIN t_proposer_duties 
-				ON t_validator_rewards_summary.f_val_idx = t_proposer_duties.f_val_idx 
-				AND t_validator_rewards_summary.f_epoch = toUInt64(t_proposer_duties.f_proposer_slot/32)
-			WHERE f_epoch = $1 AND f_status = 1 AND f_pool_name != ''
+				ON r.f_val_idx = t_proposer_duties.f_val_idx 
+				AND r.f_epoch = toUInt64(t_proposer_duties.f_proposer_slot/32)
+			WHERE f_epoch = $1 AND f_pool_name != ''
 			GROUP BY t_eth2_pubkeys.f_pool_name, f_epoch`
+
+	insertPoolSummaryQuery = `
+	INSERT INTO %s (
+		f_pool_name,
+		f_epoch,
+		aggregated_rewards,
+		aggregated_max_rewards,
+		count_sync_committee,
+		count_missing_source,
+		count_missing_target,
+		count_missing_head,
+		count_expected_attestations,
+		count_attestations_included,
+		proposed_blocks_performance,
+		missed_blocks_performance,
+		number_active_vals,
+		avg_inclusion_delay,
+		f_active,
+		source_att_balance_share,
+		target_att_balance_share,
+		head_att_balance_share,
+		any_att_balance_share)
+		VALUES`
+
+	selectPoolEfficienciesQuery = `
+		SELECT f_pool_name, aggregated_rewards, aggregated_max_rewards
+		FROM %s FINAL
+		WHERE f_epoch = %d AND f_active = TRUE`
 )
 
 func (p *DBService) InsertPoolSummary(epoch phase0.Epoch) error {
 
-	query := fmt.Sprintf(insertPoolSummary, poolsTables)
+	query := fmt.Sprintf(insertPoolSummary, poolsTables, p.valRewardsSource())
 	var err error
 	startTime := time.Now()
 
@@ -53,3 +93,116 @@ func (p *DBService) InsertPoolSummary(epoch phase0.Epoch) error {
 
 	return err
 }
+
+func poolSummaryInput(summaries []spec.PoolSummary) proto.Input {
+	// one object per column
+	var (
+		f_pool_name                 proto.ColStr
+		f_epoch                     proto.ColUInt64
+		aggregated_rewards          proto.ColUInt64
+		aggregated_max_rewards      proto.ColUInt64
+		count_sync_committee        proto.ColUInt64
+		count_missing_source        proto.ColUInt64
+		count_missing_target        proto.ColUInt64
+		count_missing_head          proto.ColUInt64
+		count_expected_attestations proto.ColUInt64
+		count_attestations_included proto.ColUInt64
+		proposed_blocks_performance proto.ColUInt64
+		missed_blocks_performance   proto.ColUInt64
+		number_active_vals          proto.ColUInt64
+		avg_inclusion_delay         proto.ColFloat32
+		f_active                    proto.ColBool
+		source_att_balance_share    proto.ColFloat64
+		target_att_balance_share    proto.ColFloat64
+		head_att_balance_share      proto.ColFloat64
+		any_att_balance_share       proto.ColFloat64
+	)
+
+	for _, summary := range summaries {
+		f_pool_name.Append(summary.PoolName)
+		f_epoch.Append(uint64(summary.Epoch))
+		aggregated_rewards.Append(uint64(summary.AggregatedRewards)) // same cast as the insert from t_validator_rewards_summary
+		aggregated_max_rewards.Append(uint64(summary.AggregatedMaxRewards))
+		count_sync_committee.Append(summary.SyncCommittee)
+		count_missing_source.Append(summary.MissingSource)
+		count_missing_target.Append(summary.MissingTarget)
+		count_missing_head.Append(summary.MissingHead)
+		count_expected_attestations.Append(summary.ExpectedAttestations)
+		count_attestations_included.Append(summary.AttestationsIncluded)
+		proposed_blocks_performance.Append(summary.ProposedBlocks)
+		missed_blocks_performance.Append(summary.MissedBlocks)
+		number_active_vals.Append(summary.ActiveValidators)
+		avg_inclusion_delay.Append(float32(summary.AvgInclusionDelay))
+		f_active.Append(summary.Active())
+		source_att_balance_share.Append(summary.SourceAttBalanceShare)
+		target_att_balance_share.Append(summary.TargetAttBalanceShare)
+		head_att_balance_share.Append(summary.HeadAttBalanceShare)
+		any_att_balance_share.Append(summary.AnyAttBalanceShare)
+	}
+
+	return proto.Input{
+		{Name: "f_pool_name", Data: f_pool_name},
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "aggregated_rewards", Data: aggregated_rewards},
+		{Name: "aggregated_max_rewards", Data: aggregated_max_rewards},
+		{Name: "count_sync_committee", Data: count_sync_committee},
+		{Name: "count_missing_source", Data: count_missing_source},
+		{Name: "count_missing_target", Data: count_missing_target},
+		{Name: "count_missing_head", Data: count_missing_head},
+		{Name: "count_expected_attestations", Data: count_expected_attestations},
+		{Name: "count_attestations_included", Data: count_attestations_included},
+		{Name: "proposed_blocks_performance", Data: proposed_blocks_performance},
+		{Name: "missed_blocks_performance", Data: missed_blocks_performance},
+		{Name: "number_active_vals", Data: number_active_vals},
+		{Name: "avg_inclusion_delay", Data: avg_inclusion_delay},
+		{Name: "f_active", Data: f_active},
+		{Name: "source_att_balance_share", Data: source_att_balance_share},
+		{Name: "target_att_balance_share", Data: target_att_balance_share},
+		{Name: "head_att_balance_share", Data: head_att_balance_share},
+		{Name: "any_att_balance_share", Data: any_att_balance_share},
+	}
+}
+
+// PersistPoolSummaries writes pool summaries aggregated in memory, when the validator rewards are not persisted
+func (p *DBService) PersistPoolSummaries(data []spec.PoolSummary) error {
+	persistObj := PersistableObject[spec.PoolSummary]{
+		input: poolSummaryInput,
+		table: poolsTables,
+		query: insertPoolSummaryQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting pool summaries: %s", err.Error())
+	}
+	return err
+}
+
+// RetrievePoolEfficiencies returns the efficiency (rewards / max rewards, in %) of each pool at the given epoch
+func (p *DBService) RetrievePoolEfficiencies(epoch phase0.Epoch) (map[string]float64, error) {
+	var dest []struct {
+		F_pool_name   string `ch:"f_pool_name"`
+		F_rewards     uint64 `ch:"aggregated_rewards"`
+		F_max_rewards uint64 `ch:"aggregated_max_rewards"`
+	}
+
+	err := p.highSelect(
+		fmt.Sprintf(selectPoolEfficienciesQuery, poolsTables, epoch),
+		&dest)
+	if err != nil {
+		return nil, err
+	}
+
+	efficiencies := make(map[string]float64, len(dest))
+	for _, row := range dest {
+		if row.F_max_rewards == 0 {
+			continue
+		}
+		efficiencies[row.F_pool_name] = float64(row.F_rewards) / float64(row.F_max_rewards) * 100
+	}
+	return efficiencies, nil
+}
@@ -70,6 +70,12 @@ var (
 			"table",
 		},
 	)
+	Connected = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "connected",
+		Help:      "Whether the connection used to write to the database is up (1) or being recovered (0)",
+	})
 )
 
 func (r *DBService) initMonitorMetrics() {
@@ -96,6 +102,41 @@ func (r *DBService) initMonitorMetrics() {
 		blsToExecutionChangeTable,
 		depositsTable,
 		eth1DepositsTable,
+		blockArrivalsTable,
+		epochCheckpointsTable,
+		valRewardsBucketsTable,
+		equivocationsTable,
+		consolidationRequestsTable,
+		compoundingBalancesTable,
+		chainSplitsTable,
+		valRewardsDeltaTable,
+		valStatusAnomaliesTable,
+		orphanEpochsTable,
+		epochCompletenessTable,
+		rootMismatchesTable,
+		registryAnomaliesTable,
+		transactionLogsTable,
+		selfCheckDiscrepanciesTable,
+		epochRandaoTable,
+		voteTalliesTable,
+		poolLuckTable,
+		balanceSamplesTable,
+		voluntaryExitEventsTable,
+		voluntaryExitsTable,
+		poolReconciliationTable,
+		effectiveBalanceHistogramTable,
+		dualWriteMismatchesTable,
+		stateProofsTable,
+		proposerFairnessTable,
+		validatorKeysTable,
+		epochChurnTable,
+		poolClientDiversityTable,
+		poolPubkeysTable,
+		customPoolKeysTable,
+		protectionAnomaliesTable,
+		dataQualityNotesTable,
+		attestationInclusionsTable,
+		reorgForkChoiceTable,
 	}
 
 	for _, tableName := range tablesArr {
@@ -114,6 +155,10 @@ func (r *DBService) GetPrometheusMetrics() *metrics.MetricsModule {
 
 	metricsMod.AddIndvMetric(r.lastProcessedSlotMetric())
 	metricsMod.AddIndvMetric(r.lastProcessedEpochMetric())
+	metricsMod.AddIndvMetric(r.connectedMetric())
+	if r.dualWriteUrl != "" {
+		metricsMod.AddIndvMetric(r.dualWriteMetric())
+	}
 	return metricsMod
 }
 
@@ -205,6 +250,25 @@ func (r *DBService) lastProcessedSlotMetric() *metrics.IndvMetrics {
 	return lastSlot
 }
 
+func (r *DBService) connectedMetric() *metrics.IndvMetrics {
+	initFn := func() error {
+		prometheus.MustRegister(Connected)
+		return nil
+	}
+	updateFn := func() (interface{}, error) {
+		return nil, nil
+	}
+	connected, err := metrics.NewIndvMetrics(
+		"connected",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		return nil
+	}
+	return connected
+}
+
 func (r *DBService) getMonitorMetrics() map[string]DBMonitorMetrics {
 	r.metricsMu.RLock()
 	defer r.metricsMu.RUnlock()
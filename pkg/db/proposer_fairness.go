@@ -0,0 +1,130 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	proposerFairnessTable       = "t_proposer_fairness"
+	insertProposerFairnessQuery = `
+	INSERT INTO %s (
+		f_snapshot_epoch,
+		f_val_idx,
+		f_epoch,
+		f_first_epoch,
+		f_epochs,
+		f_expected_proposals,
+		f_duties,
+		f_proposed,
+		f_drift)
+		VALUES`
+
+	selectProposerFairnessQuery = `
+		SELECT
+			f_snapshot_epoch,
+			f_val_idx,
+			f_epoch,
+			f_first_epoch,
+			f_epochs,
+			f_expected_proposals,
+			f_duties,
+			f_proposed
+		FROM %s FINAL`
+)
+
+func proposerFairnessInput(values []spec.ProposerFairness) proto.Input {
+	// one object per column
+	var (
+		f_snapshot_epoch     proto.ColUInt64
+		f_val_idx            proto.ColUInt64
+		f_epoch              proto.ColUInt64
+		f_first_epoch        proto.ColUInt64
+		f_epochs             proto.ColUInt64
+		f_expected_proposals proto.ColFloat64
+		f_duties             proto.ColUInt64
+		f_proposed           proto.ColUInt64
+		f_drift              proto.ColFloat64
+	)
+
+	for _, value := range values {
+		f_snapshot_epoch.Append(uint64(value.SnapshotEpoch))
+		f_val_idx.Append(uint64(value.ValIdx))
+		f_epoch.Append(uint64(value.Epoch))
+		f_first_epoch.Append(uint64(value.FirstEpoch))
+		f_epochs.Append(value.Epochs)
+		f_expected_proposals.Append(value.ExpectedProposals)
+		f_duties.Append(value.Duties)
+		f_proposed.Append(value.Proposed)
+		f_drift.Append(value.Drift())
+	}
+
+	return proto.Input{
+		{Name: "f_snapshot_epoch", Data: f_snapshot_epoch},
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_first_epoch", Data: f_first_epoch},
+		{Name: "f_epochs", Data: f_epochs},
+		{Name: "f_expected_proposals", Data: f_expected_proposals},
+		{Name: "f_duties", Data: f_duties},
+		{Name: "f_proposed", Data: f_proposed},
+		{Name: "f_drift", Data: f_drift},
+	}
+}
+
+func (p *DBService) PersistProposerFairness(data []spec.ProposerFairness) error {
+	persistObj := PersistableObject[spec.ProposerFairness]{
+		input: proposerFairnessInput,
+		table: proposerFairnessTable,
+		query: insertProposerFairnessQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting proposer fairness: %s", err.Error())
+	}
+	return err
+}
+
+// RetrieveProposerFairness returns the values of every validator in its last snapshot, to resume their accumulation
+func (p *DBService) RetrieveProposerFairness() ([]spec.ProposerFairness, error) {
+	var dest []struct {
+		F_snapshot_epoch     uint64  `ch:"f_snapshot_epoch"`
+		F_val_idx            uint64  `ch:"f_val_idx"`
+		F_epoch              uint64  `ch:"f_epoch"`
+		F_first_epoch        uint64  `ch:"f_first_epoch"`
+		F_epochs             uint64  `ch:"f_epochs"`
+		F_expected_proposals float64 `ch:"f_expected_proposals"`
+		F_duties             uint64  `ch:"f_duties"`
+		F_proposed           uint64  `ch:"f_proposed"`
+	}
+
+	err := p.highSelect(
+		fmt.Sprintf(selectProposerFairnessQuery, proposerFairnessTable),
+		&dest)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]spec.ProposerFairness, 0, len(dest))
+	for _, row := range dest {
+		values = append(values, spec.ProposerFairness{
+			SnapshotEpoch:     phase0.Epoch(row.F_snapshot_epoch),
+			ValIdx:            phase0.ValidatorIndex(row.F_val_idx),
+			Epoch:             phase0.Epoch(row.F_epoch),
+			FirstEpoch:        phase0.Epoch(row.F_first_epoch),
+			Epochs:            row.F_epochs,
+			ExpectedProposals: row.F_expected_proposals,
+			Duties:            row.F_duties,
+			Proposed:          row.F_proposed,
+		})
+	}
+	return values, nil
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/ClickHouse/ch-go"
+	"github.com/ClickHouse/ch-go/proto"
+)
+
+var (
+	healthCheckInterval = 30 * time.Second
+	healthCheckTimeout  = 10 * time.Second
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// WithReconnectTimeout makes the writes wait up to timeout for the database to come back after losing the connection.
+// While it is down, the pending batches are kept and written once reconnected, pausing the routines that persist them
+func WithReconnectTimeout(timeout time.Duration) DBServiceOption {
+	return func(s *DBService) error {
+		s.reconnectTimeout = timeout
+		return nil
+	}
+}
+
+// doWithReconnect sends the query through the low level writer, reconnecting and sending it again
+// if the connection was lost. It must be called holding the writer
+func (p *DBService) doWithReconnect(writer *lowLevelWriter, query string, input proto.Input) error {
+	deadline := time.Now().Add(p.reconnectTimeout)
+	for {
+		err := writer.client.Do(p.ctx, ch.Query{
+			Body:  query,
+			Input: input,
+		})
+		if err == nil || !p.connectionLost(writer, err) || !time.Now().Before(deadline) {
+			return err
+		}
+		log.Warnf("connection to the database lost, pausing the writes until it is back: %s", err)
+		if err := p.reconnectLowLevel(p.ctx, writer, deadline); err != nil {
+			return err
+		}
+	}
+}
+
+// connectionLost returns whether the error comes from the connection rather than from the query
+func (p *DBService) connectionLost(writer *lowLevelWriter, err error) bool {
+	if ch.IsException(err) || p.ctx.Err() != nil {
+		return false
+	}
+	var netErr net.Error
+	return writer.client.IsClosed() ||
+		errors.Is(err, ch.ErrClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &netErr)
+}
+
+// reconnectLowLevel dials the database until it answers or the deadline is reached. It must be called holding the writer.
+// The high level client keeps a pool of connections that are dialed again by the driver
+func (p *DBService) reconnectLowLevel(ctx context.Context, writer *lowLevelWriter, deadline time.Time) error {
+	Connected.Set(0)
+	writer.client.Close()
+
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		conn, err := ch.Dial(ctx, ParseChUrlIntoOptionsLowLevel(p.connectionUrl))
+		if err == nil {
+			writer.client = conn
+			Connected.Set(1)
+			log.Infof("reconnected to the database after %d attempts, resuming the writes", attempt)
+			return nil
+		}
+
+		wait := min(backoff, time.Until(deadline))
+		if wait <= 0 {
+			return fmt.Errorf("database still unreachable after %d attempts: %s", attempt, err)
+		}
+		log.Warnf("could not reconnect to the database (attempt %d), retrying in %s: %s", attempt, wait, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff = min(2*backoff, maxReconnectBackoff)
+	}
+}
+
+// runHealthCheck pings the database periodically, so a lost connection is recovered
+// before the next write instead of failing it
+func (p *DBService) runHealthCheck(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, writer := range p.openWriters() {
+			p.checkWriter(ctx, writer)
+		}
+	}
+}
+
+// checkWriter pings the database through the writer, reconnecting it if it does not answer
+func (p *DBService) checkWriter(ctx context.Context, writer *lowLevelWriter) {
+	writer.Lock()
+	defer writer.Unlock()
+	if writer.closed {
+		return
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	err := writer.client.Ping(pingCtx)
+	cancel()
+	if err != nil && ctx.Err() == nil {
+		log.Warnf("database health check failed, reconnecting: %s", err)
+		err = p.reconnectLowLevel(ctx, writer, time.Now().Add(p.reconnectTimeout))
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("could not reconnect to the database, retrying in %s: %s", healthCheckInterval, err)
+		}
+	}
+}
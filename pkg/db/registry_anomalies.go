@@ -0,0 +1,59 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	registryAnomaliesTable       = "t_registry_anomalies"
+	insertRegistryAnomaliesQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_val_idx,
+		f_kind,
+		f_detail)
+		VALUES`
+)
+
+func registryAnomaliesInput(anomalies []spec.RegistryAnomaly) proto.Input {
+	// one object per column
+	var (
+		f_epoch   proto.ColUInt64
+		f_val_idx proto.ColUInt64
+		f_kind    proto.ColStr
+		f_detail  proto.ColStr
+	)
+
+	for _, anomaly := range anomalies {
+		f_epoch.Append(uint64(anomaly.Epoch))
+		f_val_idx.Append(uint64(anomaly.ValidatorIndex))
+		f_kind.Append(string(anomaly.Kind))
+		f_detail.Append(anomaly.Detail)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_kind", Data: f_kind},
+		{Name: "f_detail", Data: f_detail},
+	}
+}
+
+func (p *DBService) PersistRegistryAnomalies(data []spec.RegistryAnomaly) error {
+	persistObj := PersistableObject[spec.RegistryAnomaly]{
+		input: registryAnomaliesInput,
+		table: registryAnomaliesTable,
+		query: insertRegistryAnomaliesQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting registry anomalies: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	reorgForkChoiceTable       = "t_reorg_fork_choice"
+	insertReorgForkChoiceQuery = `
+	INSERT INTO %s (
+		f_reorg_slot,
+		f_slot,
+		f_block_root,
+		f_parent_root,
+		f_weight,
+		f_validity,
+		f_justified_epoch,
+		f_finalized_epoch,
+		f_branch,
+		f_extra_data)
+		VALUES`
+)
+
+func reorgForkChoiceInput(nodes []spec.ReorgForkChoiceNode) proto.Input {
+	// one object per column
+	var (
+		f_reorg_slot      proto.ColUInt64
+		f_slot            proto.ColUInt64
+		f_block_root      proto.ColStr
+		f_parent_root     proto.ColStr
+		f_weight          proto.ColUInt64
+		f_validity        proto.ColStr
+		f_justified_epoch proto.ColUInt64
+		f_finalized_epoch proto.ColUInt64
+		f_branch          proto.ColStr
+		f_extra_data      proto.ColStr
+	)
+
+	for _, node := range nodes {
+		f_reorg_slot.Append(uint64(node.ReorgSlot))
+		f_slot.Append(uint64(node.Slot))
+		f_block_root.Append(node.BlockRoot.String())
+		f_parent_root.Append(node.ParentRoot.String())
+		f_weight.Append(node.Weight)
+		f_validity.Append(node.Validity)
+		f_justified_epoch.Append(uint64(node.JustifiedEpoch))
+		f_finalized_epoch.Append(uint64(node.FinalizedEpoch))
+		f_branch.Append(node.Branch)
+		f_extra_data.Append(node.ExtraData)
+	}
+
+	return proto.Input{
+		{Name: "f_reorg_slot", Data: f_reorg_slot},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_parent_root", Data: f_parent_root},
+		{Name: "f_weight", Data: f_weight},
+		{Name: "f_validity", Data: f_validity},
+		{Name: "f_justified_epoch", Data: f_justified_epoch},
+		{Name: "f_finalized_epoch", Data: f_finalized_epoch},
+		{Name: "f_branch", Data: f_branch},
+		{Name: "f_extra_data", Data: f_extra_data},
+	}
+}
+
+func (p *DBService) PersistReorgForkChoice(data []spec.ReorgForkChoiceNode) error {
+	persistObj := PersistableObject[spec.ReorgForkChoiceNode]{
+		input: reorgForkChoiceInput,
+		table: reorgForkChoiceTable,
+		query: insertReorgForkChoiceQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting reorg fork choice: %s", err.Error())
+	}
+	return err
+}
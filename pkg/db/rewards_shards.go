@@ -0,0 +1,213 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// rewardsShard is a database holding the validator rewards of the validators from its first index
+// up to the first index of the next shard
+type rewardsShard struct {
+	firstValIdx phase0.ValidatorIndex
+	url         string
+	db          *DBService
+}
+
+// WithRewardsShards writes the validator rewards of each range of validators to its own database, so that no
+// single database holds the whole rewards table. The shards are a comma separated list of <first validator index>=<url>,
+// the validators below the first index stay in the main database
+func WithRewardsShards(input string) DBServiceOption {
+	return func(s *DBService) error {
+		shards, err := parseRewardsShards(input)
+		if err != nil {
+			return err
+		}
+		s.rewardsShards = shards
+		return nil
+	}
+}
+
+func parseRewardsShards(input string) ([]*rewardsShard, error) {
+	shards := make([]*rewardsShard, 0)
+	for _, item := range strings.Split(input, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		firstValIdx, url, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rewards shard %s, expected <first validator index>=<url>", item)
+		}
+		valIdx, err := strconv.ParseUint(strings.TrimSpace(firstValIdx), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid first validator index of rewards shard %s: %s", item, err)
+		}
+		url = strings.TrimSpace(url)
+		if !validChUrl(url) {
+			return nil, fmt.Errorf("invalid url of rewards shard %s, expected clickhouse://<user>:<password>@<host>:<port>/<database>", item)
+		}
+		shards = append(shards, &rewardsShard{
+			firstValIdx: phase0.ValidatorIndex(valIdx),
+			url:         url,
+		})
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].firstValIdx < shards[j].firstValIdx })
+	for i := 1; i < len(shards); i++ {
+		if shards[i].firstValIdx == shards[i-1].firstValIdx {
+			return nil, fmt.Errorf("two rewards shards start at validator %d", shards[i].firstValIdx)
+		}
+	}
+	return shards, nil
+}
+
+// validChUrl returns whether the url has the parts ParseChUrlIntoOptionsLowLevel reads
+func validChUrl(url string) bool {
+	_, details, ok := strings.Cut(url, "://")
+	if !ok {
+		return false
+	}
+	credentials, endpoint, ok := strings.Cut(details, "@")
+	return ok && strings.Contains(credentials, ":") && strings.Contains(endpoint, "/")
+}
+
+// connectRewardsShards connects to the databases of the rewards shards, if any
+func (s *DBService) connectRewardsShards() error {
+	if len(s.rewardsShards) > 0 && s.rewardsDelta {
+		return fmt.Errorf("rewards shards do not support the delta-encoded rewards storage")
+	}
+	for _, shard := range s.rewardsShards {
+		db, err := s.connectPeer(shard.url, s.reconnectTimeout)
+		if err != nil {
+			return fmt.Errorf("could not connect to the rewards shard from validator %d: %s", shard.firstValIdx, err)
+		}
+		shard.db = db
+		log.Infof("validator rewards from validator %d written to their own database", shard.firstValIdx)
+	}
+	return nil
+}
+
+// RewardsShards returns the number of databases the validator rewards are spread over, besides the main one
+func (s *DBService) RewardsShards() int {
+	return len(s.rewardsShards)
+}
+
+// rewardsDB returns the database holding the rewards of the validator
+func (s *DBService) rewardsDB(valIdx phase0.ValidatorIndex) *DBService {
+	db := s
+	for _, shard := range s.rewardsShards {
+		if valIdx < shard.firstValIdx {
+			break
+		}
+		db = shard.db
+	}
+	return db
+}
+
+// shardedRewardsSource returns the rewards table of the main database together with the one of every shard,
+// which the main clickhouse server reads through remote() with the address and credentials of the shard url
+func (s *DBService) shardedRewardsSource() string {
+	selects := []string{fmt.Sprintf("SELECT * FROM %s", valRewardsTable)}
+	for _, shard := range s.rewardsShards {
+		opts := ParseChUrlIntoOptionsLowLevel(shard.url)
+		selects = append(selects, fmt.Sprintf("SELECT * FROM remote(%s, %s, %s, %s, %s)",
+			quoteString(opts.Address), quoteString(opts.Database), quoteString(valRewardsTable),
+			quoteString(opts.User), quoteString(opts.Password)))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(selects, " UNION ALL "))
+}
+
+// quoteString returns the string as a clickhouse string literal
+func quoteString(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+func (p *DBService) persistShardedValidatorRewards(data []spec.ValidatorRewards) error {
+	batches := make(map[*DBService]*PersistableObject[spec.ValidatorRewards])
+	for _, item := range data {
+		db := p.rewardsDB(item.ValidatorIndex)
+		if _, ok := batches[db]; !ok {
+			batches[db] = &PersistableObject[spec.ValidatorRewards]{
+				input: rewardsInput,
+				table: valRewardsTable,
+				query: insertValidatorRewardsQuery,
+			}
+		}
+		batches[db].Append(item)
+	}
+
+	var err error
+	for db, persistObj := range batches {
+		if persistErr := db.Persist(persistObj.ExportPersist()); persistErr != nil {
+			log.Errorf("error persisting validator rewards: %s", persistErr.Error())
+			err = persistErr
+		}
+	}
+	return err
+}
+
+// deleteRewardsShards deletes the validator rewards from the shards too
+func (s *DBService) deleteRewardsShards(obj DeletableObject) {
+	if obj.Table() != valRewardsTable {
+		return
+	}
+	for _, shard := range s.rewardsShards {
+		if err := shard.db.Delete(obj); err != nil {
+			log.Errorf("error deleting validator rewards in the shard from validator %d: %s", shard.firstValIdx, err)
+		}
+	}
+}
+
+// QueryRewardsRows runs the given query over the validator rewards, sorted by validator, in the main database
+// and then in every shard, as their ranges of validators are sorted too. The limit and offset apply to the merged rows
+func (p *DBService) QueryRewardsRows(
+	query string,
+	args []any,
+	limit int64,
+	offset int64,
+	onColumns func([]string) error,
+	onRow func([]any) error) (int, error) {
+
+	if len(p.rewardsShards) == 0 {
+		return p.QueryRows(fmt.Sprintf("%s LIMIT %d OFFSET %d", query, limit, offset), args, onColumns, onRow)
+	}
+
+	dbs := []*DBService{p}
+	for _, shard := range p.rewardsShards {
+		dbs = append(dbs, shard.db)
+	}
+
+	skipped, count := int64(0), 0
+	shardQuery := fmt.Sprintf("%s LIMIT %d", query, limit+offset)
+	for i, db := range dbs {
+		if int64(count) >= limit {
+			break
+		}
+		_, err := db.QueryRows(shardQuery, args,
+			func(columns []string) error {
+				if i > 0 {
+					return nil // already passed by the main database
+				}
+				return onColumns(columns)
+			},
+			func(row []any) error {
+				if skipped < offset {
+					skipped++
+					return nil
+				}
+				if int64(count) >= limit {
+					return nil
+				}
+				count++
+				return onRow(row)
+			})
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,61 @@
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/testutil"
+)
+
+func TestQueryRewardsRows(t *testing.T) {
+	main := testutil.StartClickHouse(t)
+	shard := testutil.StartClickHouse(t)
+	dbClient, err := db.New(context.Background(), main.URL(), db.WithRewardsShards(fmt.Sprintf("3=%s", shard.URL())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbClient.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer dbClient.Finish()
+
+	// the shard has its migrations applied on connection
+	main.Exec(t, `INSERT INTO t_validator_rewards_summary (f_val_idx, f_epoch, f_reward) VALUES (0, 10, 1), (1, 10, 2), (2, 10, 3)`)
+	shard.Exec(t, `INSERT INTO t_validator_rewards_summary (f_val_idx, f_epoch, f_reward) VALUES (3, 10, 4), (4, 10, 5)`)
+
+	query := fmt.Sprintf("SELECT f_val_idx, f_reward FROM %s WHERE f_epoch = $1 ORDER BY f_val_idx", dbClient.RewardsSource())
+	for _, test := range []struct {
+		limit    int64
+		offset   int64
+		expected []uint64
+	}{
+		{limit: 10, offset: 0, expected: []uint64{0, 1, 2, 3, 4}},
+		{limit: 2, offset: 0, expected: []uint64{0, 1}},
+		{limit: 2, offset: 2, expected: []uint64{2, 3}},
+		{limit: 10, offset: 4, expected: []uint64{4}},
+		{limit: 10, offset: 5, expected: []uint64{}},
+	} {
+		columns := make([]string, 0)
+		indexes := make([]any, 0)
+		count, err := dbClient.QueryRewardsRows(query, []any{uint64(10)}, test.limit, test.offset,
+			func(names []string) error {
+				columns = append(columns, names...)
+				return nil
+			},
+			func(row []any) error {
+				indexes = append(indexes, row[0])
+				return nil
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(columns) != 2 {
+			t.Errorf("expected the columns once, got %v", columns)
+		}
+		if count != len(test.expected) || fmt.Sprint(indexes) != fmt.Sprint(test.expected) {
+			t.Errorf("limit %d offset %d: expected validators %v, got %v (%d rows)", test.limit, test.offset, test.expected, indexes, count)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	rootMismatchesTable       = "t_root_mismatches"
+	insertRootMismatchesQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_kind,
+		f_cached_root,
+		f_node_root,
+		f_justified_epoch,
+		f_justified_root,
+		f_timestamp)
+		VALUES`
+)
+
+func rootMismatchesInput(mismatches []spec.RootMismatch) proto.Input {
+	// one object per column
+	var (
+		f_slot            proto.ColUInt64
+		f_kind            proto.ColStr
+		f_cached_root     proto.ColStr
+		f_node_root       proto.ColStr
+		f_justified_epoch proto.ColUInt64
+		f_justified_root  proto.ColStr
+		f_timestamp       proto.ColUInt64
+	)
+
+	for _, mismatch := range mismatches {
+		f_slot.Append(uint64(mismatch.Slot))
+		f_kind.Append(string(mismatch.Kind))
+		f_cached_root.Append(mismatch.CachedRoot.String())
+		f_node_root.Append(mismatch.NodeRoot.String())
+		f_justified_epoch.Append(uint64(mismatch.JustifiedEpoch))
+		f_justified_root.Append(mismatch.JustifiedRoot.String())
+		f_timestamp.Append(uint64(mismatch.Timestamp))
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_kind", Data: f_kind},
+		{Name: "f_cached_root", Data: f_cached_root},
+		{Name: "f_node_root", Data: f_node_root},
+		{Name: "f_justified_epoch", Data: f_justified_epoch},
+		{Name: "f_justified_root", Data: f_justified_root},
+		{Name: "f_timestamp", Data: f_timestamp},
+	}
+}
+
+func (p *DBService) PersistRootMismatches(data []spec.RootMismatch) error {
+	persistObj := PersistableObject[spec.RootMismatch]{
+		input: rootMismatchesInput,
+		table: rootMismatchesTable,
+		query: insertRootMismatchesQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting root mismatches: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ClickHouse/ch-go"
+)
+
+var schemaNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithSchema stores the metrics in the given database of the clickhouse server instead of the one in the url,
+// so several analyzers (i.e. with different metric versions) can share a server.
+// The database is created on connection, and the migrations are tracked per database
+func WithSchema(schema string) DBServiceOption {
+	return func(s *DBService) error {
+		if schema == "" {
+			return nil
+		}
+		if !schemaNameRegex.MatchString(schema) {
+			return fmt.Errorf("invalid database schema %q, only letters, digits and underscores are allowed", schema)
+		}
+		s.schema = schema
+		return nil
+	}
+}
+
+// createSchema creates the database of the schema, connecting to the database of the url.
+// The url is then pointed to the schema, so the rest of the connections and the migrations use it
+func (s *DBService) createSchema() error {
+	if s.schema == "" {
+		return nil
+	}
+	ctx := context.Background()
+	conn, err := ch.Dial(ctx, ParseChUrlIntoOptionsLowLevel(s.connectionUrl))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = conn.Do(ctx, ch.Query{
+		Body: fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", s.schema),
+	})
+	if err != nil {
+		return fmt.Errorf("could not create database schema %s: %s", s.schema, err)
+	}
+	s.connectionUrl = ReplaceUrlDatabase(s.connectionUrl, s.schema)
+	log.Infof("using database schema %s", s.schema)
+	return nil
+}
+
+// ReplaceUrlDatabase returns the clickhouse url with the given database, keeping the credentials and parameters.
+// The database is appended when the url has no path
+func ReplaceUrlDatabase(url string, database string) string {
+	protocolAndDetails := strings.SplitN(url, "://", 2)
+	if len(protocolAndDetails) != 2 {
+		return url
+	}
+	details := protocolAndDetails[1]
+
+	// the path starts after the host, the credentials may contain slashes
+	hostStart := strings.LastIndex(details, "@") + 1
+	params := ""
+	if paramsStart := strings.Index(details[hostStart:], "?"); paramsStart >= 0 {
+		params = details[hostStart+paramsStart:]
+		details = details[:hostStart+paramsStart]
+	}
+	hostEnd := len(details)
+	if pathStart := strings.Index(details[hostStart:], "/"); pathStart >= 0 {
+		hostEnd = hostStart + pathStart
+	}
+	return protocolAndDetails[0] + "://" + details[:hostEnd] + "/" + database + params
+}
@@ -0,0 +1,24 @@
+package db
+
+import "testing"
+
+func TestReplaceUrlDatabase(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"with path", "clickhouse://u:p@host:9000/goteth", "clickhouse://u:p@host:9000/schema"},
+		{"without path", "clickhouse://u:p@host:9000", "clickhouse://u:p@host:9000/schema"},
+		{"trailing slash", "clickhouse://u:p@host:9000/", "clickhouse://u:p@host:9000/schema"},
+		{"with query", "clickhouse://u:p@host:9000/goteth?x-multi-statement=true", "clickhouse://u:p@host:9000/schema?x-multi-statement=true"},
+		{"query without path", "clickhouse://u:p@host:9000?x-multi-statement=true", "clickhouse://u:p@host:9000/schema?x-multi-statement=true"},
+		{"slash in password", "clickhouse://u:p/w@host:9000", "clickhouse://u:p/w@host:9000/schema"},
+		{"not an url", "host:9000/goteth", "host:9000/goteth"},
+	}
+	for _, test := range tests {
+		if url := ReplaceUrlDatabase(test.url, "schema"); url != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, url)
+		}
+	}
+}
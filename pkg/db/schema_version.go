@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+var (
+	selectSchemaVersionQuery = `
+		SELECT version, dirty
+		FROM %s.schema_migrations
+		ORDER BY sequence DESC
+		LIMIT 1`
+)
+
+// SchemaVersion is the migration version of a database, compared with the migrations shipped with the binary
+type SchemaVersion struct {
+	Exists  bool   // the database (schema) exists, it is created on connection otherwise
+	Current uint64 // last migration applied, 0 if none
+	Dirty   bool   // the last migration failed halfway and has to be fixed by hand
+	Latest  uint64 // last migration shipped with the binary
+}
+
+// Pending returns the number of migrations that would be applied on connection
+func (v SchemaVersion) Pending() uint64 {
+	if v.Current >= v.Latest {
+		return 0
+	}
+	return v.Latest - v.Current
+}
+
+// CheckSchemaVersion reads the migration version of the database without applying the migrations
+// or creating the schema, so nothing is written
+func CheckSchemaVersion(ctx context.Context, url string, schema string) (SchemaVersion, error) {
+	version := SchemaVersion{}
+	latest, err := latestMigration()
+	if err != nil {
+		return version, err
+	}
+	version.Latest = latest
+
+	opts := ParseChUrlIntoOptionsHighLevel(url)
+	conn, err := clickhouse.Open(&opts)
+	if err != nil {
+		return version, err
+	}
+	defer conn.Close()
+	if err := conn.Ping(ctx); err != nil {
+		return version, err
+	}
+
+	database := opts.Auth.Database
+	if schema != "" {
+		database = schema
+	}
+	var count uint64
+	if err := conn.QueryRow(ctx, "SELECT count() FROM system.databases WHERE name = $1", database).Scan(&count); err != nil {
+		return version, err
+	}
+	if count == 0 {
+		return version, nil
+	}
+	version.Exists = true
+
+	if err := conn.QueryRow(ctx, "SELECT count() FROM system.tables WHERE database = $1 AND name = 'schema_migrations'", database).Scan(&count); err != nil {
+		return version, err
+	}
+	if count == 0 {
+		return version, nil // no migration applied yet
+	}
+
+	var (
+		current int64
+		dirty   uint8
+	)
+	err = conn.QueryRow(ctx, fmt.Sprintf(selectSchemaVersionQuery, database)).Scan(&current, &dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return version, fmt.Errorf("could not read the schema version: %s", err)
+	}
+	if current > 0 {
+		version.Current = uint64(current)
+	}
+	version.Dirty = dirty != 0
+	return version, nil
+}
+
+// latestMigration returns the version of the last migration in the migrations directory
+func latestMigration() (uint64, error) {
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return 0, fmt.Errorf("could not read the migrations: %s", err)
+	}
+	latest := uint64(0)
+	for _, entry := range entries {
+		number, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok || !strings.HasSuffix(entry.Name(), ".up.sql") {
+			continue
+		}
+		version, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			continue
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
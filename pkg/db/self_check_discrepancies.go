@@ -0,0 +1,67 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	selfCheckDiscrepanciesTable       = "t_self_check_discrepancies"
+	insertSelfCheckDiscrepanciesQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_slot,
+		f_kind,
+		f_val_idx,
+		f_computed,
+		f_node)
+		VALUES`
+)
+
+func selfCheckDiscrepanciesInput(discrepancies []spec.SelfCheckDiscrepancy) proto.Input {
+	// one object per column
+	var (
+		f_epoch    proto.ColUInt64
+		f_slot     proto.ColUInt64
+		f_kind     proto.ColStr
+		f_val_idx  proto.ColUInt64
+		f_computed proto.ColStr
+		f_node     proto.ColStr
+	)
+
+	for _, discrepancy := range discrepancies {
+		f_epoch.Append(uint64(discrepancy.Epoch))
+		f_slot.Append(uint64(discrepancy.Slot))
+		f_kind.Append(string(discrepancy.Kind))
+		f_val_idx.Append(uint64(discrepancy.ValidatorIndex))
+		f_computed.Append(discrepancy.Computed)
+		f_node.Append(discrepancy.Node)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_kind", Data: f_kind},
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_computed", Data: f_computed},
+		{Name: "f_node", Data: f_node},
+	}
+}
+
+func (p *DBService) PersistSelfCheckDiscrepancies(data []spec.SelfCheckDiscrepancy) error {
+	persistObj := PersistableObject[spec.SelfCheckDiscrepancy]{
+		input: selfCheckDiscrepanciesInput,
+		table: selfCheckDiscrepanciesTable,
+		query: insertSelfCheckDiscrepanciesQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting self-check discrepancies: %s", err.Error())
+	}
+	return err
+}
@@ -5,9 +5,9 @@ import (
 	"fmt"
 
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/ClickHouse/ch-go"
 	"github.com/ClickHouse/ch-go/proto"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	api "github.com/attestantio/go-eth2-client/api/v1"
@@ -32,13 +32,28 @@ type DBService struct {
 	ctx           context.Context
 	connectionUrl string // the url might not be necessary (better to remove it?¿)
 
-	lowLevelClient  *ch.Client  // for bulk loads, mainly insert
-	highLevelClient driver.Conn // for side tasks, like Select and Delete
+	writers         []*lowLevelWriter    // for bulk loads, mainly insert
+	idleWriters     chan *lowLevelWriter // writers not in use
+	writersNum      int                  // low level connections to open
+	writersMu       sync.Mutex
+	writeTime       atomic.Int64 // nanoseconds spent in the bulk loads, without waiting for a connection
+	highLevelClient driver.Conn  // for side tasks, like Select and Delete
 
 	monitorMetrics map[string]*DBMonitorMetrics // map table and metrics
-	lowMu          sync.Mutex
 	highMu         sync.Mutex
 	metricsMu      sync.RWMutex
+
+	rewardsDelta bool // validator rewards are stored delta-encoded
+
+	schema string // database of the server where the metrics are stored, the one in the url if empty
+
+	reconnectTimeout time.Duration      // how long the writes wait for the database after losing the connection
+	healthCancel     context.CancelFunc // stops the health checks
+
+	dualWriteUrl string     // secondary database where every batch is written too, none if empty
+	secondary    *DBService // connection to the secondary database
+
+	rewardsShards []*rewardsShard // databases holding the validator rewards of each range of validators, sorted
 }
 
 func New(ctx context.Context, url string, options ...DBServiceOption) (*DBService, error) {
@@ -47,6 +62,8 @@ func New(ctx context.Context, url string, options ...DBServiceOption) (*DBServic
 		ctx:            ctx,
 		connectionUrl:  url,
 		monitorMetrics: make(map[string]*DBMonitorMetrics),
+		idleWriters:    make(chan *lowLevelWriter, MaxWriters),
+		writersNum:     1,
 	}
 
 	pService.initMonitorMetrics()
@@ -62,7 +79,12 @@ func New(ctx context.Context, url string, options ...DBServiceOption) (*DBServic
 }
 
 func (s *DBService) Connect() error {
-	err := s.ConnectLowLevel()
+	err := s.createSchema()
+	if err != nil {
+		return err
+	}
+
+	err = s.ConnectLowLevel()
 	if err != nil {
 		return err
 	}
@@ -71,10 +93,47 @@ func (s *DBService) Connect() error {
 	if err != nil {
 		return err
 	}
+
+	if s.dualWriteUrl != "" {
+		if err := s.connectSecondary(); err != nil {
+			return err
+		}
+	}
+	if err := s.connectRewardsShards(); err != nil {
+		return err
+	}
+
+	Connected.Set(1)
+	healthCtx, cancel := context.WithCancel(s.ctx)
+	s.healthCancel = cancel
+	go s.runHealthCheck(healthCtx)
 	return nil
 
 }
 
+// connectPeer connects to another database with the same storage options, i.e. the secondary one or a shard.
+// The migrations are applied to it on connection
+func (s *DBService) connectPeer(url string, reconnectTimeout time.Duration) (*DBService, error) {
+	peer := &DBService{
+		ctx:              s.ctx,
+		connectionUrl:    url,
+		monitorMetrics:   make(map[string]*DBMonitorMetrics),
+		rewardsDelta:     s.rewardsDelta,
+		reconnectTimeout: reconnectTimeout,
+		idleWriters:      make(chan *lowLevelWriter, MaxWriters),
+		writersNum:       s.writersNum,
+	}
+	peer.initMonitorMetrics()
+
+	if err := peer.ConnectLowLevel(); err != nil {
+		return nil, err
+	}
+	if err := peer.ConnectHighLevel(); err != nil {
+		return nil, err
+	}
+	return peer, nil
+}
+
 func WithUrl(url string) DBServiceOption {
 	return func(s *DBService) error {
 		s.connectionUrl = url
@@ -83,9 +142,22 @@ func WithUrl(url string) DBServiceOption {
 }
 
 func (p *DBService) Finish() {
+	if p.healthCancel != nil {
+		p.healthCancel()
+	}
 
-	p.lowLevelClient.Close()
+	for _, writer := range p.openWriters() {
+		writer.Close()
+	}
 	p.highLevelClient.Close()
+	if p.secondary != nil {
+		p.secondary.Finish()
+	}
+	for _, shard := range p.rewardsShards {
+		if shard.db != nil {
+			shard.db.Finish()
+		}
+	}
 	log.Infof("Routines finished...")
 	log.Infof("closing connection to database server...")
 	log.Infof("connection to database server closed...")
@@ -150,7 +222,40 @@ type PersistableObject[
 		spec.AgnosticSlashing |
 		spec.BLSToExecutionChange |
 		spec.Deposit |
-		spec.ETH1Deposit] struct {
+		spec.ETH1Deposit |
+		spec.BlockArrival |
+		spec.EpochCheckpoints |
+		spec.Equivocation |
+		spec.ConsolidationRequest |
+		spec.CompoundingBalance |
+		spec.ChainSplit |
+		spec.ValidatorRewardsDelta |
+		spec.ValidatorRewardsKeyframe |
+		spec.ValidatorStatusAnomaly |
+		spec.EpochCompleteness |
+		spec.RootMismatch |
+		spec.RegistryAnomaly |
+		spec.TransactionLog |
+		spec.SelfCheckDiscrepancy |
+		spec.EpochRandao |
+		spec.VoteTally |
+		spec.ValidatorBalanceSample |
+		spec.VoluntaryExitEvent |
+		spec.VoluntaryExit |
+		spec.EffectiveBalanceBucket |
+		DualWriteMismatch |
+		spec.StateProof |
+		spec.ProposerFairness |
+		spec.ValidatorKey |
+		spec.EpochChurn |
+		spec.PoolKey |
+		spec.ProtectionAnomaly |
+		spec.PoolSummary |
+		spec.DataQualityNote |
+		spec.AttestationInclusion |
+		spec.ReorgForkChoiceNode |
+		spec.PoolClientDiversity |
+		spec.PoolLuck] struct {
 	table string
 	query string
 	data  []T
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	protectionAnomaliesTable       = "t_slashing_protection_anomalies"
+	insertProtectionAnomaliesQuery = `
+	INSERT INTO %s (
+		f_val_idx,
+		f_public_key,
+		f_type,
+		f_slot,
+		f_source_epoch,
+		f_target_epoch)
+		VALUES`
+)
+
+func protectionAnomaliesInput(anomalies []spec.ProtectionAnomaly) proto.Input {
+	// one object per column
+	var (
+		f_val_idx      proto.ColUInt64
+		f_public_key   proto.ColStr
+		f_type         proto.ColStr
+		f_slot         proto.ColUInt64
+		f_source_epoch proto.ColUInt64
+		f_target_epoch proto.ColUInt64
+	)
+
+	for _, anomaly := range anomalies {
+		f_val_idx.Append(uint64(anomaly.ValidatorIndex))
+		f_public_key.Append(anomaly.Pubkey.String())
+		f_type.Append(string(anomaly.Kind))
+		f_slot.Append(uint64(anomaly.Slot))
+		f_source_epoch.Append(uint64(anomaly.SourceEpoch))
+		f_target_epoch.Append(uint64(anomaly.TargetEpoch))
+	}
+
+	return proto.Input{
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_public_key", Data: f_public_key},
+		{Name: "f_type", Data: f_type},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_source_epoch", Data: f_source_epoch},
+		{Name: "f_target_epoch", Data: f_target_epoch},
+	}
+}
+
+func (p *DBService) PersistProtectionAnomalies(data []spec.ProtectionAnomaly) error {
+	persistObj := PersistableObject[spec.ProtectionAnomaly]{
+		input: protectionAnomaliesInput,
+		table: protectionAnomaliesTable,
+		query: insertProtectionAnomaliesQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting slashing protection anomalies: %s", err.Error())
+	}
+	return err
+}
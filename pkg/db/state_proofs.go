@@ -0,0 +1,80 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	stateProofsTable       = "t_state_proofs"
+	insertStateProofsQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_slot,
+		f_state_root,
+		f_validators,
+		f_verified,
+		f_failed_val_idxs,
+		f_error,
+		f_timestamp)
+		VALUES`
+)
+
+func stateProofsInput(proofs []spec.StateProof) proto.Input {
+	// one object per column
+	var (
+		f_epoch           proto.ColUInt64
+		f_slot            proto.ColUInt64
+		f_state_root      proto.ColStr
+		f_validators      proto.ColUInt64
+		f_verified        proto.ColBool
+		f_failed_val_idxs = new(proto.ColUInt64).Array()
+		f_error           proto.ColStr
+		f_timestamp       proto.ColUInt64
+	)
+
+	for _, proof := range proofs {
+		failed := make([]uint64, 0, len(proof.Failed))
+		for _, valIdx := range proof.Failed {
+			failed = append(failed, uint64(valIdx))
+		}
+
+		f_epoch.Append(uint64(proof.Epoch))
+		f_slot.Append(uint64(proof.Slot))
+		f_state_root.Append(proof.StateRoot.String())
+		f_validators.Append(uint64(proof.Validators))
+		f_verified.Append(proof.Verified())
+		f_failed_val_idxs.Append(failed)
+		f_error.Append(proof.Error)
+		f_timestamp.Append(uint64(proof.Timestamp))
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_state_root", Data: f_state_root},
+		{Name: "f_validators", Data: f_validators},
+		{Name: "f_verified", Data: f_verified},
+		{Name: "f_failed_val_idxs", Data: f_failed_val_idxs},
+		{Name: "f_error", Data: f_error},
+		{Name: "f_timestamp", Data: f_timestamp},
+	}
+}
+
+func (p *DBService) PersistStateProofs(data []spec.StateProof) error {
+	persistObj := PersistableObject[spec.StateProof]{
+		input: stateProofsInput,
+		table: stateProofsTable,
+		query: insertStateProofsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting state proofs: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+type IncomeKind string
+
+// The income is accounted when it is earned: the consensus layer rewards of every epoch and the
+// execution layer income of every proposed block. Withdrawals only pay out the rewards already
+// accounted (and the principal on exit), so they are not income
+const (
+	ProposerElIncome IncomeKind = "proposer_el"
+	ClRewardsIncome  IncomeKind = "cl_rewards"
+)
+
+var (
+	// validators whose withdrawals go to the address, or deposited with it in their withdrawal credentials (0x01 or 0x02).
+	// t_validator_last_status may keep the rows of several epochs until they are merged, the last public key is taken
+	selectAddressValidatorsQuery = `
+		SELECT DISTINCT f_val_idx
+		FROM t_withdrawals
+		WHERE lower(f_address) = lower($1)
+		UNION DISTINCT
+		SELECT f_val_idx
+		FROM (
+			SELECT f_val_idx, argMax(f_public_key, f_epoch) AS f_public_key
+			FROM t_validator_last_status
+			GROUP BY f_val_idx)
+		WHERE f_public_key IN (
+			SELECT f_public_key
+			FROM t_deposits
+			WHERE length(f_withdrawal_credentials) = 66
+				AND substring(f_withdrawal_credentials, 1, 4) IN ('0x01', '0x02')
+				AND endsWith(lower(f_withdrawal_credentials), substring(lower($1), 3)))`
+
+	// with MEV-boost the proposer receives the bid, otherwise the priority fees
+	selectProposerElIncomeQuery = `
+		SELECT
+			toDate(toDateTime(%[3]d + b.f_slot * %[4]d)) as f_day,
+			b.f_proposer_index as f_val_idx,
+			toFloat64(sum(if(r.f_bid_commission > 0, r.f_bid_commission, r.f_reward_fees))) / 1e18 as f_amount_eth
+		FROM %[1]s AS b FINAL
+		INNER JOIN %[2]s AS r FINAL ON b.f_slot = r.f_slot
+		WHERE b.f_proposed = TRUE AND b.f_slot >= %[5]d AND b.f_slot <= %[6]d AND b.f_proposer_index IN (%[7]s)
+		GROUP BY f_day, f_val_idx`
+
+	selectClRewardsIncomeQuery = `
+		SELECT
+			toDate(toDateTime(%[2]d + f_epoch * %[3]d)) as f_day,
+			f_val_idx,
+			toFloat64(sum(f_reward)) / 1e9 as f_amount_eth
+		FROM %[1]s
+		WHERE f_epoch >= %[4]d AND f_epoch <= %[5]d AND f_val_idx IN (%[6]s)
+		GROUP BY f_day, f_val_idx`
+)
+
+// Income is what the validator received in a day, of the given kind
+type Income struct {
+	Day            time.Time
+	ValidatorIndex phase0.ValidatorIndex
+	Kind           IncomeKind
+	AmountEth      float64
+}
+
+type incomeRow struct {
+	F_day        time.Time `ch:"f_day"`
+	F_val_idx    uint64    `ch:"f_val_idx"`
+	F_amount_eth float64   `ch:"f_amount_eth"`
+}
+
+// RetrieveAddressValidators returns the validators tied to the given withdrawal address
+func (p *DBService) RetrieveAddressValidators(address string) ([]phase0.ValidatorIndex, error) {
+	var dest []struct {
+		F_val_idx uint64 `ch:"f_val_idx"`
+	}
+
+	startTime := time.Now()
+	p.highMu.Lock()
+	err := p.highLevelClient.Select(p.ctx, &dest, selectAddressValidatorsQuery, address)
+	p.highMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("retrieved %d validators of address %s in %f seconds", len(dest), address, time.Since(startTime).Seconds())
+
+	validators := make([]phase0.ValidatorIndex, 0, len(dest))
+	for _, row := range dest {
+		validators = append(validators, phase0.ValidatorIndex(row.F_val_idx))
+	}
+	return validators, nil
+}
+
+// RetrieveIncome returns the daily income of the validators between the given slots (included), dated with
+// the genesis time and slot duration of the chain: execution layer income of the proposed blocks and consensus layer rewards
+func (p *DBService) RetrieveIncome(validators []phase0.ValidatorIndex, genesis int64, secondsPerSlot uint64, fromSlot phase0.Slot, toSlot phase0.Slot) ([]Income, error) {
+	if len(validators) == 0 {
+		return nil, nil
+	}
+	indexes := make([]string, 0, len(validators))
+	for _, valIdx := range validators {
+		indexes = append(indexes, fmt.Sprintf("%d", valIdx))
+	}
+	valList := strings.Join(indexes, ",")
+	// rewards are dated at the start of their epoch
+	fromEpoch := (fromSlot + spec.SlotsPerEpoch - 1) / spec.SlotsPerEpoch
+	toEpoch := toSlot / spec.SlotsPerEpoch
+
+	queries := []struct {
+		kind  IncomeKind
+		query string
+	}{
+		{ProposerElIncome, fmt.Sprintf(selectProposerElIncomeQuery, blocksTable, blockRewardsTable, genesis, secondsPerSlot, fromSlot, toSlot, valList)},
+		{ClRewardsIncome, fmt.Sprintf(selectClRewardsIncomeQuery, p.valRewardsSource(), genesis, secondsPerSlot*spec.SlotsPerEpoch, fromEpoch, toEpoch, valList)},
+	}
+
+	income := make([]Income, 0)
+	for _, item := range queries {
+		var dest []incomeRow
+		if err := p.highSelect(item.query, &dest); err != nil {
+			return nil, err
+		}
+		for _, row := range dest {
+			income = append(income, Income{
+				Day:            row.F_day,
+				ValidatorIndex: phase0.ValidatorIndex(row.F_val_idx),
+				Kind:           item.kind,
+				AmountEth:      row.F_amount_eth,
+			})
+		}
+	}
+	return income, nil
+}
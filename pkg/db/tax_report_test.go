@@ -0,0 +1,70 @@
+package db_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/testutil"
+)
+
+func TestTaxReportQueries(t *testing.T) {
+	clickhouse := testutil.StartClickHouse(t)
+	dbClient, err := db.New(context.Background(), clickhouse.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbClient.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer dbClient.Finish()
+
+	address := "0x00000000000000000000000000000000000000Ab"
+	clickhouse.Exec(t, `INSERT INTO t_withdrawals (f_slot, f_index, f_val_idx, f_address, f_amount) VALUES
+		(3300, 0, 1, '0x00000000000000000000000000000000000000ab', 5000000000),
+		(3300, 1, 3, '0x00000000000000000000000000000000000000cd', 5000000000)`)
+	clickhouse.Exec(t, `INSERT INTO t_deposits (f_slot, f_public_key, f_withdrawal_credentials, f_amount, f_signature, f_index) VALUES
+		(10, '0xaa', '0x010000000000000000000000000000000000000000000000000000000000ab', 32000000000, '', 0)`)
+	// not merged yet, the same validator at two epochs
+	clickhouse.Exec(t, `INSERT INTO t_validator_last_status (f_val_idx, f_epoch, f_public_key) VALUES (2, 10, '0xaa')`)
+	clickhouse.Exec(t, `INSERT INTO t_validator_last_status (f_val_idx, f_epoch, f_public_key) VALUES (2, 11, '0xaa')`)
+
+	validators, err := dbClient.RetrieveAddressValidators(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validators) != 2 {
+		t.Fatalf("expected validators 1 and 2, got %v", validators)
+	}
+
+	clickhouse.Exec(t, `INSERT INTO t_validator_rewards_summary (f_val_idx, f_epoch, f_reward) VALUES
+		(1, 100, 1000000), (1, 101, 2000000), (2, 100, -500000), (3, 100, 1000000)`)
+	clickhouse.Exec(t, `INSERT INTO t_block_metrics (f_slot, f_proposer_index, f_proposed) VALUES (3205, 1, true), (3206, 2, false)`)
+	clickhouse.Exec(t, `INSERT INTO t_block_rewards (f_slot, f_reward_fees, f_bid_commission) VALUES (3205, 10000000000000000, 0), (3206, 20000000000000000, 0)`)
+
+	genesis := int64(1606824023)
+	income, err := dbClient.RetrieveIncome([]phase0.ValidatorIndex{1, 2}, genesis, 12, 3200, 3300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC)
+	amounts := make(map[string]float64)
+	for _, item := range income {
+		if !item.Day.Equal(day) {
+			t.Errorf("income dated %s, expected %s", item.Day, day)
+		}
+		amounts[fmt.Sprintf("%s/%d", item.Kind, item.ValidatorIndex)] += item.AmountEth
+	}
+	expected := map[string]float64{"cl_rewards/1": 0.003, "cl_rewards/2": -0.0005, "proposer_el/1": 0.01}
+	if len(amounts) != len(expected) {
+		t.Fatalf("expected income %v, got %v", expected, amounts)
+	}
+	for key, amount := range expected {
+		if diff := amounts[key] - amount; diff > 1e-12 || diff < -1e-12 {
+			t.Errorf("expected %s income of %f, got %f", key, amount, amounts[key])
+		}
+	}
+}
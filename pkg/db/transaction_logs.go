@@ -0,0 +1,84 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	transactionLogsTable       = "t_transaction_logs"
+	insertTransactionLogsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_tx_hash,
+		f_log_index,
+		f_address,
+		f_topic0,
+		f_topic1,
+		f_topic2,
+		f_topic3,
+		f_data)
+		VALUES`
+
+	deleteTransactionLogsQuery = `
+		DELETE FROM %s
+		WHERE f_slot = $1;
+`
+)
+
+func transactionLogsInput(logs []spec.TransactionLog) proto.Input {
+	// one object per column
+	var (
+		f_slot      proto.ColUInt64
+		f_tx_hash   proto.ColStr
+		f_log_index proto.ColUInt64
+		f_address   proto.ColStr
+		f_topic0    proto.ColStr
+		f_topic1    proto.ColStr
+		f_topic2    proto.ColStr
+		f_topic3    proto.ColStr
+		f_data      proto.ColStr
+	)
+
+	for _, logEntry := range logs {
+		f_slot.Append(uint64(logEntry.Slot))
+		f_tx_hash.Append(logEntry.TxHash.String())
+		f_log_index.Append(logEntry.LogIndex)
+		f_address.Append(logEntry.Address.String())
+		f_topic0.Append(logEntry.Topic(0))
+		f_topic1.Append(logEntry.Topic(1))
+		f_topic2.Append(logEntry.Topic(2))
+		f_topic3.Append(logEntry.Topic(3))
+		f_data.Append(logEntry.Data)
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_tx_hash", Data: f_tx_hash},
+		{Name: "f_log_index", Data: f_log_index},
+		{Name: "f_address", Data: f_address},
+		{Name: "f_topic0", Data: f_topic0},
+		{Name: "f_topic1", Data: f_topic1},
+		{Name: "f_topic2", Data: f_topic2},
+		{Name: "f_topic3", Data: f_topic3},
+		{Name: "f_data", Data: f_data},
+	}
+}
+
+func (p *DBService) PersistTransactionLogs(data []spec.TransactionLog) error {
+	persistObj := PersistableObject[spec.TransactionLog]{
+		input: transactionLogsInput,
+		table: transactionLogsTable,
+		query: insertTransactionLogsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting transaction logs: %s", err.Error())
+	}
+	return err
+}
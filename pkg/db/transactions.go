@@ -28,7 +28,9 @@ var (
 			f_blob_gas_used,
 			f_blob_gas_price,
 			f_blob_gas_limit,
-			f_blob_gas_fee_cap)
+			f_blob_gas_fee_cap,
+			f_tx_category,
+			f_header_only)
 		VALUES`
 
 	deleteTransactionsQuery = `
@@ -61,6 +63,8 @@ func transactionsInput(transactions []spec.AgnosticTransaction) proto.Input {
 		f_blob_gas_price   proto.ColUInt64
 		f_blob_gas_limit   proto.ColUInt64
 		f_blob_gas_fee_cap proto.ColUInt64
+		f_tx_category      proto.ColStr
+		f_header_only      proto.ColBool
 	)
 
 	for _, transaction := range transactions {
@@ -92,6 +96,8 @@ func transactionsInput(transactions []spec.AgnosticTransaction) proto.Input {
 		f_blob_gas_price.Append(transaction.BlobGasPrice)
 		f_blob_gas_limit.Append(transaction.BlobGasLimit)
 		f_blob_gas_fee_cap.Append(transaction.BlobGasFeeCap)
+		f_tx_category.Append(string(transaction.Category))
+		f_header_only.Append(transaction.HeaderOnly)
 	}
 
 	return proto.Input{
@@ -117,6 +123,8 @@ func transactionsInput(transactions []spec.AgnosticTransaction) proto.Input {
 		{Name: "f_blob_gas_price", Data: f_blob_gas_price},
 		{Name: "f_blob_gas_limit", Data: f_blob_gas_limit},
 		{Name: "f_blob_gas_fee_cap", Data: f_blob_gas_fee_cap},
+		{Name: "f_tx_category", Data: f_tx_category},
+		{Name: "f_header_only", Data: f_header_only},
 	}
 }
 
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	balanceSamplesTable       = "t_validator_balance_samples"
+	insertBalanceSamplesQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_val_idx,
+		f_balance)
+		VALUES`
+)
+
+func balanceSamplesInput(samples []spec.ValidatorBalanceSample) proto.Input {
+	// one object per column
+	var (
+		f_slot    proto.ColUInt64
+		f_val_idx proto.ColUInt64
+		f_balance proto.ColUInt64
+	)
+
+	for _, sample := range samples {
+		f_slot.Append(uint64(sample.Slot))
+		f_val_idx.Append(uint64(sample.ValidatorIndex))
+		f_balance.Append(uint64(sample.Balance))
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_balance", Data: f_balance},
+	}
+}
+
+func (p *DBService) PersistValidatorBalanceSamples(data []spec.ValidatorBalanceSample) error {
+	persistObj := PersistableObject[spec.ValidatorBalanceSample]{
+		input: balanceSamplesInput,
+		table: balanceSamplesTable,
+		query: insertBalanceSamplesQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting validator balance samples: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	poolPubkeysTable = "t_eth2_pubkeys"
+
+	selectValidatorSnapshotsQuery = `
+		SELECT f_val_idx, f_balance_eth, f_status
+		FROM %s
+		WHERE f_epoch = %d`
+
+	selectValidatorPoolsQuery = `
+		SELECT f_val_idx, f_pool_name
+		FROM %s FINAL
+		WHERE f_pool_name != ''`
+)
+
+// RetrieveValidatorSnapshots returns the balance and status of the validators at the end of the epoch,
+// as stored with their rewards. Balances are stored in ETH as float32, so they are precise to a few thousand gwei
+func (p *DBService) RetrieveValidatorSnapshots(epoch phase0.Epoch) ([]spec.ValidatorSnapshot, error) {
+	var dest []struct {
+		F_val_idx     uint64  `ch:"f_val_idx"`
+		F_balance_eth float32 `ch:"f_balance_eth"`
+		F_status      uint8   `ch:"f_status"`
+	}
+	if err := p.highSelect(fmt.Sprintf(selectValidatorSnapshotsQuery, p.valRewardsSource(), epoch), &dest); err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]spec.ValidatorSnapshot, 0, len(dest))
+	for _, row := range dest {
+		snapshots = append(snapshots, spec.ValidatorSnapshot{
+			Index:   phase0.ValidatorIndex(row.F_val_idx),
+			Balance: phase0.Gwei(float64(row.F_balance_eth) * spec.EffectiveBalanceInc),
+			Status:  spec.ValidatorStatus(row.F_status),
+		})
+	}
+	return snapshots, nil
+}
+
+// RetrieveValidatorPools returns the pool of the validators tied to one
+func (p *DBService) RetrieveValidatorPools() (map[phase0.ValidatorIndex]string, error) {
+	var dest []struct {
+		F_val_idx   uint64 `ch:"f_val_idx"`
+		F_pool_name string `ch:"f_pool_name"`
+	}
+	if err := p.highSelect(fmt.Sprintf(selectValidatorPoolsQuery, poolPubkeysTable), &dest); err != nil {
+		return nil, err
+	}
+
+	pools := make(map[phase0.ValidatorIndex]string, len(dest))
+	for _, row := range dest {
+		pools[phase0.ValidatorIndex(row.F_val_idx)] = row.F_pool_name
+	}
+	return pools, nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	validatorKeysTable       = "t_validator_keys"
+	insertValidatorKeysQuery = `
+	INSERT INTO %s (
+		f_val_idx,
+		f_public_key,
+		f_activation_epoch,
+		f_withdrawal_credentials,
+		f_epoch)
+		VALUES`
+)
+
+func validatorKeysInput(keys []spec.ValidatorKey) proto.Input {
+	// one object per column
+	var (
+		f_val_idx                proto.ColUInt64
+		f_public_key             proto.ColStr
+		f_activation_epoch       proto.ColUInt64
+		f_withdrawal_credentials proto.ColStr
+		f_epoch                  proto.ColUInt64
+	)
+
+	for _, key := range keys {
+		f_val_idx.Append(uint64(key.ValIdx))
+		f_public_key.Append(key.PublicKey.String())
+		f_activation_epoch.Append(uint64(key.ActivationEpoch))
+		f_withdrawal_credentials.Append(fmt.Sprintf("%#x", key.WithdrawalCredentials))
+		f_epoch.Append(uint64(key.Epoch))
+	}
+
+	return proto.Input{
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_public_key", Data: f_public_key},
+		{Name: "f_activation_epoch", Data: f_activation_epoch},
+		{Name: "f_withdrawal_credentials", Data: f_withdrawal_credentials},
+		{Name: "f_epoch", Data: f_epoch},
+	}
+}
+
+func (p *DBService) PersistValidatorKeys(data []spec.ValidatorKey) error {
+	persistObj := PersistableObject[spec.ValidatorKey]{
+		input: validatorKeysInput,
+		table: validatorKeysTable,
+		query: insertValidatorKeysQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting validator keys: %s", err.Error())
+	}
+	return err
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"fmt"
+
 	"github.com/ClickHouse/ch-go/proto"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/migalabs/goteth/pkg/spec"
@@ -18,12 +20,18 @@ var (
 		f_activation_epoch,
 		f_withdrawal_epoch,
 		f_exit_epoch,
-		f_public_key)
+		f_public_key,
+		f_detailed_status)
 	VALUES`
 
 	deleteValidatorStatus = `
 		DELETE FROM %s
 		WHERE f_epoch < $1`
+
+	selectExitQueueRegistryQuery = `
+		SELECT f_val_idx, f_epoch, f_balance_eth, f_activation_epoch, f_exit_epoch
+		FROM %[1]s
+		WHERE f_epoch = (SELECT max(f_epoch) FROM %[1]s)`
 )
 
 func valStatusInput(validatorStatuses []spec.ValidatorLastStatus) proto.Input {
@@ -38,6 +46,7 @@ func valStatusInput(validatorStatuses []spec.ValidatorLastStatus) proto.Input {
 		f_withdrawal_epoch proto.ColUInt64
 		f_exit_epoch       proto.ColUInt64
 		f_public_key       proto.ColStr
+		f_detailed_status  proto.ColUInt8
 	)
 
 	for _, status := range validatorStatuses {
@@ -51,6 +60,7 @@ func valStatusInput(validatorStatuses []spec.ValidatorLastStatus) proto.Input {
 		f_withdrawal_epoch.Append(uint64(status.WithdrawalEpoch))
 		f_exit_epoch.Append(uint64(status.ExitEpoch))
 		f_public_key.Append(status.PublicKey.String())
+		f_detailed_status.Append(uint8(status.DetailedStatus))
 	}
 
 	return proto.Input{
@@ -64,6 +74,7 @@ func valStatusInput(validatorStatuses []spec.ValidatorLastStatus) proto.Input {
 		{Name: "f_withdrawal_epoch", Data: f_withdrawal_epoch},
 		{Name: "f_exit_epoch", Data: f_exit_epoch},
 		{Name: "f_public_key", Data: f_public_key},
+		{Name: "f_detailed_status", Data: f_detailed_status},
 	}
 }
 
@@ -100,3 +111,36 @@ func (p *DBService) DeleteValLastStatus(epoch phase0.Epoch) error {
 
 	return err
 }
+
+// RetrieveExitQueueRegistry returns the validators of the last epoch analyzed, with the epoch, to estimate the exit queue.
+// The effective balance is approximated from the balance, rounded down to the increment and capped at 32 ETH,
+// or at 2048 ETH if it is above 32 ETH (compounding credentials)
+func (p *DBService) RetrieveExitQueueRegistry() (phase0.Epoch, []spec.ExitQueueValidator, error) {
+	var dest []struct {
+		F_val_idx          uint64  `ch:"f_val_idx"`
+		F_epoch            uint64  `ch:"f_epoch"`
+		F_balance_eth      float32 `ch:"f_balance_eth"`
+		F_activation_epoch uint64  `ch:"f_activation_epoch"`
+		F_exit_epoch       uint64  `ch:"f_exit_epoch"`
+	}
+	if err := p.highSelect(fmt.Sprintf(selectExitQueueRegistryQuery, valLastStatusTable), &dest); err != nil {
+		return 0, nil, err
+	}
+
+	epoch := phase0.Epoch(0)
+	registry := make([]spec.ExitQueueValidator, 0, len(dest))
+	for _, row := range dest {
+		epoch = phase0.Epoch(row.F_epoch)
+		effectiveBalance := uint64(row.F_balance_eth)
+		if effectiveBalance > spec.MaxEffectiveInc {
+			effectiveBalance = min(effectiveBalance, spec.MaxEffectiveIncElectra)
+		}
+		registry = append(registry, spec.ExitQueueValidator{
+			Index:            phase0.ValidatorIndex(row.F_val_idx),
+			EffectiveBalance: phase0.Gwei(effectiveBalance * spec.EffectiveBalanceInc),
+			ActivationEpoch:  phase0.Epoch(row.F_activation_epoch),
+			ExitEpoch:        phase0.Epoch(row.F_exit_epoch),
+		})
+	}
+	return epoch, registry, nil
+}
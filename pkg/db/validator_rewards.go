@@ -1,6 +1,9 @@
 package db
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/ClickHouse/ch-go/proto"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 
@@ -28,7 +31,10 @@ var (
 		f_status,
 		f_block_api_reward,
 		f_block_experimental_reward,
-		f_inclusion_delay) VALUES`
+		f_inclusion_delay,
+		f_reward_percentile,
+		f_planned_downtime,
+		f_detailed_status) VALUES`
 
 	deleteValidatorRewardsInEpochQuery = `
 		DELETE FROM %s
@@ -39,6 +45,12 @@ var (
 		DELETE FROM %s
 		WHERE f_epoch <= $1;
 	`
+
+	selectValidatorBalancesQuery = `
+		SELECT f_val_idx, f_balance_eth
+		FROM %s
+		WHERE f_epoch = %d AND f_val_idx IN (%s)
+		LIMIT 1 BY f_val_idx`
 )
 
 func rewardsInput(vals []spec.ValidatorRewards) proto.Input {
@@ -62,6 +74,9 @@ func rewardsInput(vals []spec.ValidatorRewards) proto.Input {
 		f_block_api_reward          proto.ColUInt64
 		f_block_experimental_reward proto.ColUInt64
 		f_inclusion_delay           proto.ColUInt8
+		f_reward_percentile         proto.ColFloat32
+		f_planned_downtime          proto.ColBool
+		f_detailed_status           proto.ColUInt8
 	)
 
 	for _, val := range vals {
@@ -83,6 +98,9 @@ func rewardsInput(vals []spec.ValidatorRewards) proto.Input {
 		f_block_api_reward.Append(uint64(val.ProposerApiReward))
 		f_block_experimental_reward.Append(uint64(val.ProposerManualReward))
 		f_inclusion_delay.Append(uint8(val.InclusionDelay))
+		f_reward_percentile.Append(val.RewardPercentile)
+		f_planned_downtime.Append(val.PlannedDowntime)
+		f_detailed_status.Append(uint8(val.DetailedStatus))
 	}
 
 	return proto.Input{
@@ -104,10 +122,16 @@ func rewardsInput(vals []spec.ValidatorRewards) proto.Input {
 		{Name: "f_block_api_reward", Data: f_block_api_reward},
 		{Name: "f_block_experimental_reward", Data: f_block_experimental_reward},
 		{Name: "f_inclusion_delay", Data: f_inclusion_delay},
+		{Name: "f_reward_percentile", Data: f_reward_percentile},
+		{Name: "f_planned_downtime", Data: f_planned_downtime},
+		{Name: "f_detailed_status", Data: f_detailed_status},
 	}
 }
 
 func (p *DBService) PersistValidatorRewards(data []spec.ValidatorRewards) error {
+	if len(p.rewardsShards) > 0 {
+		return p.persistShardedValidatorRewards(data)
+	}
 	persistObj := PersistableObject[spec.ValidatorRewards]{
 		input: rewardsInput,
 		table: valRewardsTable,
@@ -137,6 +161,40 @@ func (p *DBService) DeleteValidatorRewardsUntil(epoch phase0.Epoch) error {
 	if err != nil {
 		log.Errorf("error deleting validator rewards: %s", err.Error())
 	}
+	if p.rewardsDelta {
+		err = p.deleteValidatorRewardsDeltaUntil(epoch)
+		if err != nil {
+			log.Errorf("error deleting validator rewards deltas: %s", err.Error())
+		}
+	}
 
 	return err
 }
+
+// RetrieveValidatorBalances returns the balances (in ETH) stored with the rewards of the epoch for the given validators,
+// from every shard. Validators without rewards stored at the epoch are left out
+func (p *DBService) RetrieveValidatorBalances(epoch phase0.Epoch, valIdxs []phase0.ValidatorIndex) (map[phase0.ValidatorIndex]float32, error) {
+	balances := make(map[phase0.ValidatorIndex]float32, len(valIdxs))
+	if len(valIdxs) == 0 {
+		return balances, nil
+	}
+	items := make([]string, 0, len(valIdxs))
+	for _, valIdx := range valIdxs {
+		items = append(items, fmt.Sprintf("%d", valIdx))
+	}
+
+	var dest []struct {
+		F_val_idx     uint64  `ch:"f_val_idx"`
+		F_balance_eth float32 `ch:"f_balance_eth"`
+	}
+	err := p.highSelect(
+		fmt.Sprintf(selectValidatorBalancesQuery, p.valRewardsSource(), epoch, strings.Join(items, ",")),
+		&dest)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range dest {
+		balances[phase0.ValidatorIndex(row.F_val_idx)] = row.F_balance_eth
+	}
+	return balances, nil
+}
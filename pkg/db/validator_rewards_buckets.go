@@ -0,0 +1,88 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	valRewardsBucketsTable = "t_validator_rewards_buckets"
+
+	// RewardsBucketEpochs is the number of epochs each rollup bucket covers,
+	// the 1d/7d/30d windows are made of 9/63/270 buckets
+	RewardsBucketEpochs = phase0.Epoch(25)
+	// RewardsWindowBuckets is the number of buckets of the widest window (30d), older ones are pruned
+	RewardsWindowBuckets = phase0.Epoch(270)
+
+	// re-aggregates the given bucket from the rewards persisted so far,
+	// the row with the highest f_end_epoch replaces the previous ones
+	insertValidatorRewardsBucketQuery = `
+		INSERT INTO %s
+			SELECT
+				f_val_idx,
+				toUInt64($1) as f_bucket,
+				min(f_epoch) as f_start_epoch,
+				max(f_epoch) as f_end_epoch,
+				count() as f_epochs,
+				sum(f_reward) as f_reward,
+				sum(f_max_reward) as f_max_reward,
+				countIf(f_attestation_included = TRUE) as f_attestations_included,
+				countIf(f_missing_source = TRUE) as f_missing_source_count,
+				countIf(f_missing_target = TRUE) as f_missing_target_count,
+				countIf(f_missing_head = TRUE) as f_missing_head_count,
+				countIf(f_in_sync_committee = TRUE) as f_in_sync_committee_count,
+				sum(f_block_api_reward) as f_block_api_reward,
+				sum(f_inclusion_delay) as f_inclusion_delay_sum
+			FROM %s
+			WHERE f_epoch >= $2 AND f_epoch <= $3
+			GROUP BY f_val_idx`
+
+	deleteValidatorRewardsBucketsUntilQuery = `
+		DELETE FROM %s
+		WHERE f_bucket <= $1;
+	`
+)
+
+// RewardsBucket returns the rollup bucket the epoch belongs to
+func RewardsBucket(epoch phase0.Epoch) phase0.Epoch {
+	return epoch / RewardsBucketEpochs
+}
+
+// RefreshValidatorRewardsBucket aggregates again the bucket of the given epoch,
+// so that it includes the validator rewards persisted until now
+func (p *DBService) RefreshValidatorRewardsBucket(epoch phase0.Epoch) error {
+	bucket := RewardsBucket(epoch)
+	startEpoch := bucket * RewardsBucketEpochs
+	endEpoch := startEpoch + RewardsBucketEpochs - 1
+
+	query := fmt.Sprintf(insertValidatorRewardsBucketQuery, valRewardsBucketsTable, p.valRewardsSource())
+	startTime := time.Now()
+
+	p.highMu.Lock()
+	err := p.highLevelClient.Exec(p.ctx, query, bucket, startEpoch, endEpoch)
+	p.highMu.Unlock()
+
+	if err == nil {
+		log.Debugf("validator rewards bucket %d refreshed at epoch %d, %f seconds", bucket, epoch, time.Since(startTime).Seconds())
+	}
+	return err
+}
+
+// DeleteValidatorRewardsBucketsUntil removes the buckets that no window covers anymore
+func (p *DBService) DeleteValidatorRewardsBucketsUntil(bucket phase0.Epoch) error {
+
+	deleteObj := DeletableObject{
+		query: deleteValidatorRewardsBucketsUntilQuery,
+		table: valRewardsBucketsTable,
+		args:  []any{bucket},
+	}
+
+	err := p.Delete(deleteObj)
+	if err != nil {
+		log.Errorf("error deleting validator rewards buckets: %s", err.Error())
+	}
+
+	return err
+}
@@ -0,0 +1,303 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	valRewardsDeltaTable             = "t_validator_rewards_delta"
+	valRewardsDeltaView              = "v_validator_rewards_delta" // same columns as t_validator_rewards_summary
+	valRewardsKeyframesTable         = "t_validator_rewards_keyframes"
+	insertValidatorRewardsDeltaQuery = `
+	INSERT INTO %s (
+		f_val_idx,
+		f_epoch,
+		f_keyframe_epoch,
+		f_keyframe,
+		f_balance_delta,
+		f_reward,
+		f_max_reward_delta,
+		f_max_att_reward_delta,
+		f_max_sync_reward_delta,
+		f_base_reward_delta,
+		f_att_slot,
+		f_attestation_included,
+		f_in_sync_committee,
+		f_missing_source,
+		f_missing_target,
+		f_missing_head,
+		f_status,
+		f_block_api_reward,
+		f_block_experimental_reward,
+		f_inclusion_delay,
+		f_reward_percentile,
+		f_planned_downtime,
+		f_detailed_status) VALUES`
+
+	insertValidatorRewardsKeyframeQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_val_idx,
+		f_balance,
+		f_max_reward,
+		f_max_att_reward,
+		f_max_sync_reward,
+		f_base_reward) VALUES`
+
+	// the keyframe of the latest epoch in the range
+	selectValidatorRewardsKeyframeQuery = `
+		SELECT
+			f_epoch,
+			f_val_idx,
+			f_balance,
+			f_max_reward,
+			f_max_att_reward,
+			f_max_sync_reward,
+			f_base_reward
+		FROM %[1]s FINAL
+		WHERE f_epoch >= %[2]d AND f_epoch <= %[3]d
+			AND f_epoch = (SELECT max(f_epoch) FROM %[1]s WHERE f_epoch >= %[2]d AND f_epoch <= %[3]d)`
+
+	deleteValidatorRewardsDeltaUntilEpochQuery = `
+		DELETE FROM %s
+		WHERE f_epoch <= $1;
+	`
+
+	// keyframes are deleted only if no newer row refers to them
+	deleteValidatorRewardsKeyframesUntilEpochQuery = `
+		DELETE FROM %s
+		WHERE f_epoch <= $1
+			AND f_epoch < (SELECT min(f_keyframe_epoch) FROM t_validator_rewards_delta WHERE f_epoch > $1);
+	`
+)
+
+func rewardsDeltaInput(vals []spec.ValidatorRewardsDelta) proto.Input {
+	// one object per column
+	var (
+		f_val_idx                   proto.ColUInt64
+		f_epoch                     proto.ColUInt64
+		f_keyframe_epoch            proto.ColUInt64
+		f_keyframe                  proto.ColBool
+		f_balance_delta             proto.ColInt64
+		f_reward                    proto.ColInt64
+		f_max_reward_delta          proto.ColInt64
+		f_max_att_reward_delta      proto.ColInt64
+		f_max_sync_reward_delta     proto.ColInt64
+		f_base_reward_delta         proto.ColInt64
+		f_att_slot                  proto.ColUInt64
+		f_attestation_included      proto.ColBool
+		f_in_sync_committee         proto.ColBool
+		f_missing_source            proto.ColBool
+		f_missing_target            proto.ColBool
+		f_missing_head              proto.ColBool
+		f_status                    proto.ColUInt8
+		f_block_api_reward          proto.ColUInt64
+		f_block_experimental_reward proto.ColUInt64
+		f_inclusion_delay           proto.ColUInt8
+		f_reward_percentile         proto.ColFloat32
+		f_planned_downtime          proto.ColBool
+		f_detailed_status           proto.ColUInt8
+	)
+
+	for _, val := range vals {
+		f_val_idx.Append(uint64(val.Rewards.ValidatorIndex))
+		f_epoch.Append(uint64(val.Rewards.Epoch))
+		f_keyframe_epoch.Append(uint64(val.KeyframeEpoch))
+		f_keyframe.Append(val.Keyframe())
+		f_balance_delta.Append(val.BalanceDelta)
+		f_reward.Append(val.Rewards.Reward)
+		f_max_reward_delta.Append(val.MaxRewardDelta)
+		f_max_att_reward_delta.Append(val.AttestationRewardDelta)
+		f_max_sync_reward_delta.Append(val.SyncCommitteeRewardDelta)
+		f_base_reward_delta.Append(val.BaseRewardDelta)
+		f_att_slot.Append(uint64(val.Rewards.AttSlot))
+		f_attestation_included.Append(val.Rewards.AttestationIncluded)
+		f_in_sync_committee.Append(val.Rewards.InSyncCommittee)
+		f_missing_source.Append(val.Rewards.MissingSource)
+		f_missing_target.Append(val.Rewards.MissingTarget)
+		f_missing_head.Append(val.Rewards.MissingHead)
+		f_status.Append(uint8(val.Rewards.Status))
+		f_block_api_reward.Append(uint64(val.Rewards.ProposerApiReward))
+		f_block_experimental_reward.Append(uint64(val.Rewards.ProposerManualReward))
+		f_inclusion_delay.Append(uint8(val.Rewards.InclusionDelay))
+		f_reward_percentile.Append(val.Rewards.RewardPercentile)
+		f_planned_downtime.Append(val.Rewards.PlannedDowntime)
+		f_detailed_status.Append(uint8(val.Rewards.DetailedStatus))
+	}
+
+	return proto.Input{
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_keyframe_epoch", Data: f_keyframe_epoch},
+		{Name: "f_keyframe", Data: f_keyframe},
+		{Name: "f_balance_delta", Data: f_balance_delta},
+		{Name: "f_reward", Data: f_reward},
+		{Name: "f_max_reward_delta", Data: f_max_reward_delta},
+		{Name: "f_max_att_reward_delta", Data: f_max_att_reward_delta},
+		{Name: "f_max_sync_reward_delta", Data: f_max_sync_reward_delta},
+		{Name: "f_base_reward_delta", Data: f_base_reward_delta},
+		{Name: "f_att_slot", Data: f_att_slot},
+		{Name: "f_attestation_included", Data: f_attestation_included},
+		{Name: "f_in_sync_committee", Data: f_in_sync_committee},
+		{Name: "f_missing_source", Data: f_missing_source},
+		{Name: "f_missing_target", Data: f_missing_target},
+		{Name: "f_missing_head", Data: f_missing_head},
+		{Name: "f_status", Data: f_status},
+		{Name: "f_block_api_reward", Data: f_block_api_reward},
+		{Name: "f_block_experimental_reward", Data: f_block_experimental_reward},
+		{Name: "f_inclusion_delay", Data: f_inclusion_delay},
+		{Name: "f_reward_percentile", Data: f_reward_percentile},
+		{Name: "f_planned_downtime", Data: f_planned_downtime},
+		{Name: "f_detailed_status", Data: f_detailed_status},
+	}
+}
+
+func (p *DBService) PersistValidatorRewardsDelta(data []spec.ValidatorRewardsDelta) error {
+	persistObj := PersistableObject[spec.ValidatorRewardsDelta]{
+		input: rewardsDeltaInput,
+		table: valRewardsDeltaTable,
+		query: insertValidatorRewardsDeltaQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting validator rewards deltas: %s", err.Error())
+	}
+	return err
+}
+
+func rewardsKeyframeInput(vals []spec.ValidatorRewardsKeyframe) proto.Input {
+	// one object per column
+	var (
+		f_epoch           proto.ColUInt64
+		f_val_idx         proto.ColUInt64
+		f_balance         proto.ColUInt64
+		f_max_reward      proto.ColUInt64
+		f_max_att_reward  proto.ColUInt64
+		f_max_sync_reward proto.ColUInt64
+		f_base_reward     proto.ColUInt64
+	)
+
+	for _, val := range vals {
+		f_epoch.Append(uint64(val.Epoch))
+		f_val_idx.Append(uint64(val.ValidatorIndex))
+		f_balance.Append(uint64(val.Balance))
+		f_max_reward.Append(uint64(val.MaxReward))
+		f_max_att_reward.Append(uint64(val.AttestationReward))
+		f_max_sync_reward.Append(uint64(val.SyncCommitteeReward))
+		f_base_reward.Append(uint64(val.BaseReward))
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_balance", Data: f_balance},
+		{Name: "f_max_reward", Data: f_max_reward},
+		{Name: "f_max_att_reward", Data: f_max_att_reward},
+		{Name: "f_max_sync_reward", Data: f_max_sync_reward},
+		{Name: "f_base_reward", Data: f_base_reward},
+	}
+}
+
+func (p *DBService) PersistValidatorRewardsKeyframe(data []spec.ValidatorRewardsKeyframe) error {
+	persistObj := PersistableObject[spec.ValidatorRewardsKeyframe]{
+		input: rewardsKeyframeInput,
+		table: valRewardsKeyframesTable,
+		query: insertValidatorRewardsKeyframeQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting validator rewards keyframe: %s", err.Error())
+	}
+	return err
+}
+
+// RetrieveValidatorRewardsKeyframe returns the keyframe of the latest epoch between from and to, empty if there is none
+func (p *DBService) RetrieveValidatorRewardsKeyframe(from phase0.Epoch, to phase0.Epoch) ([]spec.ValidatorRewardsKeyframe, error) {
+	var dest []struct {
+		F_epoch           uint64 `ch:"f_epoch"`
+		F_val_idx         uint64 `ch:"f_val_idx"`
+		F_balance         uint64 `ch:"f_balance"`
+		F_max_reward      uint64 `ch:"f_max_reward"`
+		F_max_att_reward  uint64 `ch:"f_max_att_reward"`
+		F_max_sync_reward uint64 `ch:"f_max_sync_reward"`
+		F_base_reward     uint64 `ch:"f_base_reward"`
+	}
+
+	err := p.highSelect(
+		fmt.Sprintf(selectValidatorRewardsKeyframeQuery, valRewardsKeyframesTable, from, to),
+		&dest)
+	if err != nil {
+		return nil, err
+	}
+
+	keyframe := make([]spec.ValidatorRewardsKeyframe, 0, len(dest))
+	for _, row := range dest {
+		keyframe = append(keyframe, spec.ValidatorRewardsKeyframe{
+			ValidatorIndex:      phase0.ValidatorIndex(row.F_val_idx),
+			Epoch:               phase0.Epoch(row.F_epoch),
+			Balance:             phase0.Gwei(row.F_balance),
+			MaxReward:           phase0.Gwei(row.F_max_reward),
+			AttestationReward:   phase0.Gwei(row.F_max_att_reward),
+			SyncCommitteeReward: phase0.Gwei(row.F_max_sync_reward),
+			BaseReward:          phase0.Gwei(row.F_base_reward),
+		})
+	}
+	return keyframe, nil
+}
+
+// WithRewardsDeltaEncoding makes the queries over the validator rewards read the delta-encoded ones
+func WithRewardsDeltaEncoding() DBServiceOption {
+	return func(s *DBService) error {
+		s.rewardsDelta = true
+		return nil
+	}
+}
+
+// valRewardsSource returns the table or view the validator rewards are read from, with the rewards
+// of every shard when the rewards are sharded
+func (p *DBService) valRewardsSource() string {
+	if len(p.rewardsShards) > 0 {
+		return p.shardedRewardsSource()
+	}
+	return p.RewardsSource()
+}
+
+// RewardsSource returns the table or view to read the validator rewards of a single database from,
+// see QueryRewardsRows to read them from every shard
+func (p *DBService) RewardsSource() string {
+	if p.rewardsDelta {
+		return valRewardsDeltaView
+	}
+	return valRewardsTable
+}
+
+func (p *DBService) deleteValidatorRewardsDeltaUntil(epoch phase0.Epoch) error {
+	// the keyframes go first, the rows after the epoch tell which ones are still referred to
+	err := p.Delete(DeletableObject{
+		query: deleteValidatorRewardsKeyframesUntilEpochQuery,
+		table: valRewardsKeyframesTable,
+		args:  []any{epoch},
+	})
+	if err != nil {
+		return err
+	}
+	return p.Delete(DeletableObject{
+		query: deleteValidatorRewardsDeltaUntilEpochQuery,
+		table: valRewardsDeltaTable,
+		args:  []any{epoch},
+	})
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	// rows written twice (i.e. reprocessed epochs) are counted once
+	selectValidatorRewardsRangeQuery = `
+		SELECT
+			count() AS f_epochs,
+			sum(f_reward) AS f_reward,
+			sum(f_max_reward) AS f_max_reward,
+			sum(f_max_att_reward) AS f_max_att_reward,
+			sum(f_max_sync_reward) AS f_max_sync_reward,
+			sum(f_block_api_reward) AS f_block_api_reward,
+			countIf(f_attestation_included) AS f_attestations_included,
+			countIf(f_missing_source) AS f_missing_source,
+			countIf(f_missing_target) AS f_missing_target,
+			countIf(f_missing_head) AS f_missing_head,
+			countIf(f_in_sync_committee) AS f_sync_committee_epochs
+		FROM (
+			SELECT *
+			FROM %s
+			WHERE f_val_idx = %d AND f_epoch >= %d AND f_epoch <= %d
+			LIMIT 1 BY f_val_idx, f_epoch
+		)`
+)
+
+// ValidatorRewardsRange is the aggregation of the rewards of a validator over a range of epochs
+type ValidatorRewardsRange struct {
+	Epochs               uint64 `ch:"f_epochs"` // epochs of the range with rewards
+	Reward               int64  `ch:"f_reward"`
+	MaxReward            uint64 `ch:"f_max_reward"`
+	MaxAttReward         uint64 `ch:"f_max_att_reward"`
+	MaxSyncReward        uint64 `ch:"f_max_sync_reward"`
+	BlockApiReward       uint64 `ch:"f_block_api_reward"`
+	AttestationsIncluded uint64 `ch:"f_attestations_included"`
+	MissingSource        uint64 `ch:"f_missing_source"`
+	MissingTarget        uint64 `ch:"f_missing_target"`
+	MissingHead          uint64 `ch:"f_missing_head"`
+	SyncCommitteeEpochs  uint64 `ch:"f_sync_committee_epochs"`
+}
+
+// Efficiency is the share of the maximum reward the validator got
+func (r ValidatorRewardsRange) Efficiency() float64 {
+	if r.MaxReward == 0 {
+		return 0
+	}
+	return float64(r.Reward) / float64(r.MaxReward)
+}
+
+// RetrieveValidatorRewardsRange aggregates in the database the rewards of the validator between the given epochs, both included
+func (p *DBService) RetrieveValidatorRewardsRange(valIdx phase0.ValidatorIndex, from phase0.Epoch, to phase0.Epoch) (ValidatorRewardsRange, error) {
+	db := p.rewardsDB(valIdx)
+	var dest []ValidatorRewardsRange
+	err := db.highSelect(fmt.Sprintf(selectValidatorRewardsRangeQuery, db.valRewardsSource(), valIdx, from, to), &dest)
+	if err != nil || len(dest) == 0 {
+		return ValidatorRewardsRange{}, err
+	}
+	return dest[0], nil
+}
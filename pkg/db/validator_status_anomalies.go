@@ -0,0 +1,63 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	valStatusAnomaliesTable       = "t_validator_status_anomalies"
+	insertValStatusAnomaliesQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_slot,
+		f_status,
+		f_computed_count,
+		f_node_count)
+		VALUES`
+)
+
+func valStatusAnomaliesInput(anomalies []spec.ValidatorStatusAnomaly) proto.Input {
+	// one object per column
+	var (
+		f_epoch          proto.ColUInt64
+		f_slot           proto.ColUInt64
+		f_status         proto.ColUInt8
+		f_computed_count proto.ColUInt64
+		f_node_count     proto.ColUInt64
+	)
+
+	for _, anomaly := range anomalies {
+		f_epoch.Append(uint64(anomaly.Epoch))
+		f_slot.Append(uint64(anomaly.Slot))
+		f_status.Append(uint8(anomaly.Status))
+		f_computed_count.Append(anomaly.ComputedCount)
+		f_node_count.Append(anomaly.NodeCount)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_status", Data: f_status},
+		{Name: "f_computed_count", Data: f_computed_count},
+		{Name: "f_node_count", Data: f_node_count},
+	}
+}
+
+func (p *DBService) PersistValidatorStatusAnomalies(data []spec.ValidatorStatusAnomaly) error {
+	persistObj := PersistableObject[spec.ValidatorStatusAnomaly]{
+		input: valStatusAnomaliesInput,
+		table: valStatusAnomaliesTable,
+		query: insertValStatusAnomaliesQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting validator status anomalies: %s", err.Error())
+	}
+	return err
+}
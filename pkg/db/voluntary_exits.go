@@ -0,0 +1,107 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	voluntaryExitEventsTable       = "t_voluntary_exit_events"
+	insertVoluntaryExitEventsQuery = `
+	INSERT INTO %s (
+		f_arrival_timestamp_ms,
+		f_val_idx,
+		f_epoch)
+		VALUES`
+
+	voluntaryExitsTable       = "t_voluntary_exits"
+	insertVoluntaryExitsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_val_idx,
+		f_epoch)
+		VALUES`
+
+	deleteVoluntaryExitsQuery = `
+		DELETE FROM %s
+		WHERE f_slot = $1;`
+)
+
+func voluntaryExitEventsInput(events []spec.VoluntaryExitEvent) proto.Input {
+	// one object per column
+	var (
+		f_arrival_timestamp_ms proto.ColUInt64
+		f_val_idx              proto.ColUInt64
+		f_epoch                proto.ColUInt64
+	)
+
+	for _, event := range events {
+		f_arrival_timestamp_ms.Append(uint64(event.Timestamp.UnixMilli()))
+		f_val_idx.Append(uint64(event.ValidatorIndex))
+		f_epoch.Append(uint64(event.Epoch))
+	}
+
+	return proto.Input{
+		{Name: "f_arrival_timestamp_ms", Data: f_arrival_timestamp_ms},
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_epoch", Data: f_epoch},
+	}
+}
+
+// PersistVoluntaryExitEvents writes a batch of the voluntary exits broadcast in the network
+func (p *DBService) PersistVoluntaryExitEvents(data []spec.VoluntaryExitEvent) error {
+	persistObj := PersistableObject[spec.VoluntaryExitEvent]{
+		input: voluntaryExitEventsInput,
+		table: voluntaryExitEventsTable,
+		query: insertVoluntaryExitEventsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting voluntary exit events: %s", err.Error())
+	}
+	return err
+}
+
+func voluntaryExitsInput(exits []spec.VoluntaryExit) proto.Input {
+	// one object per column
+	var (
+		f_slot    proto.ColUInt64
+		f_val_idx proto.ColUInt64
+		f_epoch   proto.ColUInt64
+	)
+
+	for _, exit := range exits {
+		f_slot.Append(uint64(exit.Slot))
+		f_val_idx.Append(uint64(exit.ValidatorIndex))
+		f_epoch.Append(uint64(exit.Epoch))
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_val_idx", Data: f_val_idx},
+		{Name: "f_epoch", Data: f_epoch},
+	}
+}
+
+func (p *DBService) PersistVoluntaryExits(data []spec.VoluntaryExit) error {
+	persistObj := PersistableObject[spec.VoluntaryExit]{
+		input: voluntaryExitsInput,
+		table: voluntaryExitsTable,
+		query: insertVoluntaryExitsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting voluntary exits: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	voteTalliesTable       = "t_vote_tallies"
+	insertVoteTalliesQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_epoch,
+		f_included_votes,
+		f_new_votes,
+		f_expected_votes)
+		VALUES`
+)
+
+func voteTalliesInput(tallies []spec.VoteTally) proto.Input {
+	// one object per column
+	var (
+		f_slot           proto.ColUInt64
+		f_epoch          proto.ColUInt64
+		f_included_votes proto.ColUInt64
+		f_new_votes      proto.ColUInt64
+		f_expected_votes proto.ColUInt64
+	)
+
+	for _, tally := range tallies {
+		f_slot.Append(uint64(tally.Slot))
+		f_epoch.Append(uint64(tally.Epoch))
+		f_included_votes.Append(tally.IncludedVotes)
+		f_new_votes.Append(tally.NewVotes)
+		f_expected_votes.Append(tally.ExpectedVotes)
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_included_votes", Data: f_included_votes},
+		{Name: "f_new_votes", Data: f_new_votes},
+		{Name: "f_expected_votes", Data: f_expected_votes},
+	}
+}
+
+func (p *DBService) PersistVoteTallies(data []spec.VoteTally) error {
+	persistObj := PersistableObject[spec.VoteTally]{
+		input: voteTalliesInput,
+		table: voteTalliesTable,
+		query: insertVoteTalliesQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting vote tallies: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/ch-go"
+)
+
+const (
+	MaxWriters = 32 // maximum number of low level connections writing at the same time
+)
+
+// lowLevelWriter is a low level connection, used for one bulk load at a time
+type lowLevelWriter struct {
+	sync.Mutex
+	client *ch.Client
+	closed bool // removed from the writers, not reconnected anymore
+}
+
+func (w *lowLevelWriter) Close() {
+	w.Lock()
+	defer w.Unlock()
+	w.closed = true
+	w.client.Close()
+}
+
+// WithWriters sets the number of low level connections the batches are written through, so that
+// the blocks and epochs processed at the same time do not wait for each other to be persisted
+func WithWriters(writers int) DBServiceOption {
+	return func(s *DBService) error {
+		if writers < 1 || writers > MaxWriters {
+			return fmt.Errorf("the number of database writers must be between 1 and %d, got %d", 1, MaxWriters)
+		}
+		s.writersNum = writers
+		return nil
+	}
+}
+
+// dialWriter opens a new low level connection and makes it available to the writes
+func (s *DBService) dialWriter(ctx context.Context) error {
+	conn, err := ch.Dial(ctx, ParseChUrlIntoOptionsLowLevel(s.connectionUrl))
+	if err != nil {
+		return err
+	}
+	writer := &lowLevelWriter{client: conn}
+	s.writersMu.Lock()
+	s.writers = append(s.writers, writer)
+	s.writersMu.Unlock()
+	s.idleWriters <- writer
+	return nil
+}
+
+// acquireWriter waits for a low level connection no other write is using
+func (s *DBService) acquireWriter() *lowLevelWriter {
+	writer := <-s.idleWriters
+	writer.Lock()
+	return writer
+}
+
+func (s *DBService) releaseWriter(writer *lowLevelWriter) {
+	writer.Unlock()
+	s.idleWriters <- writer
+}
+
+// Writers returns the number of low level connections open
+func (s *DBService) Writers() int {
+	s.writersMu.Lock()
+	defer s.writersMu.Unlock()
+	return len(s.writers)
+}
+
+// SetWriters opens or closes low level connections until there are the given number.
+// Connections are closed once their write in progress is done
+func (s *DBService) SetWriters(writers int) error {
+	if writers < 1 || writers > MaxWriters {
+		return fmt.Errorf("the number of database writers must be between 1 and %d, got %d", 1, MaxWriters)
+	}
+	for s.Writers() < writers {
+		if err := s.dialWriter(s.ctx); err != nil {
+			return err
+		}
+	}
+	for s.Writers() > writers {
+		writer := s.acquireWriter()
+		s.writersMu.Lock()
+		for i, item := range s.writers {
+			if item == writer {
+				s.writers = append(s.writers[:i], s.writers[i+1:]...)
+				break
+			}
+		}
+		s.writersMu.Unlock()
+		writer.Unlock()
+		writer.Close()
+	}
+	s.writersNum = writers
+	return nil
+}
+
+// WriteTime returns the time spent inserting batches since the connection, adding up the writes in parallel.
+// The time a write waits for a free connection is left out, so it measures the load of the database
+func (s *DBService) WriteTime() time.Duration {
+	return time.Duration(s.writeTime.Load())
+}
+
+// openWriters returns the connections open, to go through them one at a time
+func (s *DBService) openWriters() []*lowLevelWriter {
+	s.writersMu.Lock()
+	defer s.writersMu.Unlock()
+	return append([]*lowLevelWriter{}, s.writers...)
+}
@@ -0,0 +1,181 @@
+package era
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/golang/snappy"
+)
+
+var (
+	SlotsPerEra uint64 = 8192 // SLOTS_PER_HISTORICAL_ROOT
+
+	headerSize = int64(8) // type (2 bytes), length (4 bytes) and reserved (2 bytes)
+
+	versionType         = [2]byte{0x65, 0x32}
+	compressedBlockType = [2]byte{0x01, 0x00}
+	slotIndexType       = [2]byte{0x69, 0x32}
+)
+
+// File is an era file: the blocks of an era (8192 slots) followed by the state at its end,
+// stored as e2store entries with snappy framed SSZ and a slot index at the end of the file
+type File struct {
+	path      string
+	file      *os.File
+	startSlot phase0.Slot
+	offsets   []int64 // position of the block of each slot of the era, 0 for empty slots
+}
+
+// Open reads the slot indexes of the given era file, the blocks are only read when requested
+func Open(path string) (*File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{
+		path: path,
+		file: file,
+	}
+	if err := f.readIndexes(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("could not read the slot index of %s: %s", path, err)
+	}
+	return f, nil
+}
+
+func (f *File) Path() string {
+	return f.path
+}
+
+// StartSlot returns the first slot with blocks in the file
+func (f *File) StartSlot() phase0.Slot {
+	return f.startSlot
+}
+
+// EndSlot returns the slot after the last one with blocks in the file
+func (f *File) EndSlot() phase0.Slot {
+	return f.startSlot + phase0.Slot(len(f.offsets))
+}
+
+// Covers returns whether the file has the block (or the lack of it) of the given slot
+func (f *File) Covers(slot phase0.Slot) bool {
+	return slot >= f.StartSlot() && slot < f.EndSlot()
+}
+
+// Block returns the SSZ encoded signed block at the given slot, nil if the slot was empty
+func (f *File) Block(slot phase0.Slot) ([]byte, error) {
+	if !f.Covers(slot) {
+		return nil, fmt.Errorf("slot %d is not in %s", slot, f.path)
+	}
+	offset := f.offsets[slot-f.startSlot]
+	if offset == 0 {
+		return nil, nil
+	}
+	entryType, data, err := f.readEntry(offset)
+	if err != nil {
+		return nil, err
+	}
+	if entryType != compressedBlockType {
+		return nil, fmt.Errorf("entry of slot %d is not a block: %x", slot, entryType)
+	}
+	return io.ReadAll(snappy.NewReader(bytes.NewReader(data)))
+}
+
+func (f *File) Close() error {
+	return f.file.Close()
+}
+
+// readIndexes reads the slot indexes at the end of the file: the one of the blocks (missing in the genesis era)
+// followed by the one of the state
+func (f *File) readIndexes() error {
+	info, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+	entryType, _, err := f.readEntry(0)
+	if err != nil {
+		return err
+	}
+	if entryType != versionType {
+		return fmt.Errorf("missing e2store version entry")
+	}
+
+	stateIndex, _, _, err := f.readSlotIndex(info.Size())
+	if err != nil {
+		return err
+	}
+	// the genesis era has no blocks, its state index follows the state
+	blockIndex, startSlot, offsets, err := f.readSlotIndex(stateIndex)
+	if err != nil || blockIndex <= headerSize {
+		return nil
+	}
+	f.startSlot = startSlot
+	f.offsets = offsets
+	return nil
+}
+
+// readSlotIndex reads the slot index that ends at the given position,
+// returning its position, its starting slot and the absolute position of each slot entry
+func (f *File) readSlotIndex(end int64) (int64, phase0.Slot, []int64, error) {
+	buf := make([]byte, 8)
+	if _, err := f.file.ReadAt(buf, end-8); err != nil {
+		return 0, 0, nil, err
+	}
+	count := binary.LittleEndian.Uint64(buf)
+	if count > SlotsPerEra {
+		return 0, 0, nil, fmt.Errorf("invalid slot index count %d", count)
+	}
+	start := end - headerSize - 8*(int64(count)+2)
+	if start < 0 {
+		return 0, 0, nil, fmt.Errorf("invalid slot index count %d", count)
+	}
+
+	entryType, _, err := f.readHeader(start)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if entryType != slotIndexType {
+		return 0, 0, nil, fmt.Errorf("entry at %d is not a slot index: %x", start, entryType)
+	}
+	_, data, err := f.readEntry(start)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if int64(len(data)) != 8*(int64(count)+2) {
+		return 0, 0, nil, fmt.Errorf("slot index at %d has %d bytes, expected %d", start, len(data), 8*(count+2))
+	}
+	startSlot := phase0.Slot(binary.LittleEndian.Uint64(data[:8]))
+	offsets := make([]int64, count)
+	for i := range offsets {
+		offset := int64(binary.LittleEndian.Uint64(data[8+8*i:]))
+		if offset != 0 {
+			offset += start // relative to the index entry
+		}
+		offsets[i] = offset
+	}
+	return start, startSlot, offsets, nil
+}
+
+func (f *File) readHeader(position int64) ([2]byte, int64, error) {
+	header := make([]byte, headerSize)
+	if _, err := f.file.ReadAt(header, position); err != nil {
+		return [2]byte{}, 0, err
+	}
+	return [2]byte{header[0], header[1]}, int64(binary.LittleEndian.Uint32(header[2:6])), nil
+}
+
+func (f *File) readEntry(position int64) ([2]byte, []byte, error) {
+	entryType, length, err := f.readHeader(position)
+	if err != nil {
+		return entryType, nil, err
+	}
+	data := make([]byte, length)
+	if _, err := f.file.ReadAt(data, position+headerSize); err != nil {
+		return entryType, nil, err
+	}
+	return entryType, data, nil
+}
@@ -0,0 +1,95 @@
+package era_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/golang/snappy"
+	"github.com/migalabs/goteth/pkg/era"
+)
+
+func writeEntry(buf *bytes.Buffer, entryType [2]byte, data []byte) int64 {
+	position := int64(buf.Len())
+	header := make([]byte, 8)
+	copy(header, entryType[:])
+	binary.LittleEndian.PutUint32(header[2:], uint32(len(data)))
+	buf.Write(header)
+	buf.Write(data)
+	return position
+}
+
+func compress(data []byte) []byte {
+	var buf bytes.Buffer
+	writer := snappy.NewBufferedWriter(&buf)
+	writer.Write(data)
+	writer.Close()
+	return buf.Bytes()
+}
+
+func slotIndex(startSlot uint64, offsets []int64, position int64) []byte {
+	data := make([]byte, 8*(len(offsets)+2))
+	binary.LittleEndian.PutUint64(data, startSlot)
+	for i, offset := range offsets {
+		if offset != 0 {
+			offset -= position
+		}
+		binary.LittleEndian.PutUint64(data[8+8*i:], uint64(offset))
+	}
+	binary.LittleEndian.PutUint64(data[8*(len(offsets)+1):], uint64(len(offsets)))
+	return data
+}
+
+// writeEraFile writes an era of 4 slots starting at the given one, with the blocks of the non-empty slots
+func writeEraFile(t *testing.T, startSlot uint64, blocks [][]byte) string {
+	var buf bytes.Buffer
+	writeEntry(&buf, [2]byte{0x65, 0x32}, nil)
+
+	offsets := make([]int64, len(blocks))
+	for i, block := range blocks {
+		if block != nil {
+			offsets[i] = writeEntry(&buf, [2]byte{0x01, 0x00}, compress(block))
+		}
+	}
+	statePosition := writeEntry(&buf, [2]byte{0x02, 0x00}, compress([]byte("state")))
+
+	indexPosition := int64(buf.Len())
+	writeEntry(&buf, [2]byte{0x69, 0x32}, slotIndex(startSlot, offsets, indexPosition))
+	indexPosition = int64(buf.Len())
+	writeEntry(&buf, [2]byte{0x69, 0x32}, slotIndex(startSlot+uint64(len(blocks)), []int64{statePosition}, indexPosition))
+
+	path := filepath.Join(t.TempDir(), "test-00001-00000000.era")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileBlock(t *testing.T) {
+	blocks := [][]byte{[]byte("block 8192"), nil, []byte("block 8194"), []byte("block 8195")}
+	file, err := era.Open(writeEraFile(t, 8192, blocks))
+	if err != nil {
+		t.Fatalf("Open returned an error: %s", err)
+	}
+	defer file.Close()
+
+	if file.StartSlot() != 8192 || file.EndSlot() != 8196 {
+		t.Errorf("Open returned slots %d to %d, expected 8192 to 8196", file.StartSlot(), file.EndSlot())
+	}
+	for i, expected := range blocks {
+		slot := phase0.Slot(8192 + i)
+		block, err := file.Block(slot)
+		if err != nil {
+			t.Fatalf("Block(%d) returned an error: %s", slot, err)
+		}
+		if !bytes.Equal(block, expected) {
+			t.Errorf("Block(%d) returned %q, expected %q", slot, block, expected)
+		}
+	}
+	if _, err := file.Block(8196); err == nil {
+		t.Errorf("Block(8196) returned no error, expected the slot to be out of the file")
+	}
+}
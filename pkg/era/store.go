@@ -0,0 +1,84 @@
+package era
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	log = logrus.WithField(
+		"module", "era",
+	)
+)
+
+// Store serves the blocks of the era files of a directory, the era states are not read
+type Store struct {
+	files []*File // sorted by start slot
+}
+
+// NewStore opens the era files (*.era) of the given directory
+func NewStore(dir string) (*Store, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.era"))
+	if err != nil {
+		return nil, err
+	}
+
+	store := &Store{
+		files: make([]*File, 0, len(paths)),
+	}
+	for _, path := range paths {
+		file, err := Open(path)
+		if err != nil {
+			store.Close()
+			return nil, err
+		}
+		if len(file.offsets) == 0 { // genesis era, no blocks
+			file.Close()
+			continue
+		}
+		store.files = append(store.files, file)
+	}
+	if len(store.files) == 0 {
+		return nil, fmt.Errorf("no era files with blocks found in %s", dir)
+	}
+	sort.Slice(store.files, func(i, j int) bool {
+		return store.files[i].StartSlot() < store.files[j].StartSlot()
+	})
+
+	log.Infof("%d era files with blocks from slot %d to %d", len(store.files), store.StartSlot(), store.EndSlot())
+	return store, nil
+}
+
+func (s *Store) StartSlot() phase0.Slot {
+	return s.files[0].StartSlot()
+}
+
+func (s *Store) EndSlot() phase0.Slot {
+	return s.files[len(s.files)-1].EndSlot()
+}
+
+// Block returns the SSZ encoded signed block at the given slot (nil if the slot was empty),
+// and whether any of the files covers the slot
+func (s *Store) Block(slot phase0.Slot) ([]byte, bool, error) {
+	i := sort.Search(len(s.files), func(i int) bool {
+		return s.files[i].EndSlot() > slot
+	})
+	if i == len(s.files) || !s.files[i].Covers(slot) {
+		return nil, false, nil
+	}
+	data, err := s.files[i].Block(slot)
+	if err != nil {
+		return nil, true, fmt.Errorf("could not read the block at slot %d from %s: %s", slot, s.files[i].Path(), err)
+	}
+	return data, true, nil
+}
+
+func (s *Store) Close() {
+	for _, file := range s.files {
+		file.Close()
+	}
+}
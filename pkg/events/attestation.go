@@ -0,0 +1,85 @@
+package events
+
+import (
+	"time"
+
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	eth2spec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func (e *Events) SubscribeToAttestationEvents() {
+	// subscribe to attestation event
+	err := e.subscribe([]string{"attestation"}, e.HandleAttestationEvent) // every attestation seen by the node
+	if err != nil {
+		log.Panicf("failed to subscribe to attestation events: %s", err)
+	}
+	log.Infof("subscribed to attestation events")
+
+	// single attestations only exist since Electra, older nodes might not know the topic
+	err = e.subscribe([]string{"single_attestation"}, e.HandleSingleAttestationEvent)
+	if err != nil {
+		log.Warnf("could not subscribe to single_attestation events: %s", err)
+		return
+	}
+	log.Infof("subscribed to single_attestation events")
+}
+
+func (e *Events) HandleAttestationEvent(event *api.Event) {
+	timestamp := time.Now().UnixMilli()
+	if event.Data == nil {
+		return
+	}
+	attestation := event.Data.(*eth2spec.VersionedAttestation)
+
+	data, err := attestation.Data()
+	if err != nil {
+		log.Debugf("could not read attestation event data: %s", err)
+		return
+	}
+	committeeIndex, err := attestation.CommitteeIndex()
+	if err != nil { // aggregates over several committees are only built for blocks
+		log.Debugf("could not read attestation event committee: %s", err)
+		return
+	}
+	aggregationBits, err := attestation.AggregationBits()
+	if err != nil {
+		log.Debugf("could not read attestation event aggregation bits: %s", err)
+		return
+	}
+
+	e.notifyAttestation(spec.AttestationEvent{
+		Slot:             data.Slot,
+		CommitteeIndex:   committeeIndex,
+		AggregationBits:  aggregationBits,
+		ArrivalTimestamp: timestamp,
+	})
+}
+
+func (e *Events) HandleSingleAttestationEvent(event *api.Event) {
+	timestamp := time.Now().UnixMilli()
+	if event.Data == nil {
+		return
+	}
+	attestation := event.Data.(*electra.SingleAttestation)
+	if attestation.Data == nil {
+		return
+	}
+
+	e.notifyAttestation(spec.AttestationEvent{
+		Slot:             attestation.Data.Slot,
+		CommitteeIndex:   attestation.CommitteeIndex,
+		AttesterIndex:    attestation.AttesterIndex,
+		Single:           true,
+		ArrivalTimestamp: timestamp,
+	})
+}
+
+func (e *Events) notifyAttestation(attestation spec.AttestationEvent) {
+	select { // only notify if we can, attestations are plenty
+	case e.AttestationChan <- attestation:
+	default:
+		log.Tracef("attestation event dropped: slot %d, committee %d", attestation.Slot, attestation.CommitteeIndex)
+	}
+}
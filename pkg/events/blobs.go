@@ -9,7 +9,7 @@ import (
 
 func (e *Events) SubscribeToBlobSidecarsEvents() {
 	// subscribe to head event
-	err := e.cli.Api.Events(e.ctx, []string{"blob_sidecar"}, e.HandleBlobSidecarEvent) // every reorg
+	err := e.subscribe([]string{"blob_sidecar"}, e.HandleBlobSidecarEvent) // every reorg
 	if err != nil {
 		log.Panicf("failed to subscribe to blob_sidecar events: %s", err)
 	}
@@ -4,7 +4,7 @@ import api "github.com/attestantio/go-eth2-client/api/v1"
 
 func (e *Events) SubscribeToFinalizedCheckpointEvents() {
 	// subscribe to head event
-	err := e.cli.Api.Events(e.ctx, []string{"finalized_checkpoint"}, e.HandleCheckpointEvent) // every new checkpoint
+	err := e.subscribe([]string{"finalized_checkpoint"}, e.HandleCheckpointEvent) // every new checkpoint
 	if err != nil {
 		log.Panicf("failed to subscribe to finalized checkpoint events: %s", err)
 	}
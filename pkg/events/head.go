@@ -10,9 +10,9 @@ import (
 	"github.com/migalabs/goteth/pkg/spec"
 )
 
-func (e Events) SubscribeToHeadEvents() {
+func (e *Events) SubscribeToHeadEvents() {
 	// subscribe to head event
-	err := e.cli.Api.Events(e.ctx, []string{"head"}, e.HandleHeadEvent) // every new head
+	err := e.subscribe([]string{"head"}, e.HandleHeadEvent) // every new head
 	if err != nil {
 		log.Panicf("failed to subscribe to head events: %s", err)
 	}
@@ -26,6 +26,7 @@ func (e *Events) HandleHeadEvent(event *api.Event) {
 		return
 	}
 	data := event.Data.(*api.HeadEvent) // cast to head event
+	e.markHeadEvent(data.Slot)
 	headEpoch := phase0.Epoch(data.Slot) / spec.SlotsPerEpoch
 
 	log.Infof("New event: slot %d, epoch %d. %d pending slots for new epoch",
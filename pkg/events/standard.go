@@ -14,6 +14,7 @@ var (
 	log = logrus.WithField(
 		"module", "Events",
 	)
+	attestationQueueSize = 4096 // attestation events waiting to be recorded
 )
 
 type Events struct {
@@ -26,6 +27,11 @@ type Events struct {
 	FinalizedChan       chan api.FinalizedCheckpointEvent
 	ReorgChan           chan api.ChainReorgEvent
 	BlobSidecarChan     chan spec.BlobSideCarEventWraper
+	ReconnectChan       chan struct{} // notifies that the event stream was renewed after a drop
+	AttestationChan     chan spec.AttestationEvent
+	VoluntaryExitChan   chan spec.VoluntaryExitEvent
+
+	subs *subscriptions
 }
 
 func NewEventsObj(iCtx context.Context, iCli *clientapi.APIClient) Events {
@@ -38,5 +44,9 @@ func NewEventsObj(iCtx context.Context, iCli *clientapi.APIClient) Events {
 		FinalizedChan:       make(chan api.FinalizedCheckpointEvent),
 		ReorgChan:           make(chan api.ChainReorgEvent),
 		BlobSidecarChan:     make(chan spec.BlobSideCarEventWraper),
+		ReconnectChan:       make(chan struct{}, 1),
+		AttestationChan:     make(chan spec.AttestationEvent, attestationQueueSize),
+		VoluntaryExitChan:   make(chan spec.VoluntaryExitEvent),
+		subs:                &subscriptions{},
 	}
 }
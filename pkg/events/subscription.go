@@ -0,0 +1,170 @@
+package events
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	consensusclient "github.com/attestantio/go-eth2-client"
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	eventsTimeout     = 3 * spec.SlotSeconds * time.Second // no event in this time, nor a quiet node, means the stream is considered dropped
+	reconnectBaseWait = 1 * time.Second
+	reconnectMaxWait  = 1 * time.Minute
+)
+
+type subscription struct {
+	topics  []string
+	handler consensusclient.EventHandlerFunc
+	cancel  context.CancelFunc
+}
+
+// subscriptions keeps track of the active event streams so they can be renewed
+type subscriptions struct {
+	sync.Mutex
+	subs         []*subscription
+	lastEvent    time.Time   // last event of any topic
+	lastHeadSlot phase0.Slot // slot of the last head event
+}
+
+// subscribe opens a new event stream under its own context, so it can be cancelled and renewed
+func (e *Events) subscribe(topics []string, handler consensusclient.EventHandlerFunc) error {
+	ctx, cancel := context.WithCancel(e.ctx)
+	handler = e.watchedHandler(handler)
+	err := e.cli.Api.Events(ctx, topics, handler)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	e.subs.Lock()
+	defer e.subs.Unlock()
+	e.subs.subs = append(e.subs.subs, &subscription{
+		topics:  topics,
+		handler: handler,
+		cancel:  cancel,
+	})
+	if e.subs.lastEvent.IsZero() {
+		e.subs.lastEvent = time.Now()
+	}
+	return nil
+}
+
+// watchedHandler wraps the handler of a subscription so that every event received keeps the stream alive for the watchdog
+func (e *Events) watchedHandler(handler consensusclient.EventHandlerFunc) consensusclient.EventHandlerFunc {
+	return func(event *api.Event) {
+		e.markEvent()
+		handler(event)
+	}
+}
+
+func (e *Events) markEvent() {
+	e.subs.Lock()
+	defer e.subs.Unlock()
+	e.subs.lastEvent = time.Now()
+}
+
+func (e *Events) markHeadEvent(slot phase0.Slot) {
+	e.subs.Lock()
+	defer e.subs.Unlock()
+	if slot > e.subs.lastHeadSlot {
+		e.subs.lastHeadSlot = slot
+	}
+}
+
+func (e *Events) sinceLastEvent() time.Duration {
+	e.subs.Lock()
+	defer e.subs.Unlock()
+	return time.Since(e.subs.lastEvent)
+}
+
+// heartbeat tells whether the node is alive but has nothing new to stream: it answers and its head is not
+// newer than the last head event. The heartbeat comments of the stream are not passed on by the events client
+func (e *Events) heartbeat() bool {
+	head, err := e.cli.RequestHeadSlot(nil)
+	if err != nil {
+		return false
+	}
+	e.subs.Lock()
+	defer e.subs.Unlock()
+	return head <= e.subs.lastHeadSlot
+}
+
+// resubscribe cancels every active stream and opens them again
+func (e *Events) resubscribe() error {
+	e.subs.Lock()
+	defer e.subs.Unlock()
+
+	for _, sub := range e.subs.subs {
+		sub.cancel()
+	}
+	for _, sub := range e.subs.subs {
+		ctx, cancel := context.WithCancel(e.ctx)
+		if err := e.cli.Api.Events(ctx, sub.topics, sub.handler); err != nil {
+			cancel()
+			return err
+		}
+		sub.cancel = cancel
+	}
+	e.subs.lastEvent = time.Now()
+	return nil
+}
+
+// jitteredBackoff returns a random wait between half and the full exponential backoff for the attempt
+func jitteredBackoff(attempt int) time.Duration {
+	backoff := reconnectBaseWait << attempt
+	if backoff > reconnectMaxWait || backoff <= 0 {
+		backoff = reconnectMaxWait
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// RunWatchdog checks that events keep arriving, or that the node has no new head to stream (heartbeat).
+// If the stream goes silent all the subscriptions are renewed with jittered backoff, and once reconnected
+// a signal is sent through ReconnectChan so that missed slots can be backfilled
+func (e *Events) RunWatchdog() {
+	ticker := time.NewTicker(spec.SlotSeconds * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if e.sinceLastEvent() < eventsTimeout {
+				continue
+			}
+			if e.heartbeat() {
+				e.markEvent()
+				continue
+			}
+			log.Warnf("no event received in %s while the node head moved on, reconnecting to the event stream", eventsTimeout)
+
+			for attempt := 0; ; attempt++ {
+				wait := jitteredBackoff(attempt)
+				select {
+				case <-time.After(wait):
+				case <-e.ctx.Done():
+					return
+				}
+				err := e.resubscribe()
+				if err == nil {
+					break
+				}
+				log.Errorf("could not resubscribe to events (attempt %d, waited %s): %s", attempt+1, wait, err)
+			}
+			log.Infof("event stream reconnected")
+
+			select { // only notify if we can, one pending notification is enough
+			case e.ReconnectChan <- struct{}{}:
+			default:
+			}
+
+		case <-e.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package events
+
+import (
+	"time"
+
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func (e *Events) SubscribeToVoluntaryExitEvents() {
+	// subscribe to voluntary exit event
+	err := e.subscribe([]string{"voluntary_exit"}, e.HandleVoluntaryExitEvent) // every exit broadcast in the network
+	if err != nil {
+		log.Panicf("failed to subscribe to voluntary_exit events: %s", err)
+	}
+	log.Infof("subscribed to voluntary_exit events")
+}
+
+func (e *Events) HandleVoluntaryExitEvent(event *api.Event) {
+	timestamp := time.Now()
+	if event.Data == nil {
+		return
+	}
+	exit := event.Data.(*phase0.SignedVoluntaryExit)
+	if exit.Message == nil {
+		return
+	}
+
+	e.VoluntaryExitChan <- spec.VoluntaryExitEvent{
+		Timestamp:      timestamp,
+		ValidatorIndex: exit.Message.ValidatorIndex,
+		Epoch:          exit.Message.Epoch,
+	}
+}
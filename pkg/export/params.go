@@ -0,0 +1,25 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ParseValidators reads a comma separated list of validator indexes
+func ParseValidators(input string) ([]phase0.ValidatorIndex, error) {
+	validators := make([]phase0.ValidatorIndex, 0)
+	for _, item := range strings.Split(input, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		valIdx, err := strconv.ParseUint(item, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validator index %s: %s", item, err)
+		}
+		validators = append(validators, phase0.ValidatorIndex(valIdx))
+	}
+	return validators, nil
+}
@@ -0,0 +1,164 @@
+package export
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	ssz "github.com/ferranbt/fastssz"
+)
+
+var (
+	// columns of the canonical-roots dataset, in the order of the fields of CanonicalRoot
+	canonicalRootColumns = []string{"f_slot", "f_epoch", "f_proposed", "f_block_root", "f_parent_root", "f_state_root"}
+
+	canonicalRootSize = 8 + 8 + 1 + 3*32
+)
+
+// CanonicalRoot is the SSZ container every canonical slot is exported as:
+//
+//	slot: uint64, epoch: uint64, proposed: boolean, block_root: Root, parent_root: Root, state_root: Root
+//
+// An export is the serialization of a list of them, i.e. the containers one after the other
+type CanonicalRoot struct {
+	Slot       uint64
+	Epoch      uint64
+	Proposed   bool
+	BlockRoot  [32]byte
+	ParentRoot [32]byte
+	StateRoot  [32]byte
+}
+
+func (c *CanonicalRoot) SizeSSZ() int {
+	return canonicalRootSize
+}
+
+func (c *CanonicalRoot) MarshalSSZ() ([]byte, error) {
+	return ssz.MarshalSSZ(c)
+}
+
+func (c *CanonicalRoot) MarshalSSZTo(buf []byte) ([]byte, error) {
+	buf = ssz.MarshalUint64(buf, c.Slot)
+	buf = ssz.MarshalUint64(buf, c.Epoch)
+	buf = ssz.MarshalBool(buf, c.Proposed)
+	buf = append(buf, c.BlockRoot[:]...)
+	buf = append(buf, c.ParentRoot[:]...)
+	buf = append(buf, c.StateRoot[:]...)
+	return buf, nil
+}
+
+func (c *CanonicalRoot) UnmarshalSSZ(buf []byte) error {
+	if len(buf) != canonicalRootSize {
+		return ssz.ErrSize
+	}
+	if buf[16] > 1 {
+		return fmt.Errorf("invalid boolean %d", buf[16])
+	}
+	c.Slot = ssz.UnmarshallUint64(buf[0:8])
+	c.Epoch = ssz.UnmarshallUint64(buf[8:16])
+	c.Proposed = ssz.UnmarshalBool(buf[16:17])
+	copy(c.BlockRoot[:], buf[17:49])
+	copy(c.ParentRoot[:], buf[49:81])
+	copy(c.StateRoot[:], buf[81:113])
+	return nil
+}
+
+func (c *CanonicalRoot) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(c)
+}
+
+func (c *CanonicalRoot) HashTreeRootWith(hh ssz.HashWalker) error {
+	indx := hh.Index()
+	hh.PutUint64(c.Slot)
+	hh.PutUint64(c.Epoch)
+	hh.PutBool(c.Proposed)
+	hh.PutBytes(c.BlockRoot[:])
+	hh.PutBytes(c.ParentRoot[:])
+	hh.PutBytes(c.StateRoot[:])
+	hh.Merkleize(indx)
+	return nil
+}
+
+func (c *CanonicalRoot) GetTree() (*ssz.Node, error) {
+	return ssz.ProofTree(c)
+}
+
+// DecodeCanonicalRoots reads an export of canonical roots in the ssz format
+func DecodeCanonicalRoots(buf []byte) ([]CanonicalRoot, error) {
+	if len(buf)%canonicalRootSize != 0 {
+		return nil, fmt.Errorf("%d bytes are not a list of %d byte containers", len(buf), canonicalRootSize)
+	}
+	roots := make([]CanonicalRoot, len(buf)/canonicalRootSize)
+	for i := range roots {
+		if err := roots[i].UnmarshalSSZ(buf[i*canonicalRootSize : (i+1)*canonicalRootSize]); err != nil {
+			return nil, fmt.Errorf("container %d: %w", i, err)
+		}
+	}
+	return roots, nil
+}
+
+// newCanonicalRoot builds the container of an exported row, with the canonical-roots columns.
+// Empty roots (missed slots, or blocks stored before the roots) are zero
+func newCanonicalRoot(row []any) (CanonicalRoot, error) {
+	root := CanonicalRoot{}
+	if len(row) != len(canonicalRootColumns) {
+		return root, fmt.Errorf("row has %d values, expected %d", len(row), len(canonicalRootColumns))
+	}
+	var ok bool
+	if root.Slot, ok = row[0].(uint64); !ok {
+		return root, fmt.Errorf("slot %v of type %T is not an uint64", row[0], row[0])
+	}
+	if root.Epoch, ok = row[1].(uint64); !ok {
+		return root, fmt.Errorf("epoch %v of type %T is not an uint64", row[1], row[1])
+	}
+	if root.Proposed, ok = row[2].(bool); !ok {
+		return root, fmt.Errorf("proposed %v of type %T is not a boolean", row[2], row[2])
+	}
+	for i, dest := range []*[32]byte{&root.BlockRoot, &root.ParentRoot, &root.StateRoot} {
+		value, ok := row[3+i].(string)
+		if !ok {
+			return root, fmt.Errorf("%s %v of type %T is not a root", canonicalRootColumns[3+i], row[3+i], row[3+i])
+		}
+		if value == "" {
+			continue
+		}
+		decoded, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+		if err != nil || len(decoded) != len(dest) {
+			return root, fmt.Errorf("%s %s is not a root", canonicalRootColumns[3+i], value)
+		}
+		copy(dest[:], decoded)
+	}
+	return root, nil
+}
+
+// sszWriter writes the canonical-roots dataset as the SSZ serialization of a list of CanonicalRoot,
+// other datasets have no SSZ schema
+type sszWriter struct {
+	writer *bufio.Writer
+}
+
+func (w *sszWriter) WriteColumns(columns []string) error {
+	if !slices.Equal(columns, canonicalRootColumns) {
+		return fmt.Errorf("only the canonical-roots dataset can be written as ssz, got the columns %v", columns)
+	}
+	return nil
+}
+
+func (w *sszWriter) WriteRow(row []any) error {
+	root, err := newCanonicalRoot(row)
+	if err != nil {
+		return err
+	}
+	record, err := root.MarshalSSZTo(make([]byte, 0, canonicalRootSize))
+	if err != nil {
+		return err
+	}
+	_, err = w.writer.Write(record)
+	return err
+}
+
+func (w *sszWriter) Flush() error {
+	return w.writer.Flush()
+}
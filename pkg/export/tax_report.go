@@ -0,0 +1,91 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+)
+
+var (
+	dayLayout        = "2006-01-02"
+	TaxReportColumns = []string{"date", "validator", "kind", "amount_eth", "price", "value"}
+)
+
+// ReadPrices reads a CSV file with the daily price of ETH (date,price), the date as YYYY-MM-DD.
+// A header line is skipped
+func ReadPrices(path string) (map[string]float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not read prices file %s: %s", path, err)
+	}
+	prices := make(map[string]float64, len(records))
+	for i, record := range records {
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d: expected date,price", i+1)
+		}
+		date := strings.TrimSpace(record[0])
+		price, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if _, dateErr := time.Parse(dayLayout, date); dateErr != nil || err != nil {
+			if i == 0 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: invalid date or price", i+1)
+		}
+		prices[date] = price
+	}
+	return prices, nil
+}
+
+// TaxReportRows sorts the income by date, validator and kind, adding the price of the day and the value when known
+func TaxReportRows(income []db.Income, prices map[string]float64) [][]any {
+	sort.Slice(income, func(i, j int) bool {
+		if !income[i].Day.Equal(income[j].Day) {
+			return income[i].Day.Before(income[j].Day)
+		}
+		if income[i].ValidatorIndex != income[j].ValidatorIndex {
+			return income[i].ValidatorIndex < income[j].ValidatorIndex
+		}
+		return income[i].Kind < income[j].Kind
+	})
+
+	rows := make([][]any, 0, len(income))
+	for _, item := range income {
+		if item.AmountEth == 0 {
+			continue
+		}
+		date := item.Day.UTC().Format(dayLayout)
+		var price, value any // empty when there is no price for the day
+		if dayPrice, ok := prices[date]; ok {
+			price = dayPrice
+			value = item.AmountEth * dayPrice
+		}
+		rows = append(rows, []any{date, uint64(item.ValidatorIndex), string(item.Kind), item.AmountEth, price, value})
+	}
+	return rows
+}
+
+// YearSlots returns the first and last slots of the given year (UTC), for the given genesis time and slot duration
+func YearSlots(year int, genesis int64, secondsPerSlot uint64) (phase0.Slot, phase0.Slot, error) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	end := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC).Unix() - 1
+	if end < genesis {
+		return 0, 0, fmt.Errorf("year %d is before genesis", year)
+	}
+	if start < genesis {
+		start = genesis
+	}
+	return phase0.Slot(uint64(start-genesis) / secondsPerSlot), phase0.Slot(uint64(end-genesis) / secondsPerSlot), nil
+}
@@ -0,0 +1,67 @@
+package export_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/export"
+)
+
+func TestYearSlots(t *testing.T) {
+	genesis := int64(1606824023) // mainnet
+
+	tests := []struct {
+		name  string
+		year  int
+		first phase0.Slot
+		last  phase0.Slot
+		err   bool
+	}{
+		{name: "genesis year", year: 2020, first: 0, last: 219598},
+		{name: "full year", year: 2024, first: 8103598, last: 10738798},
+		{name: "before genesis", year: 2019, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			first, last, err := export.YearSlots(test.year, genesis, 12)
+			if (err != nil) != test.err {
+				t.Fatalf("YearSlots returned error %v, expected error: %t", err, test.err)
+			}
+			if first != test.first || last != test.last {
+				t.Errorf("YearSlots returned %d-%d, expected %d-%d", first, last, test.first, test.last)
+			}
+		})
+	}
+}
+
+func TestTaxReportRows(t *testing.T) {
+	day1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	income := []db.Income{
+		{Day: day2, ValidatorIndex: 1, Kind: db.ClRewardsIncome, AmountEth: 0.01},
+		{Day: day1, ValidatorIndex: 2, Kind: db.ProposerElIncome, AmountEth: 0.5},
+		{Day: day1, ValidatorIndex: 1, Kind: db.ProposerElIncome, AmountEth: 0},
+		{Day: day1, ValidatorIndex: 1, Kind: db.ClRewardsIncome, AmountEth: 0.02},
+	}
+	prices := map[string]float64{"2024-03-01": 3000}
+
+	rows := export.TaxReportRows(income, prices)
+	if len(rows) != 3 {
+		t.Fatalf("TaxReportRows returned %d rows, expected %d", len(rows), 3)
+	}
+	expected := [][]any{
+		{"2024-03-01", uint64(1), "cl_rewards", 0.02, 3000.0, 60.0},
+		{"2024-03-01", uint64(2), "proposer_el", 0.5, 3000.0, 1500.0},
+		{"2024-03-02", uint64(1), "cl_rewards", 0.01, nil, nil},
+	}
+	for i, row := range rows {
+		for j := range row {
+			if row[j] != expected[i][j] {
+				t.Errorf("TaxReportRows returned %v at row %d column %d, expected %v", row[j], i, j, expected[i][j])
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type Format string
+
+const (
+	CSVFormat  Format = "csv"
+	JSONFormat Format = "json" // one object per line
+	SSZFormat  Format = "ssz"  // canonical roots only, see CanonicalRoot
+)
+
+// Writer writes the rows of an export in the given format
+type Writer interface {
+	WriteColumns(columns []string) error
+	WriteRow(row []any) error
+	Flush() error
+}
+
+func NewWriter(format Format, output io.Writer) (Writer, error) {
+	switch format {
+	case CSVFormat:
+		return &csvWriter{writer: csv.NewWriter(output)}, nil
+	case JSONFormat:
+		return &jsonWriter{writer: bufio.NewWriter(output)}, nil
+	case SSZFormat:
+		return &sszWriter{writer: bufio.NewWriter(output)}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %s", format)
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvWriter) WriteColumns(columns []string) error {
+	return w.writer.Write(columns)
+}
+
+func (w *csvWriter) WriteRow(row []any) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = FormatValue(value)
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonWriter struct {
+	writer  *bufio.Writer
+	columns []string
+}
+
+func (w *jsonWriter) WriteColumns(columns []string) error {
+	w.columns = columns
+	return nil
+}
+
+func (w *jsonWriter) WriteRow(row []any) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(row), len(w.columns))
+	}
+	object := make(map[string]any, len(row))
+	for i, value := range row {
+		object[w.columns[i]] = value
+	}
+	line, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	_, err = w.writer.Write(append(line, '\n'))
+	return err
+}
+
+func (w *jsonWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+// FormatValue returns the CSV representation of a value, empty for null ones
+func FormatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,169 @@
+package export_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/migalabs/goteth/pkg/export"
+)
+
+func TestWriter(t *testing.T) {
+	columns := []string{"f_val_idx", "f_address", "f_amount"}
+	rows := [][]any{
+		{uint64(1), "0xabc", uint64(100)},
+		{uint64(2), nil, float32(0.5)},
+	}
+
+	tests := []struct {
+		format export.Format
+		result string
+	}{
+		{
+			format: export.CSVFormat,
+			result: "f_val_idx,f_address,f_amount\n1,0xabc,100\n2,,0.5\n",
+		},
+		{
+			format: export.JSONFormat,
+			result: "{\"f_address\":\"0xabc\",\"f_amount\":100,\"f_val_idx\":1}\n{\"f_address\":null,\"f_amount\":0.5,\"f_val_idx\":2}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.format), func(t *testing.T) {
+			var output bytes.Buffer
+			writer, err := export.NewWriter(test.format, &output)
+			if err != nil {
+				t.Fatalf("NewWriter returned error: %s", err)
+			}
+			if err := writer.WriteColumns(columns); err != nil {
+				t.Fatalf("WriteColumns returned error: %s", err)
+			}
+			for _, row := range rows {
+				if err := writer.WriteRow(row); err != nil {
+					t.Fatalf("WriteRow returned error: %s", err)
+				}
+			}
+			if err := writer.Flush(); err != nil {
+				t.Fatalf("Flush returned error: %s", err)
+			}
+			if output.String() != test.result {
+				t.Errorf("writer returned %q, expected %q", output.String(), test.result)
+			}
+		})
+	}
+}
+
+func TestSSZWriter(t *testing.T) {
+	var output bytes.Buffer
+	writer, err := export.NewWriter(export.SSZFormat, &output)
+	if err != nil {
+		t.Fatalf("NewWriter returned error: %s", err)
+	}
+	if err := writer.WriteColumns([]string{"f_val_idx", "f_epoch"}); err == nil {
+		t.Errorf("WriteColumns of another dataset returned no error, expected one")
+	}
+	columns := []string{"f_slot", "f_epoch", "f_proposed", "f_block_root", "f_parent_root", "f_state_root"}
+	if err := writer.WriteColumns(columns); err != nil {
+		t.Fatalf("WriteColumns returned error: %s", err)
+	}
+	root := "0x" + strings.Repeat("ab", 32)
+	rows := [][]any{
+		{uint64(258), uint64(8), true, root, root, root},
+		{uint64(259), uint64(8), false, "", root, root},
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow returned error: %s", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %s", err)
+	}
+
+	expected := append([]byte{2, 1, 0, 0, 0, 0, 0, 0, 8, 0, 0, 0, 0, 0, 0, 0, 1}, bytes.Repeat([]byte{0xab}, 3*32)...)
+	expected = append(expected, []byte{3, 1, 0, 0, 0, 0, 0, 0, 8, 0, 0, 0, 0, 0, 0, 0, 0}...)
+	expected = append(expected, make([]byte, 32)...)
+	expected = append(expected, bytes.Repeat([]byte{0xab}, 2*32)...)
+	if !bytes.Equal(output.Bytes(), expected) {
+		t.Errorf("writer returned %x, expected %x", output.Bytes(), expected)
+	}
+
+	decoded, err := export.DecodeCanonicalRoots(output.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeCanonicalRoots returned error: %s", err)
+	}
+	if len(decoded) != 2 || decoded[1].Slot != 259 || decoded[1].Proposed || decoded[1].BlockRoot != [32]byte{} || decoded[1].StateRoot[0] != 0xab {
+		t.Errorf("DecodeCanonicalRoots returned %+v", decoded)
+	}
+	if _, err := export.DecodeCanonicalRoots(output.Bytes()[1:]); err == nil {
+		t.Errorf("DecodeCanonicalRoots of a truncated export returned no error, expected one")
+	}
+
+	for _, row := range [][]any{
+		{uint64(1), uint64(0), true, "0x1234", root, root},
+		{uint64(1), uint64(0), uint8(1), root, root, root},
+		{float64(1.5), uint64(0), true, root, root, root},
+		{uint64(1), uint64(0), true},
+	} {
+		if err := writer.WriteRow(row); err == nil {
+			t.Errorf("WriteRow of %v returned no error, expected one", row)
+		}
+	}
+}
+
+func TestCanonicalRootHashTreeRoot(t *testing.T) {
+	// a container of six fields is merkleized over 8 chunks: the root is the same as the
+	// hash tree root of the fields padded with two zero chunks
+	root := export.CanonicalRoot{Slot: 258, Epoch: 8, Proposed: true, BlockRoot: [32]byte{1}, ParentRoot: [32]byte{2}, StateRoot: [32]byte{3}}
+	chunk := func(b ...byte) []byte {
+		c := make([]byte, 32)
+		copy(c, b)
+		return c
+	}
+	hash := func(a []byte, b []byte) []byte {
+		h := sha256.Sum256(append(append([]byte{}, a...), b...))
+		return h[:]
+	}
+	leaves := [][]byte{chunk(2, 1), chunk(8), chunk(1), chunk(1), chunk(2), chunk(3), chunk(), chunk()}
+	for len(leaves) > 1 {
+		next := make([][]byte, 0, len(leaves)/2)
+		for i := 0; i < len(leaves); i += 2 {
+			next = append(next, hash(leaves[i], leaves[i+1]))
+		}
+		leaves = next
+	}
+
+	result, err := root.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("HashTreeRoot returned error: %s", err)
+	}
+	if !bytes.Equal(result[:], leaves[0]) {
+		t.Errorf("HashTreeRoot returned %x, expected %x", result, leaves[0])
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  any
+		result string
+	}{
+		{name: "null", value: nil, result: ""},
+		{name: "bool", value: true, result: "true"},
+		{name: "float", value: float32(1.25), result: "1.25"},
+		{name: "small float", value: 0.00001, result: "0.00001"},
+		{name: "time", value: time.Date(2024, 3, 13, 13, 55, 35, 0, time.UTC), result: "2024-03-13T13:55:35Z"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := export.FormatValue(test.value)
+			if result != test.result {
+				t.Errorf("FormatValue returned %s, expected %s", result, test.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package graphql
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	Endpoint = "/graphql"
+
+	log = logrus.WithField(
+		"module", "graphql",
+	)
+)
+
+// Querier runs the SQL queries the GraphQL fields are resolved with (i.e. the db.DBService)
+type Querier interface {
+	QueryRows(query string, args []any, onColumns func([]string) error, onRow func([]any) error) (int, error)
+	QueryRewardsRows(query string, args []any, limit int64, offset int64,
+		onColumns func([]string) error, onRow func([]any) error) (int, error)
+	RewardsSource() string
+}
+
+type request struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type responseError struct {
+	Message string `json:"message"`
+}
+
+type response struct {
+	Data   *object         `json:"data,omitempty"`
+	Errors []responseError `json:"errors,omitempty"`
+}
+
+// member is a key of a response object, objects keep the order of the selected fields
+type member struct {
+	key   string
+	value any
+}
+
+type object []member
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// NewHandler serves the GraphQL queries (GET ?query= or POST {"query", "variables"}) over the given database
+func NewHandler(db Querier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		switch r.Method {
+		case http.MethodGet:
+			req.Query = r.URL.Query().Get("query")
+			if variables := r.URL.Query().Get("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					writeResponse(w, http.StatusBadRequest, response{Errors: []responseError{{Message: "invalid variables: " + err.Error()}}})
+					return
+				}
+			}
+		case http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeResponse(w, http.StatusBadRequest, response{Errors: []responseError{{Message: "invalid request body: " + err.Error()}}})
+				return
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		fields, err := ParseQuery(req.Query, req.Variables)
+		if err != nil {
+			writeResponse(w, http.StatusBadRequest, response{Errors: []responseError{{Message: err.Error()}}})
+			return
+		}
+		data, err := resolve(db, fields)
+		if err != nil {
+			log.Debugf("could not resolve graphql query: %s", err)
+			writeResponse(w, http.StatusOK, response{Errors: []responseError{{Message: err.Error()}}})
+			return
+		}
+		writeResponse(w, http.StatusOK, response{Data: &data})
+	}
+}
+
+// resolve runs the root fields of a query, each of them a list with the selected fields of its rows
+func resolve(db Querier, fields []Field) (object, error) {
+	data := make(object, 0, len(fields))
+	for _, field := range fields {
+		if field.Name == "__typename" {
+			data = append(data, member{key: field.Alias, value: "Query"})
+			continue
+		}
+		query, args, limit, offset, err := buildQuery(db, field)
+		if err != nil {
+			return nil, err
+		}
+		typeName := queryFields[field.Name].name
+
+		// the rewards may be spread over several databases, which paginate their merged rows
+		queryRows := func(onColumns func([]string) error, onRow func([]any) error) (int, error) {
+			if queryFields[field.Name].sharded {
+				return db.QueryRewardsRows(query, args, limit, offset, onColumns, onRow)
+			}
+			return db.QueryRows(fmt.Sprintf("%s LIMIT %d OFFSET %d", query, limit, offset), args, onColumns, onRow)
+		}
+
+		items := make([]object, 0)
+		_, err = queryRows(
+			func([]string) error { return nil },
+			func(row []any) error {
+				item := make(object, 0, len(field.Selection))
+				column := 0
+				for _, subfield := range field.Selection {
+					if subfield.Name == "__typename" {
+						item = append(item, member{key: subfield.Alias, value: typeName})
+						continue
+					}
+					item = append(item, member{key: subfield.Alias, value: row[column]})
+					column++
+				}
+				items = append(items, item)
+				return nil
+			})
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve %s: %s", field.Alias, err)
+		}
+		data = append(data, member{key: field.Alias, value: items})
+	}
+	return data, nil
+}
+
+// WithAuth only serves the requests carrying the given Authorization header (i.e. "Bearer <token>"),
+// every request is served if it is empty
+func WithAuth(authorization string, handler http.HandlerFunc) http.HandlerFunc {
+	if authorization == "" {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(authorization)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="goteth", Basic realm="goteth"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Errorf("could not write graphql response: %s", err)
+	}
+}
@@ -0,0 +1,59 @@
+package graphql_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/graphql"
+)
+
+func TestWithAuth(t *testing.T) {
+	served := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string // expected by the handler
+		header        string // sent with the request
+		status        int
+	}{
+		{
+			name:   "no auth required",
+			header: "",
+			status: http.StatusOK,
+		},
+		{
+			name:          "matching token",
+			authorization: "Bearer secret",
+			header:        "Bearer secret",
+			status:        http.StatusOK,
+		},
+		{
+			name:          "missing header",
+			authorization: "Bearer secret",
+			status:        http.StatusUnauthorized,
+		},
+		{
+			name:          "wrong token",
+			authorization: "Bearer secret",
+			header:        "Bearer other",
+			status:        http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, graphql.Endpoint, nil)
+			if test.header != "" {
+				request.Header.Set("Authorization", test.header)
+			}
+			recorder := httptest.NewRecorder()
+			graphql.WithAuth(test.authorization, served)(recorder, request)
+			if recorder.Code != test.status {
+				t.Errorf("WithAuth answered %d, expected %d", recorder.Code, test.status)
+			}
+		})
+	}
+}
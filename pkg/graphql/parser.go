@@ -0,0 +1,244 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field is a selected field of a query, with its arguments and the fields selected from it
+type Field struct {
+	Alias     string // name of the field in the response, the field name if no alias is given
+	Name      string
+	Args      map[string]any
+	Selection []Field
+}
+
+// ParseQuery parses a query operation (the subset of GraphQL served by the analyzer:
+// fields, aliases, arguments and variables, no fragments nor directives) into its root fields
+func ParseQuery(query string, variables map[string]any) ([]Field, error) {
+	p := &parser{
+		tokens:    nil,
+		variables: variables,
+	}
+	if err := p.tokenize(query); err != nil {
+		return nil, err
+	}
+
+	// optional operation type, name and variable definitions
+	if p.peek() == "query" {
+		p.next()
+		if p.peek() != "{" && p.peek() != "(" {
+			if _, err := p.name(); err != nil {
+				return nil, err
+			}
+		}
+		if p.peek() == "(" {
+			p.skipVariableDefinitions()
+		}
+	} else if p.peek() == "mutation" || p.peek() == "subscription" {
+		return nil, fmt.Errorf("only query operations are supported")
+	}
+
+	fields, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != "" {
+		return nil, fmt.Errorf("unexpected %q after the query", p.peek())
+	}
+	return fields, nil
+}
+
+type parser struct {
+	tokens    []string
+	pos       int
+	variables map[string]any
+}
+
+func (p *parser) tokenize(query string) error {
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ',':
+			i++
+		case r == '#': // comments run until the end of the line
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case strings.ContainsRune("{}():[]$!", r):
+			p.tokens = append(p.tokens, string(r))
+			i++
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				return fmt.Errorf("unterminated string in the query")
+			}
+			p.tokens = append(p.tokens, string(runes[i:j+1]))
+			i = j + 1
+		case r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			j := i + 1
+			for j < len(runes) && (runes[j] == '_' || runes[j] == '.' || unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			p.tokens = append(p.tokens, string(runes[i:j]))
+			i = j
+		default:
+			return fmt.Errorf("unexpected character %q in the query", r)
+		}
+	}
+	return nil
+}
+
+// peek returns the next token without consuming it, empty at the end of the query
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *parser) expect(token string) error {
+	if next := p.next(); next != token {
+		return fmt.Errorf("expected %q, found %q", token, next)
+	}
+	return nil
+}
+
+func (p *parser) name() (string, error) {
+	token := p.next()
+	if token == "" || !(token[0] == '_' || unicode.IsLetter(rune(token[0]))) {
+		return "", fmt.Errorf("expected a name, found %q", token)
+	}
+	return token, nil
+}
+
+// skipVariableDefinitions skips the definitions, the values are taken from the given variables
+func (p *parser) skipVariableDefinitions() {
+	for depth := 0; p.peek() != ""; {
+		switch p.next() {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth == 0 {
+			return
+		}
+	}
+}
+
+func (p *parser) selectionSet() ([]Field, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	fields := make([]Field, 0)
+	for p.peek() != "}" {
+		if p.peek() == "" {
+			return nil, fmt.Errorf("unterminated selection set")
+		}
+		field, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	p.next()
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty selection set")
+	}
+	return fields, nil
+}
+
+func (p *parser) field() (Field, error) {
+	name, err := p.name()
+	if err != nil {
+		return Field{}, err
+	}
+	field := Field{Alias: name, Name: name, Args: make(map[string]any)}
+	if p.peek() == ":" {
+		p.next()
+		if field.Name, err = p.name(); err != nil {
+			return Field{}, err
+		}
+	}
+	if p.peek() == "(" {
+		p.next()
+		for p.peek() != ")" {
+			argName, err := p.name()
+			if err != nil {
+				return Field{}, err
+			}
+			if err := p.expect(":"); err != nil {
+				return Field{}, err
+			}
+			if field.Args[argName], err = p.value(); err != nil {
+				return Field{}, err
+			}
+		}
+		p.next()
+	}
+	if p.peek() == "{" {
+		if field.Selection, err = p.selectionSet(); err != nil {
+			return Field{}, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) value() (any, error) {
+	token := p.next()
+	switch {
+	case token == "$":
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		value, ok := p.variables[name]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", name)
+		}
+		return value, nil
+	case token == "[":
+		list := make([]any, 0)
+		for p.peek() != "]" {
+			if p.peek() == "" {
+				return nil, fmt.Errorf("unterminated list")
+			}
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		p.next()
+		return list, nil
+	case strings.HasPrefix(token, "\""):
+		return strconv.Unquote(token)
+	case token == "true" || token == "false":
+		return token == "true", nil
+	case token == "null":
+		return nil, nil
+	}
+	if integer, err := strconv.ParseInt(token, 10, 64); err == nil {
+		return integer, nil
+	}
+	if float, err := strconv.ParseFloat(token, 64); err == nil {
+		return float, nil
+	}
+	return nil, fmt.Errorf("unexpected value %q", token)
+}
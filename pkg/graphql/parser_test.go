@@ -0,0 +1,76 @@
+package graphql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/graphql"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		result    []graphql.Field
+		err       bool
+	}{
+		{
+			name:  "shorthand",
+			query: "{ epochs(fromEpoch: 10, toEpoch: 20) { epoch numVals } }",
+			result: []graphql.Field{{
+				Alias: "epochs",
+				Name:  "epochs",
+				Args:  map[string]any{"fromEpoch": int64(10), "toEpoch": int64(20)},
+				Selection: []graphql.Field{
+					{Alias: "epoch", Name: "epoch", Args: map[string]any{}},
+					{Alias: "numVals", Name: "numVals", Args: map[string]any{}},
+				},
+			}},
+		},
+		{
+			name:      "named operation with variables and aliases",
+			query:     "query Pool($name: String!) {\n  lido: pools(name: $name, limit: 5) { rewards } # comment\n}",
+			variables: map[string]any{"name": "lido"},
+			result: []graphql.Field{{
+				Alias:     "lido",
+				Name:      "pools",
+				Args:      map[string]any{"name": "lido", "limit": int64(5)},
+				Selection: []graphql.Field{{Alias: "rewards", Name: "rewards", Args: map[string]any{}}},
+			}},
+		},
+		{
+			name:  "undefined variable",
+			query: "{ pools(name: $name) { rewards } }",
+			err:   true,
+		},
+		{
+			name:  "unterminated selection",
+			query: "{ epochs { epoch }",
+			err:   true,
+		},
+		{
+			name:  "mutation",
+			query: "mutation { epochs { epoch } }",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := graphql.ParseQuery(test.query, test.variables)
+			if test.err {
+				if err == nil {
+					t.Errorf("ParseQuery returned no error, expected one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQuery returned error: %s", err)
+			}
+			if !reflect.DeepEqual(result, test.result) {
+				t.Errorf("ParseQuery returned %+v, expected %+v", result, test.result)
+			}
+		})
+	}
+}
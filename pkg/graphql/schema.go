@@ -0,0 +1,332 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	DefaultPageSize int64 = 100
+	MaxPageSize     int64 = 1000
+)
+
+// filter is an argument of a root field, restricting the rows of its table
+type filter struct {
+	column   string
+	operator string // =, >= or <=
+	kind     string // Int or String
+}
+
+// objectType maps a GraphQL type to the table its rows are read from
+type objectType struct {
+	name    string
+	table   func(db Querier) string
+	order   string
+	fields  map[string]string // field name -> column
+	filters map[string]filter // argument name -> filter
+	sharded bool              // whether its rows are spread over the rewards shards
+
+	// sorting key of the ReplacingMergeTree tables that cannot be read with FINAL (i.e. views),
+	// so the duplicated rows not merged yet are returned once
+	unique string
+}
+
+var (
+	epochType = objectType{
+		name:  "Epoch",
+		table: func(Querier) string { return "t_epoch_metrics_summary FINAL" },
+		order: "f_epoch",
+		fields: map[string]string{
+			"epoch":                      "f_epoch",
+			"slot":                       "f_slot",
+			"timestamp":                  "f_timestamp",
+			"numAttestations":            "f_num_att",
+			"numAttestingVals":           "f_num_att_vals",
+			"numVals":                    "f_num_vals",
+			"numActiveVals":              "f_num_active_vals",
+			"numSlashedVals":             "f_num_slashed_vals",
+			"numExitedVals":              "f_num_exited_vals",
+			"totalBalance":               "f_total_balance_eth",
+			"totalEffectiveBalance":      "f_total_effective_balance_eth",
+			"attEffectiveBalance":        "f_att_effective_balance_eth",
+			"sourceAttEffectiveBalance":  "f_source_att_effective_balance_eth",
+			"targetAttEffectiveBalance":  "f_target_att_effective_balance_eth",
+			"headAttEffectiveBalance":    "f_head_att_effective_balance_eth",
+			"anyAttEffectiveBalance":     "f_any_att_effective_balance_eth",
+			"missingSource":              "f_missing_source",
+			"missingTarget":              "f_missing_target",
+			"missingHead":                "f_missing_head",
+			"syncCommitteeParticipation": "f_sync_committee_participation",
+			"depositsNum":                "f_deposits_num",
+			"withdrawalsNum":             "f_withdrawals_num",
+		},
+		filters: map[string]filter{
+			"epoch":     {column: "f_epoch", operator: "=", kind: "Int"},
+			"fromEpoch": {column: "f_epoch", operator: ">=", kind: "Int"},
+			"toEpoch":   {column: "f_epoch", operator: "<=", kind: "Int"},
+		},
+	}
+
+	blockType = objectType{
+		name:  "Block",
+		table: func(Querier) string { return "t_block_metrics FINAL" },
+		order: "f_slot",
+		fields: map[string]string{
+			"slot":           "f_slot",
+			"epoch":          "f_epoch",
+			"timestamp":      "f_timestamp",
+			"graffiti":       "f_graffiti",
+			"proposerIndex":  "f_proposer_index",
+			"proposed":       "f_proposed",
+			"attestations":   "f_attestations",
+			"deposits":       "f_deposits",
+			"voluntaryExits": "f_voluntary_exits",
+			"syncBits":       "f_sync_bits",
+			"feeRecipient":   "f_el_fee_recp",
+			"gasLimit":       "f_el_gas_limit",
+			"gasUsed":        "f_el_gas_used",
+			"baseFeePerGas":  "f_el_base_fee_per_gas",
+			"blockHash":      "f_el_block_hash",
+			"transactions":   "f_el_transactions",
+			"blockNumber":    "f_el_block_number",
+		},
+		filters: map[string]filter{
+			"slot":          {column: "f_slot", operator: "=", kind: "Int"},
+			"fromSlot":      {column: "f_slot", operator: ">=", kind: "Int"},
+			"toSlot":        {column: "f_slot", operator: "<=", kind: "Int"},
+			"epoch":         {column: "f_epoch", operator: "=", kind: "Int"},
+			"proposerIndex": {column: "f_proposer_index", operator: "=", kind: "Int"},
+		},
+	}
+
+	validatorType = objectType{
+		name:  "Validator",
+		table: func(Querier) string { return "t_validator_last_status" },
+		order: "f_val_idx",
+		fields: map[string]string{
+			"index":           "f_val_idx",
+			"epoch":           "f_epoch",
+			"balance":         "f_balance_eth",
+			"status":          "f_status",
+			"slashed":         "f_slashed",
+			"activationEpoch": "f_activation_epoch",
+			"withdrawalEpoch": "f_withdrawal_epoch",
+			"exitEpoch":       "f_exit_epoch",
+			"publicKey":       "f_public_key",
+		},
+		filters: map[string]filter{
+			"index":     {column: "f_val_idx", operator: "=", kind: "Int"},
+			"fromIndex": {column: "f_val_idx", operator: ">=", kind: "Int"},
+			"toIndex":   {column: "f_val_idx", operator: "<=", kind: "Int"},
+			"status":    {column: "f_status", operator: "=", kind: "Int"},
+		},
+	}
+
+	poolType = objectType{
+		name:  "Pool",
+		table: func(Querier) string { return "t_pool_summary FINAL" },
+		order: "f_pool_name, f_epoch",
+		fields: map[string]string{
+			"name":                  "f_pool_name",
+			"epoch":                 "f_epoch",
+			"rewards":               "aggregated_rewards",
+			"maxRewards":            "aggregated_max_rewards",
+			"syncCommittee":         "count_sync_committee",
+			"missingSource":         "count_missing_source",
+			"missingTarget":         "count_missing_target",
+			"missingHead":           "count_missing_head",
+			"expectedAttestations":  "count_expected_attestations",
+			"includedAttestations":  "count_attestations_included",
+			"proposedBlocks":        "proposed_blocks_performance",
+			"missedBlocks":          "missed_blocks_performance",
+			"activeVals":            "number_active_vals",
+			"avgInclusionDelay":     "avg_inclusion_delay",
+			"sourceAttBalanceShare": "source_att_balance_share",
+			"targetAttBalanceShare": "target_att_balance_share",
+			"headAttBalanceShare":   "head_att_balance_share",
+			"anyAttBalanceShare":    "any_att_balance_share",
+		},
+		filters: map[string]filter{
+			"name":      {column: "f_pool_name", operator: "=", kind: "String"},
+			"epoch":     {column: "f_epoch", operator: "=", kind: "Int"},
+			"fromEpoch": {column: "f_epoch", operator: ">=", kind: "Int"},
+			"toEpoch":   {column: "f_epoch", operator: "<=", kind: "Int"},
+		},
+	}
+
+	rewardType = objectType{
+		name:  "Reward",
+		table: func(db Querier) string { return db.RewardsSource() },
+		order: "f_val_idx, f_epoch",
+		fields: map[string]string{
+			"validator":           "f_val_idx",
+			"epoch":               "f_epoch",
+			"balance":             "f_balance_eth",
+			"reward":              "f_reward",
+			"maxReward":           "f_max_reward",
+			"maxAttReward":        "f_max_att_reward",
+			"maxSyncReward":       "f_max_sync_reward",
+			"baseReward":          "f_base_reward",
+			"attSlot":             "f_att_slot",
+			"attestationIncluded": "f_attestation_included",
+			"inclusionDelay":      "f_inclusion_delay",
+			"inSyncCommittee":     "f_in_sync_committee",
+			"missingSource":       "f_missing_source",
+			"missingTarget":       "f_missing_target",
+			"missingHead":         "f_missing_head",
+			"status":              "f_status",
+		},
+		filters: map[string]filter{
+			"validator": {column: "f_val_idx", operator: "=", kind: "Int"},
+			"epoch":     {column: "f_epoch", operator: "=", kind: "Int"},
+			"fromEpoch": {column: "f_epoch", operator: ">=", kind: "Int"},
+			"toEpoch":   {column: "f_epoch", operator: "<=", kind: "Int"},
+		},
+		sharded: true,
+		unique:  "f_val_idx, f_epoch",
+	}
+
+	churnType = objectType{
+		name:  "Churn",
+		table: func(Querier) string { return "t_epoch_churn FINAL" },
+		order: "f_epoch",
+		fields: map[string]string{
+			"epoch":                    "f_epoch",
+			"churnLimit":               "f_churn_limit",
+			"activationChurn":          "f_activation_churn",
+			"exitChurn":                "f_exit_churn",
+			"consolidationChurnLimit":  "f_consolidation_churn_limit",
+			"consolidationChurn":       "f_consolidation_churn",
+			"activationUtilization":    "f_activation_utilization",
+			"exitUtilization":          "f_exit_utilization",
+			"consolidationUtilization": "f_consolidation_utilization",
+			"pendingDeposits":          "f_pending_deposits",
+			"exitQueueEpochs":          "f_exit_queue_epochs",
+		},
+		filters: map[string]filter{
+			"epoch":     {column: "f_epoch", operator: "=", kind: "Int"},
+			"fromEpoch": {column: "f_epoch", operator: ">=", kind: "Int"},
+			"toEpoch":   {column: "f_epoch", operator: "<=", kind: "Int"},
+		},
+	}
+
+	// root fields of the query type, each one a page of rows of its type
+	queryFields = map[string]objectType{
+		"epochs":     epochType,
+		"blocks":     blockType,
+		"validators": validatorType,
+		"pools":      poolType,
+		"rewards":    rewardType,
+		"churn":      churnType,
+	}
+)
+
+// buildQuery returns the SQL query (and its arguments) of the given root field,
+// selecting only the columns of the requested fields, and the page of rows to return
+func buildQuery(db Querier, field Field) (string, []any, int64, int64, error) {
+	object, ok := queryFields[field.Name]
+	if !ok {
+		return "", nil, 0, 0, fmt.Errorf("unknown field %s on type Query", field.Name)
+	}
+	if len(field.Selection) == 0 {
+		return "", nil, 0, 0, fmt.Errorf("field %s of type [%s] must have a selection of subfields", field.Name, object.name)
+	}
+
+	columns := make([]string, 0, len(field.Selection))
+	for _, subfield := range field.Selection {
+		if len(subfield.Selection) > 0 || len(subfield.Args) > 0 {
+			return "", nil, 0, 0, fmt.Errorf("field %s on type %s is a scalar", subfield.Name, object.name)
+		}
+		if subfield.Name == "__typename" {
+			continue
+		}
+		column, ok := object.fields[subfield.Name]
+		if !ok {
+			return "", nil, 0, 0, fmt.Errorf("unknown field %s on type %s", subfield.Name, object.name)
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		columns = append(columns, "1")
+	}
+
+	limit, offset := DefaultPageSize, int64(0)
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+	// sorted, so the same query always builds the same SQL
+	names := make([]string, 0, len(field.Args))
+	for name := range field.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := field.Args[name]
+		switch name {
+		case "limit", "offset":
+			number, err := intArg(name, value)
+			if err != nil {
+				return "", nil, 0, 0, err
+			}
+			if number < 0 {
+				return "", nil, 0, 0, fmt.Errorf("argument %s must not be negative", name)
+			}
+			if name == "limit" {
+				limit = number
+			} else {
+				offset = number
+			}
+			continue
+		}
+
+		argFilter, ok := object.filters[name]
+		if !ok {
+			return "", nil, 0, 0, fmt.Errorf("unknown argument %s on field %s", name, field.Name)
+		}
+		if value == nil {
+			continue
+		}
+		switch argFilter.kind {
+		case "Int":
+			number, err := intArg(name, value)
+			if err != nil {
+				return "", nil, 0, 0, err
+			}
+			args = append(args, number)
+		case "String":
+			text, ok := value.(string)
+			if !ok {
+				return "", nil, 0, 0, fmt.Errorf("argument %s must be a String", name)
+			}
+			args = append(args, text)
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s $%d", argFilter.column, argFilter.operator, len(args)))
+	}
+	if limit > MaxPageSize {
+		return "", nil, 0, 0, fmt.Errorf("limit must not be greater than %d", MaxPageSize)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY %s",
+		strings.Join(columns, ", "), object.table(db), where, object.order)
+	if object.unique != "" {
+		query += fmt.Sprintf(" LIMIT 1 BY %s", object.unique)
+	}
+	return query, args, limit, offset, nil
+}
+
+// intArg accepts the integers of the query and the numbers of the JSON variables
+func intArg(name string, value any) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), nil
+		}
+	}
+	return 0, fmt.Errorf("argument %s must be an Int", name)
+}
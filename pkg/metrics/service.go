@@ -28,7 +28,9 @@ type PrometheusMetrics struct {
 	EndpointUrl     string
 	RefreshInterval time.Duration
 
-	Modules []*MetricsModule
+	Modules  []*MetricsModule
+	Handlers map[string]http.HandlerFunc // extra endpoints served next to the metrics (i.e. debug)
+	statsd   *StatsdExporter             // also pushes the metrics to statsd if set
 
 	wg     sync.WaitGroup
 	closeC chan struct{}
@@ -42,6 +44,7 @@ func NewPrometheusMetrics(ctx context.Context, ip string, port int) *PrometheusM
 		EndpointUrl:     EndpointUrl,
 		RefreshInterval: MetricLoopInterval,
 		Modules:         make([]*MetricsModule, 0),
+		Handlers:        make(map[string]http.HandlerFunc),
 		closeC:          make(chan struct{}),
 	}
 }
@@ -50,8 +53,16 @@ func (p *PrometheusMetrics) AddMeticsModule(newMod *MetricsModule) {
 	p.Modules = append(p.Modules, newMod)
 }
 
+// AddHandler serves the given handler at the given path, in the same port as the metrics
+func (p *PrometheusMetrics) AddHandler(path string, handler http.HandlerFunc) {
+	p.Handlers[path] = handler
+}
+
 func (p *PrometheusMetrics) Start() error {
 	http.Handle("/"+p.EndpointUrl, promhttp.Handler())
+	for path, handler := range p.Handlers {
+		http.HandleFunc(path, handler)
+	}
 	go func() {
 		log.Fatal(http.ListenAndServe(fmt.Sprintf("%s:%s", p.ExposedIp, p.ExposedPort), nil))
 	}()
@@ -101,6 +112,7 @@ metricsUpdateLoop:
 				logFields := log.Fields(modSum)
 				log.WithFields(logFields).Debugf("summary for %s", mod.Name())
 			}
+			p.pushStatsd()
 
 		case <-p.closeC:
 			log.Debug("detected a controled shutdown")
@@ -117,5 +129,8 @@ func (p *PrometheusMetrics) Close() {
 	log.Infof("closing %d prometheus metrics modules", len(p.Modules))
 	p.closeC <- struct{}{}
 	p.wg.Wait()
+	if p.statsd != nil {
+		p.statsd.Close()
+	}
 	log.Infof("prometheus metrics exporte successfully closed")
 }
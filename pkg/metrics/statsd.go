@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	statsdMaxPacket = 1432 // bytes of a UDP packet that do not fragment over ethernet
+)
+
+// StatsdExporter sends the prometheus metrics to a statsd agent, in the DogStatsD format (tags after #),
+// every time the metrics are updated. Gauges are sent as they are, counters as the increase since the
+// previous push, and histograms and summaries as their _sum and _count gauges
+type StatsdExporter struct {
+	conn     net.Conn
+	prefix   string
+	tags     []string // key:value
+	gatherer prometheus.Gatherer
+
+	counters map[string]float64 // last value of each counter series, to send increases
+}
+
+// ParseStatsdTags splits a comma separated list of key:value tags
+func ParseStatsdTags(input string) ([]string, error) {
+	tags := make([]string, 0)
+	for _, item := range strings.Split(input, ",") {
+		tag := strings.TrimSpace(item)
+		if tag == "" {
+			continue
+		}
+		if !strings.Contains(tag, ":") {
+			return nil, fmt.Errorf("statsd tag %q is not key:value", tag)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// NewStatsdExporter dials the statsd agent at address (host:port, UDP). The prefix is prepended to every
+// metric name, the tags (comma separated key:value) are added to the labels of every metric
+func NewStatsdExporter(address string, prefix string, tagList string) (*StatsdExporter, error) {
+	tags, err := ParseStatsdTags(tagList)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to dial the statsd agent")
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	return &StatsdExporter{
+		conn:     conn,
+		prefix:   prefix,
+		tags:     tags,
+		gatherer: prometheus.DefaultGatherer,
+		counters: make(map[string]float64),
+	}, nil
+}
+
+// Push sends the current value of every metric registered
+func (e *StatsdExporter) Push() error {
+	families, err := e.gatherer.Gather()
+	if err != nil {
+		return errors.Wrap(err, "unable to gather the metrics")
+	}
+	lines := make([]string, 0)
+	for _, family := range families {
+		lines = append(lines, e.lines(family)...)
+	}
+
+	packet := bytes.Buffer{}
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > statsdMaxPacket {
+			if _, err := e.conn.Write(packet.Bytes()); err != nil {
+				return errors.Wrap(err, "unable to send the metrics to statsd")
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	if packet.Len() > 0 {
+		if _, err := e.conn.Write(packet.Bytes()); err != nil {
+			return errors.Wrap(err, "unable to send the metrics to statsd")
+		}
+	}
+	return nil
+}
+
+// Timing sends a single measure in milliseconds right away, so that the statsd agent keeps its distribution
+// instead of the last value pushed. The fixed tags are added
+func (e *StatsdExporter) Timing(name string, ms float64) error {
+	tags := ""
+	if len(e.tags) > 0 {
+		tags = "|#" + strings.Join(e.tags, ",")
+	}
+	if _, err := e.conn.Write([]byte(statsdLine(e.prefix+name, ms, "ms", tags))); err != nil {
+		return errors.Wrap(err, "unable to send the timing to statsd")
+	}
+	return nil
+}
+
+func (e *StatsdExporter) Close() {
+	e.conn.Close()
+}
+
+// lines returns the statsd lines of a metric family, one per series
+func (e *StatsdExporter) lines(family *dto.MetricFamily) []string {
+	name := e.prefix + family.GetName()
+	lines := make([]string, 0, len(family.Metric))
+	for _, metric := range family.Metric {
+		tags := e.metricTags(metric)
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			key := name + tags
+			value := metric.GetCounter().GetValue()
+			increase := value - e.counters[key]
+			if increase < 0 { // restarted
+				increase = value
+			}
+			e.counters[key] = value
+			lines = append(lines, statsdLine(name, increase, "c", tags))
+		case dto.MetricType_GAUGE:
+			lines = append(lines, statsdLine(name, metric.GetGauge().GetValue(), "g", tags))
+		case dto.MetricType_UNTYPED:
+			lines = append(lines, statsdLine(name, metric.GetUntyped().GetValue(), "g", tags))
+		case dto.MetricType_HISTOGRAM:
+			lines = append(lines,
+				statsdLine(name+"_sum", metric.GetHistogram().GetSampleSum(), "g", tags),
+				statsdLine(name+"_count", float64(metric.GetHistogram().GetSampleCount()), "g", tags))
+		case dto.MetricType_SUMMARY:
+			lines = append(lines,
+				statsdLine(name+"_sum", metric.GetSummary().GetSampleSum(), "g", tags),
+				statsdLine(name+"_count", float64(metric.GetSummary().GetSampleCount()), "g", tags))
+		}
+	}
+	return lines
+}
+
+// metricTags returns the tags of the series, the fixed ones and its labels, as |#a:b,c:d
+func (e *StatsdExporter) metricTags(metric *dto.Metric) string {
+	tags := append([]string{}, e.tags...)
+	labels := make([]string, 0, len(metric.Label))
+	for _, label := range metric.Label {
+		labels = append(labels, label.GetName()+":"+label.GetValue())
+	}
+	sort.Strings(labels)
+	tags = append(tags, labels...)
+	if len(tags) == 0 {
+		return ""
+	}
+	return "|#" + strings.Join(tags, ",")
+}
+
+func statsdLine(name string, value float64, metricType string, tags string) string {
+	return fmt.Sprintf("%s:%g|%s%s", name, value, metricType, tags)
+}
+
+// SetStatsd pushes the metrics to the statsd exporter after every update of the modules
+func (p *PrometheusMetrics) SetStatsd(exporter *StatsdExporter) {
+	p.statsd = exporter
+}
+
+// Statsd returns the statsd exporter, nil if not set
+func (p *PrometheusMetrics) Statsd() *StatsdExporter {
+	if p == nil {
+		return nil
+	}
+	return p.statsd
+}
+
+func (p *PrometheusMetrics) pushStatsd() {
+	if p.statsd == nil {
+		return
+	}
+	if err := p.statsd.Push(); err != nil {
+		log.Warnf("could not push the metrics to statsd: %s", err.Error())
+	}
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestStatsdExporter(t *testing.T) {
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "head_lag_slots"})
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "equivocations"}, []string{"type"})
+	registry.MustRegister(gauge, counter)
+
+	exporter, err := NewStatsdExporter(agent.LocalAddr().String(), "goteth", "env:test, network:mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exporter.Close()
+	exporter.gatherer = registry
+
+	receive := func() []string {
+		buf := make([]byte, statsdMaxPacket)
+		agent.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := agent.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(string(buf[:n]), "\n")
+		sort.Strings(lines)
+		return lines
+	}
+	check := func(lines []string, expected []string) {
+		if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("statsd lines are %v, expected %v", lines, expected)
+		}
+	}
+
+	gauge.Set(3)
+	counter.WithLabelValues("double_vote").Add(2)
+	if err := exporter.Push(); err != nil {
+		t.Fatal(err)
+	}
+	check(receive(), []string{
+		"goteth.equivocations:2|c|#env:test,network:mainnet,type:double_vote",
+		"goteth.head_lag_slots:3|g|#env:test,network:mainnet",
+	})
+
+	// counters are sent as the increase since the previous push
+	counter.WithLabelValues("double_vote").Inc()
+	if err := exporter.Push(); err != nil {
+		t.Fatal(err)
+	}
+	check(receive(), []string{
+		"goteth.equivocations:1|c|#env:test,network:mainnet,type:double_vote",
+		"goteth.head_lag_slots:3|g|#env:test,network:mainnet",
+	})
+
+	// timings are sent right away, one per measure
+	if err := exporter.Timing("head_block_arrival_delay", 2350); err != nil {
+		t.Fatal(err)
+	}
+	check(receive(), []string{"goteth.head_block_arrival_delay:2350|ms|#env:test,network:mainnet"})
+
+	if _, err := NewStatsdExporter(agent.LocalAddr().String(), "", "env"); err == nil {
+		t.Errorf("expected an error for a tag without value")
+	}
+}
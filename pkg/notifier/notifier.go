@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "notifier"
+	log        = logrus.WithField(
+		"module", moduleName)
+
+	webhookTimeout = 10 * time.Second
+)
+
+// Kinds of the notifications emitted by the analyzer, outside of the rules
+const (
+	// an epoch transition stays in a stage for longer than the watchdog timeout
+	StuckEpochKind Kind = "stuck_epoch"
+	// a downloaded state does not match its root
+	StateProofKind Kind = "state_proof"
+	// the finality distance exceeds a threshold
+	FinalityStallKind Kind = "finality_stall"
+	// the monitored validators lose more than their max penalty, or are penalized all at once
+	BalanceDropKind Kind = "balance_drop"
+	// a slashable vote is detected
+	EquivocationKind Kind = "equivocation"
+	// the analyzer falls behind the chain head, or catches up again
+	HeadLagKind Kind = "head_lag"
+)
+
+// HighPriority marks the notifications that need an immediate action
+const HighPriority = "high"
+
+// Notification is emitted every time a rule is triggered
+type Notification struct {
+	Rule    string       `json:"rule"`
+	Kind    Kind         `json:"kind"`
+	Epoch   phase0.Epoch `json:"epoch"`
+	Message string       `json:"message"`
+
+	Priority string                  `json:"priority,omitempty"`
+	Indexes  []phase0.ValidatorIndex `json:"indexes,omitempty"` // validators the notification is about
+	Pools    []string                `json:"pools,omitempty"`
+}
+
+// Notifier logs the notifications and, if a webhook is configured, posts them as JSON
+type Notifier struct {
+	ctx        context.Context
+	webhookUrl string
+	client     *http.Client
+}
+
+func NewNotifier(ctx context.Context, webhookUrl string) *Notifier {
+	return &Notifier{
+		ctx:        ctx,
+		webhookUrl: webhookUrl,
+		client:     &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (n *Notifier) Notify(notification Notification) error {
+	if notification.Priority == HighPriority {
+		log.Errorf("rule %s triggered at epoch %d: %s", notification.Rule, notification.Epoch, notification.Message)
+	} else {
+		log.Warnf("rule %s triggered at epoch %d: %s", notification.Rule, notification.Epoch, notification.Message)
+	}
+	if n.webhookUrl == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, n.webhookUrl, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// Kind tells what emitted a notification, the rule kinds are the ones the rules file accepts
+type Kind string
+
+const (
+	// a validator does not get its attestation included for a number of epochs in a row
+	MissedAttestationsRule Kind = "missed_attestations"
+	// the efficiency (rewards / max rewards) of a pool stays below a threshold for a number of epochs
+	PoolEfficiencyRule Kind = "pool_efficiency"
+	// the target participation of the network, with the attestations included so far, stays below a threshold for a number of epochs
+	ParticipationRule Kind = "participation"
+)
+
+// Rule as written in the rules file
+type Rule struct {
+	Name       string                  `json:"name"`
+	Kind       Kind                    `json:"kind"`
+	Validators []phase0.ValidatorIndex `json:"validators,omitempty"` // missed_attestations
+	Pool       string                  `json:"pool,omitempty"`       // pool_efficiency
+	Threshold  float64                 `json:"threshold,omitempty"`  // pool_efficiency and participation, in %
+	Epochs     uint64                  `json:"epochs"`               // consecutive epochs for the rule to trigger
+}
+
+type RulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+func (r Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule without name")
+	}
+	if r.Epochs == 0 {
+		return fmt.Errorf("rule %s: epochs must be greater than 0", r.Name)
+	}
+	switch r.Kind {
+	case MissedAttestationsRule:
+		if len(r.Validators) == 0 {
+			return fmt.Errorf("rule %s: no validators provided", r.Name)
+		}
+	case PoolEfficiencyRule:
+		if r.Pool == "" {
+			return fmt.Errorf("rule %s: no pool provided", r.Name)
+		}
+		if r.Threshold <= 0 || r.Threshold > 100 {
+			return fmt.Errorf("rule %s: threshold must be a percentage", r.Name)
+		}
+	case ParticipationRule:
+		if r.Threshold <= 0 || r.Threshold > 100 {
+			return fmt.Errorf("rule %s: threshold must be a percentage", r.Name)
+		}
+	default:
+		return fmt.Errorf("rule %s: unknown kind %s", r.Name, r.Kind)
+	}
+	return nil
+}
+
+// ReadRulesFile parses and validates the rules in the given JSON file
+func ReadRulesFile(path string) ([]Rule, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file RulesFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("could not parse rules file %s: %s", path, err)
+	}
+	names := make(map[string]struct{}, len(file.Rules))
+	for _, rule := range file.Rules {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := names[rule.Name]; ok {
+			return nil, fmt.Errorf("rule %s defined twice", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+	}
+	return file.Rules, nil
+}
+
+// RulesEngine keeps the streaks of every rule across epochs,
+// a rule triggers once when its streak reaches the configured epochs and again only after recovering
+type RulesEngine struct {
+	mu          sync.Mutex // epochs may be processed concurrently
+	rules       []Rule
+	missStreaks []map[phase0.ValidatorIndex]uint64 // one per rule
+	poolStreaks []uint64                           // one per rule
+
+	participationStreaks []uint64 // one per rule
+}
+
+func NewRulesEngine(rules []Rule) *RulesEngine {
+	engine := &RulesEngine{
+		rules:       rules,
+		missStreaks: make([]map[phase0.ValidatorIndex]uint64, len(rules)),
+		poolStreaks: make([]uint64, len(rules)),
+
+		participationStreaks: make([]uint64, len(rules)),
+	}
+	for i := range rules {
+		engine.missStreaks[i] = make(map[phase0.ValidatorIndex]uint64)
+	}
+	return engine
+}
+
+// Validators returns the validators referenced by the missed attestations rules
+func (e *RulesEngine) Validators() []phase0.ValidatorIndex {
+	seen := make(map[phase0.ValidatorIndex]struct{})
+	validators := make([]phase0.ValidatorIndex, 0)
+	for _, rule := range e.rules {
+		for _, valIdx := range rule.Validators {
+			if _, ok := seen[valIdx]; ok {
+				continue
+			}
+			seen[valIdx] = struct{}{}
+			validators = append(validators, valIdx)
+		}
+	}
+	return validators
+}
+
+// HasPoolRules tells whether the pool efficiencies are needed
+func (e *RulesEngine) HasPoolRules() bool {
+	for _, rule := range e.rules {
+		if rule.Kind == PoolEfficiencyRule {
+			return true
+		}
+	}
+	return false
+}
+
+// HasParticipationRules tells whether the live participation is needed
+func (e *RulesEngine) HasParticipationRules() bool {
+	for _, rule := range e.rules {
+		if rule.Kind == ParticipationRule {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateRewards updates the missed attestations streaks with the rewards of an epoch,
+// only active validators are considered, the rest keep their streak
+func (e *RulesEngine) EvaluateRewards(epoch phase0.Epoch, rewards []spec.ValidatorRewards) []Notification {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	byIndex := make(map[phase0.ValidatorIndex]spec.ValidatorRewards, len(rewards))
+	for _, reward := range rewards {
+		byIndex[reward.ValidatorIndex] = reward
+	}
+
+	notifications := make([]Notification, 0)
+	for i, rule := range e.rules {
+		if rule.Kind != MissedAttestationsRule {
+			continue
+		}
+		for _, valIdx := range rule.Validators {
+			reward, ok := byIndex[valIdx]
+			if !ok || reward.Status != spec.ACTIVE_STATUS {
+				continue
+			}
+			if reward.AttestationIncluded {
+				e.missStreaks[i][valIdx] = 0
+				continue
+			}
+			e.missStreaks[i][valIdx]++
+			if e.missStreaks[i][valIdx] == rule.Epochs {
+				notifications = append(notifications, Notification{
+					Rule:    rule.Name,
+					Kind:    rule.Kind,
+					Epoch:   epoch,
+					Message: fmt.Sprintf("validator %d missed %d attestations in a row", valIdx, rule.Epochs),
+				})
+			}
+		}
+	}
+	return notifications
+}
+
+// EvaluatePools updates the pool efficiency streaks with the efficiencies (in %) of an epoch,
+// pools without summary at the epoch keep their streak
+func (e *RulesEngine) EvaluatePools(epoch phase0.Epoch, efficiencies map[string]float64) []Notification {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	notifications := make([]Notification, 0)
+	for i, rule := range e.rules {
+		if rule.Kind != PoolEfficiencyRule {
+			continue
+		}
+		efficiency, ok := efficiencies[rule.Pool]
+		if !ok {
+			continue
+		}
+		if efficiency >= rule.Threshold {
+			e.poolStreaks[i] = 0
+			continue
+		}
+		e.poolStreaks[i]++
+		if e.poolStreaks[i] == rule.Epochs {
+			notifications = append(notifications, Notification{
+				Rule:    rule.Name,
+				Kind:    rule.Kind,
+				Epoch:   epoch,
+				Message: fmt.Sprintf("pool %s efficiency below %.2f%% for %d epochs (last %.2f%%)", rule.Pool, rule.Threshold, rule.Epochs, efficiency),
+			})
+		}
+	}
+	return notifications
+}
+
+// EvaluateParticipation updates the participation streaks with the target participation (in %) of an epoch
+func (e *RulesEngine) EvaluateParticipation(epoch phase0.Epoch, participation float64) []Notification {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	notifications := make([]Notification, 0)
+	for i, rule := range e.rules {
+		if rule.Kind != ParticipationRule {
+			continue
+		}
+		if participation >= rule.Threshold {
+			e.participationStreaks[i] = 0
+			continue
+		}
+		e.participationStreaks[i]++
+		if e.participationStreaks[i] == rule.Epochs {
+			notifications = append(notifications, Notification{
+				Rule:    rule.Name,
+				Kind:    rule.Kind,
+				Epoch:   epoch,
+				Message: fmt.Sprintf("target participation below %.2f%% for %d epochs (last %.2f%%)", rule.Threshold, rule.Epochs, participation),
+			})
+		}
+	}
+	return notifications
+}
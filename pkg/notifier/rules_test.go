@@ -0,0 +1,97 @@
+package notifier_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/notifier"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestEvaluateRewards(t *testing.T) {
+	engine := notifier.NewRulesEngine([]notifier.Rule{
+		{Name: "missed", Kind: notifier.MissedAttestationsRule, Validators: []phase0.ValidatorIndex{1}, Epochs: 3},
+	})
+
+	tests := []struct {
+		name     string
+		included bool
+		status   spec.ValidatorStatus
+		result   int
+	}{
+		{name: "first miss", status: spec.ACTIVE_STATUS},
+		{name: "second miss", status: spec.ACTIVE_STATUS},
+		{name: "exited keeps streak", status: spec.EXIT_STATUS},
+		{name: "third miss", status: spec.ACTIVE_STATUS, result: 1},
+		{name: "fourth miss", status: spec.ACTIVE_STATUS},
+		{name: "included", included: true, status: spec.ACTIVE_STATUS},
+		{name: "miss after recovery", status: spec.ACTIVE_STATUS},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := engine.EvaluateRewards(phase0.Epoch(i), []spec.ValidatorRewards{
+				{ValidatorIndex: 1, AttestationIncluded: test.included, Status: test.status},
+				{ValidatorIndex: 2, Status: spec.ACTIVE_STATUS},
+			})
+			if len(result) != test.result {
+				t.Errorf("EvaluateRewards returned %d notifications, expected %d", len(result), test.result)
+			}
+		})
+	}
+}
+
+func TestEvaluatePools(t *testing.T) {
+	engine := notifier.NewRulesEngine([]notifier.Rule{
+		{Name: "efficiency", Kind: notifier.PoolEfficiencyRule, Pool: "pool", Threshold: 95, Epochs: 2},
+	})
+
+	tests := []struct {
+		name         string
+		efficiencies map[string]float64
+		result       int
+	}{
+		{name: "below", efficiencies: map[string]float64{"pool": 90}},
+		{name: "no summary", efficiencies: map[string]float64{"other": 10}},
+		{name: "below again", efficiencies: map[string]float64{"pool": 94.9}, result: 1},
+		{name: "still below", efficiencies: map[string]float64{"pool": 80}},
+		{name: "recovered", efficiencies: map[string]float64{"pool": 95}},
+		{name: "below after recovery", efficiencies: map[string]float64{"pool": 90}},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := engine.EvaluatePools(phase0.Epoch(i), test.efficiencies)
+			if len(result) != test.result {
+				t.Errorf("EvaluatePools returned %d notifications, expected %d", len(result), test.result)
+			}
+		})
+	}
+}
+
+func TestEvaluateParticipation(t *testing.T) {
+	engine := notifier.NewRulesEngine([]notifier.Rule{
+		{Name: "participation", Kind: notifier.ParticipationRule, Threshold: 80, Epochs: 2},
+	})
+
+	tests := []struct {
+		name          string
+		participation float64
+		result        int
+	}{
+		{name: "below", participation: 70},
+		{name: "below again", participation: 79.9, result: 1},
+		{name: "still below", participation: 50},
+		{name: "recovered", participation: 80},
+		{name: "below after recovery", participation: 60},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := engine.EvaluateParticipation(phase0.Epoch(i), test.participation)
+			if len(result) != test.result {
+				t.Errorf("EvaluateParticipation returned %d notifications, expected %d", len(result), test.result)
+			}
+		})
+	}
+}
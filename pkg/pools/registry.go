@@ -0,0 +1,105 @@
+package pools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// Registry merges the validator pools of several sources. It keeps the last content read from every source,
+// so a remote source that is unavailable during a refresh does not remove its validators
+type Registry struct {
+	m       sync.Mutex
+	sources []Source
+	loaded  map[string][]spec.PoolKey              // last content of every source, by location
+	current map[phase0.ValidatorIndex]spec.PoolKey // last merged pools
+}
+
+func NewRegistry(sources []Source) *Registry {
+	return &Registry{
+		sources: sources,
+		loaded:  make(map[string][]spec.PoolKey),
+		current: make(map[phase0.ValidatorIndex]spec.PoolKey),
+	}
+}
+
+// Remote returns whether any of the sources is remote, the only ones worth refreshing
+func (r *Registry) Remote() bool {
+	for _, source := range r.sources {
+		if source.Remote() {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads every source and merges them. It fails if a source was never read, otherwise the last content
+// of the sources that fail is used, and they are returned in failed
+func (r *Registry) Load(ctx context.Context) (failed []error, err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for _, source := range r.sources {
+		keys, err := source.Load(ctx)
+		if err != nil {
+			if _, ok := r.loaded[source.Location]; !ok {
+				return nil, fmt.Errorf("could not load pools source %s: %s", source.Location, err)
+			}
+			failed = append(failed, fmt.Errorf("could not refresh pools source %s, keeping the last content: %s", source.Location, err))
+			continue
+		}
+		r.loaded[source.Location] = keys
+	}
+	return failed, nil
+}
+
+// Restore sets the pools persisted by a previous run as the last merged ones, so the validators removed
+// from the sources while the analyzer was stopped are returned by the next Update
+func (r *Registry) Restore(keys []spec.PoolKey) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.current = make(map[phase0.ValidatorIndex]spec.PoolKey, len(keys))
+	for _, key := range keys {
+		r.current[key.ValIdx] = key
+	}
+}
+
+// Update merges the last content of the sources and returns the validators whose pool changed since the
+// previous call, along with the conflicts between sources. Validators no longer listed by any source
+// are returned as removed, with the pool they had
+func (r *Registry) Update() ([]spec.PoolKey, []Conflict) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	ordered := make([][]spec.PoolKey, 0, len(r.sources))
+	for _, source := range r.sources {
+		ordered = append(ordered, r.loaded[source.Location])
+	}
+	merged, conflicts := Merge(ordered)
+
+	next := make(map[phase0.ValidatorIndex]spec.PoolKey, len(merged))
+	changed := make([]spec.PoolKey, 0)
+	for _, key := range merged {
+		next[key.ValIdx] = key
+		prev, ok := r.current[key.ValIdx]
+		if !ok || prev.PoolName != key.PoolName {
+			changed = append(changed, key)
+		}
+	}
+	removed := make([]spec.PoolKey, 0)
+	for valIdx, prev := range r.current {
+		if _, ok := next[valIdx]; !ok {
+			prev.Removed = true
+			removed = append(removed, prev)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].ValIdx < removed[j].ValIdx })
+	changed = append(changed, removed...)
+	r.current = next
+	return changed, conflicts
+}
@@ -0,0 +1,145 @@
+package pools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+)
+
+var sourceTimeout = 30 * time.Second
+
+// Source is a custom validators file, local or served over HTTP(S), read with utils.ReadCustomValidators.
+// Every line is validator_index,pool_name, after an optional val_idx,custom_pool header
+type Source struct {
+	Location string
+}
+
+// ParseSources returns the comma separated sources, in order of precedence
+func ParseSources(input string) []Source {
+	sources := make([]Source, 0)
+	for _, item := range strings.Split(input, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			sources = append(sources, Source{Location: item})
+		}
+	}
+	return sources
+}
+
+// Remote returns whether the source is an HTTP(S) URL, which can change between loads
+func (s Source) Remote() bool {
+	return strings.HasPrefix(s.Location, "http://") || strings.HasPrefix(s.Location, "https://")
+}
+
+// Load reads the pool of every validator listed in the source
+func (s Source) Load(ctx context.Context) ([]spec.PoolKey, error) {
+	if !s.Remote() {
+		pools, err := utils.ReadCustomValidatorsFile(s.Location)
+		if err != nil {
+			return nil, err
+		}
+		return poolKeys(pools, s.Location), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sourceTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.Location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pools source %s returned status %d", s.Location, resp.StatusCode)
+	}
+	pools, err := utils.ReadCustomValidators(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return poolKeys(pools, s.Location), nil
+}
+
+// poolKeys flattens the pools read from a source
+func poolKeys(pools []utils.PoolKeys, source string) []spec.PoolKey {
+	keys := make([]spec.PoolKey, 0)
+	for _, pool := range pools {
+		for _, valIdx := range pool.ValIdxs {
+			keys = append(keys, spec.PoolKey{
+				ValIdx:   valIdx,
+				PoolName: pool.PoolName,
+				Source:   source,
+			})
+		}
+	}
+	return keys
+}
+
+// Conflict is a validator assigned to different pools by several sources
+type Conflict struct {
+	ValIdx phase0.ValidatorIndex
+	Kept   spec.PoolKey   // from the source with the highest precedence
+	Others []spec.PoolKey // dropped
+}
+
+func (c Conflict) String() string {
+	others := make([]string, 0, len(c.Others))
+	for _, other := range c.Others {
+		others = append(others, fmt.Sprintf("%s (%s)", other.PoolName, other.Source))
+	}
+	return fmt.Sprintf("validator %d is in pool %s (%s), ignoring %s",
+		c.ValIdx, c.Kept.PoolName, c.Kept.Source, strings.Join(others, ", "))
+}
+
+// Merge joins the entries of the sources, given in order of precedence: a validator listed in several sources
+// keeps the entry of the first one, and is reported as a conflict if the others assign it to a different pool.
+// Within a source the last entry of a validator wins
+func Merge(sources [][]spec.PoolKey) ([]spec.PoolKey, []Conflict) {
+	merged := make([]spec.PoolKey, 0)
+	positions := make(map[phase0.ValidatorIndex]int)
+	conflicts := make(map[phase0.ValidatorIndex]*Conflict)
+	conflictOrder := make([]phase0.ValidatorIndex, 0)
+
+	for _, keys := range sources {
+		seen := make(map[phase0.ValidatorIndex]bool, len(keys))
+		for _, key := range keys {
+			pos, ok := positions[key.ValIdx]
+			switch {
+			case !ok:
+				positions[key.ValIdx] = len(merged)
+				merged = append(merged, key)
+			case seen[key.ValIdx]: // repeated in the same source
+				merged[pos] = key
+			default: // listed by a source with higher precedence
+				kept := merged[pos]
+				if kept.PoolName == key.PoolName {
+					continue
+				}
+				conflict, ok := conflicts[key.ValIdx]
+				if !ok {
+					conflict = &Conflict{ValIdx: key.ValIdx, Kept: kept}
+					conflicts[key.ValIdx] = conflict
+					conflictOrder = append(conflictOrder, key.ValIdx)
+				}
+				conflict.Others = append(conflict.Others, key)
+				continue
+			}
+			seen[key.ValIdx] = true
+		}
+	}
+
+	result := make([]Conflict, 0, len(conflictOrder))
+	for _, valIdx := range conflictOrder {
+		conflict := conflicts[valIdx]
+		conflict.Kept = merged[positions[valIdx]]
+		result = append(result, *conflict)
+	}
+	return merged, result
+}
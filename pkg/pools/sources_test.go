@@ -0,0 +1,92 @@
+package pools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestSourceLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pools.csv")
+	if err := os.WriteFile(path, []byte("val_idx,custom_pool\n1,lido\n2,rocketpool\n3,lido\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := Source{Location: path}.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pools := make(map[phase0.ValidatorIndex]string)
+	for _, key := range keys {
+		if key.Source != path {
+			t.Fatalf("unexpected source %s", key.Source)
+		}
+		pools[key.ValIdx] = key.PoolName
+	}
+	if len(keys) != 3 || pools[1] != "lido" || pools[2] != "rocketpool" || pools[3] != "lido" {
+		t.Fatalf("unexpected keys %+v", keys)
+	}
+
+	if err := os.WriteFile(path, []byte("1,lido\nx,lido\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Source{Location: path}).Load(context.Background()); err == nil {
+		t.Fatal("expected an error for an invalid validator index")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	first := []spec.PoolKey{
+		{ValIdx: 1, PoolName: "lido", Source: "a"},
+		{ValIdx: 2, PoolName: "kiln", Source: "a"},
+		{ValIdx: 2, PoolName: "figment", Source: "a"}, // last entry wins
+	}
+	second := []spec.PoolKey{
+		{ValIdx: 1, PoolName: "coinbase", Source: "b"}, // conflict
+		{ValIdx: 2, PoolName: "figment", Source: "b"},  // same pool
+		{ValIdx: 3, PoolName: "rocketpool", Source: "b"},
+	}
+
+	merged, conflicts := Merge([][]spec.PoolKey{first, second})
+	pools := make(map[phase0.ValidatorIndex]string)
+	for _, key := range merged {
+		pools[key.ValIdx] = key.PoolName
+	}
+	if len(merged) != 3 || pools[1] != "lido" || pools[2] != "figment" || pools[3] != "rocketpool" {
+		t.Fatalf("unexpected merge %+v", merged)
+	}
+	if len(conflicts) != 1 || conflicts[0].ValIdx != 1 || conflicts[0].Others[0].PoolName != "coinbase" {
+		t.Fatalf("unexpected conflicts %+v", conflicts)
+	}
+}
+
+func TestRegistryUpdate(t *testing.T) {
+	registry := NewRegistry([]Source{{Location: "a"}})
+	registry.loaded["a"] = []spec.PoolKey{{ValIdx: 1, PoolName: "lido"}, {ValIdx: 2, PoolName: "kiln"}}
+	if changed, _ := registry.Update(); len(changed) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changed)
+	}
+
+	registry.loaded["a"] = []spec.PoolKey{{ValIdx: 1, PoolName: "lido"}}
+	changed, _ := registry.Update()
+	if len(changed) != 1 || changed[0].ValIdx != 2 || !changed[0].Removed || changed[0].PoolName != "kiln" {
+		t.Fatalf("expected validator 2 removed, got %+v", changed)
+	}
+	if changed, _ := registry.Update(); len(changed) != 0 {
+		t.Fatalf("expected no changes, got %+v", changed)
+	}
+}
+
+func TestRegistryRestore(t *testing.T) {
+	// validator 2 was removed from the sources while the analyzer was stopped
+	registry := NewRegistry([]Source{{Location: "a"}})
+	registry.Restore([]spec.PoolKey{{ValIdx: 1, PoolName: "lido"}, {ValIdx: 2, PoolName: "kiln"}})
+	registry.loaded["a"] = []spec.PoolKey{{ValIdx: 1, PoolName: "lido"}}
+	changed, _ := registry.Update()
+	if len(changed) != 1 || changed[0].ValIdx != 2 || !changed[0].Removed {
+		t.Fatalf("expected validator 2 removed, got %+v", changed)
+	}
+}
@@ -0,0 +1,124 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "sink"
+	log        = logrus.WithField(
+		"module", moduleName)
+
+	kafkaWriteTimeout = 30 * time.Second
+	kafkaBatchTimeout = 100 * time.Millisecond // messages of the same partition are sent together up to this delay
+	rewardsBatchSize  = 10000                  // validator rewards per kafka message
+)
+
+// KafkaTopics contains the topic where each kind of metric is published
+type KafkaTopics struct {
+	Epochs  string
+	Blocks  string
+	Rewards string
+}
+
+// KafkaSink publishes the processed metrics as JSON messages. Messages are batched and sent in the background,
+// so publishing does not hold the processing back; delivery errors are logged once the batch fails
+type KafkaSink struct {
+	ctx    context.Context
+	topics KafkaTopics
+	writer *kafka.Writer
+}
+
+func NewKafkaSink(ctx context.Context, brokers string, topics KafkaTopics) (*KafkaSink, error) {
+	brokerList := make([]string, 0)
+	for _, broker := range strings.Split(brokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokerList = append(brokerList, broker)
+		}
+	}
+	if len(brokerList) == 0 {
+		return nil, fmt.Errorf("no kafka brokers provided")
+	}
+
+	log.Infof("publishing metrics to kafka brokers %v", brokerList)
+	return &KafkaSink{
+		ctx:    ctx,
+		topics: topics,
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokerList...),
+			Balancer:               &kafka.Hash{}, // same key always goes to the same partition
+			AllowAutoTopicCreation: true,
+			WriteTimeout:           kafkaWriteTimeout,
+			BatchTimeout:           kafkaBatchTimeout,
+			Async:                  true,
+			Completion:             logDeliveryErrors,
+		},
+	}, nil
+}
+
+func (k *KafkaSink) PublishEpoch(epoch spec.Epoch) error {
+	return k.publish(k.topics.Epochs, uint64(epoch.Epoch), NewEpochRecord(epoch))
+}
+
+func (k *KafkaSink) PublishBlock(block spec.AgnosticBlock) error {
+	return k.publish(k.topics.Blocks, uint64(block.Slot), NewBlockRecord(block))
+}
+
+// PublishValidatorRewards splits the rewards in batches, one message per batch
+func (k *KafkaSink) PublishValidatorRewards(rewards []spec.ValidatorRewards) error {
+	for start := 0; start < len(rewards); start += rewardsBatchSize {
+		end := start + rewardsBatchSize
+		if end > len(rewards) {
+			end = len(rewards)
+		}
+		batch := rewards[start:end]
+		err := k.publish(k.topics.Rewards, uint64(batch[0].Epoch), NewRewardsRecord(uint64(batch[0].Epoch), batch))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logDeliveryErrors reports the batches the writer could not deliver, after its retries
+func logDeliveryErrors(messages []kafka.Message, err error) {
+	if err == nil || len(messages) == 0 {
+		return
+	}
+	log.Errorf("could not publish %d messages to topic %s: %s", len(messages), messages[0].Topic, err)
+}
+
+// publish queues the message, the delivery errors are logged by logDeliveryErrors
+func (k *KafkaSink) publish(topic string, key uint64, payload interface{}) error {
+	value, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not encode message for topic %s: %s", topic, err)
+	}
+
+	err = k.writer.WriteMessages(k.ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(strconv.FormatUint(key, 10)),
+		Value: value,
+		Headers: []kafka.Header{ // consumers can pick the decoder without parsing the value
+			{Key: "schema_version", Value: []byte(strconv.Itoa(SchemaVersion))},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not publish message to topic %s: %s", topic, err)
+	}
+	return nil
+}
+
+// Close sends the queued messages and waits for them to be delivered
+func (k *KafkaSink) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,220 @@
+package sink
+
+import (
+	"strings"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// SchemaVersion is the version of the JSON records published by the sinks, embedded in every record.
+// Increase it whenever a record changes, add its schema to schemas/ and the conversion from the previous
+// version to UpgradeRecord, so consumers can keep reading older records
+const SchemaVersion = 2
+
+// RecordKind identifies each kind of JSON record, and its schema
+type RecordKind string
+
+const (
+	EpochRecordKind   RecordKind = "epoch"
+	BlockRecordKind   RecordKind = "block"
+	RewardsRecordKind RecordKind = "validator_rewards"
+)
+
+var RecordKinds = []RecordKind{EpochRecordKind, BlockRecordKind, RewardsRecordKind}
+
+// EpochRecord is the record of every processed epoch
+type EpochRecord struct {
+	SchemaVersion              int     `json:"schema_version"`
+	Epoch                      uint64  `json:"epoch"`
+	Slot                       uint64  `json:"slot"`
+	Timestamp                  int64   `json:"timestamp"`
+	NumAttestations            int     `json:"num_att"`
+	NumAttValidators           int     `json:"num_att_vals"`
+	NumValidators              int     `json:"num_vals"`
+	TotalBalance               float32 `json:"total_balance_eth"`
+	AttEffectiveBalance        uint64  `json:"att_effective_balance_eth"`
+	SourceAttEffectiveBalance  uint64  `json:"source_att_effective_balance_eth"`
+	TargetAttEffectiveBalance  uint64  `json:"target_att_effective_balance_eth"`
+	HeadAttEffectiveBalance    uint64  `json:"head_att_effective_balance_eth"`
+	TotalEffectiveBalance      uint64  `json:"total_effective_balance_eth"`
+	MissingSource              int     `json:"missing_source"`
+	MissingTarget              int     `json:"missing_target"`
+	MissingHead                int     `json:"missing_head"`
+	NumSlashedVals             int     `json:"num_slashed_vals"`
+	NumActiveVals              int     `json:"num_active_vals"`
+	NumExitedVals              int     `json:"num_exited_vals"`
+	NumInActivationVals        int     `json:"num_in_activation_vals"`
+	SyncCommitteeParticipation uint64  `json:"sync_committee_participation"`
+	DepositsNum                int     `json:"deposits_num"`
+	TotalDepositsAmount        uint64  `json:"total_deposits_amount"`
+	WithdrawalsNum             int     `json:"withdrawals_num"`
+	TotalWithdrawalsAmount     uint64  `json:"total_withdrawals_amount"`
+	NewProposerSlashings       int     `json:"new_proposer_slashings"`
+	NewAttesterSlashings       int     `json:"new_attester_slashings"`
+	RewardsMedian              int64   `json:"rewards_median"`
+	RewardsP5                  int64   `json:"rewards_p5"`
+	RewardsP95                 int64   `json:"rewards_p95"`
+	RewardsGini                float64 `json:"rewards_gini"`
+	NumVotesSeen               int     `json:"num_votes_seen"`
+	VoteLatencyMean            float64 `json:"vote_latency_mean_ms"`
+	VoteLatencyP95             int64   `json:"vote_latency_p95_ms"`
+}
+
+func NewEpochRecord(epoch spec.Epoch) EpochRecord {
+	return EpochRecord{
+		SchemaVersion:              SchemaVersion,
+		Epoch:                      uint64(epoch.Epoch),
+		Slot:                       uint64(epoch.Slot),
+		Timestamp:                  epoch.Timestamp,
+		NumAttestations:            epoch.NumAttestations,
+		NumAttValidators:           epoch.NumAttValidators,
+		NumValidators:              epoch.NumValidators,
+		TotalBalance:               epoch.TotalBalance,
+		AttEffectiveBalance:        uint64(epoch.AttEffectiveBalance),
+		SourceAttEffectiveBalance:  uint64(epoch.SourceAttEffectiveBalance),
+		TargetAttEffectiveBalance:  uint64(epoch.TargetAttEffectiveBalance),
+		HeadAttEffectiveBalance:    uint64(epoch.HeadAttEffectiveBalance),
+		TotalEffectiveBalance:      uint64(epoch.TotalEffectiveBalance),
+		MissingSource:              epoch.MissingSource,
+		MissingTarget:              epoch.MissingTarget,
+		MissingHead:                epoch.MissingHead,
+		NumSlashedVals:             epoch.NumSlashedVals,
+		NumActiveVals:              epoch.NumActiveVals,
+		NumExitedVals:              epoch.NumExitedVals,
+		NumInActivationVals:        epoch.NumInActivationVals,
+		SyncCommitteeParticipation: epoch.SyncCommitteeParticipation,
+		DepositsNum:                epoch.DepositsNum,
+		TotalDepositsAmount:        uint64(epoch.TotalDepositsAmount),
+		WithdrawalsNum:             epoch.WithdrawalsNum,
+		TotalWithdrawalsAmount:     uint64(epoch.TotalWithdrawalsAmount),
+		NewProposerSlashings:       epoch.NewProposerSlashings,
+		NewAttesterSlashings:       epoch.NewAttesterSlashings,
+		RewardsMedian:              epoch.RewardsMedian,
+		RewardsP5:                  epoch.RewardsP5,
+		RewardsP95:                 epoch.RewardsP95,
+		RewardsGini:                epoch.RewardsGini,
+		NumVotesSeen:               epoch.NumVotesSeen,
+		VoteLatencyMean:            epoch.VoteLatencyMean,
+		VoteLatencyP95:             epoch.VoteLatencyP95,
+	}
+}
+
+// BlockRecord is the record of every processed slot
+type BlockRecord struct {
+	SchemaVersion     int    `json:"schema_version"`
+	Timestamp         uint64 `json:"timestamp"`
+	Epoch             uint64 `json:"epoch"`
+	Slot              uint64 `json:"slot"`
+	Graffiti          string `json:"graffiti"`
+	ProposerIndex     uint64 `json:"proposer_index"`
+	Proposed          bool   `json:"proposed"`
+	Attestations      int    `json:"attestations"`
+	Deposits          int    `json:"deposits"`
+	ProposerSlashings int    `json:"proposer_slashings"`
+	AttesterSlashings int    `json:"attester_slashings"`
+	VoluntaryExits    int    `json:"voluntary_exits"`
+	SyncBits          uint64 `json:"sync_bits"`
+	ELFeeRecipient    string `json:"el_fee_recp"`
+	ELGasLimit        uint64 `json:"el_gas_limit"`
+	ELGasUsed         uint64 `json:"el_gas_used"`
+	ELBaseFeePerGas   uint64 `json:"el_base_fee_per_gas"`
+	ELBlockHash       string `json:"el_block_hash"`
+	ELTransactions    int    `json:"el_transactions"`
+	ELBlockNumber     uint64 `json:"el_block_number"`
+}
+
+func NewBlockRecord(block spec.AgnosticBlock) BlockRecord {
+	graffiti := strings.ToValidUTF8(string(block.Graffiti[:]), "?")
+	graffiti = strings.ReplaceAll(graffiti, "\u0000", "")
+
+	return BlockRecord{
+		SchemaVersion:     SchemaVersion,
+		Timestamp:         block.ExecutionPayload.Timestamp,
+		Epoch:             uint64(block.Slot / spec.SlotsPerEpoch),
+		Slot:              uint64(block.Slot),
+		Graffiti:          graffiti,
+		ProposerIndex:     uint64(block.ProposerIndex),
+		Proposed:          block.Proposed,
+		Attestations:      block.NumAttestations(),
+		Deposits:          len(block.Deposits),
+		ProposerSlashings: len(block.ProposerSlashings),
+		AttesterSlashings: len(block.AttesterSlashings),
+		VoluntaryExits:    len(block.VoluntaryExits),
+		SyncBits:          block.SyncAggregate.SyncCommitteeBits.Count(),
+		ELFeeRecipient:    block.ExecutionPayload.FeeRecipient.String(),
+		ELGasLimit:        block.ExecutionPayload.GasLimit,
+		ELGasUsed:         block.ExecutionPayload.GasUsed,
+		ELBaseFeePerGas:   block.ExecutionPayload.BaseFeePerGas,
+		ELBlockHash:       block.ExecutionPayload.BlockHash.String(),
+		ELTransactions:    len(block.ExecutionPayload.Transactions),
+		ELBlockNumber:     block.ExecutionPayload.BlockNumber,
+	}
+}
+
+// ValidatorRewardsRecord is the record of the rewards of a validator in an epoch
+type ValidatorRewardsRecord struct {
+	ValidatorIndex       uint64  `json:"val_idx"`
+	Epoch                uint64  `json:"epoch"`
+	BalanceEth           float32 `json:"balance_eth"`
+	Reward               int64   `json:"reward"`
+	MaxReward            uint64  `json:"max_reward"`
+	AttestationReward    uint64  `json:"max_att_reward"`
+	SyncCommitteeReward  uint64  `json:"max_sync_reward"`
+	BaseReward           uint64  `json:"base_reward"`
+	AttSlot              uint64  `json:"att_slot"`
+	AttestationIncluded  bool    `json:"attestation_included"`
+	InSyncCommittee      bool    `json:"in_sync_committee"`
+	ProposerSlot         uint64  `json:"proposer_slot"`
+	ProposerApiReward    uint64  `json:"proposer_api_reward"`
+	ProposerManualReward uint64  `json:"proposer_manual_reward"`
+	MissingSource        bool    `json:"missing_source"`
+	MissingTarget        bool    `json:"missing_target"`
+	MissingHead          bool    `json:"missing_head"`
+	Status               uint8   `json:"status"`
+	InclusionDelay       int     `json:"inclusion_delay"`
+	RewardPercentile     float32 `json:"reward_percentile"`
+}
+
+func NewValidatorRewardsRecord(rewards spec.ValidatorRewards) ValidatorRewardsRecord {
+	return ValidatorRewardsRecord{
+		ValidatorIndex:       uint64(rewards.ValidatorIndex),
+		Epoch:                uint64(rewards.Epoch),
+		BalanceEth:           rewards.BalanceToEth(),
+		Reward:               rewards.Reward,
+		MaxReward:            uint64(rewards.MaxReward),
+		AttestationReward:    uint64(rewards.AttestationReward),
+		SyncCommitteeReward:  uint64(rewards.SyncCommitteeReward),
+		BaseReward:           uint64(rewards.BaseReward),
+		AttSlot:              uint64(rewards.AttSlot),
+		AttestationIncluded:  rewards.AttestationIncluded,
+		InSyncCommittee:      rewards.InSyncCommittee,
+		ProposerSlot:         uint64(rewards.ProposerSlot),
+		ProposerApiReward:    uint64(rewards.ProposerApiReward),
+		ProposerManualReward: uint64(rewards.ProposerManualReward),
+		MissingSource:        rewards.MissingSource,
+		MissingTarget:        rewards.MissingTarget,
+		MissingHead:          rewards.MissingHead,
+		Status:               uint8(rewards.Status),
+		InclusionDelay:       rewards.InclusionDelay,
+		RewardPercentile:     rewards.RewardPercentile,
+	}
+}
+
+// RewardsRecord is a batch of validator rewards of the same epoch
+type RewardsRecord struct {
+	SchemaVersion int                      `json:"schema_version"`
+	Epoch         uint64                   `json:"epoch"`
+	Rewards       []ValidatorRewardsRecord `json:"rewards"`
+}
+
+func NewRewardsRecord(epoch uint64, rewards []spec.ValidatorRewards) RewardsRecord {
+	record := RewardsRecord{
+		SchemaVersion: SchemaVersion,
+		Epoch:         epoch,
+		Rewards:       make([]ValidatorRewardsRecord, 0, len(rewards)),
+	}
+	for _, item := range rewards {
+		record.Rewards = append(record.Rewards, NewValidatorRewardsRecord(item))
+	}
+	return record
+}
@@ -0,0 +1,115 @@
+package sink
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	//go:embed schemas
+	schemaFiles embed.FS
+
+	// upgrades converts a record of the given version to the next one
+	upgrades = map[int]func(kind RecordKind, data []byte) (interface{}, error){
+		1: upgradeFromV1,
+	}
+)
+
+// Schema returns the JSON schema of the kind of record for the given version
+func Schema(kind RecordKind, version int) ([]byte, error) {
+	schema, err := schemaFiles.ReadFile(fmt.Sprintf("schemas/v%d/%s.json", version, kind))
+	if err != nil {
+		return nil, fmt.Errorf("no schema for %s records version %d", kind, version)
+	}
+	return schema, nil
+}
+
+// RecordVersion returns the schema version embedded in the record.
+// Version 1 records had it under "version"
+func RecordVersion(data []byte) (int, error) {
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+		Version       int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, fmt.Errorf("could not decode record: %s", err)
+	}
+	if header.SchemaVersion != 0 {
+		return header.SchemaVersion, nil
+	}
+	if header.Version != 0 {
+		return header.Version, nil
+	}
+	return 0, fmt.Errorf("the record has no schema version")
+}
+
+// UpgradeRecord converts a JSON record of any previous schema version to the current one (SchemaVersion).
+// Records already in the current version are returned as they are
+func UpgradeRecord(kind RecordKind, data []byte) ([]byte, error) {
+	version, err := RecordVersion(data)
+	if err != nil {
+		return nil, err
+	}
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("record version %d is newer than the supported one (%d)", version, SchemaVersion)
+	}
+
+	for ; version < SchemaVersion; version++ {
+		upgrade, ok := upgrades[version]
+		if !ok {
+			return nil, fmt.Errorf("no conversion from version %d", version)
+		}
+		record, err := upgrade(kind, data)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert %s record from version %d: %s", kind, version, err)
+		}
+		data, err = json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// Version 1 records embedded the internal structs for epochs and validator rewards
+type epochRecordV1 struct {
+	Epoch spec.Epoch `json:"epoch"`
+}
+
+type rewardsRecordV1 struct {
+	Epoch   uint64                  `json:"epoch"`
+	Rewards []spec.ValidatorRewards `json:"rewards"`
+}
+
+func upgradeFromV1(kind RecordKind, data []byte) (interface{}, error) {
+	switch kind {
+	case EpochRecordKind:
+		var record epochRecordV1
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+		upgraded := NewEpochRecord(record.Epoch)
+		upgraded.SchemaVersion = 2
+		return upgraded, nil
+	case BlockRecordKind: // same fields, only the version was renamed
+		var record BlockRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+		record.SchemaVersion = 2
+		return record, nil
+	case RewardsRecordKind:
+		var record rewardsRecordV1
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+		upgraded := NewRewardsRecord(record.Epoch, record.Rewards)
+		upgraded.SchemaVersion = 2
+		return upgraded, nil
+	default:
+		return nil, fmt.Errorf("unknown record kind %s", kind)
+	}
+}
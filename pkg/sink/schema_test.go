@@ -0,0 +1,78 @@
+package sink_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/sink"
+)
+
+func TestUpgradeRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    sink.RecordKind
+		record  string
+		field   string
+		value   float64
+		invalid bool
+	}{
+		{
+			name:   "Epoch v1",
+			kind:   sink.EpochRecordKind,
+			record: `{"version":1,"epoch":{"Epoch":"100","Slot":"3200","NumActiveVals":5}}`,
+			field:  "num_active_vals",
+			value:  5,
+		},
+		{
+			name:   "Block v1",
+			kind:   sink.BlockRecordKind,
+			record: `{"version":1,"slot":3200,"proposer_index":7}`,
+			field:  "proposer_index",
+			value:  7,
+		},
+		{
+			name:   "Epoch v2",
+			kind:   sink.EpochRecordKind,
+			record: `{"schema_version":2,"epoch":100,"num_active_vals":5}`,
+			field:  "num_active_vals",
+			value:  5,
+		},
+		{
+			name:    "Unversioned",
+			kind:    sink.EpochRecordKind,
+			record:  `{"epoch":100}`,
+			invalid: true,
+		},
+		{
+			name:    "Newer version",
+			kind:    sink.EpochRecordKind,
+			record:  `{"schema_version":99,"epoch":100}`,
+			invalid: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			upgraded, err := sink.UpgradeRecord(test.kind, []byte(test.record))
+			if test.invalid {
+				if err == nil {
+					t.Errorf("UpgradeRecord returned no error, expected one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpgradeRecord returned error: %s", err)
+			}
+			var fields map[string]interface{}
+			if err := json.Unmarshal(upgraded, &fields); err != nil {
+				t.Fatalf("could not decode upgraded record: %s", err)
+			}
+			if fields["schema_version"] != float64(sink.SchemaVersion) {
+				t.Errorf("schema_version returned %v, expected %d", fields["schema_version"], sink.SchemaVersion)
+			}
+			if fields[test.field] != test.value {
+				t.Errorf("%s returned %v, expected %f", test.field, fields[test.field], test.value)
+			}
+		})
+	}
+}
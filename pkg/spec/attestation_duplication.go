@@ -0,0 +1,81 @@
+package spec
+
+import (
+	"bytes"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// attestationAggregate is an aggregate included in a block, committees is only set since Electra
+type attestationAggregate struct {
+	data            *phase0.AttestationData
+	committees      []byte
+	aggregationBits bitfield.Bitlist
+}
+
+// RedundantAttestations returns the number of aggregates of the block whose votes are all included by
+// another aggregate of the same data and committees, which only waste block space.
+// Of several identical aggregates, all but the first are redundant
+func (p AgnosticBlock) RedundantAttestations() int {
+	aggregates := p.attestationAggregates()
+	redundant := 0
+	for i, aggregate := range aggregates {
+		for j, other := range aggregates {
+			if i == j || !sameAttestationData(aggregate.data, other.data) || !bytes.Equal(aggregate.committees, other.committees) {
+				continue
+			}
+			contains, err := other.aggregationBits.Contains(aggregate.aggregationBits)
+			if err != nil || !contains {
+				continue // different lengths or votes not in the other aggregate
+			}
+			if other.aggregationBits.Count() > aggregate.aggregationBits.Count() || j < i {
+				redundant++
+				break
+			}
+		}
+	}
+	return redundant
+}
+
+// attestationAggregates returns the aggregates included in the block, as broadcast (before expanding the Electra ones)
+func (p AgnosticBlock) attestationAggregates() []attestationAggregate {
+	aggregates := make([]attestationAggregate, 0, p.NumAttestations())
+	if len(p.ElectraAttestations) > 0 {
+		for _, attestation := range p.ElectraAttestations {
+			aggregates = append(aggregates, attestationAggregate{
+				data:            attestation.Data,
+				committees:      attestation.CommitteeBits.Bytes(),
+				aggregationBits: attestation.AggregationBits,
+			})
+		}
+		return aggregates
+	}
+	for _, attestation := range p.Attestations {
+		aggregates = append(aggregates, attestationAggregate{
+			data:            attestation.Data,
+			aggregationBits: attestation.AggregationBits,
+		})
+	}
+	return aggregates
+}
+
+// AttestationDuplicationRatio returns the share of the aggregates of the block that are redundant
+func (p AgnosticBlock) AttestationDuplicationRatio() float64 {
+	numAttestations := p.NumAttestations()
+	if numAttestations == 0 {
+		return 0
+	}
+	return float64(p.RedundantAttestations()) / float64(numAttestations)
+}
+
+func sameAttestationData(a, b *phase0.AttestationData) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Slot == b.Slot &&
+		a.Index == b.Index &&
+		a.BeaconBlockRoot == b.BeaconBlockRoot &&
+		a.Source.Epoch == b.Source.Epoch && a.Source.Root == b.Source.Root &&
+		a.Target.Epoch == b.Target.Epoch && a.Target.Root == b.Target.Root
+}
@@ -0,0 +1,67 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestRedundantAttestations(t *testing.T) {
+	bits := func(length uint64, set ...uint64) bitfield.Bitlist {
+		aggregationBits := bitfield.NewBitlist(length)
+		for _, i := range set {
+			aggregationBits.SetBitAt(i, true)
+		}
+		return aggregationBits
+	}
+	data := func(slot phase0.Slot, index phase0.CommitteeIndex) *phase0.AttestationData {
+		return &phase0.AttestationData{
+			Slot:   slot,
+			Index:  index,
+			Source: &phase0.Checkpoint{},
+			Target: &phase0.Checkpoint{},
+		}
+	}
+
+	block := spec.AgnosticBlock{
+		Attestations: []*phase0.Attestation{
+			{Data: data(10, 0), AggregationBits: bits(8, 0, 1, 2)},
+			{Data: data(10, 0), AggregationBits: bits(8, 1, 2)},    // subset of the first
+			{Data: data(10, 0), AggregationBits: bits(8, 2, 3)},    // new vote
+			{Data: data(10, 1), AggregationBits: bits(8, 1)},       // other committee
+			{Data: data(10, 0), AggregationBits: bits(8, 2, 3)},    // same as the third
+			{Data: data(11, 0), AggregationBits: bits(8, 0, 1, 2)}, // other slot
+		},
+	}
+	if result := block.RedundantAttestations(); result != 2 {
+		t.Errorf("RedundantAttestations returned %d, expected %d", result, 2)
+	}
+	if result := block.AttestationDuplicationRatio(); result != float64(2)/6 {
+		t.Errorf("AttestationDuplicationRatio returned %f, expected %f", result, float64(2)/6)
+	}
+
+	committees := func(set ...uint64) bitfield.Bitvector64 {
+		committeeBits := bitfield.NewBitvector64()
+		for _, i := range set {
+			committeeBits.SetBitAt(i, true)
+		}
+		return committeeBits
+	}
+	electraBlock := spec.AgnosticBlock{
+		ElectraAttestations: []*electra.Attestation{
+			{Data: data(10, 0), CommitteeBits: committees(0, 1), AggregationBits: bits(16, 0, 9)},
+			{Data: data(10, 0), CommitteeBits: committees(0, 1), AggregationBits: bits(16, 9)}, // subset of the first
+			{Data: data(10, 0), CommitteeBits: committees(0, 2), AggregationBits: bits(16, 9)}, // other committees
+		},
+	}
+	if result := electraBlock.RedundantAttestations(); result != 1 {
+		t.Errorf("RedundantAttestations of the electra block returned %d, expected %d", result, 1)
+	}
+
+	if result := (spec.AgnosticBlock{}).AttestationDuplicationRatio(); result != 0 {
+		t.Errorf("AttestationDuplicationRatio of an empty block returned %f, expected %f", result, float64(0))
+	}
+}
@@ -0,0 +1,70 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// AttestationInclusion is the first block including the vote of a validator, with the proposer of that block,
+// to tell which proposers include the votes of other validators promptly
+type AttestationInclusion struct {
+	ValidatorIndex phase0.ValidatorIndex
+	AttSlot        phase0.Slot // slot of the vote
+	Slot           phase0.Slot // slot of the block including it
+	ProposerIndex  phase0.ValidatorIndex
+	InclusionDelay int // slots between the vote and its inclusion
+}
+
+type inclusionKey struct {
+	valIdx  phase0.ValidatorIndex
+	attSlot phase0.Slot
+}
+
+// NewAttestationInclusions returns the first inclusion of the votes of the monitored validators in the blocks.
+// Votes already included in the previous blocks (i.e. the ones of the previous epoch) are not returned again
+func NewAttestationInclusions(
+	prevBlocks []*AgnosticBlock,
+	blocks []*AgnosticBlock,
+	committee func(phase0.Slot, phase0.CommitteeIndex) []phase0.ValidatorIndex,
+	monitored func(phase0.ValidatorIndex) bool) []AttestationInclusion {
+
+	included := make(map[inclusionKey]struct{})
+	visit := func(block *AgnosticBlock, fn func(key inclusionKey)) {
+		if !block.Proposed {
+			return
+		}
+		for _, attestation := range block.Attestations {
+			for i, valIdx := range committee(attestation.Data.Slot, attestation.Data.Index) {
+				if !monitored(valIdx) || !attestation.AggregationBits.BitAt(uint64(i)) {
+					continue
+				}
+				key := inclusionKey{valIdx: valIdx, attSlot: attestation.Data.Slot}
+				if _, ok := included[key]; ok {
+					continue
+				}
+				included[key] = struct{}{}
+				fn(key)
+			}
+		}
+	}
+
+	for _, block := range prevBlocks {
+		visit(block, func(inclusionKey) {})
+	}
+	inclusions := make([]AttestationInclusion, 0)
+	for _, block := range blocks {
+		visit(block, func(key inclusionKey) {
+			inclusions = append(inclusions, AttestationInclusion{
+				ValidatorIndex: key.valIdx,
+				AttSlot:        key.attSlot,
+				Slot:           block.Slot,
+				ProposerIndex:  block.ProposerIndex,
+				InclusionDelay: int(block.Slot - key.attSlot),
+			})
+		})
+	}
+	return inclusions
+}
+
+func (f AttestationInclusion) Type() ModelType {
+	return AttestationInclusionModel
+}
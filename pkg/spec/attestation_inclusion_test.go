@@ -0,0 +1,71 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestNewAttestationInclusions(t *testing.T) {
+	bits := func(length uint64, set ...uint64) bitfield.Bitlist {
+		aggregationBits := bitfield.NewBitlist(length)
+		for _, i := range set {
+			aggregationBits.SetBitAt(i, true)
+		}
+		return aggregationBits
+	}
+	attestation := func(slot phase0.Slot, aggregationBits bitfield.Bitlist) *phase0.Attestation {
+		return &phase0.Attestation{
+			Data: &phase0.AttestationData{
+				Slot:   slot,
+				Source: &phase0.Checkpoint{},
+				Target: &phase0.Checkpoint{},
+			},
+			AggregationBits: aggregationBits,
+		}
+	}
+	committee := func(slot phase0.Slot, index phase0.CommitteeIndex) []phase0.ValidatorIndex {
+		base := phase0.ValidatorIndex(slot) * 10
+		return []phase0.ValidatorIndex{base, base + 1, base + 2, base + 3}
+	}
+	monitored := func(valIdx phase0.ValidatorIndex) bool {
+		return valIdx%10 < 2 // first two validators of every committee
+	}
+
+	prevBlocks := []*spec.AgnosticBlock{
+		{Slot: 31, ProposerIndex: 7, Proposed: true, Attestations: []*phase0.Attestation{
+			attestation(30, bits(4, 0)), // vote of 300 already included
+		}},
+	}
+	blocks := []*spec.AgnosticBlock{
+		{Slot: 32, ProposerIndex: 5, Proposed: true, Attestations: []*phase0.Attestation{
+			attestation(30, bits(4, 0, 1, 2)), // 301 included late, 302 not monitored
+			attestation(31, bits(4, 1)),
+		}},
+		{Slot: 33, ProposerIndex: 6, Proposed: false, Attestations: []*phase0.Attestation{
+			attestation(32, bits(4, 0)), // missed block
+		}},
+		{Slot: 34, ProposerIndex: 8, Proposed: true, Attestations: []*phase0.Attestation{
+			attestation(31, bits(4, 0, 1)), // 311 included again
+			attestation(33, bits(4, 1)),
+		}},
+	}
+
+	expected := []spec.AttestationInclusion{
+		{ValidatorIndex: 301, AttSlot: 30, Slot: 32, ProposerIndex: 5, InclusionDelay: 2},
+		{ValidatorIndex: 311, AttSlot: 31, Slot: 32, ProposerIndex: 5, InclusionDelay: 1},
+		{ValidatorIndex: 310, AttSlot: 31, Slot: 34, ProposerIndex: 8, InclusionDelay: 3},
+		{ValidatorIndex: 331, AttSlot: 33, Slot: 34, ProposerIndex: 8, InclusionDelay: 1},
+	}
+	result := spec.NewAttestationInclusions(prevBlocks, blocks, committee, monitored)
+	if len(result) != len(expected) {
+		t.Fatalf("NewAttestationInclusions returned %d inclusions, expected %d: %v", len(result), len(expected), result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("inclusion %d is %+v, expected %+v", i, result[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package spec
+
+import "github.com/attestantio/go-eth2-client/spec/phase0"
+
+// CountAttestationTargets counts the aggregates of the block whose target is the canonical checkpoint of their
+// target epoch, given by checkpointRoot, and the ones voting for another checkpoint. The source is not checked:
+// blocks can only include attestations whose source is the justified checkpoint of the state.
+// Aggregates whose checkpoint is unknown (zero root) are not counted
+func (p *AgnosticBlock) CountAttestationTargets(checkpointRoot func(epoch phase0.Epoch) phase0.Root) {
+	p.CorrectTargetAttestations, p.WrongTargetAttestations = 0, 0
+	for _, aggregate := range p.attestationAggregates() {
+		if aggregate.data == nil {
+			continue
+		}
+		root := checkpointRoot(aggregate.data.Target.Epoch)
+		if root == (phase0.Root{}) {
+			continue
+		}
+		if aggregate.data.Target.Root == root {
+			p.CorrectTargetAttestations++
+		} else {
+			p.WrongTargetAttestations++
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestCountAttestationTargets(t *testing.T) {
+	data := func(epoch phase0.Epoch, root byte) *phase0.AttestationData {
+		return &phase0.AttestationData{
+			Source: &phase0.Checkpoint{},
+			Target: &phase0.Checkpoint{Epoch: epoch, Root: phase0.Root{root}},
+		}
+	}
+	checkpoints := map[phase0.Epoch]phase0.Root{
+		9:  {1},
+		10: {2},
+	}
+
+	block := spec.AgnosticBlock{
+		Attestations: []*phase0.Attestation{
+			{Data: data(10, 2)}, // canonical
+			{Data: data(9, 1)},  // canonical, previous epoch
+			{Data: data(10, 3)}, // other checkpoint
+			{Data: data(10, 2)},
+			{Data: data(8, 1)}, // unknown checkpoint
+		},
+	}
+	block.CountAttestationTargets(func(epoch phase0.Epoch) phase0.Root { return checkpoints[epoch] })
+	if block.CorrectTargetAttestations != 3 {
+		t.Errorf("CountAttestationTargets returned %d correct targets, expected %d", block.CorrectTargetAttestations, 3)
+	}
+	if block.WrongTargetAttestations != 1 {
+		t.Errorf("CountAttestationTargets returned %d wrong targets, expected %d", block.WrongTargetAttestations, 1)
+	}
+}
@@ -0,0 +1,111 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// InactivityConfig holds the values of the network the inactivity penalties are computed with
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/beacon-chain.md#inactivity-penalties
+type InactivityConfig struct {
+	ScoreBias             uint64
+	PenaltyQuotientAltair uint64
+	PenaltyQuotient       uint64 // since Bellatrix
+}
+
+// DefaultInactivityConfig is the inactivity config of mainnet
+var DefaultInactivityConfig = InactivityConfig{
+	ScoreBias:             4,
+	PenaltyQuotientAltair: 3 * (1 << 24),
+	PenaltyQuotient:       1 << 24,
+}
+
+// NewInactivityConfigFromSpec reads the inactivity config from the node's spec (/eth/v1/config/spec)
+// missing or malformed values fall back to the default ones
+func NewInactivityConfigFromSpec(nodeSpec map[string]any) InactivityConfig {
+	config := DefaultInactivityConfig
+
+	if value, ok := specUint(nodeSpec["INACTIVITY_SCORE_BIAS"]); ok && value > 0 {
+		config.ScoreBias = value
+	}
+	if value, ok := specUint(nodeSpec["INACTIVITY_PENALTY_QUOTIENT_ALTAIR"]); ok && value > 0 {
+		config.PenaltyQuotientAltair = value
+	}
+	if value, ok := specUint(nodeSpec["INACTIVITY_PENALTY_QUOTIENT_BELLATRIX"]); ok && value > 0 {
+		config.PenaltyQuotient = value
+	}
+
+	return config
+}
+
+// penaltyQuotient returns the inactivity penalty quotient of the given fork
+func (c InactivityConfig) penaltyQuotient(version spec.DataVersion) uint64 {
+	if version == spec.DataVersionAltair {
+		return c.PenaltyQuotientAltair
+	}
+	return c.PenaltyQuotient
+}
+
+// BalanceDrop is a validator whose balance dropped more than it can be penalized in an epoch
+type BalanceDrop struct {
+	ValidatorIndex phase0.ValidatorIndex
+	Drop           phase0.Gwei
+	MaxPenalty     phase0.Gwei
+}
+
+// MaxEpochPenalty returns the most a validator can lose in an epoch without being slashed, since Altair:
+// the source and target penalties of a missed attestation, the sync committee slots and the inactivity
+// penalty of its inactivity score, which is applied to every validator that misses the target
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/beacon-chain.md#get_flag_index_deltas
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/beacon-chain.md#inactivity-penalty-deltas
+func MaxEpochPenalty(reward ValidatorRewards, weights RewardWeights, effectiveBalance phase0.Gwei, inactivityScore uint64, inactivity InactivityConfig, version spec.DataVersion) phase0.Gwei {
+	penalty := phase0.Gwei(0)
+	if weights.Denominator > 0 {
+		penalty += reward.BaseReward * phase0.Gwei(weights.TimelySource+weights.TimelyTarget) / phase0.Gwei(weights.Denominator)
+	}
+	if reward.InSyncCommittee {
+		penalty += reward.SyncCommitteeReward // a missed slot costs as much as it rewards
+	}
+	if quotient := inactivity.ScoreBias * inactivity.penaltyQuotient(version); quotient > 0 {
+		penalty += phase0.Gwei(uint64(effectiveBalance) * inactivityScore / quotient)
+	}
+	return penalty
+}
+
+// NewBalanceDrops returns the validators whose epoch reward is a loss greater than their max penalty,
+// i.e. a slashing. Withdrawals and deposits are already left out of the rewards
+func NewBalanceDrops(rewards []ValidatorRewards, maxPenalty func(ValidatorRewards) phase0.Gwei) []BalanceDrop {
+	drops := make([]BalanceDrop, 0)
+	for _, reward := range rewards {
+		if reward.Reward >= 0 {
+			continue
+		}
+		drop := phase0.Gwei(-reward.Reward)
+		penalty := maxPenalty(reward)
+		if drop <= penalty {
+			continue
+		}
+		drops = append(drops, BalanceDrop{
+			ValidatorIndex: reward.ValidatorIndex,
+			Drop:           drop,
+			MaxPenalty:     penalty,
+		})
+	}
+	return drops
+}
+
+// OfflineValidators returns the active validators that missed both the source and target votes of the epoch,
+// i.e. their attestation was not included, and the number of active ones. A large share of them offline at once
+// points to a mass offline event, unlike a negative reward, which a late vote or a missed sync committee slot also give
+func OfflineValidators(rewards []ValidatorRewards) (offline []phase0.ValidatorIndex, active int) {
+	for _, reward := range rewards {
+		if reward.Status != ACTIVE_STATUS {
+			continue
+		}
+		active++
+		if reward.MissingSource && reward.MissingTarget {
+			offline = append(offline, reward.ValidatorIndex)
+		}
+	}
+	return offline, active
+}
@@ -0,0 +1,75 @@
+package spec_test
+
+import (
+	"testing"
+
+	eth2spec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestMaxEpochPenalty(t *testing.T) {
+	reward := spec.ValidatorRewards{BaseReward: 6400}
+	if penalty := spec.MaxEpochPenalty(reward, spec.DefaultRewardWeights, 32*spec.EffectiveBalanceInc, 0, spec.DefaultInactivityConfig, eth2spec.DataVersionDeneb); penalty != 4000 {
+		t.Fatalf("expected the source and target penalties, got %d", penalty)
+	}
+
+	reward.InSyncCommittee = true
+	reward.SyncCommitteeReward = 1000
+	if penalty := spec.MaxEpochPenalty(reward, spec.DefaultRewardWeights, 32*spec.EffectiveBalanceInc, 0, spec.DefaultInactivityConfig, eth2spec.DataVersionDeneb); penalty != 5000 {
+		t.Fatalf("expected the sync committee penalty added, got %d", penalty)
+	}
+
+	// inactivity score of 100: 32 ETH * 100 / (4 * 2^24)
+	if penalty := spec.MaxEpochPenalty(reward, spec.DefaultRewardWeights, 32*spec.EffectiveBalanceInc, 100, spec.DefaultInactivityConfig, eth2spec.DataVersionDeneb); penalty != 5000+47683 {
+		t.Fatalf("expected the inactivity penalty added, got %d", penalty)
+	}
+
+	// the Altair quotient is 3 times larger: 32 ETH * 100 / (4 * 3 * 2^24)
+	if penalty := spec.MaxEpochPenalty(reward, spec.DefaultRewardWeights, 32*spec.EffectiveBalanceInc, 100, spec.DefaultInactivityConfig, eth2spec.DataVersionAltair); penalty != 5000+15894 {
+		t.Fatalf("expected the Altair inactivity penalty added, got %d", penalty)
+	}
+}
+
+func TestNewInactivityConfigFromSpec(t *testing.T) {
+	config := spec.NewInactivityConfigFromSpec(map[string]any{
+		"INACTIVITY_SCORE_BIAS":                 uint64(8),
+		"INACTIVITY_PENALTY_QUOTIENT_ALTAIR":    "malformed",
+		"INACTIVITY_PENALTY_QUOTIENT_BELLATRIX": uint64(1 << 23),
+	})
+	expected := spec.InactivityConfig{ScoreBias: 8, PenaltyQuotientAltair: 3 * (1 << 24), PenaltyQuotient: 1 << 23}
+	if config != expected {
+		t.Fatalf("NewInactivityConfigFromSpec returned %+v, expected %+v", config, expected)
+	}
+
+	// inactivity score of 100: 32 ETH * 100 / (8 * 2^23)
+	reward := spec.ValidatorRewards{BaseReward: 6400}
+	if penalty := spec.MaxEpochPenalty(reward, spec.DefaultRewardWeights, 32*spec.EffectiveBalanceInc, 100, config, eth2spec.DataVersionDeneb); penalty != 4000+47683 {
+		t.Fatalf("expected the inactivity penalty of the node config, got %d", penalty)
+	}
+}
+
+func TestNewBalanceDrops(t *testing.T) {
+	rewards := []spec.ValidatorRewards{
+		{ValidatorIndex: 1, Status: spec.ACTIVE_STATUS, Reward: 100},
+		{ValidatorIndex: 2, Status: spec.ACTIVE_STATUS, Reward: -3000},
+		{ValidatorIndex: 3, Status: spec.SLASHED_STATUS, Reward: -1000000000},
+		{ValidatorIndex: 4, Status: spec.EXIT_STATUS, Reward: 0},
+	}
+	maxPenalty := func(spec.ValidatorRewards) phase0.Gwei { return 4000 }
+
+	drops := spec.NewBalanceDrops(rewards, maxPenalty)
+	if len(drops) != 1 || drops[0].ValidatorIndex != 3 || drops[0].Drop != 1000000000 || drops[0].MaxPenalty != 4000 {
+		t.Fatalf("expected only the drop of validator 3, got %+v", drops)
+	}
+
+	offline, active := spec.OfflineValidators([]spec.ValidatorRewards{
+		{ValidatorIndex: 1, Status: spec.ACTIVE_STATUS, Reward: 100},
+		{ValidatorIndex: 2, Status: spec.ACTIVE_STATUS, Reward: -3000, MissingTarget: true}, // late vote
+		{ValidatorIndex: 3, Status: spec.ACTIVE_STATUS, Reward: -4000, MissingSource: true, MissingTarget: true},
+		{ValidatorIndex: 4, Status: spec.EXIT_STATUS, MissingSource: true, MissingTarget: true},
+	})
+	if len(offline) != 1 || offline[0] != 3 || active != 3 {
+		t.Fatalf("expected validator 3 offline out of 3 active, got %v %d", offline, active)
+	}
+}
@@ -0,0 +1,93 @@
+package spec
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// BlobScheduleEntry is the max blobs per block from an epoch on, and the blobs per block the blob base fee targets
+type BlobScheduleEntry struct {
+	Epoch       phase0.Epoch
+	MaxBlobs    uint64
+	TargetBlobs uint64
+}
+
+// BlobSchedule holds the blob limits of the network, in ascending epochs
+type BlobSchedule []BlobScheduleEntry
+
+// DefaultBlobSchedule holds the blob limits of mainnet since Deneb
+var DefaultBlobSchedule = BlobSchedule{
+	{Epoch: 269568, MaxBlobs: 6, TargetBlobs: 3}, // Deneb
+	{Epoch: 364032, MaxBlobs: 9, TargetBlobs: 6}, // Electra
+}
+
+// NewBlobScheduleFromSpec reads the blob limits from the node's spec (/eth/v1/config/spec): MAX_BLOBS_PER_BLOCK
+// since Deneb, MAX_BLOBS_PER_BLOCK_ELECTRA since Electra and the BLOB_SCHEDULE entries since Fulu. The consensus
+// spec has no target since Deneb, unless the node provides it the target is half the max in Deneb and 2/3 of it after,
+// as the execution layer sets them. Without any limits in the spec, the default schedule is used
+func NewBlobScheduleFromSpec(nodeSpec map[string]any) BlobSchedule {
+	schedule := make(BlobSchedule, 0)
+	addFork := func(forkKey string, maxKey string, targetKey string, targetRatio func(uint64) uint64) {
+		epoch, okEpoch := nodeSpec[forkKey].(uint64)
+		maxBlobs, okMax := nodeSpec[maxKey].(uint64)
+		if !okEpoch || !okMax {
+			return
+		}
+		target, ok := nodeSpec[targetKey].(uint64)
+		if !ok {
+			target = targetRatio(maxBlobs)
+		}
+		schedule = append(schedule, BlobScheduleEntry{Epoch: phase0.Epoch(epoch), MaxBlobs: maxBlobs, TargetBlobs: target})
+	}
+	addFork("DENEB_FORK_EPOCH", "MAX_BLOBS_PER_BLOCK", "TARGET_BLOBS_PER_BLOCK", func(max uint64) uint64 { return max / 2 })
+	addFork("ELECTRA_FORK_EPOCH", "MAX_BLOBS_PER_BLOCK_ELECTRA", "TARGET_BLOBS_PER_BLOCK_ELECTRA", func(max uint64) uint64 { return max * 2 / 3 })
+
+	if entries, ok := nodeSpec["BLOB_SCHEDULE"].([]any); ok {
+		for _, item := range entries {
+			entry, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			epoch, okEpoch := specUint(entry["EPOCH"])
+			maxBlobs, okMax := specUint(entry["MAX_BLOBS_PER_BLOCK"])
+			if okEpoch && okMax {
+				schedule = append(schedule, BlobScheduleEntry{Epoch: phase0.Epoch(epoch), MaxBlobs: maxBlobs, TargetBlobs: maxBlobs * 2 / 3})
+			}
+		}
+	}
+
+	if len(schedule) == 0 {
+		return DefaultBlobSchedule
+	}
+	sort.SliceStable(schedule, func(i, j int) bool { return schedule[i].Epoch < schedule[j].Epoch })
+	return schedule
+}
+
+// specUint reads a number of the spec, given as a number or as a decimal string
+func specUint(value any) (uint64, bool) {
+	switch v := value.(type) {
+	case uint64:
+		return v, true
+	case float64:
+		return uint64(v), true
+	case string:
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// At returns the blob limits that apply at the given epoch, zero before Deneb
+func (s BlobSchedule) At(epoch phase0.Epoch) BlobScheduleEntry {
+	current := BlobScheduleEntry{}
+	for _, entry := range s {
+		if entry.Epoch > epoch {
+			break
+		}
+		current = entry
+	}
+	return current
+}
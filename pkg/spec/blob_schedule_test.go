@@ -0,0 +1,84 @@
+package spec_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestNewBlobScheduleFromSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodeSpec map[string]any
+		expected spec.BlobSchedule
+	}{
+		{
+			name:     "No blob limits",
+			nodeSpec: map[string]any{"SLOTS_PER_EPOCH": uint64(32)},
+			expected: spec.DefaultBlobSchedule,
+		},
+		{
+			name: "Gnosis limits",
+			nodeSpec: map[string]any{
+				"DENEB_FORK_EPOCH":            uint64(889856),
+				"MAX_BLOBS_PER_BLOCK":         uint64(2),
+				"ELECTRA_FORK_EPOCH":          uint64(1337856),
+				"MAX_BLOBS_PER_BLOCK_ELECTRA": uint64(2),
+			},
+			expected: spec.BlobSchedule{
+				{Epoch: 889856, MaxBlobs: 2, TargetBlobs: 1},
+				{Epoch: 1337856, MaxBlobs: 2, TargetBlobs: 1},
+			},
+		},
+		{
+			name: "Explicit target and blob schedule",
+			nodeSpec: map[string]any{
+				"DENEB_FORK_EPOCH":               uint64(10),
+				"MAX_BLOBS_PER_BLOCK":            uint64(6),
+				"ELECTRA_FORK_EPOCH":             uint64(20),
+				"MAX_BLOBS_PER_BLOCK_ELECTRA":    uint64(9),
+				"TARGET_BLOBS_PER_BLOCK_ELECTRA": uint64(5),
+				"BLOB_SCHEDULE": []any{
+					map[string]any{"EPOCH": "40", "MAX_BLOBS_PER_BLOCK": "21"},
+					map[string]any{"EPOCH": "30", "MAX_BLOBS_PER_BLOCK": "15"},
+					"malformed",
+				},
+			},
+			expected: spec.BlobSchedule{
+				{Epoch: 10, MaxBlobs: 6, TargetBlobs: 3},
+				{Epoch: 20, MaxBlobs: 9, TargetBlobs: 5},
+				{Epoch: 30, MaxBlobs: 15, TargetBlobs: 10},
+				{Epoch: 40, MaxBlobs: 21, TargetBlobs: 14},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule := spec.NewBlobScheduleFromSpec(test.nodeSpec)
+			if !reflect.DeepEqual(schedule, test.expected) {
+				t.Errorf("NewBlobScheduleFromSpec returned %+v, expected %+v", schedule, test.expected)
+			}
+		})
+	}
+}
+
+func TestBlobScheduleAt(t *testing.T) {
+	for _, test := range []struct {
+		epoch  phase0.Epoch
+		target uint64
+	}{
+		{epoch: 0, target: 0},
+		{epoch: 269567, target: 0},
+		{epoch: 269568, target: 3},
+		{epoch: 364031, target: 3},
+		{epoch: 364032, target: 6},
+		{epoch: 500000, target: 6},
+	} {
+		if target := spec.DefaultBlobSchedule.At(test.epoch).TargetBlobs; target != test.target {
+			t.Errorf("target at epoch %d is %d, expected %d", test.epoch, target, test.target)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package spec
+
+// BlobThroughput is the blob space used by the blocks of an epoch against the target of the fork
+type BlobThroughput struct {
+	BlobsNum         int     // blobs included in the blocks of the epoch
+	AvgBlobsPerBlock float64 // over the proposed blocks
+	BlobGasUsed      uint64
+	TargetBlobGas    uint64 // target blob gas of every proposed block, missed slots do not count
+}
+
+// Utilization returns the blob gas used over the target, in %. Over 100 the blob base fee rises
+func (b BlobThroughput) Utilization() float64 {
+	if b.TargetBlobGas == 0 {
+		return 0
+	}
+	return float64(b.BlobGasUsed) / float64(b.TargetBlobGas) * 100
+}
+
+// BlobThroughput adds up the blobs included in the blocks of the state epoch, against the target of the schedule
+// at the epoch. Blocks without body (epochs-only mode) carry no blobs, so it needs the block metrics
+func (p AgnosticState) BlobThroughput(schedule BlobSchedule) BlobThroughput {
+	throughput := BlobThroughput{}
+	proposed := 0
+	for _, block := range p.Blocks {
+		if !block.Proposed {
+			continue
+		}
+		proposed++
+		throughput.BlobsNum += block.BlobsNum
+		throughput.BlobGasUsed += block.ExecutionPayload.BlobGasUsed
+	}
+	if proposed > 0 {
+		throughput.AvgBlobsPerBlock = float64(throughput.BlobsNum) / float64(proposed)
+	}
+	throughput.TargetBlobGas = uint64(proposed) * schedule.At(p.Epoch).TargetBlobs * GasPerBlob
+	return throughput
+}
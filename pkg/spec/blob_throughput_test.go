@@ -0,0 +1,45 @@
+package spec_test
+
+import (
+	"testing"
+
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestBlobThroughput(t *testing.T) {
+	block := func(proposed bool, blobs int) *local_spec.AgnosticBlock {
+		return &local_spec.AgnosticBlock{
+			Proposed: proposed,
+			BlobsNum: blobs,
+			ExecutionPayload: local_spec.AgnosticExecutionPayload{
+				BlobGasUsed: uint64(blobs) * local_spec.GasPerBlob,
+			},
+		}
+	}
+	schedule := local_spec.BlobSchedule{
+		{Epoch: 100, MaxBlobs: 6, TargetBlobs: 3},
+		{Epoch: 200, MaxBlobs: 9, TargetBlobs: 6},
+	}
+	state := local_spec.AgnosticState{
+		Epoch:  150,
+		Blocks: []*local_spec.AgnosticBlock{block(true, 6), block(true, 0), block(false, 0), block(true, 3)},
+	}
+
+	throughput := state.BlobThroughput(schedule)
+	if throughput.BlobsNum != 9 || throughput.AvgBlobsPerBlock != 3 {
+		t.Fatalf("expected 9 blobs, 3 per block, got %+v", throughput)
+	}
+	if throughput.TargetBlobGas != 9*local_spec.GasPerBlob || throughput.Utilization() != 100 {
+		t.Fatalf("expected the target of 3 blocks fully used, got %+v", throughput)
+	}
+
+	state.Epoch = 200
+	if utilization := state.BlobThroughput(schedule).Utilization(); utilization != 50 {
+		t.Fatalf("expected 50%% of the second target, got %f", utilization)
+	}
+
+	state.Epoch = 99
+	if utilization := state.BlobThroughput(schedule).Utilization(); utilization != 0 {
+		t.Fatalf("expected no target before the schedule, got %f", utilization)
+	}
+}
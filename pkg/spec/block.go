@@ -3,12 +3,14 @@ package spec
 import (
 	"encoding/binary"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/altair"
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
 	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/electra"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/migalabs/goteth/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -39,6 +41,20 @@ type AgnosticBlock struct {
 	CompressionTime       time.Duration
 	DecompressionTime     time.Duration
 	ManualReward          phase0.Gwei
+
+	ElectraAttestations   []*electra.Attestation          // aggregate several committees, see ExpandElectraAttestations
+	ConsolidationRequests []*electra.ConsolidationRequest // execution layer requests (since Electra)
+	DepositRequests       []*electra.DepositRequest
+	WithdrawalRequests    []*electra.WithdrawalRequest // full exits (amount 0) and partial withdrawals
+
+	RandaoReveal phase0.BLSSignature // mixed into the randao of the state
+
+	BlobsNum int // KZG commitments of the blobs included (since Deneb)
+
+	CorrectTargetAttestations uint64 // aggregates voting for the canonical checkpoint of their target epoch, see CountAttestationTargets
+	WrongTargetAttestations   uint64 // aggregates voting for any other checkpoint
+
+	expandOnce *sync.Once // guards the expansion of the Electra attestations
 }
 
 // This Wrapper is meant to include all common objects across Ethereum Hard Fork Specs
@@ -54,6 +70,8 @@ type AgnosticExecutionPayload struct {
 	BlockNumber          uint64
 	Withdrawals          []*capella.Withdrawal
 	PayloadSize          uint32
+	BlobGasUsed          uint64 // since Deneb
+	ExcessBlobGas        uint64
 }
 
 func (f AgnosticBlock) Type() ModelType {
@@ -80,6 +98,15 @@ func (p AgnosticBlock) BlockGasFees() (uint64, uint64, error) {
 
 }
 
+// NumAttestations returns the number of attestations included in the block.
+// Since Electra one attestation aggregates several committees
+func (p AgnosticBlock) NumAttestations() int {
+	if len(p.ElectraAttestations) > 0 {
+		return len(p.ElectraAttestations)
+	}
+	return len(p.Attestations)
+}
+
 func GetCustomBlock(block spec.VersionedSignedBeaconBlock) (AgnosticBlock, error) {
 	switch block.Version {
 	case spec.DataVersionPhase0:
@@ -92,6 +119,8 @@ func GetCustomBlock(block spec.VersionedSignedBeaconBlock) (AgnosticBlock, error
 		return NewCapellaBlock(block), nil
 	case spec.DataVersionDeneb:
 		return NewDenebBlock(block), nil
+	case spec.DataVersionElectra:
+		return NewElectraBlock(block), nil
 	default:
 		return AgnosticBlock{}, fmt.Errorf("could not figure out the Beacon Block Fork Version: %s", block.Version)
 	}
@@ -115,6 +144,7 @@ func NewPhase0Block(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 		Root:              root,
 		ProposerIndex:     block.Phase0.Message.ProposerIndex,
 		Graffiti:          block.Phase0.Message.Body.Graffiti,
+		RandaoReveal:      block.Phase0.Message.Body.RANDAOReveal,
 		Proposed:          true,
 		Attestations:      block.Phase0.Message.Body.Attestations,
 		Deposits:          block.Phase0.Message.Body.Deposits,
@@ -157,6 +187,7 @@ func NewAltairBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 		ParentRoot:        block.Altair.Message.ParentRoot,
 		ProposerIndex:     block.Altair.Message.ProposerIndex,
 		Graffiti:          block.Altair.Message.Body.Graffiti,
+		RandaoReveal:      block.Altair.Message.Body.RANDAOReveal,
 		Proposed:          true,
 		Attestations:      block.Altair.Message.Body.Attestations,
 		Deposits:          block.Altair.Message.Body.Deposits,
@@ -199,6 +230,7 @@ func NewBellatrixBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 		ParentRoot:        block.Bellatrix.Message.ParentRoot,
 		ProposerIndex:     block.Bellatrix.Message.ProposerIndex,
 		Graffiti:          block.Bellatrix.Message.Body.Graffiti,
+		RandaoReveal:      block.Bellatrix.Message.Body.RANDAOReveal,
 		Proposed:          true,
 		Attestations:      block.Bellatrix.Message.Body.Attestations,
 		Deposits:          block.Bellatrix.Message.Body.Deposits,
@@ -241,6 +273,7 @@ func NewCapellaBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 		ParentRoot:        block.Capella.Message.ParentRoot,
 		ProposerIndex:     block.Capella.Message.ProposerIndex,
 		Graffiti:          block.Capella.Message.Body.Graffiti,
+		RandaoReveal:      block.Capella.Message.Body.RANDAOReveal,
 		Proposed:          true,
 		Attestations:      block.Capella.Message.Body.Attestations,
 		Deposits:          block.Capella.Message.Body.Deposits,
@@ -284,6 +317,7 @@ func NewDenebBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 		ParentRoot:        block.Deneb.Message.ParentRoot,
 		ProposerIndex:     block.Deneb.Message.ProposerIndex,
 		Graffiti:          block.Deneb.Message.Body.Graffiti,
+		RandaoReveal:      block.Deneb.Message.Body.RANDAOReveal,
 		Proposed:          true,
 		Attestations:      block.Deneb.Message.Body.Attestations,
 		Deposits:          block.Deneb.Message.Body.Deposits,
@@ -302,11 +336,94 @@ func NewDenebBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 			BlockNumber:   block.Deneb.Message.Body.ExecutionPayload.BlockNumber,
 			Withdrawals:   block.Deneb.Message.Body.ExecutionPayload.Withdrawals,
 			PayloadSize:   uint32(0),
+			BlobGasUsed:   block.Deneb.Message.Body.ExecutionPayload.BlobGasUsed,
+			ExcessBlobGas: block.Deneb.Message.Body.ExecutionPayload.ExcessBlobGas,
 		}, // snappy
 		BLSToExecutionChanges: block.Deneb.Message.Body.BLSToExecutionChanges,
+		BlobsNum:              len(block.Deneb.Message.Body.BlobKZGCommitments),
 		SSZsize:               compressionMetrics.SSZsize,
 		SnappySize:            compressionMetrics.SnappySize,
 		CompressionTime:       compressionMetrics.CompressionTime,
 		DecompressionTime:     compressionMetrics.DecompressionTime,
 	}
 }
+
+func NewElectraBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
+	// make the compression of the block
+	compressionMetrics, err := utils.CompressConsensusSignedBlock(block.Electra)
+	if err != nil {
+		logrus.Errorf("unable to compress electra block %d - %s", block.Electra.Message.Slot, err.Error())
+	}
+	root, err := block.Root()
+	if err != nil {
+		log.Fatalf("could not read root from block %d", block.Electra.Message.Slot)
+	}
+
+	// electra slashings only differ in the maximum number of attesting indices
+	attesterSlashings := make([]*phase0.AttesterSlashing, 0, len(block.Electra.Message.Body.AttesterSlashings))
+	for _, slashing := range block.Electra.Message.Body.AttesterSlashings {
+		attesterSlashings = append(attesterSlashings, &phase0.AttesterSlashing{
+			Attestation1: &phase0.IndexedAttestation{
+				AttestingIndices: slashing.Attestation1.AttestingIndices,
+				Data:             slashing.Attestation1.Data,
+				Signature:        slashing.Attestation1.Signature,
+			},
+			Attestation2: &phase0.IndexedAttestation{
+				AttestingIndices: slashing.Attestation2.AttestingIndices,
+				Data:             slashing.Attestation2.Data,
+				Signature:        slashing.Attestation2.Signature,
+			},
+		})
+	}
+
+	consolidationRequests := make([]*electra.ConsolidationRequest, 0)
+	depositRequests := make([]*electra.DepositRequest, 0)
+	withdrawalRequests := make([]*electra.WithdrawalRequest, 0)
+	if block.Electra.Message.Body.ExecutionRequests != nil {
+		consolidationRequests = block.Electra.Message.Body.ExecutionRequests.Consolidations
+		depositRequests = block.Electra.Message.Body.ExecutionRequests.Deposits
+		withdrawalRequests = block.Electra.Message.Body.ExecutionRequests.Withdrawals
+	}
+
+	return AgnosticBlock{
+		Slot:              block.Electra.Message.Slot,
+		Root:              root,
+		ParentRoot:        block.Electra.Message.ParentRoot,
+		ProposerIndex:     block.Electra.Message.ProposerIndex,
+		Graffiti:          block.Electra.Message.Body.Graffiti,
+		RandaoReveal:      block.Electra.Message.Body.RANDAOReveal,
+		Proposed:          true,
+		Attestations:      make([]*phase0.Attestation, 0), // filled by ExpandElectraAttestations
+		Deposits:          block.Electra.Message.Body.Deposits,
+		ProposerSlashings: block.Electra.Message.Body.ProposerSlashings,
+		AttesterSlashings: attesterSlashings,
+		VoluntaryExits:    block.Electra.Message.Body.VoluntaryExits,
+		SyncAggregate:     block.Electra.Message.Body.SyncAggregate,
+		ExecutionPayload: AgnosticExecutionPayload{
+			FeeRecipient:  block.Electra.Message.Body.ExecutionPayload.FeeRecipient,
+			GasLimit:      block.Electra.Message.Body.ExecutionPayload.GasLimit,
+			GasUsed:       block.Electra.Message.Body.ExecutionPayload.GasUsed,
+			Timestamp:     block.Electra.Message.Body.ExecutionPayload.Timestamp,
+			BaseFeePerGas: block.Electra.Message.Body.ExecutionPayload.BaseFeePerGas.Uint64(),
+			BlockHash:     block.Electra.Message.Body.ExecutionPayload.BlockHash,
+			Transactions:  block.Electra.Message.Body.ExecutionPayload.Transactions,
+			BlockNumber:   block.Electra.Message.Body.ExecutionPayload.BlockNumber,
+			Withdrawals:   block.Electra.Message.Body.ExecutionPayload.Withdrawals,
+			PayloadSize:   uint32(0),
+			BlobGasUsed:   block.Electra.Message.Body.ExecutionPayload.BlobGasUsed,
+			ExcessBlobGas: block.Electra.Message.Body.ExecutionPayload.ExcessBlobGas,
+		}, // snappy
+		BLSToExecutionChanges: block.Electra.Message.Body.BLSToExecutionChanges,
+		BlobsNum:              len(block.Electra.Message.Body.BlobKZGCommitments),
+		SSZsize:               compressionMetrics.SSZsize,
+		SnappySize:            compressionMetrics.SnappySize,
+		CompressionTime:       compressionMetrics.CompressionTime,
+		DecompressionTime:     compressionMetrics.DecompressionTime,
+
+		ElectraAttestations:   block.Electra.Message.Body.Attestations,
+		expandOnce:            &sync.Once{},
+		ConsolidationRequests: consolidationRequests,
+		DepositRequests:       depositRequests,
+		WithdrawalRequests:    withdrawalRequests,
+	}
+}
@@ -0,0 +1,46 @@
+package spec
+
+import (
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	LateBlockThreshold = int64(SlotSeconds * 1000 / 3) // ms, blocks received after the attestation deadline are late
+)
+
+// BlockArrival measures how late a block was with respect to the start of its slot
+type BlockArrival struct {
+	Slot          phase0.Slot
+	ProposerIndex phase0.ValidatorIndex
+	ArrivalDelay  int64 // ms between the slot start and the head event of the block
+	Late          bool
+}
+
+// NewBlockArrival builds the arrival of the block from the head event arrival time (unix ms)
+func NewBlockArrival(block AgnosticBlock, genesis time.Time, arrivalTimestamp int64) BlockArrival {
+	slotStart := SlotStart(genesis, block.Slot)
+	arrivalDelay := arrivalTimestamp - slotStart
+
+	return BlockArrival{
+		Slot:          block.Slot,
+		ProposerIndex: block.ProposerIndex,
+		ArrivalDelay:  arrivalDelay,
+		Late:          arrivalDelay > LateBlockThreshold,
+	}
+}
+
+func (f BlockArrival) Type() ModelType {
+	return BlockArrivalModel
+}
+
+func (f BlockArrival) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Slot,
+		f.ProposerIndex,
+		f.ArrivalDelay,
+		f.Late,
+	}
+	return rows
+}
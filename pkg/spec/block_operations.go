@@ -0,0 +1,40 @@
+package spec
+
+// BlockOperations counts the operations included in the blocks of an epoch, whether or not they end up
+// applied (i.e. a slashing of a validator already slashed), so dashboards do not join the tables of each operation.
+// Since Electra the execution layer requests of the blocks are counted as well
+type BlockOperations struct {
+	Deposits                  int
+	VoluntaryExits            int
+	AttesterSlashings         int
+	ProposerSlashings         int
+	DepositRequests           int
+	ExitRequests              int // withdrawal requests of the full balance
+	PartialWithdrawalRequests int
+	ConsolidationRequests     int
+}
+
+// BlockOperations adds up the operations included in the blocks of the state epoch. Blocks without body
+// (epochs-only mode) carry no operations, so it needs the block metrics
+func (p AgnosticState) BlockOperations() BlockOperations {
+	operations := BlockOperations{}
+	for _, block := range p.Blocks {
+		if !block.Proposed {
+			continue
+		}
+		operations.Deposits += len(block.Deposits)
+		operations.VoluntaryExits += len(block.VoluntaryExits)
+		operations.AttesterSlashings += len(block.AttesterSlashings)
+		operations.ProposerSlashings += len(block.ProposerSlashings)
+		operations.DepositRequests += len(block.DepositRequests)
+		operations.ConsolidationRequests += len(block.ConsolidationRequests)
+		for _, request := range block.WithdrawalRequests {
+			if request.Amount == FullExitRequestAmount {
+				operations.ExitRequests++
+			} else {
+				operations.PartialWithdrawalRequests++
+			}
+		}
+	}
+	return operations
+}
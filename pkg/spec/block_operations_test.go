@@ -0,0 +1,48 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestBlockOperations(t *testing.T) {
+	block := func(proposed bool, deposits int, exits int, attesterSlashings int, proposerSlashings int) *spec.AgnosticBlock {
+		return &spec.AgnosticBlock{
+			Proposed:          proposed,
+			Deposits:          make([]*phase0.Deposit, deposits),
+			VoluntaryExits:    make([]*phase0.SignedVoluntaryExit, exits),
+			AttesterSlashings: make([]*phase0.AttesterSlashing, attesterSlashings),
+			ProposerSlashings: make([]*phase0.ProposerSlashing, proposerSlashings),
+		}
+	}
+	// since Electra, with the execution layer requests
+	electraBlock := block(true, 0, 1, 0, 0)
+	electraBlock.DepositRequests = make([]*electra.DepositRequest, 4)
+	electraBlock.WithdrawalRequests = []*electra.WithdrawalRequest{{Amount: 0}, {Amount: 1_000_000_000}, {Amount: 0}}
+	electraBlock.ConsolidationRequests = make([]*electra.ConsolidationRequest, 1)
+	state := spec.AgnosticState{
+		Blocks: []*spec.AgnosticBlock{
+			block(true, 16, 2, 0, 1),
+			block(false, 0, 0, 0, 0),
+			block(true, 3, 0, 2, 0),
+			electraBlock,
+		},
+	}
+
+	expected := spec.BlockOperations{
+		Deposits:                  19,
+		VoluntaryExits:            3,
+		AttesterSlashings:         2,
+		ProposerSlashings:         1,
+		DepositRequests:           4,
+		ExitRequests:              2,
+		PartialWithdrawalRequests: 1,
+		ConsolidationRequests:     1,
+	}
+	if operations := state.BlockOperations(); operations != expected {
+		t.Fatalf("expected %+v, got %+v", expected, operations)
+	}
+}
@@ -0,0 +1,35 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ChainSplit is a period in which the main beacon node and a peer follow different chains
+type ChainSplit struct {
+	StartSlot    phase0.Slot // first slot with different canonical roots
+	DetectedSlot phase0.Slot // slot at which the split was recorded, after the threshold
+	EndSlot      phase0.Slot // slot at which both nodes agreed again, 0 while ongoing
+	PeerEndpoint string
+	Root         phase0.Root // canonical root at StartSlot for the main beacon node
+	PeerRoot     phase0.Root // canonical root at StartSlot for the peer
+}
+
+func (f ChainSplit) Resolved() bool {
+	return f.EndSlot != 0
+}
+
+func (f ChainSplit) Type() ModelType {
+	return ChainSplitModel
+}
+
+func (f ChainSplit) ToArray() []interface{} {
+	rows := []interface{}{
+		f.StartSlot,
+		f.DetectedSlot,
+		f.EndSlot,
+		f.PeerEndpoint,
+		f.Root.String(),
+		f.PeerRoot.String(),
+	}
+	return rows
+}
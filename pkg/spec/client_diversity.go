@@ -0,0 +1,141 @@
+package spec
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// GraffitiFingerprint is a consensus client and the pattern its graffiti matches: the name of the client,
+// or its code in the client version graffiti (execution client code, commit, consensus client code, commit)
+type GraffitiFingerprint struct {
+	Client  string
+	Pattern string // RE2 syntax
+}
+
+const (
+	executionClientCodes = "(GE|NM|BU|EG|RH|EJ)"
+	UnknownClient        = "unknown"
+)
+
+var (
+	GraffitiFingerprints = []GraffitiFingerprint{
+		{Client: "lighthouse", Pattern: "(?i:lighthouse)|" + executionClientCodes + "[0-9a-f]{0,4}LH"},
+		{Client: "prysm", Pattern: "(?i:prysm)|" + executionClientCodes + "[0-9a-f]{0,4}PM"},
+		{Client: "teku", Pattern: "(?i:teku)|" + executionClientCodes + "[0-9a-f]{0,4}TK"},
+		{Client: "nimbus", Pattern: "(?i:nimbus)|" + executionClientCodes + "[0-9a-f]{0,4}NB"},
+		{Client: "lodestar", Pattern: "(?i:lodestar)|" + executionClientCodes + "[0-9a-f]{0,4}LS"},
+		{Client: "grandine", Pattern: "(?i:grandine)|" + executionClientCodes + "[0-9a-f]{0,4}GR"},
+	}
+
+	graffitiRegexps = compileFingerprints()
+)
+
+func compileFingerprints() []*regexp.Regexp {
+	regexps := make([]*regexp.Regexp, 0, len(GraffitiFingerprints))
+	for _, fingerprint := range GraffitiFingerprints {
+		regexps = append(regexps, regexp.MustCompile(fingerprint.Pattern))
+	}
+	return regexps
+}
+
+// GraffitiClient returns the consensus client the graffiti belongs to, empty if it has no fingerprint.
+// The first fingerprint matched wins
+func GraffitiClient(graffiti string) string {
+	graffiti = strings.ReplaceAll(graffiti, "\u0000", "")
+	for i, re := range graffitiRegexps {
+		if re.MatchString(graffiti) {
+			return GraffitiFingerprints[i].Client
+		}
+	}
+	return ""
+}
+
+// PoolProposal is a block proposed by a validator of a pool
+type PoolProposal struct {
+	Slot          phase0.Slot
+	ProposerIndex phase0.ValidatorIndex
+	PoolName      string
+	Graffiti      string
+}
+
+// PoolClientDiversity is the share of the blocks of a pool in a window of epochs attributed to a consensus client
+type PoolClientDiversity struct {
+	Epoch             phase0.Epoch // the window ends at the epoch before
+	WindowEpochs      phase0.Epoch
+	PoolName          string
+	Client            string
+	Proposals         uint64
+	InferredProposals uint64 // blocks without fingerprint attributed from another block of the same validator
+	Share             float64
+}
+
+func (c PoolClientDiversity) Type() ModelType {
+	return PoolClientDiversityModel
+}
+
+// NewPoolClientDiversity attributes every proposal of the window to the client of its graffiti or, without
+// fingerprint, to the one of the last proposal of the same validator that had one (validators rarely switch
+// clients), and to UnknownClient otherwise. Results are sorted by pool and client
+func NewPoolClientDiversity(epoch phase0.Epoch, window phase0.Epoch, proposals []PoolProposal) []PoolClientDiversity {
+	type slotClient struct {
+		slot   phase0.Slot
+		client string
+	}
+	clients := make([]string, len(proposals))
+	lastClients := make(map[phase0.ValidatorIndex]slotClient) // last proposal of each validator with fingerprint
+	for i, proposal := range proposals {
+		clients[i] = GraffitiClient(proposal.Graffiti)
+		if clients[i] == "" {
+			continue
+		}
+		if last, ok := lastClients[proposal.ProposerIndex]; !ok || proposal.Slot > last.slot {
+			lastClients[proposal.ProposerIndex] = slotClient{slot: proposal.Slot, client: clients[i]}
+		}
+	}
+
+	type poolClient struct {
+		pool   string
+		client string
+	}
+	diversity := make(map[poolClient]*PoolClientDiversity)
+	poolProposals := make(map[string]uint64)
+	for i, proposal := range proposals {
+		client, inferred := clients[i], false
+		if client == "" {
+			client = UnknownClient
+			if last, ok := lastClients[proposal.ProposerIndex]; ok {
+				client, inferred = last.client, true
+			}
+		}
+		key := poolClient{pool: proposal.PoolName, client: client}
+		if _, ok := diversity[key]; !ok {
+			diversity[key] = &PoolClientDiversity{
+				Epoch:        epoch,
+				WindowEpochs: window,
+				PoolName:     proposal.PoolName,
+				Client:       client,
+			}
+		}
+		diversity[key].Proposals++
+		if inferred {
+			diversity[key].InferredProposals++
+		}
+		poolProposals[proposal.PoolName]++
+	}
+
+	result := make([]PoolClientDiversity, 0, len(diversity))
+	for _, item := range diversity {
+		item.Share = float64(item.Proposals) / float64(poolProposals[item.PoolName])
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PoolName != result[j].PoolName {
+			return result[i].PoolName < result[j].PoolName
+		}
+		return result[i].Client < result[j].Client
+	})
+	return result
+}
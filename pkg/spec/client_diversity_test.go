@@ -0,0 +1,55 @@
+package spec_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestGraffitiClient(t *testing.T) {
+	tests := []struct {
+		graffiti string
+		client   string
+	}{
+		{graffiti: "Lighthouse/v3.1.0-aa022f4", client: "lighthouse"},
+		{graffiti: "prysm-validator", client: "prysm"},
+		{graffiti: "NMa1b2TKc3d4", client: "teku"},
+		{graffiti: "GELS", client: "lodestar"},
+		{graffiti: "RH12NB34 my pool", client: "nimbus"},
+		{graffiti: "stakefish\u0000\u0000", client: ""},
+		{graffiti: "LH", client: ""}, // client codes need the execution client one before
+		{graffiti: "", client: ""},
+	}
+
+	for _, test := range tests {
+		if client := spec.GraffitiClient(test.graffiti); client != test.client {
+			t.Errorf("GraffitiClient(%q) returned %q, expected %q", test.graffiti, client, test.client)
+		}
+	}
+}
+
+func TestNewPoolClientDiversity(t *testing.T) {
+	proposals := []spec.PoolProposal{
+		{Slot: 10, ProposerIndex: 1, PoolName: "a", Graffiti: "Lighthouse/v5.0.0"},
+		{Slot: 11, ProposerIndex: 1, PoolName: "a", Graffiti: "pool a"},   // inferred from slot 20
+		{Slot: 20, ProposerIndex: 1, PoolName: "a", Graffiti: "GE12TK34"}, // last one of the validator wins
+		{Slot: 12, ProposerIndex: 2, PoolName: "a", Graffiti: "pool a"},   // no fingerprint in the window
+		{Slot: 13, ProposerIndex: 3, PoolName: "b", Graffiti: "prysm"},
+		{Slot: 14, ProposerIndex: 3, PoolName: "b", Graffiti: "\u0000\u0000"}, // inferred
+	}
+	expected := []spec.PoolClientDiversity{
+		{Epoch: 2, WindowEpochs: 2, PoolName: "a", Client: "lighthouse", Proposals: 1, Share: 0.25},
+		{Epoch: 2, WindowEpochs: 2, PoolName: "a", Client: "teku", Proposals: 2, InferredProposals: 1, Share: 0.5},
+		{Epoch: 2, WindowEpochs: 2, PoolName: "a", Client: spec.UnknownClient, Proposals: 1, Share: 0.25},
+		{Epoch: 2, WindowEpochs: 2, PoolName: "b", Client: "prysm", Proposals: 2, InferredProposals: 1, Share: 1},
+	}
+
+	result := spec.NewPoolClientDiversity(2, 2, proposals)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("NewPoolClientDiversity returned %+v, expected %+v", result, expected)
+	}
+	if result := spec.NewPoolClientDiversity(2, 2, nil); len(result) != 0 {
+		t.Errorf("NewPoolClientDiversity without proposals returned %+v, expected none", result)
+	}
+}
@@ -0,0 +1,41 @@
+package spec
+
+import "github.com/attestantio/go-eth2-client/spec/phase0"
+
+// CommitteeConfig holds the values of the network the beacon and sync committees are computed with
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#misc
+type CommitteeConfig struct {
+	TargetCommitteeSize  uint64
+	MinSeedLookahead     uint64
+	DomainBeaconAttester phase0.DomainType
+	SyncCommitteeSize    uint64 // since Altair
+}
+
+// DefaultCommitteeConfig is the committee config of mainnet
+var DefaultCommitteeConfig = CommitteeConfig{
+	TargetCommitteeSize:  TargetCommitteeSize,
+	MinSeedLookahead:     MinSeedLookahead,
+	DomainBeaconAttester: phase0.DomainType{0x01, 0x00, 0x00, 0x00},
+	SyncCommitteeSize:    SyncCommitteeSize,
+}
+
+// NewCommitteeConfigFromSpec reads the committee config from the node's spec (/eth/v1/config/spec)
+// missing or malformed values fall back to the default ones
+func NewCommitteeConfigFromSpec(nodeSpec map[string]any) CommitteeConfig {
+	config := DefaultCommitteeConfig
+
+	if value, ok := specUint(nodeSpec["TARGET_COMMITTEE_SIZE"]); ok && value > 0 {
+		config.TargetCommitteeSize = value
+	}
+	if value, ok := specUint(nodeSpec["MIN_SEED_LOOKAHEAD"]); ok {
+		config.MinSeedLookahead = value
+	}
+	if value, ok := specUint(nodeSpec["SYNC_COMMITTEE_SIZE"]); ok && value > 0 {
+		config.SyncCommitteeSize = value
+	}
+	if value, ok := nodeSpec["DOMAIN_BEACON_ATTESTER"].(phase0.DomainType); ok {
+		config.DomainBeaconAttester = value
+	}
+
+	return config
+}
@@ -0,0 +1,112 @@
+package spec_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestNewCommitteeConfigFromSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodeSpec map[string]any
+		expected spec.CommitteeConfig
+	}{
+		{
+			name:     "No committee config",
+			nodeSpec: map[string]any{"SLOTS_PER_EPOCH": uint64(32)},
+			expected: spec.DefaultCommitteeConfig,
+		},
+		{
+			name: "Minimal preset",
+			nodeSpec: map[string]any{
+				"TARGET_COMMITTEE_SIZE":  uint64(4),
+				"MIN_SEED_LOOKAHEAD":     uint64(1),
+				"DOMAIN_BEACON_ATTESTER": phase0.DomainType{0x01, 0x00, 0x00, 0x00},
+				"SYNC_COMMITTEE_SIZE":    uint64(32),
+			},
+			expected: spec.CommitteeConfig{TargetCommitteeSize: 4, MinSeedLookahead: 1, DomainBeaconAttester: phase0.DomainType{0x01, 0x00, 0x00, 0x00}, SyncCommitteeSize: 32},
+		},
+		{
+			name: "Malformed values",
+			nodeSpec: map[string]any{
+				"TARGET_COMMITTEE_SIZE":  uint64(0),
+				"MIN_SEED_LOOKAHEAD":     "2",
+				"DOMAIN_BEACON_ATTESTER": "0x01000000",
+				"SYNC_COMMITTEE_SIZE":    "malformed",
+			},
+			expected: spec.CommitteeConfig{TargetCommitteeSize: 128, MinSeedLookahead: 2, DomainBeaconAttester: phase0.DomainType{0x01, 0x00, 0x00, 0x00}, SyncCommitteeSize: 512},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := spec.NewCommitteeConfigFromSpec(test.nodeSpec)
+			if !reflect.DeepEqual(config, test.expected) {
+				t.Errorf("NewCommitteeConfigFromSpec returned %+v, expected %+v", config, test.expected)
+			}
+		})
+	}
+}
+
+func TestAttesterSeed(t *testing.T) {
+	// mix i is filled with byte i, the seed of epoch 10 is computed with the mix of epoch 10-lookahead-1
+	mixes := make([]phase0.Root, 16)
+	for i := range mixes {
+		copy(mixes[i][:], bytes.Repeat([]byte{byte(i)}, 32))
+	}
+	state := spec.AgnosticState{Epoch: 10, RandaoMixes: mixes}
+
+	tests := []struct {
+		name     string
+		config   spec.CommitteeConfig
+		expected string
+	}{
+		{
+			name:     "Mainnet config",
+			config:   spec.DefaultCommitteeConfig,
+			expected: "dbc047c0cc1002b177c6c68b9eae6892d9ac08a794e7690134ea7bfe52cbc882",
+		},
+		{
+			name:     "Longer lookahead",
+			config:   spec.CommitteeConfig{MinSeedLookahead: 2, DomainBeaconAttester: phase0.DomainType{0x01, 0x00, 0x00, 0x00}},
+			expected: "595c71fd1dfd52974a27ac850237ce886d2bcd45ec584036f8693a9d861a5014",
+		},
+		{
+			name:     "Other domain",
+			config:   spec.CommitteeConfig{MinSeedLookahead: 1, DomainBeaconAttester: phase0.DomainType{0x09, 0x00, 0x00, 0x00}},
+			expected: "c54c251cdb23d05ad0eb1283dd3f30b398e626353bd8eab4c6ec7f0f5dbc4c07",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			seed := state.AttesterSeed(test.config)
+			if result := hex.EncodeToString(seed[:]); result != test.expected {
+				t.Errorf("AttesterSeed returned %s, expected %s", result, test.expected)
+			}
+		})
+	}
+
+	if seed := (spec.AgnosticState{}).AttesterSeed(spec.DefaultCommitteeConfig); seed != (phase0.Root{}) {
+		t.Errorf("AttesterSeed returned %s without randao mixes, expected an empty root", seed)
+	}
+}
+
+func TestCommitteeSizes(t *testing.T) {
+	duties := spec.EpochDuties{BeaconCommittees: []*api.BeaconCommittee{
+		{Slot: 1, Index: 0, Validators: []phase0.ValidatorIndex{1, 2, 3}},
+		{Slot: 1, Index: 1, Validators: []phase0.ValidatorIndex{4, 5}},
+	}}
+	if count, avg := duties.CommitteeSizes(); count != 2 || avg != 2.5 {
+		t.Errorf("CommitteeSizes returned %d committees of %.1f validators, expected 2 of 2.5", count, avg)
+	}
+	if count, avg := (spec.EpochDuties{}).CommitteeSizes(); count != 0 || avg != 0 {
+		t.Errorf("CommitteeSizes returned %d committees of %.1f validators without duties, expected 0", count, avg)
+	}
+}
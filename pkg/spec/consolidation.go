@@ -0,0 +1,96 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ConsolidationRequest is an EIP-7251 request, sent from the execution layer, to move the balance
+// of the source validator into the target one. When both are the same validator,
+// the request switches its withdrawal credentials to compounding ones
+type ConsolidationRequest struct {
+	Slot          phase0.Slot
+	Epoch         phase0.Epoch
+	Index         uint8 // position of the request in the block
+	SourceAddress bellatrix.ExecutionAddress
+	SourcePubkey  phase0.BLSPubKey
+	TargetPubkey  phase0.BLSPubKey
+}
+
+func (f ConsolidationRequest) SwitchToCompounding() bool {
+	return f.SourcePubkey == f.TargetPubkey
+}
+
+func (f ConsolidationRequest) Type() ModelType {
+	return ConsolidationRequestModel
+}
+
+func (f ConsolidationRequest) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Slot,
+		f.Epoch,
+		f.Index,
+		f.SourceAddress.String(),
+		f.SourcePubkey.String(),
+		f.TargetPubkey.String(),
+		f.SwitchToCompounding(),
+	}
+	return rows
+}
+
+// CompoundingBalance is the balance of a validator with compounding credentials at the end of the epoch,
+// which may grow above 32 ETH
+type CompoundingBalance struct {
+	Epoch                  phase0.Epoch
+	ValidatorIndex         phase0.ValidatorIndex
+	EffectiveBalance       phase0.Gwei
+	Balance                phase0.Gwei
+	EffectiveBalanceChange int64 // since the previous epoch
+	PendingConsolidation   bool  // the validator is the target of a consolidation not processed yet
+}
+
+// NewCompoundingBalances returns the balances of the validators with compounding credentials in the state,
+// comparing the effective balance with the one in the previous state
+func NewCompoundingBalances(state *AgnosticState, prevState *AgnosticState) []CompoundingBalance {
+	pendingTargets := make(map[phase0.ValidatorIndex]bool)
+	for _, consolidation := range state.PendingConsolidations {
+		pendingTargets[consolidation.TargetIndex] = true
+	}
+
+	balances := make([]CompoundingBalance, 0)
+	for i, validator := range state.Validators {
+		if !HasCompoundingCredentials(validator) {
+			continue
+		}
+		valIdx := phase0.ValidatorIndex(i)
+		change := int64(0)
+		if prevState != nil && i < len(prevState.Validators) {
+			change = int64(validator.EffectiveBalance) - int64(prevState.Validators[i].EffectiveBalance)
+		}
+		balances = append(balances, CompoundingBalance{
+			Epoch:                  state.Epoch,
+			ValidatorIndex:         valIdx,
+			EffectiveBalance:       validator.EffectiveBalance,
+			Balance:                state.Balance(valIdx),
+			EffectiveBalanceChange: change,
+			PendingConsolidation:   pendingTargets[valIdx],
+		})
+	}
+	return balances
+}
+
+func (f CompoundingBalance) Type() ModelType {
+	return CompoundingBalanceModel
+}
+
+func (f CompoundingBalance) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Epoch,
+		f.ValidatorIndex,
+		f.EffectiveBalance,
+		f.Balance,
+		f.EffectiveBalanceChange,
+		f.PendingConsolidation,
+	}
+	return rows
+}
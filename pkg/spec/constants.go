@@ -31,6 +31,15 @@ const (
 	EpochSlots                  = 32
 	WhistleBlowerRewardQuotient = 512
 	MinInclusionDelay           = 1
+	MinSeedLookahead            = 1 // the proposers of an epoch are decided by the randao mix of MinSeedLookahead+1 epochs before
+	TargetCommitteeSize         = 128
+
+	// exit queue
+	MaxSeedLookahead                 = 4 // exits take effect MaxSeedLookahead+1 epochs after being processed at the earliest
+	MinPerEpochChurnLimit            = 4
+	ChurnLimitQuotient               = 65536
+	ShardCommitteePeriod             = 256 // epochs a validator has to be active before it can exit
+	MinValidatorWithdrawabilityDelay = 256 // epochs between the exit and the withdrawal of the balance
 
 	AttSourceFlagIndex = 0
 	AttTargetFlagIndex = 1
@@ -41,7 +50,7 @@ const (
 Altair
 */
 const (
-	// spec weight constants
+	// spec weight constants, mainnet defaults (see RewardWeights)
 	TimelySourceWeight = 14
 	TimelyTargetWeight = 26
 	TimelyHeadWeight   = 14
@@ -52,8 +61,32 @@ const (
 	SyncCommitteeSize = 512
 )
 
-var (
-	ParticipatingFlagsWeight = [3]int{TimelySourceWeight, TimelyTargetWeight, TimelyHeadWeight}
+/*
+Capella
+*/
+const (
+	MaxWithdrawalsPerPayload = 16 // withdrawals swept per block at most
+)
+
+/*
+Electra
+*/
+const (
+	MaxEffectiveIncElectra             = 2048 // maximum effective balance of validators with compounding credentials
+	CompoundingWithdrawalPrefix        = 0x02
+	WhistleBlowerRewardQuotientElectra = 4096
+
+	MinPerEpochChurnLimitElectra        = 128 * EffectiveBalanceInc // gwei
+	MaxPerEpochActivationExitChurnLimit = 256 * EffectiveBalanceInc // gwei
+
+	FullExitRequestAmount = 0 // amount of the withdrawal requests that exit the validator
+)
+
+/*
+Deneb
+*/
+const (
+	GasPerBlob = 131072 // 2**17
 )
 
 type ModelType int8
@@ -82,6 +115,37 @@ const (
 	BLSToExecutionChangeModel
 	DepositModel
 	ETH1DepositModel
+	BlockArrivalModel
+	EpochCheckpointsModel
+	EquivocationModel
+	ConsolidationRequestModel
+	CompoundingBalanceModel
+	ChainSplitModel
+	ValidatorRewardsDeltaModel
+	ValidatorStatusAnomalyModel
+	EpochCompletenessModel
+	RootMismatchModel
+	RegistryAnomalyModel
+	TransactionLogModel
+	SelfCheckDiscrepancyModel
+	EpochRandaoModel
+	VoteTallyModel
+	ValidatorBalanceSampleModel
+	VoluntaryExitEventModel
+	VoluntaryExitModel
+	EffectiveBalanceBucketModel
+	StateProofModel
+	ProposerFairnessModel
+	ValidatorKeyModel
+	EpochChurnModel
+	PoolKeyModel
+	ProtectionAnomalyModel
+	DataQualityNoteModel
+	AttestationInclusionModel
+	ReorgForkChoiceModel
+	PoolClientDiversityModel
+	PoolLuckModel
+	ValidatorRewardsKeyframeModel
 )
 
 type ValidatorStatus int8
@@ -93,3 +157,50 @@ const (
 	SLASHED_STATUS
 	NUMBER_OF_STATUS // Add new status before this
 )
+
+// ValidatorDetailedStatus is the status of the validator as defined by the beacon API
+// (https://hackmd.io/ofFJ5gOmQpu1jjHilHbdQQ), splitting each ValidatorStatus in its phases
+type ValidatorDetailedStatus uint8
+
+const (
+	PENDING_INITIALIZED_STATUS ValidatorDetailedStatus = iota
+	PENDING_QUEUED_STATUS
+	ACTIVE_ONGOING_STATUS
+	ACTIVE_EXITING_STATUS
+	ACTIVE_SLASHED_STATUS
+	EXITED_UNSLASHED_STATUS
+	EXITED_SLASHED_STATUS
+	WITHDRAWAL_POSSIBLE_STATUS
+	WITHDRAWAL_DONE_STATUS
+	NUMBER_OF_DETAILED_STATUS // Add new status before this
+)
+
+var detailedStatusNames = []string{
+	"pending_initialized",
+	"pending_queued",
+	"active_ongoing",
+	"active_exiting",
+	"active_slashed",
+	"exited_unslashed",
+	"exited_slashed",
+	"withdrawal_possible",
+	"withdrawal_done",
+}
+
+// String returns the name of the status in the beacon API
+func (s ValidatorDetailedStatus) String() string {
+	if s >= NUMBER_OF_DETAILED_STATUS {
+		return "unknown"
+	}
+	return detailedStatusNames[s]
+}
+
+// DetailedStatusFromNodeStatus returns the status with the given beacon API name
+func DetailedStatusFromNodeStatus(status string) (ValidatorDetailedStatus, bool) {
+	for i, name := range detailedStatusNames {
+		if name == status {
+			return ValidatorDetailedStatus(i), true
+		}
+	}
+	return 0, false
+}
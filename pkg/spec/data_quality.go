@@ -0,0 +1,42 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+type DataQualityNoteType string
+
+const (
+	StateSubstitutionNote DataQualityNoteType = "state_substitution" // the state of the epoch was taken from another slot
+)
+
+// DataQualityNote flags the metrics of an epoch computed from data other than the expected one
+type DataQualityNote struct {
+	Epoch  phase0.Epoch
+	Kind   DataQualityNoteType
+	Slot   phase0.Slot // slot of the data expected
+	UsedAt phase0.Slot // slot of the data used instead
+	Detail string      // why the expected data could not be used
+}
+
+func (f DataQualityNote) Type() ModelType {
+	return DataQualityNoteModel
+}
+
+// FallbackSlots returns the slots of the same epoch whose state can replace the one at the given slot,
+// the closest first and the earlier ones before the later ones, up to the given distance
+func FallbackSlots(slot phase0.Slot, distance int) []phase0.Slot {
+	firstSlot := slot / SlotsPerEpoch * SlotsPerEpoch
+	lastSlot := firstSlot + SlotsPerEpoch - 1
+
+	slots := make([]phase0.Slot, 0, 2*distance)
+	for i := phase0.Slot(1); i <= phase0.Slot(distance); i++ {
+		if slot >= firstSlot+i {
+			slots = append(slots, slot-i)
+		}
+		if slot+i <= lastSlot {
+			slots = append(slots, slot+i)
+		}
+	}
+	return slots
+}
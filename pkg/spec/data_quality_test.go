@@ -0,0 +1,27 @@
+package spec_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestFallbackSlots(t *testing.T) {
+	tests := []struct {
+		slot     phase0.Slot
+		distance int
+		expected []phase0.Slot
+	}{
+		{95, 2, []phase0.Slot{94, 93}},         // last slot of the epoch: only the earlier ones
+		{64, 2, []phase0.Slot{65, 66}},         // first slot of the epoch: only the later ones
+		{80, 2, []phase0.Slot{79, 81, 78, 82}}, // closest first, earlier before later
+		{80, 0, []phase0.Slot{}},
+	}
+	for _, test := range tests {
+		if slots := spec.FallbackSlots(test.slot, test.distance); !slices.Equal(slots, test.expected) {
+			t.Errorf("slot %d, distance %d: expected %v, got %v", test.slot, test.distance, test.expected, slots)
+		}
+	}
+}
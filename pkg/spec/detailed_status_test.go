@@ -0,0 +1,63 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestGetValDetailedStatus(t *testing.T) {
+	validator := func(eligibility, activation, exit, withdrawable phase0.Epoch, slashed bool) *phase0.Validator {
+		return &phase0.Validator{
+			ActivationEligibilityEpoch: eligibility,
+			ActivationEpoch:            activation,
+			ExitEpoch:                  exit,
+			WithdrawableEpoch:          withdrawable,
+			Slashed:                    slashed,
+		}
+	}
+	state := spec.AgnosticState{
+		Epoch: 100,
+		Validators: []*phase0.Validator{
+			validator(farFuture, farFuture, farFuture, farFuture, false),
+			validator(99, 104, farFuture, farFuture, false),
+			validator(10, 15, farFuture, farFuture, false),
+			validator(10, 15, 120, 376, false),
+			validator(10, 15, 120, 8292, true),
+			validator(10, 15, 100, 356, false),
+			validator(10, 15, 90, 8292, true),
+			validator(10, 15, 50, 100, false), // no balance in the state
+		},
+	}
+
+	expected := []spec.ValidatorDetailedStatus{
+		spec.PENDING_INITIALIZED_STATUS,
+		spec.PENDING_QUEUED_STATUS,
+		spec.ACTIVE_ONGOING_STATUS,
+		spec.ACTIVE_EXITING_STATUS,
+		spec.ACTIVE_SLASHED_STATUS,
+		spec.EXITED_UNSLASHED_STATUS,
+		spec.EXITED_SLASHED_STATUS,
+		spec.WITHDRAWAL_DONE_STATUS,
+		spec.PENDING_INITIALIZED_STATUS, // not in the state
+	}
+	for i, status := range expected {
+		result := state.GetValDetailedStatus(phase0.ValidatorIndex(i))
+		if result != status {
+			t.Errorf("GetValDetailedStatus of validator %d returned %s, expected %s", i, result, status)
+		}
+	}
+}
+
+func TestDetailedStatusFromNodeStatus(t *testing.T) {
+	for status := spec.ValidatorDetailedStatus(0); status < spec.NUMBER_OF_DETAILED_STATUS; status++ {
+		result, ok := spec.DetailedStatusFromNodeStatus(status.String())
+		if !ok || result != status {
+			t.Errorf("DetailedStatusFromNodeStatus(%s) returned %s, expected %s", status, result, status)
+		}
+	}
+	if _, ok := spec.DetailedStatusFromNodeStatus("active"); ok {
+		t.Errorf("DetailedStatusFromNodeStatus(active) returned a status, expected none")
+	}
+}
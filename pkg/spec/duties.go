@@ -23,6 +23,18 @@ func (p EpochDuties) GetValList(slot phase0.Slot, committeeIndex phase0.Committe
 	return nil
 }
 
+// CommitteeSizes returns the number of beacon committees of the epoch and their average number of validators
+func (p EpochDuties) CommitteeSizes() (int, float64) {
+	if len(p.BeaconCommittees) == 0 {
+		return 0, 0
+	}
+	validators := 0
+	for _, committee := range p.BeaconCommittees {
+		validators += len(committee.Validators)
+	}
+	return len(p.BeaconCommittees), float64(validators) / float64(len(p.BeaconCommittees))
+}
+
 func GetEffectiveBalance(balance float64) float64 {
 	return math.Min(MaxEffectiveInc*EffectiveBalanceInc, balance)
 }
@@ -0,0 +1,62 @@
+package spec
+
+import "github.com/attestantio/go-eth2-client/spec/phase0"
+
+// EffectiveBalanceBuckets are the upper bounds (included) of the buckets of the effective balance histogram.
+// Effective balances move in steps of 1 ETH, so 32 ETH has its own bucket, and the ones above are
+// the compounding validators (since Electra)
+var EffectiveBalanceBuckets = []phase0.Gwei{
+	16 * EffectiveBalanceInc,
+	31 * EffectiveBalanceInc,
+	32 * EffectiveBalanceInc,
+	64 * EffectiveBalanceInc,
+	128 * EffectiveBalanceInc,
+	256 * EffectiveBalanceInc,
+	512 * EffectiveBalanceInc,
+	1024 * EffectiveBalanceInc,
+	2048 * EffectiveBalanceInc,
+}
+
+// EffectiveBalanceBucket is the number of active validators in the epoch with an effective balance
+// above the previous bucket and up to MaxEffectiveBalance
+type EffectiveBalanceBucket struct {
+	Epoch               phase0.Epoch
+	MaxEffectiveBalance phase0.Gwei
+	Validators          uint64
+	EffectiveBalance    phase0.Gwei // sum of the effective balance of the validators
+}
+
+// EffectiveBalanceBucketIndex returns the bucket of the effective balance, the last one if above all of them
+func EffectiveBalanceBucketIndex(effectiveBalance phase0.Gwei) int {
+	for i, bound := range EffectiveBalanceBuckets {
+		if effectiveBalance <= bound {
+			return i
+		}
+	}
+	return len(EffectiveBalanceBuckets) - 1
+}
+
+func newEffectiveBalanceHistogram(epoch phase0.Epoch) []EffectiveBalanceBucket {
+	histogram := make([]EffectiveBalanceBucket, len(EffectiveBalanceBuckets))
+	for i, bound := range EffectiveBalanceBuckets {
+		histogram[i] = EffectiveBalanceBucket{
+			Epoch:               epoch,
+			MaxEffectiveBalance: bound,
+		}
+	}
+	return histogram
+}
+
+func (f EffectiveBalanceBucket) Type() ModelType {
+	return EffectiveBalanceBucketModel
+}
+
+func (f EffectiveBalanceBucket) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Epoch,
+		f.MaxEffectiveBalance,
+		f.Validators,
+		f.EffectiveBalance,
+	}
+	return rows
+}
@@ -0,0 +1,57 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestEffectiveBalanceBucketIndex(t *testing.T) {
+	tests := []struct {
+		effectiveBalance phase0.Gwei
+		expected         int
+	}{
+		{0, 0},
+		{16 * spec.EffectiveBalanceInc, 0},
+		{17 * spec.EffectiveBalanceInc, 1},
+		{31 * spec.EffectiveBalanceInc, 1},
+		{32 * spec.EffectiveBalanceInc, 2},
+		{33 * spec.EffectiveBalanceInc, 3},
+		{2048 * spec.EffectiveBalanceInc, 8},
+		{4096 * spec.EffectiveBalanceInc, 8},
+	}
+	for _, test := range tests {
+		result := spec.EffectiveBalanceBucketIndex(test.effectiveBalance)
+		if result != test.expected {
+			t.Errorf("EffectiveBalanceBucketIndex(%d) returned %d, expected %d", test.effectiveBalance, result, test.expected)
+		}
+	}
+}
+
+func TestEffectiveBalanceHistogram(t *testing.T) {
+	validator := func(effectiveBalance phase0.Gwei, exit phase0.Epoch) *phase0.Validator {
+		return &phase0.Validator{EffectiveBalance: effectiveBalance, ExitEpoch: exit}
+	}
+	state := spec.AgnosticState{
+		Epoch: 10,
+		Validators: []*phase0.Validator{
+			validator(32*spec.EffectiveBalanceInc, farFuture),
+			validator(32*spec.EffectiveBalanceInc, farFuture),
+			validator(100*spec.EffectiveBalanceInc, farFuture),
+			validator(32*spec.EffectiveBalanceInc, 5), // exited
+		},
+	}
+	state.GetTotalActiveEffBalance()
+
+	histogram := state.EffectiveBalanceHistogram
+	if len(histogram) != len(spec.EffectiveBalanceBuckets) {
+		t.Fatalf("histogram has %d buckets, expected %d", len(histogram), len(spec.EffectiveBalanceBuckets))
+	}
+	if histogram[2].Validators != 2 || histogram[2].EffectiveBalance != 64*spec.EffectiveBalanceInc {
+		t.Errorf("32 ETH bucket returned %d validators and %d Gwei, expected 2 and %d", histogram[2].Validators, histogram[2].EffectiveBalance, 64*spec.EffectiveBalanceInc)
+	}
+	if histogram[4].Validators != 1 || histogram[4].Epoch != 10 {
+		t.Errorf("128 ETH bucket returned %d validators at epoch %d, expected 1 at epoch 10", histogram[4].Validators, histogram[4].Epoch)
+	}
+}
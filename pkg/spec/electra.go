@@ -0,0 +1,83 @@
+package spec
+
+import (
+	"bytes"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// WhistleBlowerRewardQuotientAt returns the quotient of the effective balance of a slashed validator
+// rewarded to the whistleblower at the given fork
+func WhistleBlowerRewardQuotientAt(version spec.DataVersion) phase0.Gwei {
+	if version >= spec.DataVersionElectra {
+		return WhistleBlowerRewardQuotientElectra
+	}
+	return WhistleBlowerRewardQuotient
+}
+
+// ExpandElectraAttestations splits each Electra attestation of the block, which aggregates
+// the votes of several committees, into one attestation per committee, so that they can be processed as in previous forks.
+// Committees are read from the given duties, which must cover the attested slots. Blocks are shared by consecutive
+// state transitions, the attestations of a block are expanded once, the first time it is asked to
+func (p *AgnosticBlock) ExpandElectraAttestations(duties ...EpochDuties) {
+	if len(p.ElectraAttestations) == 0 {
+		return // nothing to expand
+	}
+	if p.expandOnce == nil { // not built by NewElectraBlock, not shared
+		p.expandAttestations(duties)
+		return
+	}
+	p.expandOnce.Do(func() { p.expandAttestations(duties) })
+}
+
+func (p *AgnosticBlock) expandAttestations(duties []EpochDuties) {
+	if len(p.Attestations) > 0 {
+		return // already expanded
+	}
+	for _, attestation := range p.ElectraAttestations {
+		offset := uint64(0)
+		for _, committeeIndex := range attestation.CommitteeBits.BitIndices() {
+			var committee []phase0.ValidatorIndex
+			for _, epochDuties := range duties {
+				if committee = epochDuties.GetValList(attestation.Data.Slot, phase0.CommitteeIndex(committeeIndex)); committee != nil {
+					break
+				}
+			}
+			if committee == nil {
+				log.Errorf("could not find committee %d at slot %d to expand attestation in block %d",
+					committeeIndex, attestation.Data.Slot, p.Slot)
+				break // the offset of the next committees is unknown
+			}
+
+			aggregationBits := bitfield.NewBitlist(uint64(len(committee)))
+			for i := uint64(0); i < uint64(len(committee)); i++ {
+				aggregationBits.SetBitAt(i, attestation.AggregationBits.BitAt(offset+i))
+			}
+			offset += uint64(len(committee))
+
+			data := *attestation.Data
+			data.Index = phase0.CommitteeIndex(committeeIndex)
+			p.Attestations = append(p.Attestations, &phase0.Attestation{
+				AggregationBits: aggregationBits,
+				Data:            &data,
+				Signature:       attestation.Signature,
+			})
+		}
+	}
+}
+
+// HasCompoundingCredentials returns whether the validator withdraws to an execution address
+// and its balance compounds up to MaxEffectiveIncElectra
+func HasCompoundingCredentials(validator *phase0.Validator) bool {
+	return bytes.HasPrefix(validator.WithdrawalCredentials, []byte{CompoundingWithdrawalPrefix})
+}
+
+// MaxEffectiveBalance returns the maximum effective balance the validator can reach
+func MaxEffectiveBalance(validator *phase0.Validator) phase0.Gwei {
+	if HasCompoundingCredentials(validator) {
+		return phase0.Gwei(MaxEffectiveIncElectra * EffectiveBalanceInc)
+	}
+	return phase0.Gwei(MaxEffectiveInc * EffectiveBalanceInc)
+}
@@ -0,0 +1,76 @@
+package spec_test
+
+import (
+	"testing"
+
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestExpandElectraAttestations(t *testing.T) {
+	duties := spec.EpochDuties{
+		BeaconCommittees: []*api.BeaconCommittee{
+			{Slot: 64, Index: 0, Validators: []phase0.ValidatorIndex{1, 2, 3}},
+			{Slot: 64, Index: 1, Validators: []phase0.ValidatorIndex{4, 5}},
+			{Slot: 64, Index: 2, Validators: []phase0.ValidatorIndex{6, 7, 8, 9}},
+		},
+	}
+
+	// committees 0 and 2 aggregated: bits 0-2 belong to committee 0 and 3-6 to committee 2
+	aggregationBits := bitfield.NewBitlist(7)
+	aggregationBits.SetBitAt(1, true)
+	aggregationBits.SetBitAt(3, true)
+	aggregationBits.SetBitAt(6, true)
+	committeeBits := bitfield.NewBitvector64()
+	committeeBits.SetBitAt(0, true)
+	committeeBits.SetBitAt(2, true)
+
+	block := &spec.AgnosticBlock{
+		Slot: 65,
+		ElectraAttestations: []*electra.Attestation{{
+			AggregationBits: aggregationBits,
+			Data:            &phase0.AttestationData{Slot: 64},
+			CommitteeBits:   committeeBits,
+		}},
+	}
+	block.ExpandElectraAttestations(duties)
+	block.ExpandElectraAttestations(duties) // expanding twice must not duplicate them
+
+	tests := []struct {
+		committee phase0.CommitteeIndex
+		length    uint64
+		bits      []int
+	}{
+		{committee: 0, length: 3, bits: []int{1}},
+		{committee: 2, length: 4, bits: []int{0, 3}},
+	}
+
+	if len(block.Attestations) != len(tests) {
+		t.Fatalf("ExpandElectraAttestations returned %d attestations, expected %d", len(block.Attestations), len(tests))
+	}
+	for i, test := range tests {
+		attestation := block.Attestations[i]
+		if attestation.Data.Index != test.committee {
+			t.Errorf("attestation %d has committee %d, expected %d", i, attestation.Data.Index, test.committee)
+		}
+		if attestation.AggregationBits.Len() != test.length {
+			t.Errorf("attestation %d has %d bits, expected %d", i, attestation.AggregationBits.Len(), test.length)
+		}
+		bits := attestation.AggregationBits.BitIndices()
+		if len(bits) != len(test.bits) {
+			t.Errorf("attestation %d has bits %v, expected %v", i, bits, test.bits)
+			continue
+		}
+		for j := range bits {
+			if bits[j] != test.bits[j] {
+				t.Errorf("attestation %d has bits %v, expected %v", i, bits, test.bits)
+			}
+		}
+	}
+	if block.NumAttestations() != 1 {
+		t.Errorf("NumAttestations returned %d, expected %d", block.NumAttestations(), 1)
+	}
+}
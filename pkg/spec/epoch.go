@@ -16,6 +16,7 @@ type Epoch struct {
 	SourceAttEffectiveBalance  phase0.Gwei
 	TargetAttEffectiveBalance  phase0.Gwei
 	HeadAttEffectiveBalance    phase0.Gwei
+	AnyAttEffectiveBalance     phase0.Gwei // validators with at least one flag, the union of the three above
 	TotalEffectiveBalance      phase0.Gwei
 	MissingSource              int
 	MissingTarget              int
@@ -32,6 +33,31 @@ type Epoch struct {
 	TotalWithdrawalsAmount     phase0.Gwei
 	NewProposerSlashings       int
 	NewAttesterSlashings       int
+	RewardsMedian              int64 // distribution of the rewards of active validators in the transition to the next epoch
+	RewardsP5                  int64
+	RewardsP95                 int64
+	RewardsGini                float64
+	NumVotesSeen               int     // votes of the epoch seen through attestation events, 0 if not followed
+	VoteLatencyMean            float64 // ms between the slot start and the first time each vote was seen
+	VoteLatencyP95             int64
+	CommitteeCount             int     // beacon committees of the epoch
+	AvgCommitteeSize           float64 // validators per committee
+	TargetCommitteeSize        int
+	AttesterSeed               phase0.Root // seed of the committee shuffling
+	BlobsNum                   int         // blobs included in the blocks of the epoch
+	AvgBlobsPerBlock           float64
+	BlobGasUsed                uint64
+	TargetBlobGas              uint64  // target blob gas of the proposed blocks
+	BlobUtilization            float64 // blob gas used over the target, in %
+	DepositsIncluded           int     // operations included in the blocks of the epoch
+	VoluntaryExitsIncluded     int
+	AttesterSlashingsIncluded  int
+	ProposerSlashingsIncluded  int
+	DepositRequestsIncluded    int // execution layer requests included in the blocks of the epoch (since Electra)
+	ExitRequestsIncluded       int
+	PartialWithdrawalsIncluded int
+	ConsolidationsIncluded     int
+	StateSubstituted           bool // the metrics were computed from the state of another slot, see DataQualityNote
 }
 
 func (f Epoch) Type() ModelType {
@@ -0,0 +1,59 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// EpochCheckpoints contains the justification and finalization status of the chain
+// as seen by the state at the end of the epoch
+type EpochCheckpoints struct {
+	Epoch                       phase0.Epoch
+	Slot                        phase0.Slot
+	JustificationBits           uint8 // bit 0 is the current epoch, bit 3 three epochs before
+	PreviousJustifiedCheckpoint phase0.Checkpoint
+	CurrentJustifiedCheckpoint  phase0.Checkpoint
+	FinalizedCheckpoint         phase0.Checkpoint
+}
+
+func NewEpochCheckpoints(state AgnosticState) EpochCheckpoints {
+	justificationBits := uint8(0)
+	if len(state.JustificationBits) > 0 {
+		justificationBits = state.JustificationBits[0]
+	}
+	return EpochCheckpoints{
+		Epoch:                       state.Epoch,
+		Slot:                        state.Slot,
+		JustificationBits:           justificationBits,
+		PreviousJustifiedCheckpoint: state.PreviousJustifiedCheckpoint,
+		CurrentJustifiedCheckpoint:  state.CurrentJustifiedCheckpoint,
+		FinalizedCheckpoint:         state.FinalizedCheckpoint,
+	}
+}
+
+// FinalityDelay returns the number of epochs since the last finalized checkpoint
+func (f EpochCheckpoints) FinalityDelay() uint64 {
+	if f.FinalizedCheckpoint.Epoch > f.Epoch {
+		return 0
+	}
+	return uint64(f.Epoch - f.FinalizedCheckpoint.Epoch)
+}
+
+func (f EpochCheckpoints) Type() ModelType {
+	return EpochCheckpointsModel
+}
+
+func (f EpochCheckpoints) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Epoch,
+		f.Slot,
+		f.JustificationBits,
+		f.PreviousJustifiedCheckpoint.Epoch,
+		f.PreviousJustifiedCheckpoint.Root,
+		f.CurrentJustifiedCheckpoint.Epoch,
+		f.CurrentJustifiedCheckpoint.Root,
+		f.FinalizedCheckpoint.Epoch,
+		f.FinalizedCheckpoint.Root,
+		f.FinalityDelay(),
+	}
+	return rows
+}
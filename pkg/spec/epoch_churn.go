@@ -0,0 +1,151 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// EpochChurn is the activation, exit and consolidation churn consumed at an epoch since Electra, where all of them
+// are a balance, and the limit of each per epoch
+type EpochChurn struct {
+	Epoch                   phase0.Epoch
+	ChurnLimit              phase0.Gwei // activation and exit churn limits per epoch, they are the same
+	ActivationChurn         phase0.Gwei // balance of the pending deposits processed in the transition to the epoch
+	ExitChurn               phase0.Gwei // balance requested to exit in the epoch: full exits and partial withdrawal requests
+	ConsolidationChurnLimit phase0.Gwei // consolidation churn limit per epoch
+	ConsolidationChurn      phase0.Gwei // effective balance of the consolidation sources that started to exit in the epoch
+	PendingDeposits         phase0.Gwei // balance of the deposits still waiting at the end of the epoch
+	ExitQueueEpochs         uint64      // epochs between the earliest exit epoch and the first one an exit could get
+}
+
+// ActivationUtilization returns the activation churn consumed over its limit, in %. The deposit balance
+// left over from previous epochs is also processed, so it may be over 100
+func (c EpochChurn) ActivationUtilization() float64 {
+	if c.ChurnLimit == 0 {
+		return 0
+	}
+	return float64(c.ActivationChurn) / float64(c.ChurnLimit) * 100
+}
+
+// ExitUtilization returns the exit churn requested over its limit, in %. Over 100 the exits are queued
+// to the next epochs
+func (c EpochChurn) ExitUtilization() float64 {
+	if c.ChurnLimit == 0 {
+		return 0
+	}
+	return float64(c.ExitChurn) / float64(c.ChurnLimit) * 100
+}
+
+// ConsolidationUtilization returns the consolidation churn requested over its limit, in %. Over 100 the
+// consolidations are queued to the next epochs
+func (c EpochChurn) ConsolidationUtilization() float64 {
+	if c.ConsolidationChurnLimit == 0 {
+		return 0
+	}
+	return float64(c.ConsolidationChurn) / float64(c.ConsolidationChurnLimit) * 100
+}
+
+func (c EpochChurn) Type() ModelType {
+	return EpochChurnModel
+}
+
+type pendingDepositKey struct {
+	pubkey    phase0.BLSPubKey
+	amount    phase0.Gwei
+	signature phase0.BLSSignature
+	slot      phase0.Slot
+}
+
+func newPendingDepositKey(deposit *electra.PendingDeposit) pendingDepositKey {
+	return pendingDepositKey{
+		pubkey:    deposit.Pubkey,
+		amount:    deposit.Amount,
+		signature: deposit.Signature,
+		slot:      deposit.Slot,
+	}
+}
+
+type pendingPartialWithdrawalKey struct {
+	index             phase0.ValidatorIndex
+	amount            phase0.Gwei
+	withdrawableEpoch phase0.Epoch
+}
+
+func newPendingPartialWithdrawalKey(withdrawal *electra.PendingPartialWithdrawal) pendingPartialWithdrawalKey {
+	return pendingPartialWithdrawalKey{
+		index:             withdrawal.ValidatorIndex,
+		amount:            withdrawal.Amount,
+		withdrawableEpoch: withdrawal.WithdrawableEpoch,
+	}
+}
+
+// NewEpochChurn returns the churn consumed at the epoch of the state, from the previous one. The deposits processed
+// are the ones at the front of the previous queue missing in the new one, so the deposits postponed to the end of the
+// queue are not counted. As in compute_exit_epoch_and_update_churn, the exit churn is the effective balance of the
+// validators that initiated their exit and the amount of the new partial withdrawal requests, while the consolidation
+// sources are counted in the consolidation churn, as in compute_consolidation_epoch_and_update_churn.
+// It returns false before Electra
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-compute_exit_epoch_and_update_churn
+func NewEpochChurn(prevState *AgnosticState, state *AgnosticState) (EpochChurn, bool) {
+	if prevState == nil || prevState.Version < spec.DataVersionElectra || state.Version < spec.DataVersionElectra {
+		return EpochChurn{}, false
+	}
+	churn := EpochChurn{
+		Epoch:                   state.Epoch,
+		ChurnLimit:              ActivationExitChurnLimit(state.TotalActiveBalance),
+		ConsolidationChurnLimit: ConsolidationChurnLimit(state.TotalActiveBalance),
+	}
+
+	remaining := make(map[pendingDepositKey]int, len(state.PendingDeposits))
+	for _, deposit := range state.PendingDeposits {
+		remaining[newPendingDepositKey(deposit)]++
+		churn.PendingDeposits += deposit.Amount
+	}
+	for _, deposit := range prevState.PendingDeposits {
+		key := newPendingDepositKey(deposit)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		churn.ActivationChurn += deposit.Amount
+	}
+
+	// the sources exit once their consolidation is processed, so the new ones are still pending
+	consolidating := make(map[phase0.ValidatorIndex]bool, len(state.PendingConsolidations))
+	for _, consolidation := range state.PendingConsolidations {
+		consolidating[consolidation.SourceIndex] = true
+	}
+	for i, validator := range state.Validators {
+		if validator.ExitEpoch == farFutureEpoch {
+			continue
+		}
+		if i < len(prevState.Validators) && prevState.Validators[i].ExitEpoch != farFutureEpoch {
+			continue
+		}
+		if consolidating[phase0.ValidatorIndex(i)] {
+			churn.ConsolidationChurn += validator.EffectiveBalance
+		} else {
+			churn.ExitChurn += validator.EffectiveBalance
+		}
+	}
+
+	// the requests are appended to the queue, the withdrawals processed are taken from its front
+	previous := make(map[pendingPartialWithdrawalKey]int, len(prevState.PendingPartialWithdrawals))
+	for _, withdrawal := range prevState.PendingPartialWithdrawals {
+		previous[newPendingPartialWithdrawalKey(withdrawal)]++
+	}
+	for _, withdrawal := range state.PendingPartialWithdrawals {
+		key := newPendingPartialWithdrawalKey(withdrawal)
+		if previous[key] > 0 {
+			previous[key]--
+			continue
+		}
+		churn.ExitChurn += withdrawal.Amount
+	}
+
+	if firstExitEpoch := activationExitEpoch(state.Epoch); state.EarliestExitEpoch > firstExitEpoch {
+		churn.ExitQueueEpochs = uint64(state.EarliestExitEpoch - firstExitEpoch)
+	}
+	return churn, true
+}
@@ -0,0 +1,93 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestNewEpochChurn(t *testing.T) {
+	deposit := func(pubkey byte, amount phase0.Gwei) *electra.PendingDeposit {
+		return &electra.PendingDeposit{Pubkey: phase0.BLSPubKey{pubkey}, Amount: amount, Slot: 100}
+	}
+	postponed := deposit(3, 32*local_spec.EffectiveBalanceInc)
+	prevState := &local_spec.AgnosticState{
+		Version: spec.DataVersionElectra,
+		Epoch:   1000,
+		Validators: []*phase0.Validator{
+			{EffectiveBalance: 32 * local_spec.EffectiveBalanceInc, ExitEpoch: farFuture},
+			{EffectiveBalance: 2048 * local_spec.EffectiveBalanceInc, ExitEpoch: farFuture},
+			{EffectiveBalance: 32 * local_spec.EffectiveBalanceInc, ExitEpoch: 1004},
+			{EffectiveBalance: 64 * local_spec.EffectiveBalanceInc, ExitEpoch: farFuture},
+		},
+		PendingPartialWithdrawals: []*electra.PendingPartialWithdrawal{
+			{ValidatorIndex: 0, Amount: 5 * local_spec.EffectiveBalanceInc, WithdrawableEpoch: 1000},
+		},
+		PendingDeposits: []*electra.PendingDeposit{
+			deposit(1, 32*local_spec.EffectiveBalanceInc),
+			deposit(2, 64*local_spec.EffectiveBalanceInc),
+			postponed,
+			deposit(4, 1*local_spec.EffectiveBalanceInc),
+		},
+	}
+	state := &local_spec.AgnosticState{
+		Version:            spec.DataVersionElectra,
+		Epoch:              1001,
+		TotalActiveBalance: 32_000_000 * local_spec.EffectiveBalanceInc, // 488 ETH of balance churn, capped at 256
+		Validators: []*phase0.Validator{
+			{EffectiveBalance: 32 * local_spec.EffectiveBalanceInc, ExitEpoch: farFuture},
+			{EffectiveBalance: 2048 * local_spec.EffectiveBalanceInc, ExitEpoch: 1014}, // exit requested
+			{EffectiveBalance: 32 * local_spec.EffectiveBalanceInc, ExitEpoch: 1004},
+			{EffectiveBalance: 64 * local_spec.EffectiveBalanceInc, ExitEpoch: 1006}, // consolidation source
+		},
+		PendingConsolidations: []*electra.PendingConsolidation{
+			{SourceIndex: 3, TargetIndex: 0},
+		},
+		PendingPartialWithdrawals: []*electra.PendingPartialWithdrawal{ // the previous one was processed
+			{ValidatorIndex: 0, Amount: 3 * local_spec.EffectiveBalanceInc, WithdrawableEpoch: 1270},
+		},
+		PendingDeposits: []*electra.PendingDeposit{
+			deposit(4, 1*local_spec.EffectiveBalanceInc),
+			postponed,
+			deposit(5, 16*local_spec.EffectiveBalanceInc), // new
+		},
+		EarliestExitEpoch: 1014,
+	}
+
+	churn, ok := local_spec.NewEpochChurn(prevState, state)
+	if !ok {
+		t.Fatalf("NewEpochChurn returned no churn for Electra states")
+	}
+	if churn.ChurnLimit != local_spec.MaxPerEpochActivationExitChurnLimit {
+		t.Errorf("NewEpochChurn returned churn limit %d, expected %d", churn.ChurnLimit, phase0.Gwei(local_spec.MaxPerEpochActivationExitChurnLimit))
+	}
+	if churn.ActivationChurn != 96*local_spec.EffectiveBalanceInc {
+		t.Errorf("NewEpochChurn returned activation churn %d, expected %d", churn.ActivationChurn, 96*local_spec.EffectiveBalanceInc)
+	}
+	// the exit and the partial withdrawal request, not the consolidation source
+	if churn.ExitChurn != 2051*local_spec.EffectiveBalanceInc {
+		t.Errorf("NewEpochChurn returned exit churn %d, expected %d", churn.ExitChurn, 2051*local_spec.EffectiveBalanceInc)
+	}
+	if churn.ConsolidationChurnLimit != 232*local_spec.EffectiveBalanceInc || churn.ConsolidationChurn != 64*local_spec.EffectiveBalanceInc {
+		t.Errorf("NewEpochChurn returned consolidation churn %d of %d, expected %d of %d", churn.ConsolidationChurn,
+			churn.ConsolidationChurnLimit, 64*local_spec.EffectiveBalanceInc, 232*local_spec.EffectiveBalanceInc)
+	}
+	if churn.PendingDeposits != 49*local_spec.EffectiveBalanceInc || churn.ExitQueueEpochs != 8 {
+		t.Errorf("NewEpochChurn returned pending deposits %d and exit queue %d epochs, expected %d and 8",
+			churn.PendingDeposits, churn.ExitQueueEpochs, 49*local_spec.EffectiveBalanceInc)
+	}
+	if utilization := churn.ActivationUtilization(); utilization != 37.5 {
+		t.Errorf("ActivationUtilization returned %f, expected 37.5", utilization)
+	}
+	if utilization := churn.ExitUtilization(); utilization != 801.171875 {
+		t.Errorf("ExitUtilization returned %f, expected 801.171875", utilization)
+	}
+
+	prevState.Version = spec.DataVersionDeneb
+	if _, ok := local_spec.NewEpochChurn(prevState, state); ok {
+		t.Errorf("NewEpochChurn returned churn for a Deneb state")
+	}
+}
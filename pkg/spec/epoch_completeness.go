@@ -0,0 +1,37 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// MetricFamily is a group of metrics persisted together for every epoch
+type MetricFamily string
+
+const (
+	BlocksFamily       MetricFamily = "blocks"
+	RewardsFamily      MetricFamily = "rewards"
+	TransactionsFamily MetricFamily = "transactions"
+	WithdrawalsFamily  MetricFamily = "withdrawals"
+	BlobsFamily        MetricFamily = "blobs"
+)
+
+// EpochCompleteness tells whether a metric family was persisted without errors for the whole epoch.
+// Families of disabled metrics are not recorded
+type EpochCompleteness struct {
+	Epoch    phase0.Epoch
+	Family   MetricFamily
+	Complete bool
+}
+
+func (f EpochCompleteness) Type() ModelType {
+	return EpochCompletenessModel
+}
+
+func (f EpochCompleteness) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Epoch,
+		f.Family,
+		f.Complete,
+	}
+	return rows
+}
@@ -0,0 +1,78 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// EpochRandao contains the randao mix at the end of the epoch and how much the last proposers of the epoch could influence it.
+// The mix decides the proposers of ProposersEpoch, which are known from the end of the epoch (one epoch of lookahead).
+// The last revealer can withhold its block to choose between two mixes, and a proposer with the last k proposed slots
+// between 2^k, so long tails of the same proposer or of missed slots point to lookahead or randao manipulation
+type EpochRandao struct {
+	Epoch              phase0.Epoch
+	Slot               phase0.Slot
+	RandaoMix          phase0.Root
+	ProposersEpoch     phase0.Epoch          // epoch whose proposers are decided by the mix
+	LastRevealSlot     phase0.Slot           // last proposed slot of the epoch, 0 if none
+	LastRevealProposer phase0.ValidatorIndex // proposer of the last proposed slot
+	TailMissedSlots    uint64                // consecutive missed slots at the end of the epoch
+	TailProposerSlots  uint64                // proposed slots at the end of the epoch by the last revealer, until another proposer
+}
+
+func NewEpochRandao(state AgnosticState) EpochRandao {
+	randao := EpochRandao{
+		Epoch:          state.Epoch,
+		Slot:           state.Slot,
+		RandaoMix:      state.RandaoMix(),
+		ProposersEpoch: state.Epoch + MinSeedLookahead + 1,
+	}
+
+	missed := make(map[phase0.Slot]bool, len(state.MissedBlocks))
+	for _, slot := range state.MissedBlocks {
+		missed[slot] = true
+	}
+	proposers := make(map[phase0.Slot]phase0.ValidatorIndex, len(state.EpochStructs.ProposerDuties))
+	for _, duty := range state.EpochStructs.ProposerDuties {
+		proposers[duty.Slot] = duty.ValidatorIndex
+	}
+
+	firstSlot := phase0.Slot(state.Epoch * SlotsPerEpoch)
+	revealed := false
+	for i := SlotsPerEpoch - 1; i >= 0; i-- {
+		slot := firstSlot + phase0.Slot(i)
+		if missed[slot] {
+			if !revealed {
+				randao.TailMissedSlots++
+			}
+			continue
+		}
+		if !revealed {
+			revealed = true
+			randao.LastRevealSlot = slot
+			randao.LastRevealProposer = proposers[slot]
+		}
+		if proposers[slot] != randao.LastRevealProposer {
+			break
+		}
+		randao.TailProposerSlots++
+	}
+	return randao
+}
+
+func (f EpochRandao) Type() ModelType {
+	return EpochRandaoModel
+}
+
+func (f EpochRandao) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Epoch,
+		f.Slot,
+		f.RandaoMix,
+		f.ProposersEpoch,
+		f.LastRevealSlot,
+		f.LastRevealProposer,
+		f.TailMissedSlots,
+		f.TailProposerSlots,
+	}
+	return rows
+}
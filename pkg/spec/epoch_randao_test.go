@@ -0,0 +1,84 @@
+package spec_test
+
+import (
+	"testing"
+
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// randaoState returns a state of epoch 10 whose slots are proposed by the given validators, in slot order
+func randaoState(proposers []phase0.ValidatorIndex, missed []phase0.Slot) spec.AgnosticState {
+	duties := make([]*api.ProposerDuty, 0, len(proposers))
+	for i, proposer := range proposers {
+		duties = append(duties, &api.ProposerDuty{Slot: phase0.Slot(320 + i), ValidatorIndex: proposer})
+	}
+	mixes := make([]phase0.Root, 16)
+	mixes[10] = phase0.Root{0xaa}
+	return spec.AgnosticState{
+		Epoch:        10,
+		Slot:         351,
+		RandaoMixes:  mixes,
+		MissedBlocks: missed,
+		EpochStructs: spec.EpochDuties{ProposerDuties: duties},
+	}
+}
+
+func TestNewEpochRandao(t *testing.T) {
+	proposers := make([]phase0.ValidatorIndex, spec.SlotsPerEpoch)
+	for i := range proposers {
+		proposers[i] = phase0.ValidatorIndex(i)
+	}
+	proposers[29], proposers[30], proposers[31] = 7, 7, 7
+
+	tests := []struct {
+		name              string
+		missed            []phase0.Slot
+		lastRevealSlot    phase0.Slot
+		lastProposer      phase0.ValidatorIndex
+		tailMissedSlots   uint64
+		tailProposerSlots uint64
+	}{
+		{
+			name:              "same proposer tail",
+			missed:            []phase0.Slot{},
+			lastRevealSlot:    351,
+			lastProposer:      7,
+			tailMissedSlots:   0,
+			tailProposerSlots: 3,
+		},
+		{
+			name:              "missed tail",
+			missed:            []phase0.Slot{350, 351},
+			lastRevealSlot:    349,
+			lastProposer:      7,
+			tailMissedSlots:   2,
+			tailProposerSlots: 1,
+		},
+		{
+			name:              "missed slot inside the tail",
+			missed:            []phase0.Slot{327, 350},
+			lastRevealSlot:    351,
+			lastProposer:      7,
+			tailMissedSlots:   0,
+			tailProposerSlots: 2,
+		},
+	}
+
+	for _, test := range tests {
+		randao := spec.NewEpochRandao(randaoState(proposers, test.missed))
+		if randao.LastRevealSlot != test.lastRevealSlot || randao.LastRevealProposer != test.lastProposer {
+			t.Errorf("%s: NewEpochRandao returned last reveal %d by %d, expected %d by %d",
+				test.name, randao.LastRevealSlot, randao.LastRevealProposer, test.lastRevealSlot, test.lastProposer)
+		}
+		if randao.TailMissedSlots != test.tailMissedSlots || randao.TailProposerSlots != test.tailProposerSlots {
+			t.Errorf("%s: NewEpochRandao returned tail of %d missed and %d proposer slots, expected %d and %d",
+				test.name, randao.TailMissedSlots, randao.TailProposerSlots, test.tailMissedSlots, test.tailProposerSlots)
+		}
+		if randao.RandaoMix != (phase0.Root{0xaa}) || randao.ProposersEpoch != 12 {
+			t.Errorf("%s: NewEpochRandao returned mix %s for epoch %d, expected %s for epoch 12",
+				test.name, randao.RandaoMix, randao.ProposersEpoch, phase0.Root{0xaa})
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+type EquivocationType string
+
+const (
+	NoEquivocation EquivocationType = ""
+	DoubleVote     EquivocationType = "double_vote"   // two different votes for the same target epoch
+	SurroundVote   EquivocationType = "surround_vote" // one vote surrounds the other
+)
+
+// Equivocation is a pair of slashable votes of a validator, either included in an attester slashing
+// or detected by goteth comparing the votes included in the blocks
+type Equivocation struct {
+	ValidatorIndex phase0.ValidatorIndex
+	Kind           EquivocationType
+	Slot           phase0.Slot // slot of the block including the slashing or the second vote
+	Included       bool        // whether the equivocation was included as an attester slashing
+	Vote1          VoteSummary
+	Vote2          VoteSummary
+}
+
+// VoteSummary identifies an attestation vote
+type VoteSummary struct {
+	SourceEpoch phase0.Epoch
+	TargetEpoch phase0.Epoch
+	Root        phase0.Root // hash tree root of the attestation data
+}
+
+func NewVoteSummary(data *phase0.AttestationData) VoteSummary {
+	root, err := data.HashTreeRoot()
+	if err != nil {
+		log.Errorf("could not compute attestation data root: %s", err)
+	}
+	return VoteSummary{
+		SourceEpoch: data.Source.Epoch,
+		TargetEpoch: data.Target.Epoch,
+		Root:        root,
+	}
+}
+
+// ClassifyVotes returns whether the two votes are slashable together
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#is_slashable_attestation_data
+func ClassifyVotes(vote1 VoteSummary, vote2 VoteSummary) EquivocationType {
+	if vote1.TargetEpoch == vote2.TargetEpoch && vote1.Root != vote2.Root {
+		return DoubleVote
+	}
+	if (vote1.SourceEpoch < vote2.SourceEpoch && vote2.TargetEpoch < vote1.TargetEpoch) ||
+		(vote2.SourceEpoch < vote1.SourceEpoch && vote1.TargetEpoch < vote2.TargetEpoch) {
+		return SurroundVote
+	}
+	return NoEquivocation
+}
+
+// AttesterSlashingEquivocations returns one equivocation per validator slashed by the attester slashing
+func AttesterSlashingEquivocations(slashing *phase0.AttesterSlashing, slot phase0.Slot) []Equivocation {
+	equivocations := make([]Equivocation, 0)
+	if slashing.Attestation1 == nil || slashing.Attestation2 == nil {
+		return equivocations
+	}
+	vote1 := NewVoteSummary(slashing.Attestation1.Data)
+	vote2 := NewVoteSummary(slashing.Attestation2.Data)
+	equivocationType := ClassifyVotes(vote1, vote2)
+
+	for _, valIdx := range SlashingIntersection(slashing.Attestation1.AttestingIndices, slashing.Attestation2.AttestingIndices) {
+		equivocations = append(equivocations, Equivocation{
+			ValidatorIndex: valIdx,
+			Kind:           equivocationType,
+			Slot:           slot,
+			Included:       true,
+			Vote1:          vote1,
+			Vote2:          vote2,
+		})
+	}
+	return equivocations
+}
+
+func (f Equivocation) Type() ModelType {
+	return EquivocationModel
+}
+
+func (f Equivocation) ToArray() []interface{} {
+	rows := []interface{}{
+		f.ValidatorIndex,
+		f.Kind,
+		f.Slot,
+		f.Included,
+		f.Vote1.SourceEpoch,
+		f.Vote1.TargetEpoch,
+		f.Vote1.Root,
+		f.Vote2.SourceEpoch,
+		f.Vote2.TargetEpoch,
+		f.Vote2.Root,
+	}
+	return rows
+}
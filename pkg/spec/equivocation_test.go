@@ -0,0 +1,67 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestClassifyVotes(t *testing.T) {
+	vote := func(source phase0.Epoch, target phase0.Epoch, root byte) spec.VoteSummary {
+		return spec.VoteSummary{SourceEpoch: source, TargetEpoch: target, Root: phase0.Root{root}}
+	}
+
+	tests := []struct {
+		name   string
+		vote1  spec.VoteSummary
+		vote2  spec.VoteSummary
+		result spec.EquivocationType
+	}{
+		{
+			name:   "Same vote",
+			vote1:  vote(9, 10, 1),
+			vote2:  vote(9, 10, 1),
+			result: spec.NoEquivocation,
+		},
+		{
+			name:   "Consecutive votes",
+			vote1:  vote(9, 10, 1),
+			vote2:  vote(10, 11, 2),
+			result: spec.NoEquivocation,
+		},
+		{
+			name:   "Double vote",
+			vote1:  vote(9, 10, 1),
+			vote2:  vote(9, 10, 2),
+			result: spec.DoubleVote,
+		},
+		{
+			name:   "Second vote surrounds the first",
+			vote1:  vote(9, 10, 1),
+			vote2:  vote(8, 11, 2),
+			result: spec.SurroundVote,
+		},
+		{
+			name:   "First vote surrounds the second",
+			vote1:  vote(5, 12, 1),
+			vote2:  vote(9, 10, 2),
+			result: spec.SurroundVote,
+		},
+		{
+			name:   "Same source, different target",
+			vote1:  vote(9, 10, 1),
+			vote2:  vote(9, 11, 2),
+			result: spec.NoEquivocation,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := spec.ClassifyVotes(test.vote1, test.vote2)
+			if result != test.result {
+				t.Errorf("ClassifyVotes returned %q, expected %q", result, test.result)
+			}
+		})
+	}
+}
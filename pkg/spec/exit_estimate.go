@@ -0,0 +1,154 @@
+package spec
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ExitQueueValidator is a validator of the registry, with what the exit queue depends on
+type ExitQueueValidator struct {
+	Index            phase0.ValidatorIndex
+	EffectiveBalance phase0.Gwei
+	ActivationEpoch  phase0.Epoch
+	ExitEpoch        phase0.Epoch
+}
+
+// ExitQueue is the exit queue at an epoch: the epoch the next exit would be assigned to,
+// and the churn still available at it
+type ExitQueue struct {
+	Epoch        phase0.Epoch // epoch of the registry
+	Electra      bool         // the churn is a balance (gwei) since Electra, a number of validators before
+	ChurnLimit   uint64       // per epoch
+	ExitEpoch    phase0.Epoch // epoch of the last exit in the queue, or the earliest one possible
+	ChurnAtEpoch uint64       // churn left at ExitEpoch
+}
+
+// ExitEstimate is the epoch a validator would exit at, and become withdrawable, if it requested the exit at the
+// epoch of the queue. Validators already exiting keep their epochs
+type ExitEstimate struct {
+	Index             phase0.ValidatorIndex
+	ExitEpoch         phase0.Epoch
+	WithdrawableEpoch phase0.Epoch
+	Exiting           bool   // the exit was already requested
+	Error             string // why the validator cannot exit, i.e. it is not active
+}
+
+// activationExitEpoch returns the first epoch an exit (or activation) processed at the given epoch takes effect at
+func activationExitEpoch(epoch phase0.Epoch) phase0.Epoch {
+	return epoch + 1 + MaxSeedLookahead
+}
+
+// BalanceChurnLimit returns the balance churn per epoch since Electra, given the balance of the active validators
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-get_balance_churn_limit
+func BalanceChurnLimit(activeBalance phase0.Gwei) phase0.Gwei {
+	balanceChurn := max(MinPerEpochChurnLimitElectra, uint64(activeBalance)/ChurnLimitQuotient)
+	return phase0.Gwei(balanceChurn - balanceChurn%EffectiveBalanceInc)
+}
+
+// ActivationExitChurnLimit returns the balance that can be activated, and the one that can exit,
+// per epoch since Electra, given the balance of the active validators
+func ActivationExitChurnLimit(activeBalance phase0.Gwei) phase0.Gwei {
+	return min(MaxPerEpochActivationExitChurnLimit, BalanceChurnLimit(activeBalance))
+}
+
+// ConsolidationChurnLimit returns the balance of the consolidation sources that can exit per epoch since Electra,
+// the balance churn left by the activations and exits
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md#new-get_consolidation_churn_limit
+func ConsolidationChurnLimit(activeBalance phase0.Gwei) phase0.Gwei {
+	return BalanceChurnLimit(activeBalance) - ActivationExitChurnLimit(activeBalance)
+}
+
+// NewExitQueue returns the exit queue at the given epoch from the validator registry. Since Electra the churn
+// is the balance of the validators, otherwise their number
+func NewExitQueue(epoch phase0.Epoch, registry []ExitQueueValidator, electra bool) ExitQueue {
+	queue := ExitQueue{
+		Epoch:     epoch,
+		Electra:   electra,
+		ExitEpoch: activationExitEpoch(epoch),
+	}
+	activeValidators := uint64(0)
+	activeBalance := phase0.Gwei(0)
+	for _, validator := range registry {
+		if validator.ActivationEpoch <= epoch && epoch < validator.ExitEpoch {
+			activeValidators++
+			activeBalance += validator.EffectiveBalance
+		}
+		if validator.ExitEpoch != farFutureEpoch && validator.ExitEpoch > queue.ExitEpoch {
+			queue.ExitEpoch = validator.ExitEpoch
+		}
+	}
+
+	consumed := uint64(0)
+	for _, validator := range registry {
+		if validator.ExitEpoch != queue.ExitEpoch {
+			continue
+		}
+		if electra {
+			consumed += uint64(validator.EffectiveBalance)
+		} else {
+			consumed++
+		}
+	}
+
+	if electra {
+		queue.ChurnLimit = uint64(ActivationExitChurnLimit(activeBalance))
+	} else {
+		queue.ChurnLimit = max(MinPerEpochChurnLimit, activeValidators/ChurnLimitQuotient)
+	}
+	if consumed < queue.ChurnLimit {
+		queue.ChurnAtEpoch = queue.ChurnLimit - consumed
+	}
+	return queue
+}
+
+// Estimate queues the exits of the given validators, in order, returning the epochs they would exit at
+func (q *ExitQueue) Estimate(registry []ExitQueueValidator, indexes []phase0.ValidatorIndex) []ExitEstimate {
+	validators := make(map[phase0.ValidatorIndex]ExitQueueValidator, len(registry))
+	for _, validator := range registry {
+		validators[validator.Index] = validator
+	}
+
+	estimates := make([]ExitEstimate, 0, len(indexes))
+	for _, valIdx := range indexes {
+		estimate := ExitEstimate{Index: valIdx}
+		validator, ok := validators[valIdx]
+		switch {
+		case !ok:
+			estimate.Error = "validator not in the registry"
+		case validator.ExitEpoch != farFutureEpoch:
+			estimate.Exiting = true
+			estimate.ExitEpoch = validator.ExitEpoch
+			estimate.WithdrawableEpoch = validator.ExitEpoch + MinValidatorWithdrawabilityDelay
+		case validator.ActivationEpoch > q.Epoch:
+			estimate.Error = fmt.Sprintf("validator not active until epoch %d", validator.ActivationEpoch)
+		default:
+			// the exit cannot be requested before the validator was active for the shard committee period
+			requestEpoch := max(q.Epoch, validator.ActivationEpoch+ShardCommitteePeriod)
+			estimate.ExitEpoch = q.queue(requestEpoch, validator.EffectiveBalance)
+			estimate.WithdrawableEpoch = estimate.ExitEpoch + MinValidatorWithdrawabilityDelay
+		}
+		estimates = append(estimates, estimate)
+	}
+	return estimates
+}
+
+// queue assigns the exit requested at the given epoch to the first epoch with enough churn, consuming it
+func (q *ExitQueue) queue(requestEpoch phase0.Epoch, effectiveBalance phase0.Gwei) phase0.Epoch {
+	if earliest := activationExitEpoch(requestEpoch); earliest > q.ExitEpoch {
+		q.ExitEpoch = earliest
+		q.ChurnAtEpoch = q.ChurnLimit
+	}
+	exitChurn := uint64(1)
+	if q.Electra {
+		exitChurn = uint64(effectiveBalance)
+	}
+	if exitChurn > q.ChurnAtEpoch {
+		// since Electra a large balance spans several epochs of churn
+		additionalEpochs := (exitChurn-q.ChurnAtEpoch-1)/q.ChurnLimit + 1
+		q.ExitEpoch += phase0.Epoch(additionalEpochs)
+		q.ChurnAtEpoch += additionalEpochs * q.ChurnLimit
+	}
+	q.ChurnAtEpoch -= exitChurn
+	return q.ExitEpoch
+}
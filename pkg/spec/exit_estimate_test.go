@@ -0,0 +1,74 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func exitQueueRegistry(balances ...phase0.Gwei) []spec.ExitQueueValidator {
+	registry := make([]spec.ExitQueueValidator, 0, len(balances))
+	for i, balance := range balances {
+		registry = append(registry, spec.ExitQueueValidator{
+			Index:            phase0.ValidatorIndex(i),
+			EffectiveBalance: balance,
+			ExitEpoch:        farFuture,
+		})
+	}
+	return registry
+}
+
+func TestExitQueueEstimate(t *testing.T) {
+	balances := make([]phase0.Gwei, 13)
+	for i := range balances {
+		balances[i] = 32 * spec.EffectiveBalanceInc
+	}
+	registry := exitQueueRegistry(balances...)
+	registry[10].ExitEpoch = 1010       // already exiting
+	registry[11].ActivationEpoch = 2000 // not active yet
+	registry[12].ActivationEpoch = 900  // active for less than the shard committee period
+
+	queue := spec.NewExitQueue(1000, registry, false)
+	if queue.ChurnLimit != spec.MinPerEpochChurnLimit || queue.ExitEpoch != 1010 || queue.ChurnAtEpoch != 3 {
+		t.Errorf("NewExitQueue returned churn %d, exit epoch %d and churn at epoch %d, expected %d, 1010 and 3",
+			queue.ChurnLimit, queue.ExitEpoch, queue.ChurnAtEpoch, spec.MinPerEpochChurnLimit)
+	}
+
+	estimates := queue.Estimate(registry, []phase0.ValidatorIndex{0, 1, 2, 3, 10, 11, 12, 99})
+	expected := []phase0.Epoch{1010, 1010, 1010, 1011, 1010, 0, 1161, 0}
+	for i, estimate := range estimates {
+		if estimate.ExitEpoch != expected[i] {
+			t.Errorf("Estimate of validator %d returned exit epoch %d, expected %d", estimate.Index, estimate.ExitEpoch, expected[i])
+		}
+		if (estimate.Error != "") != (expected[i] == 0) {
+			t.Errorf("Estimate of validator %d returned error %q", estimate.Index, estimate.Error)
+		}
+		if estimate.Error == "" && estimate.WithdrawableEpoch != estimate.ExitEpoch+spec.MinValidatorWithdrawabilityDelay {
+			t.Errorf("Estimate of validator %d returned withdrawable epoch %d, expected %d",
+				estimate.Index, estimate.WithdrawableEpoch, estimate.ExitEpoch+spec.MinValidatorWithdrawabilityDelay)
+		}
+	}
+	if !estimates[4].Exiting {
+		t.Errorf("Estimate of validator 10 returned not exiting, expected exiting")
+	}
+}
+
+func TestExitQueueEstimateElectra(t *testing.T) {
+	registry := exitQueueRegistry(32*spec.EffectiveBalanceInc, 2048*spec.EffectiveBalanceInc, 32*spec.EffectiveBalanceInc)
+
+	queue := spec.NewExitQueue(1000, registry, true)
+	if queue.ChurnLimit != spec.MinPerEpochChurnLimitElectra || queue.ExitEpoch != 1005 {
+		t.Errorf("NewExitQueue returned churn %d and exit epoch %d, expected %d and 1005",
+			queue.ChurnLimit, queue.ExitEpoch, spec.MinPerEpochChurnLimitElectra)
+	}
+
+	// the 2048 ETH validator consumes 16 epochs of churn
+	estimates := queue.Estimate(registry, []phase0.ValidatorIndex{1, 0, 2})
+	expected := []phase0.Epoch{1020, 1021, 1021}
+	for i, estimate := range estimates {
+		if estimate.ExitEpoch != expected[i] {
+			t.Errorf("Estimate of validator %d returned exit epoch %d, expected %d", estimate.Index, estimate.ExitEpoch, expected[i])
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// forks that can be scheduled, in fork order
+var scheduledForks = []spec.DataVersion{
+	spec.DataVersionAltair,
+	spec.DataVersionBellatrix,
+	spec.DataVersionCapella,
+	spec.DataVersionDeneb,
+	spec.DataVersionElectra,
+}
+
+// ForkSchedule holds the fork epochs of a devnet by their spec key (i.e. ALTAIR_FORK_EPOCH),
+// they override the ones of the node spec
+type ForkSchedule map[string]phase0.Epoch
+
+// ParseForkSchedule reads the fork epochs given as comma separated <fork>=<epoch> (i.e. altair=0,bellatrix=10).
+// The forks must be scheduled in fork order
+func ParseForkSchedule(input string) (ForkSchedule, error) {
+	schedule := make(ForkSchedule)
+	for _, item := range strings.Split(input, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, found := strings.Cut(item, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid fork %q, expected <fork>=<epoch>", item)
+		}
+		key, ok := forkEpochKey(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown fork %q", name)
+		}
+		epoch, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid epoch of fork %s: %s", name, err)
+		}
+		schedule[key] = phase0.Epoch(epoch)
+	}
+
+	previous := ""
+	for _, version := range scheduledForks {
+		key, _ := forkEpochKey(version.String())
+		epoch, ok := schedule[key]
+		if !ok {
+			continue
+		}
+		if previous != "" && epoch < schedule[previous] {
+			return nil, fmt.Errorf("%s is scheduled before %s", key, previous)
+		}
+		previous = key
+	}
+	return schedule, nil
+}
+
+func forkEpochKey(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, version := range scheduledForks {
+		if version.String() == name {
+			return strings.ToUpper(name) + "_FORK_EPOCH", true
+		}
+	}
+	return "", false
+}
+
+// Apply returns a copy of the node spec with the fork epochs of the schedule
+func (s ForkSchedule) Apply(nodeSpec map[string]any) map[string]any {
+	if len(s) == 0 {
+		return nodeSpec
+	}
+	result := make(map[string]any, len(nodeSpec)+len(s))
+	for key, value := range nodeSpec {
+		result[key] = value
+	}
+	for key, epoch := range s {
+		result[key] = uint64(epoch)
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package spec_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestParseForkSchedule(t *testing.T) {
+	schedule, err := spec.ParseForkSchedule(" altair=0, BELLATRIX=0,capella=10,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := spec.ForkSchedule{"ALTAIR_FORK_EPOCH": 0, "BELLATRIX_FORK_EPOCH": 0, "CAPELLA_FORK_EPOCH": 10}
+	if !reflect.DeepEqual(schedule, expected) {
+		t.Errorf("expected %v, got %v", expected, schedule)
+	}
+
+	empty, err := spec.ParseForkSchedule("")
+	if err != nil || len(empty) != 0 {
+		t.Errorf("expected no forks, got %v (%v)", empty, err)
+	}
+
+	for _, input := range []string{
+		"altair",                // no epoch
+		"altair=x",              // epoch not a number
+		"phase0=0",              // not scheduled
+		"fulu=10",               // unknown fork
+		"altair=10,capella=5",   // out of order
+		"deneb=10,electra=9,x=", // out of order and invalid
+	} {
+		if _, err := spec.ParseForkSchedule(input); err == nil {
+			t.Errorf("expected an error parsing %q", input)
+		}
+	}
+}
+
+func TestForkScheduleApply(t *testing.T) {
+	nodeSpec := map[string]any{"SLOTS_PER_EPOCH": uint64(32), "ALTAIR_FORK_EPOCH": uint64(74240)}
+	schedule := spec.ForkSchedule{"ALTAIR_FORK_EPOCH": 0, "DENEB_FORK_EPOCH": 5}
+
+	result := schedule.Apply(nodeSpec)
+	expected := map[string]any{"SLOTS_PER_EPOCH": uint64(32), "ALTAIR_FORK_EPOCH": uint64(0), "DENEB_FORK_EPOCH": uint64(5)}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+	if nodeSpec["ALTAIR_FORK_EPOCH"] != uint64(74240) {
+		t.Errorf("the node spec was modified")
+	}
+	if result := spec.ForkSchedule(nil).Apply(nodeSpec); !reflect.DeepEqual(result, nodeSpec) {
+		t.Errorf("expected the node spec without a schedule, got %v", result)
+	}
+}
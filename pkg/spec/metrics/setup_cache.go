@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+const (
+	transitionStates = 3 // a state is the next one of a transition, then the current and the previous one of the two following
+)
+
+type committeeKey struct {
+	slot  phase0.Slot
+	index phase0.CommitteeIndex
+}
+
+// StateSetup holds what is derived from a single state to compute the transitions it takes part in.
+// A state takes part in three transitions, so its setup is computed once and reused
+type StateSetup struct {
+	epoch         phase0.Epoch
+	uses          int                                      // transitions that took the setup
+	committees    map[committeeKey][]phase0.ValidatorIndex // beacon committees of the epoch by slot and index
+	syncCommittee []phase0.ValidatorIndex                  // members of the current sync committee, a validator may appear several times
+}
+
+// Committee returns the validators of the given beacon committee, nil if it is not in the state epoch
+func (s *StateSetup) Committee(slot phase0.Slot, index phase0.CommitteeIndex) []phase0.ValidatorIndex {
+	if s == nil {
+		return nil
+	}
+	return s.committees[committeeKey{slot: slot, index: index}]
+}
+
+// TransitionSetups are the setups of the three states of a transition, with the blob limits and committee config of the network
+type TransitionSetups struct {
+	Prev    *StateSetup
+	Current *StateSetup
+	Next    *StateSetup
+
+	BlobSchedule    local_spec.BlobSchedule
+	CommitteeConfig local_spec.CommitteeConfig
+}
+
+// SetupCache keeps the setups of the states until the three transitions they take part in took them,
+// so consecutive transitions only set up their new (next) state. The states themselves are kept by the download cache
+type SetupCache struct {
+	mu   sync.Mutex
+	iApi *http.Service
+
+	pubkeys *local_spec.PubkeyRegistry
+	setups  map[phase0.Root]*StateSetup // by state root
+	latest  phase0.Epoch                // highest epoch transitioned to
+	window  phase0.Epoch                // epochs behind the latest after which a setup is dropped, even if not taken by all its transitions
+}
+
+// NewSetupCache returns a cache for the given number of transitions processed at a time.
+// Transitions of reorged or reprocessed states never take all their setups, they are dropped once they fall out of the window
+func NewSetupCache(iApi *http.Service, concurrentTransitions int) *SetupCache {
+	return &SetupCache{
+		iApi:    iApi,
+		pubkeys: local_spec.NewPubkeyRegistry(),
+		setups:  make(map[phase0.Root]*StateSetup),
+		window:  phase0.Epoch(max(concurrentTransitions, 1) + transitionStates),
+	}
+}
+
+// Pubkeys returns the public key index of the registry, extended with the states of the transitions
+func (p *SetupCache) Pubkeys() *local_spec.PubkeyRegistry {
+	return p.pubkeys
+}
+
+// Transition computes the metrics of the transition between the given states
+func (p *SetupCache) Transition(
+	nextState *local_spec.AgnosticState,
+	currentState *local_spec.AgnosticState,
+	prevState *local_spec.AgnosticState) (StateMetrics, error) {
+
+	setups := p.transitionSetups(nextState, currentState, prevState)
+	return stateMetricsByForkVersion(nextState, currentState, prevState, setups, p.iApi)
+}
+
+// transitionSetups takes the setups of the three states of a transition
+func (p *SetupCache) transitionSetups(
+	nextState *local_spec.AgnosticState,
+	currentState *local_spec.AgnosticState,
+	prevState *local_spec.AgnosticState) TransitionSetups {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	setups := TransitionSetups{
+		Prev:    p.take(prevState),
+		Current: p.take(currentState),
+		Next:    p.take(nextState),
+
+		BlobSchedule:    BlobScheduleFromNode(p.iApi),
+		CommitteeConfig: CommitteeConfigFromNode(p.iApi),
+	}
+	if nextState.Epoch > p.latest {
+		p.latest = nextState.Epoch
+		p.evictBefore(p.latest)
+	}
+	return setups
+}
+
+// take returns the setup of the state for a transition, dropping it from the cache once all its transitions took it
+func (p *SetupCache) take(state *local_spec.AgnosticState) *StateSetup {
+	setup := p.setup(state)
+	setup.uses++
+	if setup.uses >= transitionStates {
+		delete(p.setups, state.StateRoot)
+	}
+	return setup
+}
+
+// setup returns the setup of the state, computing it if the state was not part of a previous transition
+func (p *SetupCache) setup(state *local_spec.AgnosticState) *StateSetup {
+	if state.EmptyStateRoot() {
+		return &StateSetup{}
+	}
+	if setup, ok := p.setups[state.StateRoot]; ok {
+		return setup
+	}
+
+	setup := &StateSetup{
+		epoch:      state.Epoch,
+		committees: make(map[committeeKey][]phase0.ValidatorIndex, len(state.EpochStructs.BeaconCommittees)),
+	}
+	for _, committee := range state.EpochStructs.BeaconCommittees {
+		setup.committees[committeeKey{slot: committee.Slot, index: committee.Index}] = committee.Validators
+	}
+
+	if len(state.SyncCommittee.Pubkeys) > 0 { // since Altair
+		p.pubkeys.Add(state.Validators)
+		setup.syncCommittee = make([]phase0.ValidatorIndex, 0, len(state.SyncCommittee.Pubkeys))
+		for _, pubkey := range state.SyncCommittee.Pubkeys {
+			if valIdx, ok := p.pubkeys.ValidatorIndex(pubkey); ok {
+				setup.syncCommittee = append(setup.syncCommittee, valIdx)
+			}
+		}
+	}
+
+	p.setups[state.StateRoot] = setup
+	return setup
+}
+
+func (p *SetupCache) evictBefore(epoch phase0.Epoch) {
+	if epoch < p.window {
+		return
+	}
+	for root, setup := range p.setups {
+		if setup.epoch < epoch-p.window {
+			delete(p.setups, root)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func setupCacheStates(epochs int) []*spec.AgnosticState {
+	states := make([]*spec.AgnosticState, epochs)
+	for i := range states {
+		states[i] = &spec.AgnosticState{Epoch: phase0.Epoch(i), StateRoot: phase0.Root{byte(i + 1)}}
+	}
+	return states
+}
+
+func TestSetupCacheOutOfOrder(t *testing.T) {
+	states := setupCacheStates(10)
+	cache := NewSetupCache(nil, 8)
+
+	// historical workers reach their transitions in any order
+	taken := make(map[phase0.Epoch][]*StateSetup)
+	for _, epoch := range []int{9, 4, 7, 2, 8, 5, 3, 6} {
+		setups := cache.transitionSetups(states[epoch], states[epoch-1], states[epoch-2])
+		taken[phase0.Epoch(epoch-2)] = append(taken[phase0.Epoch(epoch-2)], setups.Prev)
+		taken[phase0.Epoch(epoch-1)] = append(taken[phase0.Epoch(epoch-1)], setups.Current)
+		taken[phase0.Epoch(epoch)] = append(taken[phase0.Epoch(epoch)], setups.Next)
+	}
+
+	for epoch, setups := range taken {
+		for _, setup := range setups[1:] {
+			if setup != setups[0] {
+				t.Errorf("the state of epoch %d was set up more than once", epoch)
+			}
+		}
+	}
+	// the states of epochs 2 to 7 took part in their three transitions
+	for _, state := range states {
+		_, cached := cache.setups[state.StateRoot]
+		if expected := state.Epoch < 2 || state.Epoch > 7; cached != expected {
+			t.Errorf("setup of epoch %d cached: %t, expected %t", state.Epoch, cached, expected)
+		}
+	}
+}
+
+func TestSetupCacheWindow(t *testing.T) {
+	states := setupCacheStates(20)
+	cache := NewSetupCache(nil, 1)
+
+	// a reorged state never takes part in its following transitions
+	cache.transitionSetups(states[5], states[4], states[3])
+	if len(cache.setups) != 3 {
+		t.Fatalf("expected the 3 setups of the transition, got %d", len(cache.setups))
+	}
+	cache.transitionSetups(states[19], states[18], states[17])
+	for _, state := range states[3:6] {
+		if _, ok := cache.setups[state.StateRoot]; ok {
+			t.Errorf("setup of epoch %d out of the window still cached", state.Epoch)
+		}
+	}
+	if len(cache.setups) != 3 {
+		t.Errorf("expected the 3 setups of the last transition, got %d", len(cache.setups))
+	}
+}
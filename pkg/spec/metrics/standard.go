@@ -26,11 +26,21 @@ type StateMetricsBase struct {
 	MaxBlockRewards    map[phase0.ValidatorIndex]phase0.Gwei // from including attestation and sync aggregates. In this case, not max reward but the actual reward
 	InclusionDelays    []int                                 // from attestation inclusion delay
 	MaxAttesterRewards map[phase0.ValidatorIndex]phase0.Gwei // rewards from attesting
+	Weights            local_spec.RewardWeights              // reward weights of the network
+	EpochRewards       []int64                               // rewards of the validators active in the current epoch, for their distribution
+
+	Setups TransitionSetups // derived from the states, shared with the rest of the transitions of the setup cache
+}
+
+// genesisTransition tells whether the transition is the one from genesis, which has no previous epoch:
+// no rewards are applied at genesis, and the previous state is a copy of the genesis one without participation
+func (s StateMetricsBase) genesisTransition() bool {
+	return s.CurrentState.Epoch == 0
 }
 
 func (p StateMetricsBase) EpochReward(valIdx phase0.ValidatorIndex) int64 {
-	if valIdx < phase0.ValidatorIndex(len(p.CurrentState.Balances)) && valIdx < phase0.ValidatorIndex(len(p.NextState.Balances)) {
-		reward := int64(p.NextState.Balances[valIdx]) - int64(p.CurrentState.Balances[valIdx])
+	if int(valIdx) < p.CurrentState.NumBalances() && int(valIdx) < p.NextState.NumBalances() {
+		reward := int64(p.NextState.Balance(valIdx)) - int64(p.CurrentState.Balance(valIdx))
 		reward += int64(p.NextState.Withdrawals[valIdx])
 		reward -= int64(p.NextState.Deposits[valIdx])
 		return reward
@@ -40,35 +50,60 @@ func (p StateMetricsBase) EpochReward(valIdx phase0.ValidatorIndex) int64 {
 
 }
 
+// addEpochReward keeps the reward of the validator for the rewards distribution, if it was active in the current epoch.
+// Called from the max rewards loop of each fork, so the validators are iterated once
+func (p *StateMetricsBase) addEpochReward(valIdx phase0.ValidatorIndex, validator *phase0.Validator) {
+	if local_spec.IsActive(*validator, p.CurrentState.Epoch) {
+		p.EpochRewards = append(p.EpochRewards, p.EpochReward(valIdx))
+	}
+}
+
 type StateMetrics interface {
 	GetMetricsBase() StateMetricsBase
+	GetRewardWeights() local_spec.RewardWeights
 	GetMaxReward(valIdx phase0.ValidatorIndex) (local_spec.ValidatorRewards, error)
 	// keep in mind that att rewards for epoch 10 can be seen at beginning of epoch 12,
 	// after state_transition
 	// https://notes.ethereum.org/@vbuterin/Sys3GLJbD#Epoch-processing
 }
 
+// StateMetricsByForkVersion computes the metrics of a single transition, setting up the three states.
+// To process consecutive epochs, use a SetupCache instead
 func StateMetricsByForkVersion(
 	nextState *local_spec.AgnosticState,
 	currentState *local_spec.AgnosticState,
 	prevState *local_spec.AgnosticState,
 	iApi *http.Service) (StateMetrics, error) {
+	return NewSetupCache(iApi, 1).Transition(nextState, currentState, prevState)
+}
+
+func stateMetricsByForkVersion(
+	nextState *local_spec.AgnosticState,
+	currentState *local_spec.AgnosticState,
+	prevState *local_spec.AgnosticState,
+	setups TransitionSetups,
+	iApi *http.Service) (StateMetrics, error) {
+	weights := RewardWeightsByForkVersion(nextState.Version, iApi)
+
 	switch nextState.Version { // rewards are written at nextState epoch
 
 	case spec.DataVersionPhase0:
-		return NewPhase0Metrics(nextState, currentState, prevState), nil
+		return NewPhase0Metrics(nextState, currentState, prevState, weights, setups), nil
 
 	case spec.DataVersionAltair:
-		return NewAltairMetrics(nextState, currentState, prevState), nil
+		return NewAltairMetrics(nextState, currentState, prevState, weights, setups), nil
 
 	case spec.DataVersionBellatrix:
-		return NewAltairMetrics(nextState, currentState, prevState), nil // We use Altair as Rewards system is the same
+		return NewAltairMetrics(nextState, currentState, prevState, weights, setups), nil // We use Altair as Rewards system is the same
 
 	case spec.DataVersionCapella:
-		return NewAltairMetrics(nextState, currentState, prevState), nil // We use Altair as Rewards system is the same
+		return NewAltairMetrics(nextState, currentState, prevState, weights, setups), nil // We use Altair as Rewards system is the same
 
 	case spec.DataVersionDeneb:
-		return NewDenebMetrics(nextState, currentState, prevState), nil
+		return NewDenebMetrics(nextState, currentState, prevState, weights, setups), nil
+
+	case spec.DataVersionElectra:
+		return NewElectraMetrics(nextState, currentState, prevState, weights, setups), nil
 	default:
 		return nil, fmt.Errorf("could not figure out the State Metrics Fork Version: %s", currentState.Version)
 	}
@@ -76,6 +111,11 @@ func StateMetricsByForkVersion(
 
 func (s StateMetricsBase) ExportToEpoch() local_spec.Epoch {
 
+	rewardsDistribution := s.RewardsDistribution()
+	committeeCount, avgCommitteeSize := s.CurrentState.EpochStructs.CommitteeSizes()
+	blobThroughput := s.CurrentState.BlobThroughput(s.Setups.BlobSchedule)
+	operations := s.CurrentState.BlockOperations()
+
 	return local_spec.Epoch{
 		Epoch:                      s.CurrentState.Epoch,
 		Slot:                       s.CurrentState.Slot,
@@ -87,6 +127,7 @@ func (s StateMetricsBase) ExportToEpoch() local_spec.Epoch {
 		SourceAttEffectiveBalance:  s.NextState.AttestingBalance[local_spec.AttSourceFlagIndex] / local_spec.EffectiveBalanceInc,
 		TargetAttEffectiveBalance:  s.NextState.AttestingBalance[local_spec.AttTargetFlagIndex] / local_spec.EffectiveBalanceInc,
 		HeadAttEffectiveBalance:    s.NextState.AttestingBalance[local_spec.AttHeadFlagIndex] / local_spec.EffectiveBalanceInc,
+		AnyAttEffectiveBalance:     s.NextState.GetAnyAttestingBalance() / local_spec.EffectiveBalanceInc,
 		TotalEffectiveBalance:      s.CurrentState.TotalActiveBalance / local_spec.EffectiveBalanceInc,
 		MissingSource:              int(s.NextState.GetMissingFlagCount(int(altair.TimelySourceFlagIndex))),
 		MissingTarget:              int(s.NextState.GetMissingFlagCount(int(altair.TimelyTargetFlagIndex))),
@@ -103,5 +144,33 @@ func (s StateMetricsBase) ExportToEpoch() local_spec.Epoch {
 		TotalWithdrawalsAmount:     s.CurrentState.TotalWithdrawalsAmount,
 		NewProposerSlashings:       int(s.CurrentState.NewProposerSlashings),
 		NewAttesterSlashings:       int(s.CurrentState.NewAttesterSlashings),
+		RewardsMedian:              rewardsDistribution.Median,
+		RewardsP5:                  rewardsDistribution.P5,
+		RewardsP95:                 rewardsDistribution.P95,
+		RewardsGini:                rewardsDistribution.Gini,
+		CommitteeCount:             committeeCount,
+		AvgCommitteeSize:           avgCommitteeSize,
+		TargetCommitteeSize:        int(s.Setups.CommitteeConfig.TargetCommitteeSize),
+		AttesterSeed:               s.CurrentState.AttesterSeed(s.Setups.CommitteeConfig),
+		BlobsNum:                   blobThroughput.BlobsNum,
+		AvgBlobsPerBlock:           blobThroughput.AvgBlobsPerBlock,
+		BlobGasUsed:                blobThroughput.BlobGasUsed,
+		TargetBlobGas:              blobThroughput.TargetBlobGas,
+		BlobUtilization:            blobThroughput.Utilization(),
+		DepositsIncluded:           operations.Deposits,
+		VoluntaryExitsIncluded:     operations.VoluntaryExits,
+		AttesterSlashingsIncluded:  operations.AttesterSlashings,
+		ProposerSlashingsIncluded:  operations.ProposerSlashings,
+		DepositRequestsIncluded:    operations.DepositRequests,
+		ExitRequestsIncluded:       operations.ExitRequests,
+		PartialWithdrawalsIncluded: operations.PartialWithdrawalRequests,
+		ConsolidationsIncluded:     operations.ConsolidationRequests,
+		StateSubstituted:           s.CurrentState.Substitution != nil || s.NextState.Substitution != nil,
 	}
 }
+
+// RewardsDistribution computes the distribution of the rewards obtained by the validators
+// active in the current epoch, between the current and the next state
+func (s StateMetricsBase) RewardsDistribution() local_spec.RewardsDistribution {
+	return local_spec.NewRewardsDistribution(s.EpochRewards)
+}
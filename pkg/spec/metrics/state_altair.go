@@ -16,11 +16,13 @@ type AltairMetrics struct {
 func NewAltairMetrics(
 	nextState *spec.AgnosticState,
 	currentState *spec.AgnosticState,
-	prevState *spec.AgnosticState) AltairMetrics {
+	prevState *spec.AgnosticState,
+	weights spec.RewardWeights,
+	setups TransitionSetups) AltairMetrics {
 
 	altairObj := AltairMetrics{}
 
-	altairObj.InitBundle(nextState, currentState, prevState)
+	altairObj.InitBundle(nextState, currentState, prevState, weights, setups)
 	altairObj.PreProcessBundle()
 
 	return altairObj
@@ -29,14 +31,19 @@ func NewAltairMetrics(
 
 func (p *AltairMetrics) InitBundle(nextState *spec.AgnosticState,
 	currentState *spec.AgnosticState,
-	prevState *spec.AgnosticState) {
+	prevState *spec.AgnosticState,
+	weights spec.RewardWeights,
+	setups TransitionSetups) {
 	p.baseMetrics.NextState = nextState
 	p.baseMetrics.CurrentState = currentState
 	p.baseMetrics.PrevState = prevState
+	p.baseMetrics.Weights = weights
+	p.baseMetrics.Setups = setups
 	p.baseMetrics.MaxBlockRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
 	p.baseMetrics.MaxSlashingRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
 	p.baseMetrics.InclusionDelays = make([]int, len(p.baseMetrics.NextState.Validators))
 	p.baseMetrics.MaxAttesterRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
+	p.baseMetrics.EpochRewards = make([]int64, 0, len(currentState.Validators))
 	p.MaxSyncCommitteeRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
 }
 
@@ -61,11 +68,8 @@ func (p AltairMetrics) GetMetricsBase() StateMetricsBase {
 func (p AltairMetrics) ProcessSyncAggregates() {
 	for _, block := range p.baseMetrics.NextState.Blocks {
 
-		totalActiveInc := p.baseMetrics.NextState.TotalActiveBalance / spec.EffectiveBalanceInc
-		totalBaseRewards := p.GetBaseRewardPerInc(p.baseMetrics.NextState.TotalActiveBalance) * totalActiveInc
-		maxParticipantRewards := totalBaseRewards * phase0.Gwei(spec.SyncRewardWeight) / phase0.Gwei(spec.WeightDenominator) / spec.SlotsPerEpoch
-		participantReward := maxParticipantRewards / phase0.Gwei(spec.SyncCommitteeSize) // this is the participantReward for a single slot
-		singleProposerSyncReward := phase0.Gwei(participantReward * spec.ProposerWeight / (spec.WeightDenominator - spec.ProposerWeight))
+		participantReward := p.syncParticipantReward()
+		singleProposerSyncReward := phase0.Gwei(participantReward * phase0.Gwei(p.baseMetrics.Weights.Proposer) / phase0.Gwei(p.baseMetrics.Weights.Denominator-p.baseMetrics.Weights.Proposer))
 		proposerSyncReward := singleProposerSyncReward * phase0.Gwei(block.SyncAggregate.SyncCommitteeBits.Count())
 
 		p.baseMetrics.MaxBlockRewards[block.ProposerIndex] += proposerSyncReward
@@ -152,7 +156,7 @@ func (p AltairMetrics) ProcessAttestations() {
 					log.Fatalf("error processing attestations at block %d: %s", block.Slot, err)
 				}
 				if epochParticipation[valIdx] == nil {
-					epochParticipation[valIdx] = make([]bool, len(spec.ParticipatingFlagsWeight))
+					epochParticipation[valIdx] = make([]bool, len(p.baseMetrics.Weights.ParticipatingFlagsWeight()))
 				}
 
 				if slotInEpoch(slot, p.baseMetrics.CurrentState.Epoch) {
@@ -164,17 +168,17 @@ func (p AltairMetrics) ProcessAttestations() {
 
 				new := false
 				if participationFlags[spec.AttSourceFlagIndex] && !epochParticipation[valIdx][spec.AttSourceFlagIndex] { // source
-					attReward += attesterBaseReward * spec.TimelySourceWeight
+					attReward += attesterBaseReward * phase0.Gwei(p.baseMetrics.Weights.TimelySource)
 					epochParticipation[valIdx][spec.AttSourceFlagIndex] = true
 					new = true
 				}
 				if participationFlags[spec.AttTargetFlagIndex] && !epochParticipation[valIdx][spec.AttTargetFlagIndex] { // target
-					attReward += attesterBaseReward * spec.TimelyTargetWeight
+					attReward += attesterBaseReward * phase0.Gwei(p.baseMetrics.Weights.TimelyTarget)
 					epochParticipation[valIdx][spec.AttTargetFlagIndex] = true
 					new = true
 				}
 				if participationFlags[spec.AttHeadFlagIndex] && !epochParticipation[valIdx][spec.AttHeadFlagIndex] { // head
-					attReward += attesterBaseReward * spec.TimelyHeadWeight
+					attReward += attesterBaseReward * phase0.Gwei(p.baseMetrics.Weights.TimelyHead)
 					epochParticipation[valIdx][spec.AttHeadFlagIndex] = true
 					new = true
 				}
@@ -185,7 +189,7 @@ func (p AltairMetrics) ProcessAttestations() {
 
 			// only process rewards for blocks in NextState
 			if block.Slot >= phase0.Slot(p.baseMetrics.NextState.Epoch)*spec.SlotsPerEpoch {
-				denominator := phase0.Gwei((spec.WeightDenominator - spec.ProposerWeight) * spec.WeightDenominator / spec.ProposerWeight)
+				denominator := phase0.Gwei((p.baseMetrics.Weights.Denominator - p.baseMetrics.Weights.Proposer) * p.baseMetrics.Weights.Denominator / p.baseMetrics.Weights.Proposer)
 				attReward = attReward / denominator
 
 				p.baseMetrics.MaxBlockRewards[block.ProposerIndex] += attReward
@@ -197,36 +201,53 @@ func (p AltairMetrics) ProcessAttestations() {
 	}
 }
 
-// So far we have computed the max sync committee proposer reward for a slot. Since the validator remains in the sync committee for the full epoch, we multiply the reward for the 32 slots in the epoch.
+// syncParticipantReward returns the reward of a sync committee member for a single slot
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/beacon-chain.md#sync-aggregate-processing
-func (p AltairMetrics) GetMaxSyncComReward() {
-
-	for _, valPubkey := range p.baseMetrics.NextState.SyncCommittee.Pubkeys {
-
-		for valIdx, validator := range p.baseMetrics.NextState.Validators {
+func (p AltairMetrics) syncParticipantReward() phase0.Gwei {
+	totalActiveInc := p.baseMetrics.NextState.TotalActiveBalance / spec.EffectiveBalanceInc
+	totalBaseRewards := p.GetBaseRewardPerInc(p.baseMetrics.NextState.TotalActiveBalance) * totalActiveInc
+	maxParticipantRewards := totalBaseRewards * phase0.Gwei(p.baseMetrics.Weights.SyncReward) / phase0.Gwei(p.baseMetrics.Weights.Denominator) / spec.SlotsPerEpoch
+	return maxParticipantRewards / phase0.Gwei(spec.SyncCommitteeSize)
+}
 
-			if valPubkey == validator.PublicKey { // hit, one validator can be multiple times in the same committee
-				// at this point we know the validator was inside the sync committee and, therefore, active at that point
+// syncRewardedSlots returns the slots of the epoch whose block was proposed, the only ones that reward the sync committee.
+// The blocks of the epoch tell every slot, also the ones after a state taken before the end of the epoch;
+// the missed blocks of the state are used when the blocks are not attached
+func (p AltairMetrics) syncRewardedSlots() int {
+	if len(p.baseMetrics.NextState.Blocks) == 0 {
+		return spec.SlotsPerEpoch - len(p.baseMetrics.NextState.MissedBlocks)
+	}
+	proposed := 0
+	for _, block := range p.baseMetrics.NextState.Blocks {
+		if block.Proposed && spec.EpochAtSlot(block.Slot) == p.baseMetrics.NextState.Epoch {
+			proposed++
+		}
+	}
+	return proposed
+}
 
-				reward := phase0.Gwei(0)
-				totalActiveInc := p.baseMetrics.NextState.TotalActiveBalance / spec.EffectiveBalanceInc
-				totalBaseRewards := p.GetBaseRewardPerInc(p.baseMetrics.NextState.TotalActiveBalance) * totalActiveInc
-				maxParticipantRewards := totalBaseRewards * phase0.Gwei(spec.SyncRewardWeight) / phase0.Gwei(spec.WeightDenominator) / spec.SlotsPerEpoch
-				participantReward := maxParticipantRewards / phase0.Gwei(spec.SyncCommitteeSize) // this is the participantReward for a single slot
+// So far we have computed the max sync committee proposer reward for a slot. Since the validator remains in the sync committee for the full epoch,
+// we multiply the reward for the slots of the epoch with a block proposed, as a missed block rewards no participant.
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/beacon-chain.md#sync-aggregate-processing
+func (p AltairMetrics) GetMaxSyncComReward() {
 
-				reward += participantReward * phase0.Gwei(spec.SlotsPerEpoch-len(p.baseMetrics.NextState.MissedBlocks)) // max reward would be 32 perfect slots
-				p.MaxSyncCommitteeRewards[phase0.ValidatorIndex(valIdx)] += reward
-			}
-		}
+	reward := p.syncParticipantReward() * phase0.Gwei(p.syncRewardedSlots())
 
+	// one validator can be multiple times in the same committee
+	// at this point we know the validator was inside the sync committee and, therefore, active at that point
+	for _, valIdx := range p.baseMetrics.Setups.Next.syncCommittee {
+		p.MaxSyncCommitteeRewards[valIdx] += reward
 	}
 
 }
 
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/beacon-chain.md#get_flag_index_deltas
-func (p AltairMetrics) GetMaxFlagIndexDeltas() {
-
+func (p *AltairMetrics) GetMaxFlagIndexDeltas() {
+	if p.baseMetrics.genesisTransition() {
+		return
+	}
 	for valIdx, validator := range p.baseMetrics.NextState.Validators {
+		p.baseMetrics.addEpochReward(phase0.ValidatorIndex(valIdx), validator)
 		maxFlagsReward := phase0.Gwei(0)
 		// the maxReward would be each flag_index_weight * base_reward * (attesting_balance_inc / total_active_balance_inc) / WEIGHT_DENOMINATOR
 
@@ -242,8 +263,8 @@ func (p AltairMetrics) GetMaxFlagIndexDeltas() {
 				// apply formula
 				attestingBalanceInc := p.baseMetrics.CurrentState.AttestingBalance[i] / spec.EffectiveBalanceInc
 
-				flagReward := phase0.Gwei(spec.ParticipatingFlagsWeight[i]) * baseReward * attestingBalanceInc
-				flagReward = flagReward / ((phase0.Gwei(p.baseMetrics.CurrentState.TotalActiveBalance / spec.EffectiveBalanceInc)) * phase0.Gwei(spec.WeightDenominator))
+				flagReward := phase0.Gwei(p.baseMetrics.Weights.ParticipatingFlagsWeight()[i]) * baseReward * attestingBalanceInc
+				flagReward = flagReward / ((phase0.Gwei(p.baseMetrics.CurrentState.TotalActiveBalance / spec.EffectiveBalanceInc)) * phase0.Gwei(p.baseMetrics.Weights.Denominator))
 				maxFlagsReward += flagReward
 			}
 		}
@@ -294,7 +315,7 @@ func (p AltairMetrics) GetMaxReward(valIdx phase0.ValidatorIndex) (spec.Validato
 	result := spec.ValidatorRewards{
 		ValidatorIndex:       valIdx,
 		Epoch:                p.baseMetrics.NextState.Epoch,
-		ValidatorBalance:     p.baseMetrics.NextState.Balances[valIdx],
+		ValidatorBalance:     p.baseMetrics.NextState.Balance(valIdx),
 		Reward:               p.baseMetrics.EpochReward(valIdx),
 		MaxReward:            maxReward,
 		AttestationReward:    flagIndexMaxReward,
@@ -305,6 +326,7 @@ func (p AltairMetrics) GetMaxReward(valIdx phase0.ValidatorIndex) (spec.Validato
 		MissingTarget:        flags[spec.AttTargetFlagIndex],
 		MissingHead:          flags[spec.AttHeadFlagIndex],
 		Status:               p.baseMetrics.CurrentState.GetValStatus(valIdx),
+		DetailedStatus:       p.baseMetrics.CurrentState.GetValDetailedStatus(valIdx),
 		BaseReward:           baseReward,
 		ProposerApiReward:    proposerApiReward,
 		ProposerManualReward: proposerManualReward,
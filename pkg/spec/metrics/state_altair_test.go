@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"slices"
+	"testing"
+
+	eth2spec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// loadAltairState reads the altair state fixture written by pkg/spec/testdata/gen_state.go
+func loadAltairState(t *testing.T) *spec.AgnosticState {
+	file, err := os.Open("../testdata/altair_state.ssz.gz")
+	if err != nil {
+		t.Fatalf("could not open the state fixture: %s", err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("could not read the state fixture: %s", err)
+	}
+	encoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("could not read the state fixture: %s", err)
+	}
+	bstate := &altair.BeaconState{}
+	if err := bstate.UnmarshalSSZ(encoded); err != nil {
+		t.Fatalf("could not decode the state fixture: %s", err)
+	}
+	state := spec.NewAltairState(eth2spec.VersionedBeaconState{Version: eth2spec.DataVersionAltair, Altair: bstate}, spec.EpochDuties{})
+	state.StateRoot = phase0.Root{1}
+	return &state
+}
+
+func TestGetMaxSyncComReward(t *testing.T) {
+	// the fixture state is at the last slot of epoch 320 with 18048 ETH active, every validator 8 times in the
+	// sync committee. Per the spec, with integer_squareroot(18048 * 10^9) = 4248293:
+	//   base reward per increment = 10^9 * 64 / 4248293 = 15064
+	//   total base rewards = 15064 * 18048 = 271875072
+	//   participant reward = 271875072 * 2 / 64 / 32 / 512 = 518 per slot
+	state := loadAltairState(t)
+	missed := []phase0.Slot{10241, 10242, 10250, 10271} // the last one after the state slot
+	if !slices.Equal(state.MissedBlocks, missed[:3]) {
+		t.Fatalf("the fixture state has the missed blocks %v, expected %v", state.MissedBlocks, missed[:3])
+	}
+	blocks := make([]*spec.AgnosticBlock, 0, spec.SlotsPerEpoch)
+	for slot := phase0.Slot(10240); slot < 10272; slot++ {
+		blocks = append(blocks, &spec.AgnosticBlock{Slot: slot, Proposed: !slices.Contains(missed, slot)})
+	}
+
+	tests := []struct {
+		name     string
+		blocks   []*spec.AgnosticBlock
+		expected phase0.Gwei
+	}{
+		{
+			name:     "Missed blocks from the blocks of the epoch",
+			blocks:   blocks,
+			expected: 518 * 28 * 8,
+		},
+		{
+			name:     "Blocks not attached, missed blocks of the state only",
+			expected: 518 * 29 * 8,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nextState := *state
+			nextState.Blocks = test.blocks
+			p := AltairMetrics{MaxSyncCommitteeRewards: make(map[phase0.ValidatorIndex]phase0.Gwei)}
+			p.baseMetrics.NextState = &nextState
+			p.baseMetrics.Weights = spec.DefaultRewardWeights
+			p.baseMetrics.Setups = TransitionSetups{Next: NewSetupCache(nil, 1).setup(&nextState)}
+
+			p.GetMaxSyncComReward()
+			if len(p.MaxSyncCommitteeRewards) != 64 {
+				t.Fatalf("max sync committee reward for %d validators, expected 64", len(p.MaxSyncCommitteeRewards))
+			}
+			for valIdx, result := range p.MaxSyncCommitteeRewards {
+				if result != test.expected {
+					t.Errorf("max sync committee reward of %d is %d, expected %d", valIdx, result, test.expected)
+				}
+			}
+		})
+	}
+}
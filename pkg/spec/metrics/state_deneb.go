@@ -14,11 +14,13 @@ type DenebMetrics struct {
 func NewDenebMetrics(
 	nextState *spec.AgnosticState,
 	currentState *spec.AgnosticState,
-	prevState *spec.AgnosticState) DenebMetrics {
+	prevState *spec.AgnosticState,
+	weights spec.RewardWeights,
+	setups TransitionSetups) DenebMetrics {
 
 	denebObj := DenebMetrics{}
 
-	denebObj.InitBundle(nextState, currentState, prevState)
+	denebObj.InitBundle(nextState, currentState, prevState, weights, setups)
 	denebObj.PreProcessBundle()
 
 	return denebObj
@@ -26,14 +28,19 @@ func NewDenebMetrics(
 
 func (p *DenebMetrics) InitBundle(nextState *spec.AgnosticState,
 	currentState *spec.AgnosticState,
-	prevState *spec.AgnosticState) {
+	prevState *spec.AgnosticState,
+	weights spec.RewardWeights,
+	setups TransitionSetups) {
 	p.baseMetrics.NextState = nextState
 	p.baseMetrics.CurrentState = currentState
 	p.baseMetrics.PrevState = prevState
+	p.baseMetrics.Weights = weights
+	p.baseMetrics.Setups = setups
 	p.baseMetrics.MaxBlockRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
 	p.baseMetrics.MaxSlashingRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
 	p.baseMetrics.InclusionDelays = make([]int, len(p.baseMetrics.NextState.Validators))
 	p.baseMetrics.MaxAttesterRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
+	p.baseMetrics.EpochRewards = make([]int64, 0, len(currentState.Validators))
 	p.MaxSyncCommitteeRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
 }
 
@@ -95,7 +102,7 @@ func (p DenebMetrics) ProcessAttestations() {
 					log.Fatalf("error processing attestations at block %d: %s", block.Slot, err)
 				}
 				if epochParticipation[valIdx] == nil {
-					epochParticipation[valIdx] = make([]bool, len(spec.ParticipatingFlagsWeight))
+					epochParticipation[valIdx] = make([]bool, len(p.baseMetrics.Weights.ParticipatingFlagsWeight()))
 				}
 
 				if slotInEpoch(slot, p.baseMetrics.CurrentState.Epoch) {
@@ -107,17 +114,17 @@ func (p DenebMetrics) ProcessAttestations() {
 
 				new := false
 				if participationFlags[spec.AttSourceFlagIndex] && !epochParticipation[valIdx][spec.AttSourceFlagIndex] { // source
-					attReward += attesterBaseReward * spec.TimelySourceWeight
+					attReward += attesterBaseReward * phase0.Gwei(p.baseMetrics.Weights.TimelySource)
 					epochParticipation[valIdx][spec.AttSourceFlagIndex] = true
 					new = true
 				}
 				if participationFlags[spec.AttTargetFlagIndex] && !epochParticipation[valIdx][spec.AttTargetFlagIndex] { // target
-					attReward += attesterBaseReward * spec.TimelyTargetWeight
+					attReward += attesterBaseReward * phase0.Gwei(p.baseMetrics.Weights.TimelyTarget)
 					epochParticipation[valIdx][spec.AttTargetFlagIndex] = true
 					new = true
 				}
 				if participationFlags[spec.AttHeadFlagIndex] && !epochParticipation[valIdx][spec.AttHeadFlagIndex] { // head
-					attReward += attesterBaseReward * spec.TimelyHeadWeight
+					attReward += attesterBaseReward * phase0.Gwei(p.baseMetrics.Weights.TimelyHead)
 					epochParticipation[valIdx][spec.AttHeadFlagIndex] = true
 					new = true
 				}
@@ -128,7 +135,7 @@ func (p DenebMetrics) ProcessAttestations() {
 
 			// only process rewards for blocks in NextState
 			if block.Slot >= phase0.Slot(p.baseMetrics.NextState.Epoch)*spec.SlotsPerEpoch {
-				denominator := phase0.Gwei((spec.WeightDenominator - spec.ProposerWeight) * spec.WeightDenominator / spec.ProposerWeight)
+				denominator := phase0.Gwei((p.baseMetrics.Weights.Denominator - p.baseMetrics.Weights.Proposer) * p.baseMetrics.Weights.Denominator / p.baseMetrics.Weights.Proposer)
 				attReward = attReward / denominator
 
 				p.baseMetrics.MaxBlockRewards[block.ProposerIndex] += attReward
@@ -176,9 +183,12 @@ func (p *DenebMetrics) ProcessInclusionDelays() {
 }
 
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/beacon-chain.md#get_flag_index_deltas
-func (p DenebMetrics) GetMaxFlagIndexDeltas() {
-
+func (p *DenebMetrics) GetMaxFlagIndexDeltas() {
+	if p.baseMetrics.genesisTransition() {
+		return
+	}
 	for valIdx, validator := range p.baseMetrics.NextState.Validators {
+		p.baseMetrics.addEpochReward(phase0.ValidatorIndex(valIdx), validator)
 		maxFlagsReward := phase0.Gwei(0)
 		// the maxReward would be each flag_index_weight * base_reward * (attesting_balance_inc / total_active_balance_inc) / WEIGHT_DENOMINATOR
 
@@ -194,8 +204,8 @@ func (p DenebMetrics) GetMaxFlagIndexDeltas() {
 				// apply formula
 				attestingBalanceInc := p.baseMetrics.CurrentState.AttestingBalance[i] / spec.EffectiveBalanceInc
 
-				flagReward := phase0.Gwei(spec.ParticipatingFlagsWeight[i]) * baseReward * attestingBalanceInc
-				flagReward = flagReward / ((phase0.Gwei(p.baseMetrics.CurrentState.TotalActiveBalance / spec.EffectiveBalanceInc)) * phase0.Gwei(spec.WeightDenominator))
+				flagReward := phase0.Gwei(p.baseMetrics.Weights.ParticipatingFlagsWeight()[i]) * baseReward * attestingBalanceInc
+				flagReward = flagReward / ((phase0.Gwei(p.baseMetrics.CurrentState.TotalActiveBalance / spec.EffectiveBalanceInc)) * phase0.Gwei(p.baseMetrics.Weights.Denominator))
 				maxFlagsReward += flagReward
 			}
 		}
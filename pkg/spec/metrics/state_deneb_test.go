@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestGetMaxFlagIndexDeltasGenesis(t *testing.T) {
+	// at genesis there is no previous epoch to reward, nor a previous state
+	state := loadAltairState(t)
+	genesis := *state
+	genesis.Epoch = 0
+
+	p := DenebMetrics{}
+	p.baseMetrics.NextState = state
+	p.baseMetrics.CurrentState = &genesis
+	p.baseMetrics.MaxAttesterRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
+
+	p.GetMaxFlagIndexDeltas()
+	if len(p.baseMetrics.MaxAttesterRewards) != 0 {
+		t.Errorf("max attester rewards for %d validators at genesis, expected none", len(p.baseMetrics.MaxAttesterRewards))
+	}
+}
+
+func TestGetMaxFlagIndexDeltasEpochRewards(t *testing.T) {
+	// the rewards distribution is accumulated in the max rewards loop, from the validators active in the current epoch
+	state := loadAltairState(t)
+	state.Withdrawals = make([]phase0.Gwei, len(state.Validators))
+	state.Deposits = make([]phase0.Gwei, len(state.Validators))
+	state.Deposits[3] = 1000000000 // a top-up is not a reward
+	current := *state
+	current.Epoch = state.Epoch - 1
+	current.Blocks = make([]*spec.AgnosticBlock, spec.SlotsPerEpoch)
+	for i := range current.Blocks {
+		current.Blocks[i] = &spec.AgnosticBlock{Proposed: true}
+	}
+
+	p := DenebMetrics{}
+	p.baseMetrics.NextState = state
+	p.baseMetrics.CurrentState = &current
+	p.baseMetrics.PrevState = &current
+	p.baseMetrics.InclusionDelays = make([]int, len(state.Validators))
+	p.baseMetrics.Weights = spec.DefaultRewardWeights
+	p.baseMetrics.MaxAttesterRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
+
+	p.GetMaxFlagIndexDeltas()
+
+	expected := make([]int64, 0)
+	for valIdx, validator := range current.Validators {
+		if spec.IsActive(*validator, current.Epoch) {
+			expected = append(expected, p.baseMetrics.EpochReward(phase0.ValidatorIndex(valIdx)))
+		}
+	}
+	if len(p.baseMetrics.EpochRewards) != len(expected) {
+		t.Fatalf("%d epoch rewards accumulated, expected %d", len(p.baseMetrics.EpochRewards), len(expected))
+	}
+	if distribution := p.baseMetrics.RewardsDistribution(); distribution != spec.NewRewardsDistribution(expected) {
+		t.Errorf("rewards distribution %+v, expected %+v", distribution, spec.NewRewardsDistribution(expected))
+	}
+}
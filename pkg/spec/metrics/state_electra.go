@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// ElectraMetrics computes the rewards as in Deneb once the attestations of the blocks, which aggregate several
+// committees since Electra, are split by committee. The compounding validators reach an effective balance
+// of 2048 ETH, which the base rewards take from the states, and the whistleblower reward of a slashing
+// is a smaller share of the slashed balance (see spec.WhistleBlowerRewardQuotientAt)
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/electra/beacon-chain.md
+type ElectraMetrics struct {
+	DenebMetrics
+}
+
+func NewElectraMetrics(
+	nextState *spec.AgnosticState,
+	currentState *spec.AgnosticState,
+	prevState *spec.AgnosticState,
+	weights spec.RewardWeights,
+	setups TransitionSetups) ElectraMetrics {
+
+	electraObj := ElectraMetrics{}
+
+	electraObj.InitBundle(nextState, currentState, prevState, weights, setups)
+	electraObj.PreProcessBundle()
+
+	return electraObj
+}
+
+func (p *ElectraMetrics) InitBundle(nextState *spec.AgnosticState,
+	currentState *spec.AgnosticState,
+	prevState *spec.AgnosticState,
+	weights spec.RewardWeights,
+	setups TransitionSetups) {
+	// attestations are included in the same or the next epoch
+	for _, block := range currentState.Blocks {
+		block.ExpandElectraAttestations(prevState.EpochStructs, currentState.EpochStructs)
+	}
+	for _, block := range nextState.Blocks {
+		block.ExpandElectraAttestations(currentState.EpochStructs, nextState.EpochStructs)
+	}
+	p.DenebMetrics.InitBundle(nextState, currentState, prevState, weights, setups)
+}
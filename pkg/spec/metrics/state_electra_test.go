@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"testing"
+
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	eth2spec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestElectraProcessSlashings(t *testing.T) {
+	// validator 1 compounds up to 2048 ETH, it is slashed by the proposer of slot 330
+	validators := []*phase0.Validator{
+		{EffectiveBalance: 32_000_000_000, ExitEpoch: 1 << 62, WithdrawableEpoch: 1 << 62},
+		{EffectiveBalance: 2048_000_000_000, ExitEpoch: 1 << 62, WithdrawableEpoch: 1 << 62},
+	}
+	slashing := &phase0.ProposerSlashing{
+		SignedHeader1: &phase0.SignedBeaconBlockHeader{Message: &phase0.BeaconBlockHeader{ProposerIndex: 1}},
+		SignedHeader2: &phase0.SignedBeaconBlockHeader{Message: &phase0.BeaconBlockHeader{ProposerIndex: 1}},
+	}
+
+	tests := []struct {
+		name        string
+		version     eth2spec.DataVersion
+		whistleblow phase0.Gwei // proposer and whistleblower reward
+	}{
+		{
+			name:        "Deneb quotient",
+			version:     eth2spec.DataVersionDeneb,
+			whistleblow: 2048_000_000_000 / 512,
+		},
+		{
+			name:        "Electra quotient",
+			version:     eth2spec.DataVersionElectra,
+			whistleblow: 2048_000_000_000 / 4096,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			currentState := &spec.AgnosticState{Epoch: 9, Version: test.version, Validators: validators}
+			nextState := &spec.AgnosticState{
+				Epoch:      10,
+				Version:    test.version,
+				Validators: validators,
+				Blocks:     []*spec.AgnosticBlock{{Slot: 330, ProposerIndex: 0, ProposerSlashings: []*phase0.ProposerSlashing{slashing}}},
+			}
+			p := ElectraMetrics{}
+			p.baseMetrics.NextState = nextState
+			p.baseMetrics.CurrentState = currentState
+			p.baseMetrics.Weights = spec.DefaultRewardWeights
+			p.baseMetrics.MaxSlashingRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
+
+			p.ProcessSlashings()
+			if result := p.baseMetrics.MaxSlashingRewards[0]; result != test.whistleblow {
+				t.Errorf("slashing reward of the proposer is %d, expected %d", result, test.whistleblow)
+			}
+			if nextState.NewProposerSlashings != 1 {
+				t.Errorf("%d new proposer slashings, expected 1", nextState.NewProposerSlashings)
+			}
+		})
+	}
+}
+
+func TestElectraBaseReward(t *testing.T) {
+	// the base reward grows with the effective balance of the compounding validators up to 2048 ETH
+	p := ElectraMetrics{}
+	totalBalance := phase0.Gwei(1_000_000 * spec.EffectiveBalanceInc)
+	base := p.GetBaseReward(0, 32*spec.EffectiveBalanceInc, totalBalance)
+	compounding := p.GetBaseReward(1, spec.MaxEffectiveIncElectra*spec.EffectiveBalanceInc, totalBalance)
+	if base == 0 || compounding != 64*base {
+		t.Errorf("base reward of a 2048 ETH validator is %d, expected 64 times %d", compounding, base)
+	}
+}
+
+func TestElectraInitBundle(t *testing.T) {
+	// the blocks of the current epoch attest the previous one, the ones of the next epoch the current one
+	duties := func(slot phase0.Slot) spec.EpochDuties {
+		return spec.EpochDuties{BeaconCommittees: []*api.BeaconCommittee{
+			{Slot: slot, Index: 0, Validators: []phase0.ValidatorIndex{0, 1}},
+			{Slot: slot, Index: 1, Validators: []phase0.ValidatorIndex{2}},
+		}}
+	}
+	aggregate := func(slot phase0.Slot) *electra.Attestation {
+		aggregationBits := bitfield.NewBitlist(3)
+		aggregationBits.SetBitAt(0, true)
+		aggregationBits.SetBitAt(2, true)
+		committeeBits := bitfield.NewBitvector64()
+		committeeBits.SetBitAt(0, true)
+		committeeBits.SetBitAt(1, true)
+		return &electra.Attestation{AggregationBits: aggregationBits, Data: &phase0.AttestationData{Slot: slot}, CommitteeBits: committeeBits}
+	}
+	currentBlock := &spec.AgnosticBlock{Slot: 320, ElectraAttestations: []*electra.Attestation{aggregate(319)}}
+	nextBlock := &spec.AgnosticBlock{Slot: 352, ElectraAttestations: []*electra.Attestation{aggregate(351)}}
+
+	prevState := &spec.AgnosticState{Epoch: 9, EpochStructs: duties(319)}
+	currentState := &spec.AgnosticState{Epoch: 10, EpochStructs: duties(351), Blocks: []*spec.AgnosticBlock{currentBlock}}
+	nextState := &spec.AgnosticState{Epoch: 11, Blocks: []*spec.AgnosticBlock{nextBlock}}
+
+	p := ElectraMetrics{}
+	p.InitBundle(nextState, currentState, prevState, spec.DefaultRewardWeights, TransitionSetups{})
+	for _, block := range []*spec.AgnosticBlock{currentBlock, nextBlock} {
+		if len(block.Attestations) != 2 {
+			t.Fatalf("block %d has %d attestations, expected one per committee", block.Slot, len(block.Attestations))
+		}
+		if bits := block.Attestations[0].AggregationBits.BitIndices(); len(bits) != 1 || bits[0] != 0 {
+			t.Errorf("block %d attests bits %v of committee 0, expected [0]", block.Slot, bits)
+		}
+		if bits := block.Attestations[1].AggregationBits.BitIndices(); len(bits) != 1 || bits[0] != 0 {
+			t.Errorf("block %d attests bits %v of committee 1, expected [0]", block.Slot, bits)
+		}
+	}
+}
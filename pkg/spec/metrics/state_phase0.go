@@ -13,11 +13,11 @@ type Phase0Metrics struct {
 	baseMetrics StateMetricsBase
 }
 
-func NewPhase0Metrics(nextState *spec.AgnosticState, currentState *spec.AgnosticState, prevState *spec.AgnosticState) Phase0Metrics {
+func NewPhase0Metrics(nextState *spec.AgnosticState, currentState *spec.AgnosticState, prevState *spec.AgnosticState, weights spec.RewardWeights, setups TransitionSetups) Phase0Metrics {
 
 	phase0Obj := Phase0Metrics{}
 
-	phase0Obj.InitBundle(nextState, currentState, prevState)
+	phase0Obj.InitBundle(nextState, currentState, prevState, weights, setups)
 	phase0Obj.PreProcessBundle()
 
 	return phase0Obj
@@ -26,14 +26,19 @@ func NewPhase0Metrics(nextState *spec.AgnosticState, currentState *spec.Agnostic
 
 func (p *Phase0Metrics) InitBundle(nextState *spec.AgnosticState,
 	currentState *spec.AgnosticState,
-	prevState *spec.AgnosticState) {
+	prevState *spec.AgnosticState,
+	weights spec.RewardWeights,
+	setups TransitionSetups) {
 	p.baseMetrics.NextState = nextState
 	p.baseMetrics.CurrentState = currentState
 	p.baseMetrics.PrevState = prevState
+	p.baseMetrics.Weights = weights
+	p.baseMetrics.Setups = setups
 	p.baseMetrics.MaxBlockRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
 	p.baseMetrics.MaxSlashingRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
 	p.baseMetrics.InclusionDelays = make([]int, len(p.baseMetrics.NextState.Validators))
 	p.baseMetrics.MaxAttesterRewards = make(map[phase0.ValidatorIndex]phase0.Gwei)
+	p.baseMetrics.EpochRewards = make([]int64, 0, len(currentState.Validators))
 }
 
 func (p *Phase0Metrics) PreProcessBundle() {
@@ -96,8 +101,8 @@ func (p *Phase0Metrics) ProcessSlashings() {
 
 		for _, slashing := range state.Slashings {
 			slashedEffBalance := p.baseMetrics.NextState.Validators[slashing.SlashedValidator].EffectiveBalance
-			whistleBlowerReward += slashedEffBalance / spec.WhistleBlowerRewardQuotient
-			proposerReward += whistleBlowerReward * spec.ProposerWeight / spec.WeightDenominator
+			whistleBlowerReward += slashedEffBalance / spec.WhistleBlowerRewardQuotientAt(state.Version)
+			proposerReward += whistleBlowerReward * phase0.Gwei(p.baseMetrics.Weights.Proposer) / phase0.Gwei(p.baseMetrics.Weights.Denominator)
 		}
 		p.baseMetrics.MaxSlashingRewards[block.ProposerIndex] += proposerReward
 		p.baseMetrics.MaxSlashingRewards[whistleBlowerIdx] += whistleBlowerReward - proposerReward
@@ -106,6 +111,10 @@ func (p *Phase0Metrics) ProcessSlashings() {
 	}
 }
 
+func (p Phase0Metrics) GetRewardWeights() spec.RewardWeights {
+	return p.baseMetrics.Weights
+}
+
 func (p Phase0Metrics) GetMetricsBase() StateMetricsBase {
 	return p.baseMetrics
 }
@@ -126,8 +135,8 @@ func (p *Phase0Metrics) GetInclusionDelayDeltas() {
 		}
 		proposerIndex := inclusionBlock.ProposerIndex
 
-		attValidatorIDs := p.baseMetrics.CurrentState.EpochStructs.GetValList(slot, committeeIndex) // Beacon Committee
-		attestingIndices := attestation.AggregationBits.BitIndices()                                // we only get the 1s, meaning the validator voted
+		attValidatorIDs := p.baseMetrics.Setups.Current.Committee(slot, committeeIndex) // Beacon Committee
+		attestingIndices := attestation.AggregationBits.BitIndices()                    // we only get the 1s, meaning the validator voted
 
 		for _, index := range attestingIndices {
 			attestingValIdx := attValidatorIDs[index]
@@ -146,15 +155,16 @@ func (p *Phase0Metrics) GetInclusionDelayDeltas() {
 					// configure attester participation
 					p.baseMetrics.CurrentState.ValidatorAttestationIncluded[attestingValIdx] = true
 
-					// add proposer reward
-					proposerReward := p.GetProposerReward(attestingValIdx)
-					p.baseMetrics.MaxBlockRewards[proposerIndex] += proposerReward
-					inclusionBlock.ManualReward += proposerReward
-
-					// add attester rewards
-					maxAttesterReward := p.GetBaseReward(attestingValIdx) - proposerReward
-					p.baseMetrics.MaxAttesterRewards[attestingValIdx] += maxAttesterReward / phase0.Gwei(bestPossibleInclusionDelay)
+					if !p.baseMetrics.genesisTransition() {
+						// add proposer reward
+						proposerReward := p.GetProposerReward(attestingValIdx)
+						p.baseMetrics.MaxBlockRewards[proposerIndex] += proposerReward
+						inclusionBlock.ManualReward += proposerReward
 
+						// add attester rewards
+						maxAttesterReward := p.GetBaseReward(attestingValIdx) - proposerReward
+						p.baseMetrics.MaxAttesterRewards[attestingValIdx] += maxAttesterReward / phase0.Gwei(bestPossibleInclusionDelay)
+					}
 				}
 
 				if p.IsCorrectTarget(*attestation) {
@@ -178,10 +188,11 @@ func (p *Phase0Metrics) GetInclusionDelayDeltas() {
 }
 
 func (p *Phase0Metrics) GetMaxAttComponentDeltas() {
-	if p.baseMetrics.CurrentState.Epoch == 0 { // No rewards are applied at genesis
+	if p.baseMetrics.genesisTransition() {
 		return
 	}
 	for valIdx, validator := range p.baseMetrics.NextState.Validators {
+		p.baseMetrics.addEpochReward(phase0.ValidatorIndex(valIdx), validator)
 		// if not in the list of validators or not active
 		if !spec.IsActive(*validator, phase0.Epoch(p.baseMetrics.PrevState.Epoch)) {
 			continue
@@ -221,7 +232,7 @@ func (p Phase0Metrics) GetMaxReward(valIdx phase0.ValidatorIndex) (spec.Validato
 	result := spec.ValidatorRewards{
 		ValidatorIndex:       valIdx,
 		Epoch:                p.baseMetrics.NextState.Epoch,
-		ValidatorBalance:     p.baseMetrics.CurrentState.Balances[valIdx],
+		ValidatorBalance:     p.baseMetrics.CurrentState.Balance(valIdx),
 		Reward:               p.baseMetrics.EpochReward(valIdx),
 		MaxReward:            maxReward,
 		AttestationReward:    p.baseMetrics.MaxAttesterRewards[valIdx],
@@ -232,6 +243,7 @@ func (p Phase0Metrics) GetMaxReward(valIdx phase0.ValidatorIndex) (spec.Validato
 		MissingTarget:        !p.baseMetrics.CurrentState.PrevEpochCorrectFlags[spec.AttTargetFlagIndex][valIdx],
 		MissingHead:          !p.baseMetrics.CurrentState.PrevEpochCorrectFlags[spec.AttHeadFlagIndex][valIdx],
 		Status:               p.baseMetrics.NextState.GetValStatus(valIdx),
+		DetailedStatus:       p.baseMetrics.NextState.GetValDetailedStatus(valIdx),
 		BaseReward:           p.GetBaseReward(valIdx),
 		ProposerManualReward: proposerReward,
 		ProposerApiReward:    0,
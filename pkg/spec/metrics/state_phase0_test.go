@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"testing"
+
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// phase0State returns a state at the end of the given epoch with two active validators and every block proposed
+func phase0State(epoch phase0.Epoch) *spec.AgnosticState {
+	validators := []*phase0.Validator{
+		{EffectiveBalance: 32_000_000_000, ExitEpoch: 1 << 62, WithdrawableEpoch: 1 << 62},
+		{EffectiveBalance: 32_000_000_000, ExitEpoch: 1 << 62, WithdrawableEpoch: 1 << 62},
+	}
+	blocks := make([]*spec.AgnosticBlock, spec.SlotsPerEpoch)
+	for i := range blocks {
+		blocks[i] = &spec.AgnosticBlock{Slot: phase0.Slot(epoch)*spec.SlotsPerEpoch + phase0.Slot(i), Proposed: true}
+	}
+	return &spec.AgnosticState{
+		StateRoot:                    phase0.Root{byte(epoch + 1)},
+		Epoch:                        epoch,
+		Slot:                         phase0.Slot(epoch+1)*spec.SlotsPerEpoch - 1,
+		Validators:                   validators,
+		TotalActiveBalance:           64_000_000_000,
+		AttestingBalance:             make([]phase0.Gwei, 3),
+		PrevEpochCorrectFlags:        [][]bool{make([]bool, 2), make([]bool, 2), make([]bool, 2)},
+		ValidatorAttestationIncluded: make([]bool, 2),
+		BlockRoots:                   make([]phase0.Root, spec.SlotsPerHistoricalRoot),
+		Blocks:                       blocks,
+		EpochStructs: spec.EpochDuties{BeaconCommittees: []*api.BeaconCommittee{
+			{Slot: phase0.Slot(epoch) * spec.SlotsPerEpoch, Index: 0, Validators: []phase0.ValidatorIndex{0, 1}},
+		}},
+	}
+}
+
+// phase0Transition computes the metrics of the transition to the epoch after the given one,
+// the next state includes the attestations of both validators to the first slot of the current epoch
+func phase0Transition(currentEpoch phase0.Epoch) Phase0Metrics {
+	currentState := phase0State(currentEpoch)
+	prevState := currentState.GenesisPrevState()
+	if currentEpoch > 0 {
+		prevState = phase0State(currentEpoch - 1)
+	}
+	nextState := phase0State(currentEpoch + 1)
+	aggregationBits := bitfield.NewBitlist(2)
+	aggregationBits.SetBitAt(0, true)
+	aggregationBits.SetBitAt(1, true)
+	nextState.PrevAttestations = []*phase0.PendingAttestation{{
+		AggregationBits: aggregationBits,
+		Data: &phase0.AttestationData{
+			Slot:   phase0.Slot(currentEpoch) * spec.SlotsPerEpoch,
+			Source: &phase0.Checkpoint{},
+			Target: &phase0.Checkpoint{},
+		},
+		InclusionDelay: 1,
+	}}
+
+	cache := NewSetupCache(nil, 1)
+	setups := TransitionSetups{Prev: cache.setup(prevState), Current: cache.setup(currentState), Next: cache.setup(nextState)}
+	return NewPhase0Metrics(nextState, currentState, prevState, spec.DefaultRewardWeights, setups)
+}
+
+func TestPhase0GenesisTransition(t *testing.T) {
+	tests := []struct {
+		name         string
+		currentEpoch phase0.Epoch
+		rewarded     bool
+	}{
+		{"genesis", 0, false},
+		{"after genesis", 1, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := phase0Transition(test.currentEpoch)
+			for valIdx := range p.baseMetrics.NextState.Validators {
+				result, err := p.GetMaxReward(phase0.ValidatorIndex(valIdx))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if rewarded := result.MaxReward > 0; rewarded != test.rewarded {
+					t.Errorf("validator %d max reward %d, expected rewarded %t", valIdx, result.MaxReward, test.rewarded)
+				}
+				if !result.AttestationIncluded || result.InclusionDelay != 1 {
+					t.Errorf("validator %d attestation included %t with delay %d, expected included in the next slot",
+						valIdx, result.AttestationIncluded, result.InclusionDelay)
+				}
+			}
+		})
+	}
+}
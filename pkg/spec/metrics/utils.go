@@ -9,28 +9,29 @@ import (
 )
 
 func (p AltairMetrics) GetValidatorFromCommitteeIndex(slot phase0.Slot, committeeIndex phase0.CommitteeIndex, idx int) (phase0.ValidatorIndex, error) {
-	if slot >= phase0.Slot(p.baseMetrics.PrevState.Epoch)*spec.SlotsPerEpoch &&
-		slot < phase0.Slot(p.baseMetrics.CurrentState.Epoch)*spec.SlotsPerEpoch {
+	var valList []phase0.ValidatorIndex
+
+	switch {
+	case slot >= phase0.Slot(p.baseMetrics.PrevState.Epoch)*spec.SlotsPerEpoch &&
+		slot < phase0.Slot(p.baseMetrics.CurrentState.Epoch)*spec.SlotsPerEpoch:
 		// slot in PrevEpoch
-		valList := p.baseMetrics.PrevState.EpochStructs.GetValList(slot, committeeIndex)
-		return valList[idx], nil
-	}
+		valList = p.baseMetrics.Setups.Prev.Committee(slot, committeeIndex)
 
-	if slot >= phase0.Slot(p.baseMetrics.CurrentState.Epoch)*spec.SlotsPerEpoch &&
-		slot < phase0.Slot(p.baseMetrics.NextState.Epoch)*spec.SlotsPerEpoch {
+	case slot >= phase0.Slot(p.baseMetrics.CurrentState.Epoch)*spec.SlotsPerEpoch &&
+		slot < phase0.Slot(p.baseMetrics.NextState.Epoch)*spec.SlotsPerEpoch:
 		// slot in CurrentEpoch
-		valList := p.baseMetrics.CurrentState.EpochStructs.GetValList(slot, committeeIndex)
-		return valList[idx], nil
-	}
+		valList = p.baseMetrics.Setups.Current.Committee(slot, committeeIndex)
 
-	if slot >= phase0.Slot(p.baseMetrics.NextState.Epoch)*spec.SlotsPerEpoch &&
-		slot < phase0.Slot(p.baseMetrics.NextState.Epoch+1)*spec.SlotsPerEpoch {
+	case slot >= phase0.Slot(p.baseMetrics.NextState.Epoch)*spec.SlotsPerEpoch &&
+		slot < phase0.Slot(p.baseMetrics.NextState.Epoch+1)*spec.SlotsPerEpoch:
 		// slot in NextEpoch
-		valList := p.baseMetrics.NextState.EpochStructs.GetValList(slot, committeeIndex)
-		return valList[idx], nil
+		valList = p.baseMetrics.Setups.Next.Committee(slot, committeeIndex)
 	}
 
-	return 0, fmt.Errorf("could not get validator from any epoch: slot %d, committee %d, index %d", slot, committeeIndex, idx)
+	if idx >= len(valList) {
+		return 0, fmt.Errorf("could not get validator from any epoch: slot %d, committee %d, index %d", slot, committeeIndex, idx)
+	}
+	return valList[idx], nil
 }
 
 func (p AltairMetrics) GetJustifiedRootfromSlot(slot phase0.Slot) (phase0.Root, error) {
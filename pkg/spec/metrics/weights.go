@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	nodeSpecMu     sync.Mutex
+	loadedNodeSpec map[string]any          // spec of the node, only requested once
+	forkSchedule   local_spec.ForkSchedule // custom fork epochs, over the node's ones
+)
+
+// UseForkSchedule overrides the fork epochs of the node spec (custom devnets)
+func UseForkSchedule(schedule local_spec.ForkSchedule) {
+	nodeSpecMu.Lock()
+	defer nodeSpecMu.Unlock()
+	forkSchedule = schedule
+	loadedNodeSpec = nil
+}
+
+// NodeSpec returns the spec of the node (/eth/v1/config/spec) with the custom fork epochs, requested once and reused afterwards.
+// Without a node, or if it cannot be requested, it is nil and the values read from it are the mainnet ones
+func NodeSpec(iApi *http.Service) map[string]any {
+	nodeSpecMu.Lock()
+	defer nodeSpecMu.Unlock()
+
+	if loadedNodeSpec != nil || iApi == nil {
+		return loadedNodeSpec
+	}
+
+	nodeSpec, err := iApi.Spec(context.Background(), &api.SpecOpts{})
+	if err != nil {
+		log.Warnf("could not request the node spec, using the mainnet values: %s", err)
+		return nil
+	}
+	loadedNodeSpec = forkSchedule.Apply(nodeSpec.Data)
+	log.Infof("spec loaded from the node: %d values", len(loadedNodeSpec))
+	return loadedNodeSpec
+}
+
+// RewardWeightsByForkVersion returns the reward weights that apply to the given fork, read from the node spec.
+// Phase0 has no weights in the spec, the default ones are used to split the whistleblower reward
+func RewardWeightsByForkVersion(version spec.DataVersion, iApi *http.Service) local_spec.RewardWeights {
+	if version == spec.DataVersionPhase0 {
+		return local_spec.DefaultRewardWeights
+	}
+	return local_spec.NewRewardWeightsFromSpec(NodeSpec(iApi))
+}
+
+// BlobScheduleFromNode returns the blob limits of the network, read from the node spec
+func BlobScheduleFromNode(iApi *http.Service) local_spec.BlobSchedule {
+	return local_spec.NewBlobScheduleFromSpec(NodeSpec(iApi))
+}
+
+// CommitteeConfigFromNode returns the committee config of the network, read from the node spec
+func CommitteeConfigFromNode(iApi *http.Service) local_spec.CommitteeConfig {
+	return local_spec.NewCommitteeConfigFromSpec(NodeSpec(iApi))
+}
+
+// InactivityConfigFromNode returns the inactivity penalty config of the network, read from the node spec
+func InactivityConfigFromNode(iApi *http.Service) local_spec.InactivityConfig {
+	return local_spec.NewInactivityConfigFromSpec(NodeSpec(iApi))
+}
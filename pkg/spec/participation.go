@@ -0,0 +1,44 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// EpochParticipation is the participation in the current epoch of a state, with the attestations
+// included so far. The attestations of the last slots are still to be included, so it is an estimate
+type EpochParticipation struct {
+	Epoch            phase0.Epoch
+	ActiveBalance    phase0.Gwei   // effective balance of the active validators
+	AttestingVals    []uint64      // one per flag (source, target, head), validators with the flag
+	AttestingBalance []phase0.Gwei // one per flag (source, target, head), effective balance of the validators with the flag
+}
+
+// Rate returns the attesting balance of the given flag over the active balance, in %
+func (p EpochParticipation) Rate(flag altair.ParticipationFlag) float64 {
+	if p.ActiveBalance == 0 || int(flag) >= len(p.AttestingBalance) {
+		return 0
+	}
+	return float64(p.AttestingBalance[flag]) / float64(p.ActiveBalance) * 100
+}
+
+// ProcessCurrentParticipation sums the flags of the current epoch participation (since Altair)
+func ProcessCurrentParticipation(customState *AgnosticState, currentEpochParticipation []altair.ParticipationFlags) {
+	participation := EpochParticipation{
+		Epoch:            customState.Epoch,
+		ActiveBalance:    customState.TotalActiveBalance,
+		AttestingVals:    make([]uint64, 3),
+		AttestingBalance: make([]phase0.Gwei, 3),
+	}
+
+	for valIndex, item := range currentEpochParticipation {
+		for flagIndex := range participation.AttestingVals {
+			flag := altair.ParticipationFlags(1 << flagIndex)
+			if (item & flag) == flag {
+				participation.AttestingVals[flagIndex]++
+				participation.AttestingBalance[flagIndex] += customState.Validators[valIndex].EffectiveBalance
+			}
+		}
+	}
+	customState.CurrentParticipation = participation
+}
@@ -0,0 +1,84 @@
+package spec_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+
+	eth2spec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestProcessCurrentParticipation(t *testing.T) {
+	state := &spec.AgnosticState{
+		Epoch:              10,
+		TotalActiveBalance: 96_000_000_000,
+		Validators: []*phase0.Validator{
+			{EffectiveBalance: 32_000_000_000},
+			{EffectiveBalance: 32_000_000_000},
+			{EffectiveBalance: 32_000_000_000},
+		},
+	}
+	// source+target+head, source+target, nothing yet
+	spec.ProcessCurrentParticipation(state, []altair.ParticipationFlags{7, 3, 0})
+
+	participation := state.CurrentParticipation
+	expectedVals := []uint64{2, 2, 1}
+	for flag, vals := range expectedVals {
+		if participation.AttestingVals[flag] != vals {
+			t.Errorf("flag %d has %d attesting validators, expected %d", flag, participation.AttestingVals[flag], vals)
+		}
+	}
+	rate := participation.Rate(altair.TimelyTargetFlagIndex)
+	if rate < 66.66 || rate > 66.67 {
+		t.Errorf("Rate returned %f, expected 66.67", rate)
+	}
+}
+
+// loadAltairState reads the altair state fixture written by testdata/gen_state.go
+func loadAltairState(t *testing.T) spec.AgnosticState {
+	file, err := os.Open("testdata/altair_state.ssz.gz")
+	if err != nil {
+		t.Fatalf("could not open the state fixture: %s", err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("could not read the state fixture: %s", err)
+	}
+	encoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("could not read the state fixture: %s", err)
+	}
+	state := &altair.BeaconState{}
+	if err := state.UnmarshalSSZ(encoded); err != nil {
+		t.Fatalf("could not decode the state fixture: %s", err)
+	}
+	return spec.NewAltairState(eth2spec.VersionedBeaconState{Version: eth2spec.DataVersionAltair, Altair: state}, spec.EpochDuties{})
+}
+
+func TestAttestingBalances(t *testing.T) {
+	state := loadAltairState(t)
+
+	// see the validator groups in testdata/gen_state.go
+	expected := []phase0.Gwei{
+		17_152 * spec.EffectiveBalanceInc, // 24 of the 32 ETH validators and the 8 of 2048 ETH
+		8_960 * spec.EffectiveBalanceInc,  // 24 of the 32 ETH validators and 4 of 2048 ETH
+		896 * spec.EffectiveBalanceInc,    // 24 of the 32 ETH validators and the 8 of 16 ETH
+	}
+	for flag, balance := range expected {
+		if state.AttestingBalance[flag] != balance {
+			t.Errorf("flag %d has attesting balance %d, expected %d", flag, state.AttestingBalance[flag], balance)
+		}
+	}
+	// 42 of the 32 ETH validators (all but the ones without flags) and the rest
+	if balance := state.GetAnyAttestingBalance(); balance != 17_856*spec.EffectiveBalanceInc {
+		t.Errorf("GetAnyAttestingBalance returned %d, expected %d", balance, 17_856*spec.EffectiveBalanceInc)
+	}
+	if state.TotalActiveBalance != 18_048*spec.EffectiveBalanceInc {
+		t.Errorf("TotalActiveBalance is %d, expected %d", state.TotalActiveBalance, 18_048*spec.EffectiveBalanceInc)
+	}
+}
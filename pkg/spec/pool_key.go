@@ -0,0 +1,15 @@
+package spec
+
+import "github.com/attestantio/go-eth2-client/spec/phase0"
+
+// PoolKey assigns a validator to a pool from the custom pool sources, as stored in t_custom_pool_keys
+type PoolKey struct {
+	ValIdx   phase0.ValidatorIndex
+	PoolName string
+	Removed  bool   // no longer listed by any source, PoolName is the last pool it had
+	Source   string // file or URL the entry was read from, not persisted
+}
+
+func (f PoolKey) Type() ModelType {
+	return PoolKeyModel
+}
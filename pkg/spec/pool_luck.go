@@ -0,0 +1,60 @@
+package spec
+
+import (
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// PoolStake is the stake of the active validators of a pool at an epoch, weighted with their base reward
+// (proportional to the effective balance), with the sync committee seats and proposer duties they had
+type PoolStake struct {
+	PoolName    string
+	BaseRewards phase0.Gwei
+	SyncSeats   uint64
+	Proposals   uint64
+}
+
+// PoolLuck compares the proposals and sync committee seats of a pool at an epoch with the ones expected
+// from its share of the active stake
+type PoolLuck struct {
+	Epoch             phase0.Epoch
+	PoolName          string
+	StakeShare        float64
+	Proposals         uint64
+	ExpectedProposals float64
+	SyncSeats         uint64
+	ExpectedSyncSeats float64
+}
+
+func (p PoolLuck) Type() ModelType {
+	return PoolLuckModel
+}
+
+// NewPoolLuck returns the luck of every pool, given the stake of all the active validators of the epoch.
+// Every slot of the epoch has a proposer, the sync committee has the seats of the network config
+// since Altair, no seats are expected in the epochs without any
+func NewPoolLuck(epoch phase0.Epoch, pools []PoolStake, total PoolStake, config CommitteeConfig) []PoolLuck {
+	luck := make([]PoolLuck, 0, len(pools))
+	if total.BaseRewards == 0 {
+		return luck
+	}
+	for _, pool := range pools {
+		share := float64(pool.BaseRewards) / float64(total.BaseRewards)
+		expectedSyncSeats := 0.0
+		if total.SyncSeats > 0 {
+			expectedSyncSeats = share * float64(config.SyncCommitteeSize)
+		}
+		luck = append(luck, PoolLuck{
+			Epoch:             epoch,
+			PoolName:          pool.PoolName,
+			StakeShare:        share,
+			Proposals:         pool.Proposals,
+			ExpectedProposals: share * SlotsPerEpoch,
+			SyncSeats:         pool.SyncSeats,
+			ExpectedSyncSeats: expectedSyncSeats,
+		})
+	}
+	sort.Slice(luck, func(i, j int) bool { return luck[i].PoolName < luck[j].PoolName })
+	return luck
+}
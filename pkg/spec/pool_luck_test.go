@@ -0,0 +1,69 @@
+package spec_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestNewPoolLuck(t *testing.T) {
+	pools := []spec.PoolStake{
+		{PoolName: "poolB", BaseRewards: 100, SyncSeats: 4, Proposals: 1},
+		{PoolName: "poolA", BaseRewards: 300, SyncSeats: 10, Proposals: 10},
+	}
+	minimal := spec.DefaultCommitteeConfig
+	minimal.SyncCommitteeSize = 32
+
+	tests := []struct {
+		name     string
+		pools    []spec.PoolStake
+		total    spec.PoolStake
+		config   spec.CommitteeConfig
+		expected []spec.PoolLuck
+	}{
+		{
+			name:   "Mainnet config",
+			pools:  pools,
+			total:  spec.PoolStake{BaseRewards: 1000, SyncSeats: 512},
+			config: spec.DefaultCommitteeConfig,
+			expected: []spec.PoolLuck{
+				{Epoch: 10, PoolName: "poolA", StakeShare: 0.3, Proposals: 10, ExpectedProposals: 9.6, SyncSeats: 10, ExpectedSyncSeats: 153.6},
+				{Epoch: 10, PoolName: "poolB", StakeShare: 0.1, Proposals: 1, ExpectedProposals: 3.2, SyncSeats: 4, ExpectedSyncSeats: 51.2},
+			},
+		},
+		{
+			name:   "Sync committee size of the config",
+			pools:  pools[:1],
+			total:  spec.PoolStake{BaseRewards: 1000, SyncSeats: 32},
+			config: minimal,
+			expected: []spec.PoolLuck{
+				{Epoch: 10, PoolName: "poolB", StakeShare: 0.1, Proposals: 1, ExpectedProposals: 3.2, SyncSeats: 4, ExpectedSyncSeats: 3.2},
+			},
+		},
+		{
+			name:   "No sync committee before Altair",
+			pools:  []spec.PoolStake{{PoolName: "poolA", BaseRewards: 500, Proposals: 20}},
+			total:  spec.PoolStake{BaseRewards: 1000},
+			config: spec.DefaultCommitteeConfig,
+			expected: []spec.PoolLuck{
+				{Epoch: 10, PoolName: "poolA", StakeShare: 0.5, Proposals: 20, ExpectedProposals: 16},
+			},
+		},
+		{
+			name:     "No active stake",
+			pools:    pools,
+			config:   spec.DefaultCommitteeConfig,
+			expected: []spec.PoolLuck{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := spec.NewPoolLuck(10, test.pools, test.total, test.config)
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("NewPoolLuck returned %+v, expected %+v", result, test.expected)
+			}
+		})
+	}
+}
@@ -4,14 +4,130 @@ import (
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
+// PoolSummary aggregates the rewards and duties of the validators of a pool at an epoch,
+// as in t_pool_summary. Only active validators count, except for the proposer duties
 type PoolSummary struct {
-	PoolName      string
-	Epoch         phase0.Epoch
-	ValidatorList []ValidatorRewards
+	PoolName              string
+	Epoch                 phase0.Epoch
+	AggregatedRewards     int64 // rewards not above the max reward
+	AggregatedMaxRewards  phase0.Gwei
+	SyncCommittee         uint64
+	MissingSource         uint64
+	MissingTarget         uint64
+	MissingHead           uint64
+	ExpectedAttestations  uint64
+	AttestationsIncluded  uint64
+	ProposedBlocks        uint64
+	MissedBlocks          uint64
+	ActiveValidators      uint64
+	AvgInclusionDelay     float64
+	SourceAttBalanceShare float64 // weighted with the base reward, proportional to the effective balance
+	TargetAttBalanceShare float64
+	HeadAttBalanceShare   float64
+	AnyAttBalanceShare    float64
+	inclusionDelay        int
+	baseReward            phase0.Gwei
+	sourceBaseReward      phase0.Gwei
+	targetBaseReward      phase0.Gwei
+	headBaseReward        phase0.Gwei
+	anyBaseReward         phase0.Gwei
 }
 
-func (p *PoolSummary) AddValidator(input ValidatorRewards) {
-	p.ValidatorList = append(p.ValidatorList, input)
+// Active returns whether any validator of the pool is active. Pools whose validators all left keep a summary
+func (p PoolSummary) Active() bool {
+	return p.ActiveValidators > 0
+}
+
+func (p *PoolSummary) addValidator(reward ValidatorRewards) {
+	if reward.Status != ACTIVE_STATUS {
+		return
+	}
+	if reward.Reward <= int64(reward.MaxReward) {
+		p.AggregatedRewards += reward.Reward
+		p.AggregatedMaxRewards += reward.MaxReward
+	}
+	p.ActiveValidators++
+	p.ExpectedAttestations++
+	p.inclusionDelay += reward.InclusionDelay
+	p.baseReward += reward.BaseReward
+	if reward.InSyncCommittee {
+		p.SyncCommittee++
+	}
+	if reward.AttestationIncluded {
+		p.AttestationsIncluded++
+	}
+	if reward.MissingSource {
+		p.MissingSource++
+	} else {
+		p.sourceBaseReward += reward.BaseReward
+	}
+	if reward.MissingTarget {
+		p.MissingTarget++
+	} else {
+		p.targetBaseReward += reward.BaseReward
+	}
+	if reward.MissingHead {
+		p.MissingHead++
+	} else {
+		p.headBaseReward += reward.BaseReward
+	}
+	if !(reward.MissingSource && reward.MissingTarget && reward.MissingHead) {
+		p.anyBaseReward += reward.BaseReward
+	}
+}
+
+func (p *PoolSummary) close() {
+	if p.ActiveValidators == 0 {
+		return
+	}
+	p.AvgInclusionDelay = float64(p.inclusionDelay) / float64(p.ActiveValidators)
+	if p.baseReward == 0 {
+		return
+	}
+	p.SourceAttBalanceShare = float64(p.sourceBaseReward) / float64(p.baseReward)
+	p.TargetAttBalanceShare = float64(p.targetBaseReward) / float64(p.baseReward)
+	p.HeadAttBalanceShare = float64(p.headBaseReward) / float64(p.baseReward)
+	p.AnyAttBalanceShare = float64(p.anyBaseReward) / float64(p.baseReward)
+}
+
+// NewPoolSummaries aggregates the rewards of an epoch by the pool of each validator, without persisting a row
+// per validator. Validators without a pool are left out, duties are the proposer duties of the same epoch
+func NewPoolSummaries(epoch phase0.Epoch, rewards []ValidatorRewards, pools map[phase0.ValidatorIndex]string, duties []ProposerDuty) []PoolSummary {
+	summaries := make(map[string]*PoolSummary)
+	get := func(poolName string) *PoolSummary {
+		summary, ok := summaries[poolName]
+		if !ok {
+			summary = &PoolSummary{PoolName: poolName, Epoch: epoch}
+			summaries[poolName] = summary
+		}
+		return summary
+	}
+
+	for _, reward := range rewards {
+		poolName := pools[reward.ValidatorIndex]
+		if poolName == "" {
+			continue
+		}
+		get(poolName).addValidator(reward)
+	}
+	for _, duty := range duties {
+		poolName := pools[duty.ValIdx]
+		if poolName == "" {
+			continue
+		}
+		if duty.Proposed {
+			get(poolName).ProposedBlocks++
+		} else {
+			get(poolName).MissedBlocks++
+		}
+	}
+
+	result := make([]PoolSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		summary.close()
+		result = append(result, *summary)
+	}
+	return result
 }
 
 func (f PoolSummary) Type() ModelType {
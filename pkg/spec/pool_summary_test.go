@@ -0,0 +1,43 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestNewPoolSummaries(t *testing.T) {
+	rewards := []spec.ValidatorRewards{
+		{ValidatorIndex: 1, Status: spec.ACTIVE_STATUS, Reward: 90, MaxReward: 100, BaseReward: 10, AttestationIncluded: true, InclusionDelay: 1},
+		{ValidatorIndex: 2, Status: spec.ACTIVE_STATUS, Reward: 150, MaxReward: 100, BaseReward: 30, MissingSource: true, MissingTarget: true, MissingHead: true, InclusionDelay: 3},
+		{ValidatorIndex: 3, Status: spec.EXIT_STATUS, Reward: 0, MaxReward: 0},
+		{ValidatorIndex: 4, Status: spec.ACTIVE_STATUS, Reward: 10, MaxReward: 10, BaseReward: 10}, // no pool
+	}
+	pools := map[phase0.ValidatorIndex]string{1: "lido", 2: "lido", 3: "kiln"}
+	duties := []spec.ProposerDuty{{ValIdx: 1, Proposed: true}, {ValIdx: 3, Proposed: false}, {ValIdx: 4, Proposed: true}}
+
+	summaries := make(map[string]spec.PoolSummary)
+	for _, summary := range spec.NewPoolSummaries(10, rewards, pools, duties) {
+		summaries[summary.PoolName] = summary
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 pools, got %+v", summaries)
+	}
+
+	lido := summaries["lido"]
+	if lido.AggregatedRewards != 90 || lido.AggregatedMaxRewards != 100 { // rewards above the max are left out
+		t.Fatalf("unexpected rewards %d/%d", lido.AggregatedRewards, lido.AggregatedMaxRewards)
+	}
+	if lido.ActiveValidators != 2 || lido.AttestationsIncluded != 1 || lido.MissingTarget != 1 || lido.ProposedBlocks != 1 {
+		t.Fatalf("unexpected counts %+v", lido)
+	}
+	if lido.AvgInclusionDelay != 2 || lido.SourceAttBalanceShare != 0.25 || lido.AnyAttBalanceShare != 0.25 {
+		t.Fatalf("unexpected averages %+v", lido)
+	}
+
+	kiln := summaries["kiln"]
+	if kiln.Active() || kiln.MissedBlocks != 1 || kiln.SourceAttBalanceShare != 0 {
+		t.Fatalf("unexpected inactive pool %+v", kiln)
+	}
+}
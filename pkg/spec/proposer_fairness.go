@@ -0,0 +1,129 @@
+package spec
+
+import (
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ProposerFairness is the number of proposer duties a validator was assigned since FirstEpoch,
+// compared with the ones expected from its share of the active stake at every epoch
+type ProposerFairness struct {
+	SnapshotEpoch     phase0.Epoch // epoch the values were persisted at, the last snapshot of each validator is kept
+	ValIdx            phase0.ValidatorIndex
+	Epoch             phase0.Epoch // last epoch accumulated
+	FirstEpoch        phase0.Epoch // first epoch accumulated
+	Epochs            uint64       // epochs accumulated, fewer than the range if the analysis had gaps
+	ExpectedProposals float64
+	Duties            uint64 // proposer duties assigned
+	Proposed          uint64 // blocks proposed of the assigned duties
+}
+
+func (f ProposerFairness) Type() ModelType {
+	return ProposerFairnessModel
+}
+
+// Drift returns the duties assigned over the expected ones, positive if the validator was luckier than its stake
+func (f ProposerFairness) Drift() float64 {
+	return float64(f.Duties) - f.ExpectedProposals
+}
+
+// ProposerFairnessTracker accumulates the expected and the assigned proposer duties of every validator, epoch by epoch
+type ProposerFairnessTracker struct {
+	sync.Mutex
+	validators map[phase0.ValidatorIndex]*ProposerFairness
+	counted    map[phase0.Epoch]bool // epochs already accumulated
+	floor      phase0.Epoch          // epochs up to this one were accumulated before (restored), 0 if none
+}
+
+func NewProposerFairnessTracker() *ProposerFairnessTracker {
+	return &ProposerFairnessTracker{
+		validators: make(map[phase0.ValidatorIndex]*ProposerFairness),
+		counted:    make(map[phase0.Epoch]bool),
+	}
+}
+
+// Restore resumes the accumulation from the given values, i.e. the last ones persisted
+func (t *ProposerFairnessTracker) Restore(values []ProposerFairness) {
+	t.Lock()
+	defer t.Unlock()
+	for _, value := range values {
+		restored := value
+		t.validators[value.ValIdx] = &restored
+		if value.Epoch > t.floor {
+			t.floor = value.Epoch
+		}
+	}
+}
+
+// AddEpoch accumulates the proposer duties of the epoch of the state, expecting from each active validator
+// SLOTS_PER_EPOCH * effective balance / total active balance of them. Returns false if the epoch was already added
+func (t *ProposerFairnessTracker) AddEpoch(state *AgnosticState) bool {
+	t.Lock()
+	defer t.Unlock()
+	if t.counted[state.Epoch] || (t.floor > 0 && state.Epoch <= t.floor) {
+		return false
+	}
+	t.counted[state.Epoch] = true
+
+	totalBalance := phase0.Gwei(0)
+	for _, validator := range state.Validators {
+		if IsActive(*validator, state.Epoch) {
+			totalBalance += validator.EffectiveBalance
+		}
+	}
+	if totalBalance == 0 {
+		return true
+	}
+	for i, validator := range state.Validators {
+		if !IsActive(*validator, state.Epoch) {
+			continue
+		}
+		fairness := t.validator(phase0.ValidatorIndex(i), state.Epoch)
+		fairness.Epochs++
+		fairness.Epoch = max(fairness.Epoch, state.Epoch)
+		fairness.FirstEpoch = min(fairness.FirstEpoch, state.Epoch)
+		fairness.ExpectedProposals += float64(SlotsPerEpoch) * float64(validator.EffectiveBalance) / float64(totalBalance)
+	}
+
+	missed := make(map[phase0.Slot]bool, len(state.MissedBlocks))
+	for _, slot := range state.MissedBlocks {
+		missed[slot] = true
+	}
+	for _, duty := range state.EpochStructs.ProposerDuties {
+		fairness := t.validator(duty.ValidatorIndex, state.Epoch)
+		fairness.Duties++
+		if !missed[duty.Slot] {
+			fairness.Proposed++
+		}
+	}
+	return true
+}
+
+// validator returns the values of the validator, starting them at the given epoch the first time.
+// It must be called holding the lock
+func (t *ProposerFairnessTracker) validator(valIdx phase0.ValidatorIndex, epoch phase0.Epoch) *ProposerFairness {
+	fairness, ok := t.validators[valIdx]
+	if !ok {
+		fairness = &ProposerFairness{
+			ValIdx:     valIdx,
+			Epoch:      epoch,
+			FirstEpoch: epoch,
+		}
+		t.validators[valIdx] = fairness
+	}
+	return fairness
+}
+
+// Snapshot returns the values accumulated so far for every validator, as a snapshot of the given epoch
+func (t *ProposerFairnessTracker) Snapshot(epoch phase0.Epoch) []ProposerFairness {
+	t.Lock()
+	defer t.Unlock()
+	values := make([]ProposerFairness, 0, len(t.validators))
+	for _, fairness := range t.validators {
+		value := *fairness
+		value.SnapshotEpoch = epoch
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,74 @@
+package spec_test
+
+import (
+	"testing"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestProposerFairnessTracker(t *testing.T) {
+	validator := func(effectiveBalance phase0.Gwei, exit phase0.Epoch) *phase0.Validator {
+		return &phase0.Validator{
+			EffectiveBalance: effectiveBalance,
+			ActivationEpoch:  0,
+			ExitEpoch:        exit,
+		}
+	}
+	duties := func(epoch phase0.Epoch, proposers ...phase0.ValidatorIndex) []*v1.ProposerDuty {
+		result := make([]*v1.ProposerDuty, 0, len(proposers))
+		for i, valIdx := range proposers {
+			result = append(result, &v1.ProposerDuty{
+				Slot:           phase0.Slot(epoch)*spec.SlotsPerEpoch + phase0.Slot(i),
+				ValidatorIndex: valIdx,
+			})
+		}
+		return result
+	}
+	state := func(epoch phase0.Epoch, proposers ...phase0.ValidatorIndex) *spec.AgnosticState {
+		return &spec.AgnosticState{
+			Epoch: epoch,
+			Validators: []*phase0.Validator{
+				validator(32_000_000_000, farFuture),
+				validator(96_000_000_000, farFuture),
+				validator(32_000_000_000, 11), // exited at epoch 11
+			},
+			EpochStructs: spec.EpochDuties{ProposerDuties: duties(epoch, proposers...)},
+			MissedBlocks: []phase0.Slot{phase0.Slot(epoch) * spec.SlotsPerEpoch},
+		}
+	}
+
+	tracker := spec.NewProposerFairnessTracker()
+	if !tracker.AddEpoch(state(10, 1, 0, 1)) {
+		t.Errorf("AddEpoch returned false, expected true")
+	}
+	if tracker.AddEpoch(state(10, 1)) {
+		t.Errorf("AddEpoch of an epoch already added returned true, expected false")
+	}
+	tracker.AddEpoch(state(11, 0))
+
+	expected := map[phase0.ValidatorIndex]spec.ProposerFairness{
+		0: {ValIdx: 0, Epoch: 11, FirstEpoch: 10, Epochs: 2, ExpectedProposals: 6.4 + 8, Duties: 2, Proposed: 1},
+		1: {ValIdx: 1, Epoch: 11, FirstEpoch: 10, Epochs: 2, ExpectedProposals: 19.2 + 24, Duties: 2, Proposed: 1},
+		2: {ValIdx: 2, Epoch: 10, FirstEpoch: 10, Epochs: 1, ExpectedProposals: 6.4, Duties: 0, Proposed: 0},
+	}
+	snapshot := tracker.Snapshot(12)
+	if len(snapshot) != len(expected) {
+		t.Fatalf("Snapshot returned %d validators, expected %d", len(snapshot), len(expected))
+	}
+	for _, value := range snapshot {
+		want := expected[value.ValIdx]
+		if value.SnapshotEpoch != 12 || value.Epoch != want.Epoch || value.FirstEpoch != want.FirstEpoch || value.Epochs != want.Epochs ||
+			value.Duties != want.Duties || value.Proposed != want.Proposed ||
+			value.ExpectedProposals < want.ExpectedProposals-1e-9 || value.ExpectedProposals > want.ExpectedProposals+1e-9 {
+			t.Errorf("Snapshot of validator %d returned %+v, expected %+v", value.ValIdx, value, want)
+		}
+	}
+
+	restored := spec.NewProposerFairnessTracker()
+	restored.Restore(snapshot)
+	if restored.AddEpoch(state(11, 0)) {
+		t.Errorf("AddEpoch of an epoch before the restored ones returned true, expected false")
+	}
+}
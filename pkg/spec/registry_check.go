@@ -0,0 +1,158 @@
+package spec
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var farFutureEpoch = phase0.Epoch(math.MaxUint64)
+
+// RegistryAnomalyKind is the kind of inconsistency found in the validator registry
+type RegistryAnomalyKind string
+
+const (
+	// the public key at an index changed, indexes are never reused
+	IndexReuseAnomaly RegistryAnomalyKind = "index_reuse"
+	// the registry has less validators than in the previous state, it only grows
+	RegistryShrinkAnomaly RegistryAnomalyKind = "registry_shrink"
+	// an activation, exit or withdrawable epoch changed once set, or the slashed flag was cleared
+	NonMonotonicAnomaly RegistryAnomalyKind = "non_monotonic"
+	// the public key of a new validator is already in the registry, deposits to it are top-ups
+	DuplicatePubkeyAnomaly RegistryAnomalyKind = "duplicate_pubkey"
+)
+
+// RegistryAnomaly is an inconsistency of the validator registry between consecutive states
+type RegistryAnomaly struct {
+	Epoch          phase0.Epoch // epoch of the state where it was found
+	ValidatorIndex phase0.ValidatorIndex
+	Kind           RegistryAnomalyKind
+	Detail         string
+}
+
+func (f RegistryAnomaly) Type() ModelType {
+	return RegistryAnomalyModel
+}
+
+func (f RegistryAnomaly) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Epoch,
+		f.ValidatorIndex,
+		f.Kind,
+		f.Detail,
+	}
+	return rows
+}
+
+// CheckValidatorRegistry compares the registry of a state with the one of the previous state,
+// returning the entries that changed in a way the spec does not allow
+func CheckValidatorRegistry(prevState *AgnosticState, state *AgnosticState) []RegistryAnomaly {
+	anomalies := make([]RegistryAnomaly, 0)
+	if len(state.Validators) < len(prevState.Validators) {
+		anomalies = append(anomalies, RegistryAnomaly{
+			Epoch:          state.Epoch,
+			ValidatorIndex: phase0.ValidatorIndex(len(state.Validators)),
+			Kind:           RegistryShrinkAnomaly,
+			Detail:         fmt.Sprintf("%d validators, %d in the previous state", len(state.Validators), len(prevState.Validators)),
+		})
+	}
+
+	limit := min(len(state.Validators), len(prevState.Validators))
+	for i := 0; i < limit; i++ {
+		prev, current := prevState.Validators[i], state.Validators[i]
+		if prev == current {
+			continue // shared entry, did not change
+		}
+		detail := ""
+		kind := NonMonotonicAnomaly
+		switch {
+		case prev.PublicKey != current.PublicKey:
+			kind = IndexReuseAnomaly
+			detail = fmt.Sprintf("public key changed from %s to %s", prev.PublicKey, current.PublicKey)
+		case prev.Slashed && !current.Slashed:
+			detail = "slashed flag cleared"
+		case !setOnce(prev.ActivationEligibilityEpoch, current.ActivationEligibilityEpoch):
+			detail = fmt.Sprintf("activation eligibility epoch changed from %d to %d", prev.ActivationEligibilityEpoch, current.ActivationEligibilityEpoch)
+		case !setOnce(prev.ActivationEpoch, current.ActivationEpoch):
+			detail = fmt.Sprintf("activation epoch changed from %d to %d", prev.ActivationEpoch, current.ActivationEpoch)
+		case !setOnce(prev.ExitEpoch, current.ExitEpoch):
+			detail = fmt.Sprintf("exit epoch changed from %d to %d", prev.ExitEpoch, current.ExitEpoch)
+		// slashings can only push the withdrawable epoch further
+		case !setOnce(prev.WithdrawableEpoch, current.WithdrawableEpoch) &&
+			!(current.Slashed && current.WithdrawableEpoch > prev.WithdrawableEpoch):
+			detail = fmt.Sprintf("withdrawable epoch changed from %d to %d", prev.WithdrawableEpoch, current.WithdrawableEpoch)
+		default:
+			continue
+		}
+		anomalies = append(anomalies, RegistryAnomaly{
+			Epoch:          state.Epoch,
+			ValidatorIndex: phase0.ValidatorIndex(i),
+			Kind:           kind,
+			Detail:         detail,
+		})
+	}
+	return anomalies
+}
+
+// setOnce tells whether an epoch kept its value or was set for the first time
+func setOnce(prev phase0.Epoch, current phase0.Epoch) bool {
+	return prev == current || prev == farFutureEpoch
+}
+
+// PubkeyRegistry indexes the public keys of the registry, shared by the setup cache of the states (sync committees)
+// and the registry check. Indexes are never reused, so only the new validators of each state are added
+type PubkeyRegistry struct {
+	sync.Mutex
+	indexes map[phase0.BLSPubKey]phase0.ValidatorIndex
+	known   int // validators already indexed
+}
+
+func NewPubkeyRegistry() *PubkeyRegistry {
+	return &PubkeyRegistry{
+		indexes: make(map[phase0.BLSPubKey]phase0.ValidatorIndex),
+	}
+}
+
+// Add indexes the validators not seen yet, a duplicated public key keeps the index of its first validator
+func (r *PubkeyRegistry) Add(validators []*phase0.Validator) {
+	r.Lock()
+	defer r.Unlock()
+
+	for ; r.known < len(validators); r.known++ {
+		pubkey := validators[r.known].PublicKey
+		if _, ok := r.indexes[pubkey]; !ok {
+			r.indexes[pubkey] = phase0.ValidatorIndex(r.known)
+		}
+	}
+}
+
+// ValidatorIndex returns the index of the validator with the given public key, if indexed
+func (r *PubkeyRegistry) ValidatorIndex(pubkey phase0.BLSPubKey) (phase0.ValidatorIndex, bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	valIdx, ok := r.indexes[pubkey]
+	return valIdx, ok
+}
+
+// DuplicatePubkeys indexes the validators of the state, returning the ones added since the previous state
+// whose public key was already used by another validator
+func (r *PubkeyRegistry) DuplicatePubkeys(prevState *AgnosticState, state *AgnosticState) []RegistryAnomaly {
+	r.Add(state.Validators)
+
+	anomalies := make([]RegistryAnomaly, 0)
+	for i := len(prevState.Validators); i < len(state.Validators); i++ {
+		pubkey := state.Validators[i].PublicKey
+		if prevIndex, ok := r.ValidatorIndex(pubkey); ok && prevIndex != phase0.ValidatorIndex(i) {
+			anomalies = append(anomalies, RegistryAnomaly{
+				Epoch:          state.Epoch,
+				ValidatorIndex: phase0.ValidatorIndex(i),
+				Kind:           DuplicatePubkeyAnomaly,
+				Detail:         fmt.Sprintf("public key %s already used by validator %d", pubkey, prevIndex),
+			})
+		}
+	}
+	return anomalies
+}
@@ -0,0 +1,99 @@
+package spec_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var farFuture = phase0.Epoch(math.MaxUint64)
+
+func registryValidator(pubkey byte, activation phase0.Epoch, exit phase0.Epoch) *phase0.Validator {
+	return &phase0.Validator{
+		PublicKey:                  phase0.BLSPubKey{pubkey},
+		ActivationEligibilityEpoch: 0,
+		ActivationEpoch:            activation,
+		ExitEpoch:                  exit,
+		WithdrawableEpoch:          farFuture,
+	}
+}
+
+func TestCheckValidatorRegistry(t *testing.T) {
+	prevState := &spec.AgnosticState{
+		Epoch: 9,
+		Validators: []*phase0.Validator{
+			registryValidator(1, 5, farFuture),
+			registryValidator(2, 5, farFuture),
+			registryValidator(3, farFuture, farFuture),
+			registryValidator(4, 5, 20),
+		},
+	}
+	slashed := registryValidator(2, 5, 15)
+	slashed.Slashed = true
+	slashed.WithdrawableEpoch = 8207
+
+	tests := []struct {
+		name       string
+		validators []*phase0.Validator
+		result     []spec.RegistryAnomalyKind
+	}{
+		{
+			name:       "valid transitions",
+			validators: []*phase0.Validator{registryValidator(1, 5, 12), slashed, registryValidator(3, 11, farFuture), prevState.Validators[3], registryValidator(5, farFuture, farFuture)},
+			result:     []spec.RegistryAnomalyKind{},
+		},
+		{
+			name:       "index reuse",
+			validators: []*phase0.Validator{registryValidator(9, 5, farFuture), prevState.Validators[1], prevState.Validators[2], prevState.Validators[3]},
+			result:     []spec.RegistryAnomalyKind{spec.IndexReuseAnomaly},
+		},
+		{
+			name:       "exit epoch changed",
+			validators: []*phase0.Validator{prevState.Validators[0], prevState.Validators[1], prevState.Validators[2], registryValidator(4, 5, 25)},
+			result:     []spec.RegistryAnomalyKind{spec.NonMonotonicAnomaly},
+		},
+		{
+			name:       "registry shrink",
+			validators: []*phase0.Validator{prevState.Validators[0], prevState.Validators[1], prevState.Validators[2]},
+			result:     []spec.RegistryAnomalyKind{spec.RegistryShrinkAnomaly},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := &spec.AgnosticState{Epoch: 10, Validators: test.validators}
+			result := spec.CheckValidatorRegistry(prevState, state)
+			if len(result) != len(test.result) {
+				t.Fatalf("CheckValidatorRegistry returned %d anomalies, expected %d", len(result), len(test.result))
+			}
+			for i := range result {
+				if result[i].Kind != test.result[i] {
+					t.Errorf("CheckValidatorRegistry returned %s, expected %s", result[i].Kind, test.result[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPubkeyRegistry(t *testing.T) {
+	registry := spec.NewPubkeyRegistry()
+	validators := []*phase0.Validator{registryValidator(1, 5, farFuture), registryValidator(2, 5, farFuture)}
+	prevState := &spec.AgnosticState{Validators: validators}
+
+	if result := registry.DuplicatePubkeys(&spec.AgnosticState{}, prevState); len(result) != 0 {
+		t.Errorf("DuplicatePubkeys returned %d anomalies, expected 0", len(result))
+	}
+	validators = append(validators, registryValidator(3, farFuture, farFuture), registryValidator(1, farFuture, farFuture))
+	state := &spec.AgnosticState{Validators: validators}
+	// the setup cache may have indexed the state first
+	registry.Add(state.Validators)
+	result := registry.DuplicatePubkeys(prevState, state)
+	if len(result) != 1 || result[0].ValidatorIndex != 3 {
+		t.Errorf("DuplicatePubkeys returned %+v, expected a duplicate of validator 3", result)
+	}
+	if valIdx, ok := registry.ValidatorIndex(validators[3].PublicKey); !ok || valIdx != 0 {
+		t.Errorf("ValidatorIndex returned %d, expected the first validator with the public key", valIdx)
+	}
+}
@@ -0,0 +1,92 @@
+package spec
+
+import (
+	"encoding/json"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// Branches of the fork choice nodes of a reorg
+const (
+	ReorgOldHeadBranch = "old_head" // only in the chain of the head before the reorg
+	ReorgNewHeadBranch = "new_head" // only in the chain of the head after the reorg
+	ReorgCommonBranch  = "common"   // in both, i.e. the common ancestor
+)
+
+// ReorgForkChoiceNode is a block of the fork choice of the beacon node when a reorg was reported,
+// with the weight behind it, to tell why the node switched heads
+type ReorgForkChoiceNode struct {
+	ReorgSlot      phase0.Slot // slot of the reorg event
+	Slot           phase0.Slot
+	BlockRoot      phase0.Root
+	ParentRoot     phase0.Root
+	Weight         uint64 // effective balance (Gwei) voting for the block or its descendants
+	Validity       string
+	JustifiedEpoch phase0.Epoch
+	FinalizedEpoch phase0.Epoch
+	Branch         string // empty for the blocks in neither of the two chains
+	ExtraData      string // client specific data of the node, as JSON
+}
+
+// NewReorgForkChoice returns the fork choice nodes from the slot the reorg went back to, the common ancestor
+// of the two heads included, marking the chain of the old and the new head
+func NewReorgForkChoice(reorg v1.ChainReorgEvent, forkChoice *v1.ForkChoice) []ReorgForkChoiceNode {
+	firstSlot := phase0.Slot(0)
+	if uint64(reorg.Slot) > reorg.Depth {
+		firstSlot = reorg.Slot - phase0.Slot(reorg.Depth)
+	}
+
+	parents := make(map[phase0.Root]phase0.Root, len(forkChoice.ForkChoiceNodes))
+	for _, node := range forkChoice.ForkChoiceNodes {
+		parents[node.BlockRoot] = node.ParentRoot
+	}
+	chain := func(head phase0.Root) map[phase0.Root]bool {
+		roots := make(map[phase0.Root]bool)
+		for root, ok := head, true; ok && !roots[root]; root, ok = parents[root] {
+			roots[root] = true
+		}
+		return roots
+	}
+	oldChain := chain(reorg.OldHeadBlock)
+	newChain := chain(reorg.NewHeadBlock)
+
+	nodes := make([]ReorgForkChoiceNode, 0)
+	for _, node := range forkChoice.ForkChoiceNodes {
+		if node.Slot < firstSlot {
+			continue
+		}
+		branch := ""
+		switch {
+		case oldChain[node.BlockRoot] && newChain[node.BlockRoot]:
+			branch = ReorgCommonBranch
+		case oldChain[node.BlockRoot]:
+			branch = ReorgOldHeadBranch
+		case newChain[node.BlockRoot]:
+			branch = ReorgNewHeadBranch
+		}
+		extraData := ""
+		if len(node.ExtraData) > 0 {
+			if data, err := json.Marshal(node.ExtraData); err == nil {
+				extraData = string(data)
+			}
+		}
+		nodes = append(nodes, ReorgForkChoiceNode{
+			ReorgSlot:      reorg.Slot,
+			Slot:           node.Slot,
+			BlockRoot:      node.BlockRoot,
+			ParentRoot:     node.ParentRoot,
+			Weight:         node.Weight,
+			Validity:       node.Validity.String(),
+			JustifiedEpoch: node.JustifiedEpoch,
+			FinalizedEpoch: node.FinalizedEpoch,
+			Branch:         branch,
+			ExtraData:      extraData,
+		})
+	}
+	return nodes
+}
+
+func (f ReorgForkChoiceNode) Type() ModelType {
+	return ReorgForkChoiceModel
+}
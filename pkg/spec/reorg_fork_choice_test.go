@@ -0,0 +1,63 @@
+package spec_test
+
+import (
+	"testing"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestNewReorgForkChoice(t *testing.T) {
+	node := func(slot phase0.Slot, root byte, parent byte, weight uint64) *v1.ForkChoiceNode {
+		return &v1.ForkChoiceNode{
+			Slot:       slot,
+			BlockRoot:  phase0.Root{root},
+			ParentRoot: phase0.Root{parent},
+			Weight:     weight,
+			Validity:   v1.ForkChoiceNodeValidityValid,
+		}
+	}
+	// 1 <- 2 <- 3 (old head)
+	//        <- 4 (new head)
+	//   <- 5 (abandoned before)
+	forkChoice := &v1.ForkChoice{
+		ForkChoiceNodes: []*v1.ForkChoiceNode{
+			node(98, 1, 0, 1000),
+			node(99, 2, 1, 900),
+			node(100, 3, 2, 300),
+			node(101, 4, 2, 600),
+			node(100, 5, 1, 0),
+		},
+	}
+	reorg := v1.ChainReorgEvent{
+		Slot:         101,
+		Depth:        2,
+		OldHeadBlock: phase0.Root{3},
+		NewHeadBlock: phase0.Root{4},
+	}
+
+	nodes := spec.NewReorgForkChoice(reorg, forkChoice)
+	expected := map[phase0.Root]string{
+		{2}: spec.ReorgCommonBranch,
+		{3}: spec.ReorgOldHeadBranch,
+		{4}: spec.ReorgNewHeadBranch,
+		{5}: "",
+	}
+	if len(nodes) != len(expected) {
+		t.Fatalf("NewReorgForkChoice returned %d nodes, expected %d: %+v", len(nodes), len(expected), nodes)
+	}
+	for _, node := range nodes {
+		branch, ok := expected[node.BlockRoot]
+		if !ok {
+			t.Errorf("node %s before the reorg returned", node.BlockRoot)
+			continue
+		}
+		if node.Branch != branch {
+			t.Errorf("node %s is in branch %q, expected %q", node.BlockRoot, node.Branch, branch)
+		}
+		if node.ReorgSlot != 101 || node.Validity != "valid" {
+			t.Errorf("node %s has reorg slot %d and validity %q", node.BlockRoot, node.ReorgSlot, node.Validity)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package spec
+
+// RewardWeights contains the weights applied to each reward component since Altair
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/altair/beacon-chain.md#incentivization-weights
+type RewardWeights struct {
+	TimelySource uint64
+	TimelyTarget uint64
+	TimelyHead   uint64
+	SyncReward   uint64
+	Proposer     uint64
+	Denominator  uint64
+}
+
+// DefaultRewardWeights are the incentivization weights of mainnet
+var DefaultRewardWeights = RewardWeights{
+	TimelySource: TimelySourceWeight,
+	TimelyTarget: TimelyTargetWeight,
+	TimelyHead:   TimelyHeadWeight,
+	SyncReward:   SyncRewardWeight,
+	Proposer:     ProposerWeight,
+	Denominator:  WeightDenominator,
+}
+
+// NewRewardWeightsFromSpec reads the weights from the node's spec (/eth/v1/config/spec)
+// missing or malformed values fall back to the default ones
+func NewRewardWeightsFromSpec(nodeSpec map[string]any) RewardWeights {
+	weights := DefaultRewardWeights
+
+	readWeight := func(key string, dest *uint64) {
+		if value, ok := nodeSpec[key].(uint64); ok && value > 0 {
+			*dest = value
+		}
+	}
+
+	readWeight("TIMELY_SOURCE_WEIGHT", &weights.TimelySource)
+	readWeight("TIMELY_TARGET_WEIGHT", &weights.TimelyTarget)
+	readWeight("TIMELY_HEAD_WEIGHT", &weights.TimelyHead)
+	readWeight("SYNC_REWARD_WEIGHT", &weights.SyncReward)
+	readWeight("PROPOSER_WEIGHT", &weights.Proposer)
+	readWeight("WEIGHT_DENOMINATOR", &weights.Denominator)
+
+	return weights
+}
+
+// ParticipatingFlagsWeight returns the flag weights indexed by flag index (source, target, head)
+func (w RewardWeights) ParticipatingFlagsWeight() [3]uint64 {
+	return [3]uint64{w.TimelySource, w.TimelyTarget, w.TimelyHead}
+}
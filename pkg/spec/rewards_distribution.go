@@ -0,0 +1,95 @@
+package spec
+
+import (
+	"sort"
+)
+
+// RewardsDistribution summarizes the distribution of the validator rewards in an epoch
+type RewardsDistribution struct {
+	Median int64
+	P5     int64
+	P95    int64
+	Gini   float64
+}
+
+// NewRewardsDistribution computes the median, 5th and 95th percentiles (nearest rank)
+// and the Gini coefficient of the given rewards.
+// Negative rewards (penalties) are counted as zero for the Gini coefficient
+func NewRewardsDistribution(rewards []int64) RewardsDistribution {
+	if len(rewards) == 0 {
+		return RewardsDistribution{}
+	}
+
+	sorted := make([]int64, len(rewards))
+	copy(sorted, rewards)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return RewardsDistribution{
+		Median: Percentile(sorted, 50),
+		P5:     Percentile(sorted, 5),
+		P95:    Percentile(sorted, 95),
+		Gini:   gini(sorted),
+	}
+}
+
+// Percentile returns the nearest rank percentile of an already sorted slice
+func Percentile(sorted []int64, percentile int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	// nearest rank: ceil(p / 100 * n)
+	rank := (percentile*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// gini computes the Gini coefficient of an already sorted slice
+// G = 2 * sum(i * x_i) / (n * sum(x_i)) - (n + 1) / n, with i starting at 1
+func gini(sorted []int64) float64 {
+	n := float64(len(sorted))
+	sum := float64(0)
+	weightedSum := float64(0)
+
+	for i, value := range sorted {
+		if value < 0 {
+			value = 0 // sorted, penalties are at the beginning
+		}
+		sum += float64(value)
+		weightedSum += float64(i+1) * float64(value)
+	}
+	if sum == 0 {
+		return 0
+	}
+	return 2*weightedSum/(n*sum) - (n+1)/n
+}
+
+// RewardPercentileRanks returns, for each reward, its percentile rank (0-100) among the rewards
+// that share the same bucket. Ties count as half, so equal rewards get the same rank
+func RewardPercentileRanks(rewards []int64, buckets []uint64) []float32 {
+	ranks := make([]float32, len(rewards))
+
+	bucketRewards := make(map[uint64][]int64)
+	for i, reward := range rewards {
+		bucketRewards[buckets[i]] = append(bucketRewards[buckets[i]], reward)
+	}
+	for _, sorted := range bucketRewards {
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i] < sorted[j]
+		})
+	}
+
+	for i, reward := range rewards {
+		sorted := bucketRewards[buckets[i]]
+		lower := sort.Search(len(sorted), func(j int) bool { return sorted[j] >= reward })
+		upper := sort.Search(len(sorted), func(j int) bool { return sorted[j] > reward })
+		ranks[i] = float32((float64(lower) + float64(upper-lower)/2) / float64(len(sorted)) * 100)
+	}
+	return ranks
+}
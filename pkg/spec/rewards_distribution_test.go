@@ -0,0 +1,114 @@
+package spec_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestNewRewardsDistribution(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewards []int64
+		median  int64
+		p5      int64
+		p95     int64
+		gini    float64
+	}{
+		{
+			name:    "Empty",
+			rewards: []int64{},
+		},
+		{
+			name:    "Equal rewards",
+			rewards: []int64{10, 10, 10, 10},
+			median:  10,
+			p5:      10,
+			p95:     10,
+			gini:    0,
+		},
+		{
+			name:    "Single earner",
+			rewards: []int64{0, 0, 0, 100},
+			median:  0,
+			p5:      0,
+			p95:     100,
+			gini:    0.75,
+		},
+		{
+			name:    "Unsorted with penalties",
+			rewards: []int64{30, -5, 10, 20},
+			median:  10,
+			p5:      -5,
+			p95:     30,
+			gini:    5.0 / 12.0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dist := spec.NewRewardsDistribution(test.rewards)
+			if dist.Median != test.median {
+				t.Errorf("Median returned %d, expected %d", dist.Median, test.median)
+			}
+			if dist.P5 != test.p5 {
+				t.Errorf("P5 returned %d, expected %d", dist.P5, test.p5)
+			}
+			if dist.P95 != test.p95 {
+				t.Errorf("P95 returned %d, expected %d", dist.P95, test.p95)
+			}
+			if math.Abs(dist.Gini-test.gini) > 1e-9 {
+				t.Errorf("Gini returned %f, expected %f", dist.Gini, test.gini)
+			}
+		})
+	}
+}
+
+func TestRewardPercentileRanks(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewards []int64
+		buckets []uint64
+		ranks   []float32
+	}{
+		{
+			name:    "Empty",
+			rewards: []int64{},
+			buckets: []uint64{},
+			ranks:   []float32{},
+		},
+		{
+			name:    "Single bucket",
+			rewards: []int64{40, 10, 30, 20},
+			buckets: []uint64{32, 32, 32, 32},
+			ranks:   []float32{87.5, 12.5, 62.5, 37.5},
+		},
+		{
+			name:    "Ties",
+			rewards: []int64{10, 10, 20, 20},
+			buckets: []uint64{32, 32, 32, 32},
+			ranks:   []float32{25, 25, 75, 75},
+		},
+		{
+			name:    "Separate buckets",
+			rewards: []int64{100, 10, 20, 5},
+			buckets: []uint64{2048, 32, 32, 2048},
+			ranks:   []float32{75, 25, 75, 25},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ranks := spec.RewardPercentileRanks(test.rewards, test.buckets)
+			if len(ranks) != len(test.ranks) {
+				t.Fatalf("returned %d ranks, expected %d", len(ranks), len(test.ranks))
+			}
+			for i := range ranks {
+				if ranks[i] != test.ranks[i] {
+					t.Errorf("rank %d returned %f, expected %f", i, ranks[i], test.ranks[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// RootKind is the object whose root is compared once finalized
+type RootKind string
+
+const (
+	StateRootKind RootKind = "state"
+	BlockRootKind RootKind = "block"
+)
+
+// RootMismatch is a cached root that differed from the one given by the node once finalized,
+// with the node's justified checkpoint at that moment
+type RootMismatch struct {
+	Slot           phase0.Slot
+	Kind           RootKind
+	CachedRoot     phase0.Root // root stored when the object was downloaded
+	NodeRoot       phase0.Root // root given by the node once finalized
+	JustifiedEpoch phase0.Epoch
+	JustifiedRoot  phase0.Root
+	Timestamp      int64 // unix time at which the mismatch was detected
+}
+
+func (f RootMismatch) Type() ModelType {
+	return RootMismatchModel
+}
+
+func (f RootMismatch) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Slot,
+		f.Kind,
+		f.CachedRoot.String(),
+		f.NodeRoot.String(),
+		f.JustifiedEpoch,
+		f.JustifiedRoot.String(),
+		f.Timestamp,
+	}
+	return rows
+}
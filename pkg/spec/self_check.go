@@ -0,0 +1,126 @@
+package spec
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// SelfCheckKind is the value stored for an epoch that did not match the one reported by the beacon node
+type SelfCheckKind string
+
+const (
+	FinalizedCheckpointCheck         SelfCheckKind = "finalized_checkpoint"
+	CurrentJustifiedCheckpointCheck  SelfCheckKind = "current_justified_checkpoint"
+	PreviousJustifiedCheckpointCheck SelfCheckKind = "previous_justified_checkpoint"
+	BalanceCheck                     SelfCheckKind = "balance"          // balance of a sampled validator
+	SampledBalanceCheck              SelfCheckKind = "sampled_balances" // sum of the balances of the sampled validators
+)
+
+// SelfCheckDiscrepancy is a value stored for an epoch that does not match the one the beacon node reports for the same slot
+type SelfCheckDiscrepancy struct {
+	Epoch          phase0.Epoch
+	Slot           phase0.Slot
+	Kind           SelfCheckKind
+	ValidatorIndex phase0.ValidatorIndex // only for the balance of a validator
+	Computed       string                // as stored in the database
+	Node           string
+}
+
+func (f SelfCheckDiscrepancy) Type() ModelType {
+	return SelfCheckDiscrepancyModel
+}
+
+func (f SelfCheckDiscrepancy) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Epoch,
+		f.Slot,
+		f.Kind,
+		f.ValidatorIndex,
+		f.Computed,
+		f.Node,
+	}
+	return rows
+}
+
+// SelfCheckSample returns the validators whose balances are checked at the given epoch:
+// size indexes evenly spread over the registry, shifted every epoch so the whole registry is covered over time
+func SelfCheckSample(epoch phase0.Epoch, numVals int, size int) []phase0.ValidatorIndex {
+	if numVals == 0 || size <= 0 {
+		return []phase0.ValidatorIndex{}
+	}
+	size = min(size, numVals)
+	stride := numVals / size
+	offset := int(uint64(epoch) % uint64(stride))
+
+	sample := make([]phase0.ValidatorIndex, 0, size)
+	for i := 0; i < size; i++ {
+		sample = append(sample, phase0.ValidatorIndex(offset+i*stride))
+	}
+	return sample
+}
+
+// CompareWithNode returns the finality checkpoints and the balances of the sampled validators stored for the epoch
+// that do not match the ones the beacon node reports for the same slot. Balances are compared in ETH, as stored.
+// Sampled validators without a stored balance are not checked, those missing in the node response count as a 0 balance
+func CompareWithNode(
+	stored EpochCheckpoints,
+	nodeFinalized phase0.Checkpoint,
+	nodeCurrentJustified phase0.Checkpoint,
+	nodePreviousJustified phase0.Checkpoint,
+	storedBalances map[phase0.ValidatorIndex]float32,
+	nodeBalances map[phase0.ValidatorIndex]phase0.Gwei) []SelfCheckDiscrepancy {
+
+	discrepancies := make([]SelfCheckDiscrepancy, 0)
+	newDiscrepancy := func(kind SelfCheckKind, valIdx phase0.ValidatorIndex, computed string, node string) {
+		discrepancies = append(discrepancies, SelfCheckDiscrepancy{
+			Epoch:          stored.Epoch,
+			Slot:           stored.Slot,
+			Kind:           kind,
+			ValidatorIndex: valIdx,
+			Computed:       computed,
+			Node:           node,
+		})
+	}
+
+	checkpoints := []struct {
+		kind     SelfCheckKind
+		computed phase0.Checkpoint
+		node     phase0.Checkpoint
+	}{
+		{FinalizedCheckpointCheck, stored.FinalizedCheckpoint, nodeFinalized},
+		{CurrentJustifiedCheckpointCheck, stored.CurrentJustifiedCheckpoint, nodeCurrentJustified},
+		{PreviousJustifiedCheckpointCheck, stored.PreviousJustifiedCheckpoint, nodePreviousJustified},
+	}
+	for _, checkpoint := range checkpoints {
+		if checkpoint.computed != checkpoint.node {
+			newDiscrepancy(checkpoint.kind, 0, checkpointString(checkpoint.computed), checkpointString(checkpoint.node))
+		}
+	}
+
+	sample := make([]phase0.ValidatorIndex, 0, len(storedBalances))
+	for valIdx := range storedBalances {
+		sample = append(sample, valIdx)
+	}
+	slices.Sort(sample)
+
+	computedSum, nodeSum := float64(0), float64(0)
+	for _, valIdx := range sample {
+		computed := storedBalances[valIdx]
+		nodeBalance := float32(nodeBalances[valIdx]) / EffectiveBalanceInc // same rounding as the stored one
+		computedSum += float64(computed)
+		nodeSum += float64(nodeBalance)
+		if computed != nodeBalance {
+			newDiscrepancy(BalanceCheck, valIdx, fmt.Sprintf("%g", computed), fmt.Sprintf("%g", nodeBalance))
+		}
+	}
+	if computedSum != nodeSum {
+		newDiscrepancy(SampledBalanceCheck, 0, fmt.Sprintf("%.6f", computedSum), fmt.Sprintf("%.6f", nodeSum))
+	}
+	return discrepancies
+}
+
+func checkpointString(checkpoint phase0.Checkpoint) string {
+	return fmt.Sprintf("%d:%s", checkpoint.Epoch, checkpoint.Root)
+}
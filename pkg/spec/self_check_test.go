@@ -0,0 +1,88 @@
+package spec_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestSelfCheckSample(t *testing.T) {
+	tests := []struct {
+		name    string
+		epoch   phase0.Epoch
+		numVals int
+		size    int
+		sample  []phase0.ValidatorIndex
+	}{
+		{
+			name:    "Empty registry",
+			epoch:   10,
+			numVals: 0,
+			size:    4,
+			sample:  []phase0.ValidatorIndex{},
+		},
+		{
+			name:    "Registry smaller than the sample",
+			epoch:   10,
+			numVals: 3,
+			size:    4,
+			sample:  []phase0.ValidatorIndex{0, 1, 2},
+		},
+		{
+			name:    "Evenly spread",
+			epoch:   0,
+			numVals: 100,
+			size:    4,
+			sample:  []phase0.ValidatorIndex{0, 25, 50, 75},
+		},
+		{
+			name:    "Shifted by the epoch",
+			epoch:   27,
+			numVals: 100,
+			size:    4,
+			sample:  []phase0.ValidatorIndex{2, 27, 52, 77},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sample := spec.SelfCheckSample(test.epoch, test.numVals, test.size)
+			if !reflect.DeepEqual(sample, test.sample) {
+				t.Errorf("SelfCheckSample returned %v, expected %v", sample, test.sample)
+			}
+		})
+	}
+}
+
+func TestCompareWithNode(t *testing.T) {
+	stored := spec.EpochCheckpoints{Epoch: 100, Slot: 3231}
+	stored.FinalizedCheckpoint = phase0.Checkpoint{Epoch: 98, Root: phase0.Root{1}}
+	stored.CurrentJustifiedCheckpoint = phase0.Checkpoint{Epoch: 99, Root: phase0.Root{2}}
+	stored.PreviousJustifiedCheckpoint = phase0.Checkpoint{Epoch: 98, Root: phase0.Root{1}}
+
+	storedBalances := map[phase0.ValidatorIndex]float32{
+		1: float32(32_000_012_345) / spec.EffectiveBalanceInc,
+		5: 31.5,
+	}
+	nodeBalances := map[phase0.ValidatorIndex]phase0.Gwei{1: 32_000_012_345, 5: 31_500_000_000, 9: 32_000_000_000}
+
+	discrepancies := spec.CompareWithNode(stored, stored.FinalizedCheckpoint, stored.CurrentJustifiedCheckpoint,
+		stored.PreviousJustifiedCheckpoint, storedBalances, nodeBalances)
+	if len(discrepancies) != 0 {
+		t.Fatalf("CompareWithNode returned %v, expected no discrepancies", discrepancies)
+	}
+
+	nodeBalances[5] = 31_000_000_000
+	discrepancies = spec.CompareWithNode(stored, phase0.Checkpoint{Epoch: 99, Root: phase0.Root{2}}, stored.CurrentJustifiedCheckpoint,
+		stored.PreviousJustifiedCheckpoint, storedBalances, nodeBalances)
+	expected := []spec.SelfCheckDiscrepancy{
+		{Epoch: 100, Slot: 3231, Kind: spec.FinalizedCheckpointCheck, Computed: "98:" + phase0.Root{1}.String(), Node: "99:" + phase0.Root{2}.String()},
+		{Epoch: 100, Slot: 3231, Kind: spec.BalanceCheck, ValidatorIndex: 5, Computed: "31.5", Node: "31"},
+		{Epoch: 100, Slot: 3231, Kind: spec.SampledBalanceCheck, Computed: "63.500011", Node: "63.000011"},
+	}
+	if !reflect.DeepEqual(discrepancies, expected) {
+		t.Errorf("CompareWithNode returned %v, expected %v", discrepancies, expected)
+	}
+}
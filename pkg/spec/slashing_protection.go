@@ -0,0 +1,155 @@
+package spec
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+type ProtectionAnomalyType string
+
+const (
+	AttestationNotInHistory ProtectionAnomalyType = "attestation_not_in_history" // vote included on chain, not signed according to the history
+	BlockNotInHistory       ProtectionAnomalyType = "block_not_in_history"       // block proposed on chain, not signed according to the history
+)
+
+// SlashingProtectionInterchange is an EIP-3076 slashing protection file, as exported by the validator clients.
+// Numbers are strings in the format
+// https://eips.ethereum.org/EIPS/eip-3076
+type SlashingProtectionInterchange struct {
+	Metadata struct {
+		InterchangeFormatVersion string `json:"interchange_format_version"`
+		GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+	} `json:"metadata"`
+	Data []struct {
+		Pubkey       string `json:"pubkey"`
+		SignedBlocks []struct {
+			Slot        string `json:"slot"`
+			SigningRoot string `json:"signing_root,omitempty"`
+		} `json:"signed_blocks"`
+		SignedAttestations []struct {
+			SourceEpoch string `json:"source_epoch"`
+			TargetEpoch string `json:"target_epoch"`
+			SigningRoot string `json:"signing_root,omitempty"`
+		} `json:"signed_attestations"`
+	} `json:"data"`
+}
+
+// ProtectionHistory is what a validator signed according to its slashing protection history
+type ProtectionHistory struct {
+	Pubkey       phase0.BLSPubKey
+	Blocks       map[phase0.Slot]struct{}
+	Attestations map[[2]phase0.Epoch]struct{} // source and target epochs
+	MinSlot      phase0.Slot                  // range of slots covered by the signed blocks
+	MaxSlot      phase0.Slot
+	MinTarget    phase0.Epoch // range of target epochs covered by the signed attestations
+	MaxTarget    phase0.Epoch
+}
+
+// ProtectionAnomaly is a block or vote of a validator included on chain that its slashing protection history
+// does not contain, although it covers that slot or target epoch
+type ProtectionAnomaly struct {
+	ValidatorIndex phase0.ValidatorIndex
+	Pubkey         phase0.BLSPubKey
+	Kind           ProtectionAnomalyType
+	Slot           phase0.Slot // slot of the block proposed or including the vote
+	SourceEpoch    phase0.Epoch
+	TargetEpoch    phase0.Epoch
+}
+
+// ReadSlashingProtectionFile reads an EIP-3076 file, checking the signing history of every validator can be parsed.
+// The complete and the minimal formats are accepted, the latter only covers its last block and vote
+func ReadSlashingProtectionFile(path string) (SlashingProtectionInterchange, error) {
+	var interchange SlashingProtectionInterchange
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return interchange, err
+	}
+	if err := json.Unmarshal(content, &interchange); err != nil {
+		return interchange, fmt.Errorf("could not parse the slashing protection file: %s", err)
+	}
+	if _, err := interchange.Histories(phase0.Root{}); err != nil {
+		return interchange, err
+	}
+	return interchange, nil
+}
+
+// Histories returns the signing history of every validator in the interchange, checking it belongs to the chain
+// with the given genesis validators root (not checked if empty)
+func (p SlashingProtectionInterchange) Histories(genesisValidatorsRoot phase0.Root) ([]*ProtectionHistory, error) {
+	if p.Metadata.InterchangeFormatVersion != "5" {
+		return nil, fmt.Errorf("unsupported slashing protection format version %s, expected 5", p.Metadata.InterchangeFormatVersion)
+	}
+	if genesisValidatorsRoot != (phase0.Root{}) && !strings.EqualFold(p.Metadata.GenesisValidatorsRoot, genesisValidatorsRoot.String()) {
+		return nil, fmt.Errorf("slashing protection file of another chain, genesis validators root %s", p.Metadata.GenesisValidatorsRoot)
+	}
+
+	histories := make([]*ProtectionHistory, 0, len(p.Data))
+	for _, item := range p.Data {
+		pubkeyBytes, err := hex.DecodeString(strings.TrimPrefix(item.Pubkey, "0x"))
+		if err != nil || len(pubkeyBytes) != len(phase0.BLSPubKey{}) {
+			return nil, fmt.Errorf("invalid public key %s", item.Pubkey)
+		}
+		history := &ProtectionHistory{
+			Blocks:       make(map[phase0.Slot]struct{}),
+			Attestations: make(map[[2]phase0.Epoch]struct{}),
+		}
+		copy(history.Pubkey[:], pubkeyBytes)
+
+		for i, block := range item.SignedBlocks {
+			slot, err := strconv.ParseUint(block.Slot, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid slot %s of validator %s", block.Slot, item.Pubkey)
+			}
+			history.Blocks[phase0.Slot(slot)] = struct{}{}
+			if i == 0 || phase0.Slot(slot) < history.MinSlot {
+				history.MinSlot = phase0.Slot(slot)
+			}
+			history.MaxSlot = max(history.MaxSlot, phase0.Slot(slot))
+		}
+		for i, attestation := range item.SignedAttestations {
+			source, err := strconv.ParseUint(attestation.SourceEpoch, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid source epoch %s of validator %s", attestation.SourceEpoch, item.Pubkey)
+			}
+			target, err := strconv.ParseUint(attestation.TargetEpoch, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid target epoch %s of validator %s", attestation.TargetEpoch, item.Pubkey)
+			}
+			history.Attestations[[2]phase0.Epoch{phase0.Epoch(source), phase0.Epoch(target)}] = struct{}{}
+			if i == 0 || phase0.Epoch(target) < history.MinTarget {
+				history.MinTarget = phase0.Epoch(target)
+			}
+			history.MaxTarget = max(history.MaxTarget, phase0.Epoch(target))
+		}
+		histories = append(histories, history)
+	}
+	return histories, nil
+}
+
+// CheckBlock returns whether the block proposed at the slot is in the history, or out of the range it covers
+func (h ProtectionHistory) CheckBlock(slot phase0.Slot) bool {
+	if len(h.Blocks) == 0 || slot < h.MinSlot || slot > h.MaxSlot {
+		return true
+	}
+	_, ok := h.Blocks[slot]
+	return ok
+}
+
+// CheckAttestation returns whether the vote is in the history, or out of the range of target epochs it covers
+func (h ProtectionHistory) CheckAttestation(source phase0.Epoch, target phase0.Epoch) bool {
+	if len(h.Attestations) == 0 || target < h.MinTarget || target > h.MaxTarget {
+		return true
+	}
+	_, ok := h.Attestations[[2]phase0.Epoch{source, target}]
+	return ok
+}
+
+func (f ProtectionAnomaly) Type() ModelType {
+	return ProtectionAnomalyModel
+}
@@ -0,0 +1,63 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+const testInterchange = `{
+	"metadata": {
+		"interchange_format_version": "5",
+		"genesis_validators_root": "0x04700007fabc8282644aed6d1c7c9e21d38a03a0c4ba193f3afe428824b3a673"
+	},
+	"data": [{
+		"pubkey": "0xb845089a1457f811bfc000588fbb4e713669be8ce060ea6be3c6ece09afc3794106c91ca73acda5e5457122d58723bed",
+		"signed_blocks": [{"slot": "81952"}, {"slot": "81984"}],
+		"signed_attestations": [
+			{"source_epoch": "2290", "target_epoch": "3007"},
+			{"source_epoch": "3007", "target_epoch": "3008"}
+		]
+	}]
+}`
+
+func TestSlashingProtectionHistories(t *testing.T) {
+	var interchange local_spec.SlashingProtectionInterchange
+	if err := json.Unmarshal([]byte(testInterchange), &interchange); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interchange.Histories(phase0.Root{0x01}); err == nil {
+		t.Fatal("expected an error for another genesis validators root")
+	}
+	histories, err := interchange.Histories(phase0.Root{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(histories) != 1 {
+		t.Fatalf("expected 1 history, got %d", len(histories))
+	}
+	history := histories[0]
+
+	for slot, expected := range map[phase0.Slot]bool{81952: true, 81984: true, 81960: false, 80000: true, 90000: true} {
+		if history.CheckBlock(slot) != expected {
+			t.Errorf("block at slot %d: expected %t", slot, expected)
+		}
+	}
+	votes := []struct {
+		source, target phase0.Epoch
+		expected       bool
+	}{
+		{2290, 3007, true},
+		{3007, 3008, true},
+		{3006, 3007, false}, // double vote out of the protection
+		{3008, 3009, true},  // signed after the export
+		{100, 200, true},    // before the history
+	}
+	for _, vote := range votes {
+		if history.CheckAttestation(vote.source, vote.target) != vote.expected {
+			t.Errorf("vote %d->%d: expected %t", vote.source, vote.target, vote.expected)
+		}
+	}
+}
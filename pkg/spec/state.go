@@ -2,22 +2,27 @@ package spec
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"math"
 
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/electra"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
 )
 
 // This Wrapper is meant to include all common objects across Ethereum Hard Fork Specs
 type AgnosticState struct {
 	Version                      spec.DataVersion
-	GenesisTimestamp             uint64 // genesis timestamp
+	GenesisTimestamp             uint64      // genesis timestamp
+	GenesisValidatorsRoot        phase0.Root // identifies the chain, signed along with every message
 	StateRoot                    phase0.Root
 	Epoch                        phase0.Epoch                 // Epoch of the state
 	Slot                         phase0.Slot                  // Slot of the state
-	Balances                     []phase0.Gwei                // balance of each validator
+	balances                     []phase0.Gwei                // balance of each validator, references the versioned state (read-only)
 	Validators                   []*phase0.Validator          // list of validators
 	TotalActiveBalance           phase0.Gwei                  // effective balance
 	TotalActiveRealBalance       phase0.Gwei                  // real balance
@@ -38,15 +43,36 @@ type AgnosticState struct {
 	Withdrawals                  []phase0.Gwei                // one position per validator
 	WithdrawalsNum               uint64                       // number of withdrawals
 	TotalWithdrawalsAmount       phase0.Gwei                  // total amount of withdrawals
+	NextWithdrawalValidatorIndex phase0.ValidatorIndex        // next validator in the withdrawals sweep (since Capella)
 	Deposits                     []phase0.Gwei                // one per validator index
 	DepositsNum                  uint64                       // number of deposits
 	TotalDepositsAmount          phase0.Gwei                  // total amount of deposits
+	JustificationBits            bitfield.Bitvector4          // justification of the last 4 epochs, bit 0 is the current one
+	PreviousJustifiedCheckpoint  phase0.Checkpoint            // the justified checkpoint before the latest one
 	CurrentJustifiedCheckpoint   phase0.Checkpoint            // the latest justified checkpoint
+	FinalizedCheckpoint          phase0.Checkpoint            // the latest finalized checkpoint
 	LatestBlockHeader            *phase0.BeaconBlockHeader
 	SyncCommitteeParticipation   uint64 // Tracks sync committee participation
 	NewProposerSlashings         int    // number of new proposer slashings
 	NewAttesterSlashings         int    // number of new attester slashings
 	Slashings                    []AgnosticSlashing
+
+	PendingConsolidations     []*electra.PendingConsolidation     // consolidations waiting to be processed (since Electra)
+	PendingDeposits           []*electra.PendingDeposit           // deposits waiting to be processed (since Electra)
+	PendingPartialWithdrawals []*electra.PendingPartialWithdrawal // partial withdrawal requests waiting to be processed (since Electra)
+	DepositBalanceToConsume   phase0.Gwei                         // deposit churn carried over from the previous epoch
+	EarliestExitEpoch         phase0.Epoch                        // exit epoch of the last exit in the queue
+
+	CurrentParticipation EpochParticipation // participation in the state epoch so far (since Altair)
+	InactivityScores     []uint64           // inactivity score of each validator, references the versioned state (read-only) (since Altair)
+
+	RandaoMixes []phase0.Root // randao mixes of the last epochs, references the versioned state (read-only)
+
+	EffectiveBalanceHistogram []EffectiveBalanceBucket // effective balances of the active validators
+
+	Proof *StateProof // merkle proofs of a sample of validators against the state root, nil if not verified
+
+	Substitution *DataQualityNote // set when this state replaced the one of another slot, which could not be decoded
 }
 
 func GetCustomState(bstate spec.VersionedBeaconState, duties EpochDuties) (AgnosticState, error) {
@@ -65,6 +91,8 @@ func GetCustomState(bstate spec.VersionedBeaconState, duties EpochDuties) (Agnos
 		return NewCapellaState(bstate, duties), nil
 	case spec.DataVersionDeneb:
 		return NewDenebState(bstate, duties), nil
+	case spec.DataVersionElectra:
+		return NewElectraState(bstate, duties), nil
 	default:
 		return AgnosticState{}, fmt.Errorf("could not figure out the Beacon State Fork Version: %s", bstate.Version)
 	}
@@ -109,7 +137,7 @@ func (p *AgnosticState) CalculateSyncParticipation() {
 
 func (p *AgnosticState) CalculateNumAttestations() {
 	for _, block := range p.Blocks {
-		p.NumAttestations += len(block.Attestations)
+		p.NumAttestations += block.NumAttestations()
 	}
 }
 
@@ -162,9 +190,14 @@ func (p AgnosticState) ValsEffectiveBalance(valList []phase0.Gwei) phase0.Gwei {
 func (p *AgnosticState) GetTotalActiveEffBalance() phase0.Gwei {
 
 	val_array := make([]phase0.Gwei, len(p.Validators))
+	p.EffectiveBalanceHistogram = newEffectiveBalanceHistogram(p.Epoch)
 	for idx := range val_array {
 		if IsActive(*p.Validators[idx], phase0.Epoch(p.Epoch)) {
 			val_array[idx] += 1
+			effectiveBalance := p.Validators[idx].EffectiveBalance
+			bucket := &p.EffectiveBalanceHistogram[EffectiveBalanceBucketIndex(effectiveBalance)]
+			bucket.Validators++
+			bucket.EffectiveBalance += effectiveBalance
 		}
 	}
 
@@ -185,7 +218,7 @@ func (p AgnosticState) GetTotalActiveRealBalance() phase0.Gwei {
 
 	for idx := range p.Validators {
 		if IsActive(*p.Validators[idx], phase0.Epoch(p.Epoch)) {
-			totalBalance += p.Balances[idx]
+			totalBalance += p.Balance(phase0.ValidatorIndex(idx))
 		}
 
 	}
@@ -279,6 +312,21 @@ func (p AgnosticState) GetMissingFlagCount(flagIndex int) uint64 {
 	return result
 }
 
+// GetAnyAttestingBalance returns the effective balance of the validators with at least one correct flag
+// in the previous epoch attestations, the union of the attesting balances of the flags
+func (p AgnosticState) GetAnyAttestingBalance() phase0.Gwei {
+	balance := phase0.Gwei(0)
+	for valIdx, validator := range p.Validators {
+		for _, flags := range p.PrevEpochCorrectFlags {
+			if valIdx < len(flags) && flags[valIdx] {
+				balance += validator.EffectiveBalance
+				break
+			}
+		}
+	}
+	return balance
+}
+
 func (p AgnosticState) GetValStatus(valIdx phase0.ValidatorIndex) ValidatorStatus {
 	// if the validator index is not in the list, return QUEUE_STATUS. Goteth should be designed to avoid this situation
 	// but by the way that the validator rewards are calculated, it is possible that the index is not in the list
@@ -303,6 +351,42 @@ func (p AgnosticState) GetValStatus(valIdx phase0.ValidatorIndex) ValidatorStatu
 
 }
 
+// GetValDetailedStatus returns the status of the validator as the beacon API computes it for the state:
+// https://hackmd.io/ofFJ5gOmQpu1jjHilHbdQQ
+func (p AgnosticState) GetValDetailedStatus(valIdx phase0.ValidatorIndex) ValidatorDetailedStatus {
+	// same as GetValStatus, the index may not be in the state yet
+	if int(valIdx) >= len(p.Validators) {
+		return PENDING_INITIALIZED_STATUS
+	}
+	validator := p.Validators[valIdx]
+	epoch := phase0.Epoch(p.Epoch)
+
+	switch {
+	case validator.ActivationEpoch > epoch:
+		if validator.ActivationEligibilityEpoch == farFutureEpoch {
+			return PENDING_INITIALIZED_STATUS
+		}
+		return PENDING_QUEUED_STATUS
+	case validator.ExitEpoch > epoch:
+		if validator.ExitEpoch == farFutureEpoch {
+			return ACTIVE_ONGOING_STATUS
+		}
+		if validator.Slashed {
+			return ACTIVE_SLASHED_STATUS
+		}
+		return ACTIVE_EXITING_STATUS
+	case validator.WithdrawableEpoch > epoch:
+		if validator.Slashed {
+			return EXITED_SLASHED_STATUS
+		}
+		return EXITED_UNSLASHED_STATUS
+	case p.Balance(valIdx) == 0:
+		return WITHDRAWAL_DONE_STATUS
+	default:
+		return WITHDRAWAL_POSSIBLE_STATUS
+	}
+}
+
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#get_block_root
 func (p AgnosticState) GetBlockRoot(epoch phase0.Epoch) phase0.Root {
 
@@ -317,6 +401,44 @@ func (p AgnosticState) GetBlockRootAtSlot(slot phase0.Slot) phase0.Root {
 	return p.BlockRoots[slot%SlotsPerHistoricalRoot]
 }
 
+// RandaoMix returns the randao mix of the state epoch, accumulated with the reveals of its blocks so far
+func (p AgnosticState) RandaoMix() phase0.Root {
+	if len(p.RandaoMixes) == 0 {
+		return phase0.Root{}
+	}
+	return p.RandaoMixes[uint64(p.Epoch)%uint64(len(p.RandaoMixes))]
+}
+
+// AttesterSeed returns the seed used to shuffle the beacon committees of the state epoch, with the given network config
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#get_seed
+func (p AgnosticState) AttesterSeed(config CommitteeConfig) phase0.Root {
+	if len(p.RandaoMixes) == 0 {
+		return phase0.Root{}
+	}
+	mixes := uint64(len(p.RandaoMixes))
+	mix := p.RandaoMixes[(uint64(p.Epoch)+mixes-config.MinSeedLookahead-1)%mixes]
+
+	data := make([]byte, 0, 44)
+	data = append(data, config.DomainBeaconAttester[:]...)
+	data = binary.LittleEndian.AppendUint64(data, uint64(p.Epoch))
+	data = append(data, mix[:]...)
+	return sha256.Sum256(data)
+}
+
+// Balance returns the balance of the given validator, 0 if the validator is not in the state
+// balances are not copied from the downloaded state, they are accessed through here
+func (p AgnosticState) Balance(valIdx phase0.ValidatorIndex) phase0.Gwei {
+	if int(valIdx) >= len(p.balances) {
+		return 0
+	}
+	return p.balances[valIdx]
+}
+
+// NumBalances returns the number of balances in the state
+func (p AgnosticState) NumBalances() int {
+	return len(p.balances)
+}
+
 // https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md#get_block_root_at_slot
 func (p AgnosticState) EmptyStateRoot() bool {
 
@@ -326,25 +448,25 @@ func (p AgnosticState) EmptyStateRoot() bool {
 // This Wrapper is meant to include all necessary data from the Phase0 Fork
 func NewPhase0State(bstate spec.VersionedBeaconState, duties EpochDuties) AgnosticState {
 
-	balances := make([]phase0.Gwei, 0)
-
-	for _, item := range bstate.Phase0.Balances {
-		balances = append(balances, phase0.Gwei(item))
-	}
-
 	phase0Obj := AgnosticState{
 
 		Version:                    bstate.Version,
-		Balances:                   balances,
+		balances:                   bstate.Phase0.Balances,
 		Validators:                 bstate.Phase0.Validators,
 		EpochStructs:               duties,
 		Epoch:                      phase0.Epoch(bstate.Phase0.Slot / SlotsPerEpoch),
 		Slot:                       phase0.Slot(bstate.Phase0.Slot),
 		BlockRoots:                 bstate.Phase0.BlockRoots,
+		RandaoMixes:                bstate.Phase0.RANDAOMixes,
 		PrevAttestations:           bstate.Phase0.PreviousEpochAttestations,
 		GenesisTimestamp:           bstate.Phase0.GenesisTime,
+		GenesisValidatorsRoot:      bstate.Phase0.GenesisValidatorsRoot,
 		CurrentJustifiedCheckpoint: *bstate.Phase0.CurrentJustifiedCheckpoint,
 		LatestBlockHeader:          bstate.Phase0.LatestBlockHeader,
+
+		JustificationBits:           bstate.Phase0.JustificationBits,
+		PreviousJustifiedCheckpoint: *bstate.Phase0.PreviousJustifiedCheckpoint,
+		FinalizedCheckpoint:         *bstate.Phase0.FinalizedCheckpoint,
 	}
 
 	phase0Obj.Setup()
@@ -358,21 +480,29 @@ func NewAltairState(bstate spec.VersionedBeaconState, duties EpochDuties) Agnost
 
 	altairObj := AgnosticState{
 		Version:                    bstate.Version,
-		Balances:                   bstate.Altair.Balances,
+		balances:                   bstate.Altair.Balances,
 		Validators:                 bstate.Altair.Validators,
 		EpochStructs:               duties,
 		Epoch:                      phase0.Epoch(bstate.Altair.Slot / SlotsPerEpoch),
 		Slot:                       bstate.Altair.Slot,
 		BlockRoots:                 bstate.Altair.BlockRoots,
+		RandaoMixes:                bstate.Altair.RANDAOMixes,
+		InactivityScores:           bstate.Altair.InactivityScores,
 		SyncCommittee:              *bstate.Altair.CurrentSyncCommittee,
 		GenesisTimestamp:           bstate.Altair.GenesisTime,
+		GenesisValidatorsRoot:      bstate.Altair.GenesisValidatorsRoot,
 		CurrentJustifiedCheckpoint: *bstate.Altair.CurrentJustifiedCheckpoint,
 		LatestBlockHeader:          bstate.Altair.LatestBlockHeader,
+
+		JustificationBits:           bstate.Altair.JustificationBits,
+		PreviousJustifiedCheckpoint: *bstate.Altair.PreviousJustifiedCheckpoint,
+		FinalizedCheckpoint:         *bstate.Altair.FinalizedCheckpoint,
 	}
 
 	altairObj.Setup()
 
 	ProcessAltairAttestations(&altairObj, bstate.Altair.PreviousEpochParticipation)
+	ProcessCurrentParticipation(&altairObj, bstate.Altair.CurrentEpochParticipation)
 
 	return altairObj
 }
@@ -409,21 +539,29 @@ func NewBellatrixState(bstate spec.VersionedBeaconState, duties EpochDuties) Agn
 
 	bellatrixObj := AgnosticState{
 		Version:                    bstate.Version,
-		Balances:                   bstate.Bellatrix.Balances,
+		balances:                   bstate.Bellatrix.Balances,
 		Validators:                 bstate.Bellatrix.Validators,
 		EpochStructs:               duties,
 		Epoch:                      phase0.Epoch(bstate.Bellatrix.Slot / SlotsPerEpoch),
 		Slot:                       bstate.Bellatrix.Slot,
 		BlockRoots:                 bstate.Bellatrix.BlockRoots,
+		RandaoMixes:                bstate.Bellatrix.RANDAOMixes,
+		InactivityScores:           bstate.Bellatrix.InactivityScores,
 		SyncCommittee:              *bstate.Bellatrix.CurrentSyncCommittee,
 		GenesisTimestamp:           bstate.Bellatrix.GenesisTime,
+		GenesisValidatorsRoot:      bstate.Bellatrix.GenesisValidatorsRoot,
 		CurrentJustifiedCheckpoint: *bstate.Bellatrix.CurrentJustifiedCheckpoint,
 		LatestBlockHeader:          bstate.Bellatrix.LatestBlockHeader,
+
+		JustificationBits:           bstate.Bellatrix.JustificationBits,
+		PreviousJustifiedCheckpoint: *bstate.Bellatrix.PreviousJustifiedCheckpoint,
+		FinalizedCheckpoint:         *bstate.Bellatrix.FinalizedCheckpoint,
 	}
 
 	bellatrixObj.Setup()
 
 	ProcessAltairAttestations(&bellatrixObj, bstate.Bellatrix.PreviousEpochParticipation)
+	ProcessCurrentParticipation(&bellatrixObj, bstate.Bellatrix.CurrentEpochParticipation)
 
 	return bellatrixObj
 }
@@ -433,21 +571,31 @@ func NewCapellaState(bstate spec.VersionedBeaconState, duties EpochDuties) Agnos
 
 	capellaObj := AgnosticState{
 		Version:                    bstate.Version,
-		Balances:                   bstate.Capella.Balances,
+		balances:                   bstate.Capella.Balances,
 		Validators:                 bstate.Capella.Validators,
 		EpochStructs:               duties,
 		Epoch:                      phase0.Epoch(bstate.Capella.Slot / SlotsPerEpoch),
 		Slot:                       bstate.Capella.Slot,
 		BlockRoots:                 bstate.Capella.BlockRoots,
+		RandaoMixes:                bstate.Capella.RANDAOMixes,
+		InactivityScores:           bstate.Capella.InactivityScores,
 		SyncCommittee:              *bstate.Capella.CurrentSyncCommittee,
 		GenesisTimestamp:           bstate.Capella.GenesisTime,
+		GenesisValidatorsRoot:      bstate.Capella.GenesisValidatorsRoot,
 		CurrentJustifiedCheckpoint: *bstate.Capella.CurrentJustifiedCheckpoint,
 		LatestBlockHeader:          bstate.Capella.LatestBlockHeader,
+
+		JustificationBits:           bstate.Capella.JustificationBits,
+		PreviousJustifiedCheckpoint: *bstate.Capella.PreviousJustifiedCheckpoint,
+		FinalizedCheckpoint:         *bstate.Capella.FinalizedCheckpoint,
+
+		NextWithdrawalValidatorIndex: bstate.Capella.NextWithdrawalValidatorIndex,
 	}
 
 	capellaObj.Setup()
 
 	ProcessAltairAttestations(&capellaObj, bstate.Capella.PreviousEpochParticipation)
+	ProcessCurrentParticipation(&capellaObj, bstate.Capella.CurrentEpochParticipation)
 
 	return capellaObj
 }
@@ -457,21 +605,82 @@ func NewDenebState(bstate spec.VersionedBeaconState, duties EpochDuties) Agnosti
 
 	denebObj := AgnosticState{
 		Version:                    bstate.Version,
-		Balances:                   bstate.Deneb.Balances,
+		balances:                   bstate.Deneb.Balances,
 		Validators:                 bstate.Deneb.Validators,
 		EpochStructs:               duties,
 		Epoch:                      phase0.Epoch(bstate.Deneb.Slot / SlotsPerEpoch),
 		Slot:                       bstate.Deneb.Slot,
 		BlockRoots:                 bstate.Deneb.BlockRoots,
+		RandaoMixes:                bstate.Deneb.RANDAOMixes,
+		InactivityScores:           bstate.Deneb.InactivityScores,
 		SyncCommittee:              *bstate.Deneb.CurrentSyncCommittee,
 		GenesisTimestamp:           bstate.Deneb.GenesisTime,
+		GenesisValidatorsRoot:      bstate.Deneb.GenesisValidatorsRoot,
 		CurrentJustifiedCheckpoint: *bstate.Deneb.CurrentJustifiedCheckpoint,
 		LatestBlockHeader:          bstate.Deneb.LatestBlockHeader,
+
+		JustificationBits:           bstate.Deneb.JustificationBits,
+		PreviousJustifiedCheckpoint: *bstate.Deneb.PreviousJustifiedCheckpoint,
+		FinalizedCheckpoint:         *bstate.Deneb.FinalizedCheckpoint,
+
+		NextWithdrawalValidatorIndex: bstate.Deneb.NextWithdrawalValidatorIndex,
 	}
 
 	denebObj.Setup()
 
 	ProcessAltairAttestations(&denebObj, bstate.Deneb.PreviousEpochParticipation)
+	ProcessCurrentParticipation(&denebObj, bstate.Deneb.CurrentEpochParticipation)
 
 	return denebObj
 }
+
+func NewElectraState(bstate spec.VersionedBeaconState, duties EpochDuties) AgnosticState {
+
+	electraObj := AgnosticState{
+		Version:                    bstate.Version,
+		balances:                   bstate.Electra.Balances,
+		Validators:                 bstate.Electra.Validators,
+		EpochStructs:               duties,
+		Epoch:                      phase0.Epoch(bstate.Electra.Slot / SlotsPerEpoch),
+		Slot:                       bstate.Electra.Slot,
+		BlockRoots:                 bstate.Electra.BlockRoots,
+		RandaoMixes:                bstate.Electra.RANDAOMixes,
+		InactivityScores:           bstate.Electra.InactivityScores,
+		SyncCommittee:              *bstate.Electra.CurrentSyncCommittee,
+		GenesisTimestamp:           bstate.Electra.GenesisTime,
+		GenesisValidatorsRoot:      bstate.Electra.GenesisValidatorsRoot,
+		CurrentJustifiedCheckpoint: *bstate.Electra.CurrentJustifiedCheckpoint,
+		LatestBlockHeader:          bstate.Electra.LatestBlockHeader,
+
+		JustificationBits:           bstate.Electra.JustificationBits,
+		PreviousJustifiedCheckpoint: *bstate.Electra.PreviousJustifiedCheckpoint,
+		FinalizedCheckpoint:         *bstate.Electra.FinalizedCheckpoint,
+
+		NextWithdrawalValidatorIndex: bstate.Electra.NextWithdrawalValidatorIndex,
+
+		PendingConsolidations:     bstate.Electra.PendingConsolidations,
+		PendingDeposits:           bstate.Electra.PendingDeposits,
+		PendingPartialWithdrawals: bstate.Electra.PendingPartialWithdrawals,
+		DepositBalanceToConsume:   bstate.Electra.DepositBalanceToConsume,
+		EarliestExitEpoch:         bstate.Electra.EarliestExitEpoch,
+	}
+
+	electraObj.Setup()
+
+	ProcessAltairAttestations(&electraObj, bstate.Electra.PreviousEpochParticipation)
+	ProcessCurrentParticipation(&electraObj, bstate.Electra.CurrentEpochParticipation)
+
+	return electraObj
+}
+
+// GenesisPrevState returns the state to be used as previous state when the current one
+// is at the genesis epoch: same registry and committees, but no blocks nor participation,
+// as there is no epoch before genesis
+func (p AgnosticState) GenesisPrevState() *AgnosticState {
+	prevState := p
+	prevState.Blocks = nil
+	prevState.PrevAttestations = nil
+	prevState.NumAttestations = 0
+	prevState.MissedBlocks = nil
+	return &prevState
+}
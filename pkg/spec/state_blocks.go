@@ -0,0 +1,118 @@
+package spec
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+const eth1AddressWithdrawalPrefix = 0x01
+
+// BlocksFromState builds the blocks of the epoch with the information the state contains:
+// proposer, whether the slot was missed and the block root. Bodies are left empty.
+// Used when block bodies are not downloaded (epochs-only mode)
+func (p AgnosticState) BlocksFromState() []*AgnosticBlock {
+	blocks := make([]*AgnosticBlock, 0, SlotsPerEpoch)
+	firstSlot := phase0.Slot(p.Epoch * SlotsPerEpoch)
+
+	for slot := firstSlot; slot < firstSlot+SlotsPerEpoch; slot++ {
+		block := &AgnosticBlock{
+			Slot:              slot,
+			Proposed:          !slices.Contains(p.MissedBlocks, slot),
+			Attestations:      make([]*phase0.Attestation, 0),
+			Deposits:          make([]*phase0.Deposit, 0),
+			ProposerSlashings: make([]*phase0.ProposerSlashing, 0),
+			AttesterSlashings: make([]*phase0.AttesterSlashing, 0),
+			VoluntaryExits:    make([]*phase0.SignedVoluntaryExit, 0),
+			SyncAggregate: &altair.SyncAggregate{
+				SyncCommitteeBits: bitfield.NewBitvector512(),
+			},
+			ExecutionPayload: AgnosticExecutionPayload{
+				Transactions: make([]bellatrix.Transaction, 0),
+				Withdrawals:  make([]*capella.Withdrawal, 0),
+			},
+		}
+		for _, duty := range p.EpochStructs.ProposerDuties {
+			if duty.Slot == slot {
+				block.ProposerIndex = duty.ValidatorIndex
+			}
+		}
+		if block.Proposed {
+			block.Root = p.blockRootFromState(slot)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// the root of the block at the state slot is not in the block roots yet, but its header is
+func (p AgnosticState) blockRootFromState(slot phase0.Slot) phase0.Root {
+	if slot < p.Slot {
+		return p.GetBlockRootAtSlot(slot)
+	}
+	if p.LatestBlockHeader == nil {
+		return phase0.Root{}
+	}
+	header := *p.LatestBlockHeader
+	header.StateRoot = p.StateRoot // filled by the next slot processing, not yet in the state
+	root, err := header.HashTreeRoot()
+	if err != nil {
+		log.Errorf("could not compute the block root at slot %d: %s", slot, err)
+		return phase0.Root{}
+	}
+	return root
+}
+
+// EstimateWithdrawals returns the withdrawals of the validators swept since the previous state,
+// taking the amounts from the previous state balances. The sweep stops at MaxWithdrawalsPerPayload
+// withdrawals per block, so there are no more than that for each block proposed in the epoch.
+// Withdrawals are usually read from the blocks, this is meant for when they are not downloaded
+func (p AgnosticState) EstimateWithdrawals(prevState *AgnosticState) []*capella.Withdrawal {
+	withdrawals := make([]*capella.Withdrawal, 0)
+	numVals := phase0.ValidatorIndex(len(p.Validators))
+	if numVals == 0 {
+		return withdrawals
+	}
+	maxWithdrawals := MaxWithdrawalsPerPayload * max(int(SlotsPerEpoch)-len(p.MissedBlocks), 0)
+
+	// the sweep moves cyclically over the validator set
+	for valIdx := prevState.NextWithdrawalValidatorIndex % numVals; valIdx != p.NextWithdrawalValidatorIndex%numVals; valIdx = (valIdx + 1) % numVals {
+		if len(withdrawals) >= maxWithdrawals {
+			break
+		}
+		if int(valIdx) >= len(prevState.Validators) || int(valIdx) >= prevState.NumBalances() {
+			continue // new validator, nothing to withdraw
+		}
+		validator := p.Validators[valIdx]
+		if !bytes.HasPrefix(validator.WithdrawalCredentials, []byte{eth1AddressWithdrawalPrefix}) && !HasCompoundingCredentials(validator) {
+			continue
+		}
+		balance := prevState.Balance(valIdx)
+		maxBalance := MaxEffectiveBalance(validator)
+
+		amount := phase0.Gwei(0)
+		switch {
+		case validator.WithdrawableEpoch <= p.Epoch: // full withdrawal
+			amount = balance
+		case validator.EffectiveBalance == maxBalance && balance > maxBalance: // partial withdrawal
+			amount = balance - maxBalance
+		}
+		if amount == 0 {
+			continue
+		}
+
+		var address bellatrix.ExecutionAddress
+		copy(address[:], validator.WithdrawalCredentials[12:])
+		withdrawals = append(withdrawals, &capella.Withdrawal{
+			ValidatorIndex: valIdx,
+			Address:        address,
+			Amount:         amount,
+		})
+	}
+	return withdrawals
+}
@@ -0,0 +1,90 @@
+package spec_test
+
+import (
+	"testing"
+
+	eth2spec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// capellaState builds the state at the last slot of the epoch, every validator with a partial withdrawal
+// of 1 ETH pending, and the given slots of the epoch missed
+func capellaState(epoch phase0.Epoch, numVals int, nextWithdrawal phase0.ValidatorIndex, missed ...phase0.Slot) spec.AgnosticState {
+	lastSlot := phase0.Slot(epoch+1)*spec.SlotsPerEpoch - 1
+	bstate := &capella.BeaconState{
+		Slot:                         lastSlot,
+		BlockRoots:                   make([]phase0.Root, spec.SlotsPerHistoricalRoot),
+		CurrentSyncCommittee:         &altair.SyncCommittee{},
+		CurrentJustifiedCheckpoint:   &phase0.Checkpoint{},
+		PreviousJustifiedCheckpoint:  &phase0.Checkpoint{},
+		FinalizedCheckpoint:          &phase0.Checkpoint{},
+		LatestBlockHeader:            &phase0.BeaconBlockHeader{Slot: lastSlot},
+		NextWithdrawalValidatorIndex: nextWithdrawal,
+	}
+	for slot := range bstate.BlockRoots {
+		bstate.BlockRoots[slot] = phase0.Root{byte(slot), byte(slot >> 8), 1}
+	}
+	for _, slot := range missed {
+		bstate.BlockRoots[slot] = bstate.BlockRoots[slot-1]
+	}
+	for i := 0; i < numVals; i++ {
+		credentials := make([]byte, 32)
+		credentials[0] = 0x01
+		bstate.Validators = append(bstate.Validators, &phase0.Validator{
+			WithdrawalCredentials: credentials,
+			EffectiveBalance:      32 * spec.EffectiveBalanceInc,
+			ActivationEpoch:       0,
+			ExitEpoch:             phase0.Epoch(^uint64(0)),
+			WithdrawableEpoch:     phase0.Epoch(^uint64(0)),
+		})
+		bstate.Balances = append(bstate.Balances, 33*spec.EffectiveBalanceInc)
+	}
+	return spec.NewCapellaState(eth2spec.VersionedBeaconState{Version: eth2spec.DataVersionCapella, Capella: bstate}, spec.EpochDuties{})
+}
+
+func TestEstimateWithdrawals(t *testing.T) {
+	tests := []struct {
+		name     string
+		swept    phase0.ValidatorIndex // validators swept in the epoch
+		missed   []phase0.Slot
+		expected int
+	}{
+		{
+			name:     "Withdrawals under the limit",
+			swept:    100,
+			expected: 100,
+		},
+		{
+			name:     "Capped at the withdrawals of every block",
+			swept:    600,
+			expected: 32 * spec.MaxWithdrawalsPerPayload,
+		},
+		{
+			name:     "Capped at the withdrawals of the proposed blocks",
+			swept:    600,
+			missed:   []phase0.Slot{70, 80},
+			expected: 30 * spec.MaxWithdrawalsPerPayload,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prevState := capellaState(1, 1000, 0)
+			state := capellaState(2, 1000, test.swept, test.missed...)
+
+			withdrawals := state.EstimateWithdrawals(&prevState)
+			if len(withdrawals) != test.expected {
+				t.Fatalf("EstimateWithdrawals returned %d withdrawals, expected %d", len(withdrawals), test.expected)
+			}
+			for i, withdrawal := range withdrawals {
+				if withdrawal.ValidatorIndex != phase0.ValidatorIndex(i) || withdrawal.Amount != spec.EffectiveBalanceInc {
+					t.Errorf("withdrawal %d of validator %d and %d gwei, expected validator %d and %d gwei",
+						i, withdrawal.ValidatorIndex, withdrawal.Amount, i, spec.EffectiveBalanceInc)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ssz "github.com/ferranbt/fastssz"
+)
+
+const (
+	validatorsFieldIndex = 11     // position of the validators in the beacon state container (all forks)
+	balancesFieldIndex   = 12     // position of the balances in the beacon state container (all forks)
+	registryLimitDepth   = 40     // VALIDATOR_REGISTRY_LIMIT = 2**40
+	balancesPerChunk     = 4      // uint64 balances packed in each 32 byte leaf
+	balancesLimitDepth   = 40 - 2 // leaves of the balances list
+	stateDepth           = 5      // up to 32 fields before Electra
+	electraStateDepth    = 6      // the state container has more than 32 fields since Electra
+	proofLeafSize        = 32     // bytes of each node of the tree
+)
+
+// StateProof is the result of verifying the balances and validators of a sample of validators
+// against the state root with SSZ Merkle proofs
+type StateProof struct {
+	Epoch      phase0.Epoch
+	Slot       phase0.Slot
+	StateRoot  phase0.Root
+	Validators int                     // validators whose record and balance were proven
+	Failed     []phase0.ValidatorIndex // validators whose proofs did not match the state root
+	Error      string                  // why the proofs could not be computed, if so
+	Timestamp  int64                   // unix time of the verification
+}
+
+func (f StateProof) Type() ModelType {
+	return StateProofModel
+}
+
+// Verified returns whether every proof matched the state root
+func (f StateProof) Verified() bool {
+	return f.Error == "" && len(f.Failed) == 0
+}
+
+// validatorGeneralizedIndex returns the generalized index of the root of a validator in the state tree
+func validatorGeneralizedIndex(depth int, valIdx phase0.ValidatorIndex) int {
+	listRoot := (1 << depth) + validatorsFieldIndex
+	return (listRoot*2)<<registryLimitDepth + int(valIdx) // left child of the list root holds the items
+}
+
+// balanceGeneralizedIndex returns the generalized index of the leaf holding the balance of a validator
+func balanceGeneralizedIndex(depth int, valIdx phase0.ValidatorIndex) int {
+	listRoot := (1 << depth) + balancesFieldIndex
+	return (listRoot*2)<<balancesLimitDepth + int(valIdx)/balancesPerChunk
+}
+
+// stateTree returns the SSZ tree of the versioned state and the depth of its container
+func stateTree(bstate spec.VersionedBeaconState) (*ssz.Node, int, error) {
+	switch bstate.Version {
+	case spec.DataVersionPhase0:
+		tree, err := bstate.Phase0.GetTree()
+		return tree, stateDepth, err
+	case spec.DataVersionAltair:
+		tree, err := bstate.Altair.GetTree()
+		return tree, stateDepth, err
+	case spec.DataVersionBellatrix:
+		tree, err := bstate.Bellatrix.GetTree()
+		return tree, stateDepth, err
+	case spec.DataVersionCapella:
+		tree, err := bstate.Capella.GetTree()
+		return tree, stateDepth, err
+	case spec.DataVersionDeneb:
+		tree, err := bstate.Deneb.GetTree()
+		return tree, stateDepth, err
+	case spec.DataVersionElectra:
+		tree, err := bstate.Electra.GetTree()
+		return tree, electraStateDepth, err
+	default:
+		return nil, 0, fmt.Errorf("could not figure out the Beacon State Fork Version: %s", bstate.Version)
+	}
+}
+
+// VerifyStateProofs proves the record and the balance of the given validators, as parsed in the agnostic state,
+// against the state root. The tree of the whole state is built, which takes several GB of memory on mainnet
+func VerifyStateProofs(bstate spec.VersionedBeaconState, state *AgnosticState, sample []phase0.ValidatorIndex) ([]phase0.ValidatorIndex, error) {
+	tree, depth, err := stateTree(bstate)
+	if err != nil {
+		return nil, fmt.Errorf("could not build the state tree: %s", err)
+	}
+
+	failed := make([]phase0.ValidatorIndex, 0)
+	for _, valIdx := range sample {
+		if int(valIdx) >= len(state.Validators) {
+			return nil, fmt.Errorf("validator %d is not in the state", valIdx)
+		}
+
+		validatorRoot, err := state.Validators[valIdx].HashTreeRoot()
+		if err != nil {
+			return nil, fmt.Errorf("could not hash validator %d: %s", valIdx, err)
+		}
+		validatorOk, err := verifyLeaf(tree, state.StateRoot, validatorGeneralizedIndex(depth, valIdx), validatorRoot[:])
+		if err != nil {
+			return nil, fmt.Errorf("could not prove validator %d: %s", valIdx, err)
+		}
+
+		balanceOk, err := verifyLeaf(tree, state.StateRoot, balanceGeneralizedIndex(depth, valIdx), balancesChunk(state, valIdx))
+		if err != nil {
+			return nil, fmt.Errorf("could not prove the balance of validator %d: %s", valIdx, err)
+		}
+
+		if !validatorOk || !balanceOk {
+			failed = append(failed, valIdx)
+		}
+	}
+	return failed, nil
+}
+
+// verifyLeaf checks that the proof of the leaf at the generalized index reaches the root and that the leaf
+// holds the expected value
+func verifyLeaf(tree *ssz.Node, root phase0.Root, index int, expected []byte) (bool, error) {
+	proof, err := tree.Prove(index)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.Equal(proof.Leaf, expected) {
+		return false, nil
+	}
+	return ssz.VerifyProof(root[:], proof)
+}
+
+// balancesChunk returns the leaf packing the balance of the validator with its neighbours
+func balancesChunk(state *AgnosticState, valIdx phase0.ValidatorIndex) []byte {
+	chunk := make([]byte, proofLeafSize)
+	first := int(valIdx) / balancesPerChunk * balancesPerChunk
+	for i := 0; i < balancesPerChunk && first+i < state.NumBalances(); i++ {
+		binary.LittleEndian.PutUint64(chunk[i*8:], uint64(state.Balance(phase0.ValidatorIndex(first+i))))
+	}
+	return chunk
+}
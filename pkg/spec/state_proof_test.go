@@ -0,0 +1,69 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestVerifyStateProofs(t *testing.T) {
+	bstate := &phase0.BeaconState{
+		Slot:                        phase0.Slot(3 * local_spec.SlotsPerEpoch),
+		Fork:                        &phase0.Fork{},
+		LatestBlockHeader:           &phase0.BeaconBlockHeader{},
+		BlockRoots:                  make([]phase0.Root, local_spec.SlotsPerHistoricalRoot),
+		StateRoots:                  make([]phase0.Root, local_spec.SlotsPerHistoricalRoot),
+		ETH1Data:                    &phase0.ETH1Data{BlockHash: make([]byte, 32)},
+		RANDAOMixes:                 make([]phase0.Root, 65536),
+		Slashings:                   make([]phase0.Gwei, 8192),
+		JustificationBits:           bitfield.NewBitvector4(),
+		PreviousJustifiedCheckpoint: &phase0.Checkpoint{},
+		CurrentJustifiedCheckpoint:  &phase0.Checkpoint{},
+		FinalizedCheckpoint:         &phase0.Checkpoint{},
+	}
+	for i := 0; i < 11; i++ {
+		bstate.Validators = append(bstate.Validators, &phase0.Validator{
+			PublicKey:             phase0.BLSPubKey{byte(i)},
+			WithdrawalCredentials: make([]byte, 32),
+			EffectiveBalance:      32000000000,
+			ExitEpoch:             farFuture,
+			WithdrawableEpoch:     farFuture,
+		})
+		bstate.Balances = append(bstate.Balances, phase0.Gwei(32000000000+i))
+	}
+	root, err := bstate.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("could not hash the state: %s", err)
+	}
+
+	versioned := spec.VersionedBeaconState{Version: spec.DataVersionPhase0, Phase0: bstate}
+	state, err := local_spec.GetCustomState(versioned, local_spec.EpochDuties{})
+	if err != nil {
+		t.Fatalf("could not read the state: %s", err)
+	}
+	state.StateRoot = root
+	sample := []phase0.ValidatorIndex{0, 5, 10}
+
+	failed, err := local_spec.VerifyStateProofs(versioned, &state, sample)
+	if err != nil || len(failed) != 0 {
+		t.Errorf("VerifyStateProofs returned %v (%v), expected no failed validators", failed, err)
+	}
+
+	state.Validators = append([]*phase0.Validator{}, state.Validators...)
+	tampered := *state.Validators[5]
+	tampered.Slashed = true
+	state.Validators[5] = &tampered
+	failed, err = local_spec.VerifyStateProofs(versioned, &state, sample)
+	if err != nil || len(failed) != 1 || failed[0] != 5 {
+		t.Errorf("VerifyStateProofs of a tampered validator returned %v (%v), expected %v", failed, err, []phase0.ValidatorIndex{5})
+	}
+
+	state.StateRoot = phase0.Root{1}
+	failed, err = local_spec.VerifyStateProofs(versioned, &state, sample)
+	if err != nil || len(failed) != len(sample) {
+		t.Errorf("VerifyStateProofs with another root returned %v (%v), expected %v", failed, err, sample)
+	}
+}
@@ -0,0 +1,37 @@
+package spec_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestGenesisPrevState(t *testing.T) {
+	genesis := spec.AgnosticState{
+		StateRoot:          phase0.Root{1},
+		Validators:         []*phase0.Validator{{EffectiveBalance: 32_000_000_000}},
+		TotalActiveBalance: 32_000_000_000,
+		EpochStructs:       spec.EpochDuties{ValidatorAttSlot: map[phase0.ValidatorIndex]phase0.Slot{0: 3}},
+		Blocks:             []*spec.AgnosticBlock{{Slot: 0, Proposed: true}},
+		PrevAttestations:   []*phase0.PendingAttestation{{InclusionDelay: 1}},
+		NumAttestations:    1,
+		MissedBlocks:       []phase0.Slot{1},
+	}
+
+	prevState := genesis.GenesisPrevState()
+	// same registry and committees
+	if prevState.Epoch != 0 || prevState.StateRoot != genesis.StateRoot || !reflect.DeepEqual(prevState.Validators, genesis.Validators) ||
+		prevState.TotalActiveBalance != genesis.TotalActiveBalance || !reflect.DeepEqual(prevState.EpochStructs, genesis.EpochStructs) {
+		t.Errorf("the previous state of genesis does not keep its registry and committees")
+	}
+	// no blocks nor participation
+	if prevState.Blocks != nil || prevState.PrevAttestations != nil || prevState.NumAttestations != 0 || prevState.MissedBlocks != nil {
+		t.Errorf("the previous state of genesis has blocks or participation")
+	}
+	// the genesis state is not modified
+	if len(genesis.Blocks) != 1 || len(genesis.PrevAttestations) != 1 || genesis.NumAttestations != 1 || len(genesis.MissedBlocks) != 1 {
+		t.Errorf("the genesis state was modified")
+	}
+}
@@ -0,0 +1,121 @@
+//go:build ignore
+
+// gen_state writes the altair state fixture of the attesting balance and sync committee reward tests, taken at the
+// last slot of epoch 320: 64 active validators whose previous epoch participation follows the groups below, each
+// of them 8 times in the sync committee, and the blocks of slots 10241, 10242 and 10250 missed (10271, the state
+// slot, is proposed or missed after the state). Run it from this directory:
+//
+//	go run gen_state.go
+//
+//	validators  effective balance  previous epoch flags
+//	0-47        32 ETH             index % 8 (every combination six times)
+//	48-51       2048 ETH           source
+//	52-55       2048 ETH           source and target
+//	56-63       16 ETH             head (late source and target)
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+const (
+	file       = "altair_state.ssz.gz"
+	slot       = 320*32 + 31 // last slot of epoch 320
+	validators = 64
+	ethGwei    = 1_000_000_000
+	farFuture  = phase0.Epoch(0xffffffffffffffff)
+)
+
+var missed = map[phase0.Slot]bool{10241: true, 10242: true, 10250: true}
+
+func main() {
+	state := &altair.BeaconState{
+		GenesisTime: 1606824023,
+		Slot:        slot,
+		Fork: &phase0.Fork{
+			PreviousVersion: phase0.Version{0, 0, 0, 0},
+			CurrentVersion:  phase0.Version{1, 0, 0, 0},
+		},
+		LatestBlockHeader:           &phase0.BeaconBlockHeader{Slot: slot},
+		BlockRoots:                  make([]phase0.Root, 8192),
+		StateRoots:                  make([]phase0.Root, 8192),
+		ETH1Data:                    &phase0.ETH1Data{BlockHash: make([]byte, 32)},
+		RANDAOMixes:                 make([]phase0.Root, 65536),
+		Slashings:                   make([]phase0.Gwei, 8192),
+		JustificationBits:           bitfield.NewBitvector4(),
+		PreviousJustifiedCheckpoint: &phase0.Checkpoint{Epoch: 318},
+		CurrentJustifiedCheckpoint:  &phase0.Checkpoint{Epoch: 319},
+		FinalizedCheckpoint:         &phase0.Checkpoint{Epoch: 318},
+		CurrentSyncCommittee:        syncCommittee(),
+		NextSyncCommittee:           syncCommittee(),
+	}
+
+	// the root of the last block at every slot before the state slot, the previous one if missed
+	for root, s := (phase0.Root{}), phase0.Slot(slot-32); s < slot; s++ {
+		if !missed[s] {
+			root = phase0.Root{byte(s), byte(s >> 8), 1}
+		}
+		state.BlockRoots[s%8192] = root
+	}
+
+	for i := 0; i < validators; i++ {
+		balance, flags := group(i)
+		state.Validators = append(state.Validators, &phase0.Validator{
+			PublicKey:                  phase0.BLSPubKey{byte(i), 1},
+			WithdrawalCredentials:      make([]byte, 32),
+			EffectiveBalance:           balance,
+			ActivationEligibilityEpoch: 0,
+			ActivationEpoch:            0,
+			ExitEpoch:                  farFuture,
+			WithdrawableEpoch:          farFuture,
+		})
+		state.Balances = append(state.Balances, balance)
+		state.PreviousEpochParticipation = append(state.PreviousEpochParticipation, flags)
+		state.CurrentEpochParticipation = append(state.CurrentEpochParticipation, 0)
+		state.InactivityScores = append(state.InactivityScores, 0)
+	}
+
+	encoded, err := state.MarshalSSZ()
+	if err != nil {
+		panic(err)
+	}
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(encoded); err != nil {
+		panic(err)
+	}
+	if err := writer.Close(); err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(file, buffer.Bytes(), 0o644); err != nil {
+		panic(err)
+	}
+}
+
+// group returns the effective balance and the previous epoch flags of the validator
+func group(i int) (phase0.Gwei, altair.ParticipationFlags) {
+	switch {
+	case i < 48:
+		return 32 * ethGwei, altair.ParticipationFlags(i % 8)
+	case i < 52:
+		return 2048 * ethGwei, 1
+	case i < 56:
+		return 2048 * ethGwei, 3
+	default:
+		return 16 * ethGwei, 4
+	}
+}
+
+func syncCommittee() *altair.SyncCommittee {
+	committee := &altair.SyncCommittee{Pubkeys: make([]phase0.BLSPubKey, 512)}
+	for i := range committee.Pubkeys {
+		committee.Pubkeys[i] = phase0.BLSPubKey{byte(i % validators), 1}
+	}
+	return committee
+}
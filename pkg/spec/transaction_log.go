@@ -0,0 +1,51 @@
+package spec
+
+import (
+	"encoding/hex"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TransactionLog is a log emitted by a transaction, only stored with the full transactions storage
+type TransactionLog struct {
+	Slot     phase0.Slot
+	TxHash   phase0.Hash32
+	LogIndex uint64 // index of the log in the block
+	Address  common.Address
+	Topics   []common.Hash // up to 4
+	Data     string
+}
+
+func (f TransactionLog) Type() ModelType {
+	return TransactionLogModel
+}
+
+// Topic returns the topic at the given position, empty if the log has less topics
+func (f TransactionLog) Topic(i int) string {
+	if i >= len(f.Topics) {
+		return ""
+	}
+	return f.Topics[i].String()
+}
+
+// TransactionLogs returns the receipt logs of the given transactions
+func TransactionLogs(txs []AgnosticTransaction) []TransactionLog {
+	logs := make([]TransactionLog, 0)
+	for _, tx := range txs {
+		if tx.Receipt == nil {
+			continue
+		}
+		for _, logEntry := range tx.Receipt.Logs {
+			logs = append(logs, TransactionLog{
+				Slot:     tx.Slot,
+				TxHash:   tx.Hash,
+				LogIndex: uint64(logEntry.Index),
+				Address:  logEntry.Address,
+				Topics:   logEntry.Topics,
+				Data:     hex.EncodeToString(logEntry.Data),
+			})
+		}
+	}
+	return logs
+}
@@ -2,6 +2,7 @@ package spec
 
 import (
 	"encoding/hex"
+	"fmt"
 
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
@@ -13,6 +14,23 @@ var (
 	blobTxType uint8 = 3
 )
 
+// TxStorage defines how much of each transaction is stored
+type TxStorage string
+
+const (
+	LightTxStorage TxStorage = "light" // hash, addresses, value, gas and fees, without calldata
+	FullTxStorage  TxStorage = "full"  // also the calldata, the receipt logs and the category
+)
+
+func NewTxStorage(input string) (TxStorage, error) {
+	switch TxStorage(input) {
+	case LightTxStorage, FullTxStorage:
+		return TxStorage(input), nil
+	default:
+		return "", fmt.Errorf("unknown transactions storage %s, expected light or full", input)
+	}
+}
+
 // A wrapper for blockchain transaction with basic information retrieved from the Ethereum blockchain
 type AgnosticTransaction struct {
 	TxType          uint8           // type of transaction: LegacyTxType, AccessListTxType, or DynamicFeeTxType
@@ -32,6 +50,8 @@ type AgnosticTransaction struct {
 	BlockNumber     uint64          // the number of the block where this transaction was added
 	Timestamp       uint64          // timestamp of the block to which this transaction belongs
 	ContractAddress common.Address  // address of the smart contract associated with this transaction
+	Category        TxCategory      // kind of activity, empty unless the calldata is stored
+	HeaderOnly      bool            // parsed without receipt: gas is the limit, price the fee cap
 
 	// Blobs
 	BlobHashes    []common.Hash
@@ -48,34 +68,64 @@ func (txs AgnosticTransaction) Type() ModelType {
 	return TransactionsModel
 }
 
-func ParseTransactionsFromBlock(block AgnosticBlock, receipts []*types.Receipt) ([]AgnosticTransaction, error) {
+// ParseTransactionsFromBlock matches the transactions of the block with their receipts.
+// The light storage does not encode the calldata, the receipts are kept in both (i.e. for the eth1 deposits)
+func ParseTransactionsFromBlock(block AgnosticBlock, receipts []*types.Receipt, storage TxStorage) ([]AgnosticTransaction, error) {
 	agnosticTxs := make([]AgnosticTransaction, 0)
 	txs := make([]bellatrix.Transaction, len(block.ExecutionPayload.Transactions))
 	copy(txs, block.ExecutionPayload.Transactions)
 
 	// match receipts and transactions
+	receiptsByHash := make(map[common.Hash]*types.Receipt, len(receipts))
+	for _, receipt := range receipts {
+		receiptsByHash[receipt.TxHash] = receipt
+	}
 
 	for _, tx := range txs {
 		var parsedTx = &types.Transaction{}
 		if err := parsedTx.UnmarshalBinary(tx); err != nil {
 			return nil, err
 		}
-		for _, receipt := range receipts {
-			if receipt.TxHash.String() == parsedTx.Hash().String() {
-				// we found a match
-				agnosticTx, err := ParseTransactionFromReceipt(
-					*parsedTx,
-					receipt,
-					block.Slot,
-					block.ExecutionPayload.BlockNumber,
-					block.ExecutionPayload.Timestamp)
-				if err != nil {
-					return nil, err
-				}
-				agnosticTxs = append(agnosticTxs, agnosticTx)
-				break
-			}
+		receipt, ok := receiptsByHash[parsedTx.Hash()]
+		if !ok {
+			continue
+		}
+		agnosticTx, err := parseTransaction(
+			parsedTx,
+			receipt,
+			block.Slot,
+			block.ExecutionPayload.BlockNumber,
+			block.ExecutionPayload.Timestamp,
+			storage == FullTxStorage)
+		if err != nil {
+			return nil, err
+		}
+		agnosticTxs = append(agnosticTxs, agnosticTx)
+	}
+	return agnosticTxs, nil
+}
+
+// ParseHeaderOnlyTransactions parses the transactions of the block without their receipts,
+// for runs without execution endpoint. Gas used, effective price and contract address are unknown
+func ParseHeaderOnlyTransactions(block AgnosticBlock, storage TxStorage) ([]AgnosticTransaction, error) {
+	agnosticTxs := make([]AgnosticTransaction, 0, len(block.ExecutionPayload.Transactions))
+	for _, tx := range block.ExecutionPayload.Transactions {
+		var parsedTx = &types.Transaction{}
+		if err := parsedTx.UnmarshalBinary(tx); err != nil {
+			return nil, err
+		}
+		agnosticTx, err := parseTransaction(
+			parsedTx,
+			nil,
+			block.Slot,
+			block.ExecutionPayload.BlockNumber,
+			block.ExecutionPayload.Timestamp,
+			storage == FullTxStorage)
+		if err != nil {
+			return nil, err
 		}
+		agnosticTx.HeaderOnly = true
+		agnosticTxs = append(agnosticTxs, agnosticTx)
 	}
 	return agnosticTxs, nil
 }
@@ -86,8 +136,18 @@ func ParseTransactionFromReceipt(
 	slot phase0.Slot,
 	blockNumber uint64,
 	timestamp uint64) (AgnosticTransaction, error) {
+	return parseTransaction(&parsedTx, receipt, slot, blockNumber, timestamp, true)
+}
 
-	from, err := types.Sender(types.LatestSignerForChainID(parsedTx.ChainId()), &parsedTx)
+func parseTransaction(
+	parsedTx *types.Transaction,
+	receipt *types.Receipt,
+	slot phase0.Slot,
+	blockNumber uint64,
+	timestamp uint64,
+	withData bool) (AgnosticTransaction, error) {
+
+	from, err := types.Sender(types.LatestSignerForChainID(parsedTx.ChainId()), parsedTx)
 	if err != nil {
 		log.Warnf("unable to retrieve sender address from transaction: %s", err)
 		return AgnosticTransaction{}, err
@@ -108,16 +168,23 @@ func ParseTransactionFromReceipt(
 	}
 
 	if parsedTx.Type() == blobTxType {
-		blobGasUsed = receipt.BlobGasUsed
-		blobGasPrice = receipt.BlobGasPrice.Uint64()
+		if receipt != nil {
+			blobGasUsed = receipt.BlobGasUsed
+			blobGasPrice = receipt.BlobGasPrice.Uint64()
+		}
 		blobGasLimit = parsedTx.BlobGas()
 		blobGasFeeCap = parsedTx.BlobGasFeeCap().Uint64()
 	}
 
-	return AgnosticTransaction{
+	data := ""
+	if withData {
+		data = hex.EncodeToString(parsedTx.Data())
+	}
+
+	agnosticTx := AgnosticTransaction{
 		TxType:          parsedTx.Type(),
 		ChainId:         uint8(parsedTx.ChainId().Uint64()),
-		Data:            hex.EncodeToString(parsedTx.Data()),
+		Data:            data,
 		Gas:             gasUsed,
 		GasPrice:        gasPrice,
 		GasTipCap:       parsedTx.GasTipCap().Uint64(),
@@ -138,6 +205,9 @@ func ParseTransactionFromReceipt(
 		BlobGasFeeCap:   blobGasFeeCap,
 		BlobHashes:      parsedTx.BlobHashes(),
 		Receipt:         receipt,
-	}, nil
-
+	}
+	if withData {
+		agnosticTx.Category = ClassifyTransaction(agnosticTx)
+	}
+	return agnosticTx, nil
 }
@@ -0,0 +1,58 @@
+package spec_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestParseHeaderOnlyTransactions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa")
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     7,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(30),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(100),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := spec.AgnosticBlock{Slot: 100}
+	block.ExecutionPayload.Transactions = []bellatrix.Transaction{encoded}
+	txs, err := spec.ParseHeaderOnlyTransactions(block, spec.LightTxStorage)
+	if err != nil {
+		t.Fatalf("ParseHeaderOnlyTransactions returned error %s", err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("ParseHeaderOnlyTransactions returned %d transactions, expected 1", len(txs))
+	}
+	parsed := txs[0]
+	if !parsed.HeaderOnly || parsed.Receipt != nil {
+		t.Errorf("ParseHeaderOnlyTransactions returned header only %v, expected true without receipt", parsed.HeaderOnly)
+	}
+	if parsed.Gas != 21000 || parsed.GasPrice != 30 || parsed.Nonce != 7 || parsed.Slot != 100 {
+		t.Errorf("ParseHeaderOnlyTransactions returned gas %d, price %d, nonce %d and slot %d, expected 21000, 30, 7 and 100",
+			parsed.Gas, parsed.GasPrice, parsed.Nonce, parsed.Slot)
+	}
+	if parsed.From != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("ParseHeaderOnlyTransactions returned sender %s, expected %s", parsed.From, crypto.PubkeyToAddress(key.PublicKey))
+	}
+}
@@ -0,0 +1,60 @@
+package spec
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxCategory is the kind of activity of a transaction, only classified with the full transactions storage
+type TxCategory string
+
+const (
+	ContractDeployTx TxCategory = "contract_deploy"
+	ERC20TransferTx  TxCategory = "erc20_transfer"
+	ERC721TransferTx TxCategory = "erc721_transfer"
+	PlainTransferTx  TxCategory = "transfer"
+	ContractCallTx   TxCategory = "contract_call"
+)
+
+var (
+	// keccak256("Transfer(address,address,uint256)"), shared by ERC-20 and ERC-721
+	transferEventTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+)
+
+// ClassifyTransaction returns the category of the transaction from its recipient, calldata and receipt logs.
+// ERC-20 and ERC-721 transfers emit the same event, ERC-721 ones index the token id (4 topics)
+func ClassifyTransaction(tx AgnosticTransaction) TxCategory {
+	if tx.To == nil {
+		return ContractDeployTx
+	}
+
+	category := TxCategory("")
+	if tx.Receipt != nil {
+		category = tokenTransferCategory(tx.Receipt.Logs)
+	}
+	if category != "" {
+		return category
+	}
+
+	if tx.Data == "" {
+		return PlainTransferTx
+	}
+	return ContractCallTx
+}
+
+// tokenTransferCategory returns the kind of token transferred by the logs, ERC-721 transfers first, empty if none
+func tokenTransferCategory(logs []*types.Log) TxCategory {
+	category := TxCategory("")
+	for _, logEntry := range logs {
+		if len(logEntry.Topics) == 0 || logEntry.Topics[0] != transferEventTopic {
+			continue
+		}
+		switch len(logEntry.Topics) {
+		case 4:
+			return ERC721TransferTx
+		case 3:
+			category = ERC20TransferTx
+		}
+	}
+	return category
+}
@@ -0,0 +1,63 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestClassifyTransaction(t *testing.T) {
+	transferTopic := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	to := common.HexToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa")
+	erc20Log := &types.Log{Topics: []common.Hash{transferTopic, {1}, {2}}}
+	erc721Log := &types.Log{Topics: []common.Hash{transferTopic, {1}, {2}, {3}}}
+	otherLog := &types.Log{Topics: []common.Hash{{9}}}
+
+	tests := []struct {
+		name     string
+		tx       spec.AgnosticTransaction
+		category spec.TxCategory
+	}{
+		{
+			name:     "Contract deployment",
+			tx:       spec.AgnosticTransaction{Data: "6080", Receipt: &types.Receipt{}},
+			category: spec.ContractDeployTx,
+		},
+		{
+			name:     "Plain transfer",
+			tx:       spec.AgnosticTransaction{To: &to, Receipt: &types.Receipt{}},
+			category: spec.PlainTransferTx,
+		},
+		{
+			name:     "Contract call",
+			tx:       spec.AgnosticTransaction{To: &to, Data: "a9059cbb", Receipt: &types.Receipt{Logs: []*types.Log{otherLog}}},
+			category: spec.ContractCallTx,
+		},
+		{
+			name:     "ERC-20 transfer",
+			tx:       spec.AgnosticTransaction{To: &to, Data: "a9059cbb", Receipt: &types.Receipt{Logs: []*types.Log{otherLog, erc20Log}}},
+			category: spec.ERC20TransferTx,
+		},
+		{
+			name:     "ERC-721 transfer with ERC-20 payment",
+			tx:       spec.AgnosticTransaction{To: &to, Data: "23b872dd", Receipt: &types.Receipt{Logs: []*types.Log{erc20Log, erc721Log}}},
+			category: spec.ERC721TransferTx,
+		},
+		{
+			name:     "No receipt",
+			tx:       spec.AgnosticTransaction{To: &to, Data: "a9059cbb"},
+			category: spec.ContractCallTx,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			category := spec.ClassifyTransaction(test.tx)
+			if category != test.category {
+				t.Errorf("ClassifyTransaction returned %s, expected %s", category, test.category)
+			}
+		})
+	}
+}
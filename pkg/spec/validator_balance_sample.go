@@ -0,0 +1,26 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ValidatorBalanceSample is the balance of a monitored validator in the state of a slot,
+// to locate balance drops within an epoch
+type ValidatorBalanceSample struct {
+	Slot           phase0.Slot
+	ValidatorIndex phase0.ValidatorIndex
+	Balance        phase0.Gwei
+}
+
+func (f ValidatorBalanceSample) Type() ModelType {
+	return ValidatorBalanceSampleModel
+}
+
+func (f ValidatorBalanceSample) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Slot,
+		f.ValidatorIndex,
+		f.Balance,
+	}
+	return rows
+}
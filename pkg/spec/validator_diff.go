@@ -0,0 +1,127 @@
+package spec
+
+import (
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ValidatorSnapshot is the balance and status of a validator at the end of an epoch
+type ValidatorSnapshot struct {
+	Index   phase0.ValidatorIndex
+	Balance phase0.Gwei
+	Status  ValidatorStatus
+	Pool    string // empty if the validator is not tied to a pool
+}
+
+// PoolDiff is the change of the validators of a pool between two epochs
+type PoolDiff struct {
+	Pool          string
+	ValidatorsA   uint64
+	ValidatorsB   uint64
+	ActiveA       uint64
+	ActiveB       uint64
+	BalanceA      phase0.Gwei
+	BalanceB      phase0.Gwei
+	BalanceChange int64 // of the validators in both epochs, deposits of new validators are not counted
+}
+
+// ValidatorSetDiff is the change of the validator set between two epochs
+type ValidatorSetDiff struct {
+	EpochA        phase0.Epoch
+	EpochB        phase0.Epoch
+	New           []phase0.ValidatorIndex // not in the set at EpochA
+	Activations   []phase0.ValidatorIndex
+	Exits         []phase0.ValidatorIndex
+	Slashings     []phase0.ValidatorIndex
+	StatusA       []uint64 // validators per status, indexed by ValidatorStatus
+	StatusB       []uint64
+	BalanceA      phase0.Gwei
+	BalanceB      phase0.Gwei
+	BalanceChange int64 // of the validators in both epochs, deposits of new validators are not counted
+	Pools         []PoolDiff
+}
+
+// DiffValidators compares the validator sets of two epochs. A validator counts as activated, exited or slashed
+// if it was in a different status at EpochA (or not in the set yet)
+func DiffValidators(epochA phase0.Epoch, a []ValidatorSnapshot, epochB phase0.Epoch, b []ValidatorSnapshot) ValidatorSetDiff {
+	diff := ValidatorSetDiff{
+		EpochA:      epochA,
+		EpochB:      epochB,
+		New:         make([]phase0.ValidatorIndex, 0),
+		Activations: make([]phase0.ValidatorIndex, 0),
+		Exits:       make([]phase0.ValidatorIndex, 0),
+		Slashings:   make([]phase0.ValidatorIndex, 0),
+		StatusA:     make([]uint64, NUMBER_OF_STATUS),
+		StatusB:     make([]uint64, NUMBER_OF_STATUS),
+		Pools:       make([]PoolDiff, 0),
+	}
+	pools := make(map[string]*PoolDiff)
+	poolDiff := func(pool string) *PoolDiff {
+		if _, ok := pools[pool]; !ok {
+			pools[pool] = &PoolDiff{Pool: pool}
+		}
+		return pools[pool]
+	}
+
+	before := make(map[phase0.ValidatorIndex]ValidatorSnapshot, len(a))
+	for _, val := range a {
+		before[val.Index] = val
+		diff.StatusA[val.Status]++
+		diff.BalanceA += val.Balance
+		if val.Pool != "" {
+			pool := poolDiff(val.Pool)
+			pool.ValidatorsA++
+			pool.BalanceA += val.Balance
+			if val.Status == ACTIVE_STATUS {
+				pool.ActiveA++
+			}
+		}
+	}
+
+	for _, val := range b {
+		diff.StatusB[val.Status]++
+		diff.BalanceB += val.Balance
+
+		var pool *PoolDiff
+		if val.Pool != "" {
+			pool = poolDiff(val.Pool)
+			pool.ValidatorsB++
+			pool.BalanceB += val.Balance
+			if val.Status == ACTIVE_STATUS {
+				pool.ActiveB++
+			}
+		}
+
+		prev, ok := before[val.Index]
+		if ok {
+			change := int64(val.Balance) - int64(prev.Balance)
+			diff.BalanceChange += change
+			if pool != nil {
+				pool.BalanceChange += change
+			}
+		} else {
+			diff.New = append(diff.New, val.Index)
+			prev.Status = QUEUE_STATUS
+		}
+		if prev.Status == val.Status {
+			continue
+		}
+		switch val.Status {
+		case ACTIVE_STATUS:
+			diff.Activations = append(diff.Activations, val.Index)
+		case EXIT_STATUS:
+			diff.Exits = append(diff.Exits, val.Index)
+		case SLASHED_STATUS:
+			diff.Slashings = append(diff.Slashings, val.Index)
+		}
+	}
+
+	for _, pool := range pools {
+		diff.Pools = append(diff.Pools, *pool)
+	}
+	sort.Slice(diff.Pools, func(i, j int) bool {
+		return diff.Pools[i].Pool < diff.Pools[j].Pool
+	})
+	return diff
+}
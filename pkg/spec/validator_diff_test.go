@@ -0,0 +1,59 @@
+package spec_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestDiffValidators(t *testing.T) {
+	a := []spec.ValidatorSnapshot{
+		{Index: 0, Balance: 32_000_000_000, Status: spec.ACTIVE_STATUS, Pool: "pool"},
+		{Index: 1, Balance: 32_000_000_000, Status: spec.ACTIVE_STATUS, Pool: "pool"},
+		{Index: 2, Balance: 32_000_000_000, Status: spec.ACTIVE_STATUS},
+		{Index: 3, Balance: 32_000_000_000, Status: spec.QUEUE_STATUS},
+	}
+	b := []spec.ValidatorSnapshot{
+		{Index: 0, Balance: 32_000_100_000, Status: spec.ACTIVE_STATUS, Pool: "pool"},
+		{Index: 1, Balance: 31_000_000_000, Status: spec.SLASHED_STATUS, Pool: "pool"},
+		{Index: 2, Balance: 32_000_000_000, Status: spec.EXIT_STATUS},
+		{Index: 3, Balance: 32_000_000_000, Status: spec.ACTIVE_STATUS},
+		{Index: 4, Balance: 32_000_000_000, Status: spec.QUEUE_STATUS, Pool: "other"},
+	}
+	diff := spec.DiffValidators(10, a, 20, b)
+
+	tests := []struct {
+		name     string
+		result   []phase0.ValidatorIndex
+		expected []phase0.ValidatorIndex
+	}{
+		{"new", diff.New, []phase0.ValidatorIndex{4}},
+		{"activations", diff.Activations, []phase0.ValidatorIndex{3}},
+		{"exits", diff.Exits, []phase0.ValidatorIndex{2}},
+		{"slashings", diff.Slashings, []phase0.ValidatorIndex{1}},
+	}
+	for _, test := range tests {
+		if !slices.Equal(test.result, test.expected) {
+			t.Errorf("DiffValidators returned %s %v, expected %v", test.name, test.result, test.expected)
+		}
+	}
+
+	if diff.BalanceChange != -999_900_000 {
+		t.Errorf("DiffValidators returned a balance change of %d, expected %d", diff.BalanceChange, -999_900_000)
+	}
+	if diff.StatusA[spec.ACTIVE_STATUS] != 3 || diff.StatusB[spec.ACTIVE_STATUS] != 2 {
+		t.Errorf("DiffValidators returned %d and %d active validators, expected 3 and 2",
+			diff.StatusA[spec.ACTIVE_STATUS], diff.StatusB[spec.ACTIVE_STATUS])
+	}
+
+	expectedPools := []spec.PoolDiff{
+		{Pool: "other", ValidatorsB: 1, BalanceB: 32_000_000_000},
+		{Pool: "pool", ValidatorsA: 2, ValidatorsB: 2, ActiveA: 2, ActiveB: 1,
+			BalanceA: 64_000_000_000, BalanceB: 63_000_100_000, BalanceChange: -999_900_000},
+	}
+	if !slices.Equal(diff.Pools, expectedPools) {
+		t.Errorf("DiffValidators returned pools %+v, expected %+v", diff.Pools, expectedPools)
+	}
+}
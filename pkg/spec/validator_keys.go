@@ -0,0 +1,48 @@
+package spec
+
+import (
+	"bytes"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ValidatorKey links the public key of a validator to its index, for joins by public key without the state
+type ValidatorKey struct {
+	ValIdx                phase0.ValidatorIndex
+	PublicKey             phase0.BLSPubKey
+	ActivationEpoch       phase0.Epoch
+	WithdrawalCredentials []byte
+	Epoch                 phase0.Epoch // epoch of the state the entry was read from
+}
+
+func (f ValidatorKey) Type() ModelType {
+	return ValidatorKeyModel
+}
+
+// ValidatorKeys returns the validators of the state that are new, or whose activation epoch or withdrawal
+// credentials changed, with respect to the previous state. All of them are returned without previous state
+func ValidatorKeys(prevState *AgnosticState, state *AgnosticState) []ValidatorKey {
+	known := 0
+	if prevState != nil {
+		known = min(len(prevState.Validators), len(state.Validators))
+	}
+
+	keys := make([]ValidatorKey, 0)
+	for i, validator := range state.Validators {
+		if i < known {
+			prev := prevState.Validators[i]
+			if prev == validator || (prev.ActivationEpoch == validator.ActivationEpoch &&
+				bytes.Equal(prev.WithdrawalCredentials, validator.WithdrawalCredentials)) {
+				continue
+			}
+		}
+		keys = append(keys, ValidatorKey{
+			ValIdx:                phase0.ValidatorIndex(i),
+			PublicKey:             validator.PublicKey,
+			ActivationEpoch:       validator.ActivationEpoch,
+			WithdrawalCredentials: validator.WithdrawalCredentials,
+			Epoch:                 state.Epoch,
+		})
+	}
+	return keys
+}
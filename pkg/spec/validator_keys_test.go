@@ -0,0 +1,50 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestValidatorKeys(t *testing.T) {
+	validator := func(key byte, activation phase0.Epoch, prefix byte) *phase0.Validator {
+		credentials := make([]byte, 32)
+		credentials[0] = prefix
+		return &phase0.Validator{
+			PublicKey:             phase0.BLSPubKey{key},
+			ActivationEpoch:       activation,
+			WithdrawalCredentials: credentials,
+		}
+	}
+	shared := validator(0, 10, 0x01)
+	prevState := &spec.AgnosticState{
+		Epoch:      99,
+		Validators: []*phase0.Validator{shared, validator(1, 10, 0x00), validator(2, farFuture, 0x01), validator(3, 10, 0x01)},
+	}
+	state := &spec.AgnosticState{
+		Epoch: 100,
+		Validators: []*phase0.Validator{
+			shared,
+			validator(1, 10, 0x01),        // credentials changed
+			validator(2, 105, 0x01),       // activation epoch set
+			validator(3, 10, 0x01),        // same values, different entry
+			validator(4, farFuture, 0x01), // new
+		},
+	}
+
+	keys := spec.ValidatorKeys(prevState, state)
+	expected := []phase0.ValidatorIndex{1, 2, 4}
+	if len(keys) != len(expected) {
+		t.Fatalf("ValidatorKeys returned %d keys, expected %d", len(keys), len(expected))
+	}
+	for i, key := range keys {
+		if key.ValIdx != expected[i] || key.PublicKey != state.Validators[key.ValIdx].PublicKey || key.Epoch != 100 {
+			t.Errorf("ValidatorKeys returned validator %d at epoch %d, expected validator %d at epoch 100", key.ValIdx, key.Epoch, expected[i])
+		}
+	}
+
+	if keys := spec.ValidatorKeys(nil, state); len(keys) != len(state.Validators) {
+		t.Errorf("ValidatorKeys without previous state returned %d keys, expected %d", len(keys), len(state.Validators))
+	}
+}
@@ -9,6 +9,7 @@ type ValidatorLastStatus struct {
 	Epoch           phase0.Epoch
 	CurrentBalance  phase0.Gwei
 	CurrentStatus   ValidatorStatus
+	DetailedStatus  ValidatorDetailedStatus
 	Slashed         bool
 	ActivationEpoch phase0.Epoch
 	WithdrawalEpoch phase0.Epoch
@@ -27,6 +28,7 @@ func (f ValidatorLastStatus) ToArray() []interface{} {
 	resultArgs = append(resultArgs, f.WithdrawalEpoch)
 	resultArgs = append(resultArgs, f.ExitEpoch)
 	resultArgs = append(resultArgs, f.PublicKey.String())
+	resultArgs = append(resultArgs, f.DetailedStatus)
 	return resultArgs
 }
 
@@ -0,0 +1,50 @@
+package spec
+
+import (
+	"bytes"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ShareValidatorRegistry replaces the validator entries that did not change with respect to
+// the given (consecutive) state by the entries of that state, so both states point to the same objects.
+// Most of the registry stays the same between epochs, so the duplicated entries can be released.
+// Validator entries must be treated as read-only once shared.
+// Returns the number of shared entries
+func (p *AgnosticState) ShareValidatorRegistry(other *AgnosticState) int {
+	if other == nil || other.Validators == nil || p.Validators == nil {
+		return 0
+	}
+
+	shared := 0
+	limit := len(p.Validators)
+	if len(other.Validators) < limit {
+		limit = len(other.Validators)
+	}
+
+	for i := 0; i < limit; i++ {
+		if p.Validators[i] == other.Validators[i] {
+			shared++ // already shared
+			continue
+		}
+		if equalValidators(p.Validators[i], other.Validators[i]) {
+			p.Validators[i] = other.Validators[i]
+			shared++
+		}
+	}
+	return shared
+}
+
+func equalValidators(a *phase0.Validator, b *phase0.Validator) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.PublicKey == b.PublicKey &&
+		a.EffectiveBalance == b.EffectiveBalance &&
+		a.Slashed == b.Slashed &&
+		a.ActivationEligibilityEpoch == b.ActivationEligibilityEpoch &&
+		a.ActivationEpoch == b.ActivationEpoch &&
+		a.ExitEpoch == b.ExitEpoch &&
+		a.WithdrawableEpoch == b.WithdrawableEpoch &&
+		bytes.Equal(a.WithdrawalCredentials, b.WithdrawalCredentials)
+}
@@ -0,0 +1,89 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func sharedValidator(pubkey byte, effectiveBalance phase0.Gwei) *phase0.Validator {
+	return &phase0.Validator{
+		PublicKey:             phase0.BLSPubKey{pubkey},
+		WithdrawalCredentials: []byte{0x01, pubkey},
+		EffectiveBalance:      effectiveBalance,
+		ExitEpoch:             phase0.Epoch(1<<64 - 1),
+		WithdrawableEpoch:     phase0.Epoch(1<<64 - 1),
+	}
+}
+
+func TestShareValidatorRegistry(t *testing.T) {
+	prev := &spec.AgnosticState{Epoch: 10, Validators: []*phase0.Validator{
+		sharedValidator(0, 32_000_000_000),
+		sharedValidator(1, 32_000_000_000),
+		sharedValidator(2, 32_000_000_000),
+	}}
+	// same entries as copies, a changed effective balance and a new deposit
+	next := &spec.AgnosticState{Epoch: 11, Validators: []*phase0.Validator{
+		sharedValidator(0, 32_000_000_000),
+		sharedValidator(1, 31_000_000_000),
+		sharedValidator(2, 32_000_000_000),
+		sharedValidator(3, 32_000_000_000),
+	}}
+	changed := next.Validators[1]
+	deposit := next.Validators[3]
+
+	if shared := next.ShareValidatorRegistry(prev); shared != 2 {
+		t.Errorf("ShareValidatorRegistry shared %d entries, expected 2", shared)
+	}
+	if next.Validators[0] != prev.Validators[0] || next.Validators[2] != prev.Validators[2] {
+		t.Errorf("ShareValidatorRegistry did not point the unchanged entries to the previous state")
+	}
+	if next.Validators[1] != changed || next.Validators[1].EffectiveBalance != 31_000_000_000 {
+		t.Errorf("ShareValidatorRegistry replaced a changed entry")
+	}
+	if next.Validators[3] != deposit {
+		t.Errorf("ShareValidatorRegistry replaced an entry the previous state does not have")
+	}
+
+	// sharing again counts the entries already shared and changes nothing
+	if shared := next.ShareValidatorRegistry(prev); shared != 2 {
+		t.Errorf("ShareValidatorRegistry shared %d entries again, expected 2", shared)
+	}
+
+	if shared := next.ShareValidatorRegistry(nil); shared != 0 {
+		t.Errorf("ShareValidatorRegistry shared %d entries without a state, expected 0", shared)
+	}
+	if shared := next.ShareValidatorRegistry(&spec.AgnosticState{}); shared != 0 {
+		t.Errorf("ShareValidatorRegistry shared %d entries without validators, expected 0", shared)
+	}
+}
+
+func TestShareValidatorRegistryConsecutiveStates(t *testing.T) {
+	first := &spec.AgnosticState{Epoch: 10, Validators: []*phase0.Validator{
+		sharedValidator(0, 32_000_000_000),
+		sharedValidator(1, 32_000_000_000),
+	}}
+	second := &spec.AgnosticState{Epoch: 11, Validators: []*phase0.Validator{
+		sharedValidator(0, 32_000_000_000),
+		sharedValidator(1, 31_000_000_000),
+	}}
+	third := &spec.AgnosticState{Epoch: 12, Validators: []*phase0.Validator{
+		sharedValidator(0, 32_000_000_000),
+		sharedValidator(1, 31_000_000_000),
+	}}
+	second.ShareValidatorRegistry(first)
+	third.ShareValidatorRegistry(second)
+
+	// an entry unchanged since the first state is shared by the three of them
+	if third.Validators[0] != first.Validators[0] || second.Validators[0] != first.Validators[0] {
+		t.Errorf("the unchanged entry is not shared across the consecutive states")
+	}
+	// a refreshed entry is shared from the state it changed in
+	if third.Validators[1] != second.Validators[1] || third.Validators[1] == first.Validators[1] {
+		t.Errorf("the refreshed entry is not shared from the state it changed in")
+	}
+	if first.Validators[1].EffectiveBalance != 32_000_000_000 {
+		t.Errorf("sharing the registry modified the entries of the previous state")
+	}
+}
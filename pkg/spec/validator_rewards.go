@@ -24,6 +24,9 @@ type ValidatorRewards struct {
 	MissingHead          bool
 	Status               ValidatorStatus
 	InclusionDelay       int
+	RewardPercentile     float32 // percentile of the reward among active validators with the same effective balance
+	PlannedDowntime      bool    // the attestation was missed inside a registered downtime window
+	DetailedStatus       ValidatorDetailedStatus
 }
 
 func (f ValidatorRewards) Type() ModelType {
@@ -55,6 +58,9 @@ func (f ValidatorRewards) ToArray() []interface{} {
 		f.MissingHead,
 		f.Status,
 		f.InclusionDelay,
+		f.RewardPercentile,
+		f.PlannedDowntime,
+		f.DetailedStatus,
 	}
 	return rows
 }
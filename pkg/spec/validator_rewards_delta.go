@@ -0,0 +1,212 @@
+package spec
+
+import (
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ValidatorRewardsDelta is the delta-encoded version of ValidatorRewards. The slow-moving
+// amounts are stored as the difference with the keyframe of the validator, while keyframe rows
+// contain the full amounts. The rest of the fields are stored as they are
+type ValidatorRewardsDelta struct {
+	Rewards                  ValidatorRewards
+	KeyframeEpoch            phase0.Epoch // epoch of the keyframe the deltas refer to
+	BalanceDelta             int64
+	MaxRewardDelta           int64
+	AttestationRewardDelta   int64
+	SyncCommitteeRewardDelta int64
+	BaseRewardDelta          int64
+}
+
+func (f ValidatorRewardsDelta) Keyframe() bool {
+	return f.KeyframeEpoch == f.Rewards.Epoch
+}
+
+func (f ValidatorRewardsDelta) Type() ModelType {
+	return ValidatorRewardsDeltaModel
+}
+
+func (f ValidatorRewardsDelta) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Rewards.ValidatorIndex,
+		f.Rewards.Epoch,
+		f.KeyframeEpoch,
+		f.Keyframe(),
+		f.BalanceDelta,
+		f.Rewards.Reward,
+		f.MaxRewardDelta,
+		f.AttestationRewardDelta,
+		f.SyncCommitteeRewardDelta,
+		f.BaseRewardDelta,
+		f.Rewards.AttSlot,
+		f.Rewards.AttestationIncluded,
+		f.Rewards.InSyncCommittee,
+		f.Rewards.MissingSource,
+		f.Rewards.MissingTarget,
+		f.Rewards.MissingHead,
+		f.Rewards.Status,
+		f.Rewards.ProposerApiReward,
+		f.Rewards.ProposerManualReward,
+		f.Rewards.InclusionDelay,
+		f.Rewards.RewardPercentile,
+	}
+	return rows
+}
+
+// ValidatorRewardsKeyframe holds the amounts of a validator at a keyframe epoch, the deltas of the following
+// epochs refer to it. Keyframes are written once and never rewritten, so rewriting an epoch (i.e. after a reorg)
+// does not change the amounts of the rows that refer to it
+type ValidatorRewardsKeyframe struct {
+	ValidatorIndex      phase0.ValidatorIndex
+	Epoch               phase0.Epoch
+	Balance             phase0.Gwei
+	MaxReward           phase0.Gwei
+	AttestationReward   phase0.Gwei
+	SyncCommitteeReward phase0.Gwei
+	BaseReward          phase0.Gwei
+}
+
+func (f ValidatorRewardsKeyframe) Type() ModelType {
+	return ValidatorRewardsKeyframeModel
+}
+
+// RewardsKeyframeLoader returns the keyframe of the latest epoch between from and to that was persisted, if any
+type RewardsKeyframeLoader func(from phase0.Epoch, to phase0.Epoch) ([]ValidatorRewardsKeyframe, error)
+
+// RewardsDeltaEncoder keeps the last keyframes to delta-encode the validator rewards.
+// A new keyframe is written every keyframeEpochs epochs, or when there is no previous keyframe to refer to
+type RewardsDeltaEncoder struct {
+	sync.Mutex
+	keyframeEpochs phase0.Epoch
+	keyframes      map[phase0.Epoch]map[phase0.ValidatorIndex]ValidatorRewardsKeyframe
+	load           RewardsKeyframeLoader // nil if the persisted keyframes cannot be read
+}
+
+func NewRewardsDeltaEncoder(keyframeEpochs uint64, load RewardsKeyframeLoader) *RewardsDeltaEncoder {
+	if keyframeEpochs == 0 {
+		keyframeEpochs = 1 // every epoch is a keyframe
+	}
+	return &RewardsDeltaEncoder{
+		keyframeEpochs: phase0.Epoch(keyframeEpochs),
+		keyframes:      make(map[phase0.Epoch]map[phase0.ValidatorIndex]ValidatorRewardsKeyframe),
+		load:           load,
+	}
+}
+
+// Encode delta-encodes the rewards of the given epoch. Epochs may be encoded in any order, each one refers
+// to the latest keyframe that is not older than keyframeEpochs, either in memory or persisted (i.e. after a restart).
+// The new keyframe is returned when there is none to refer to, and must be persisted with the deltas
+func (e *RewardsDeltaEncoder) Encode(epoch phase0.Epoch, rewards []ValidatorRewards) ([]ValidatorRewardsDelta, []ValidatorRewardsKeyframe, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	keyframeEpoch, keyframe, found := e.latestKeyframe(epoch)
+	if !found && e.load != nil {
+		from := phase0.Epoch(0)
+		if epoch >= e.keyframeEpochs {
+			from = epoch - e.keyframeEpochs + 1
+		}
+		persisted, err := e.load(from, epoch)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(persisted) > 0 {
+			keyframeEpoch = persisted[0].Epoch
+			keyframe = make(map[phase0.ValidatorIndex]ValidatorRewardsKeyframe, len(persisted))
+			for _, values := range persisted {
+				keyframe[values.ValidatorIndex] = values
+			}
+			e.keyframes[keyframeEpoch] = keyframe
+			e.cleanUp(keyframeEpoch)
+			found = true
+		}
+	}
+
+	var newKeyframe []ValidatorRewardsKeyframe
+	if !found {
+		keyframeEpoch = epoch
+		keyframe = make(map[phase0.ValidatorIndex]ValidatorRewardsKeyframe, len(rewards))
+		newKeyframe = make([]ValidatorRewardsKeyframe, 0, len(rewards))
+		for _, reward := range rewards {
+			values := newRewardsKeyframe(reward)
+			keyframe[reward.ValidatorIndex] = values
+			newKeyframe = append(newKeyframe, values)
+		}
+		e.keyframes[epoch] = keyframe
+		e.cleanUp(epoch)
+	}
+
+	deltas := make([]ValidatorRewardsDelta, 0, len(rewards))
+	for _, reward := range rewards {
+		values, ok := keyframe[reward.ValidatorIndex]
+		if !ok || keyframeEpoch == epoch { // keyframe row, or new validator since the keyframe (a keyframe by itself)
+			deltas = append(deltas, newKeyframeDelta(reward))
+			continue
+		}
+		deltas = append(deltas, ValidatorRewardsDelta{
+			Rewards:                  reward,
+			KeyframeEpoch:            keyframeEpoch,
+			BalanceDelta:             int64(reward.ValidatorBalance) - int64(values.Balance),
+			MaxRewardDelta:           int64(reward.MaxReward) - int64(values.MaxReward),
+			AttestationRewardDelta:   int64(reward.AttestationReward) - int64(values.AttestationReward),
+			SyncCommitteeRewardDelta: int64(reward.SyncCommitteeReward) - int64(values.SyncCommitteeReward),
+			BaseRewardDelta:          int64(reward.BaseReward) - int64(values.BaseReward),
+		})
+	}
+	return deltas, newKeyframe, nil
+}
+
+// latestKeyframe returns the keyframe in memory the epoch refers to: its own one if the epoch was a keyframe
+// already (i.e. rewritten after a reorg), or the latest older one inside the keyframe window
+func (e *RewardsDeltaEncoder) latestKeyframe(epoch phase0.Epoch) (phase0.Epoch, map[phase0.ValidatorIndex]ValidatorRewardsKeyframe, bool) {
+	if keyframe, found := e.keyframes[epoch]; found {
+		return epoch, keyframe, true
+	}
+	var (
+		keyframeEpoch phase0.Epoch
+		keyframe      map[phase0.ValidatorIndex]ValidatorRewardsKeyframe
+		found         bool
+	)
+	for kEpoch, values := range e.keyframes {
+		if kEpoch < epoch && epoch-kEpoch < e.keyframeEpochs && (!found || kEpoch > keyframeEpoch) {
+			keyframeEpoch = kEpoch
+			keyframe = values
+			found = true
+		}
+	}
+	return keyframeEpoch, keyframe, found
+}
+
+// only the two most recent keyframes are kept, older epochs load theirs from the database
+func (e *RewardsDeltaEncoder) cleanUp(newest phase0.Epoch) {
+	for kEpoch := range e.keyframes {
+		if kEpoch+2*e.keyframeEpochs <= newest {
+			delete(e.keyframes, kEpoch)
+		}
+	}
+}
+
+func newRewardsKeyframe(reward ValidatorRewards) ValidatorRewardsKeyframe {
+	return ValidatorRewardsKeyframe{
+		ValidatorIndex:      reward.ValidatorIndex,
+		Epoch:               reward.Epoch,
+		Balance:             reward.ValidatorBalance,
+		MaxReward:           reward.MaxReward,
+		AttestationReward:   reward.AttestationReward,
+		SyncCommitteeReward: reward.SyncCommitteeReward,
+		BaseReward:          reward.BaseReward,
+	}
+}
+
+func newKeyframeDelta(reward ValidatorRewards) ValidatorRewardsDelta {
+	return ValidatorRewardsDelta{
+		Rewards:                  reward,
+		KeyframeEpoch:            reward.Epoch,
+		BalanceDelta:             int64(reward.ValidatorBalance),
+		MaxRewardDelta:           int64(reward.MaxReward),
+		AttestationRewardDelta:   int64(reward.AttestationReward),
+		SyncCommitteeRewardDelta: int64(reward.SyncCommitteeReward),
+		BaseRewardDelta:          int64(reward.BaseReward),
+	}
+}
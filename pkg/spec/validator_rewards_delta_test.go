@@ -0,0 +1,140 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestRewardsDeltaEncoder(t *testing.T) {
+	rewards := func(epoch phase0.Epoch, balance phase0.Gwei) []spec.ValidatorRewards {
+		return []spec.ValidatorRewards{{ValidatorIndex: 1, Epoch: epoch, ValidatorBalance: balance, MaxReward: 100}}
+	}
+	encoder := spec.NewRewardsDeltaEncoder(10, nil)
+
+	tests := []struct {
+		name          string
+		epoch         phase0.Epoch
+		balance       phase0.Gwei
+		keyframeEpoch phase0.Epoch
+		balanceDelta  int64
+	}{
+		{
+			name:          "First epoch is a keyframe",
+			epoch:         100,
+			balance:       32000000000,
+			keyframeEpoch: 100,
+			balanceDelta:  32000000000,
+		},
+		{
+			name:          "Next epoch refers to the keyframe",
+			epoch:         101,
+			balance:       32000010000,
+			keyframeEpoch: 100,
+			balanceDelta:  10000,
+		},
+		{
+			name:          "Previous epoch is a new keyframe",
+			epoch:         99,
+			balance:       31999990000,
+			keyframeEpoch: 99,
+			balanceDelta:  31999990000,
+		},
+		{
+			name:          "Epoch after the keyframe window is a new keyframe",
+			epoch:         110,
+			balance:       32000100000,
+			keyframeEpoch: 110,
+			balanceDelta:  32000100000,
+		},
+		{
+			name:          "Negative delta",
+			epoch:         111,
+			balance:       32000090000,
+			keyframeEpoch: 110,
+			balanceDelta:  -10000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deltas, keyframe, err := encoder.Encode(test.epoch, rewards(test.epoch, test.balance))
+			if err != nil {
+				t.Fatalf("Encode returned error: %s", err)
+			}
+			if deltas[0].Keyframe() != (len(keyframe) == 1) {
+				t.Errorf("Encode returned %d keyframe rows for a keyframe row: %t", len(keyframe), deltas[0].Keyframe())
+			}
+			if len(deltas) != 1 {
+				t.Fatalf("Encode returned %d rows, expected %d", len(deltas), 1)
+			}
+			if deltas[0].KeyframeEpoch != test.keyframeEpoch {
+				t.Errorf("Encode returned keyframe epoch %d, expected %d", deltas[0].KeyframeEpoch, test.keyframeEpoch)
+			}
+			if deltas[0].BalanceDelta != test.balanceDelta {
+				t.Errorf("Encode returned balance delta %d, expected %d", deltas[0].BalanceDelta, test.balanceDelta)
+			}
+			if deltas[0].Keyframe() && deltas[0].MaxRewardDelta != 100 || !deltas[0].Keyframe() && deltas[0].MaxRewardDelta != 0 {
+				t.Errorf("Encode returned max reward delta %d", deltas[0].MaxRewardDelta)
+			}
+		})
+	}
+}
+
+func TestRewardsDeltaEncoderRewrittenKeyframe(t *testing.T) {
+	encoder := spec.NewRewardsDeltaEncoder(10, nil)
+	reward := func(epoch phase0.Epoch, balance phase0.Gwei) []spec.ValidatorRewards {
+		return []spec.ValidatorRewards{{ValidatorIndex: 1, Epoch: epoch, ValidatorBalance: balance}}
+	}
+
+	if _, keyframe, _ := encoder.Encode(100, reward(100, 32000000000)); len(keyframe) != 1 {
+		t.Fatalf("Encode returned %d keyframe rows, expected %d", len(keyframe), 1)
+	}
+	// the keyframe epoch is rewritten after a reorg: its row changes, but not the keyframe
+	deltas, keyframe, _ := encoder.Encode(100, reward(100, 32000005000))
+	if len(keyframe) != 0 {
+		t.Errorf("rewritten epoch returned %d keyframe rows, expected none", len(keyframe))
+	}
+	if !deltas[0].Keyframe() || deltas[0].BalanceDelta != 32000005000 {
+		t.Errorf("rewritten epoch returned keyframe %t and balance %d", deltas[0].Keyframe(), deltas[0].BalanceDelta)
+	}
+	deltas, _, _ = encoder.Encode(101, reward(101, 32000010000))
+	if deltas[0].KeyframeEpoch != 100 || deltas[0].BalanceDelta != 10000 {
+		t.Errorf("next epoch refers to keyframe %d with balance delta %d, expected %d and %d", deltas[0].KeyframeEpoch, deltas[0].BalanceDelta, 100, 10000)
+	}
+}
+
+func TestRewardsDeltaEncoderLoad(t *testing.T) {
+	var loaded [][2]phase0.Epoch
+	load := func(from phase0.Epoch, to phase0.Epoch) ([]spec.ValidatorRewardsKeyframe, error) {
+		loaded = append(loaded, [2]phase0.Epoch{from, to})
+		if from > 95 || to < 95 {
+			return nil, nil
+		}
+		return []spec.ValidatorRewardsKeyframe{{ValidatorIndex: 1, Epoch: 95, Balance: 32000000000}}, nil
+	}
+	encoder := spec.NewRewardsDeltaEncoder(10, load)
+	reward := func(epoch phase0.Epoch, balance phase0.Gwei) []spec.ValidatorRewards {
+		return []spec.ValidatorRewards{{ValidatorIndex: 1, Epoch: epoch, ValidatorBalance: balance}}
+	}
+
+	// after a restart, the first epoch refers to the persisted keyframe
+	deltas, keyframe, err := encoder.Encode(100, reward(100, 32000010000))
+	if err != nil {
+		t.Fatalf("Encode returned error: %s", err)
+	}
+	if len(keyframe) != 0 || deltas[0].KeyframeEpoch != 95 || deltas[0].BalanceDelta != 10000 {
+		t.Errorf("Encode returned %d keyframe rows, keyframe epoch %d and balance delta %d", len(keyframe), deltas[0].KeyframeEpoch, deltas[0].BalanceDelta)
+	}
+	// the keyframe is kept in memory
+	encoder.Encode(101, reward(101, 32000020000))
+	if len(loaded) != 1 || loaded[0] != [2]phase0.Epoch{91, 100} {
+		t.Errorf("keyframes loaded %v, expected %v", loaded, [][2]phase0.Epoch{{91, 100}})
+	}
+	// no keyframe persisted in the window, a new one is written
+	deltas, keyframe, _ = encoder.Encode(105, reward(105, 32000030000))
+	if len(keyframe) != 1 || !deltas[0].Keyframe() {
+		t.Errorf("Encode returned %d keyframe rows and keyframe %t", len(keyframe), deltas[0].Keyframe())
+	}
+}
@@ -0,0 +1,68 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// ValidatorStatusAnomaly is a mismatch between the number of validators in a status computed
+// from the state and the one reported by the beacon node for the same state
+type ValidatorStatusAnomaly struct {
+	Epoch         phase0.Epoch
+	Slot          phase0.Slot
+	Status        ValidatorStatus
+	ComputedCount uint64
+	NodeCount     uint64
+}
+
+func (f ValidatorStatusAnomaly) Type() ModelType {
+	return ValidatorStatusAnomalyModel
+}
+
+func (f ValidatorStatusAnomaly) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Epoch,
+		f.Slot,
+		f.Status,
+		f.ComputedCount,
+		f.NodeCount,
+	}
+	return rows
+}
+
+// StatusFromNodeStatus translates the status given by the beacon API
+// (https://hackmd.io/ofFJ5gOmQpu1jjHilHbdQQ) into the statuses computed by the analyzer.
+// Slashed validators count as slashed until they are withdrawn, as the analyzer only checks the flag
+func StatusFromNodeStatus(status string, slashed bool) ValidatorStatus {
+	if slashed {
+		return SLASHED_STATUS
+	}
+	switch status {
+	case "active_ongoing", "active_exiting":
+		return ACTIVE_STATUS
+	case "active_slashed", "exited_slashed":
+		return SLASHED_STATUS
+	case "exited_unslashed", "withdrawal_possible", "withdrawal_done":
+		return EXIT_STATUS
+	default: // pending_initialized, pending_queued
+		return QUEUE_STATUS
+	}
+}
+
+// CompareValidatorStatuses returns the statuses whose computed count (indexed by status)
+// does not match the count reported by the beacon node
+func CompareValidatorStatuses(epoch phase0.Epoch, slot phase0.Slot, computed []uint64, node []uint64) []ValidatorStatusAnomaly {
+	anomalies := make([]ValidatorStatusAnomaly, 0)
+	for status := ValidatorStatus(0); status < NUMBER_OF_STATUS; status++ {
+		if computed[status] == node[status] {
+			continue
+		}
+		anomalies = append(anomalies, ValidatorStatusAnomaly{
+			Epoch:         epoch,
+			Slot:          slot,
+			Status:        status,
+			ComputedCount: computed[status],
+			NodeCount:     node[status],
+		})
+	}
+	return anomalies
+}
@@ -0,0 +1,34 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestStatusFromNodeStatus(t *testing.T) {
+	tests := []struct {
+		status  string
+		slashed bool
+		result  spec.ValidatorStatus
+	}{
+		{status: "pending_initialized", result: spec.QUEUE_STATUS},
+		{status: "pending_queued", result: spec.QUEUE_STATUS},
+		{status: "active_ongoing", result: spec.ACTIVE_STATUS},
+		{status: "active_exiting", result: spec.ACTIVE_STATUS},
+		{status: "active_slashed", slashed: true, result: spec.SLASHED_STATUS},
+		{status: "exited_unslashed", result: spec.EXIT_STATUS},
+		{status: "exited_slashed", slashed: true, result: spec.SLASHED_STATUS},
+		{status: "withdrawal_possible", result: spec.EXIT_STATUS},
+		{status: "withdrawal_done", slashed: true, result: spec.SLASHED_STATUS},
+	}
+
+	for _, test := range tests {
+		t.Run(test.status, func(t *testing.T) {
+			result := spec.StatusFromNodeStatus(test.status, test.slashed)
+			if result != test.result {
+				t.Errorf("StatusFromNodeStatus returned %d, expected %d", result, test.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package spec
+
+import (
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// VoluntaryExitEvent is a voluntary exit broadcast in the network, at the time the beacon node notified it
+type VoluntaryExitEvent struct {
+	Timestamp      time.Time
+	ValidatorIndex phase0.ValidatorIndex
+	Epoch          phase0.Epoch // earliest epoch at which the exit can be processed
+}
+
+func (f VoluntaryExitEvent) Type() ModelType {
+	return VoluntaryExitEventModel
+}
+
+func (f VoluntaryExitEvent) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Timestamp.UnixMilli(),
+		f.ValidatorIndex,
+		f.Epoch,
+	}
+	return rows
+}
+
+// VoluntaryExit is a voluntary exit included in a block
+type VoluntaryExit struct {
+	Slot           phase0.Slot
+	ValidatorIndex phase0.ValidatorIndex
+	Epoch          phase0.Epoch
+}
+
+func (f VoluntaryExit) Type() ModelType {
+	return VoluntaryExitModel
+}
+
+func (f VoluntaryExit) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Slot,
+		f.ValidatorIndex,
+		f.Epoch,
+	}
+	return rows
+}
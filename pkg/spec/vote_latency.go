@@ -0,0 +1,57 @@
+package spec
+
+import (
+	"sort"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// AttestationEvent is an attestation received through the event stream, before any block includes it.
+// Aggregates identify the voters by committee and aggregation bits, single attestations (Electra) by their index
+type AttestationEvent struct {
+	Slot             phase0.Slot
+	CommitteeIndex   phase0.CommitteeIndex
+	AggregationBits  bitfield.Bitlist
+	AttesterIndex    phase0.ValidatorIndex
+	Single           bool
+	ArrivalTimestamp int64 // unix ms
+}
+
+// VoteLatency summarizes the time between the start of the attestation slot
+// and the first time the vote of each validator was seen
+type VoteLatency struct {
+	NumVotes int
+	Mean     float64 // ms
+	P95      int64   // ms
+}
+
+// NewVoteLatency computes the mean and 95th percentile (nearest rank) of the given latencies (ms)
+func NewVoteLatency(latencies []int64) VoteLatency {
+	if len(latencies) == 0 {
+		return VoteLatency{}
+	}
+
+	sorted := make([]int64, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	sum := float64(0)
+	for _, latency := range sorted {
+		sum += float64(latency)
+	}
+
+	return VoteLatency{
+		NumVotes: len(sorted),
+		Mean:     sum / float64(len(sorted)),
+		P95:      Percentile(sorted, 95),
+	}
+}
+
+// SlotStart returns the start of the slot in unix ms
+func SlotStart(genesis time.Time, slot phase0.Slot) int64 {
+	return genesis.UnixMilli() + int64(slot)*SlotSeconds*1000
+}
@@ -0,0 +1,52 @@
+package spec_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func TestNewVoteLatency(t *testing.T) {
+	tests := []struct {
+		name      string
+		latencies []int64
+		numVotes  int
+		mean      float64
+		p95       int64
+	}{
+		{
+			name:      "Empty",
+			latencies: []int64{},
+		},
+		{
+			name:      "Single vote",
+			latencies: []int64{4000},
+			numVotes:  1,
+			mean:      4000,
+			p95:       4000,
+		},
+		{
+			name:      "Unsorted with late votes",
+			latencies: []int64{4100, 3900, 12000, 4000},
+			numVotes:  4,
+			mean:      6000,
+			p95:       12000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			latency := spec.NewVoteLatency(test.latencies)
+			if latency.NumVotes != test.numVotes {
+				t.Errorf("NumVotes returned %d, expected %d", latency.NumVotes, test.numVotes)
+			}
+			if math.Abs(latency.Mean-test.mean) > 1e-9 {
+				t.Errorf("Mean returned %f, expected %f", latency.Mean, test.mean)
+			}
+			if latency.P95 != test.p95 {
+				t.Errorf("P95 returned %d, expected %d", latency.P95, test.p95)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+const notIncluded = uint8(0xff)
+
+// VoteTally is the number of validators whose vote for an epoch was included in the blocks up to a slot,
+// one point of the inclusion curve of the epoch
+type VoteTally struct {
+	Slot          phase0.Slot
+	Epoch         phase0.Epoch // epoch of the votes
+	IncludedVotes uint64       // validators with a vote for the epoch included up to the slot
+	NewVotes      uint64       // validators whose vote was first included in the block of the slot
+	ExpectedVotes uint64       // validators in the committees of the epoch
+}
+
+func (f VoteTally) Type() ModelType {
+	return VoteTallyModel
+}
+
+func (f VoteTally) ToArray() []interface{} {
+	rows := []interface{}{
+		f.Slot,
+		f.Epoch,
+		f.IncludedVotes,
+		f.NewVotes,
+		f.ExpectedVotes,
+	}
+	return rows
+}
+
+// EpochVoteTracker keeps the first slot at which the vote of each validator of an epoch was included.
+// Votes can be included until the end of the next epoch, so slots are kept as an offset from the start of the epoch
+type EpochVoteTracker struct {
+	Epoch          phase0.Epoch
+	duties         EpochDuties
+	expected       uint64
+	firstInclusion []uint8 // per validator index, notIncluded if not included yet
+}
+
+func NewEpochVoteTracker(epoch phase0.Epoch, duties EpochDuties) *EpochVoteTracker {
+	maxIndex := phase0.ValidatorIndex(0)
+	expected := uint64(0)
+	for _, committee := range duties.BeaconCommittees {
+		for _, valIdx := range committee.Validators {
+			maxIndex = max(maxIndex, valIdx)
+		}
+		expected += uint64(len(committee.Validators))
+	}
+
+	firstInclusion := make([]uint8, maxIndex+1)
+	for i := range firstInclusion {
+		firstInclusion[i] = notIncluded
+	}
+	return &EpochVoteTracker{
+		Epoch:          epoch,
+		duties:         duties,
+		expected:       expected,
+		firstInclusion: firstInclusion,
+	}
+}
+
+// Duties returns the committees of the epoch
+func (t *EpochVoteTracker) Duties() EpochDuties {
+	return t.duties
+}
+
+// AddBlock marks the votes for the epoch included in the block and returns the tally at the slot of the block.
+// Blocks can be added in any order, the tally counts the votes first included up to the slot
+func (t *EpochVoteTracker) AddBlock(block *AgnosticBlock) VoteTally {
+	epochStart := phase0.Slot(t.Epoch) * SlotsPerEpoch
+	tally := VoteTally{
+		Slot:          block.Slot,
+		Epoch:         t.Epoch,
+		ExpectedVotes: t.expected,
+	}
+	if block.Slot < epochStart || block.Slot >= epochStart+2*SlotsPerEpoch {
+		return tally
+	}
+	offset := uint8(block.Slot - epochStart)
+
+	for _, attestation := range block.Attestations {
+		if phase0.Epoch(attestation.Data.Slot/SlotsPerEpoch) != t.Epoch {
+			continue
+		}
+		committee := t.duties.GetValList(attestation.Data.Slot, attestation.Data.Index)
+		for i, valIdx := range committee {
+			if !attestation.AggregationBits.BitAt(uint64(i)) || int(valIdx) >= len(t.firstInclusion) {
+				continue
+			}
+			if offset < t.firstInclusion[valIdx] {
+				t.firstInclusion[valIdx] = offset
+			}
+		}
+	}
+
+	for _, first := range t.firstInclusion {
+		if first == offset {
+			tally.NewVotes++
+		}
+		if first <= offset {
+			tally.IncludedVotes++
+		}
+	}
+	return tally
+}
@@ -0,0 +1,58 @@
+package spec_test
+
+import (
+	"testing"
+
+	api "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func tallyAttestation(slot phase0.Slot, bits ...bool) *phase0.Attestation {
+	aggregationBits := bitfield.NewBitlist(uint64(len(bits)))
+	for i, bit := range bits {
+		aggregationBits.SetBitAt(uint64(i), bit)
+	}
+	return &phase0.Attestation{
+		AggregationBits: aggregationBits,
+		Data:            &phase0.AttestationData{Slot: slot, Index: 0},
+	}
+}
+
+func TestEpochVoteTracker(t *testing.T) {
+	duties := spec.EpochDuties{
+		BeaconCommittees: []*api.BeaconCommittee{
+			{Slot: 320, Index: 0, Validators: []phase0.ValidatorIndex{1, 2, 3}},
+			{Slot: 321, Index: 0, Validators: []phase0.ValidatorIndex{4, 5}},
+		},
+	}
+	blocks := []*spec.AgnosticBlock{
+		{Slot: 321, Attestations: []*phase0.Attestation{tallyAttestation(320, true, false, true)}},
+		{Slot: 322, Attestations: []*phase0.Attestation{tallyAttestation(320, true, true, false), tallyAttestation(321, false, true)}},
+		{Slot: 353, Attestations: []*phase0.Attestation{tallyAttestation(321, true, true), tallyAttestation(352, true)}},
+	}
+	expected := []struct {
+		included uint64
+		new      uint64
+	}{
+		{2, 2},
+		{4, 2},
+		{5, 1},
+	}
+
+	tracker := spec.NewEpochVoteTracker(10, duties)
+	for i, block := range blocks {
+		tally := tracker.AddBlock(block)
+		if tally.IncludedVotes != expected[i].included || tally.NewVotes != expected[i].new || tally.ExpectedVotes != 5 {
+			t.Errorf("AddBlock(%d) returned %d included, %d new and %d expected votes, expected %d, %d and 5",
+				block.Slot, tally.IncludedVotes, tally.NewVotes, tally.ExpectedVotes, expected[i].included, expected[i].new)
+		}
+	}
+
+	// a block added late only moves the first inclusion of its votes back
+	tally := tracker.AddBlock(&spec.AgnosticBlock{Slot: 320, Attestations: []*phase0.Attestation{tallyAttestation(320, false, true, false)}})
+	if tally.IncludedVotes != 1 || tally.NewVotes != 1 {
+		t.Errorf("AddBlock(320) returned %d included and %d new votes, expected 1 and 1", tally.IncludedVotes, tally.NewVotes)
+	}
+}
@@ -0,0 +1,137 @@
+package testutil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// BeaconMock is a beacon node API served from fixture files: the response of a request to /eth/v1/x/y is
+// <dir>/eth/v1/x/y.json, or <dir>/eth/v1/x/y.ssz when the client accepts SSZ, with the consensus version
+// of the SSZ response in <dir>/eth/v1/x/y.version. Query strings and methods do not change the fixture
+type BeaconMock struct {
+	*httptest.Server
+	dir      string
+	upstream string // node the missing fixtures are recorded from, none if empty
+
+	mu       sync.Mutex
+	handlers map[string]http.HandlerFunc
+	served   map[string]int
+	missing  []string
+}
+
+type BeaconMockOption func(*BeaconMock)
+
+// WithUpstream records the fixtures that are missing from the given beacon node, so a fixture range
+// can be captured by running the analyzer once against a real node
+func WithUpstream(url string) BeaconMockOption {
+	return func(b *BeaconMock) {
+		b.upstream = strings.TrimSuffix(url, "/")
+	}
+}
+
+// NewBeaconMock serves the fixtures in dir until the end of the test
+func NewBeaconMock(t testing.TB, dir string, options ...BeaconMockOption) *BeaconMock {
+	t.Helper()
+	b := &BeaconMock{
+		dir:      dir,
+		handlers: make(map[string]http.HandlerFunc),
+		served:   make(map[string]int),
+	}
+	for _, option := range options {
+		option(b)
+	}
+	b.Server = httptest.NewServer(http.HandlerFunc(b.serve))
+	t.Cleanup(b.Close)
+	return b
+}
+
+// Handle serves the given path with a handler instead of a fixture (i.e. responses that depend on the query)
+func (b *BeaconMock) Handle(path string, handler http.HandlerFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[path] = handler
+}
+
+// Served returns how many times the given path was served
+func (b *BeaconMock) Served(path string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.served[path]
+}
+
+// Missing returns the paths requested without a fixture, in order
+func (b *BeaconMock) Missing() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string{}, b.missing...)
+}
+
+func (b *BeaconMock) serve(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	handler, ok := b.handlers[r.URL.Path]
+	b.served[r.URL.Path]++
+	b.mu.Unlock()
+	if ok {
+		handler(w, r)
+		return
+	}
+
+	base := filepath.Join(b.dir, filepath.FromSlash(strings.TrimPrefix(r.URL.Path, "/")))
+	if strings.Contains(r.Header.Get("Accept"), "application/octet-stream") {
+		if data, err := os.ReadFile(base + ".ssz"); err == nil {
+			if version, err := os.ReadFile(base + ".version"); err == nil {
+				w.Header().Set("Eth-Consensus-Version", strings.TrimSpace(string(version)))
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(data)
+			return
+		}
+	}
+	if data, err := os.ReadFile(base + ".json"); err == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return
+	}
+	if b.upstream != "" && b.record(w, r, base) {
+		return
+	}
+
+	b.mu.Lock()
+	b.missing = append(b.missing, r.URL.Path)
+	b.mu.Unlock()
+	http.Error(w, `{"code":404,"message":"no fixture for `+r.URL.Path+`"}`, http.StatusNotFound)
+}
+
+// record requests the missing fixture from the upstream node, as JSON, and stores it if it exists
+func (b *BeaconMock) record(w http.ResponseWriter, r *http.Request, base string) bool {
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, b.upstream+r.URL.RequestURI(), r.Body)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", r.Header.Get("Content-Type"))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return false
+	}
+	if err := os.MkdirAll(filepath.Dir(base), 0o755); err != nil {
+		return false
+	}
+	if err := os.WriteFile(base+".json", data, 0o644); err != nil {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+	return true
+}
@@ -0,0 +1,73 @@
+package testutil_test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/testutil"
+)
+
+func TestBeaconMock(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path string, data string) {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("eth/v1/beacon/genesis.json", `{"data":{}}`)
+	write("eth/v2/debug/beacon/states/63.json", `{"version":"phase0"}`)
+	write("eth/v2/debug/beacon/states/63.ssz", "ssz")
+	write("eth/v2/debug/beacon/states/63.version", "phase0\n")
+
+	mock := testutil.NewBeaconMock(t, dir)
+	get := func(path string, accept string) (int, string, http.Header) {
+		req, err := http.NewRequest(http.MethodGet, mock.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body), resp.Header
+	}
+
+	if status, body, _ := get("/eth/v1/beacon/genesis", ""); status != http.StatusOK || body != `{"data":{}}` {
+		t.Errorf("genesis returned %d %q", status, body)
+	}
+	if status, body, header := get("/eth/v2/debug/beacon/states/63", "application/octet-stream;q=1,application/json;q=0.9"); status != http.StatusOK || body != "ssz" || header.Get("Eth-Consensus-Version") != "phase0" {
+		t.Errorf("SSZ state returned %d %q, version %q", status, body, header.Get("Eth-Consensus-Version"))
+	}
+	if status, body, _ := get("/eth/v2/debug/beacon/states/63", "application/json"); status != http.StatusOK || body != `{"version":"phase0"}` {
+		t.Errorf("JSON state returned %d %q", status, body)
+	}
+	if status, _, _ := get("/eth/v1/beacon/headers/head?x=1", ""); status != http.StatusNotFound {
+		t.Errorf("missing fixture returned %d, expected %d", status, http.StatusNotFound)
+	}
+
+	mock.Handle("/eth/v1/node/syncing", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("handled"))
+	})
+	if _, body, _ := get("/eth/v1/node/syncing", ""); body != "handled" {
+		t.Errorf("handled path returned %q", body)
+	}
+
+	if served := mock.Served("/eth/v2/debug/beacon/states/63"); served != 2 {
+		t.Errorf("state served %d times, expected %d", served, 2)
+	}
+	if missing := mock.Missing(); len(missing) != 1 || missing[0] != "/eth/v1/beacon/headers/head" {
+		t.Errorf("missing fixtures are %v", missing)
+	}
+}
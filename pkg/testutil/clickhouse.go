@@ -0,0 +1,170 @@
+package testutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	ClickHouseImage = "clickhouse/clickhouse-server:24" // same as the docker-compose.yml
+
+	clickhouseUser     = "goteth"
+	clickhousePassword = "goteth"
+	clickhouseDB       = "goteth"
+	clickhouseStartup  = time.Minute
+)
+
+// ClickHouse is a clickhouse server running in a docker container, removed at the end of the test
+type ClickHouse struct {
+	container  string
+	nativePort string
+	httpPort   string
+}
+
+// StartClickHouse runs a clickhouse container with an empty database. The test is skipped if docker is not available
+func StartClickHouse(t testing.TB) *ClickHouse {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker is not available, skipping the integration test")
+	}
+	out, err := exec.Command("docker", "run", "-d", "--rm",
+		"-e", "CLICKHOUSE_USER="+clickhouseUser,
+		"-e", "CLICKHOUSE_PASSWORD="+clickhousePassword,
+		"-e", "CLICKHOUSE_DB="+clickhouseDB,
+		"-p", "127.0.0.1::9000",
+		"-p", "127.0.0.1::8123",
+		ClickHouseImage).Output()
+	if err != nil {
+		t.Fatalf("could not start the clickhouse container: %s", commandError(err))
+	}
+	c := &ClickHouse{container: strings.TrimSpace(string(out))}
+	t.Cleanup(func() {
+		exec.Command("docker", "rm", "-f", c.container).Run()
+	})
+
+	if c.nativePort, err = c.mappedPort("9000/tcp"); err != nil {
+		t.Fatalf("could not get the clickhouse native port: %s", err)
+	}
+	if c.httpPort, err = c.mappedPort("8123/tcp"); err != nil {
+		t.Fatalf("could not get the clickhouse http port: %s", err)
+	}
+	if err := c.waitReady(clickhouseStartup); err != nil {
+		t.Fatalf("clickhouse did not start: %s", err)
+	}
+	return c
+}
+
+// URL returns the --db-url of the database
+func (c *ClickHouse) URL() string {
+	return fmt.Sprintf("clickhouse://%s:%s@127.0.0.1:%s/%s?x-multi-statement=true",
+		clickhouseUser, clickhousePassword, c.nativePort, clickhouseDB)
+}
+
+// Query returns the rows of the query, with the columns by name. 64 bit integers are numbers, not strings
+func (c *ClickHouse) Query(t testing.TB, query string) []map[string]any {
+	t.Helper()
+	data, err := c.query(query + " FORMAT JSONEachRow")
+	if err != nil {
+		t.Fatalf("query %q failed: %s", query, err)
+	}
+	rows := make([]map[string]any, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		row := make(map[string]any)
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&row); err != nil {
+			t.Fatalf("could not decode the row of query %q: %s", query, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Exec runs a statement without result, i.e. to insert the rows a test reads
+func (c *ClickHouse) Exec(t testing.TB, statement string) {
+	t.Helper()
+	if _, err := c.query(statement); err != nil {
+		t.Fatalf("statement %q failed: %s", statement, err)
+	}
+}
+
+// Count returns the rows of the table, after the duplicates are merged
+func (c *ClickHouse) Count(t testing.TB, table string) uint64 {
+	t.Helper()
+	data, err := c.query(fmt.Sprintf("SELECT count() FROM %s FINAL FORMAT TabSeparated", table))
+	if err != nil {
+		t.Fatalf("could not count the rows of %s: %s", table, err)
+	}
+	count, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		t.Fatalf("could not parse the rows of %s: %s", table, err)
+	}
+	return count
+}
+
+func (c *ClickHouse) query(query string) ([]byte, error) {
+	params := url.Values{}
+	params.Set("database", clickhouseDB)
+	params.Set("output_format_json_quote_64bit_integers", "0")
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:%s/?%s", c.httpPort, params.Encode()), strings.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(clickhouseUser, clickhousePassword)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+func (c *ClickHouse) mappedPort(port string) (string, error) {
+	out, err := exec.Command("docker", "port", c.container, port).Output()
+	if err != nil {
+		return "", commandError(err)
+	}
+	// first line, 127.0.0.1:<port>
+	line := strings.SplitN(strings.TrimSpace(string(out)), "\n", 2)[0]
+	return line[strings.LastIndex(line, ":")+1:], nil
+}
+
+func (c *ClickHouse) waitReady(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		_, err := c.query("SELECT 1")
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func commandError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
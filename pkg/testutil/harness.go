@@ -0,0 +1,74 @@
+package testutil
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/analyzer"
+	"github.com/migalabs/goteth/pkg/config"
+)
+
+// the prometheus metrics and their endpoint are registered globally, so the analyzer only runs once per test binary
+var analyzerRan atomic.Bool
+
+// Harness runs the analyzer over a fixture range, served by a BeaconMock, into a clean ClickHouse database
+type Harness struct {
+	Beacon *BeaconMock
+	DB     *ClickHouse
+}
+
+// NewHarness starts the beacon mock over the fixtures in dir and the clickhouse container.
+// The test is skipped if docker is not available
+func NewHarness(t testing.TB, dir string, options ...BeaconMockOption) *Harness {
+	t.Helper()
+	return &Harness{
+		DB:     StartClickHouse(t),
+		Beacon: NewBeaconMock(t, dir, options...),
+	}
+}
+
+// Config returns the configuration of a historical run from initSlot to finalSlot against the harness,
+// with the defaults of the cli otherwise. The metrics or any other option can be changed before the run
+func (h *Harness) Config(initSlot phase0.Slot, finalSlot phase0.Slot) *config.AnalyzerConfig {
+	conf := config.NewAnalyzerConfig()
+	conf.BnEndpoint = h.Beacon.URL
+	conf.DBUrl = h.DB.URL()
+	conf.DownloadMode = "historical"
+	conf.InitSlot = initSlot
+	conf.FinalSlot = finalSlot
+	conf.PrometheusPort = 0 // the listener takes any free port, so the run does not clash with a local analyzer
+	return conf
+}
+
+// Run processes the range of the configuration and waits for the analyzer to finish, failing the test
+// if it cannot start or does not finish within the timeout. It returns the failure report of the run
+func (h *Harness) Run(t testing.TB, conf *config.AnalyzerConfig, timeout time.Duration) analyzer.FailureReport {
+	t.Helper()
+	if !analyzerRan.CompareAndSwap(false, true) {
+		t.Fatalf("the analyzer can only run once per test binary")
+	}
+
+	chainAnalyzer, err := analyzer.NewChainAnalyzer(context.Background(), *conf)
+	if err != nil {
+		t.Fatalf("could not create the analyzer: %s", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		chainAnalyzer.Run()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		chainAnalyzer.Close()
+		t.Fatalf("the analyzer did not finish in %s, requests without fixture: %v", timeout, h.Beacon.Missing())
+	}
+	if missing := h.Beacon.Missing(); len(missing) > 0 {
+		t.Logf("requests without fixture: %v", missing)
+	}
+	return chainAnalyzer.FailureReport()
+}
@@ -0,0 +1,54 @@
+package testutil_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/migalabs/goteth/pkg/testutil"
+)
+
+// TestHarness runs the analyzer in blocks-only mode over the fixtures of testdata/blocks,
+// written by testdata/gen_fixtures.go: a deneb chain up to slot 96 with the slots 70 and 81 missed
+func TestHarness(t *testing.T) {
+	harness := testutil.NewHarness(t, "testdata/blocks")
+	missed := []uint64{70, 81}
+	for _, slot := range missed {
+		harness.Beacon.Handle(fmt.Sprintf("/eth/v2/beacon/blocks/%d", slot), func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"code":404,"message":"block not found"}`, http.StatusNotFound)
+		})
+	}
+
+	conf := harness.Config(64, 95)
+	conf.Mode = "blocks-only"
+	conf.Metrics = "block"
+	report := harness.Run(t, conf, 2*time.Minute)
+	if len(report.FailedEpochs) > 0 || len(report.Failures) > 0 {
+		t.Fatalf("the run failed: %+v", report)
+	}
+	if missing := harness.Beacon.Missing(); len(missing) > 0 {
+		t.Fatalf("requests without fixture: %v", missing)
+	}
+
+	// the blocks-only mode starts two epochs before the init slot, and ends with the first slot of the next epoch
+	if count := harness.DB.Count(t, "t_block_metrics"); count != 97 {
+		t.Errorf("expected 97 slots, got %d", count)
+	}
+	rows := harness.DB.Query(t, "SELECT f_slot FROM t_block_metrics FINAL WHERE NOT f_proposed ORDER BY f_slot")
+	if fmt.Sprint(rows) != fmt.Sprintf("[map[f_slot:%d] map[f_slot:%d]]", missed[0], missed[1]) {
+		t.Errorf("expected the slots %v missed, got %v", missed, rows)
+	}
+
+	// every block withdraws 1000 + slot gwei
+	expected := uint64(0)
+	for slot := uint64(0); slot <= 96; slot++ {
+		if slot != missed[0] && slot != missed[1] {
+			expected += 1000 + slot
+		}
+	}
+	rows = harness.DB.Query(t, "SELECT count() AS f_count, sum(f_amount) AS f_amount FROM t_withdrawals FINAL")
+	if fmt.Sprint(rows) != fmt.Sprintf("[map[f_amount:%d f_count:95]]", expected) {
+		t.Errorf("expected 95 withdrawals of %d gwei, got %v", expected, rows)
+	}
+}
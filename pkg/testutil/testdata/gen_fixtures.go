@@ -0,0 +1,224 @@
+//go:build ignore
+
+// gen_fixtures writes the beacon node fixtures of the harness test: a deneb chain from genesis
+// with a block in every slot but the missed ones, which the test answers as not found. Run it from this directory:
+//
+//	go run gen_fixtures.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/holiman/uint256"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+const (
+	dir            = "blocks"
+	genesisTime    = 1700000000
+	lastSlot       = 96 // the harness test runs the epochs 0 to 2, and the first block of epoch 3
+	headSlot       = 160
+	finalizedEpoch = 3
+	proposers      = 16
+)
+
+var missed = map[phase0.Slot]bool{70: true, 81: true}
+
+func main() {
+	write("eth/v1/beacon/genesis", map[string]any{"data": map[string]string{
+		"genesis_time":            fmt.Sprint(genesisTime),
+		"genesis_validators_root": root(0xaa).String(),
+		"genesis_fork_version":    "0x00000000",
+	}})
+	write("eth/v1/config/spec", map[string]any{"data": specValues()})
+	write("eth/v1/node/version", map[string]any{"data": map[string]string{"version": "Lighthouse/v5.3.0-harness/x86_64-linux"}})
+	write("eth/v1/node/syncing", map[string]any{"data": map[string]any{
+		"head_slot":     fmt.Sprint(headSlot),
+		"sync_distance": "0",
+		"is_syncing":    false,
+		"is_optimistic": false,
+		"el_offline":    false,
+	}})
+
+	checkpoint := func(epoch int) map[string]string {
+		return map[string]string{"epoch": fmt.Sprint(epoch), "root": root(byte(epoch)).String()}
+	}
+	write("eth/v1/beacon/states/head/finality_checkpoints", map[string]any{
+		"execution_optimistic": false,
+		"finalized":            false,
+		"data": map[string]any{
+			"previous_justified": checkpoint(finalizedEpoch),
+			"current_justified":  checkpoint(finalizedEpoch + 1),
+			"finalized":          checkpoint(finalizedEpoch),
+		},
+	})
+
+	for epoch := 0; epoch <= lastSlot/32; epoch++ {
+		duties := make([]map[string]string, 0, 32)
+		for slot := epoch * 32; slot < (epoch+1)*32; slot++ {
+			duties = append(duties, map[string]string{
+				"pubkey":          pubkey(proposer(phase0.Slot(slot))),
+				"validator_index": fmt.Sprint(proposer(phase0.Slot(slot))),
+				"slot":            fmt.Sprint(slot),
+			})
+		}
+		write(fmt.Sprintf("eth/v1/validator/duties/proposer/%d", epoch), map[string]any{
+			"dependent_root":       root(byte(epoch)).String(),
+			"execution_optimistic": false,
+			"data":                 duties,
+		})
+	}
+
+	parent := phase0.Root{}
+	number := uint64(1000)
+	for slot := phase0.Slot(0); slot <= lastSlot; slot++ {
+		write(fmt.Sprintf("eth/v1/beacon/states/%d/root", slot), map[string]any{
+			"execution_optimistic": false,
+			"finalized":            true,
+			"data":                 map[string]string{"root": root(byte(slot)).String()},
+		})
+		if missed[slot] {
+			continue
+		}
+		block := newBlock(slot, parent, number)
+		number++
+		write(fmt.Sprintf("eth/v2/beacon/blocks/%d", slot), map[string]any{
+			"version":              "deneb",
+			"execution_optimistic": false,
+			"finalized":            true,
+			"data":                 block,
+		})
+		var err error
+		parent, err = block.Message.HashTreeRoot()
+		check(err)
+	}
+}
+
+func newBlock(slot phase0.Slot, parent phase0.Root, number uint64) *deneb.SignedBeaconBlock {
+	graffiti := [32]byte{}
+	copy(graffiti[:], fmt.Sprintf("harness slot %d", slot))
+	syncBits := bitfield.NewBitvector512()
+	for i := uint64(0); i < 512; i += 2 {
+		syncBits.SetBitAt(i, true)
+	}
+	return &deneb.SignedBeaconBlock{
+		Message: &deneb.BeaconBlock{
+			Slot:          slot,
+			ProposerIndex: proposer(slot),
+			ParentRoot:    parent,
+			StateRoot:     root(byte(slot)),
+			Body: &deneb.BeaconBlockBody{
+				ETH1Data: &phase0.ETH1Data{
+					DepositRoot: root(0xdd),
+					BlockHash:   make([]byte, 32),
+				},
+				Graffiti: graffiti,
+				SyncAggregate: &altair.SyncAggregate{
+					SyncCommitteeBits: syncBits,
+				},
+				ExecutionPayload: &deneb.ExecutionPayload{
+					ParentHash:    phase0.Hash32(root(byte(number - 1))),
+					FeeRecipient:  bellatrix.ExecutionAddress{0x11},
+					BlockNumber:   number,
+					GasLimit:      30_000_000,
+					GasUsed:       15_000_000,
+					Timestamp:     genesisTime + uint64(slot)*12,
+					BaseFeePerGas: uint256.NewInt(7),
+					BlockHash:     phase0.Hash32(root(byte(number))),
+					Transactions:  []bellatrix.Transaction{},
+					Withdrawals: []*capella.Withdrawal{{
+						Index:          capella.WithdrawalIndex(slot),
+						ValidatorIndex: proposer(slot),
+						Address:        bellatrix.ExecutionAddress{0x22},
+						Amount:         phase0.Gwei(1000 + uint64(slot)),
+					}},
+				},
+				BLSToExecutionChanges: []*capella.SignedBLSToExecutionChange{},
+				BlobKZGCommitments:    []deneb.KZGCommitment{},
+			},
+		},
+	}
+}
+
+func proposer(slot phase0.Slot) phase0.ValidatorIndex {
+	return phase0.ValidatorIndex(uint64(slot) % proposers)
+}
+
+func pubkey(index phase0.ValidatorIndex) string {
+	key := phase0.BLSPubKey{}
+	key[0] = byte(index)
+	return key.String()
+}
+
+func root(b byte) phase0.Root {
+	r := phase0.Root{}
+	for i := range r {
+		r[i] = b
+	}
+	return r
+}
+
+func specValues() map[string]string {
+	return map[string]string{
+		"CONFIG_NAME":                      "harness",
+		"PRESET_BASE":                      "mainnet",
+		"SECONDS_PER_SLOT":                 "12",
+		"SLOTS_PER_EPOCH":                  "32",
+		"GENESIS_FORK_VERSION":             "0x00000000",
+		"ALTAIR_FORK_VERSION":              "0x01000000",
+		"ALTAIR_FORK_EPOCH":                "0",
+		"BELLATRIX_FORK_VERSION":           "0x02000000",
+		"BELLATRIX_FORK_EPOCH":             "0",
+		"CAPELLA_FORK_VERSION":             "0x03000000",
+		"CAPELLA_FORK_EPOCH":               "0",
+		"DENEB_FORK_VERSION":               "0x04000000",
+		"DENEB_FORK_EPOCH":                 "0",
+		"ELECTRA_FORK_VERSION":             "0x05000000",
+		"ELECTRA_FORK_EPOCH":               "18446744073709551615",
+		"EPOCHS_PER_SYNC_COMMITTEE_PERIOD": "256",
+		"SYNC_COMMITTEE_SIZE":              "512",
+		"MAX_COMMITTEES_PER_SLOT":          "64",
+		"TARGET_COMMITTEE_SIZE":            "128",
+		"MAX_VALIDATORS_PER_COMMITTEE":     "2048",
+		"MAX_EFFECTIVE_BALANCE":            "32000000000",
+		"EFFECTIVE_BALANCE_INCREMENT":      "1000000000",
+		"MAX_BLOB_COMMITMENTS_PER_BLOCK":   "4096",
+		"MAX_BLOBS_PER_BLOCK":              "6",
+		"MAX_WITHDRAWALS_PER_PAYLOAD":      "16",
+		"MAX_BLS_TO_EXECUTION_CHANGES":     "16",
+		"MAX_PROPOSER_SLASHINGS":           "16",
+		"MAX_ATTESTER_SLASHINGS":           "2",
+		"MAX_ATTESTATIONS":                 "128",
+		"MAX_DEPOSITS":                     "16",
+		"MAX_VOLUNTARY_EXITS":              "16",
+		"MAX_EXTRA_DATA_BYTES":             "32",
+		"MAX_TRANSACTIONS_PER_PAYLOAD":     "1048576",
+		"MAX_BYTES_PER_TRANSACTION":        "1073741824",
+		"BYTES_PER_LOGS_BLOOM":             "256",
+		"DEPOSIT_CONTRACT_ADDRESS":         "0x00000000219ab540356cbb839cbe05303d7705fa",
+		"DEPOSIT_CHAIN_ID":                 "1",
+		"DEPOSIT_NETWORK_ID":               "1",
+	}
+}
+
+func write(path string, data any) {
+	content, err := json.MarshalIndent(data, "", "  ")
+	check(err)
+	path = filepath.Join(dir, filepath.FromSlash(path)) + ".json"
+	check(os.MkdirAll(filepath.Dir(path), 0o755))
+	check(os.WriteFile(path, append(content, '\n'), 0o644))
+}
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
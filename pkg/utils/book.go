@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/migalabs/goteth/pkg/metrics"
@@ -11,21 +13,26 @@ var (
 	emptyKey          = ""
 	structName        = "routinebook"
 	CheckPageInterval = 1 * time.Second
+	YieldPageInterval = 100 * time.Millisecond
 )
 
 type RoutineBook struct {
 	sync.Mutex
 	pages         map[string]string
+	acquiredAt    map[string]time.Time // when each page was acquired
 	freeSpaceChan chan struct{}
 	size          int64
 	bookTag       string
+
+	priorityWaiters int64 // routines waiting for a page with priority
 }
 
 func NewRoutineBook(size int, tag string) *RoutineBook {
 
 	r := &RoutineBook{
 		pages:         make(map[string]string, size), // contains a list of keys identifying routines
-		freeSpaceChan: make(chan struct{}, size),     // indicates the free position in the array
+		acquiredAt:    make(map[string]time.Time, size),
+		freeSpaceChan: make(chan struct{}, size), // indicates the free position in the array
 		size:          int64(size),
 		bookTag:       tag,
 	}
@@ -41,13 +48,35 @@ func (r *RoutineBook) Init() {
 }
 
 func (r *RoutineBook) Acquire(key string) {
+	r.acquire(key, false)
+}
+
+// AcquirePriority acquires a page before any routine waiting through Acquire
+func (r *RoutineBook) AcquirePriority(key string) {
+	atomic.AddInt64(&r.priorityWaiters, 1)
+	defer atomic.AddInt64(&r.priorityWaiters, -1)
+	r.acquire(key, true)
+}
+
+func (r *RoutineBook) acquire(key string, priority bool) {
 
 	ticker := time.NewTicker(AcquireWaitIntervalLog)
-	select {
-	case <-ticker.C:
-		log.WithField("bookTag", r.bookTag).Warnf("Waiting for too long to acquire page %s...", key)
-	case <-r.freeSpaceChan:
-		r.Set(key, "active")
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			log.WithField("bookTag", r.bookTag).Warnf("Waiting for too long to acquire page %s...", key)
+			return
+		case <-r.freeSpaceChan:
+			if !priority && atomic.LoadInt64(&r.priorityWaiters) > 0 {
+				// give the free page back to the priority routines and try again later
+				r.freeSpaceChan <- struct{}{}
+				time.Sleep(YieldPageInterval)
+				continue
+			}
+			r.Set(key, "active")
+			return
+		}
 	}
 }
 
@@ -59,6 +88,7 @@ func (r *RoutineBook) FreePage(key string) {
 	// If the key exists
 	if ok {
 		delete(r.pages, key)
+		delete(r.acquiredAt, key)
 		r.freeSpaceChan <- struct{}{}
 	}
 
@@ -92,6 +122,9 @@ func (r *RoutineBook) Set(key string, value string) {
 	r.Lock()
 	defer r.Unlock()
 	r.pages[key] = value // book page
+	if _, ok := r.acquiredAt[key]; !ok {
+		r.acquiredAt[key] = time.Now()
+	}
 
 }
 
@@ -135,6 +168,33 @@ func (r *RoutineBook) GetKeys() []string {
 	return keys
 }
 
+// BookPage represents a page currently acquired in the book
+type BookPage struct {
+	Key     string        `json:"key"`
+	HeldFor time.Duration `json:"held_for"`
+}
+
+// GetPages returns the acquired pages sorted by the time they have been held (longest first)
+func (r *RoutineBook) GetPages() []BookPage {
+	r.Lock()
+	defer r.Unlock()
+	pages := make([]BookPage, 0, len(r.pages))
+	for k := range r.pages {
+		pages = append(pages, BookPage{
+			Key:     k,
+			HeldFor: time.Since(r.acquiredAt[k]),
+		})
+	}
+	sort.Slice(pages, func(i, j int) bool {
+		return pages[i].HeldFor > pages[j].HeldFor
+	})
+	return pages
+}
+
+func (r *RoutineBook) Tag() string {
+	return r.bookTag
+}
+
 func (r *RoutineBook) GetPrometheusMetrics() *metrics.MetricsModule {
 	metricsMod := metrics.NewMetricsModule(
 		structName,
@@ -3,6 +3,7 @@ package utils
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -87,7 +88,6 @@ func AddOthersPool(batches []PoolKeys, othervalList []phase0.ValidatorIndex) []P
 
 func ReadCustomValidatorsFile(validatorKeysFile string) (validatorKeysByPool []PoolKeys, err error) {
 	log.Info("Reading validator keys from: ", validatorKeysFile)
-	validatorKeysByPool = make([]PoolKeys, 0)
 
 	file, err := os.Open(validatorKeysFile)
 	if err != nil {
@@ -95,7 +95,20 @@ func ReadCustomValidatorsFile(validatorKeysFile string) (validatorKeysByPool []P
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	validatorKeysByPool, err = ReadCustomValidators(file)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Done reading from %s", validatorKeysFile)
+	return validatorKeysByPool, nil
+}
+
+// ReadCustomValidators reads the val_idx,custom_pool lines of a custom validators file, grouped by pool
+func ReadCustomValidators(input io.Reader) (validatorKeysByPool []PoolKeys, err error) {
+	validatorKeysByPool = make([]PoolKeys, 0)
+
+	scanner := bufio.NewScanner(input)
 	for scanner.Scan() {
 		line := scanner.Text()
 
@@ -143,7 +156,6 @@ func ReadCustomValidatorsFile(validatorKeysFile string) (validatorKeysByPool []P
 		return nil, err
 	}
 
-	log.Infof("Done reading from %s", validatorKeysFile)
 	return validatorKeysByPool, nil
 }
 